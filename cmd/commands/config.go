@@ -0,0 +1,121 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func NewCmdConfig() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Share app and function configuration between dev servers",
+	}
+	cmd.PersistentFlags().String("url", "http://localhost:8288", "The dev server URL")
+	cmd.AddCommand(newCmdConfigExport())
+	cmd.AddCommand(newCmdConfigImport())
+	return cmd
+}
+
+func newCmdConfigExport() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "export",
+		Short:   "Export every synced app and function's configuration as a bundle",
+		Example: "inngest config export -o inngest.yaml --format yaml",
+		Args:    cobra.NoArgs,
+		Run:     doConfigExport,
+	}
+
+	cmd.Flags().StringP("output", "o", "", "Path to write the bundle to, defaulting to inngest.<format>")
+	cmd.Flags().String("format", "json", "The bundle format, either json or yaml")
+
+	return cmd
+}
+
+func newCmdConfigImport() *cobra.Command {
+	return &cobra.Command{
+		Use:   "import [file]",
+		Short: "Import apps and functions from a bundle into the dev server",
+		Long: `Import apps and functions from a bundle into the dev server.
+
+Each app within the bundle replaces any app with the same URL.  Other apps are
+left as-is.`,
+		Example: "inngest config import inngest.yaml",
+		Args:    cobra.ExactArgs(1),
+		Run:     doConfigImport,
+	}
+}
+
+func doConfigExport(cmd *cobra.Command, args []string) {
+	url, _ := cmd.Flags().GetString("url")
+	output, _ := cmd.Flags().GetString("output")
+	format, _ := cmd.Flags().GetString("format")
+	if output == "" {
+		output = fmt.Sprintf("inngest.%s", format)
+	}
+
+	if err := exportConfig(strings.TrimSuffix(url, "/"), format, output); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	fmt.Printf("Wrote config to %s\n", output)
+}
+
+func doConfigImport(cmd *cobra.Command, args []string) {
+	url, _ := cmd.Flags().GetString("url")
+
+	if err := importConfig(strings.TrimSuffix(url, "/"), args[0]); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	fmt.Printf("Imported config from %s\n", args[0])
+}
+
+// exportConfig writes the dev server's config bundle to the given path.
+func exportConfig(url, format, path string) error {
+	client := http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Get(fmt.Sprintf("%s/dev/config?format=%s", url, format))
+	if err != nil {
+		return fmt.Errorf("error requesting config: %w", err)
+	}
+	defer resp.Body.Close()
+
+	byt, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("error reading config: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("error exporting config: %s", strings.TrimSpace(string(byt)))
+	}
+	if err := os.WriteFile(path, byt, 0644); err != nil {
+		return fmt.Errorf("error writing config: %w", err)
+	}
+	return nil
+}
+
+// importConfig sends the config bundle at the given path to the dev server.
+func importConfig(url, path string) error {
+	byt, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("error reading config: %w", err)
+	}
+
+	client := http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Post(fmt.Sprintf("%s/dev/config", url), "application/octet-stream", bytes.NewReader(byt))
+	if err != nil {
+		return fmt.Errorf("error importing config: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("error importing config: %s", strings.TrimSpace(string(body)))
+	}
+	return nil
+}
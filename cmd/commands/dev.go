@@ -23,14 +23,29 @@ func NewCmdDev() *cobra.Command {
 		Run:     doDev,
 	}
 
+	cmd.Flags().StringP("config", "c", "", "Path to an Inngest config file (inngest.cue or inngest.json), eg. to configure the HTTP driver")
 	cmd.Flags().String("host", "", "host to run the API on")
 	cmd.Flags().StringP("port", "p", "8288", "port to run the API on")
-	cmd.Flags().StringSliceP("sdk-url", "u", []string{}, "SDK URLs to load functions from")
+	cmd.Flags().StringSliceP("sdk-url", "u", []string{}, "SDK URLs to load functions from, which may contain port ranges or wildcards, eg. http://localhost:3000-3005/api/inngest")
+	cmd.Flags().String("sdk-url-file", "", "Path to a file listing SDK URLs to load functions from, one per line, which is reloaded when changed")
 	cmd.Flags().Bool("no-discovery", false, "Disable autodiscovery")
 	cmd.Flags().Bool("no-poll", false, "Disable polling of apps for updates")
 	cmd.Flags().Int("poll-interval", 5, "Interval in seconds between polling for updates to apps")
+	cmd.Flags().Int("drain-period", 0, "Seconds that an app's previous URL continues to run steps for started runs after the app syncs at a new URL, while new runs use the new URL")
 	cmd.Flags().Int("retry-interval", 0, "Retry interval in seconds for linear backoff when retrying functions - must be 1 or above")
 
+	cmd.Flags().String("signing-key", "", "Signing key used to sign requests to apps, and to verify signed responses and syncs")
+	cmd.Flags().String("previous-signing-key", "", "Previous signing key, accepted when verifying responses and syncs while rotating signing keys")
+	cmd.Flags().Int("signature-tolerance", 300, "Maximum age in seconds of signed responses and syncs before they're rejected")
+
+	cmd.Flags().String("snapshot", "", "Path to a dev server snapshot to restore on startup, created via GET /dev/snapshot")
+
+	cmd.Flags().Float64("chaos-driver-errors", 0, "Ratio of step requests, between 0 and 1, which fail with a 500 without calling the SDK")
+	cmd.Flags().Int("chaos-lease-delay", 0, "Maximum random delay in milliseconds applied before leasing each queue item")
+	cmd.Flags().Float64("chaos-pause-drops", 0, "Ratio of pause consumes, between 0 and 1, which fail and leave the pause in place")
+	cmd.Flags().Int("chaos-state-delay", 0, "Maximum random delay in milliseconds applied to each run state write")
+	cmd.Flags().Bool("record", false, "Record each SDK request and response so that runs can be replayed")
+
 	cmd.Flags().Int("tick", 150, "The interval (in milliseconds) at which the executor checks for new work, during local development")
 
 	return cmd
@@ -52,7 +67,7 @@ func doDev(cmd *cobra.Command, args []string) {
 	}()
 
 	ctx := cmd.Context()
-	conf, err := config.Dev(ctx)
+	conf, err := loadDevConfig(ctx, cmd.Flag("config").Value.String())
 	if err != nil {
 		fmt.Println(err.Error())
 		os.Exit(1)
@@ -71,13 +86,24 @@ func doDev(cmd *cobra.Command, args []string) {
 	}
 
 	urls, _ := cmd.Flags().GetStringSlice("sdk-url")
+	urlsFile, _ := cmd.Flags().GetString("sdk-url-file")
 
 	// Run auto-discovery unless we've explicitly disabled it.
 	noDiscovery, _ := cmd.Flags().GetBool("no-discovery")
 	noPoll, _ := cmd.Flags().GetBool("no-poll")
 	pollInterval, _ := cmd.Flags().GetInt("poll-interval")
 	retryInterval, _ := cmd.Flags().GetInt("retry-interval")
+	drainPeriod, _ := cmd.Flags().GetInt("drain-period")
 	tick, _ := cmd.Flags().GetInt("tick")
+	snapshot, _ := cmd.Flags().GetString("snapshot")
+	signingKey, _ := cmd.Flags().GetString("signing-key")
+	previousSigningKey, _ := cmd.Flags().GetString("previous-signing-key")
+	signatureTolerance, _ := cmd.Flags().GetInt("signature-tolerance")
+	chaosDriverErrors, _ := cmd.Flags().GetFloat64("chaos-driver-errors")
+	chaosLeaseDelay, _ := cmd.Flags().GetInt("chaos-lease-delay")
+	chaosPauseDrops, _ := cmd.Flags().GetFloat64("chaos-pause-drops")
+	chaosStateDelay, _ := cmd.Flags().GetInt("chaos-state-delay")
+	record, _ := cmd.Flags().GetBool("record")
 
 	if err := telemetry.NewUserTracer(ctx, telemetry.TracerOpts{
 		ServiceName: "devserver",
@@ -93,11 +119,26 @@ func doDev(cmd *cobra.Command, args []string) {
 	opts := devserver.StartOpts{
 		Config:        *conf,
 		URLs:          urls,
+		URLsFile:      urlsFile,
 		Autodiscover:  !noDiscovery,
 		Poll:          !noPoll,
 		PollInterval:  pollInterval,
 		RetryInterval: retryInterval,
 		Tick:          time.Duration(tick) * time.Millisecond,
+		SnapshotPath:  snapshot,
+		DrainPeriod:   drainPeriod,
+		Record:        record,
+
+		SigningKey:         signingKey,
+		PreviousSigningKey: previousSigningKey,
+		SignatureTolerance: signatureTolerance,
+
+		Chaos: devserver.ChaosOpts{
+			DriverErrorRate: chaosDriverErrors,
+			LeaseDelay:      time.Duration(chaosLeaseDelay) * time.Millisecond,
+			PauseDropRate:   chaosPauseDrops,
+			StateWriteDelay: time.Duration(chaosStateDelay) * time.Millisecond,
+		},
 	}
 
 	err = devserver.New(ctx, opts)
@@ -106,3 +147,15 @@ func doDev(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 }
+
+// loadDevConfig loads the config file at the given path, or the default dev
+// config if path is empty.
+func loadDevConfig(ctx context.Context, path string) (*config.Config, error) {
+	if path == "" {
+		return config.Dev(ctx)
+	}
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("error reading config file: %w", err)
+	}
+	return config.Load(ctx, path)
+}
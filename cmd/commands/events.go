@@ -0,0 +1,251 @@
+package commands
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/inngest/inngest/pkg/api/apiv1"
+	"github.com/inngest/inngest/pkg/consts"
+	"github.com/inngest/inngest/pkg/cqrs"
+	"github.com/inngest/inngest/pkg/dateutil"
+	"github.com/inngest/inngest/pkg/event"
+	"github.com/spf13/cobra"
+	"github.com/xhit/go-str2duration/v2"
+)
+
+func NewCmdEvents() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "events",
+		Short: "Move events between dev servers as NDJSON",
+	}
+	cmd.PersistentFlags().String("url", "http://localhost:8288", "The dev server URL")
+	cmd.AddCommand(newCmdEventsExport())
+	cmd.AddCommand(newCmdEventsImport())
+	return cmd
+}
+
+func newCmdEventsExport() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export events received within a time range as NDJSON",
+		Long: `Export events received within a time range as NDJSON, oldest first.
+
+--from and --to accept either a time, eg. 2024-01-02T15:04:05Z, or a duration
+before now, eg. 2h.  Internal inngest/ events are not exported, as they can't be
+sent to the event API.`,
+		Example: "inngest events export --from 24h -o events.ndjson",
+		Args:    cobra.NoArgs,
+		Run:     doEventsExport,
+	}
+
+	cmd.Flags().StringP("output", "o", "", "Path to write events to, defaulting to stdout")
+	cmd.Flags().String("from", "1h", "Export events received at or after this time")
+	cmd.Flags().String("to", "", "Export events received at or before this time, defaulting to now")
+	cmd.Flags().String("name", "", "Only export events with this name")
+
+	return cmd
+}
+
+func newCmdEventsImport() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "import [file]",
+		Short:   "Send each event within an NDJSON file to the event API",
+		Example: "inngest events import events.ndjson --rate 5",
+		Args:    cobra.ExactArgs(1),
+		Run:     doEventsImport,
+	}
+
+	cmd.Flags().Float64("rate", 10, "The maximum number of events sent per second, or 0 for no limit")
+	cmd.Flags().String("event-key", "test", "The event key used to send events")
+
+	return cmd
+}
+
+func doEventsExport(cmd *cobra.Command, args []string) {
+	serverURL, _ := cmd.Flags().GetString("url")
+	output, _ := cmd.Flags().GetString("output")
+	fromFlag, _ := cmd.Flags().GetString("from")
+	toFlag, _ := cmd.Flags().GetString("to")
+	name, _ := cmd.Flags().GetString("name")
+
+	from, err := parseTimeFlag(fromFlag, time.Now())
+	if err != nil {
+		fmt.Printf("Invalid --from: %s\n", err)
+		os.Exit(1)
+	}
+	to, err := parseTimeFlag(toFlag, time.Now())
+	if err != nil {
+		fmt.Printf("Invalid --to: %s\n", err)
+		os.Exit(1)
+	}
+
+	w := os.Stdout
+	if output != "" {
+		f, err := os.Create(output)
+		if err != nil {
+			fmt.Printf("error creating output: %s\n", err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	n, err := exportEvents(strings.TrimSuffix(serverURL, "/"), from, to, name, w)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if output != "" {
+		fmt.Printf("Exported %d events to %s\n", n, output)
+	}
+}
+
+func doEventsImport(cmd *cobra.Command, args []string) {
+	serverURL, _ := cmd.Flags().GetString("url")
+	rate, _ := cmd.Flags().GetFloat64("rate")
+	key, _ := cmd.Flags().GetString("event-key")
+	if rate < 0 {
+		fmt.Println("--rate must not be negative")
+		os.Exit(1)
+	}
+
+	n, err := importEvents(strings.TrimSuffix(serverURL, "/"), key, args[0], rate)
+	if err != nil {
+		fmt.Printf("Sent %d events before failing\n", n)
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	fmt.Printf("Sent %d events from %s\n", n, args[0])
+}
+
+// parseTimeFlag parses the flag as a time or as a duration before now.  Empty
+// flags return the zero time.
+func parseTimeFlag(s string, now time.Time) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if d, err := str2duration.ParseDuration(s); err == nil {
+		return now.Add(-d), nil
+	}
+	return dateutil.ParseString(s)
+}
+
+// exportEvents writes each event received between from and to as NDJSON,
+// returning the number of events written.
+func exportEvents(serverURL string, from, to time.Time, name string, w io.Writer) (int, error) {
+	client := http.Client{Timeout: 30 * time.Second}
+	enc := json.NewEncoder(w)
+
+	query := url.Values{
+		"order": {string(cqrs.SortOrderAsc)},
+		"limit": {fmt.Sprintf("%d", cqrs.MaxEvents)},
+	}
+	if !from.IsZero() {
+		query.Set("received_after", from.UTC().Format(time.RFC3339))
+	}
+	if !to.IsZero() {
+		query.Set("received_before", to.UTC().Format(time.RFC3339))
+	}
+	if name != "" {
+		query.Set("name", name)
+	}
+
+	n := 0
+	for {
+		resp, err := client.Get(fmt.Sprintf("%s/v1/events?%s", serverURL, query.Encode()))
+		if err != nil {
+			return n, fmt.Errorf("error requesting events: %w", err)
+		}
+		byt, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return n, fmt.Errorf("error reading events: %w", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			return n, fmt.Errorf("error exporting events: %s", strings.TrimSpace(string(byt)))
+		}
+
+		page := apiv1.Response[[]cqrs.Event]{}
+		if err := json.Unmarshal(byt, &page); err != nil {
+			return n, fmt.Errorf("error reading events: %w", err)
+		}
+		for _, evt := range page.Data {
+			if strings.HasPrefix(strings.ToLower(evt.EventName), "inngest/") {
+				continue
+			}
+			if err := enc.Encode(evt.Event()); err != nil {
+				return n, fmt.Errorf("error writing events: %w", err)
+			}
+			n++
+		}
+
+		if len(page.Data) < cqrs.MaxEvents {
+			return n, nil
+		}
+		query.Set("cursor", page.Data[len(page.Data)-1].ID.String())
+	}
+}
+
+// importEvents sends each event within the NDJSON file at path to the event API,
+// sending up to rate events per second.  This returns the number of events sent.
+func importEvents(serverURL, key, path string, rate float64) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("error reading events: %w", err)
+	}
+	defer f.Close()
+
+	var tick <-chan time.Time
+	if rate > 0 {
+		t := time.NewTicker(time.Duration(float64(time.Second) / rate))
+		defer t.Stop()
+		tick = t.C
+	}
+
+	client := http.Client{Timeout: 30 * time.Second}
+	endpoint := fmt.Sprintf("%s/e/%s", serverURL, url.PathEscape(key))
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), consts.AbsoluteMaxEventSize)
+
+	n := 0
+	line := 0
+	for scanner.Scan() {
+		line++
+		byt := bytes.TrimSpace(scanner.Bytes())
+		if len(byt) == 0 {
+			continue
+		}
+		evt := event.Event{}
+		if err := json.Unmarshal(byt, &evt); err != nil {
+			return n, fmt.Errorf("invalid event on line %d: %w", line, err)
+		}
+
+		if tick != nil && n > 0 {
+			<-tick
+		}
+
+		resp, err := client.Post(endpoint, "application/json", bytes.NewReader(byt))
+		if err != nil {
+			return n, fmt.Errorf("error sending event on line %d: %w", line, err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return n, fmt.Errorf("error sending event on line %d: %s", line, strings.TrimSpace(string(body)))
+		}
+		n++
+	}
+	if err := scanner.Err(); err != nil {
+		return n, fmt.Errorf("error reading events: %w", err)
+	}
+	return n, nil
+}
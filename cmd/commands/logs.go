@@ -0,0 +1,159 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/gorilla/websocket"
+	"github.com/inngest/inngest/pkg/cli"
+	"github.com/inngest/inngest/pkg/devserver"
+	"github.com/inngest/inngest/pkg/enums"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func NewCmdLogs() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "logs",
+		Short: "Print a run's timeline from the dev server",
+		Long: `Print a run's timeline of lifecycle events, step outputs and errors from
+the dev server.
+
+With --follow, new entries are printed as the run executes until the run
+finishes.  Entries are printed as JSON lines with --json, or when stdout is not
+a terminal.`,
+		Example: "inngest logs --run 01HQ8PTAESBZPBDS8JTRZZYY3S --follow",
+		Args:    cobra.NoArgs,
+		Run:     doLogs,
+	}
+
+	cmd.Flags().String("url", "http://localhost:8288", "The dev server URL")
+	cmd.Flags().String("run", "", "The ID of the run")
+	cmd.Flags().BoolP("follow", "f", false, "Stream new entries until the run finishes")
+	_ = cmd.MarkFlagRequired("run")
+
+	return cmd
+}
+
+func doLogs(cmd *cobra.Command, args []string) {
+	url, _ := cmd.Flags().GetString("url")
+	runID, _ := cmd.Flags().GetString("run")
+	follow, _ := cmd.Flags().GetBool("follow")
+	url = strings.TrimSuffix(url, "/")
+
+	printEntry := printTimelineEntry
+	if viper.GetBool("json") {
+		printEntry = func(entry devserver.TimelineEntry) {
+			byt, _ := json.Marshal(entry)
+			fmt.Println(string(byt))
+		}
+	}
+
+	var err error
+	if follow {
+		err = followTimeline(url, runID, printEntry)
+	} else {
+		err = fetchTimeline(url, runID, printEntry)
+	}
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+// fetchTimeline prints each entry within the run's timeline.
+func fetchTimeline(url, runID string, printEntry func(devserver.TimelineEntry)) error {
+	client := http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Get(fmt.Sprintf("%s/dev/runs/%s/timeline", url, runID))
+	if err != nil {
+		return fmt.Errorf("error requesting timeline: %w", err)
+	}
+	defer resp.Body.Close()
+
+	byt, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("error reading timeline: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("error loading timeline: %s", strings.TrimSpace(string(byt)))
+	}
+
+	entries := []devserver.TimelineEntry{}
+	if err := json.Unmarshal(byt, &entries); err != nil {
+		return fmt.Errorf("error reading timeline: %w", err)
+	}
+	for _, entry := range entries {
+		printEntry(entry)
+	}
+	return nil
+}
+
+// followTimeline prints each entry within the run's timeline as the run executes,
+// returning once the run finishes.
+func followTimeline(url, runID string, printEntry func(devserver.TimelineEntry)) error {
+	wsURL := "ws" + strings.TrimPrefix(url, "http")
+	conn, resp, err := websocket.DefaultDialer.Dial(fmt.Sprintf("%s/dev/runs/%s/timeline/feed", wsURL, runID), nil)
+	if err != nil {
+		if resp != nil {
+			defer resp.Body.Close()
+			byt, _ := io.ReadAll(resp.Body)
+			return fmt.Errorf("error following timeline: %s", strings.TrimSpace(string(byt)))
+		}
+		return fmt.Errorf("error following timeline: %w", err)
+	}
+	defer conn.Close()
+
+	for {
+		entry := devserver.TimelineEntry{}
+		if err := conn.ReadJSON(&entry); err != nil {
+			if websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+				return nil
+			}
+			return fmt.Errorf("error reading timeline: %w", err)
+		}
+		printEntry(entry)
+	}
+}
+
+// printTimelineEntry prints the entry formatted for terminals, eg:
+//
+//	12:01:02.345  StepCompleted  fetch-user  {"id": 1}
+func printTimelineEntry(entry devserver.TimelineEntry) {
+	style := cli.BoldStyle
+	switch entry.Type {
+	case enums.HistoryTypeFunctionCompleted.String(), enums.HistoryTypeStepCompleted.String():
+		style = style.Copy().Foreground(cli.Green)
+	case enums.HistoryTypeFunctionFailed.String(), enums.HistoryTypeStepErrored.String(), enums.HistoryTypeStepFailed.String():
+		style = style.Copy().Foreground(cli.Red)
+	case enums.HistoryTypeFunctionCancelled.String():
+		style = style.Copy().Foreground(cli.Orange)
+	}
+
+	parts := []string{
+		cli.FeintStyle.Render(entry.CreatedAt.Local().Format("15:04:05.000")),
+		style.Copy().Width(18).Render(entry.Type),
+	}
+	if entry.StepName != nil && *entry.StepName != "" {
+		parts = append(parts, cli.TextStyle.Render(*entry.StepName))
+	}
+	if entry.Attempt > 0 {
+		parts = append(parts, cli.FeintStyle.Render(fmt.Sprintf("(attempt %d)", entry.Attempt+1)))
+	}
+	if entry.Sleep != nil {
+		parts = append(parts, cli.FeintStyle.Render("until "+entry.Sleep.Until.Local().Format(time.RFC3339)))
+	}
+	if entry.WaitForEvent != nil {
+		parts = append(parts, cli.FeintStyle.Render("for "+entry.WaitForEvent.EventName))
+	}
+	fmt.Println(strings.Join(parts, "  "))
+
+	if entry.Output != nil {
+		fmt.Println(lipgloss.NewStyle().PaddingLeft(14).Render(*entry.Output))
+	}
+}
@@ -81,6 +81,14 @@ func Execute() {
 	rootCmd.AddCommand(NewCmdDev())
 	rootCmd.AddCommand(NewCmdVersion())
 	rootCmd.AddCommand(NewCmdServe())
+	rootCmd.AddCommand(NewCmdTrace())
+	rootCmd.AddCommand(NewCmdState())
+	rootCmd.AddCommand(NewCmdConfig())
+	rootCmd.AddCommand(NewCmdRun())
+	rootCmd.AddCommand(NewCmdLogs())
+	rootCmd.AddCommand(NewCmdEvents())
+	rootCmd.AddCommand(NewCmdStatus())
+	rootCmd.AddCommand(NewCmdValidate())
 
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Println(err)
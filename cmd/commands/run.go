@@ -0,0 +1,124 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/inngest/inngest/pkg/devserver"
+	"github.com/inngest/inngest/pkg/enums"
+	"github.com/spf13/cobra"
+)
+
+func NewCmdRun() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "run [function-slug]",
+		Short: "Invoke a function on the dev server",
+		Long: `Invoke a function on the dev server by sending it an invocation event.
+
+With --wait, this waits for the run to finish and prints its output, exiting
+with a non-zero status if the run fails.`,
+		Example: `inngest run my-app-my-fn --data '{"user_id": 1}' --wait`,
+		Args:    cobra.ExactArgs(1),
+		Run:     doRun,
+	}
+
+	cmd.Flags().String("url", "http://localhost:8288", "The dev server URL")
+	cmd.Flags().StringP("data", "d", "", "The invocation event's data, as a JSON object")
+	cmd.Flags().BoolP("wait", "w", false, "Wait for the run to finish and print its output")
+	cmd.Flags().Duration("timeout", 5*time.Minute, "The maximum time to wait for the run to finish")
+
+	return cmd
+}
+
+func doRun(cmd *cobra.Command, args []string) {
+	slug := args[0]
+	serverURL, _ := cmd.Flags().GetString("url")
+	data, _ := cmd.Flags().GetString("data")
+	wait, _ := cmd.Flags().GetBool("wait")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	serverURL = strings.TrimSuffix(serverURL, "/")
+
+	req := devserver.InvokeRequest{}
+	if data != "" {
+		if err := json.Unmarshal([]byte(data), &req.Data); err != nil {
+			fmt.Printf("Invalid data, which must be a JSON object: %s\n", err)
+			os.Exit(1)
+		}
+	}
+
+	resp, err := invokeFunction(serverURL, slug, req, wait, timeout)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Sent invocation event %s\n", resp.EventID)
+	if resp.RunID != nil {
+		fmt.Printf("Run %s: %s/stream/trigger?event=%s&run=%s\n", resp.RunID, serverURL, resp.EventID, resp.RunID)
+	}
+	if !wait {
+		return
+	}
+
+	switch resp.Status {
+	case enums.RunStatusCompleted.String(), enums.RunStatusFailed.String(), enums.RunStatusCancelled.String():
+		fmt.Printf("Status: %s\n", resp.Status)
+	default:
+		fmt.Printf("Run did not finish within %s\n", timeout)
+		os.Exit(1)
+	}
+	if len(resp.Output) > 0 {
+		out := bytes.Buffer{}
+		if err := json.Indent(&out, resp.Output, "", "  "); err != nil {
+			out.Reset()
+			out.Write(resp.Output)
+		}
+		fmt.Printf("Output:\n%s\n", out.String())
+	}
+	if resp.Status != enums.RunStatusCompleted.String() {
+		os.Exit(1)
+	}
+}
+
+// invokeFunction invokes the function with the given slug via the dev server,
+// optionally waiting up to timeout for the run to finish.
+func invokeFunction(serverURL, slug string, req devserver.InvokeRequest, wait bool, timeout time.Duration) (*devserver.InvokeResponse, error) {
+	byt, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling invocation: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("%s/fn/%s/invoke", serverURL, url.PathEscape(slug))
+	if wait {
+		endpoint += "?" + url.Values{"wait": {"true"}, "timeout": {timeout.String()}}.Encode()
+	}
+
+	// Allow time for the dev server to respond after waiting for the run.
+	client := http.Client{Timeout: timeout + 30*time.Second}
+	resp, err := client.Post(endpoint, "application/json", bytes.NewReader(byt))
+	if err != nil {
+		return nil, fmt.Errorf("error invoking function: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
+		return nil, fmt.Errorf("error invoking function: %s", strings.TrimSpace(string(body)))
+	}
+
+	result := &devserver.InvokeResponse{}
+	if err := json.Unmarshal(body, result); err != nil {
+		return nil, fmt.Errorf("error reading response: %w", err)
+	}
+	return result, nil
+}
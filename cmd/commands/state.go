@@ -0,0 +1,111 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/inngest/inngest/pkg/execution/state"
+	"github.com/inngest/inngest/pkg/execution/state/migrate"
+	"github.com/inngest/inngest/pkg/execution/state/redis_state"
+	"github.com/redis/rueidis"
+	"github.com/spf13/cobra"
+)
+
+func NewCmdState() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "state",
+		Short: "Manage function run state",
+	}
+	cmd.AddCommand(newCmdStateMigrate())
+	return cmd
+}
+
+func newCmdStateMigrate() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Copy every active run's state and pauses from one state store to another",
+		Long: `Copy every active run's state and pauses from one state store to another.
+
+Each run's metadata, events, stack, and step outputs are copied and verified
+once written.  Runs and pauses which already exist within the destination are
+skipped, so the migration can be re-run to copy runs started while migrating.`,
+		Example: "inngest state migrate --from localhost:6379 --to localhost:6380",
+		Run:     doStateMigrate,
+	}
+
+	cmd.Flags().String("from", "", "The address of the Redis server to copy state from")
+	cmd.Flags().String("to", "", "The address of the Redis server to copy state to")
+	cmd.Flags().String("from-prefix", "{state}", "The key prefix of the state store to copy state from")
+	cmd.Flags().String("to-prefix", "{state}", "The key prefix of the state store to copy state to")
+	cmd.Flags().Bool("dry-run", false, "Load and validate every run without writing state")
+	cmd.Flags().Bool("continue", false, "Continue migrating when a run fails to migrate")
+	_ = cmd.MarkFlagRequired("from")
+	_ = cmd.MarkFlagRequired("to")
+
+	return cmd
+}
+
+func doStateMigrate(cmd *cobra.Command, args []string) {
+	ctx := cmd.Context()
+	fromAddr, _ := cmd.Flags().GetString("from")
+	toAddr, _ := cmd.Flags().GetString("to")
+	fromPrefix, _ := cmd.Flags().GetString("from-prefix")
+	toPrefix, _ := cmd.Flags().GetString("to-prefix")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	cont, _ := cmd.Flags().GetBool("continue")
+
+	if fromAddr == toAddr && fromPrefix == toPrefix {
+		fmt.Println("The source and destination state stores must differ")
+		os.Exit(1)
+	}
+
+	from, err := newRedisStateManager(ctx, fromAddr, fromPrefix)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	to, err := newRedisStateManager(ctx, toAddr, toPrefix)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	opts := migrate.Opts{DryRun: dryRun}
+	if cont {
+		opts.OnError = func(err error) {
+			fmt.Println(err)
+		}
+	}
+
+	res, err := migrate.Migrate(ctx, from, to, opts)
+	if res != nil {
+		fmt.Printf(
+			"Migrated %d runs, %d steps, and %d pauses.  Skipped %d existing and %d failed.\n",
+			res.Runs, res.Steps, res.Pauses, res.Skipped, res.Failed,
+		)
+	}
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if res.Failed > 0 {
+		os.Exit(1)
+	}
+}
+
+func newRedisStateManager(ctx context.Context, addr, prefix string) (state.Manager, error) {
+	rc, err := rueidis.NewClient(rueidis.ClientOption{
+		InitAddress:  []string{addr},
+		DisableCache: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to %s: %w", addr, err)
+	}
+	return redis_state.New(
+		ctx,
+		redis_state.WithRedisClient(rc),
+		redis_state.WithFunctionLoader(migrate.FunctionLoader{}),
+		redis_state.WithKeyGenerator(redis_state.DefaultKeyFunc{Prefix: prefix}),
+	)
+}
@@ -0,0 +1,106 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/inngest/inngest/cmd/commands/internal/table"
+	"github.com/inngest/inngest/pkg/cli"
+	"github.com/inngest/inngest/pkg/devserver"
+	"github.com/inngest/inngest/pkg/enums"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func NewCmdStatus() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show queue backlogs, recent runs and state store health for the dev server",
+		Long: `Show each function's queue backlog and oldest pending item, the runs started
+within the last hour by status, and the health of the dev server's state store.`,
+		Example: "inngest status",
+		Args:    cobra.NoArgs,
+		Run:     doStatus,
+	}
+
+	cmd.Flags().String("url", "http://localhost:8288", "The dev server URL")
+
+	return cmd
+}
+
+func doStatus(cmd *cobra.Command, args []string) {
+	url, _ := cmd.Flags().GetString("url")
+
+	status, byt, err := fetchStatus(strings.TrimSuffix(url, "/"))
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if viper.GetBool("json") {
+		fmt.Println(strings.TrimSpace(string(byt)))
+		return
+	}
+
+	now := time.Now()
+	t := table.New(table.Row{"Function", "Ready", "Scheduled", "In progress", "Oldest pending", "Running", "Waiting", "Completed", "Failed", "Cancelled"})
+	for _, fn := range status.Functions {
+		oldest := "-"
+		if fn.Queue.OldestItemAt != nil {
+			oldest = fn.Queue.OldestItemAge(now).Round(time.Second).String()
+		}
+		name := fn.Slug
+		if fn.Queue.Paused {
+			name += " (paused)"
+		}
+		t.AppendRow(table.Row{
+			name,
+			fn.Queue.Ready,
+			fn.Queue.Scheduled,
+			fn.Queue.InProgress,
+			oldest,
+			fn.RunCount(enums.RunStatusRunning),
+			fn.RunCount(enums.RunStatusWaitingForEvent) + fn.RunCount(enums.RunStatusSleeping) + fn.RunCount(enums.RunStatusThrottled) + fn.RunCount(enums.RunStatusHalted),
+			fn.RunCount(enums.RunStatusCompleted),
+			fn.RunCount(enums.RunStatusFailed),
+			fn.RunCount(enums.RunStatusCancelled),
+		})
+	}
+	t.Render()
+
+	fmt.Println(cli.FeintStyle.Render(fmt.Sprintf("Run counts are for runs started since %s.", status.Since.Local().Format(time.Kitchen))))
+	if status.State.Healthy {
+		fmt.Println(cli.BoldStyle.Render(fmt.Sprintf("State store: healthy (%.2fms)", status.State.Latency)))
+	} else {
+		fmt.Println(cli.RenderError(fmt.Sprintf("State store: unhealthy: %s", status.State.Error)))
+		os.Exit(1)
+	}
+}
+
+// fetchStatus returns the dev server's status, along with the raw response.
+func fetchStatus(url string) (*devserver.Status, []byte, error) {
+	client := http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Get(fmt.Sprintf("%s/dev/status", url))
+	if err != nil {
+		return nil, nil, fmt.Errorf("error requesting status: %w", err)
+	}
+	defer resp.Body.Close()
+
+	byt, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error reading status: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("error loading status: %s", strings.TrimSpace(string(byt)))
+	}
+
+	status := &devserver.Status{}
+	if err := json.Unmarshal(byt, status); err != nil {
+		return nil, nil, fmt.Errorf("error reading status: %w", err)
+	}
+	return status, byt, nil
+}
@@ -0,0 +1,65 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func NewCmdTrace() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "trace [run-id]",
+		Short:   "Export a run's trace from the dev server as an OTLP JSON file",
+		Example: "inngest trace 01HQ8PTAESBZPBDS8JTRZZYY3S -o trace.json",
+		Args:    cobra.ExactArgs(1),
+		Run:     doTrace,
+	}
+
+	cmd.Flags().String("url", "http://localhost:8288", "The dev server URL")
+	cmd.Flags().StringP("output", "o", "", "Path to write the trace to, defaulting to trace-<run-id>.json")
+
+	return cmd
+}
+
+func doTrace(cmd *cobra.Command, args []string) {
+	runID := args[0]
+	url, _ := cmd.Flags().GetString("url")
+	output, _ := cmd.Flags().GetString("output")
+	if output == "" {
+		output = fmt.Sprintf("trace-%s.json", runID)
+	}
+
+	if err := exportTrace(strings.TrimSuffix(url, "/"), runID, output); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	fmt.Printf("Wrote trace to %s\n", output)
+}
+
+// exportTrace writes the run's trace, exported by the dev server, to the given
+// path.
+func exportTrace(url, runID, path string) error {
+	client := http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Get(fmt.Sprintf("%s/dev/runs/%s/trace", url, runID))
+	if err != nil {
+		return fmt.Errorf("error requesting trace: %w", err)
+	}
+	defer resp.Body.Close()
+
+	byt, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("error reading trace: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("error exporting trace: %s", strings.TrimSpace(string(byt)))
+	}
+	if err := os.WriteFile(path, byt, 0644); err != nil {
+		return fmt.Errorf("error writing trace: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,204 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/inngest/inngest/pkg/cli"
+	"github.com/inngest/inngest/pkg/consts"
+	"github.com/inngest/inngest/pkg/cqrs"
+	"github.com/inngest/inngest/pkg/inngest"
+	"github.com/inngest/inngest/pkg/sdk"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func NewCmdValidate() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "validate [file]",
+		Short: "Validate function configuration without sending events",
+		Long: `Validate each function's configuration, including cron schedules, expressions
+for cancellation, concurrency and idempotency keys, batching, retries and
+timeouts.
+
+Pass the path to an SDK's register request, eg. the JSON returned from the SDK's
+serve endpoint, or use --app to validate an app synced with the dev server.`,
+		Example: "inngest validate register.json\ninngest validate --app my-app",
+		Args:    cobra.MaximumNArgs(1),
+		Run:     doValidate,
+	}
+
+	cmd.Flags().String("url", "http://localhost:8288", "The dev server URL")
+	cmd.Flags().String("app", "", "The name of an app synced with the dev server")
+
+	return cmd
+}
+
+// FunctionValidation lists the problems found within a function's configuration.
+type FunctionValidation struct {
+	Slug     string   `json:"slug"`
+	Errors   []string `json:"errors"`
+	Warnings []string `json:"warnings"`
+}
+
+func doValidate(cmd *cobra.Command, args []string) {
+	serverURL, _ := cmd.Flags().GetString("url")
+	app, _ := cmd.Flags().GetString("app")
+
+	if (len(args) == 0) == (app == "") {
+		fmt.Println("Provide either a register request file or --app")
+		os.Exit(1)
+	}
+
+	var (
+		results []FunctionValidation
+		err     error
+	)
+	if app != "" {
+		results, err = validateApp(cmd.Context(), strings.TrimSuffix(serverURL, "/"), app)
+	} else {
+		results, err = validateFile(cmd.Context(), args[0])
+	}
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	failed := false
+	for _, res := range results {
+		if len(res.Errors) > 0 {
+			failed = true
+		}
+	}
+
+	if viper.GetBool("json") {
+		byt, _ := json.Marshal(results)
+		fmt.Println(string(byt))
+	} else {
+		for _, res := range results {
+			if len(res.Errors) == 0 {
+				fmt.Println(cli.BoldStyle.Copy().Foreground(cli.Green).Render("✓ " + res.Slug))
+			} else {
+				fmt.Println(cli.BoldStyle.Copy().Foreground(cli.Red).Render("✗ " + res.Slug))
+			}
+			for _, e := range res.Errors {
+				fmt.Println(cli.TextStyle.Render("    error: " + e))
+			}
+			for _, w := range res.Warnings {
+				fmt.Println(cli.FeintStyle.Render("    warning: " + w))
+			}
+		}
+		fmt.Println(cli.FeintStyle.Render(fmt.Sprintf("Validated %d functions.", len(results))))
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// validateFile validates each function within the register request at path.
+func validateFile(ctx context.Context, path string) ([]FunctionValidation, error) {
+	byt, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading register request: %w", err)
+	}
+	req := sdk.RegisterRequest{}
+	if err := json.Unmarshal(byt, &req); err != nil {
+		return nil, fmt.Errorf("invalid register request: %w", err)
+	}
+
+	results := []FunctionValidation{}
+	for _, sdkFn := range req.Functions {
+		res := FunctionValidation{Slug: sdkFn.Slug, Errors: []string{}, Warnings: []string{}}
+		if res.Slug == "" {
+			res.Slug = sdkFn.Name
+		}
+
+		// The SDK's retries are clamped when registering, so these are only
+		// warnings.
+		if sdkFn.Retries != nil && *sdkFn.Retries > consts.MaxRetries {
+			res.Warnings = append(res.Warnings, fmt.Sprintf("Retries are limited to %d", consts.MaxRetries))
+		}
+		for _, step := range sdkFn.Steps {
+			if step.Retries != nil && step.Retries.Attempts > consts.MaxRetries {
+				res.Warnings = append(res.Warnings, fmt.Sprintf("Step %q retries are limited to %d", step.ID, consts.MaxRetries))
+			}
+		}
+
+		fn, err := sdkFn.Function()
+		if err != nil {
+			res.Errors = append(res.Errors, err.Error())
+			results = append(results, res)
+			continue
+		}
+		res.Slug = fn.GetSlug()
+		res.Errors = append(res.Errors, validationErrors(ctx, fn)...)
+		results = append(results, res)
+	}
+	return results, nil
+}
+
+// validateApp validates each function within the app synced with the dev server.
+func validateApp(ctx context.Context, serverURL, app string) ([]FunctionValidation, error) {
+	client := http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Get(fmt.Sprintf("%s/v1/apps/%s/functions", serverURL, url.PathEscape(app)))
+	if err != nil {
+		return nil, fmt.Errorf("error requesting functions: %w", err)
+	}
+	defer resp.Body.Close()
+
+	byt, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading functions: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error loading functions: %s", strings.TrimSpace(string(byt)))
+	}
+
+	fns := []*cqrs.Function{}
+	if err := json.Unmarshal(byt, &fns); err != nil {
+		return nil, fmt.Errorf("error reading functions: %w", err)
+	}
+	if len(fns) == 0 {
+		return nil, fmt.Errorf("no functions found for app %q", app)
+	}
+
+	results := []FunctionValidation{}
+	for _, f := range fns {
+		res := FunctionValidation{Slug: f.Slug, Errors: []string{}, Warnings: []string{}}
+		fn, err := f.InngestFunction()
+		if err != nil {
+			res.Errors = append(res.Errors, fmt.Sprintf("invalid function config: %s", err))
+		} else {
+			res.Errors = append(res.Errors, validationErrors(ctx, fn)...)
+		}
+		results = append(results, res)
+	}
+	return results, nil
+}
+
+// validationErrors returns each error from validating the function.
+func validationErrors(ctx context.Context, fn *inngest.Function) []string {
+	err := fn.Validate(ctx)
+	if err == nil {
+		return nil
+	}
+	merr := &multierror.Error{}
+	if !errors.As(err, &merr) {
+		return []string{err.Error()}
+	}
+	msgs := make([]string, len(merr.Errors))
+	for n, e := range merr.Errors {
+		msgs[n] = e.Error()
+	}
+	return msgs
+}
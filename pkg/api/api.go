@@ -3,7 +3,9 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"net/url"
 	"strings"
@@ -14,8 +16,11 @@ import (
 	"github.com/inngest/inngest/pkg/config"
 	"github.com/inngest/inngest/pkg/consts"
 	"github.com/inngest/inngest/pkg/coreapi/apiutil"
+	"github.com/inngest/inngest/pkg/cqrs"
+	"github.com/inngest/inngest/pkg/devserver/environment"
 	"github.com/inngest/inngest/pkg/event"
 	"github.com/inngest/inngest/pkg/eventstream"
+	"github.com/inngest/inngest/pkg/execution/ratelimit"
 	"github.com/inngest/inngest/pkg/headers"
 	"github.com/inngest/inngest/pkg/publicerr"
 	"github.com/inngest/inngest/pkg/telemetry"
@@ -24,25 +29,46 @@ import (
 	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc"
 )
 
 type EventHandler func(context.Context, *event.Event) (string, error)
 
+// EventChecker checks whether an event may be accepted before it's handled.  Events
+// are rejected with the status of any returned publicerr.Error, defaulting to 400.
+type EventChecker func(context.Context, *event.Event) error
+
 type Options struct {
 	Config config.Config
 
 	EventHandler EventHandler
 	Logger       *zerolog.Logger
+
+	// EventChecker rejects events before they're handled, eg. as a function
+	// triggered by the event can't hold any more batches.  If nil, every valid
+	// event is handled.
+	EventChecker EventChecker
+
+	// EventKeys authenticates event keys sent to the event API.  If nil, or if
+	// no event keys have been created, any event key is accepted.
+	EventKeys cqrs.EventKeyReader
+
+	// RateLimiter enforces the event API rate limits in the config.  If nil,
+	// requests aren't rate limited.
+	RateLimiter ratelimit.RateLimiter
 }
 
 func NewAPI(o Options) (chi.Router, error) {
 	logger := o.Logger.With().Str("caller", "api").Logger()
 
 	api := &API{
-		Router:  chi.NewMux(),
-		config:  o.Config,
-		handler: o.EventHandler,
-		log:     &logger,
+		Router:      chi.NewMux(),
+		config:      o.Config,
+		handler:     o.EventHandler,
+		checkEvent:  o.EventChecker,
+		eventKeys:   o.EventKeys,
+		rateLimiter: o.RateLimiter,
+		log:         &logger,
 	}
 
 	cors := cors.New(cors.Options{
@@ -55,9 +81,13 @@ func NewAPI(o Options) (chi.Router, error) {
 	})
 	api.Use(cors.Handler)
 	api.Use(headers.StaticHeadersMiddleware(headers.ServerKindDev))
+	// Requests act within the environment selected by the X-Inngest-Env header,
+	// eg. to sync apps or send events within a branch environment.
+	api.Use(environment.Middleware)
 
 	api.Get("/health", api.HealthCheck)
-	api.Post("/e/{key}", api.ReceiveEvent)
+	api.With(api.rateLimit).Post("/e/{key}", api.ReceiveEvent)
+	api.With(api.rateLimit).Post("/e/{key}/bulk", api.ReceiveBulkEvents)
 	api.Post("/invoke/{slug}", api.Invoke)
 
 	return api, nil
@@ -68,10 +98,15 @@ type API struct {
 
 	config config.Config
 
-	handler EventHandler
-	log     *zerolog.Logger
+	handler     EventHandler
+	checkEvent  EventChecker
+	eventKeys   cqrs.EventKeyReader
+	rateLimiter ratelimit.RateLimiter
+	log         *zerolog.Logger
 
 	server *http.Server
+	// grpcServer serves the gRPC EventService, if a gRPC port is configured.
+	grpcServer *grpc.Server
 }
 
 func (a *API) AddRoutes() {
@@ -82,11 +117,31 @@ func (a *API) Start(ctx context.Context) error {
 		Addr:    fmt.Sprintf("%s:%d", a.config.EventAPI.Addr, a.config.EventAPI.Port),
 		Handler: a.Router,
 	}
+
+	if a.config.EventAPI.GRPCPort > 0 {
+		addr := fmt.Sprintf("%s:%d", a.config.EventAPI.Addr, a.config.EventAPI.GRPCPort)
+		l, err := net.Listen("tcp", addr)
+		if err != nil {
+			return fmt.Errorf("error listening for grpc: %w", err)
+		}
+		a.grpcServer = newGRPCServer(a)
+		a.log.Info().Str("addr", addr).Msg("starting grpc server")
+		go func() {
+			if err := a.grpcServer.Serve(l); err != nil {
+				a.log.Error().Err(err).Msg("error serving grpc")
+			}
+		}()
+	}
+
 	a.log.Info().Str("addr", a.server.Addr).Msg("starting server")
 	return a.server.ListenAndServe()
 }
 
 func (a API) Stop(ctx context.Context) error {
+	if a.grpcServer != nil {
+		a.grpcServer.GracefulStop()
+	}
+
 	if a.server == nil {
 		return nil
 	}
@@ -103,16 +158,10 @@ func (a API) HealthCheck(w http.ResponseWriter, r *http.Request) {
 }
 
 func (a API) ReceiveEvent(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
 	defer r.Body.Close()
 
-	key := chi.URLParam(r, "key")
-	if key == "" {
-		a.writeResponse(w, apiResponse{
-			StatusCode: http.StatusUnauthorized,
-			Error:      "Event key is required",
-		})
-
+	ctx, ok := a.authenticateRequest(w, r)
+	if !ok {
 		return
 	}
 
@@ -153,39 +202,8 @@ func (a API) ReceiveEvent(w http.ResponseWriter, r *http.Request) {
 		defer close(idChan)
 
 		for s := range stream {
-			evt := event.Event{}
-			if err := json.Unmarshal(s.Item, &evt); err != nil {
-				return err
-			}
-
-			if strings.HasPrefix(strings.ToLower(evt.Name), "inngest/") {
-				err := fmt.Errorf("event name is reserved for internal use: %s", evt.Name)
-				return err
-			}
-
-			ts := time.Now()
-			if evt.Timestamp == 0 {
-				evt.Timestamp = ts.UnixMilli()
-			}
-
-			if err := evt.Validate(ctx); err != nil {
-				return err
-			}
-
-			ctx, span := telemetry.UserTracer().Provider().
-				Tracer(consts.OtelScopeEvent).
-				Start(ctx, consts.OtelSpanEvent,
-					trace.WithTimestamp(ts),
-					trace.WithNewRoot(),
-					trace.WithLinks(trace.LinkFromContext(ctx)),
-					trace.WithAttributes(
-						attribute.Bool(consts.OtelUserTraceFilterKey, true),
-					))
-			defer span.End()
-
-			id, err := a.handler(ctx, &evt)
+			id, err := a.handleEvent(ctx, s.Item)
 			if err != nil {
-				a.log.Error().Str("event", evt.Name).Err(err).Msg("error handling event")
 				return err
 			}
 			idChan <- struct {
@@ -205,10 +223,11 @@ func (a API) ReceiveEvent(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err != nil {
-		w.WriteHeader(400)
+		status := eventErrorStatus(err)
+		w.WriteHeader(status)
 		_ = json.NewEncoder(w).Encode(apiutil.EventAPIResponse{
 			IDs:    ids[0 : max+1],
-			Status: 400,
+			Status: status,
 			Error:  err.Error(),
 		})
 
@@ -222,6 +241,130 @@ func (a API) ReceiveEvent(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// handleEvent parses, validates, and handles a single JSON encoded event sent to
+// the event API, returning the event's ID.
+func (a API) handleEvent(ctx context.Context, byt []byte) (string, error) {
+	evt := event.Event{}
+	if err := json.Unmarshal(byt, &evt); err != nil {
+		return "", err
+	}
+	return a.publishEvent(ctx, evt)
+}
+
+// publishEvent validates and handles a single event, returning the event's ID.
+func (a API) publishEvent(ctx context.Context, evt event.Event) (string, error) {
+	if strings.HasPrefix(strings.ToLower(evt.Name), "inngest/") {
+		return "", fmt.Errorf("event name is reserved for internal use: %s", evt.Name)
+	}
+
+	ts := time.Now()
+	if evt.Timestamp == 0 {
+		evt.Timestamp = ts.UnixMilli()
+	}
+
+	if err := evt.Validate(ctx); err != nil {
+		return "", err
+	}
+
+	if a.checkEvent != nil {
+		if err := a.checkEvent(ctx, &evt); err != nil {
+			return "", err
+		}
+	}
+
+	// Store the caller's trace context so that runs link to the caller's trace.
+	telemetry.InjectEventTraceContext(ctx, &evt)
+
+	ctx, span := telemetry.UserTracer().Provider().
+		Tracer(consts.OtelScopeEvent).
+		Start(ctx, consts.OtelSpanEvent,
+			trace.WithTimestamp(ts),
+			trace.WithNewRoot(),
+			trace.WithLinks(trace.LinkFromContext(ctx)),
+			trace.WithAttributes(
+				attribute.Bool(consts.OtelUserTraceFilterKey, true),
+			))
+	defer span.End()
+
+	id, err := a.handler(ctx, &evt)
+	if err != nil {
+		a.log.Error().Str("event", evt.Name).Err(err).Msg("error handling event")
+		return "", err
+	}
+	return id, nil
+}
+
+// eventErrorStatus returns the HTTP status used when an event can't be accepted,
+// using the status of any publicerr.Error and defaulting to 400.
+func eventErrorStatus(err error) int {
+	perr := publicerr.Error{}
+	if errors.As(err, &perr) && perr.Status >= 400 {
+		return perr.Status
+	}
+	return http.StatusBadRequest
+}
+
+// authenticateRequest authenticates the event key within the request's URL,
+// returning the request's context within the key's environment.  This writes an
+// error response and returns false if the key is invalid.
+func (a API) authenticateRequest(w http.ResponseWriter, r *http.Request) (context.Context, bool) {
+	ctx := r.Context()
+	key := chi.URLParam(r, "key")
+	if key == "" {
+		a.writeResponse(w, apiResponse{
+			StatusCode: http.StatusUnauthorized,
+			Error:      "Event key is required",
+		})
+		return ctx, false
+	}
+
+	ctx, err := a.authenticateKey(ctx, key)
+	if err != nil {
+		if errors.Is(err, cqrs.ErrEventKeyNotFound) {
+			a.writeResponse(w, apiResponse{
+				StatusCode: http.StatusUnauthorized,
+				Error:      "Event key not found",
+			})
+			return ctx, false
+		}
+		if errors.Is(err, cqrs.ErrEventKeyScope) {
+			a.writeResponse(w, apiResponse{
+				StatusCode: http.StatusForbidden,
+				Error:      "Event key does not have the events:write scope",
+			})
+			return ctx, false
+		}
+		a.log.Error().Err(err).Msg("error authenticating event key")
+		a.writeResponse(w, apiResponse{
+			StatusCode: http.StatusInternalServerError,
+			Error:      "Unable to authenticate event key",
+		})
+		return ctx, false
+	}
+	return ctx, true
+}
+
+// authenticateKey returns cqrs.ErrEventKeyNotFound if the given event key isn't
+// an active event key, or cqrs.ErrEventKeyScope if the key can't send events.
+// Event keys are only enforced once at least one key has been created, so that
+// any key can be used in development.
+//
+// Keys created for an environment always send events to that environment, so the
+// returned context acts within the key's environment.
+func (a API) authenticateKey(ctx context.Context, key string) (context.Context, error) {
+	if a.eventKeys == nil {
+		return ctx, nil
+	}
+	k, err := cqrs.AuthorizeEventKey(ctx, a.eventKeys, key, cqrs.ScopeEventsWrite)
+	if err != nil {
+		return ctx, err
+	}
+	if k != nil && k.Env != "" {
+		ctx = environment.WithName(ctx, k.Env)
+	}
+	return ctx, nil
+}
+
 // Invoke creates an event to invoke a specific function.
 func (a API) Invoke(w http.ResponseWriter, r *http.Request) {
 	// XXX: In OSS self hosting, check signing keys here.
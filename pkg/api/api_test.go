@@ -0,0 +1,105 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/inngest/inngest/pkg/config"
+	"github.com/inngest/inngest/pkg/cqrs"
+	"github.com/inngest/inngest/pkg/cqrs/sqlitecqrs"
+	"github.com/inngest/inngest/pkg/devserver/environment"
+	"github.com/inngest/inngest/pkg/event"
+	"github.com/inngest/inngest/pkg/headers"
+	"github.com/inngest/inngest/pkg/publicerr"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReceiveEventEnvironment(t *testing.T) {
+	ctx := context.Background()
+	db, err := sqlitecqrs.New()
+	require.NoError(t, err)
+	data := sqlitecqrs.NewCQRS(db)
+
+	l := zerolog.Nop()
+	var env string
+	api, err := NewAPI(Options{
+		Config:    config.Config{},
+		Logger:    &l,
+		EventKeys: data,
+		EventHandler: func(ctx context.Context, evt *event.Event) (string, error) {
+			env = environment.Name(ctx)
+			return "id", nil
+		},
+	})
+	require.NoError(t, err)
+
+	send := func(key, header string) int {
+		env = ""
+		req := httptest.NewRequest(http.MethodPost, "/e/"+key, strings.NewReader(`{"name":"test/env","data":{}}`))
+		if header != "" {
+			req.Header.Set(headers.HeaderKeyEnv, header)
+		}
+		rec := httptest.NewRecorder()
+		api.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	t.Run("It selects the environment via the header", func(t *testing.T) {
+		require.Equal(t, http.StatusOK, send("key", ""))
+		require.Equal(t, environment.Default, env)
+		require.Equal(t, http.StatusOK, send("key", "staging"))
+		require.Equal(t, "staging", env)
+		require.Equal(t, http.StatusBadRequest, send("key", "not valid"))
+	})
+
+	t.Run("It sends events to the key's environment", func(t *testing.T) {
+		key, err := data.CreateEventKey(ctx, cqrs.CreateEventKeyOpts{Name: "branch", Env: "feature/x"})
+		require.NoError(t, err)
+		defer func() { _, _ = data.RevokeEventKey(ctx, key.ID) }()
+
+		require.Equal(t, http.StatusOK, send(key.Key, "staging"))
+		require.Equal(t, "feature/x", env)
+	})
+}
+
+func TestReceiveEventChecker(t *testing.T) {
+	l := zerolog.Nop()
+	handled := 0
+	api, err := NewAPI(Options{
+		Config: config.Config{},
+		Logger: &l,
+		EventHandler: func(ctx context.Context, evt *event.Event) (string, error) {
+			handled++
+			return "id", nil
+		},
+		EventChecker: func(ctx context.Context, evt *event.Event) error {
+			if evt.Name == "test/rejected" {
+				return publicerr.Errorf(http.StatusTooManyRequests, "too many batches")
+			}
+			if evt.Name == "test/invalid" {
+				return fmt.Errorf("invalid")
+			}
+			return nil
+		},
+	})
+	require.NoError(t, err)
+
+	send := func(name string) int {
+		req := httptest.NewRequest(http.MethodPost, "/e/key", strings.NewReader(fmt.Sprintf(`{"name":%q,"data":{}}`, name)))
+		rec := httptest.NewRecorder()
+		api.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	require.Equal(t, http.StatusOK, send("test/accepted"))
+	require.Equal(t, 1, handled)
+	// Rejected events use the status of the checker's error, defaulting to 400.
+	require.Equal(t, http.StatusTooManyRequests, send("test/rejected"))
+	require.Equal(t, http.StatusBadRequest, send("test/invalid"))
+	require.Equal(t, 1, handled)
+}
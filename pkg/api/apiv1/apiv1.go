@@ -29,14 +29,42 @@ type Opts struct {
 	Executor execution.Executor
 	// EventReader allows reading of events from storage.
 	EventReader EventReader
+	// EventWriter stores the invocation events which trigger delayed runs, if
+	// set.
+	EventWriter cqrs.EventWriter
 	// FunctionReader reads functions from a backing store.
 	FunctionReader cqrs.FunctionReader
 	// FunctionRunReader reads function runs, history, etc. from backing storage
 	FunctionRunReader cqrs.APIV1FunctionRunReader
+	// FunctionRunSearcher finds runs by their triggering event.  The run search
+	// route is only added if this is set.
+	FunctionRunSearcher cqrs.FunctionRunSearcher
+	// RunTimelineReader loads each run's merged timeline.  The run timeline
+	// route is only added if this is set.
+	RunTimelineReader cqrs.RunTimelineReader
+	// StepOutputReader loads each step's stored output.  The step output route
+	// is only added if this is set.
+	StepOutputReader cqrs.StepOutputReader
+	// RunBatchReader loads each run's batch of events, for SDKs which load
+	// batches lazily.  The run batch route is only added if this is set.
+	RunBatchReader cqrs.RunBatchReader
 	// JobQueueReader reads information around a function run's job queues.
 	JobQueueReader queue.JobQueueReader
 	// CancellationReadWriter reads and writes cancellations to/from a backing store.
 	CancellationReadWriter cqrs.CancellationReadWriter
+	// EventKeyManager creates and manages event keys.  Event key routes are
+	// only added if this is set.
+	EventKeyManager cqrs.EventKeyManager
+	// ConcurrencyLimitUpdater updates the concurrency limits of queued jobs.
+	// The concurrency route is only added if this is set.
+	ConcurrencyLimitUpdater queue.ConcurrencyLimitUpdater
+	// FunctionArchiver archives and unarchives functions.  Archive routes are
+	// only added if this is set.
+	FunctionArchiver cqrs.FunctionArchiver
+	// RequireEventKeys requires each request to be authenticated with an event
+	// key granted the route's scope, once any event key exists.  This requires
+	// EventKeyManager to be set.
+	RequireEventKeys bool
 }
 
 // AddRoutes adds a new API handler to the given router.
@@ -73,25 +101,99 @@ type router struct {
 func (a *router) setup() {
 	a.Group(func(r chi.Router) {
 		r.Use(middleware.Recoverer)
+		r.Use(headers.ContentTypeJsonResponse())
+
+		// The OpenAPI document is generated from the routes below, and is
+		// public so that clients can be generated without an event key.
+		r.Get("/openapi.json", a.getOpenAPI)
+
+		r.Group(func(r chi.Router) {
+			// Check scopes before caching, so that cached responses are never
+			// served to unauthorized requests.
+			r.Use(a.requireScope(cqrs.ScopeRunsRead))
+			if a.opts.CachingMiddleware != nil {
+				r.Use(a.opts.CachingMiddleware.Middleware)
+			}
+
+			r.Get("/events", a.getEvents)
+			r.Get("/events/{eventID}", a.getEvent)
+			r.Get("/events/{eventID}/runs", a.getEventRuns)
+			r.Get("/runs/{runID}", a.GetFunctionRun)
+			r.Get("/runs/{runID}/jobs", a.GetFunctionRunJobs)
+			r.Get("/runs/{runID}/scheduled", a.GetRunScheduledJobs)
+
+			r.Get("/apps/{appName}/functions", a.GetAppFunctions) // Returns an app and all of its functions.
+			r.Get("/functions/{functionID}/queue", a.GetFunctionQueue)
+			r.Get("/functions/{functionID}/queue/peek", a.PeekFunctionQueue)
+			r.Get("/functions/{functionID}/scheduled", a.GetFunctionScheduledJobs)
+			r.Get("/functions/{functionID}/runs/scheduled", a.GetScheduledFunctionRuns)
+
+			if a.opts.CancellationReadWriter != nil {
+				r.Get("/cancellations", a.getCancellations)
+			}
+			if a.opts.FunctionRunSearcher != nil {
+				r.Get("/runs", a.searchFunctionRuns)
+			}
+			if a.opts.RunTimelineReader != nil {
+				r.Get("/runs/{runID}/timeline", a.GetFunctionRunTimeline)
+			}
+			if a.opts.StepOutputReader != nil {
+				r.Get("/runs/{runID}/steps/{stepID}/output", a.GetStepOutput)
+			}
+			if a.opts.RunBatchReader != nil {
+				r.Get("/runs/{runID}/batch", a.GetRunBatch)
+			}
+		})
+
+		r.Group(func(r chi.Router) {
+			r.Use(a.requireScope(cqrs.ScopeEventsWrite))
+
+			r.Post("/functions/{functionID}/runs", a.ScheduleFunctionRun)
+		})
+
+		r.Group(func(r chi.Router) {
+			r.Use(a.requireScope(cqrs.ScopeRunsCancel))
+
+			r.Delete("/runs/{runID}", a.cancelFunctionRun)
+			r.Delete("/functions/{functionID}/runs/scheduled/{runID}", a.CancelScheduledFunctionRun)
+
+			if a.opts.CancellationReadWriter != nil {
+				r.Post("/cancellations", a.createCancellation)
+				r.Delete("/cancellations/{id}", a.deleteCancellation)
+			}
+		})
+
+		if a.opts.FunctionArchiver != nil {
+			r.Group(func(r chi.Router) {
+				r.Use(a.requireScope(cqrs.ScopeAdmin))
 
-		if a.opts.CachingMiddleware != nil {
-			r.Use(a.opts.CachingMiddleware.Middleware)
+				r.Post("/functions/{functionID}/archive", a.ArchiveFunction)
+				r.Delete("/functions/{functionID}/archive", a.UnarchiveFunction)
+			})
 		}
 
-		r.Use(headers.ContentTypeJsonResponse())
+		if a.opts.ConcurrencyLimitUpdater != nil {
+			r.Group(func(r chi.Router) {
+				r.Use(a.requireScope(cqrs.ScopeAdmin))
 
-		r.Get("/events", a.getEvents)
-		r.Get("/events/{eventID}", a.getEvent)
-		r.Get("/events/{eventID}/runs", a.getEventRuns)
-		r.Get("/runs/{runID}", a.GetFunctionRun)
-		r.Delete("/runs/{runID}", a.cancelFunctionRun)
-		r.Get("/runs/{runID}/jobs", a.GetFunctionRunJobs)
+				r.Put("/functions/{functionID}/concurrency", a.UpdateConcurrencyLimits)
+			})
+		}
 
-		r.Get("/apps/{appName}/functions", a.GetAppFunctions) // Returns an app and all of its functions.
+		if a.opts.EventKeyManager != nil {
+			r.Group(func(r chi.Router) {
+				// Keys grant access to every other route, so managing keys
+				// always requires an admin key once any key exists, and the
+				// first key may only be created locally.
+				r.Use(a.enforceScope(cqrs.ScopeAdmin))
+				r.Use(a.requireLocalBootstrap)
 
-		r.Post("/cancellations", a.createCancellation)
-		r.Get("/cancellations", a.getCancellations)
-		r.Delete("/cancellations/{id}", a.deleteCancellation)
+				r.Post("/event-keys", a.createEventKey)
+				r.Get("/event-keys", a.getEventKeys)
+				r.Post("/event-keys/{id}/rotate", a.rotateEventKey)
+				r.Delete("/event-keys/{id}", a.revokeEventKey)
+			})
+		}
 	})
 }
 
@@ -99,6 +201,19 @@ func WriteResponse[T any](w http.ResponseWriter, data T) error {
 	return WriteCachedResponse(w, data, 0)
 }
 
+// WriteCursorResponse writes a page of data, along with the cursor used to fetch
+// the next page.  An empty cursor indicates that there are no more pages.
+func WriteCursorResponse[T any](w http.ResponseWriter, data T, cursor string) error {
+	resp := Response[T]{
+		Data: data,
+		Metadata: ResponseMetadata{
+			FetchedAt: time.Now(),
+			Cursor:    cursor,
+		},
+	}
+	return json.NewEncoder(w).Encode(resp)
+}
+
 func WriteCachedResponse[T any](w http.ResponseWriter, data T, cachePeriod time.Duration) error {
 	resp := Response[T]{
 		Data: data,
@@ -128,6 +243,8 @@ type Response[T any] struct {
 type ResponseMetadata struct {
 	FetchedAt   time.Time  `json:"fetched_at,omitempty"`
 	CachedUntil *time.Time `json:"cached_until,omitempty"`
+	// Cursor fetches the next page of paginated responses.
+	Cursor string `json:"cursor,omitempty"`
 }
 
 // TODO (tonyhb) Open source the auth context.
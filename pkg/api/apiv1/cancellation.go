@@ -10,6 +10,7 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	"github.com/inngest/inngest/pkg/cqrs"
+	"github.com/inngest/inngest/pkg/expressions"
 	"github.com/inngest/inngest/pkg/publicerr"
 	"github.com/oklog/ulid/v2"
 )
@@ -38,7 +39,7 @@ func (a API) DeleteCancellation(ctx context.Context, cancellationID ulid.ULID) e
 		}
 	}
 
-	return publicerr.Wrap(err, 404, "Cancellation not found")
+	return publicerr.Errorf(404, "Cancellation not found")
 }
 
 // DeleteCancellation is the HTTP handler implementation.
@@ -83,10 +84,15 @@ type CreateCancellationBody struct {
 	// AppID is the client ID specified via the SDK in the app that defines the function.
 	AppID string `json:"app_id"`
 	// FunctionID is the function ID string specified in configuration via the SDK.
-	FunctionID    string     `json:"function_id"`
-	StartedAfter  *time.Time `json:"started_after"`
-	StartedBefore time.Time  `json:"started_before"`
-	If            *string    `json:"if,omitempty"`
+	FunctionID string  `json:"function_id"`
+	Name       *string `json:"name,omitempty"`
+	// StartedAfter optionally cancels only runs started after this time.
+	StartedAfter *time.Time `json:"started_after"`
+	// StartedBefore cancels runs started before this time, defaulting to now.
+	StartedBefore time.Time `json:"started_before"`
+	// If is an optional expression evaluated against the run's triggering event,
+	// eg. "event.data.user_id == 'u_123'".  Only matching runs are cancelled.
+	If *string `json:"if,omitempty"`
 }
 
 func (a API) CreateCancellation(ctx context.Context, opts CreateCancellationBody) (*cqrs.Cancellation, error) {
@@ -94,6 +100,18 @@ func (a API) CreateCancellation(ctx context.Context, opts CreateCancellationBody
 	if err != nil {
 		return nil, publicerr.Wrap(err, 401, "No auth found")
 	}
+	if opts.StartedBefore.IsZero() {
+		opts.StartedBefore = time.Now()
+	}
+	if opts.StartedAfter != nil && opts.StartedAfter.After(opts.StartedBefore) {
+		return nil, publicerr.Errorf(400, "started_after must be before started_before")
+	}
+	if opts.If != nil {
+		if err := expressions.Validate(ctx, *opts.If); err != nil {
+			return nil, publicerr.Wrap(err, 400, "Invalid cancellation expression")
+		}
+	}
+
 	fn, err := a.opts.FunctionReader.GetFunctionByExternalID(
 		ctx,
 		auth.WorkspaceID(),
@@ -105,6 +123,8 @@ func (a API) CreateCancellation(ctx context.Context, opts CreateCancellationBody
 	}
 	// Create a new cancellation for the given function ID
 	cancel := cqrs.Cancellation{
+		CreatedAt:     time.Now(),
+		Name:          opts.Name,
 		ID:            ulid.MustNew(ulid.Now(), rand.Reader),
 		WorkspaceID:   auth.WorkspaceID(),
 		FunctionID:    fn.ID,
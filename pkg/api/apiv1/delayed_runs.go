@@ -0,0 +1,256 @@
+package apiv1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/inngest/inngest/pkg/cqrs"
+	"github.com/inngest/inngest/pkg/event"
+	"github.com/inngest/inngest/pkg/execution"
+	"github.com/inngest/inngest/pkg/execution/queue"
+	"github.com/inngest/inngest/pkg/execution/state"
+	"github.com/inngest/inngest/pkg/publicerr"
+	"github.com/oklog/ulid/v2"
+)
+
+// ScheduleRunRequest is the request body used to schedule a single run of a
+// function in the future.
+type ScheduleRunRequest struct {
+	// At is the time that the run starts.
+	At time.Time `json:"at"`
+	// ID is an optional idempotency ID for the run.  Scheduling a run with the
+	// same ID twice only schedules a single run.
+	ID   string         `json:"id,omitempty"`
+	Data map[string]any `json:"data,omitempty"`
+	User map[string]any `json:"user,omitempty"`
+}
+
+// ScheduledRun is a run which is scheduled to start in the future.
+type ScheduledRun struct {
+	RunID      ulid.ULID `json:"run_id"`
+	FunctionID uuid.UUID `json:"function_id"`
+	// EventID is the internal ID of the invocation event that triggers the run.
+	// This is only set when scheduling a run.
+	EventID *ulid.ULID `json:"event_id,omitempty"`
+	// At is the time that the run starts.
+	At time.Time `json:"at"`
+}
+
+// ScheduleFunctionRun schedules a single run of the given function to start at
+// the requested time, triggered by an invocation event with the given payload.
+func (a API) ScheduleFunctionRun(ctx context.Context, fnID uuid.UUID, req ScheduleRunRequest) (*ScheduledRun, error) {
+	auth, err := a.opts.AuthFinder(ctx)
+	if err != nil {
+		return nil, publicerr.Wrap(err, 401, "No auth found")
+	}
+
+	if req.At.IsZero() {
+		return nil, publicerr.Errorf(400, "A run time must be specified")
+	}
+	if req.At.Before(time.Now()) {
+		return nil, publicerr.Errorf(400, "Runs must be scheduled in the future: %s", req.At.Format(time.RFC3339))
+	}
+
+	cfn, err := a.opts.FunctionReader.GetFunctionByInternalUUID(ctx, auth.WorkspaceID(), fnID)
+	if err != nil || cfn == nil {
+		return nil, publicerr.Wrap(err, 404, "Function not found")
+	}
+	if cfn.ArchivedAt != nil {
+		return nil, publicerr.Errorf(400, "Function is archived: %s", cfn.Slug)
+	}
+	fn, err := cfn.InngestFunction()
+	if err != nil {
+		return nil, publicerr.Wrap(err, 500, "Unable to load function")
+	}
+
+	evt := event.NewInvocationEvent(event.NewInvocationEventOpts{
+		Event: event.Event{
+			ID:   req.ID,
+			Data: req.Data,
+			User: req.User,
+		},
+		FnID: cfn.Slug,
+	})
+	tracked := event.NewOSSTrackedEventForWorkspace(evt, auth.WorkspaceID())
+
+	if a.opts.EventWriter != nil {
+		e := cqrs.ConvertFromEvent(tracked.GetInternalID(), evt)
+		e.AccountID = auth.AccountID()
+		e.WorkspaceID = auth.WorkspaceID()
+		if err := a.opts.EventWriter.InsertEvent(ctx, e); err != nil {
+			return nil, publicerr.Wrap(err, 500, "Unable to save invocation event")
+		}
+	}
+
+	sr := execution.ScheduleRequest{
+		Function:    *fn,
+		At:          &req.At,
+		AccountID:   auth.AccountID(),
+		WorkspaceID: auth.WorkspaceID(),
+		AppID:       cfn.AppID,
+		Events:      []event.TrackedEvent{tracked},
+		// Delayed runs start at the requested time, and are never debounced.
+		PreventDebounce: true,
+	}
+	if req.ID != "" {
+		sr.IdempotencyKey = &req.ID
+	}
+
+	id, err := a.opts.Executor.Schedule(ctx, sr)
+	if errors.Is(err, state.ErrIdentifierExists) {
+		return nil, publicerr.Wrapf(err, 409, "A run with this ID has already been scheduled: %s", req.ID)
+	}
+	if err != nil {
+		return nil, publicerr.Wrap(err, 500, "Unable to schedule run")
+	}
+
+	eventID := tracked.GetInternalID()
+	return &ScheduledRun{
+		RunID:      id.RunID,
+		FunctionID: fn.ID,
+		EventID:    &eventID,
+		At:         req.At,
+	}, nil
+}
+
+// GetScheduledFunctionRuns lists the given function's runs which are scheduled
+// to start in the future, ordered by the time they start.
+func (a API) GetScheduledFunctionRuns(ctx context.Context, fnID uuid.UUID, limit int64) ([]ScheduledRun, error) {
+	jobs, err := a.GetFunctionScheduledJobs(ctx, fnID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	runs := []ScheduledRun{}
+	for _, job := range jobs {
+		if job.Kind != queue.KindStart {
+			continue
+		}
+		runs = append(runs, ScheduledRun{
+			RunID:      job.RunID,
+			FunctionID: job.FunctionID,
+			At:         job.At,
+		})
+	}
+	return runs, nil
+}
+
+// CancelScheduledFunctionRun cancels a run which is scheduled to start in the
+// future, so that the run never starts.
+func (a API) CancelScheduledFunctionRun(ctx context.Context, fnID uuid.UUID, runID ulid.ULID) error {
+	auth, err := a.opts.AuthFinder(ctx)
+	if err != nil {
+		return publicerr.Wrap(err, 401, "No auth found")
+	}
+
+	remover, ok := a.opts.JobQueueReader.(queue.RunStartRemover)
+	if !ok {
+		return publicerr.Errorf(501, "The queue does not support cancelling scheduled runs")
+	}
+
+	fn, err := a.opts.FunctionReader.GetFunctionByInternalUUID(ctx, auth.WorkspaceID(), fnID)
+	if err != nil || fn == nil {
+		return publicerr.Wrap(err, 404, "Function not found")
+	}
+
+	// Ensure that the run belongs to the function before removing its job.
+	jobs, err := a.opts.JobQueueReader.ScheduledJobs(ctx, auth.WorkspaceID(), fn.ID, &runID, 0)
+	if err != nil {
+		return publicerr.Wrap(err, 500, "Unable to read scheduled jobs")
+	}
+	found := false
+	for _, job := range jobs {
+		if job.Kind == queue.KindStart {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return publicerr.Errorf(404, "Scheduled run not found: %s", runID)
+	}
+
+	if err := remover.RemoveRunStart(ctx, runID); err != nil {
+		if errors.Is(err, queue.ErrJobNotFound) {
+			return publicerr.Wrapf(err, 404, "Scheduled run not found: %s", runID)
+		}
+		return publicerr.Wrapf(err, 500, "Unable to cancel scheduled run: %s", runID)
+	}
+
+	// Cancel the run, marking the run as cancelled in history and removing its
+	// state.
+	if err := a.opts.Executor.Cancel(ctx, runID, execution.CancelRequest{}); err != nil {
+		return publicerr.Wrapf(err, 500, "Unable to cancel scheduled run: %s", runID)
+	}
+	return nil
+}
+
+// ScheduleFunctionRun is the route wrapper for the ScheduleFunctionRun API handler.
+func (a router) ScheduleFunctionRun(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	ctx := r.Context()
+	fnID, err := uuid.Parse(chi.URLParam(r, "functionID"))
+	if err != nil {
+		_ = publicerr.WriteHTTP(w, publicerr.Wrapf(err, 400, "Invalid function ID: %s", chi.URLParam(r, "functionID")))
+		return
+	}
+	req := ScheduleRunRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		_ = publicerr.WriteHTTP(w, publicerr.Wrap(err, 400, "Invalid request"))
+		return
+	}
+	run, err := a.API.ScheduleFunctionRun(ctx, fnID, req)
+	if err != nil {
+		_ = publicerr.WriteHTTP(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	_ = WriteResponse(w, run)
+}
+
+// GetScheduledFunctionRuns is the route wrapper for the GetScheduledFunctionRuns
+// API handler.
+func (a router) GetScheduledFunctionRuns(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	fnID, err := uuid.Parse(chi.URLParam(r, "functionID"))
+	if err != nil {
+		_ = publicerr.WriteHTTP(w, publicerr.Wrapf(err, 400, "Invalid function ID: %s", chi.URLParam(r, "functionID")))
+		return
+	}
+	limit, err := scheduledLimit(r)
+	if err != nil {
+		_ = publicerr.WriteHTTP(w, err)
+		return
+	}
+	runs, err := a.API.GetScheduledFunctionRuns(ctx, fnID, limit)
+	if err != nil {
+		_ = publicerr.WriteHTTP(w, err)
+		return
+	}
+	_ = WriteResponse(w, runs)
+}
+
+// CancelScheduledFunctionRun is the route wrapper for the
+// CancelScheduledFunctionRun API handler.
+func (a router) CancelScheduledFunctionRun(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	fnID, err := uuid.Parse(chi.URLParam(r, "functionID"))
+	if err != nil {
+		_ = publicerr.WriteHTTP(w, publicerr.Wrapf(err, 400, "Invalid function ID: %s", chi.URLParam(r, "functionID")))
+		return
+	}
+	runID, err := ulid.Parse(chi.URLParam(r, "runID"))
+	if err != nil {
+		_ = publicerr.WriteHTTP(w, publicerr.Wrapf(err, 400, "Invalid run ID: %s", chi.URLParam(r, "runID")))
+		return
+	}
+	if err := a.API.CancelScheduledFunctionRun(ctx, fnID, runID); err != nil {
+		_ = publicerr.WriteHTTP(w, err)
+		return
+	}
+	_ = WriteResponse(w, map[string]any{"ok": true})
+}
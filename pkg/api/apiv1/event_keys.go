@@ -0,0 +1,141 @@
+package apiv1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/inngest/inngest/pkg/cqrs"
+	"github.com/inngest/inngest/pkg/devserver/environment"
+	"github.com/inngest/inngest/pkg/publicerr"
+	"github.com/oklog/ulid/v2"
+)
+
+// CreateEventKey creates a new event key.  The returned key contains the key's
+// plaintext value, which is not retrievable after creation.
+func (a API) CreateEventKey(ctx context.Context, opts cqrs.CreateEventKeyOpts) (*cqrs.EventKey, error) {
+	opts.Name = strings.TrimSpace(opts.Name)
+	if opts.Name == "" {
+		return nil, publicerr.Errorf(400, "Event keys must have a name")
+	}
+	for _, scope := range opts.Scopes {
+		if !scope.Valid() {
+			return nil, publicerr.Errorf(400, "Invalid event key scope: %s", scope)
+		}
+	}
+	if opts.Env != "" {
+		if err := environment.Validate(opts.Env); err != nil {
+			return nil, publicerr.Wrap(err, 400, "Invalid event key environment")
+		}
+		opts.Env = environment.Normalize(opts.Env)
+	}
+
+	// The first key is created without authentication, and keys are managed
+	// using admin keys, so the first key is always granted the admin scope.
+	// Otherwise no key could manage keys once the first key exists.
+	exists, err := a.opts.EventKeyManager.HasEventKeys(ctx)
+	if err != nil {
+		return nil, publicerr.Wrap(err, 500, "Error creating event key")
+	}
+	if !exists && !(cqrs.EventKey{Scopes: opts.Scopes}).HasScope(cqrs.ScopeAdmin) {
+		if len(opts.Scopes) == 0 {
+			opts.Scopes = append(opts.Scopes, cqrs.DefaultEventKeyScopes...)
+		}
+		opts.Scopes = append(opts.Scopes, cqrs.ScopeAdmin)
+	}
+
+	key, err := a.opts.EventKeyManager.CreateEventKey(ctx, opts)
+	if err != nil {
+		return nil, publicerr.Wrap(err, 500, "Error creating event key")
+	}
+	return key, nil
+}
+
+func (a router) createEventKey(w http.ResponseWriter, r *http.Request) {
+	opts := cqrs.CreateEventKeyOpts{}
+	if err := json.NewDecoder(r.Body).Decode(&opts); err != nil {
+		_ = publicerr.WriteHTTP(w, publicerr.Wrap(err, 400, "Invalid event key request"))
+		return
+	}
+	key, err := a.API.CreateEventKey(r.Context(), opts)
+	if err != nil {
+		_ = publicerr.WriteHTTP(w, err)
+		return
+	}
+	_ = WriteResponse(w, key)
+}
+
+// GetEventKeys returns all event keys, including revoked keys.
+func (a API) GetEventKeys(ctx context.Context) ([]*cqrs.EventKey, error) {
+	keys, err := a.opts.EventKeyManager.GetEventKeys(ctx)
+	if err != nil {
+		return nil, publicerr.Wrap(err, 500, "Error listing event keys")
+	}
+	return keys, nil
+}
+
+func (a router) getEventKeys(w http.ResponseWriter, r *http.Request) {
+	keys, err := a.API.GetEventKeys(r.Context())
+	if err != nil {
+		_ = publicerr.WriteHTTP(w, err)
+		return
+	}
+	_ = WriteResponse(w, keys)
+}
+
+// RotateEventKey replaces an event key's value, immediately invalidating the
+// previous value.  The returned key contains the new plaintext value.
+func (a API) RotateEventKey(ctx context.Context, id ulid.ULID) (*cqrs.EventKey, error) {
+	key, err := a.opts.EventKeyManager.RotateEventKey(ctx, id)
+	if errors.Is(err, cqrs.ErrEventKeyNotFound) {
+		return nil, publicerr.Wrap(err, 404, "Event key not found")
+	}
+	if err != nil {
+		return nil, publicerr.Wrap(err, 500, "Error rotating event key")
+	}
+	return key, nil
+}
+
+func (a router) rotateEventKey(w http.ResponseWriter, r *http.Request) {
+	id, err := ulid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		_ = publicerr.WriteHTTP(w, publicerr.Wrap(err, 400, "Invalid event key ID"))
+		return
+	}
+	key, err := a.API.RotateEventKey(r.Context(), id)
+	if err != nil {
+		_ = publicerr.WriteHTTP(w, err)
+		return
+	}
+	_ = WriteResponse(w, key)
+}
+
+// RevokeEventKey revokes an event key, immediately preventing the key from
+// sending events.
+func (a API) RevokeEventKey(ctx context.Context, id ulid.ULID) (*cqrs.EventKey, error) {
+	key, err := a.opts.EventKeyManager.RevokeEventKey(ctx, id)
+	if errors.Is(err, cqrs.ErrEventKeyNotFound) {
+		return nil, publicerr.Wrap(err, 404, "Event key not found")
+	}
+	if err != nil {
+		return nil, publicerr.Wrap(err, 500, "Error revoking event key")
+	}
+	return key, nil
+}
+
+func (a router) revokeEventKey(w http.ResponseWriter, r *http.Request) {
+	id, err := ulid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		_ = publicerr.WriteHTTP(w, publicerr.Wrap(err, 400, "Invalid event key ID"))
+		return
+	}
+	key, err := a.API.RevokeEventKey(r.Context(), id)
+	if err != nil {
+		_ = publicerr.WriteHTTP(w, err)
+		return
+	}
+	_ = WriteResponse(w, key)
+}
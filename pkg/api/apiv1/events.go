@@ -53,6 +53,13 @@ func (a router) getEvents(w http.ResponseWriter, r *http.Request) {
 	}
 	opts.Limit = util.Bound(limit, 1, cqrs.MaxEvents)
 
+	order, err := sortOrder(r)
+	if err != nil {
+		_ = publicerr.WriteHTTP(w, err)
+		return
+	}
+	opts.Order = order
+
 	if cursor := r.FormValue("cursor"); cursor != "" {
 		parsed, err := ulid.Parse(cursor)
 		if err != nil {
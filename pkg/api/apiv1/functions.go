@@ -4,9 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
 	"github.com/inngest/inngest/pkg/cqrs"
+	"github.com/inngest/inngest/pkg/execution/queue"
 	"github.com/inngest/inngest/pkg/publicerr"
 )
 
@@ -39,3 +42,201 @@ func (a router) GetAppFunctions(w http.ResponseWriter, r *http.Request) {
 	}
 	_ = json.NewEncoder(w).Encode(fns)
 }
+
+// GetFunctionQueue returns backlog information for the given function's queue.
+func (a API) GetFunctionQueue(ctx context.Context, fnID uuid.UUID) (*queue.PartitionStats, error) {
+	auth, err := a.opts.AuthFinder(ctx)
+	if err != nil {
+		return nil, publicerr.Wrap(err, 401, "No auth found")
+	}
+
+	fn, err := a.opts.FunctionReader.GetFunctionByInternalUUID(ctx, auth.WorkspaceID(), fnID)
+	if err != nil || fn == nil {
+		return nil, publicerr.Wrap(err, 404, "Function not found")
+	}
+
+	stats, err := a.opts.JobQueueReader.PartitionStats(ctx, fn.ID)
+	if err != nil {
+		return nil, publicerr.Wrap(err, 500, "Unable to read function queue")
+	}
+	return &stats, nil
+}
+
+// GetFunctionQueue is the route wrapper for the GetFunctionQueue API handler.
+func (a router) GetFunctionQueue(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	fnID, err := uuid.Parse(chi.URLParam(r, "functionID"))
+	if err != nil {
+		_ = publicerr.WriteHTTP(w, publicerr.Wrapf(err, 400, "Invalid function ID: %s", chi.URLParam(r, "functionID")))
+		return
+	}
+	stats, err := a.API.GetFunctionQueue(ctx, fnID)
+	if err != nil {
+		_ = publicerr.WriteHTTP(w, err)
+		return
+	}
+	_ = WriteCachedResponse(w, stats, 5*time.Second)
+}
+
+// PeekFunctionQueue returns the next jobs in the given function's queue without
+// leasing them, for debugging stuck functions.
+func (a API) PeekFunctionQueue(ctx context.Context, fnID uuid.UUID, limit int64) ([]queue.ScheduledJob, error) {
+	auth, err := a.opts.AuthFinder(ctx)
+	if err != nil {
+		return nil, publicerr.Wrap(err, 401, "No auth found")
+	}
+
+	fn, err := a.opts.FunctionReader.GetFunctionByInternalUUID(ctx, auth.WorkspaceID(), fnID)
+	if err != nil || fn == nil {
+		return nil, publicerr.Wrap(err, 404, "Function not found")
+	}
+
+	jobs, err := a.opts.JobQueueReader.PeekJobs(ctx, auth.WorkspaceID(), fn.ID, limit)
+	if err != nil {
+		return nil, publicerr.Wrap(err, 500, "Unable to peek function queue")
+	}
+	return jobs, nil
+}
+
+// PeekFunctionQueue is the route wrapper for the PeekFunctionQueue API handler.
+func (a router) PeekFunctionQueue(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	fnID, err := uuid.Parse(chi.URLParam(r, "functionID"))
+	if err != nil {
+		_ = publicerr.WriteHTTP(w, publicerr.Wrapf(err, 400, "Invalid function ID: %s", chi.URLParam(r, "functionID")))
+		return
+	}
+	limit, err := scheduledLimit(r)
+	if err != nil {
+		_ = publicerr.WriteHTTP(w, err)
+		return
+	}
+	jobs, err := a.API.PeekFunctionQueue(ctx, fnID, limit)
+	if err != nil {
+		_ = publicerr.WriteHTTP(w, err)
+		return
+	}
+	_ = WriteResponse(w, jobs)
+}
+
+// ArchiveFunction archives the given function.  Archived functions no longer
+// start new runs, while existing runs complete as usual.
+func (a API) ArchiveFunction(ctx context.Context, fnID uuid.UUID) (*cqrs.Function, error) {
+	return a.setArchived(ctx, fnID, true)
+}
+
+// UnarchiveFunction unarchives the given function, so that it starts new runs.
+func (a API) UnarchiveFunction(ctx context.Context, fnID uuid.UUID) (*cqrs.Function, error) {
+	return a.setArchived(ctx, fnID, false)
+}
+
+func (a API) setArchived(ctx context.Context, fnID uuid.UUID, archived bool) (*cqrs.Function, error) {
+	auth, err := a.opts.AuthFinder(ctx)
+	if err != nil {
+		return nil, publicerr.Wrap(err, 401, "No auth found")
+	}
+
+	fn, err := a.opts.FunctionReader.GetFunctionByInternalUUID(ctx, auth.WorkspaceID(), fnID)
+	if err != nil || fn == nil {
+		return nil, publicerr.Wrap(err, 404, "Function not found")
+	}
+
+	if archived {
+		fn, err = a.opts.FunctionArchiver.ArchiveFunction(ctx, fn.ID)
+	} else {
+		fn, err = a.opts.FunctionArchiver.UnarchiveFunction(ctx, fn.ID)
+	}
+	if err != nil {
+		return nil, publicerr.Wrap(err, 500, "Unable to update function")
+	}
+	return fn, nil
+}
+
+// ArchiveFunction is the route wrapper for the ArchiveFunction API handler.
+func (a router) ArchiveFunction(w http.ResponseWriter, r *http.Request) {
+	a.writeArchived(w, r, a.API.ArchiveFunction)
+}
+
+// UnarchiveFunction is the route wrapper for the UnarchiveFunction API handler.
+func (a router) UnarchiveFunction(w http.ResponseWriter, r *http.Request) {
+	a.writeArchived(w, r, a.API.UnarchiveFunction)
+}
+
+func (a router) writeArchived(w http.ResponseWriter, r *http.Request, f func(context.Context, uuid.UUID) (*cqrs.Function, error)) {
+	fnID, err := uuid.Parse(chi.URLParam(r, "functionID"))
+	if err != nil {
+		_ = publicerr.WriteHTTP(w, publicerr.Wrapf(err, 400, "Invalid function ID: %s", chi.URLParam(r, "functionID")))
+		return
+	}
+	fn, err := f(r.Context(), fnID)
+	if err != nil {
+		_ = publicerr.WriteHTTP(w, err)
+		return
+	}
+	_ = WriteResponse(w, fn)
+}
+
+// ConcurrencyLimitsRequest updates the custom concurrency limits of a function's
+// queued jobs.
+type ConcurrencyLimitsRequest struct {
+	// Limits lists the new limit for each custom concurrency key, keyed by the
+	// hash of the key's expression as listed in the function's configuration.
+	Limits map[string]int `json:"limits"`
+}
+
+// ConcurrencyLimitsResponse lists the number of queued jobs updated.
+type ConcurrencyLimitsResponse struct {
+	Updated int `json:"updated"`
+}
+
+// UpdateConcurrencyLimits updates the custom concurrency limits stored within the
+// given function's queued jobs, so that limits can be changed for work that's
+// already queued without waiting for each run's next step.
+func (a API) UpdateConcurrencyLimits(ctx context.Context, fnID uuid.UUID, limits map[string]int) (int, error) {
+	auth, err := a.opts.AuthFinder(ctx)
+	if err != nil {
+		return 0, publicerr.Wrap(err, 401, "No auth found")
+	}
+
+	if len(limits) == 0 {
+		return 0, publicerr.Errorf(400, "No concurrency limits specified")
+	}
+	for hash, limit := range limits {
+		if limit < 1 {
+			return 0, publicerr.Errorf(400, "Invalid concurrency limit for key %s: %d", hash, limit)
+		}
+	}
+
+	fn, err := a.opts.FunctionReader.GetFunctionByInternalUUID(ctx, auth.WorkspaceID(), fnID)
+	if err != nil || fn == nil {
+		return 0, publicerr.Wrap(err, 404, "Function not found")
+	}
+
+	n, err := a.opts.ConcurrencyLimitUpdater.UpdateConcurrencyLimits(ctx, fn.ID, limits)
+	if err != nil {
+		return n, publicerr.Wrap(err, 500, "Unable to update concurrency limits")
+	}
+	return n, nil
+}
+
+// UpdateConcurrencyLimits is the route wrapper for the UpdateConcurrencyLimits API
+// handler.
+func (a router) UpdateConcurrencyLimits(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	fnID, err := uuid.Parse(chi.URLParam(r, "functionID"))
+	if err != nil {
+		_ = publicerr.WriteHTTP(w, publicerr.Wrapf(err, 400, "Invalid function ID: %s", chi.URLParam(r, "functionID")))
+		return
+	}
+	req := ConcurrencyLimitsRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		_ = publicerr.WriteHTTP(w, publicerr.Wrap(err, 400, "Invalid request body"))
+		return
+	}
+	n, err := a.API.UpdateConcurrencyLimits(ctx, fnID, req.Limits)
+	if err != nil {
+		_ = publicerr.WriteHTTP(w, err)
+		return
+	}
+	_ = WriteResponse(w, ConcurrencyLimitsResponse{Updated: n})
+}
@@ -0,0 +1,116 @@
+package apiv1
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/inngest/inngest/pkg/cqrs"
+	"github.com/stretchr/testify/require"
+)
+
+// functionsByID reads functions by their internal ID.
+type functionsByID struct {
+	cqrs.FunctionReader
+	fns map[uuid.UUID]*cqrs.Function
+}
+
+func (f functionsByID) GetFunctionByInternalUUID(ctx context.Context, wsID uuid.UUID, fnID uuid.UUID) (*cqrs.Function, error) {
+	fn, ok := f.fns[fnID]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return fn, nil
+}
+
+// eventKeys authenticates the given plaintext keys.
+type eventKeys struct {
+	cqrs.EventKeyManager
+	keys map[string]*cqrs.EventKey
+}
+
+func (e eventKeys) GetEventKeyByKey(ctx context.Context, key string) (*cqrs.EventKey, error) {
+	k, ok := e.keys[key]
+	if !ok {
+		return nil, cqrs.ErrEventKeyNotFound
+	}
+	return k, nil
+}
+
+func (e eventKeys) HasEventKeys(ctx context.Context) (bool, error) {
+	return len(e.keys) > 0, nil
+}
+
+// limitUpdates records the concurrency limits updated for each function.
+type limitUpdates map[uuid.UUID]map[string]int
+
+func (l limitUpdates) UpdateConcurrencyLimits(ctx context.Context, workflowID uuid.UUID, limits map[string]int) (int, error) {
+	l[workflowID] = limits
+	return 3, nil
+}
+
+func (l limitUpdates) ConcurrencyLimitOverrides(ctx context.Context, workflowID uuid.UUID) (map[string]int, error) {
+	return l[workflowID], nil
+}
+
+func TestUpdateConcurrencyLimits(t *testing.T) {
+	fnID := uuid.New()
+	updates := limitUpdates{}
+	r := chi.NewRouter()
+	AddRoutes(r, Opts{
+		EventKeyManager: eventKeys{keys: map[string]*cqrs.EventKey{
+			"admin":  {Scopes: []cqrs.EventKeyScope{cqrs.ScopeAdmin}},
+			"reader": {Scopes: []cqrs.EventKeyScope{cqrs.ScopeRunsRead}},
+		}},
+		RequireEventKeys:        true,
+		FunctionReader:          functionsByID{fns: map[uuid.UUID]*cqrs.Function{fnID: {ID: fnID}}},
+		ConcurrencyLimitUpdater: updates,
+	})
+
+	do := func(key string, fnID string, body any) (int, ConcurrencyLimitsResponse) {
+		byt, err := json.Marshal(body)
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPut, "/functions/"+fnID+"/concurrency", bytes.NewReader(byt))
+		if key != "" {
+			req.Header.Set("Authorization", "Bearer "+key)
+		}
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		resp := Response[ConcurrencyLimitsResponse]{}
+		if rec.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		}
+		return rec.Code, resp.Data
+	}
+
+	body := ConcurrencyLimitsRequest{Limits: map[string]int{"abc": 5}}
+
+	// Updating limits requires an admin key.
+	code, _ := do("", fnID.String(), body)
+	require.Equal(t, http.StatusUnauthorized, code)
+	code, _ = do("reader", fnID.String(), body)
+	require.Equal(t, http.StatusForbidden, code)
+	require.Empty(t, updates)
+
+	code, resp := do("admin", fnID.String(), body)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, 3, resp.Updated)
+	require.Equal(t, map[string]int{"abc": 5}, updates[fnID])
+
+	// Unknown functions and invalid limits are rejected.
+	code, _ = do("admin", uuid.NewString(), body)
+	require.Equal(t, http.StatusNotFound, code)
+	code, _ = do("admin", "nope", body)
+	require.Equal(t, http.StatusBadRequest, code)
+	code, _ = do("admin", fnID.String(), ConcurrencyLimitsRequest{})
+	require.Equal(t, http.StatusBadRequest, code)
+	code, _ = do("admin", fnID.String(), ConcurrencyLimitsRequest{Limits: map[string]int{"abc": 0}})
+	require.Equal(t, http.StatusBadRequest, code)
+	require.Len(t, updates, 1)
+}
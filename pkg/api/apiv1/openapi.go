@@ -0,0 +1,494 @@
+package apiv1
+
+import (
+	"encoding"
+	"encoding/json"
+	"net/http"
+	"path"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/inngest/inngest/pkg/cqrs"
+	"github.com/inngest/inngest/pkg/execution/queue"
+	"github.com/inngest/inngest/pkg/publicerr"
+	"github.com/oklog/ulid/v2"
+)
+
+// OpenAPIServerURL is the path that the API is served from.
+const OpenAPIServerURL = "/v1"
+
+// operation documents a route for the OpenAPI spec.  Paths and methods are
+// read from the router, so that the spec only contains routes which are served.
+type operation struct {
+	ID      string
+	Summary string
+	Query   []queryParam
+	// Body is the request body, if any.
+	Body any
+	// Response is the data returned within the Response envelope, unless Raw is
+	// set.
+	Response any
+	// Raw is set for routes which don't wrap the response within Response.
+	Raw bool
+	// Status is the status code of successful responses, defaulting to 200.
+	Status int
+}
+
+type queryParam struct {
+	Name        string
+	Description string
+	Schema      map[string]any
+}
+
+var (
+	limitParam  = queryParam{Name: "limit", Description: "The maximum number of items to return", Schema: map[string]any{"type": "integer"}}
+	cursorParam = queryParam{Name: "cursor", Description: "The ID of the last item in the previous page", Schema: map[string]any{"type": "string", "format": "ulid"}}
+	orderParam  = queryParam{Name: "order", Description: "Sort oldest first with asc, or newest first with desc", Schema: map[string]any{"type": "string", "enum": []string{string(cqrs.SortOrderAsc), string(cqrs.SortOrderDesc)}}}
+)
+
+// ok is the response for routes that return {"ok": true}.
+type ok struct {
+	OK bool `json:"ok"`
+}
+
+// operations documents each route, keyed by method and pattern.
+var operations = map[string]operation{
+	"GET /openapi.json": {
+		ID:       "getOpenAPI",
+		Summary:  "Returns this OpenAPI document.",
+		Raw:      true,
+		Response: map[string]any{},
+	},
+	"GET /events": {
+		ID:      "listEvents",
+		Summary: "Lists events received by the environment.",
+		Query: []queryParam{
+			limitParam,
+			cursorParam,
+			orderParam,
+			{Name: "name", Description: "Only return events with this name", Schema: map[string]any{"type": "string"}},
+			{Name: "received_after", Description: "Only return events received after this time", Schema: map[string]any{"type": "string", "format": "date-time"}},
+			{Name: "received_before", Description: "Only return events received before this time", Schema: map[string]any{"type": "string", "format": "date-time"}},
+		},
+		Response: []cqrs.Event{},
+	},
+	"GET /events/{eventID}": {
+		ID:       "getEvent",
+		Summary:  "Returns a single event.",
+		Response: cqrs.Event{},
+	},
+	"GET /events/{eventID}/runs": {
+		ID:       "listEventRuns",
+		Summary:  "Lists the runs triggered by an event.",
+		Response: []cqrs.FunctionRun{},
+	},
+	"GET /runs": {
+		ID:      "searchRuns",
+		Summary: "Searches runs, optionally filtering by an expression evaluated against each run's triggering event.  The cursor for the next page is returned in the response metadata.",
+		Query: []queryParam{
+			limitParam,
+			{Name: "cursor", Description: "The cursor returned in the previous page's metadata", Schema: map[string]any{"type": "string"}},
+			orderParam,
+			{Name: "if", Description: "An expression evaluated against each run's triggering event, eg. event.data.userId == \"123\"", Schema: map[string]any{"type": "string"}},
+			{Name: "status", Description: "Only return runs with these statuses", Schema: map[string]any{"type": "array", "items": map[string]any{"type": "string"}}},
+			{Name: "function_id", Description: "Only return runs for this function", Schema: map[string]any{"type": "string", "format": "uuid"}},
+			{Name: "started_after", Description: "Only return runs started after this time", Schema: map[string]any{"type": "string", "format": "date-time"}},
+			{Name: "started_before", Description: "Only return runs started before this time", Schema: map[string]any{"type": "string", "format": "date-time"}},
+		},
+		Response: []cqrs.FunctionRun{},
+	},
+	"GET /runs/{runID}": {
+		ID:       "getRun",
+		Summary:  "Returns a single run.",
+		Response: cqrs.FunctionRun{},
+	},
+	"DELETE /runs/{runID}": {
+		ID:      "cancelRun",
+		Summary: "Cancels a run.",
+		Raw:     true,
+	},
+	"GET /runs/{runID}/jobs": {
+		ID:       "listRunJobs",
+		Summary:  "Lists the run's queued jobs.",
+		Response: []queue.JobResponse{},
+	},
+	"GET /runs/{runID}/scheduled": {
+		ID:       "listRunScheduledJobs",
+		Summary:  "Lists the run's jobs which are scheduled to run in the future, such as sleeps and wait timeouts.",
+		Query:    []queryParam{limitParam},
+		Response: []queue.ScheduledJob{},
+	},
+	"GET /runs/{runID}/timeline": {
+		ID:       "getRunTimeline",
+		Summary:  "Returns the run's trigger, steps and result as a single timeline.",
+		Response: cqrs.RunTimeline{},
+	},
+	"GET /runs/{runID}/steps/{stepID}/output": {
+		ID:      "getStepOutput",
+		Summary: "Returns a step's stored output, or its error if the step failed.",
+		Query: []queryParam{
+			{Name: "max_bytes", Description: "The maximum number of bytes of the output to return, truncating larger outputs", Schema: map[string]any{"type": "integer"}},
+		},
+		Response: StepOutput{},
+	},
+	"GET /runs/{runID}/batch": {
+		ID:       "getRunBatch",
+		Summary:  "Returns the batch of events which triggered the run, eg. for SDKs which load batches lazily.",
+		Response: cqrs.RunBatch{},
+	},
+	"GET /apps/{appName}/functions": {
+		ID:       "listAppFunctions",
+		Summary:  "Lists an app's functions.",
+		Raw:      true,
+		Response: []cqrs.Function{},
+	},
+	"GET /functions/{functionID}/queue": {
+		ID:       "getFunctionQueue",
+		Summary:  "Returns the function's queue backlog.",
+		Response: queue.PartitionStats{},
+	},
+	"GET /functions/{functionID}/queue/peek": {
+		ID:       "peekFunctionQueue",
+		Summary:  "Returns the next jobs within the function's queue without leasing them.",
+		Query:    []queryParam{limitParam},
+		Response: []queue.ScheduledJob{},
+	},
+	"GET /functions/{functionID}/scheduled": {
+		ID:       "listFunctionScheduledJobs",
+		Summary:  "Lists the function's jobs which are scheduled to run in the future.",
+		Query:    []queryParam{limitParam},
+		Response: []queue.ScheduledJob{},
+	},
+	"POST /functions/{functionID}/runs": {
+		ID:       "scheduleRun",
+		Summary:  "Schedules a single run of the function in the future.",
+		Body:     ScheduleRunRequest{},
+		Response: ScheduledRun{},
+		Status:   http.StatusCreated,
+	},
+	"GET /functions/{functionID}/runs/scheduled": {
+		ID:       "listScheduledRuns",
+		Summary:  "Lists the function's runs which are scheduled to start in the future.",
+		Query:    []queryParam{limitParam},
+		Response: []ScheduledRun{},
+	},
+	"DELETE /functions/{functionID}/runs/scheduled/{runID}": {
+		ID:       "cancelScheduledRun",
+		Summary:  "Cancels a run which is scheduled to start in the future.",
+		Response: ok{},
+	},
+	"POST /functions/{functionID}/archive": {
+		ID:       "archiveFunction",
+		Summary:  "Archives the function, so that it no longer starts new runs.",
+		Response: cqrs.Function{},
+	},
+	"DELETE /functions/{functionID}/archive": {
+		ID:       "unarchiveFunction",
+		Summary:  "Unarchives the function.",
+		Response: cqrs.Function{},
+	},
+	"GET /cancellations": {
+		ID:       "listCancellations",
+		Summary:  "Lists bulk cancellations.",
+		Response: []cqrs.Cancellation{},
+	},
+	"POST /cancellations": {
+		ID:       "createCancellation",
+		Summary:  "Cancels each of a function's runs started within a time range.",
+		Body:     CreateCancellationBody{},
+		Response: cqrs.Cancellation{},
+	},
+	"DELETE /cancellations/{id}": {
+		ID:       "deleteCancellation",
+		Summary:  "Deletes a bulk cancellation.",
+		Response: ok{},
+	},
+	"PUT /functions/{functionID}/concurrency": {
+		ID:       "updateConcurrencyLimits",
+		Summary:  "Updates the custom concurrency limits of the function's queued jobs, keyed by the hash of each concurrency key.",
+		Body:     ConcurrencyLimitsRequest{},
+		Response: ConcurrencyLimitsResponse{},
+	},
+	"GET /event-keys": {
+		ID:       "listEventKeys",
+		Summary:  "Lists event keys, including revoked keys.",
+		Response: []cqrs.EventKey{},
+	},
+	"POST /event-keys": {
+		ID:       "createEventKey",
+		Summary:  "Creates an event key.  The key's value is only returned on creation.  The first key is granted the admin scope, and may only be created locally.",
+		Body:     cqrs.CreateEventKeyOpts{},
+		Response: cqrs.EventKey{},
+	},
+	"POST /event-keys/{id}/rotate": {
+		ID:       "rotateEventKey",
+		Summary:  "Replaces the event key's value.",
+		Response: cqrs.EventKey{},
+	},
+	"DELETE /event-keys/{id}": {
+		ID:       "revokeEventKey",
+		Summary:  "Revokes the event key.",
+		Response: cqrs.EventKey{},
+	},
+}
+
+// pathParams documents each path parameter, keyed by name.
+var pathParams = map[string]map[string]any{
+	"runID":      {"type": "string", "format": "ulid"},
+	"eventID":    {"type": "string", "format": "ulid"},
+	"id":         {"type": "string", "format": "ulid"},
+	"functionID": {"type": "string", "format": "uuid"},
+	"appName":    {"type": "string"},
+	"stepID":     {"type": "string"},
+}
+
+var pathParamRegex = regexp.MustCompile(`{([^}]+)}`)
+
+// getOpenAPI serves the OpenAPI document for the API.
+func (a *router) getOpenAPI(w http.ResponseWriter, r *http.Request) {
+	spec, err := OpenAPI(a.Router)
+	if err != nil {
+		_ = publicerr.WriteHTTP(w, publicerr.Wrap(err, 500, "Unable to generate OpenAPI document"))
+		return
+	}
+	_ = json.NewEncoder(w).Encode(spec)
+}
+
+// OpenAPI returns an OpenAPI 3 document describing each route served by the
+// router.  Schemas are generated from each route's request and response types.
+func OpenAPI(routes chi.Routes) (map[string]any, error) {
+	gen := &schemaGenerator{components: map[string]any{}}
+	errSchema := gen.schema(reflect.TypeOf(publicerr.Error{}))
+	metadata := gen.schema(reflect.TypeOf(ResponseMetadata{}))
+
+	paths := map[string]map[string]any{}
+	err := chi.Walk(routes, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		route = path.Clean(route)
+		op := operations[method+" "+route]
+
+		params := []map[string]any{}
+		for _, match := range pathParamRegex.FindAllStringSubmatch(route, -1) {
+			schema, ok := pathParams[match[1]]
+			if !ok {
+				schema = map[string]any{"type": "string"}
+			}
+			params = append(params, map[string]any{
+				"name":     match[1],
+				"in":       "path",
+				"required": true,
+				"schema":   schema,
+			})
+		}
+		for _, q := range op.Query {
+			params = append(params, map[string]any{
+				"name":        q.Name,
+				"in":          "query",
+				"description": q.Description,
+				"schema":      q.Schema,
+			})
+		}
+
+		status := op.Status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		success := map[string]any{"description": http.StatusText(status)}
+		if op.Response != nil {
+			schema := gen.schema(reflect.TypeOf(op.Response))
+			if !op.Raw {
+				schema = map[string]any{
+					"type":     "object",
+					"required": []string{"data", "metadata"},
+					"properties": map[string]any{
+						"data":     schema,
+						"metadata": metadata,
+					},
+				}
+			}
+			success["content"] = map[string]any{"application/json": map[string]any{"schema": schema}}
+		}
+
+		item := map[string]any{
+			"parameters": params,
+			"responses": map[string]any{
+				strconv.Itoa(status): success,
+				"default": map[string]any{
+					"description": "An error",
+					"content":     map[string]any{"application/json": map[string]any{"schema": errSchema}},
+				},
+			},
+		}
+		if op.ID != "" {
+			item["operationId"] = op.ID
+		}
+		if op.Summary != "" {
+			item["summary"] = op.Summary
+		}
+		if op.Body != nil {
+			item["requestBody"] = map[string]any{
+				"required": true,
+				"content": map[string]any{"application/json": map[string]any{
+					"schema": gen.schema(reflect.TypeOf(op.Body)),
+				}},
+			}
+		}
+
+		if paths[route] == nil {
+			paths[route] = map[string]any{}
+		}
+		paths[route][strings.ToLower(method)] = item
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]any{
+		"openapi": "3.0.3",
+		"info": map[string]any{
+			"title":   "Inngest REST API",
+			"version": "v1",
+		},
+		"servers": []map[string]any{{"url": OpenAPIServerURL}},
+		"paths":   paths,
+		// Requests are authenticated with event keys when keys are required.
+		"security": []map[string]any{{}, {"eventKey": []string{}}},
+		"components": map[string]any{
+			"schemas": gen.components,
+			"securitySchemes": map[string]any{
+				"eventKey": map[string]any{"type": "http", "scheme": "bearer"},
+			},
+		},
+	}, nil
+}
+
+var (
+	jsonMarshaler = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshaler = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// schemaGenerator generates OpenAPI schemas from Go types, adding named structs
+// to components.
+type schemaGenerator struct {
+	components map[string]any
+}
+
+func (g *schemaGenerator) schema(t reflect.Type) map[string]any {
+	switch t {
+	case reflect.TypeOf(time.Time{}):
+		return map[string]any{"type": "string", "format": "date-time"}
+	case reflect.TypeOf(ulid.ULID{}):
+		return map[string]any{"type": "string", "format": "ulid"}
+	case reflect.TypeOf(uuid.UUID{}):
+		return map[string]any{"type": "string", "format": "uuid"}
+	case reflect.TypeOf(time.Duration(0)):
+		return map[string]any{"type": "integer", "description": "A duration in nanoseconds"}
+	case reflect.TypeOf(json.RawMessage{}):
+		return map[string]any{}
+	}
+
+	if t.Kind() == reflect.Pointer {
+		schema := map[string]any{"nullable": true}
+		elem := g.schema(t.Elem())
+		if ref, ok := elem["$ref"]; ok {
+			schema["allOf"] = []any{map[string]any{"$ref": ref}}
+			return schema
+		}
+		for k, v := range elem {
+			schema[k] = v
+		}
+		return schema
+	}
+
+	// Types with custom marshalling, eg. enums, are marshalled as strings.
+	if t.Kind() != reflect.Struct && (t.Implements(jsonMarshaler) || t.Implements(textMarshaler)) {
+		return map[string]any{"type": "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]any{"type": "string", "format": "byte"}
+		}
+		return map[string]any{"type": "array", "items": g.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": g.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Implements(jsonMarshaler) {
+			return map[string]any{}
+		}
+		if t.Name() == "" {
+			return g.object(t)
+		}
+		name := path.Base(t.PkgPath()) + "." + t.Name()
+		ref := map[string]any{"$ref": "#/components/schemas/" + name}
+		if _, ok := g.components[name]; !ok {
+			// Add the name before generating fields, so that recursive types
+			// reference themselves.
+			g.components[name] = map[string]any{}
+			g.components[name] = g.object(t)
+		}
+		return ref
+	}
+	return map[string]any{}
+}
+
+// object returns the schema for a struct's JSON fields.
+func (g *schemaGenerator) object(t reflect.Type) map[string]any {
+	props := map[string]any{}
+	required := []string{}
+	g.fields(t, props, &required)
+	sort.Strings(required)
+
+	schema := map[string]any{"type": "object", "properties": props}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+func (g *schemaGenerator) fields(t reflect.Type, props map[string]any, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				g.fields(ft, props, required)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		props[name] = g.schema(f.Type)
+		if !strings.Contains(opts, "omitempty") && f.Type.Kind() != reflect.Pointer {
+			*required = append(*required, name)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package apiv1
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/inngest/inngest/pkg/cqrs/sqlitecqrs"
+	"github.com/inngest/inngest/pkg/history_drivers/memory_reader"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAPI(t *testing.T) {
+	db, err := sqlitecqrs.New()
+	require.NoError(t, err)
+	mgr := sqlitecqrs.NewCQRS(db)
+
+	r := chi.NewRouter()
+	AddRoutes(r, Opts{
+		EventKeyManager:        mgr,
+		FunctionArchiver:       mgr,
+		CancellationReadWriter: mgr,
+		FunctionRunSearcher:    mgr,
+		RunTimelineReader:      memory_reader.NewReader(),
+		StepOutputReader:       stepOutputs{},
+	})
+
+	t.Run("Each route is documented", func(t *testing.T) {
+		err := chi.Walk(r, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+			_, ok := operations[method+" "+route]
+			require.True(t, ok, "%s %s is not documented", method, route)
+			return nil
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("The spec is served", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		spec := struct {
+			OpenAPI    string                               `json:"openapi"`
+			Paths      map[string]map[string]map[string]any `json:"paths"`
+			Components struct {
+				Schemas map[string]any `json:"schemas"`
+			} `json:"components"`
+		}{}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &spec))
+		require.Equal(t, "3.0.3", spec.OpenAPI)
+
+		require.Equal(t, "searchRuns", spec.Paths["/runs"]["get"]["operationId"])
+		require.Equal(t, "cancelRun", spec.Paths["/runs/{runID}"]["delete"]["operationId"])
+		require.Contains(t, spec.Paths["/functions/{functionID}/runs"]["post"]["responses"], "201")
+		require.Contains(t, spec.Paths["/cancellations"], "post")
+		require.Contains(t, spec.Components.Schemas, "cqrs.FunctionRun")
+		require.Contains(t, spec.Components.Schemas, "cqrs.RunTimeline")
+		require.Contains(t, spec.Components.Schemas, "publicerr.Error")
+	})
+}
@@ -0,0 +1,42 @@
+package apiv1
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/inngest/inngest/pkg/cqrs"
+	"github.com/inngest/inngest/pkg/publicerr"
+	"github.com/oklog/ulid/v2"
+)
+
+// GetRunBatch returns the batch of events which triggered a run.  SDKs call this
+// when requests only contain the first event of the run's batch.
+func (a router) GetRunBatch(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	auth, err := a.opts.AuthFinder(ctx)
+	if err != nil {
+		_ = publicerr.WriteHTTP(w, publicerr.Wrap(err, 401, "No auth found"))
+		return
+	}
+
+	runID, err := ulid.Parse(chi.URLParam(r, "runID"))
+	if err != nil {
+		_ = publicerr.WriteHTTP(w, publicerr.Wrapf(err, 400, "Invalid run ID: %s", chi.URLParam(r, "runID")))
+		return
+	}
+
+	batch, err := a.opts.RunBatchReader.GetRunBatch(ctx, auth.AccountID(), auth.WorkspaceID(), runID)
+	if errors.Is(err, cqrs.ErrRunBatchNotFound) {
+		_ = publicerr.WriteHTTP(w, publicerr.Wrapf(err, 404, "No batch found for run: %s", runID))
+		return
+	}
+	if err != nil {
+		_ = publicerr.WriteHTTP(w, publicerr.Wrapf(err, 500, "Unable to load batch for run: %s", runID))
+		return
+	}
+
+	// A run's batch never changes, so responses can be cached.
+	_ = WriteCachedResponse(w, batch, 60*time.Second)
+}
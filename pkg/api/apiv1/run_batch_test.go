@@ -0,0 +1,66 @@
+package apiv1
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/inngest/inngest/pkg/cqrs"
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/require"
+)
+
+// runBatches stores each run's batch, keyed by run ID.
+type runBatches map[ulid.ULID]cqrs.RunBatch
+
+func (b runBatches) GetRunBatch(ctx context.Context, accountID, workspaceID uuid.UUID, runID ulid.ULID) (*cqrs.RunBatch, error) {
+	batch, ok := b[runID]
+	if !ok {
+		return nil, cqrs.ErrRunBatchNotFound
+	}
+	return &batch, nil
+}
+
+func TestGetRunBatch(t *testing.T) {
+	runID := ulid.Make()
+	evtIDs := []ulid.ULID{ulid.Make(), ulid.Make()}
+	r := chi.NewRouter()
+	AddRoutes(r, Opts{
+		RunBatchReader: runBatches{
+			runID: {
+				RunID:    runID,
+				EventIDs: evtIDs,
+				Events: []map[string]any{
+					{"name": "test/event", "data": map[string]any{"n": 1.0}},
+					{"name": "test/event", "data": map[string]any{"n": 2.0}},
+				},
+			},
+		},
+	})
+
+	get := func(path string) (int, cqrs.RunBatch) {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		resp := Response[cqrs.RunBatch]{}
+		if rec.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		}
+		return rec.Code, resp.Data
+	}
+
+	code, batch := get("/runs/" + runID.String() + "/batch")
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, runID, batch.RunID)
+	require.Equal(t, evtIDs, batch.EventIDs)
+	require.Len(t, batch.Events, 2)
+	require.Equal(t, map[string]any{"n": 2.0}, batch.Events[1]["data"])
+
+	code, _ = get("/runs/" + ulid.Make().String() + "/batch")
+	require.Equal(t, http.StatusNotFound, code)
+	code, _ = get("/runs/nope/batch")
+	require.Equal(t, http.StatusBadRequest, code)
+}
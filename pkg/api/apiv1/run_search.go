@@ -0,0 +1,133 @@
+package apiv1
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/inngest/inngest/pkg/cqrs"
+	"github.com/inngest/inngest/pkg/dateutil"
+	"github.com/inngest/inngest/pkg/enums"
+	"github.com/inngest/inngest/pkg/expressions"
+	"github.com/inngest/inngest/pkg/publicerr"
+	"github.com/inngest/inngest/pkg/util"
+)
+
+const (
+	DefaultRunSearchResults = 20
+)
+
+// SearchFunctionRuns pages through runs sorted by the time they started,
+// optionally filtered by status or an expression matching their triggering event.
+func (a API) SearchFunctionRuns(ctx context.Context, opts cqrs.SearchFunctionRunsOpts) (*cqrs.FunctionRunSearchResult, error) {
+	auth, err := a.opts.AuthFinder(ctx)
+	if err != nil {
+		return nil, publicerr.Wrap(err, 401, "No auth found")
+	}
+	if opts.If != "" {
+		if err := expressions.Validate(ctx, opts.If); err != nil {
+			return nil, publicerr.Wrap(err, 400, "Invalid search expression")
+		}
+	}
+
+	res, err := a.opts.FunctionRunSearcher.SearchFunctionRuns(ctx, auth.AccountID(), auth.WorkspaceID(), opts)
+	if err != nil {
+		return nil, publicerr.Wrap(err, 500, "Unable to search function runs")
+	}
+	return res, nil
+}
+
+// searchFunctionRuns is the HTTP handler for paging through runs, eg.
+// GET /runs?if=event.data.userId == "123"&status=Failed&cursor=01H...
+//
+// The cursor for the next page is returned in the response's metadata.
+func (a router) searchFunctionRuns(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	opts := cqrs.SearchFunctionRunsOpts{
+		If: r.FormValue("if"),
+	}
+
+	order, err := sortOrder(r)
+	if err != nil {
+		_ = publicerr.WriteHTTP(w, err)
+		return
+	}
+	opts.Order = order
+
+	if cursor := r.FormValue("cursor"); cursor != "" {
+		parsed, err := cqrs.ParseFunctionRunCursor(cursor)
+		if err != nil {
+			_ = publicerr.WriteHTTP(w, publicerr.Wrap(err, 400, "Invalid cursor query parameter"))
+			return
+		}
+		opts.Cursor = parsed
+	}
+
+	for _, str := range r.Form["status"] {
+		status, err := enums.RunStatusString(str)
+		if err != nil {
+			_ = publicerr.WriteHTTP(w, publicerr.Wrapf(err, 400, "Invalid status query parameter: %s", str))
+			return
+		}
+		opts.Statuses = append(opts.Statuses, status)
+	}
+
+	limit, _ := strconv.Atoi(r.FormValue("limit"))
+	if limit == 0 {
+		limit = DefaultRunSearchResults
+	}
+	opts.Limit = util.Bound(limit, 1, cqrs.MaxFunctionRunSearchResults)
+
+	if fnID := r.FormValue("function_id"); fnID != "" {
+		parsed, err := uuid.Parse(fnID)
+		if err != nil {
+			_ = publicerr.WriteHTTP(w, publicerr.Wrap(err, 400, "Invalid function_id query parameter"))
+			return
+		}
+		opts.FunctionID = &parsed
+	}
+
+	if before := r.FormValue("started_before"); before != "" {
+		parsed, err := dateutil.Parse(before)
+		if err != nil {
+			_ = publicerr.WriteHTTP(w, publicerr.Wrap(err, 400, "Invalid started_before query parameter"))
+			return
+		}
+		opts.Timebound.Before = &parsed
+	}
+
+	if after := r.FormValue("started_after"); after != "" {
+		parsed, err := dateutil.Parse(after)
+		if err != nil {
+			_ = publicerr.WriteHTTP(w, publicerr.Wrap(err, 400, "Invalid started_after query parameter"))
+			return
+		}
+		opts.Timebound.After = &parsed
+	}
+
+	res, err := a.API.SearchFunctionRuns(ctx, opts)
+	if err != nil {
+		_ = publicerr.WriteHTTP(w, err)
+		return
+	}
+	var cursor string
+	if res.Cursor != nil {
+		cursor = res.Cursor.String()
+	}
+	_ = WriteCursorResponse(w, res.Runs, cursor)
+}
+
+// sortOrder parses the optional `order` query parameter, which is either "asc"
+// or "desc".
+func sortOrder(r *http.Request) (cqrs.SortOrder, error) {
+	switch order := cqrs.SortOrder(r.FormValue("order")); order {
+	case "":
+		return cqrs.SortOrderDesc, nil
+	case cqrs.SortOrderAsc, cqrs.SortOrderDesc:
+		return order, nil
+	default:
+		return "", publicerr.Errorf(400, "Invalid order: %s", order)
+	}
+}
@@ -37,6 +37,32 @@ func (a router) GetFunctionRun(w http.ResponseWriter, r *http.Request) {
 	_ = WriteCachedResponse(w, fr, 3*time.Second)
 }
 
+// GetFunctionRunTimeline returns the run's trigger, steps and result as a single
+// timeline.
+func (a router) GetFunctionRunTimeline(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	auth, err := a.opts.AuthFinder(ctx)
+	if err != nil {
+		_ = publicerr.WriteHTTP(w, publicerr.Wrap(err, 401, "No auth found"))
+		return
+	}
+
+	runID, err := ulid.Parse(chi.URLParam(r, "runID"))
+	if err != nil {
+		_ = publicerr.WriteHTTP(w, publicerr.Wrapf(err, 400, "Invalid run ID: %s", chi.URLParam(r, "runID")))
+		return
+	}
+
+	tl, err := a.opts.RunTimelineReader.GetRunTimeline(ctx, auth.AccountID(), auth.WorkspaceID(), runID)
+	if err != nil {
+		_ = publicerr.WriteHTTP(w, publicerr.Wrapf(err, 404, "Unable to load run timeline: %s", chi.URLParam(r, "runID")))
+		return
+	}
+
+	// Update the cache every 3 seconds to prevent stale displays
+	_ = WriteCachedResponse(w, tl, 3*time.Second)
+}
+
 // CancelFunctionRun cancels a function run.
 func (a API) CancelFunctionRun(ctx context.Context, runID ulid.ULID) error {
 	auth, err := a.opts.AuthFinder(ctx)
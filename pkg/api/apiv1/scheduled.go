@@ -0,0 +1,111 @@
+package apiv1
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/inngest/inngest/pkg/execution/queue"
+	"github.com/inngest/inngest/pkg/publicerr"
+	"github.com/oklog/ulid/v2"
+)
+
+// GetFunctionScheduledJobs returns the jobs scheduled to run in the future for
+// the given function, such as sleeps, waitForEvent timeouts, and delayed runs.
+func (a API) GetFunctionScheduledJobs(ctx context.Context, fnID uuid.UUID, limit int64) ([]queue.ScheduledJob, error) {
+	auth, err := a.opts.AuthFinder(ctx)
+	if err != nil {
+		return nil, publicerr.Wrap(err, 401, "No auth found")
+	}
+
+	fn, err := a.opts.FunctionReader.GetFunctionByInternalUUID(ctx, auth.WorkspaceID(), fnID)
+	if err != nil || fn == nil {
+		return nil, publicerr.Wrap(err, 404, "Function not found")
+	}
+
+	jobs, err := a.opts.JobQueueReader.ScheduledJobs(ctx, auth.WorkspaceID(), fn.ID, nil, limit)
+	if err != nil {
+		return nil, publicerr.Wrap(err, 500, "Unable to read scheduled jobs")
+	}
+	return jobs, nil
+}
+
+// GetRunScheduledJobs returns the jobs scheduled to run in the future for the
+// given run, showing why a run is waiting.
+func (a API) GetRunScheduledJobs(ctx context.Context, runID ulid.ULID, limit int64) ([]queue.ScheduledJob, error) {
+	auth, err := a.opts.AuthFinder(ctx)
+	if err != nil {
+		return nil, publicerr.Wrap(err, 401, "No auth found")
+	}
+
+	fr, err := a.opts.FunctionRunReader.GetFunctionRun(ctx, auth.AccountID(), auth.WorkspaceID(), runID)
+	if err != nil || fr == nil {
+		return nil, publicerr.Wrapf(err, 404, "Function run not found: %s", runID)
+	}
+
+	jobs, err := a.opts.JobQueueReader.ScheduledJobs(ctx, auth.WorkspaceID(), fr.FunctionID, &runID, limit)
+	if err != nil {
+		return nil, publicerr.Wrap(err, 500, "Unable to read scheduled jobs")
+	}
+	return jobs, nil
+}
+
+// GetFunctionScheduledJobs is the route wrapper for the GetFunctionScheduledJobs
+// API handler.
+func (a router) GetFunctionScheduledJobs(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	fnID, err := uuid.Parse(chi.URLParam(r, "functionID"))
+	if err != nil {
+		_ = publicerr.WriteHTTP(w, publicerr.Wrapf(err, 400, "Invalid function ID: %s", chi.URLParam(r, "functionID")))
+		return
+	}
+	limit, err := scheduledLimit(r)
+	if err != nil {
+		_ = publicerr.WriteHTTP(w, err)
+		return
+	}
+	jobs, err := a.API.GetFunctionScheduledJobs(ctx, fnID, limit)
+	if err != nil {
+		_ = publicerr.WriteHTTP(w, err)
+		return
+	}
+	_ = WriteCachedResponse(w, jobs, 5*time.Second)
+}
+
+// GetRunScheduledJobs is the route wrapper for the GetRunScheduledJobs API handler.
+func (a router) GetRunScheduledJobs(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	runID, err := ulid.Parse(chi.URLParam(r, "runID"))
+	if err != nil {
+		_ = publicerr.WriteHTTP(w, publicerr.Wrapf(err, 400, "Invalid run ID: %s", chi.URLParam(r, "runID")))
+		return
+	}
+	limit, err := scheduledLimit(r)
+	if err != nil {
+		_ = publicerr.WriteHTTP(w, err)
+		return
+	}
+	jobs, err := a.API.GetRunScheduledJobs(ctx, runID, limit)
+	if err != nil {
+		_ = publicerr.WriteHTTP(w, err)
+		return
+	}
+	_ = WriteCachedResponse(w, jobs, 5*time.Second)
+}
+
+// scheduledLimit returns the ?limit query parameter, or zero to use the default
+// limit.
+func scheduledLimit(r *http.Request) (int64, error) {
+	str := r.URL.Query().Get("limit")
+	if str == "" {
+		return 0, nil
+	}
+	limit, err := strconv.ParseInt(str, 10, 64)
+	if err != nil || limit < 0 {
+		return 0, publicerr.Errorf(400, "Invalid limit: %s", str)
+	}
+	return limit, nil
+}
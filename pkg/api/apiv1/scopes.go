@@ -0,0 +1,81 @@
+package apiv1
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/inngest/inngest/pkg/cqrs"
+	"github.com/inngest/inngest/pkg/devserver/environment"
+	"github.com/inngest/inngest/pkg/headers"
+	"github.com/inngest/inngest/pkg/publicerr"
+)
+
+// requireScope returns middleware which rejects requests that aren't authenticated
+// with an event key granted the given scope.  Requests authenticated with a key
+// created for an environment act within that environment.  As with the GraphQL
+// API, scopes are only checked if RequireEventKeys is set, and only once any
+// event key exists.
+func (a router) requireScope(scope cqrs.EventKeyScope) func(http.Handler) http.Handler {
+	if !a.opts.RequireEventKeys {
+		return func(next http.Handler) http.Handler { return next }
+	}
+	return a.enforceScope(scope)
+}
+
+// enforceScope is requireScope regardless of RequireEventKeys, so that routes
+// which manage access, such as event key routes, are always authenticated once
+// any event key exists.
+func (a router) enforceScope(scope cqrs.EventKeyScope) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if a.opts.EventKeyManager == nil {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			k, err := cqrs.AuthorizeEventKey(r.Context(), a.opts.EventKeyManager, headers.BearerToken(r), scope)
+			switch {
+			case err == nil:
+				if k != nil && k.Env != "" {
+					r = r.WithContext(environment.WithName(r.Context(), k.Env))
+				}
+				next.ServeHTTP(w, r)
+			case errors.Is(err, cqrs.ErrEventKeyNotFound):
+				_ = publicerr.WriteHTTP(w, publicerr.Wrap(err, 401, "A valid event key is required"))
+			case errors.Is(err, cqrs.ErrEventKeyScope):
+				_ = publicerr.WriteHTTP(w, publicerr.Wrap(err, 403, fmt.Sprintf("Event key does not have the %s scope", scope)))
+			default:
+				_ = publicerr.WriteHTTP(w, publicerr.Wrap(err, 500, "Unable to authenticate event key"))
+			}
+		})
+	}
+}
+
+// requireLocalBootstrap rejects requests to manage event keys from other machines
+// until the first key exists.  There's no key to authenticate requests with until
+// then, and the first key is granted the admin scope, so a remote caller creating
+// the first key would lock everyone else out.
+func (a router) requireLocalBootstrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		exists, err := a.opts.EventKeyManager.HasEventKeys(r.Context())
+		if err != nil {
+			_ = publicerr.WriteHTTP(w, publicerr.Wrap(err, 500, "Unable to authenticate event key"))
+			return
+		}
+		if !exists && !isLoopback(r.RemoteAddr) {
+			_ = publicerr.WriteHTTP(w, publicerr.Errorf(403, "The first event key must be created from the machine running Inngest"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// isLoopback returns whether the given remote address is a loopback address.
+func isLoopback(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
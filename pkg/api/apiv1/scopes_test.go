@@ -0,0 +1,86 @@
+package apiv1
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/inngest/inngest/pkg/cqrs"
+	"github.com/inngest/inngest/pkg/cqrs/sqlitecqrs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequireScope(t *testing.T) {
+	ctx := context.Background()
+	db, err := sqlitecqrs.New()
+	require.NoError(t, err)
+	mgr := sqlitecqrs.NewCQRS(db)
+
+	required := chi.NewRouter()
+	AddRoutes(required, Opts{
+		EventKeyManager:  mgr,
+		RequireEventKeys: true,
+	})
+	optional := chi.NewRouter()
+	AddRoutes(optional, Opts{
+		EventKeyManager: mgr,
+	})
+
+	do := func(r chi.Router, method, key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/event-keys", strings.NewReader(body))
+		req.RemoteAddr = "127.0.0.1:40000"
+		if key != "" {
+			req.Header.Set("Authorization", "Bearer "+key)
+		}
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// Keys aren't required until a key exists.
+	require.Equal(t, http.StatusOK, do(required, http.MethodGet, "", "").Code)
+	require.Equal(t, http.StatusOK, do(optional, http.MethodGet, "", "").Code)
+
+	// The first key may only be created locally.
+	remote := httptest.NewRequest(http.MethodPost, "/event-keys", strings.NewReader(`{"name":"remote"}`))
+	remote.RemoteAddr = "203.0.113.1:40000"
+	rec := httptest.NewRecorder()
+	optional.ServeHTTP(rec, remote)
+	require.Equal(t, http.StatusForbidden, rec.Code)
+
+	// The first key is granted the admin scope, so that it can manage keys.
+	rec = do(optional, http.MethodPost, "", `{"name":"first","scopes":["events:write"]}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	resp := Response[cqrs.EventKey]{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	admin := resp.Data
+	require.True(t, admin.HasScope(cqrs.ScopeAdmin))
+
+	reader, err := mgr.CreateEventKey(ctx, cqrs.CreateEventKeyOpts{
+		Name:   "reader",
+		Scopes: []cqrs.EventKeyScope{cqrs.ScopeRunsRead},
+	})
+	require.NoError(t, err)
+	require.False(t, reader.HasScope(cqrs.ScopeAdmin))
+
+	// Managing keys requires an admin key, even if keys aren't required.
+	for _, r := range []chi.Router{required, optional} {
+		require.Equal(t, http.StatusUnauthorized, do(r, http.MethodGet, "", "").Code)
+		require.Equal(t, http.StatusUnauthorized, do(r, http.MethodGet, "invalid", "").Code)
+		require.Equal(t, http.StatusForbidden, do(r, http.MethodGet, reader.Key, "").Code)
+		require.Equal(t, http.StatusForbidden, do(r, http.MethodPost, reader.Key, `{"name":"escalated","scopes":["admin"]}`).Code)
+		require.Equal(t, http.StatusOK, do(r, http.MethodGet, admin.Key, "").Code)
+	}
+
+	// Admin keys manage keys remotely once the first key exists.
+	remote = httptest.NewRequest(http.MethodGet, "/event-keys", nil)
+	remote.RemoteAddr = "203.0.113.1:40000"
+	remote.Header.Set("Authorization", "Bearer "+admin.Key)
+	rec = httptest.NewRecorder()
+	optional.ServeHTTP(rec, remote)
+	require.Equal(t, http.StatusOK, rec.Code)
+}
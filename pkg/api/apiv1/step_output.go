@@ -0,0 +1,127 @@
+package apiv1
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/inngest/inngest/pkg/cqrs"
+	"github.com/inngest/inngest/pkg/publicerr"
+	"github.com/oklog/ulid/v2"
+)
+
+const (
+	// DefaultStepOutputBytes is the number of bytes of a step's output returned
+	// when max_bytes isn't specified.
+	DefaultStepOutputBytes = 64 * 1024
+	// MaxStepOutputBytes is the maximum number of bytes of a step's output that
+	// can be requested.
+	MaxStepOutputBytes = 4 * 1024 * 1024
+
+	contentTypeJSON = "application/json"
+	contentTypeText = "text/plain"
+)
+
+// StepOutput is a step's stored output, or its error if the step failed.
+type StepOutput struct {
+	RunID  ulid.ULID `json:"run_id"`
+	StepID string    `json:"step_id"`
+	// Source is the storage that the output was loaded from, eg. "state".
+	Source string `json:"source"`
+	// Output is the step's output, if the step succeeded.
+	Output *string `json:"output,omitempty"`
+	// Error is the step's error, if the step failed.
+	Error *string `json:"error,omitempty"`
+	// ContentType is application/json if the untruncated output or error is
+	// valid JSON, otherwise text/plain.
+	ContentType string `json:"content_type"`
+	// Size is the size of the untruncated output or error, in bytes.
+	Size int `json:"size"`
+	// Truncated is true if the output or error is larger than max_bytes.
+	Truncated bool `json:"truncated"`
+}
+
+// NewStepOutput formats the stored output, truncating the output or error to
+// maxBytes.
+func NewStepOutput(o cqrs.StepOutput, maxBytes int) StepOutput {
+	out := StepOutput{
+		RunID:  o.RunID,
+		StepID: o.StepID,
+		Source: o.Source,
+	}
+	data, field := o.Output, &out.Output
+	if o.Error != nil {
+		data, field = o.Error, &out.Error
+	}
+
+	out.Size = len(data)
+	out.ContentType = contentTypeText
+	if json.Valid(data) {
+		out.ContentType = contentTypeJSON
+	}
+	if len(data) > maxBytes {
+		data = truncateUTF8(data, maxBytes)
+		out.Truncated = true
+	}
+	str := string(data)
+	*field = &str
+	return out
+}
+
+// truncateUTF8 truncates b to at most n bytes without splitting a rune.
+func truncateUTF8(b []byte, n int) []byte {
+	if len(b) <= n {
+		return b
+	}
+	for n > 0 && !utf8.RuneStart(b[n]) {
+		n--
+	}
+	return b[:n]
+}
+
+// GetStepOutput returns a step's stored output or error, so that clients don't
+// need to parse the output from the run's history or traces.
+func (a router) GetStepOutput(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	auth, err := a.opts.AuthFinder(ctx)
+	if err != nil {
+		_ = publicerr.WriteHTTP(w, publicerr.Wrap(err, 401, "No auth found"))
+		return
+	}
+
+	runID, err := ulid.Parse(chi.URLParam(r, "runID"))
+	if err != nil {
+		_ = publicerr.WriteHTTP(w, publicerr.Wrapf(err, 400, "Invalid run ID: %s", chi.URLParam(r, "runID")))
+		return
+	}
+
+	maxBytes := DefaultStepOutputBytes
+	if str := r.URL.Query().Get("max_bytes"); str != "" {
+		maxBytes, err = strconv.Atoi(str)
+		if err != nil || maxBytes <= 0 {
+			_ = publicerr.WriteHTTP(w, publicerr.Errorf(400, "Invalid max_bytes: %s", str))
+			return
+		}
+		if maxBytes > MaxStepOutputBytes {
+			maxBytes = MaxStepOutputBytes
+		}
+	}
+
+	stepID := chi.URLParam(r, "stepID")
+	o, err := a.opts.StepOutputReader.GetStepOutput(ctx, auth.AccountID(), auth.WorkspaceID(), runID, stepID)
+	if errors.Is(err, cqrs.ErrStepOutputNotFound) {
+		_ = publicerr.WriteHTTP(w, publicerr.Wrapf(err, 404, "No output found for step: %s", stepID))
+		return
+	}
+	if err != nil {
+		_ = publicerr.WriteHTTP(w, publicerr.Wrapf(err, 500, "Unable to load step output: %s", stepID))
+		return
+	}
+
+	// Update the cache every 3 seconds to prevent stale displays
+	_ = WriteCachedResponse(w, NewStepOutput(*o, maxBytes), 3*time.Second)
+}
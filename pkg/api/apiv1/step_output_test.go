@@ -0,0 +1,81 @@
+package apiv1
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/inngest/inngest/pkg/cqrs"
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/require"
+)
+
+// stepOutputs stores each step's output, keyed by step ID.
+type stepOutputs map[string]cqrs.StepOutput
+
+func (s stepOutputs) GetStepOutput(ctx context.Context, accountID, workspaceID uuid.UUID, runID ulid.ULID, stepID string) (*cqrs.StepOutput, error) {
+	o, ok := s[stepID]
+	if !ok {
+		return nil, cqrs.ErrStepOutputNotFound
+	}
+	return &o, nil
+}
+
+func TestGetStepOutput(t *testing.T) {
+	runID := ulid.Make()
+	r := chi.NewRouter()
+	AddRoutes(r, Opts{
+		StepOutputReader: stepOutputs{
+			"json":  {RunID: runID, StepID: "json", Source: cqrs.StepOutputSourceState, Output: []byte(`{"data":"héllo"}`)},
+			"error": {RunID: runID, StepID: "error", Source: cqrs.StepOutputSourceHistory, Error: []byte("boom")},
+		},
+	})
+
+	get := func(path string) (int, StepOutput) {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		resp := Response[StepOutput]{}
+		if rec.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		}
+		return rec.Code, resp.Data
+	}
+	base := "/runs/" + runID.String() + "/steps/"
+
+	code, out := get(base + "json/output")
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, `{"data":"héllo"}`, *out.Output)
+	require.Nil(t, out.Error)
+	require.Equal(t, "application/json", out.ContentType)
+	require.Equal(t, "state", out.Source)
+	require.False(t, out.Truncated)
+
+	t.Run("Outputs are truncated without splitting runes", func(t *testing.T) {
+		// The é is the 11th and 12th bytes.
+		code, out := get(base + "json/output?max_bytes=11")
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, `{"data":"h`, *out.Output)
+		require.Equal(t, "application/json", out.ContentType)
+		require.Equal(t, 17, out.Size)
+		require.True(t, out.Truncated)
+	})
+
+	t.Run("Errors are returned for failed steps", func(t *testing.T) {
+		code, out := get(base + "error/output")
+		require.Equal(t, http.StatusOK, code)
+		require.Nil(t, out.Output)
+		require.Equal(t, "boom", *out.Error)
+		require.Equal(t, "text/plain", out.ContentType)
+	})
+
+	t.Run("Invalid requests", func(t *testing.T) {
+		code, _ := get(base + "missing/output")
+		require.Equal(t, http.StatusNotFound, code)
+		code, _ = get(base + "json/output?max_bytes=-1")
+		require.Equal(t, http.StatusBadRequest, code)
+	})
+}
@@ -0,0 +1,122 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/inngest/inngest/pkg/consts"
+	"github.com/inngest/inngest/pkg/telemetry"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// BulkEventResult is the per-line result streamed when receiving newline-delimited
+// events.
+type BulkEventResult struct {
+	// Line is the 1-indexed line of the request body.
+	Line   int    `json:"line"`
+	ID     string `json:"id,omitempty"`
+	Status int    `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// ReceiveBulkEvents handles newline-delimited JSON events, which allows producers
+// to send many events in a single request, eg. when backfilling.
+//
+// Each line is handled independently:  a result is streamed for each non-empty line
+// as it's handled, in order, with the event's ID or the reason the event was
+// rejected.  Lines past the configured max batch size are rejected.
+func (a API) ReceiveBulkEvents(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	ctx, ok := a.authenticateRequest(w, r)
+	if !ok {
+		return
+	}
+
+	ctx = telemetry.UserTracer().Propagator().Extract(ctx, propagation.HeaderCarrier(r.Header))
+
+	max := a.config.EventAPI.MaxBulkEvents
+	if max <= 0 {
+		max = consts.DefaultMaxBulkEvents
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+	enc := json.NewEncoder(w)
+	flusher, _ := w.(http.Flusher)
+
+	write := func(res BulkEventResult) {
+		if err := enc.Encode(res); err != nil {
+			a.log.Error().Err(err).Msg("error writing bulk event result")
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+
+	scanner := bufio.NewScanner(r.Body)
+	// Allow a little extra space over the max event size so that events which
+	// are slightly too large are rejected by size, rather than failing the scan.
+	scanner.Buffer(make([]byte, 0, 64*1024), consts.AbsoluteMaxEventSize+1024)
+
+	var line, count int
+	for scanner.Scan() {
+		line++
+		byt := bytes.TrimSpace(scanner.Bytes())
+		if len(byt) == 0 {
+			continue
+		}
+
+		count++
+		if count > max {
+			write(BulkEventResult{
+				Line:   line,
+				Status: http.StatusRequestEntityTooLarge,
+				Error:  fmt.Sprintf("The batch exceeds the maximum of %d events", max),
+			})
+			continue
+		}
+
+		if len(byt) > consts.AbsoluteMaxEventSize {
+			write(BulkEventResult{
+				Line:   line,
+				Status: http.StatusRequestEntityTooLarge,
+				Error:  fmt.Sprintf("The event exceeds the maximum size of %d bytes", consts.AbsoluteMaxEventSize),
+			})
+			continue
+		}
+
+		id, err := a.handleEvent(ctx, byt)
+		if err != nil {
+			write(BulkEventResult{
+				Line:   line,
+				Status: http.StatusBadRequest,
+				Error:  err.Error(),
+			})
+			continue
+		}
+		write(BulkEventResult{
+			Line:   line,
+			ID:     id,
+			Status: http.StatusOK,
+		})
+	}
+
+	if err := scanner.Err(); err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, bufio.ErrTooLong) {
+			status = http.StatusRequestEntityTooLarge
+		}
+		// The remainder of the body can't be read, so the error is reported
+		// against the next line.
+		write(BulkEventResult{
+			Line:   line + 1,
+			Status: status,
+			Error:  err.Error(),
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/inngest/inngest/pkg/config"
+	"github.com/inngest/inngest/pkg/event"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReceiveBulkEvents(t *testing.T) {
+	l := zerolog.Nop()
+	var names []string
+	conf := config.Config{}
+	conf.EventAPI.MaxBulkEvents = 3
+
+	api, err := NewAPI(Options{
+		Config: conf,
+		Logger: &l,
+		EventHandler: func(ctx context.Context, evt *event.Event) (string, error) {
+			names = append(names, evt.Name)
+			return "id-" + evt.Name, nil
+		},
+	})
+	require.NoError(t, err)
+
+	body := strings.Join([]string{
+		`{"name":"test/a","data":{}}`,
+		``,
+		`{"name":"inngest/reserved","data":{}}`,
+		`not json`,
+		`{"name":"test/b","data":{}}`,
+	}, "\n")
+
+	req := httptest.NewRequest(http.MethodPost, "/e/key/bulk", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	api.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	results := []BulkEventResult{}
+	scanner := bufio.NewScanner(rec.Body)
+	for scanner.Scan() {
+		res := BulkEventResult{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &res))
+		results = append(results, res)
+	}
+
+	require.Equal(t, []string{"test/a"}, names)
+	require.Len(t, results, 4)
+	require.Equal(t, BulkEventResult{Line: 1, ID: "id-test/a", Status: 200}, results[0])
+	require.Equal(t, 3, results[1].Line)
+	require.Equal(t, 400, results[1].Status)
+	require.Contains(t, results[1].Error, "reserved")
+	require.Equal(t, 4, results[2].Line)
+	require.Equal(t, 400, results[2].Status)
+	// The last event exceeds the batch size.
+	require.Equal(t, 5, results[3].Line)
+	require.Equal(t, 413, results[3].Status)
+}
@@ -0,0 +1,4 @@
+// Package eventspb contains the protobuf definitions for the gRPC event API.
+package eventspb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative events.proto
@@ -0,0 +1,154 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/inngest/inngest/pkg/api/eventspb"
+	"github.com/inngest/inngest/pkg/consts"
+	"github.com/inngest/inngest/pkg/cqrs"
+	"github.com/inngest/inngest/pkg/devserver/environment"
+	"github.com/inngest/inngest/pkg/event"
+	"github.com/inngest/inngest/pkg/headers"
+	"github.com/inngest/inngest/pkg/telemetry"
+	"go.opentelemetry.io/otel/propagation"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// maxGRPCMessageSize is the maximum size of a request.  As with the HTTP API, a
+// batch of events may be at most consts.AbsoluteMaxEventSize, plus the framing
+// of each event within the batch:  a field tag and a length of up to 5 bytes.
+const maxGRPCMessageSize = consts.AbsoluteMaxEventSize + consts.MaxEvents*6
+
+// newGRPCServer returns a gRPC server which serves the EventService using the
+// given API to handle events.
+func newGRPCServer(a *API) *grpc.Server {
+	srv := grpc.NewServer(
+		grpc.MaxRecvMsgSize(maxGRPCMessageSize),
+	)
+	eventspb.RegisterEventServiceServer(srv, &eventService{api: a})
+	return srv
+}
+
+// eventService implements the gRPC EventService, sharing event handling with the
+// HTTP event API.
+type eventService struct {
+	eventspb.UnimplementedEventServiceServer
+
+	api *API
+}
+
+func (s *eventService) SendEvent(ctx context.Context, req *eventspb.SendEventRequest) (*eventspb.SendEventResponse, error) {
+	ctx, err := s.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := s.send(ctx, req.GetEvent())
+	if err != nil {
+		code := codes.InvalidArgument
+		if eventErrorStatus(err) == http.StatusTooManyRequests {
+			code = codes.ResourceExhausted
+		}
+		return nil, status.Error(code, err.Error())
+	}
+	return &eventspb.SendEventResponse{Id: id}, nil
+}
+
+func (s *eventService) SendEvents(ctx context.Context, req *eventspb.SendEventsRequest) (*eventspb.SendEventsResponse, error) {
+	ctx, err := s.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(req.GetEvents()) > consts.MaxEvents {
+		return nil, status.Errorf(codes.InvalidArgument, "A maximum of %d events may be sent at once", consts.MaxEvents)
+	}
+
+	results := make([]*eventspb.EventResult, len(req.GetEvents()))
+	for n, evt := range req.GetEvents() {
+		id, err := s.send(ctx, evt)
+		if err != nil {
+			results[n] = &eventspb.EventResult{Error: err.Error()}
+			continue
+		}
+		results[n] = &eventspb.EventResult{Id: id}
+	}
+	return &eventspb.SendEventsResponse{Results: results}, nil
+}
+
+// authenticate checks the event key sent within the "authorization" metadata,
+// and extracts any trace context and environment sent by the caller.
+func (s *eventService) authenticate(ctx context.Context) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	env := ""
+	if v := md.Get(strings.ToLower(headers.HeaderKeyEnv)); len(v) > 0 {
+		env = v[0]
+	}
+	if err := environment.Validate(env); err != nil {
+		return ctx, status.Error(codes.InvalidArgument, err.Error())
+	}
+	ctx = environment.WithName(ctx, env)
+
+	key := ""
+	if auth := md.Get("authorization"); len(auth) > 0 {
+		key = strings.TrimSpace(strings.TrimPrefix(auth[0], "Bearer "))
+	}
+	if key == "" {
+		return ctx, status.Error(codes.Unauthenticated, "Event key is required")
+	}
+
+	ctx, err := s.api.authenticateKey(ctx, key)
+	if err != nil {
+		if errors.Is(err, cqrs.ErrEventKeyNotFound) {
+			return ctx, status.Error(codes.Unauthenticated, "Event key not found")
+		}
+		if errors.Is(err, cqrs.ErrEventKeyScope) {
+			return ctx, status.Error(codes.PermissionDenied, "Event key does not have the events:write scope")
+		}
+		s.api.log.Error().Err(err).Msg("error authenticating event key")
+		return ctx, status.Error(codes.Internal, "Unable to authenticate event key")
+	}
+
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		if limited, retryAfter := s.api.rateLimited(ctx, key, p.Addr.String()); limited {
+			_ = grpc.SetHeader(ctx, metadata.Pairs("retry-after", strconv.Itoa(retryAfterSeconds(retryAfter))))
+			return ctx, status.Error(codes.ResourceExhausted, "Rate limit exceeded")
+		}
+	}
+
+	carrier := propagation.MapCarrier{}
+	for k, v := range md {
+		if len(v) > 0 {
+			carrier[k] = v[0]
+		}
+	}
+	return telemetry.UserTracer().Propagator().Extract(ctx, carrier), nil
+}
+
+func (s *eventService) send(ctx context.Context, evt *eventspb.Event) (string, error) {
+	if evt == nil {
+		return "", fmt.Errorf("event is required")
+	}
+	if size := proto.Size(evt); size > consts.AbsoluteMaxEventSize {
+		return "", fmt.Errorf("event is %d bytes, exceeding the maximum size of %d bytes", size, consts.AbsoluteMaxEventSize)
+	}
+	return s.api.publishEvent(ctx, event.Event{
+		ID:        evt.GetId(),
+		Name:      evt.GetName(),
+		Data:      evt.GetData().AsMap(),
+		User:      evt.GetUser().AsMap(),
+		Timestamp: evt.GetTimestamp(),
+		Version:   evt.GetVersion(),
+	})
+}
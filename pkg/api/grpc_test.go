@@ -0,0 +1,93 @@
+package api
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/inngest/inngest/pkg/api/eventspb"
+	"github.com/inngest/inngest/pkg/config"
+	"github.com/inngest/inngest/pkg/event"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestGRPCEventService(t *testing.T) {
+	ctx := context.Background()
+	l := zerolog.Nop()
+
+	var received []event.Event
+	router, err := NewAPI(Options{
+		Config: config.Config{},
+		Logger: &l,
+		EventHandler: func(ctx context.Context, evt *event.Event) (string, error) {
+			received = append(received, *evt)
+			return "id-" + evt.Name, nil
+		},
+	})
+	require.NoError(t, err)
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	srv := newGRPCServer(router.(*API))
+	go func() { _ = srv.Serve(lis) }()
+	defer srv.Stop()
+
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	client := eventspb.NewEventServiceClient(conn)
+
+	t.Run("it requires an event key", func(t *testing.T) {
+		_, err := client.SendEvent(ctx, &eventspb.SendEventRequest{})
+		require.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer key")
+
+	t.Run("it sends a single event", func(t *testing.T) {
+		data, err := structpb.NewStruct(map[string]any{"id": "123"})
+		require.NoError(t, err)
+
+		resp, err := client.SendEvent(ctx, &eventspb.SendEventRequest{
+			Event: &eventspb.Event{Name: "test/a", Data: data, Timestamp: 1_700_000_000_000},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "id-test/a", resp.Id)
+		require.Equal(t, "test/a", received[0].Name)
+		require.Equal(t, map[string]any{"id": "123"}, received[0].Data)
+		require.EqualValues(t, 1_700_000_000_000, received[0].Timestamp)
+	})
+
+	t.Run("it rejects invalid events individually", func(t *testing.T) {
+		resp, err := client.SendEvents(ctx, &eventspb.SendEventsRequest{
+			Events: []*eventspb.Event{
+				{Name: "test/b"},
+				{Name: "inngest/reserved"},
+				{Name: "test/c"},
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, resp.Results, 3)
+		require.Equal(t, "id-test/b", resp.Results[0].Id)
+		require.Contains(t, resp.Results[1].Error, "reserved")
+		require.Equal(t, "id-test/c", resp.Results[2].Id)
+	})
+
+	t.Run("it rejects requests larger than the max event size", func(t *testing.T) {
+		data, err := structpb.NewStruct(map[string]any{"big": strings.Repeat("a", maxGRPCMessageSize)})
+		require.NoError(t, err)
+
+		_, err = client.SendEvent(ctx, &eventspb.SendEventRequest{
+			Event: &eventspb.Event{Name: "test/big", Data: data},
+		})
+		require.Equal(t, codes.ResourceExhausted, status.Code(err))
+	})
+}
@@ -0,0 +1,104 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/cespare/xxhash/v2"
+	"github.com/go-chi/chi/v5"
+	"github.com/inngest/inngest/pkg/config"
+	"github.com/inngest/inngest/pkg/inngest"
+	"github.com/inngest/inngest/pkg/telemetry"
+)
+
+const pkgName = "api.inngest"
+
+const (
+	rateLimitKey = "key"
+	rateLimitIP  = "ip"
+)
+
+// rateLimited checks the configured event API rate limits for the given event key
+// and remote address, returning true and the duration until the next permitted
+// request if either limit is exceeded.
+//
+// Rate limiting fails open:  if the rate limiter errors, requests are allowed.
+func (a API) rateLimited(ctx context.Context, key, remoteAddr string) (bool, time.Duration) {
+	if a.rateLimiter == nil {
+		return false, 0
+	}
+
+	limits := a.config.EventAPI.RateLimit
+	checks := []struct {
+		kind  string
+		id    string
+		limit *config.EventAPIRateLimit
+	}{
+		// Event keys are hashed so that they're never stored in the limiter.
+		{kind: rateLimitKey, id: strconv.FormatUint(xxhash.Sum64String(key), 36), limit: limits.Key},
+		{kind: rateLimitIP, id: remoteIP(remoteAddr), limit: limits.IP},
+	}
+
+	for _, c := range checks {
+		if c.limit == nil || c.limit.Limit == 0 || c.id == "" {
+			continue
+		}
+		limited, retryAfter, err := a.rateLimiter.RateLimit(
+			ctx,
+			fmt.Sprintf("eventapi:%s:%s", c.kind, c.id),
+			inngest.RateLimit{Limit: c.limit.Limit, Period: c.limit.Period},
+		)
+		if err != nil {
+			a.log.Warn().Err(err).Str("limit", c.kind).Msg("error checking event api rate limit")
+			continue
+		}
+		if limited {
+			telemetry.IncrEventAPIRateLimitedCounter(ctx, telemetry.CounterOpt{
+				PkgName: pkgName,
+				Tags:    map[string]any{"limit": c.kind},
+			})
+			return true, retryAfter
+		}
+	}
+	return false, 0
+}
+
+// rateLimit is middleware which rejects requests over the event API's rate limits
+// with a 429 and a Retry-After header.
+func (a API) rateLimit(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limited, retryAfter := a.rateLimited(r.Context(), chi.URLParam(r, "key"), r.RemoteAddr)
+		if !limited {
+			next.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Set("Retry-After", strconv.Itoa(retryAfterSeconds(retryAfter)))
+		a.writeResponse(w, apiResponse{
+			StatusCode: http.StatusTooManyRequests,
+			Error:      "Rate limit exceeded",
+		})
+	})
+}
+
+// retryAfterSeconds returns the given duration in whole seconds, rounding up, as
+// used within Retry-After headers.
+func retryAfterSeconds(d time.Duration) int {
+	if d <= 0 {
+		return 1
+	}
+	return int(math.Ceil(d.Seconds()))
+}
+
+// remoteIP returns the IP address of the given remote address.
+func remoteIP(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	return host
+}
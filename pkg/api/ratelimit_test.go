@@ -0,0 +1,64 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/inngest/inngest/pkg/config"
+	"github.com/inngest/inngest/pkg/event"
+	"github.com/inngest/inngest/pkg/inngest"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+// countingLimiter limits each key once it's been seen more than limit times.
+type countingLimiter struct {
+	seen map[string]uint
+}
+
+func (c *countingLimiter) RateLimit(ctx context.Context, key string, rl inngest.RateLimit) (bool, time.Duration, error) {
+	c.seen[key]++
+	return c.seen[key] > rl.Limit, 1500 * time.Millisecond, nil
+}
+
+func TestRateLimit(t *testing.T) {
+	l := zerolog.Nop()
+	conf := config.Config{}
+	conf.EventAPI.RateLimit.Key = &config.EventAPIRateLimit{Limit: 2, Period: "1m"}
+	conf.EventAPI.RateLimit.IP = &config.EventAPIRateLimit{Limit: 3, Period: "1m"}
+
+	limiter := &countingLimiter{seen: map[string]uint{}}
+	api, err := NewAPI(Options{
+		Config:      conf,
+		Logger:      &l,
+		RateLimiter: limiter,
+		EventHandler: func(ctx context.Context, evt *event.Event) (string, error) {
+			return "id", nil
+		},
+	})
+	require.NoError(t, err)
+
+	send := func(key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/e/"+key, strings.NewReader(`{"name":"test/a","data":{}}`))
+		req.RemoteAddr = "10.0.0.1:1234"
+		rec := httptest.NewRecorder()
+		api.ServeHTTP(rec, req)
+		return rec
+	}
+
+	require.Equal(t, http.StatusOK, send("a").Code)
+	require.Equal(t, http.StatusOK, send("a").Code)
+
+	// The key's limit is exceeded.
+	rec := send("a")
+	require.Equal(t, http.StatusTooManyRequests, rec.Code)
+	require.Equal(t, "2", rec.Header().Get("Retry-After"))
+
+	// Another key is allowed, until the IP's limit is exceeded.
+	require.Equal(t, http.StatusOK, send("b").Code)
+	require.Equal(t, http.StatusTooManyRequests, send("c").Code)
+}
@@ -10,7 +10,10 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/inngest/inngest/pkg/config"
 	"github.com/inngest/inngest/pkg/consts"
+	"github.com/inngest/inngest/pkg/cqrs"
+	"github.com/inngest/inngest/pkg/devserver/environment"
 	"github.com/inngest/inngest/pkg/event"
+	"github.com/inngest/inngest/pkg/execution/ratelimit"
 	"github.com/inngest/inngest/pkg/logger"
 	"github.com/inngest/inngest/pkg/pubsub"
 	"github.com/inngest/inngest/pkg/service"
@@ -31,17 +34,52 @@ type Mount struct {
 	Handler http.Handler
 }
 
-func NewService(c config.Config, mounts ...Mount) service.Service {
-	return &apiServer{
-		config: c,
-		mounts: mounts,
+func NewService(c config.Config, opts ...func(a *apiServer)) service.Service {
+	a := &apiServer{config: c}
+	for _, o := range opts {
+		o(a)
+	}
+	return a
+}
+
+// WithMounts mounts additional APIs to the service.
+func WithMounts(mounts ...Mount) func(a *apiServer) {
+	return func(a *apiServer) {
+		a.mounts = append(a.mounts, mounts...)
+	}
+}
+
+// WithEventKeys authenticates event keys sent to the event API using the given
+// reader.
+func WithEventKeys(r cqrs.EventKeyReader) func(a *apiServer) {
+	return func(a *apiServer) {
+		a.eventKeys = r
+	}
+}
+
+// WithRateLimiter enforces the event API's configured rate limits using the given
+// rate limiter.
+func WithRateLimiter(rl ratelimit.RateLimiter) func(a *apiServer) {
+	return func(a *apiServer) {
+		a.rateLimiter = rl
+	}
+}
+
+// WithEventChecker rejects events sent to the event API which fail the given
+// check, before they're published.
+func WithEventChecker(c EventChecker) func(a *apiServer) {
+	return func(a *apiServer) {
+		a.checkEvent = c
 	}
 }
 
 type apiServer struct {
-	config    config.Config
-	api       *API
-	publisher pubsub.Publisher
+	config      config.Config
+	api         *API
+	publisher   pubsub.Publisher
+	eventKeys   cqrs.EventKeyReader
+	rateLimiter ratelimit.RateLimiter
+	checkEvent  EventChecker
 
 	mounts []Mount
 }
@@ -57,6 +95,9 @@ func (a *apiServer) Pre(ctx context.Context) error {
 		Config:       a.config,
 		Logger:       logger.From(ctx),
 		EventHandler: a.handleEvent,
+		EventChecker: a.checkEvent,
+		EventKeys:    a.eventKeys,
+		RateLimiter:  a.rateLimiter,
 	})
 	if err != nil {
 		return err
@@ -96,7 +137,7 @@ func (a *apiServer) handleEvent(ctx context.Context, e *event.Event) (string, er
 
 	l.Debug().Str("event", e.Name).Msg("handling event")
 
-	trackedEvent := event.NewOSSTrackedEvent(*e)
+	trackedEvent := event.NewOSSTrackedEventForWorkspace(*e, environment.WorkspaceIDFromContext(ctx))
 
 	byt, err := json.Marshal(trackedEvent)
 	if err != nil {
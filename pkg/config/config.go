@@ -4,10 +4,23 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/inngest/inngest/pkg/config/registration"
+	"github.com/inngest/inngest/pkg/consts"
+	"github.com/inngest/inngest/pkg/execution/aigateway"
+	"github.com/inngest/inngest/pkg/execution/gateway"
+	"github.com/inngest/inngest/pkg/execution/redact"
+	"github.com/inngest/inngest/pkg/inngest"
+	"github.com/inngest/inngest/pkg/logger"
+	"github.com/rs/zerolog"
+	"github.com/xhit/go-str2duration/v2"
 )
 
+// DefaultDrainTimeout is the default time in-progress steps have to finish when
+// the executor shuts down.
+const DefaultDrainTimeout = 25 * time.Second
+
 const devConfig = `package main
 
 import (
@@ -20,9 +33,7 @@ config.#Config & {
 	}
 	execution: {
 		drivers: {
-			http: config.#HTTPDriver & {
-				signingKey: "dev-signing-key"
-			}
+			http: config.#HTTPDriver
 		}
 	}
 }
@@ -59,6 +70,16 @@ type Config struct {
 	Queue Queue
 	// State configures the execution state store.
 	State State
+	// Redis configures the keys stored within Redis.
+	Redis Redis
+}
+
+// Redis configures the keys stored within Redis.
+type Redis struct {
+	// Namespace prefixes every key stored within Redis, including state, queue,
+	// and rate limit keys, so that many installs or environments can safely
+	// share a Redis instance or cluster.  Defaults to no namespace.
+	Namespace string
 }
 
 // Log configures the logger used within Inngest services.
@@ -70,6 +91,32 @@ type Log struct {
 	// Format configures the log format.  Currently, only "json"
 	// is supported and is the default.
 	Format string
+	// Dedup suppresses repeated logs.
+	Dedup LogDedup
+}
+
+// LogDedup configures the suppression of repeated logs with the same level,
+// message and function.
+type LogDedup struct {
+	// Window is the number of seconds that repeated logs are counted within.
+	Window int
+	// Levels maps each level to the number of repeated logs written within
+	// each window.  Levels without a limit are never suppressed.
+	Levels map[string]int
+}
+
+// DedupOpts returns the logger's deduplication options.
+func (l LogDedup) DedupOpts() logger.DedupOpts {
+	opts := logger.DedupOpts{
+		Window: time.Duration(l.Window) * time.Second,
+		Limits: map[zerolog.Level]int{},
+	}
+	for level, limit := range l.Levels {
+		if lvl, err := zerolog.ParseLevel(level); err == nil {
+			opts.Limits[lvl] = limit
+		}
+	}
+	return opts
 }
 
 // EventAPI configures the event API service.
@@ -80,6 +127,32 @@ type EventAPI struct {
 	Port int
 	// MaxSize represents the max size of events ingested, in bytes.
 	MaxSize int
+	// MaxBulkEvents is the maximum number of newline-delimited events accepted
+	// in a single bulk request.
+	MaxBulkEvents int
+	// GRPCPort is the port used to serve the gRPC EventService.  The gRPC
+	// service is disabled if this is 0.
+	GRPCPort int
+	// RateLimit limits the number of requests sent to the event API.
+	RateLimit EventAPIRateLimits
+	// DropUnmatchedEvents drops events which don't trigger any function or
+	// match any pause, instead of storing them.
+	DropUnmatchedEvents bool
+}
+
+// EventAPIRateLimits configures rate limits for the event API.  Requests over
+// either limit are rejected with a 429.
+type EventAPIRateLimits struct {
+	// Key limits requests made with each event key.
+	Key *EventAPIRateLimit
+	// IP limits requests made from each IP address.
+	IP *EventAPIRateLimit
+}
+
+// EventAPIRateLimit limits requests to Limit requests every Period, eg. "1s".
+type EventAPIRateLimit struct {
+	Limit  uint
+	Period string
 }
 
 type CoreAPI struct {
@@ -87,6 +160,10 @@ type CoreAPI struct {
 	Addr string
 	// Port is the port to use, defaulting to 8288.
 	Port int
+	// RequireKeys requires REST and GraphQL API requests to be authenticated
+	// with an event key granted the request's scope, via an "Authorization:
+	// Bearer" header.  Keys are only required once at least one key exists.
+	RequireKeys bool
 }
 
 // EventAPI configures the event stream, which connects events to the execution engine.
@@ -106,12 +183,229 @@ type Execution struct {
 	// Drivers represents all drivers enabled.
 	Drivers   map[string]registration.DriverConfig
 	LogOutput bool `json:"logOutput"`
+	// Redact lists JSON paths scrubbed from event payloads and step outputs
+	// before they're saved to state or recorded in traces.
+	Redact redact.Config `json:"redact"`
+	// DrainTimeout is the number of seconds in-progress steps have to finish
+	// when the executor shuts down.  Steps which don't finish in time are
+	// released back to the queue.
+	DrainTimeout int `json:"drainTimeout"`
+	// IdempotencyPeriod is how long a run's idempotency key prevents duplicate
+	// runs once the run finishes, eg. "48h", for functions which don't specify
+	// their own period.
+	IdempotencyPeriod string `json:"idempotencyPeriod"`
+	// CancelTimeout is how long cancellations last, eg. "30d", or "never" for
+	// cancellations which never expire, for cancellations and functions which
+	// don't specify their own timeout.
+	CancelTimeout string `json:"cancelTimeout"`
+	// FinishEvents configures how events sent when runs finish are published.
+	FinishEvents FinishEvents `json:"finishEvents"`
+	// LifecycleEvents sends `inngest/run.started`, `inngest/run.cancelled` and
+	// `inngest/step.failed` events as runs change state, so that functions can
+	// be triggered by them.
+	LifecycleEvents bool `json:"lifecycleEvents"`
+	// TraceSampleRatio is the ratio of runs, between 0 and 1, whose traces are
+	// exported, for functions which don't specify their own ratio.  Spans
+	// which record errors are always exported.  If nil, every trace is exported.
+	TraceSampleRatio *float64 `json:"traceSampleRatio"`
+	// Aggregator configures the in-memory trees used to match events against
+	// pause expressions.
+	Aggregator Aggregator `json:"aggregator"`
+	// PauseExpressions bounds the evaluation of each pause's expression when
+	// events are matched against pauses.
+	PauseExpressions PauseExpressions `json:"pauseExpressions"`
+	// Batches limits the batches held for functions which batch events.
+	Batches Batches `json:"batches"`
+	// ConcurrencyKeyCardinality is the number of distinct keys that each of a
+	// function's custom concurrency limits may use within an hour before the
+	// function is logged and counted as generating unbounded keys, eg. as its
+	// keys use unique IDs.  Zero disables the limit.
+	ConcurrencyKeyCardinality int `json:"concurrencyKeyCardinality"`
+	// QueueCompression is the encoded size, in bytes, at which the payloads
+	// of queue items are compressed.  Zero disables compression.
+	QueueCompression int `json:"queueCompression"`
+	// AppConcurrency limits the steps running concurrently across every
+	// function within each app.
+	AppConcurrency AppConcurrency `json:"appConcurrency"`
+	// AI configures the model providers used for inference requests, which
+	// the executor makes on behalf of functions.
+	AI aigateway.Config `json:"ai"`
+	// Gateway configures the HTTP requests which the executor makes on behalf
+	// of functions, eg. the hosts that may be requested.
+	Gateway gateway.Config `json:"gateway"`
+}
+
+// DefaultAggregatorMaxTrees is the default number of aggregate trees held in
+// memory.
+const DefaultAggregatorMaxTrees = 100
+
+// Aggregator configures the expression aggregator's memory bounds.
+type Aggregator struct {
+	// MaxTrees is the number of workspace event trees held in memory.  The
+	// least recently used trees are evicted once full.
+	MaxTrees int `json:"maxTrees"`
+	// MaxTreeSize is the maximum number of expressions held in a single
+	// tree.  Larger trees are evicted, and their pauses are evaluated
+	// individually.  Zero disables the limit.
+	MaxTreeSize int `json:"maxTreeSize"`
+}
+
+// MaxTreeCount returns the number of trees held in memory, using the default
+// if unset.
+func (a Aggregator) MaxTreeCount() int64 {
+	if a.MaxTrees <= 0 {
+		return DefaultAggregatorMaxTrees
+	}
+	return int64(a.MaxTrees)
+}
+
+// PauseExpressions bounds the time and work spent evaluating each pause's
+// expression, so that pathological expressions can't stall event handling.
+// Pauses whose expressions exceed the budget are skipped.
+type PauseExpressions struct {
+	// Timeout is the maximum number of milliseconds spent evaluating a
+	// single expression.
+	Timeout int `json:"timeout"`
+	// CostLimit is the maximum runtime cost of evaluating a single
+	// expression, as calculated by CEL.
+	CostLimit int `json:"costLimit"`
+}
+
+// TimeoutDuration returns the evaluation timeout, using the default if unset.
+func (p PauseExpressions) TimeoutDuration() time.Duration {
+	if p.Timeout <= 0 {
+		return consts.DefaultPauseExpressionTimeout
+	}
+	return time.Duration(p.Timeout) * time.Millisecond
+}
+
+// MaxCost returns the evaluation cost limit, using the default if unset.
+func (p PauseExpressions) MaxCost() uint64 {
+	if p.CostLimit <= 0 {
+		return consts.DefaultPauseExpressionCostLimit
+	}
+	return uint64(p.CostLimit)
+}
+
+const (
+	// BatchLimitRun starts a run immediately for each event which can't be
+	// batched as its function holds its maximum number of pending batches.
+	BatchLimitRun = "run"
+	// BatchLimitReject rejects each event which can't be batched as its
+	// function holds its maximum number of pending batches.  The event API
+	// responds with a 429 for such events.
+	BatchLimitReject = "reject"
+)
+
+// Batches limits the batches held for functions which batch events, so that
+// functions can't exhaust redis with pending batches.
+type Batches struct {
+	// MaxPending is the maximum number of pending batches, which hold events
+	// but haven't yet started, that each function may hold at once.  Zero
+	// disables the limit.
+	MaxPending int `json:"maxPending"`
+	// OnLimit is either BatchLimitRun or BatchLimitReject, and determines how
+	// further events are handled once a function holds its maximum number of
+	// pending batches.  This defaults to BatchLimitRun.
+	OnLimit string `json:"onLimit"`
+}
+
+// AppConcurrency limits the steps running concurrently across every function
+// within each app, so that one app's functions can't exceed a bound together.
+type AppConcurrency struct {
+	// Limit is the number of steps that may run concurrently within each
+	// app.  Zero disables the limit.
+	Limit int `json:"limit"`
+	// Apps overrides the limit for individual apps, keyed by app name.
+	Apps map[string]int `json:"apps"`
+}
+
+// AppLimit returns the concurrency limit for the app with the given name, or
+// zero if the app's concurrency is unlimited.
+func (a AppConcurrency) AppLimit(name string) int {
+	if limit, ok := a.Apps[name]; ok {
+		return limit
+	}
+	return a.Limit
+}
+
+// RejectOnLimit returns whether events are rejected once a function holds its
+// maximum number of pending batches.
+func (b Batches) RejectOnLimit() bool {
+	return b.OnLimit == BatchLimitReject
+}
+
+// FinishEvents configures buffering of the events sent when runs finish, eg.
+// `inngest/function.finished`.  Buffering allows events from many runs to be
+// published together.
+type FinishEvents struct {
+	// FlushSize is the maximum number of events buffered before they're
+	// published.  Events aren't buffered if this is less than 2.
+	FlushSize int `json:"flushSize"`
+	// FlushInterval is the maximum number of milliseconds events are buffered
+	// before they're published.
+	FlushInterval int `json:"flushInterval"`
+}
+
+// FlushIntervalDuration returns the flush interval as a duration.
+func (f FinishEvents) FlushIntervalDuration() time.Duration {
+	return time.Duration(f.FlushInterval) * time.Millisecond
+}
+
+// DrainTimeoutDuration returns the drain timeout as a duration.
+func (e Execution) DrainTimeoutDuration() time.Duration {
+	if e.DrainTimeout <= 0 {
+		return DefaultDrainTimeout
+	}
+	return time.Duration(e.DrainTimeout) * time.Second
+}
+
+// IdempotencyPeriodDuration returns the idempotency period as a duration, or 0
+// if the period is unset or invalid.
+func (e Execution) IdempotencyPeriodDuration() time.Duration {
+	if e.IdempotencyPeriod == "" {
+		return 0
+	}
+	dur, err := str2duration.ParseDuration(e.IdempotencyPeriod)
+	if err != nil || dur <= 0 || dur > consts.MaxFunctionIdempotencyPeriod {
+		return 0
+	}
+	return dur
+}
+
+// CancelTimeoutDuration returns the cancellation timeout as a duration, or 0 if
+// the timeout is unset or invalid.  This returns consts.CancelTimeoutNever for
+// cancellations which never expire.
+func (e Execution) CancelTimeoutDuration() time.Duration {
+	if e.CancelTimeout == "" {
+		return 0
+	}
+	dur, err := inngest.ParseCancelTimeout(e.CancelTimeout)
+	if err != nil {
+		return 0
+	}
+	return dur
 }
 
 func (e *Execution) UnmarshalJSON(byt []byte) error {
 	type drivers struct {
-		Drivers   map[string]unmarshalDriver
-		LogOutput bool
+		Drivers                   map[string]unmarshalDriver
+		LogOutput                 bool
+		Redact                    redact.Config
+		DrainTimeout              int
+		IdempotencyPeriod         string
+		CancelTimeout             string
+		FinishEvents              FinishEvents
+		LifecycleEvents           bool
+		TraceSampleRatio          *float64
+		Aggregator                Aggregator
+		PauseExpressions          PauseExpressions
+		Batches                   Batches
+		ConcurrencyKeyCardinality int
+		QueueCompression          int
+		AppConcurrency            AppConcurrency
+		AI                        aigateway.Config
+		Gateway                   gateway.Config
 	}
 	names := &drivers{}
 	if err := json.Unmarshal(byt, names); err != nil {
@@ -120,6 +414,21 @@ func (e *Execution) UnmarshalJSON(byt []byte) error {
 
 	e.Drivers = map[string]registration.DriverConfig{}
 	e.LogOutput = names.LogOutput
+	e.Redact = names.Redact
+	e.DrainTimeout = names.DrainTimeout
+	e.IdempotencyPeriod = names.IdempotencyPeriod
+	e.CancelTimeout = names.CancelTimeout
+	e.FinishEvents = names.FinishEvents
+	e.LifecycleEvents = names.LifecycleEvents
+	e.TraceSampleRatio = names.TraceSampleRatio
+	e.Aggregator = names.Aggregator
+	e.PauseExpressions = names.PauseExpressions
+	e.Batches = names.Batches
+	e.ConcurrencyKeyCardinality = names.ConcurrencyKeyCardinality
+	e.QueueCompression = names.QueueCompression
+	e.AppConcurrency = names.AppConcurrency
+	e.AI = names.AI
+	e.Gateway = names.Gateway
 
 	for runtime, driver := range names.Drivers {
 		f, ok := registration.RegisteredDrivers()[driver.Name]
@@ -144,6 +144,7 @@ func decode(i *cue.Instance) (*Config, error) {
 	// Ensure we set log levels and formats here, globally.
 	// XXX: This could/should be refactored;  this is messy (but minimal impact rn).
 	logger.SetFormat(c.Log.Format)
+	logger.SetDedup(c.Log.Dedup.DedupOpts())
 
 	return c, nil
 }
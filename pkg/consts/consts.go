@@ -21,6 +21,9 @@ const (
 	// be initialized with.
 	AbsoluteMaxStepLimit = 10_000
 
+	// DefaultMaxParallelStepLimit is the maximum number of steps a run may plan at once.
+	DefaultMaxParallelStepLimit = 1_000
+
 	// MaxFunctionTimeout represents the longest running function or step allowed within
 	// our system.
 	MaxFunctionTimeout = 2 * time.Hour
@@ -46,6 +49,9 @@ const (
 	// lets users delay functions for up to MaxDebouncePeriod when events are received.
 	MaxDebouncePeriod = time.Hour * 24 * 7
 
+	// MaxCronJitter is the maximum jitter that can be used to configure a cron trigger.
+	MaxCronJitter = time.Hour
+
 	// MaxCancellations represents the max automatic cancellation signals per function
 	MaxCancellations = 5
 
@@ -64,12 +70,28 @@ const (
 	// FunctionIdempotencyPeriod determines how long a specific function remains idempotent
 	// when using idempotency keys.
 	FunctionIdempotencyPeriod = 24 * time.Hour
+	// MaxFunctionIdempotencyPeriod is the maximum idempotency period that can be
+	// configured for a function's runs.
+	MaxFunctionIdempotencyPeriod = 7 * 24 * time.Hour
 
 	DefaultBatchSize = 100
 	MaxBatchTimeout  = 60 * time.Second
+	// MaxBatchWindow is the maximum total time that a sliding batch may wait
+	// for events, as each appended event extends the batch's timeout.
+	MaxBatchWindow = 5 * time.Minute
+	// MaxBatchesInFlight is the maximum number of batches that a function may
+	// append events to concurrently.
+	MaxBatchesInFlight = 10
+
+	// ConcurrencyKeyCardinalityWindow is the window over which the distinct
+	// custom concurrency keys used by each function are counted.
+	ConcurrencyKeyCardinalityWindow = time.Hour
 
 	// MaxEvents is the maximum number of events we can parse in a single batch.
 	MaxEvents = 5_000
+	// DefaultMaxBulkEvents is the default maximum number of newline-delimited
+	// events accepted in a single bulk request.
+	DefaultMaxBulkEvents = 10_000
 
 	InngestEventDataPrefix = "_inngest"
 	// InvokeSlugKey is the data key used to store the fn name when invoking a function
@@ -77,9 +99,15 @@ const (
 	InvokeFnID          = "fn_id"
 	InvokeCorrelationId = "correlation_id"
 
-	// CancelTimeout is the maximum time a cancellation can exist
+	// CancelTimeout is the maximum time a cancellation can exist, used when neither
+	// the cancellation, its function, nor its workspace specify a timeout.
 	CancelTimeout = time.Hour * 24 * 365
 
+	// CancelTimeoutNever is the timeout of cancellations which never expire, eg.
+	// for runs which track long-lived entities.  These are removed once their run
+	// ends.
+	CancelTimeoutNever time.Duration = -1
+
 	// SourceEdgeRetries represents the number of times we'll retry running a source edge.
 	// Each edge gets their own set of retries in our execution engine, embedded directly
 	// in the job.  The retry count is taken from function config for every step _but_
@@ -88,6 +116,17 @@ const (
 
 	RequestVersionUnknown = -1
 
+	// DefaultStateReplicaLag is the age of runs whose state is read from the
+	// primary when state is read from replicas.
+	DefaultStateReplicaLag = 5 * time.Second
+
+	// DefaultPauseExpressionTimeout is the maximum time spent evaluating a single
+	// pause's expression when handling events.
+	DefaultPauseExpressionTimeout = 100 * time.Millisecond
+	// DefaultPauseExpressionCostLimit is the maximum runtime cost of evaluating a
+	// single pause's expression when handling events.
+	DefaultPauseExpressionCostLimit = 1_000_000
+
 	// PriorityFactorMin is the minimum priority factor for any function run, in seconds.
 	PriorityFactorMin = int64(-1 * 60 * 60 * 12)
 	// PriorityFactorMax is the maximum priority factor for any function run, in seconds.
@@ -57,11 +57,14 @@ const (
 	OtelSysStepInvokeRunID             = "sys.step.invoke.run.id"
 	OtelSysStepInvokeExpired           = "sys.step.invoke.expired"
 
+	OtelSysStepAIProvider = "sys.step.ai.provider"
+
 	OtelSysStepRetry         = "sys.step.retry"
 	OtelSysStepNextOpcode    = "sys.step.next.opcode"
 	OtelSysStepNextTimestamp = "sys.step.next.time"
 	OtelSysStepNextExpires   = "sys.step.next.expires"
 	OtelSysStepDelete        = "sys.step.delete"
+	OtelSysStepPanicStack    = "sys.step.panic.stack"
 
 	OtelSysCronTimestamp = "sys.cron.timestamp"
 	OtelSysCronExpr      = "sys.cron.expr"
@@ -16,6 +16,7 @@ import (
 	"github.com/inngest/inngest/pkg/coreapi/generated"
 	"github.com/inngest/inngest/pkg/coreapi/graph/resolvers"
 	"github.com/inngest/inngest/pkg/cqrs"
+	"github.com/inngest/inngest/pkg/devserver/environment"
 	"github.com/inngest/inngest/pkg/execution"
 	"github.com/inngest/inngest/pkg/execution/queue"
 	"github.com/inngest/inngest/pkg/execution/runner"
@@ -62,6 +63,8 @@ func NewCoreApi(o Options) (*CoreAPI, error) {
 	})
 	a.Use(cors.Handler)
 	a.Use(headers.StaticHeadersMiddleware(headers.ServerKindDev))
+	// Queries act within the environment selected by the environment header.
+	a.Use(environment.Middleware)
 
 	srv := handler.NewDefaultServer(generated.NewExecutableSchema(generated.Config{Resolvers: &resolvers.Resolver{
 		Data:          o.Data,
@@ -74,10 +77,12 @@ func NewCoreApi(o Options) (*CoreAPI, error) {
 
 	// TODO - Add option for enabling GraphQL Playground
 	a.Handle("/", playground.Handler("GraphQL playground", "/v0/gql"))
-	a.Handle("/gql", srv)
+
+	srv.AroundOperations(a.requireScopes)
+	a.With(withEventKey).Handle("/gql", srv)
+	a.With(a.requireScope(cqrs.ScopeRunsCancel)).Delete("/runs/{runID}", a.CancelRun)
 
 	// V0 APIs
-	a.Delete("/runs/{runID}", a.CancelRun)
 	// NOTE: These are present in the 2.x and 3.x SDKs to enable large payload sizes.
 	a.Get("/runs/{runID}/batch", a.GetEventBatch)
 	a.Get("/runs/{runID}/actions", a.GetActions)
@@ -18,6 +18,7 @@ import (
 	"github.com/inngest/inngest/pkg/coreapi/graph/models"
 	"github.com/inngest/inngest/pkg/cqrs"
 	"github.com/inngest/inngest/pkg/enums"
+	"github.com/inngest/inngest/pkg/execution/queue"
 	"github.com/inngest/inngest/pkg/function"
 	types "github.com/inngest/inngest/pkg/gql_scalars"
 	"github.com/inngest/inngest/pkg/history_reader"
@@ -94,6 +95,7 @@ type ComplexityRoot struct {
 		Config      func(childComplexity int) int
 		ID          func(childComplexity int) int
 		Name        func(childComplexity int) int
+		Queue       func(childComplexity int) int
 		Slug        func(childComplexity int) int
 		Triggers    func(childComplexity int) int
 		URL         func(childComplexity int) int
@@ -107,6 +109,16 @@ type ComplexityRoot struct {
 		Workspace   func(childComplexity int) int
 	}
 
+	FunctionQueue struct {
+		ConcurrencyLimit   func(childComplexity int) int
+		ConcurrencyLimited func(childComplexity int) int
+		InProgress         func(childComplexity int) int
+		OldestItemAt       func(childComplexity int) int
+		Paused             func(childComplexity int) int
+		Ready              func(childComplexity int) int
+		Scheduled          func(childComplexity int) int
+	}
+
 	FunctionRun struct {
 		BatchCreatedAt    func(childComplexity int) int
 		BatchID           func(childComplexity int) int
@@ -124,10 +136,17 @@ type ComplexityRoot struct {
 		PendingSteps      func(childComplexity int) int
 		StartedAt         func(childComplexity int) int
 		Status            func(childComplexity int) int
+		StepLogs          func(childComplexity int, stepID *string) int
 		WaitingFor        func(childComplexity int) int
+		WaitingReason     func(childComplexity int) int
 		Workspace         func(childComplexity int) int
 	}
 
+	FunctionRunSearchResult struct {
+		Cursor func(childComplexity int) int
+		Runs   func(childComplexity int) int
+	}
+
 	FunctionTrigger struct {
 		Type  func(childComplexity int) int
 		Value func(childComplexity int) int
@@ -144,22 +163,28 @@ type ComplexityRoot struct {
 	}
 
 	Mutation struct {
-		CancelRun       func(childComplexity int, runID ulid.ULID) int
-		CreateApp       func(childComplexity int, input models.CreateAppInput) int
-		DeleteApp       func(childComplexity int, id string) int
-		DeleteAppByName func(childComplexity int, name string) int
-		InvokeFunction  func(childComplexity int, data map[string]interface{}, functionSlug string) int
-		Rerun           func(childComplexity int, runID ulid.ULID) int
-		UpdateApp       func(childComplexity int, input models.UpdateAppInput) int
+		BoostRunPriority       func(childComplexity int, runID ulid.ULID, seconds int) int
+		CancelRun              func(childComplexity int, runID ulid.ULID) int
+		CreateApp              func(childComplexity int, input models.CreateAppInput) int
+		DeleteApp              func(childComplexity int, id string) int
+		DeleteAppByName        func(childComplexity int, name string) int
+		InvokeFunction         func(childComplexity int, data map[string]interface{}, functionSlug string) int
+		PauseFunctionQueue     func(childComplexity int, functionID string) int
+		RequeueStep            func(childComplexity int, runID ulid.ULID, stepID string) int
+		Rerun                  func(childComplexity int, runID ulid.ULID) int
+		ResumeFunctionQueue    func(childComplexity int, functionID string) int
+		UpdateApp              func(childComplexity int, input models.UpdateAppInput) int
+		UpdateFunctionThrottle func(childComplexity int, functionID string, throttle *models.FunctionThrottleInput) int
 	}
 
 	Query struct {
-		Apps        func(childComplexity int) int
-		Event       func(childComplexity int, query models.EventQuery) int
-		Events      func(childComplexity int, query models.EventsQuery) int
-		FunctionRun func(childComplexity int, query models.FunctionRunQuery) int
-		Functions   func(childComplexity int) int
-		Stream      func(childComplexity int, query models.StreamQuery) int
+		Apps               func(childComplexity int) int
+		Event              func(childComplexity int, query models.EventQuery) int
+		Events             func(childComplexity int, query models.EventsQuery) int
+		FunctionRun        func(childComplexity int, query models.FunctionRunQuery) int
+		Functions          func(childComplexity int) int
+		SearchFunctionRuns func(childComplexity int, query models.SearchFunctionRunsQuery) int
+		Stream             func(childComplexity int, query models.StreamQuery) int
 	}
 
 	RunHistoryCancel struct {
@@ -242,6 +267,16 @@ type ComplexityRoot struct {
 		Expression func(childComplexity int) int
 	}
 
+	StepLog struct {
+		Attempt   func(childComplexity int) int
+		Fields    func(childComplexity int) int
+		Level     func(childComplexity int) int
+		Message   func(childComplexity int) int
+		RunID     func(childComplexity int) int
+		StepID    func(childComplexity int) int
+		Timestamp func(childComplexity int) int
+	}
+
 	StreamItem struct {
 		CreatedAt func(childComplexity int) int
 		ID        func(childComplexity int) int
@@ -276,6 +311,7 @@ type EventResolver interface {
 }
 type FunctionResolver interface {
 	App(ctx context.Context, obj *models.Function) (*cqrs.App, error)
+	Queue(ctx context.Context, obj *models.Function) (*queue.PartitionStats, error)
 }
 type FunctionRunResolver interface {
 	Function(ctx context.Context, obj *models.FunctionRun) (*models.Function, error)
@@ -290,6 +326,7 @@ type FunctionRunResolver interface {
 
 	History(ctx context.Context, obj *models.FunctionRun) ([]*history_reader.RunHistory, error)
 	HistoryItemOutput(ctx context.Context, obj *models.FunctionRun, id ulid.ULID) (*string, error)
+	StepLogs(ctx context.Context, obj *models.FunctionRun, stepID *string) ([]*cqrs.StepLog, error)
 }
 type MutationResolver interface {
 	CreateApp(ctx context.Context, input models.CreateAppInput) (*cqrs.App, error)
@@ -299,6 +336,11 @@ type MutationResolver interface {
 	InvokeFunction(ctx context.Context, data map[string]interface{}, functionSlug string) (*bool, error)
 	CancelRun(ctx context.Context, runID ulid.ULID) (*models.FunctionRun, error)
 	Rerun(ctx context.Context, runID ulid.ULID) (ulid.ULID, error)
+	RequeueStep(ctx context.Context, runID ulid.ULID, stepID string) (bool, error)
+	BoostRunPriority(ctx context.Context, runID ulid.ULID, seconds int) (int, error)
+	UpdateFunctionThrottle(ctx context.Context, functionID string, throttle *models.FunctionThrottleInput) (bool, error)
+	PauseFunctionQueue(ctx context.Context, functionID string) (bool, error)
+	ResumeFunctionQueue(ctx context.Context, functionID string) (bool, error)
 }
 type QueryResolver interface {
 	Apps(ctx context.Context) ([]*cqrs.App, error)
@@ -307,6 +349,7 @@ type QueryResolver interface {
 	Events(ctx context.Context, query models.EventsQuery) ([]*models.Event, error)
 	Functions(ctx context.Context) ([]*models.Function, error)
 	FunctionRun(ctx context.Context, query models.FunctionRunQuery) (*models.FunctionRun, error)
+	SearchFunctionRuns(ctx context.Context, query models.SearchFunctionRunsQuery) (*models.FunctionRunSearchResult, error)
 }
 type StreamItemResolver interface {
 	InBatch(ctx context.Context, obj *models.StreamItem) (bool, error)
@@ -537,6 +580,13 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.Function.Name(childComplexity), true
 
+	case "Function.queue":
+		if e.complexity.Function.Queue == nil {
+			break
+		}
+
+		return e.complexity.Function.Queue(childComplexity), true
+
 	case "Function.slug":
 		if e.complexity.Function.Slug == nil {
 			break
@@ -593,6 +643,55 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.FunctionEvent.Workspace(childComplexity), true
 
+	case "FunctionQueue.concurrencyLimit":
+		if e.complexity.FunctionQueue.ConcurrencyLimit == nil {
+			break
+		}
+
+		return e.complexity.FunctionQueue.ConcurrencyLimit(childComplexity), true
+
+	case "FunctionQueue.concurrencyLimited":
+		if e.complexity.FunctionQueue.ConcurrencyLimited == nil {
+			break
+		}
+
+		return e.complexity.FunctionQueue.ConcurrencyLimited(childComplexity), true
+
+	case "FunctionQueue.inProgress":
+		if e.complexity.FunctionQueue.InProgress == nil {
+			break
+		}
+
+		return e.complexity.FunctionQueue.InProgress(childComplexity), true
+
+	case "FunctionQueue.oldestItemAt":
+		if e.complexity.FunctionQueue.OldestItemAt == nil {
+			break
+		}
+
+		return e.complexity.FunctionQueue.OldestItemAt(childComplexity), true
+
+	case "FunctionQueue.paused":
+		if e.complexity.FunctionQueue.Paused == nil {
+			break
+		}
+
+		return e.complexity.FunctionQueue.Paused(childComplexity), true
+
+	case "FunctionQueue.ready":
+		if e.complexity.FunctionQueue.Ready == nil {
+			break
+		}
+
+		return e.complexity.FunctionQueue.Ready(childComplexity), true
+
+	case "FunctionQueue.scheduled":
+		if e.complexity.FunctionQueue.Scheduled == nil {
+			break
+		}
+
+		return e.complexity.FunctionQueue.Scheduled(childComplexity), true
+
 	case "FunctionRun.batchCreatedAt":
 		if e.complexity.FunctionRun.BatchCreatedAt == nil {
 			break
@@ -710,6 +809,18 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.FunctionRun.Status(childComplexity), true
 
+	case "FunctionRun.stepLogs":
+		if e.complexity.FunctionRun.StepLogs == nil {
+			break
+		}
+
+		args, err := ec.field_FunctionRun_stepLogs_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.FunctionRun.StepLogs(childComplexity, args["stepID"].(*string)), true
+
 	case "FunctionRun.waitingFor":
 		if e.complexity.FunctionRun.WaitingFor == nil {
 			break
@@ -717,6 +828,13 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.FunctionRun.WaitingFor(childComplexity), true
 
+	case "FunctionRun.waitingReason":
+		if e.complexity.FunctionRun.WaitingReason == nil {
+			break
+		}
+
+		return e.complexity.FunctionRun.WaitingReason(childComplexity), true
+
 	case "FunctionRun.workspace":
 		if e.complexity.FunctionRun.Workspace == nil {
 			break
@@ -724,6 +842,20 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.FunctionRun.Workspace(childComplexity), true
 
+	case "FunctionRunSearchResult.cursor":
+		if e.complexity.FunctionRunSearchResult.Cursor == nil {
+			break
+		}
+
+		return e.complexity.FunctionRunSearchResult.Cursor(childComplexity), true
+
+	case "FunctionRunSearchResult.runs":
+		if e.complexity.FunctionRunSearchResult.Runs == nil {
+			break
+		}
+
+		return e.complexity.FunctionRunSearchResult.Runs(childComplexity), true
+
 	case "FunctionTrigger.type":
 		if e.complexity.FunctionTrigger.Type == nil {
 			break
@@ -787,6 +919,18 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.FunctionVersion.Version(childComplexity), true
 
+	case "Mutation.boostRunPriority":
+		if e.complexity.Mutation.BoostRunPriority == nil {
+			break
+		}
+
+		args, err := ec.field_Mutation_boostRunPriority_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Mutation.BoostRunPriority(childComplexity, args["runID"].(ulid.ULID), args["seconds"].(int)), true
+
 	case "Mutation.cancelRun":
 		if e.complexity.Mutation.CancelRun == nil {
 			break
@@ -847,6 +991,30 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.Mutation.InvokeFunction(childComplexity, args["data"].(map[string]interface{}), args["functionSlug"].(string)), true
 
+	case "Mutation.pauseFunctionQueue":
+		if e.complexity.Mutation.PauseFunctionQueue == nil {
+			break
+		}
+
+		args, err := ec.field_Mutation_pauseFunctionQueue_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Mutation.PauseFunctionQueue(childComplexity, args["functionID"].(string)), true
+
+	case "Mutation.requeueStep":
+		if e.complexity.Mutation.RequeueStep == nil {
+			break
+		}
+
+		args, err := ec.field_Mutation_requeueStep_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Mutation.RequeueStep(childComplexity, args["runID"].(ulid.ULID), args["stepID"].(string)), true
+
 	case "Mutation.rerun":
 		if e.complexity.Mutation.Rerun == nil {
 			break
@@ -859,6 +1027,18 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.Mutation.Rerun(childComplexity, args["runID"].(ulid.ULID)), true
 
+	case "Mutation.resumeFunctionQueue":
+		if e.complexity.Mutation.ResumeFunctionQueue == nil {
+			break
+		}
+
+		args, err := ec.field_Mutation_resumeFunctionQueue_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Mutation.ResumeFunctionQueue(childComplexity, args["functionID"].(string)), true
+
 	case "Mutation.updateApp":
 		if e.complexity.Mutation.UpdateApp == nil {
 			break
@@ -871,6 +1051,18 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.Mutation.UpdateApp(childComplexity, args["input"].(models.UpdateAppInput)), true
 
+	case "Mutation.updateFunctionThrottle":
+		if e.complexity.Mutation.UpdateFunctionThrottle == nil {
+			break
+		}
+
+		args, err := ec.field_Mutation_updateFunctionThrottle_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Mutation.UpdateFunctionThrottle(childComplexity, args["functionID"].(string), args["throttle"].(*models.FunctionThrottleInput)), true
+
 	case "Query.apps":
 		if e.complexity.Query.Apps == nil {
 			break
@@ -921,6 +1113,18 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.Query.Functions(childComplexity), true
 
+	case "Query.searchFunctionRuns":
+		if e.complexity.Query.SearchFunctionRuns == nil {
+			break
+		}
+
+		args, err := ec.field_Query_searchFunctionRuns_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Query.SearchFunctionRuns(childComplexity, args["query"].(models.SearchFunctionRunsQuery)), true
+
 	case "Query.stream":
 		if e.complexity.Query.Stream == nil {
 			break
@@ -1283,6 +1487,55 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.StepEventWait.Expression(childComplexity), true
 
+	case "StepLog.attempt":
+		if e.complexity.StepLog.Attempt == nil {
+			break
+		}
+
+		return e.complexity.StepLog.Attempt(childComplexity), true
+
+	case "StepLog.fields":
+		if e.complexity.StepLog.Fields == nil {
+			break
+		}
+
+		return e.complexity.StepLog.Fields(childComplexity), true
+
+	case "StepLog.level":
+		if e.complexity.StepLog.Level == nil {
+			break
+		}
+
+		return e.complexity.StepLog.Level(childComplexity), true
+
+	case "StepLog.message":
+		if e.complexity.StepLog.Message == nil {
+			break
+		}
+
+		return e.complexity.StepLog.Message(childComplexity), true
+
+	case "StepLog.runID":
+		if e.complexity.StepLog.RunID == nil {
+			break
+		}
+
+		return e.complexity.StepLog.RunID(childComplexity), true
+
+	case "StepLog.stepID":
+		if e.complexity.StepLog.StepID == nil {
+			break
+		}
+
+		return e.complexity.StepLog.StepID(childComplexity), true
+
+	case "StepLog.timestamp":
+		if e.complexity.StepLog.Timestamp == nil {
+			break
+		}
+
+		return e.complexity.StepLog.Timestamp(childComplexity), true
+
 	case "StreamItem.createdAt":
 		if e.complexity.StreamItem.CreatedAt == nil {
 			break
@@ -1346,6 +1599,8 @@ func (e *executableSchema) Exec(ctx context.Context) graphql.ResponseHandler {
 		ec.unmarshalInputEventsQuery,
 		ec.unmarshalInputFunctionRunQuery,
 		ec.unmarshalInputFunctionRunsQuery,
+		ec.unmarshalInputFunctionThrottleInput,
+		ec.unmarshalInputSearchFunctionRunsQuery,
 		ec.unmarshalInputStreamQuery,
 		ec.unmarshalInputUpdateAppInput,
 	)
@@ -1423,6 +1678,40 @@ type Mutation {
 
   cancelRun(runID: ULID!): FunctionRun!
   rerun(runID: ULID!): ULID!
+  """
+  Reschedules a delayed or retrying step to run immediately, bypassing any
+  retry backoff or sleep.
+  """
+  requeueStep(runID: ULID!, stepID: String!): Boolean!
+  """
+  Moves a run's jobs which are ready to run ahead of the function's backlog,
+  scheduling them earlier by the given number of seconds.  Returns the number
+  of jobs boosted.
+  """
+  boostRunPriority(runID: ULID!, seconds: Int!): Int!
+  """
+  Overrides a function's throttle limit, burst and period for queued and future
+  runs, without redeploying the function.  Passing null removes the override.
+  """
+  updateFunctionThrottle(functionID: String!, throttle: FunctionThrottleInput): Boolean!
+  """
+  Stops a function's queued jobs from starting, without dropping them.  Jobs
+  can still be scheduled while paused, and run once the queue is resumed.
+  """
+  pauseFunctionQueue(functionID: String!): Boolean!
+  """
+  Resumes processing of a function's queued jobs.
+  """
+  resumeFunctionQueue(functionID: String!): Boolean!
+}
+
+input FunctionThrottleInput {
+  limit: Int!
+  burst: Int
+  """
+  The throttle period, eg. "1m" or "24h".
+  """
+  period: String!
 }
 
 input CreateAppInput {
@@ -1450,6 +1739,10 @@ input UpdateAppInput {
 
   # Get an individual function run
   functionRun(query: FunctionRunQuery!): FunctionRun
+
+  # Page through runs, optionally filtered by status or by an expression
+  # matching their triggering event
+  searchFunctionRuns(query: SearchFunctionRunsQuery!): FunctionRunSearchResult!
 }
 
 input ActionVersionQuery {
@@ -1477,6 +1770,22 @@ input FunctionRunsQuery {
   workspaceId: ID! = "local"
 }
 
+input SearchFunctionRunsQuery {
+  workspaceId: ID! = "local"
+  # An expression evaluated against each run's triggering event, eg.
+  # ` + "`" + `event.data.userId == "123"` + "`" + `.
+  expression: String
+  functionId: ID
+  status: [FunctionRunStatus!]
+  startedAfter: Time
+  startedBefore: Time
+  # The ID of the last run in the previous page.
+  cursor: ID
+  # Sorts runs by the time they started.
+  order: SortOrder! = DESC
+  limit: Int! = 20
+}
+
 input StreamQuery {
   # This allows you to query for items after a specific time.  If
   # not provided this defaults to the most recent items in the stream,
@@ -1607,6 +1916,17 @@ type Function {
   url: String!
   appID: String!
   app: App!
+  queue: FunctionQueue!
+}
+
+type FunctionQueue {
+  ready: Int!
+  scheduled: Int!
+  inProgress: Int!
+  concurrencyLimit: Int!
+  concurrencyLimited: Int!
+  oldestItemAt: Time
+  paused: Boolean!
 }
 
 enum FunctionTriggerTypes {
@@ -1614,6 +1934,14 @@ enum FunctionTriggerTypes {
   CRON
 }
 
+type FunctionRunSearchResult {
+  runs: [FunctionRun!]!
+  # Continues the search after this page, or null once every run has been
+  # searched.  Pages may contain fewer runs than the limit while a cursor is
+  # returned, as each search scans a bounded number of runs.
+  cursor: ID
+}
+
 type FunctionTrigger {
   type: FunctionTriggerTypes!
   value: String!
@@ -1627,6 +1955,20 @@ enum FunctionRunStatus {
   QUEUED
 }
 
+# FunctionRunWaitingReason describes why a running or queued run isn't
+# currently progressing.
+enum FunctionRunWaitingReason {
+  SLEEPING
+  WAITING_FOR_EVENT
+  THROTTLED
+  HALTED
+}
+
+enum SortOrder {
+  ASC
+  DESC
+}
+
 enum FunctionEventType {
   STARTED
   COMPLETED
@@ -1683,6 +2025,8 @@ type FunctionRun {
   batchCreatedAt: Time
 
   status: FunctionRunStatus
+  # waitingReason is set while the run is waiting, and is null otherwise.
+  waitingReason: FunctionRunWaitingReason
   waitingFor: StepEventWait
   pendingSteps: Int @deprecated
   startedAt: Time
@@ -1691,10 +2035,23 @@ type FunctionRun {
 
   history: [RunHistoryItem!]!
   historyItemOutput(id: ULID!): String
+  # stepLogs returns structured log lines emitted by the SDK during the run's
+  # steps, optionally filtered to a single step.
+  stepLogs(stepID: String): [StepLog!]!
   eventID: ID!
   cron: String
 }
 
+type StepLog {
+  runID: ULID!
+  stepID: String!
+  attempt: Int!
+  level: String!
+  message: String!
+  fields: Map
+  timestamp: Time!
+}
+
 enum HistoryType {
 	FunctionCancelled
 	FunctionCompleted
@@ -1804,6 +2161,45 @@ func (ec *executionContext) field_FunctionRun_historyItemOutput_args(ctx context
 	return args, nil
 }
 
+func (ec *executionContext) field_FunctionRun_stepLogs_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+	var err error
+	args := map[string]interface{}{}
+	var arg0 *string
+	if tmp, ok := rawArgs["stepID"]; ok {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("stepID"))
+		arg0, err = ec.unmarshalOString2ᚖstring(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["stepID"] = arg0
+	return args, nil
+}
+
+func (ec *executionContext) field_Mutation_boostRunPriority_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+	var err error
+	args := map[string]interface{}{}
+	var arg0 ulid.ULID
+	if tmp, ok := rawArgs["runID"]; ok {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("runID"))
+		arg0, err = ec.unmarshalNULID2githubᚗcomᚋoklogᚋulidᚋv2ᚐULID(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["runID"] = arg0
+	var arg1 int
+	if tmp, ok := rawArgs["seconds"]; ok {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("seconds"))
+		arg1, err = ec.unmarshalNInt2int(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["seconds"] = arg1
+	return args, nil
+}
+
 func (ec *executionContext) field_Mutation_cancelRun_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
 	var err error
 	args := map[string]interface{}{}
@@ -1888,6 +2284,45 @@ func (ec *executionContext) field_Mutation_invokeFunction_args(ctx context.Conte
 	return args, nil
 }
 
+func (ec *executionContext) field_Mutation_pauseFunctionQueue_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+	var err error
+	args := map[string]interface{}{}
+	var arg0 string
+	if tmp, ok := rawArgs["functionID"]; ok {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("functionID"))
+		arg0, err = ec.unmarshalNString2string(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["functionID"] = arg0
+	return args, nil
+}
+
+func (ec *executionContext) field_Mutation_requeueStep_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+	var err error
+	args := map[string]interface{}{}
+	var arg0 ulid.ULID
+	if tmp, ok := rawArgs["runID"]; ok {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("runID"))
+		arg0, err = ec.unmarshalNULID2githubᚗcomᚋoklogᚋulidᚋv2ᚐULID(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["runID"] = arg0
+	var arg1 string
+	if tmp, ok := rawArgs["stepID"]; ok {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("stepID"))
+		arg1, err = ec.unmarshalNString2string(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["stepID"] = arg1
+	return args, nil
+}
+
 func (ec *executionContext) field_Mutation_rerun_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
 	var err error
 	args := map[string]interface{}{}
@@ -1903,6 +2338,21 @@ func (ec *executionContext) field_Mutation_rerun_args(ctx context.Context, rawAr
 	return args, nil
 }
 
+func (ec *executionContext) field_Mutation_resumeFunctionQueue_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+	var err error
+	args := map[string]interface{}{}
+	var arg0 string
+	if tmp, ok := rawArgs["functionID"]; ok {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("functionID"))
+		arg0, err = ec.unmarshalNString2string(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["functionID"] = arg0
+	return args, nil
+}
+
 func (ec *executionContext) field_Mutation_updateApp_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
 	var err error
 	args := map[string]interface{}{}
@@ -1918,6 +2368,30 @@ func (ec *executionContext) field_Mutation_updateApp_args(ctx context.Context, r
 	return args, nil
 }
 
+func (ec *executionContext) field_Mutation_updateFunctionThrottle_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+	var err error
+	args := map[string]interface{}{}
+	var arg0 string
+	if tmp, ok := rawArgs["functionID"]; ok {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("functionID"))
+		arg0, err = ec.unmarshalNString2string(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["functionID"] = arg0
+	var arg1 *models.FunctionThrottleInput
+	if tmp, ok := rawArgs["throttle"]; ok {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("throttle"))
+		arg1, err = ec.unmarshalOFunctionThrottleInput2ᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋcoreapiᚋgraphᚋmodelsᚐFunctionThrottleInput(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["throttle"] = arg1
+	return args, nil
+}
+
 func (ec *executionContext) field_Query___type_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
 	var err error
 	args := map[string]interface{}{}
@@ -1978,6 +2452,21 @@ func (ec *executionContext) field_Query_functionRun_args(ctx context.Context, ra
 	return args, nil
 }
 
+func (ec *executionContext) field_Query_searchFunctionRuns_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+	var err error
+	args := map[string]interface{}{}
+	var arg0 models.SearchFunctionRunsQuery
+	if tmp, ok := rawArgs["query"]; ok {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("query"))
+		arg0, err = ec.unmarshalNSearchFunctionRunsQuery2githubᚗcomᚋinngestᚋinngestᚋpkgᚋcoreapiᚋgraphᚋmodelsᚐSearchFunctionRunsQuery(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["query"] = arg0
+	return args, nil
+}
+
 func (ec *executionContext) field_Query_stream_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
 	var err error
 	args := map[string]interface{}{}
@@ -2428,6 +2917,8 @@ func (ec *executionContext) fieldContext_App_functions(ctx context.Context, fiel
 				return ec.fieldContext_Function_appID(ctx, field)
 			case "app":
 				return ec.fieldContext_Function_app(ctx, field)
+			case "queue":
+				return ec.fieldContext_Function_queue(ctx, field)
 			}
 			return nil, fmt.Errorf("no field named %q was found under type Function", field.Name)
 		},
@@ -3079,6 +3570,8 @@ func (ec *executionContext) fieldContext_Event_functionRuns(ctx context.Context,
 				return ec.fieldContext_FunctionRun_batchCreatedAt(ctx, field)
 			case "status":
 				return ec.fieldContext_FunctionRun_status(ctx, field)
+			case "waitingReason":
+				return ec.fieldContext_FunctionRun_waitingReason(ctx, field)
 			case "waitingFor":
 				return ec.fieldContext_FunctionRun_waitingFor(ctx, field)
 			case "pendingSteps":
@@ -3093,6 +3586,8 @@ func (ec *executionContext) fieldContext_Event_functionRuns(ctx context.Context,
 				return ec.fieldContext_FunctionRun_history(ctx, field)
 			case "historyItemOutput":
 				return ec.fieldContext_FunctionRun_historyItemOutput(ctx, field)
+			case "stepLogs":
+				return ec.fieldContext_FunctionRun_stepLogs(ctx, field)
 			case "eventID":
 				return ec.fieldContext_FunctionRun_eventID(ctx, field)
 			case "cron":
@@ -3529,8 +4024,8 @@ func (ec *executionContext) fieldContext_Function_app(ctx context.Context, field
 	return fc, nil
 }
 
-func (ec *executionContext) _FunctionEvent_workspace(ctx context.Context, field graphql.CollectedField, obj *models.FunctionEvent) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_FunctionEvent_workspace(ctx, field)
+func (ec *executionContext) _Function_queue(ctx context.Context, field graphql.CollectedField, obj *models.Function) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Function_queue(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -3543,21 +4038,81 @@ func (ec *executionContext) _FunctionEvent_workspace(ctx context.Context, field
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Workspace, nil
+		return ec.resolvers.Function().Queue(rctx, obj)
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
 		return graphql.Null
 	}
-	res := resTmp.(*models.Workspace)
+	res := resTmp.(*queue.PartitionStats)
 	fc.Result = res
-	return ec.marshalOWorkspace2ᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋcoreapiᚋgraphᚋmodelsᚐWorkspace(ctx, field.Selections, res)
+	return ec.marshalNFunctionQueue2ᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋexecutionᚋqueueᚐPartitionStats(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_FunctionEvent_workspace(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Function_queue(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Function",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "ready":
+				return ec.fieldContext_FunctionQueue_ready(ctx, field)
+			case "scheduled":
+				return ec.fieldContext_FunctionQueue_scheduled(ctx, field)
+			case "inProgress":
+				return ec.fieldContext_FunctionQueue_inProgress(ctx, field)
+			case "concurrencyLimit":
+				return ec.fieldContext_FunctionQueue_concurrencyLimit(ctx, field)
+			case "concurrencyLimited":
+				return ec.fieldContext_FunctionQueue_concurrencyLimited(ctx, field)
+			case "oldestItemAt":
+				return ec.fieldContext_FunctionQueue_oldestItemAt(ctx, field)
+			case "paused":
+				return ec.fieldContext_FunctionQueue_paused(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type FunctionQueue", field.Name)
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _FunctionEvent_workspace(ctx context.Context, field graphql.CollectedField, obj *models.FunctionEvent) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_FunctionEvent_workspace(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Workspace, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*models.Workspace)
+	fc.Result = res
+	return ec.marshalOWorkspace2ᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋcoreapiᚋgraphᚋmodelsᚐWorkspace(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_FunctionEvent_workspace(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
 		Object:     "FunctionEvent",
 		Field:      field,
@@ -3628,6 +4183,8 @@ func (ec *executionContext) fieldContext_FunctionEvent_functionRun(ctx context.C
 				return ec.fieldContext_FunctionRun_batchCreatedAt(ctx, field)
 			case "status":
 				return ec.fieldContext_FunctionRun_status(ctx, field)
+			case "waitingReason":
+				return ec.fieldContext_FunctionRun_waitingReason(ctx, field)
 			case "waitingFor":
 				return ec.fieldContext_FunctionRun_waitingFor(ctx, field)
 			case "pendingSteps":
@@ -3642,6 +4199,8 @@ func (ec *executionContext) fieldContext_FunctionEvent_functionRun(ctx context.C
 				return ec.fieldContext_FunctionRun_history(ctx, field)
 			case "historyItemOutput":
 				return ec.fieldContext_FunctionRun_historyItemOutput(ctx, field)
+			case "stepLogs":
+				return ec.fieldContext_FunctionRun_stepLogs(ctx, field)
 			case "eventID":
 				return ec.fieldContext_FunctionRun_eventID(ctx, field)
 			case "cron":
@@ -3776,8 +4335,8 @@ func (ec *executionContext) fieldContext_FunctionEvent_createdAt(ctx context.Con
 	return fc, nil
 }
 
-func (ec *executionContext) _FunctionRun_id(ctx context.Context, field graphql.CollectedField, obj *models.FunctionRun) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_FunctionRun_id(ctx, field)
+func (ec *executionContext) _FunctionQueue_ready(ctx context.Context, field graphql.CollectedField, obj *queue.PartitionStats) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_FunctionQueue_ready(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -3790,7 +4349,7 @@ func (ec *executionContext) _FunctionRun_id(ctx context.Context, field graphql.C
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.ID, nil
+		return obj.Ready, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -3802,26 +4361,26 @@ func (ec *executionContext) _FunctionRun_id(ctx context.Context, field graphql.C
 		}
 		return graphql.Null
 	}
-	res := resTmp.(string)
+	res := resTmp.(int64)
 	fc.Result = res
-	return ec.marshalNID2string(ctx, field.Selections, res)
+	return ec.marshalNInt2int64(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_FunctionRun_id(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_FunctionQueue_ready(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "FunctionRun",
+		Object:     "FunctionQueue",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type ID does not have child fields")
+			return nil, errors.New("field of type Int does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _FunctionRun_functionID(ctx context.Context, field graphql.CollectedField, obj *models.FunctionRun) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_FunctionRun_functionID(ctx, field)
+func (ec *executionContext) _FunctionQueue_scheduled(ctx context.Context, field graphql.CollectedField, obj *queue.PartitionStats) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_FunctionQueue_scheduled(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -3834,7 +4393,7 @@ func (ec *executionContext) _FunctionRun_functionID(ctx context.Context, field g
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.FunctionID, nil
+		return obj.Scheduled, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -3846,26 +4405,26 @@ func (ec *executionContext) _FunctionRun_functionID(ctx context.Context, field g
 		}
 		return graphql.Null
 	}
-	res := resTmp.(string)
+	res := resTmp.(int64)
 	fc.Result = res
-	return ec.marshalNString2string(ctx, field.Selections, res)
+	return ec.marshalNInt2int64(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_FunctionRun_functionID(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_FunctionQueue_scheduled(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "FunctionRun",
+		Object:     "FunctionQueue",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type String does not have child fields")
+			return nil, errors.New("field of type Int does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _FunctionRun_function(ctx context.Context, field graphql.CollectedField, obj *models.FunctionRun) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_FunctionRun_function(ctx, field)
+func (ec *executionContext) _FunctionQueue_inProgress(ctx context.Context, field graphql.CollectedField, obj *queue.PartitionStats) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_FunctionQueue_inProgress(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -3878,55 +4437,38 @@ func (ec *executionContext) _FunctionRun_function(ctx context.Context, field gra
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return ec.resolvers.FunctionRun().Function(rctx, obj)
+		return obj.InProgress, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
 		return graphql.Null
 	}
-	res := resTmp.(*models.Function)
+	res := resTmp.(int64)
 	fc.Result = res
-	return ec.marshalOFunction2ᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋcoreapiᚋgraphᚋmodelsᚐFunction(ctx, field.Selections, res)
+	return ec.marshalNInt2int64(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_FunctionRun_function(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_FunctionQueue_inProgress(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "FunctionRun",
+		Object:     "FunctionQueue",
 		Field:      field,
-		IsMethod:   true,
-		IsResolver: true,
+		IsMethod:   false,
+		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "id":
-				return ec.fieldContext_Function_id(ctx, field)
-			case "name":
-				return ec.fieldContext_Function_name(ctx, field)
-			case "slug":
-				return ec.fieldContext_Function_slug(ctx, field)
-			case "config":
-				return ec.fieldContext_Function_config(ctx, field)
-			case "concurrency":
-				return ec.fieldContext_Function_concurrency(ctx, field)
-			case "triggers":
-				return ec.fieldContext_Function_triggers(ctx, field)
-			case "url":
-				return ec.fieldContext_Function_url(ctx, field)
-			case "appID":
-				return ec.fieldContext_Function_appID(ctx, field)
-			case "app":
-				return ec.fieldContext_Function_app(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type Function", field.Name)
+			return nil, errors.New("field of type Int does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _FunctionRun_workspace(ctx context.Context, field graphql.CollectedField, obj *models.FunctionRun) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_FunctionRun_workspace(ctx, field)
+func (ec *executionContext) _FunctionQueue_concurrencyLimit(ctx context.Context, field graphql.CollectedField, obj *queue.PartitionStats) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_FunctionQueue_concurrencyLimit(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -3939,39 +4481,38 @@ func (ec *executionContext) _FunctionRun_workspace(ctx context.Context, field gr
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Workspace, nil
+		return obj.ConcurrencyLimit, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
 		return graphql.Null
 	}
-	res := resTmp.(*models.Workspace)
+	res := resTmp.(int64)
 	fc.Result = res
-	return ec.marshalOWorkspace2ᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋcoreapiᚋgraphᚋmodelsᚐWorkspace(ctx, field.Selections, res)
+	return ec.marshalNInt2int64(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_FunctionRun_workspace(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_FunctionQueue_concurrencyLimit(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "FunctionRun",
+		Object:     "FunctionQueue",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "id":
-				return ec.fieldContext_Workspace_id(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type Workspace", field.Name)
+			return nil, errors.New("field of type Int does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _FunctionRun_event(ctx context.Context, field graphql.CollectedField, obj *models.FunctionRun) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_FunctionRun_event(ctx, field)
+func (ec *executionContext) _FunctionQueue_concurrencyLimited(ctx context.Context, field graphql.CollectedField, obj *queue.PartitionStats) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_FunctionQueue_concurrencyLimited(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -3984,61 +4525,38 @@ func (ec *executionContext) _FunctionRun_event(ctx context.Context, field graphq
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return ec.resolvers.FunctionRun().Event(rctx, obj)
+		return obj.ConcurrencyLimited, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
 		return graphql.Null
 	}
-	res := resTmp.(*models.Event)
+	res := resTmp.(int64)
 	fc.Result = res
-	return ec.marshalOEvent2ᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋcoreapiᚋgraphᚋmodelsᚐEvent(ctx, field.Selections, res)
+	return ec.marshalNInt2int64(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_FunctionRun_event(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_FunctionQueue_concurrencyLimited(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "FunctionRun",
+		Object:     "FunctionQueue",
 		Field:      field,
-		IsMethod:   true,
-		IsResolver: true,
+		IsMethod:   false,
+		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "id":
-				return ec.fieldContext_Event_id(ctx, field)
-			case "externalID":
-				return ec.fieldContext_Event_externalID(ctx, field)
-			case "workspace":
-				return ec.fieldContext_Event_workspace(ctx, field)
-			case "name":
-				return ec.fieldContext_Event_name(ctx, field)
-			case "createdAt":
-				return ec.fieldContext_Event_createdAt(ctx, field)
-			case "payload":
-				return ec.fieldContext_Event_payload(ctx, field)
-			case "schema":
-				return ec.fieldContext_Event_schema(ctx, field)
-			case "status":
-				return ec.fieldContext_Event_status(ctx, field)
-			case "pendingRuns":
-				return ec.fieldContext_Event_pendingRuns(ctx, field)
-			case "totalRuns":
-				return ec.fieldContext_Event_totalRuns(ctx, field)
-			case "raw":
-				return ec.fieldContext_Event_raw(ctx, field)
-			case "functionRuns":
-				return ec.fieldContext_Event_functionRuns(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type Event", field.Name)
+			return nil, errors.New("field of type Int does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _FunctionRun_events(ctx context.Context, field graphql.CollectedField, obj *models.FunctionRun) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_FunctionRun_events(ctx, field)
+func (ec *executionContext) _FunctionQueue_oldestItemAt(ctx context.Context, field graphql.CollectedField, obj *queue.PartitionStats) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_FunctionQueue_oldestItemAt(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -4051,64 +4569,35 @@ func (ec *executionContext) _FunctionRun_events(ctx context.Context, field graph
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return ec.resolvers.FunctionRun().Events(rctx, obj)
+		return obj.OldestItemAt, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
-		if !graphql.HasFieldError(ctx, fc) {
-			ec.Errorf(ctx, "must not be null")
-		}
 		return graphql.Null
 	}
-	res := resTmp.([]*models.Event)
+	res := resTmp.(*time.Time)
 	fc.Result = res
-	return ec.marshalNEvent2ᚕᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋcoreapiᚋgraphᚋmodelsᚐEventᚄ(ctx, field.Selections, res)
+	return ec.marshalOTime2ᚖtimeᚐTime(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_FunctionRun_events(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_FunctionQueue_oldestItemAt(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "FunctionRun",
+		Object:     "FunctionQueue",
 		Field:      field,
-		IsMethod:   true,
-		IsResolver: true,
+		IsMethod:   false,
+		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "id":
-				return ec.fieldContext_Event_id(ctx, field)
-			case "externalID":
-				return ec.fieldContext_Event_externalID(ctx, field)
-			case "workspace":
-				return ec.fieldContext_Event_workspace(ctx, field)
-			case "name":
-				return ec.fieldContext_Event_name(ctx, field)
-			case "createdAt":
-				return ec.fieldContext_Event_createdAt(ctx, field)
-			case "payload":
-				return ec.fieldContext_Event_payload(ctx, field)
-			case "schema":
-				return ec.fieldContext_Event_schema(ctx, field)
-			case "status":
-				return ec.fieldContext_Event_status(ctx, field)
-			case "pendingRuns":
-				return ec.fieldContext_Event_pendingRuns(ctx, field)
-			case "totalRuns":
-				return ec.fieldContext_Event_totalRuns(ctx, field)
-			case "raw":
-				return ec.fieldContext_Event_raw(ctx, field)
-			case "functionRuns":
-				return ec.fieldContext_Event_functionRuns(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type Event", field.Name)
+			return nil, errors.New("field of type Time does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _FunctionRun_batchID(ctx context.Context, field graphql.CollectedField, obj *models.FunctionRun) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_FunctionRun_batchID(ctx, field)
+func (ec *executionContext) _FunctionQueue_paused(ctx context.Context, field graphql.CollectedField, obj *queue.PartitionStats) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_FunctionQueue_paused(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -4121,35 +4610,38 @@ func (ec *executionContext) _FunctionRun_batchID(ctx context.Context, field grap
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.BatchID, nil
+		return obj.Paused, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
 		return graphql.Null
 	}
-	res := resTmp.(*ulid.ULID)
+	res := resTmp.(bool)
 	fc.Result = res
-	return ec.marshalOULID2ᚖgithubᚗcomᚋoklogᚋulidᚋv2ᚐULID(ctx, field.Selections, res)
+	return ec.marshalNBoolean2bool(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_FunctionRun_batchID(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_FunctionQueue_paused(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "FunctionRun",
+		Object:     "FunctionQueue",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type ULID does not have child fields")
+			return nil, errors.New("field of type Boolean does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _FunctionRun_batchCreatedAt(ctx context.Context, field graphql.CollectedField, obj *models.FunctionRun) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_FunctionRun_batchCreatedAt(ctx, field)
+func (ec *executionContext) _FunctionRun_id(ctx context.Context, field graphql.CollectedField, obj *models.FunctionRun) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_FunctionRun_id(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -4162,35 +4654,38 @@ func (ec *executionContext) _FunctionRun_batchCreatedAt(ctx context.Context, fie
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return ec.resolvers.FunctionRun().BatchCreatedAt(rctx, obj)
+		return obj.ID, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
 		return graphql.Null
 	}
-	res := resTmp.(*time.Time)
+	res := resTmp.(string)
 	fc.Result = res
-	return ec.marshalOTime2ᚖtimeᚐTime(ctx, field.Selections, res)
+	return ec.marshalNID2string(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_FunctionRun_batchCreatedAt(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_FunctionRun_id(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
 		Object:     "FunctionRun",
 		Field:      field,
-		IsMethod:   true,
-		IsResolver: true,
+		IsMethod:   false,
+		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type Time does not have child fields")
+			return nil, errors.New("field of type ID does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _FunctionRun_status(ctx context.Context, field graphql.CollectedField, obj *models.FunctionRun) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_FunctionRun_status(ctx, field)
+func (ec *executionContext) _FunctionRun_functionID(ctx context.Context, field graphql.CollectedField, obj *models.FunctionRun) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_FunctionRun_functionID(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -4203,35 +4698,38 @@ func (ec *executionContext) _FunctionRun_status(ctx context.Context, field graph
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Status, nil
+		return obj.FunctionID, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
 		return graphql.Null
 	}
-	res := resTmp.(*models.FunctionRunStatus)
+	res := resTmp.(string)
 	fc.Result = res
-	return ec.marshalOFunctionRunStatus2ᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋcoreapiᚋgraphᚋmodelsᚐFunctionRunStatus(ctx, field.Selections, res)
+	return ec.marshalNString2string(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_FunctionRun_status(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_FunctionRun_functionID(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
 		Object:     "FunctionRun",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type FunctionRunStatus does not have child fields")
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _FunctionRun_waitingFor(ctx context.Context, field graphql.CollectedField, obj *models.FunctionRun) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_FunctionRun_waitingFor(ctx, field)
+func (ec *executionContext) _FunctionRun_function(ctx context.Context, field graphql.CollectedField, obj *models.FunctionRun) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_FunctionRun_function(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -4244,7 +4742,7 @@ func (ec *executionContext) _FunctionRun_waitingFor(ctx context.Context, field g
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return ec.resolvers.FunctionRun().WaitingFor(rctx, obj)
+		return ec.resolvers.FunctionRun().Function(rctx, obj)
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -4253,12 +4751,12 @@ func (ec *executionContext) _FunctionRun_waitingFor(ctx context.Context, field g
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*models.StepEventWait)
+	res := resTmp.(*models.Function)
 	fc.Result = res
-	return ec.marshalOStepEventWait2ᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋcoreapiᚋgraphᚋmodelsᚐStepEventWait(ctx, field.Selections, res)
+	return ec.marshalOFunction2ᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋcoreapiᚋgraphᚋmodelsᚐFunction(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_FunctionRun_waitingFor(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_FunctionRun_function(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
 		Object:     "FunctionRun",
 		Field:      field,
@@ -4266,21 +4764,35 @@ func (ec *executionContext) fieldContext_FunctionRun_waitingFor(ctx context.Cont
 		IsResolver: true,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
 			switch field.Name {
-			case "eventName":
-				return ec.fieldContext_StepEventWait_eventName(ctx, field)
-			case "expression":
-				return ec.fieldContext_StepEventWait_expression(ctx, field)
-			case "expiryTime":
-				return ec.fieldContext_StepEventWait_expiryTime(ctx, field)
+			case "id":
+				return ec.fieldContext_Function_id(ctx, field)
+			case "name":
+				return ec.fieldContext_Function_name(ctx, field)
+			case "slug":
+				return ec.fieldContext_Function_slug(ctx, field)
+			case "config":
+				return ec.fieldContext_Function_config(ctx, field)
+			case "concurrency":
+				return ec.fieldContext_Function_concurrency(ctx, field)
+			case "triggers":
+				return ec.fieldContext_Function_triggers(ctx, field)
+			case "url":
+				return ec.fieldContext_Function_url(ctx, field)
+			case "appID":
+				return ec.fieldContext_Function_appID(ctx, field)
+			case "app":
+				return ec.fieldContext_Function_app(ctx, field)
+			case "queue":
+				return ec.fieldContext_Function_queue(ctx, field)
 			}
-			return nil, fmt.Errorf("no field named %q was found under type StepEventWait", field.Name)
+			return nil, fmt.Errorf("no field named %q was found under type Function", field.Name)
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _FunctionRun_pendingSteps(ctx context.Context, field graphql.CollectedField, obj *models.FunctionRun) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_FunctionRun_pendingSteps(ctx, field)
+func (ec *executionContext) _FunctionRun_workspace(ctx context.Context, field graphql.CollectedField, obj *models.FunctionRun) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_FunctionRun_workspace(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -4293,7 +4805,7 @@ func (ec *executionContext) _FunctionRun_pendingSteps(ctx context.Context, field
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return ec.resolvers.FunctionRun().PendingSteps(rctx, obj)
+		return obj.Workspace, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -4302,26 +4814,30 @@ func (ec *executionContext) _FunctionRun_pendingSteps(ctx context.Context, field
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*int)
+	res := resTmp.(*models.Workspace)
 	fc.Result = res
-	return ec.marshalOInt2ᚖint(ctx, field.Selections, res)
+	return ec.marshalOWorkspace2ᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋcoreapiᚋgraphᚋmodelsᚐWorkspace(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_FunctionRun_pendingSteps(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_FunctionRun_workspace(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
 		Object:     "FunctionRun",
 		Field:      field,
-		IsMethod:   true,
-		IsResolver: true,
+		IsMethod:   false,
+		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type Int does not have child fields")
+			switch field.Name {
+			case "id":
+				return ec.fieldContext_Workspace_id(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type Workspace", field.Name)
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _FunctionRun_startedAt(ctx context.Context, field graphql.CollectedField, obj *models.FunctionRun) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_FunctionRun_startedAt(ctx, field)
+func (ec *executionContext) _FunctionRun_event(ctx context.Context, field graphql.CollectedField, obj *models.FunctionRun) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_FunctionRun_event(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -4334,7 +4850,7 @@ func (ec *executionContext) _FunctionRun_startedAt(ctx context.Context, field gr
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.StartedAt, nil
+		return ec.resolvers.FunctionRun().Event(rctx, obj)
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -4343,26 +4859,52 @@ func (ec *executionContext) _FunctionRun_startedAt(ctx context.Context, field gr
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*time.Time)
+	res := resTmp.(*models.Event)
 	fc.Result = res
-	return ec.marshalOTime2ᚖtimeᚐTime(ctx, field.Selections, res)
+	return ec.marshalOEvent2ᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋcoreapiᚋgraphᚋmodelsᚐEvent(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_FunctionRun_startedAt(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_FunctionRun_event(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
 		Object:     "FunctionRun",
 		Field:      field,
-		IsMethod:   false,
-		IsResolver: false,
+		IsMethod:   true,
+		IsResolver: true,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type Time does not have child fields")
+			switch field.Name {
+			case "id":
+				return ec.fieldContext_Event_id(ctx, field)
+			case "externalID":
+				return ec.fieldContext_Event_externalID(ctx, field)
+			case "workspace":
+				return ec.fieldContext_Event_workspace(ctx, field)
+			case "name":
+				return ec.fieldContext_Event_name(ctx, field)
+			case "createdAt":
+				return ec.fieldContext_Event_createdAt(ctx, field)
+			case "payload":
+				return ec.fieldContext_Event_payload(ctx, field)
+			case "schema":
+				return ec.fieldContext_Event_schema(ctx, field)
+			case "status":
+				return ec.fieldContext_Event_status(ctx, field)
+			case "pendingRuns":
+				return ec.fieldContext_Event_pendingRuns(ctx, field)
+			case "totalRuns":
+				return ec.fieldContext_Event_totalRuns(ctx, field)
+			case "raw":
+				return ec.fieldContext_Event_raw(ctx, field)
+			case "functionRuns":
+				return ec.fieldContext_Event_functionRuns(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type Event", field.Name)
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _FunctionRun_finishedAt(ctx context.Context, field graphql.CollectedField, obj *models.FunctionRun) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_FunctionRun_finishedAt(ctx, field)
+func (ec *executionContext) _FunctionRun_events(ctx context.Context, field graphql.CollectedField, obj *models.FunctionRun) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_FunctionRun_events(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -4375,35 +4917,64 @@ func (ec *executionContext) _FunctionRun_finishedAt(ctx context.Context, field g
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.FinishedAt, nil
+		return ec.resolvers.FunctionRun().Events(rctx, obj)
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
 		return graphql.Null
 	}
-	res := resTmp.(*time.Time)
+	res := resTmp.([]*models.Event)
 	fc.Result = res
-	return ec.marshalOTime2ᚖtimeᚐTime(ctx, field.Selections, res)
+	return ec.marshalNEvent2ᚕᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋcoreapiᚋgraphᚋmodelsᚐEventᚄ(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_FunctionRun_finishedAt(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_FunctionRun_events(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
 		Object:     "FunctionRun",
 		Field:      field,
-		IsMethod:   false,
-		IsResolver: false,
+		IsMethod:   true,
+		IsResolver: true,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type Time does not have child fields")
+			switch field.Name {
+			case "id":
+				return ec.fieldContext_Event_id(ctx, field)
+			case "externalID":
+				return ec.fieldContext_Event_externalID(ctx, field)
+			case "workspace":
+				return ec.fieldContext_Event_workspace(ctx, field)
+			case "name":
+				return ec.fieldContext_Event_name(ctx, field)
+			case "createdAt":
+				return ec.fieldContext_Event_createdAt(ctx, field)
+			case "payload":
+				return ec.fieldContext_Event_payload(ctx, field)
+			case "schema":
+				return ec.fieldContext_Event_schema(ctx, field)
+			case "status":
+				return ec.fieldContext_Event_status(ctx, field)
+			case "pendingRuns":
+				return ec.fieldContext_Event_pendingRuns(ctx, field)
+			case "totalRuns":
+				return ec.fieldContext_Event_totalRuns(ctx, field)
+			case "raw":
+				return ec.fieldContext_Event_raw(ctx, field)
+			case "functionRuns":
+				return ec.fieldContext_Event_functionRuns(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type Event", field.Name)
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _FunctionRun_output(ctx context.Context, field graphql.CollectedField, obj *models.FunctionRun) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_FunctionRun_output(ctx, field)
+func (ec *executionContext) _FunctionRun_batchID(ctx context.Context, field graphql.CollectedField, obj *models.FunctionRun) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_FunctionRun_batchID(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -4416,7 +4987,7 @@ func (ec *executionContext) _FunctionRun_output(ctx context.Context, field graph
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Output, nil
+		return obj.BatchID, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -4425,26 +4996,26 @@ func (ec *executionContext) _FunctionRun_output(ctx context.Context, field graph
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*string)
+	res := resTmp.(*ulid.ULID)
 	fc.Result = res
-	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+	return ec.marshalOULID2ᚖgithubᚗcomᚋoklogᚋulidᚋv2ᚐULID(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_FunctionRun_output(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_FunctionRun_batchID(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
 		Object:     "FunctionRun",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type String does not have child fields")
+			return nil, errors.New("field of type ULID does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _FunctionRun_history(ctx context.Context, field graphql.CollectedField, obj *models.FunctionRun) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_FunctionRun_history(ctx, field)
+func (ec *executionContext) _FunctionRun_batchCreatedAt(ctx context.Context, field graphql.CollectedField, obj *models.FunctionRun) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_FunctionRun_batchCreatedAt(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -4457,72 +5028,35 @@ func (ec *executionContext) _FunctionRun_history(ctx context.Context, field grap
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return ec.resolvers.FunctionRun().History(rctx, obj)
+		return ec.resolvers.FunctionRun().BatchCreatedAt(rctx, obj)
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
-		if !graphql.HasFieldError(ctx, fc) {
-			ec.Errorf(ctx, "must not be null")
-		}
 		return graphql.Null
 	}
-	res := resTmp.([]*history_reader.RunHistory)
+	res := resTmp.(*time.Time)
 	fc.Result = res
-	return ec.marshalNRunHistoryItem2ᚕᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋhistory_readerᚐRunHistoryᚄ(ctx, field.Selections, res)
+	return ec.marshalOTime2ᚖtimeᚐTime(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_FunctionRun_history(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_FunctionRun_batchCreatedAt(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
 		Object:     "FunctionRun",
 		Field:      field,
 		IsMethod:   true,
 		IsResolver: true,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "attempt":
-				return ec.fieldContext_RunHistoryItem_attempt(ctx, field)
-			case "cancel":
-				return ec.fieldContext_RunHistoryItem_cancel(ctx, field)
-			case "createdAt":
-				return ec.fieldContext_RunHistoryItem_createdAt(ctx, field)
-			case "functionVersion":
-				return ec.fieldContext_RunHistoryItem_functionVersion(ctx, field)
-			case "groupID":
-				return ec.fieldContext_RunHistoryItem_groupID(ctx, field)
-			case "id":
-				return ec.fieldContext_RunHistoryItem_id(ctx, field)
-			case "result":
-				return ec.fieldContext_RunHistoryItem_result(ctx, field)
-			case "sleep":
-				return ec.fieldContext_RunHistoryItem_sleep(ctx, field)
-			case "stepName":
-				return ec.fieldContext_RunHistoryItem_stepName(ctx, field)
-			case "stepType":
-				return ec.fieldContext_RunHistoryItem_stepType(ctx, field)
-			case "type":
-				return ec.fieldContext_RunHistoryItem_type(ctx, field)
-			case "url":
-				return ec.fieldContext_RunHistoryItem_url(ctx, field)
-			case "waitForEvent":
-				return ec.fieldContext_RunHistoryItem_waitForEvent(ctx, field)
-			case "waitResult":
-				return ec.fieldContext_RunHistoryItem_waitResult(ctx, field)
-			case "invokeFunction":
-				return ec.fieldContext_RunHistoryItem_invokeFunction(ctx, field)
-			case "invokeFunctionResult":
-				return ec.fieldContext_RunHistoryItem_invokeFunctionResult(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type RunHistoryItem", field.Name)
+			return nil, errors.New("field of type Time does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _FunctionRun_historyItemOutput(ctx context.Context, field graphql.CollectedField, obj *models.FunctionRun) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_FunctionRun_historyItemOutput(ctx, field)
+func (ec *executionContext) _FunctionRun_status(ctx context.Context, field graphql.CollectedField, obj *models.FunctionRun) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_FunctionRun_status(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -4535,7 +5069,7 @@ func (ec *executionContext) _FunctionRun_historyItemOutput(ctx context.Context,
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return ec.resolvers.FunctionRun().HistoryItemOutput(rctx, obj, fc.Args["id"].(ulid.ULID))
+		return obj.Status, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -4544,37 +5078,26 @@ func (ec *executionContext) _FunctionRun_historyItemOutput(ctx context.Context,
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*string)
+	res := resTmp.(*models.FunctionRunStatus)
 	fc.Result = res
-	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+	return ec.marshalOFunctionRunStatus2ᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋcoreapiᚋgraphᚋmodelsᚐFunctionRunStatus(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_FunctionRun_historyItemOutput(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_FunctionRun_status(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
 		Object:     "FunctionRun",
 		Field:      field,
-		IsMethod:   true,
-		IsResolver: true,
+		IsMethod:   false,
+		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type String does not have child fields")
+			return nil, errors.New("field of type FunctionRunStatus does not have child fields")
 		},
 	}
-	defer func() {
-		if r := recover(); r != nil {
-			err = ec.Recover(ctx, r)
-			ec.Error(ctx, err)
-		}
-	}()
-	ctx = graphql.WithFieldContext(ctx, fc)
-	if fc.Args, err = ec.field_FunctionRun_historyItemOutput_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
-		ec.Error(ctx, err)
-		return
-	}
 	return fc, nil
 }
 
-func (ec *executionContext) _FunctionRun_eventID(ctx context.Context, field graphql.CollectedField, obj *models.FunctionRun) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_FunctionRun_eventID(ctx, field)
+func (ec *executionContext) _FunctionRun_waitingReason(ctx context.Context, field graphql.CollectedField, obj *models.FunctionRun) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_FunctionRun_waitingReason(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -4587,38 +5110,35 @@ func (ec *executionContext) _FunctionRun_eventID(ctx context.Context, field grap
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.EventID, nil
+		return obj.WaitingReason, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
-		if !graphql.HasFieldError(ctx, fc) {
-			ec.Errorf(ctx, "must not be null")
-		}
 		return graphql.Null
 	}
-	res := resTmp.(string)
+	res := resTmp.(*models.FunctionRunWaitingReason)
 	fc.Result = res
-	return ec.marshalNID2string(ctx, field.Selections, res)
+	return ec.marshalOFunctionRunWaitingReason2ᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋcoreapiᚋgraphᚋmodelsᚐFunctionRunWaitingReason(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_FunctionRun_eventID(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_FunctionRun_waitingReason(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
 		Object:     "FunctionRun",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type ID does not have child fields")
+			return nil, errors.New("field of type FunctionRunWaitingReason does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _FunctionRun_cron(ctx context.Context, field graphql.CollectedField, obj *models.FunctionRun) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_FunctionRun_cron(ctx, field)
+func (ec *executionContext) _FunctionRun_waitingFor(ctx context.Context, field graphql.CollectedField, obj *models.FunctionRun) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_FunctionRun_waitingFor(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -4631,7 +5151,7 @@ func (ec *executionContext) _FunctionRun_cron(ctx context.Context, field graphql
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Cron, nil
+		return ec.resolvers.FunctionRun().WaitingFor(rctx, obj)
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -4640,26 +5160,34 @@ func (ec *executionContext) _FunctionRun_cron(ctx context.Context, field graphql
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*string)
+	res := resTmp.(*models.StepEventWait)
 	fc.Result = res
-	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+	return ec.marshalOStepEventWait2ᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋcoreapiᚋgraphᚋmodelsᚐStepEventWait(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_FunctionRun_cron(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_FunctionRun_waitingFor(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
 		Object:     "FunctionRun",
 		Field:      field,
-		IsMethod:   false,
-		IsResolver: false,
+		IsMethod:   true,
+		IsResolver: true,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type String does not have child fields")
+			switch field.Name {
+			case "eventName":
+				return ec.fieldContext_StepEventWait_eventName(ctx, field)
+			case "expression":
+				return ec.fieldContext_StepEventWait_expression(ctx, field)
+			case "expiryTime":
+				return ec.fieldContext_StepEventWait_expiryTime(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type StepEventWait", field.Name)
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _FunctionTrigger_type(ctx context.Context, field graphql.CollectedField, obj *models.FunctionTrigger) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_FunctionTrigger_type(ctx, field)
+func (ec *executionContext) _FunctionRun_pendingSteps(ctx context.Context, field graphql.CollectedField, obj *models.FunctionRun) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_FunctionRun_pendingSteps(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -4672,38 +5200,35 @@ func (ec *executionContext) _FunctionTrigger_type(ctx context.Context, field gra
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Type, nil
+		return ec.resolvers.FunctionRun().PendingSteps(rctx, obj)
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
-		if !graphql.HasFieldError(ctx, fc) {
-			ec.Errorf(ctx, "must not be null")
-		}
 		return graphql.Null
 	}
-	res := resTmp.(models.FunctionTriggerTypes)
+	res := resTmp.(*int)
 	fc.Result = res
-	return ec.marshalNFunctionTriggerTypes2githubᚗcomᚋinngestᚋinngestᚋpkgᚋcoreapiᚋgraphᚋmodelsᚐFunctionTriggerTypes(ctx, field.Selections, res)
+	return ec.marshalOInt2ᚖint(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_FunctionTrigger_type(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_FunctionRun_pendingSteps(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "FunctionTrigger",
+		Object:     "FunctionRun",
 		Field:      field,
-		IsMethod:   false,
-		IsResolver: false,
+		IsMethod:   true,
+		IsResolver: true,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type FunctionTriggerTypes does not have child fields")
+			return nil, errors.New("field of type Int does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _FunctionTrigger_value(ctx context.Context, field graphql.CollectedField, obj *models.FunctionTrigger) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_FunctionTrigger_value(ctx, field)
+func (ec *executionContext) _FunctionRun_startedAt(ctx context.Context, field graphql.CollectedField, obj *models.FunctionRun) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_FunctionRun_startedAt(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -4716,38 +5241,35 @@ func (ec *executionContext) _FunctionTrigger_value(ctx context.Context, field gr
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Value, nil
+		return obj.StartedAt, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
-		if !graphql.HasFieldError(ctx, fc) {
-			ec.Errorf(ctx, "must not be null")
-		}
 		return graphql.Null
 	}
-	res := resTmp.(string)
+	res := resTmp.(*time.Time)
 	fc.Result = res
-	return ec.marshalNString2string(ctx, field.Selections, res)
+	return ec.marshalOTime2ᚖtimeᚐTime(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_FunctionTrigger_value(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_FunctionRun_startedAt(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "FunctionTrigger",
+		Object:     "FunctionRun",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type String does not have child fields")
+			return nil, errors.New("field of type Time does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _FunctionVersion_functionId(ctx context.Context, field graphql.CollectedField, obj *function.FunctionVersion) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_FunctionVersion_functionId(ctx, field)
+func (ec *executionContext) _FunctionRun_finishedAt(ctx context.Context, field graphql.CollectedField, obj *models.FunctionRun) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_FunctionRun_finishedAt(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -4760,38 +5282,35 @@ func (ec *executionContext) _FunctionVersion_functionId(ctx context.Context, fie
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.FunctionID, nil
+		return obj.FinishedAt, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
-		if !graphql.HasFieldError(ctx, fc) {
-			ec.Errorf(ctx, "must not be null")
-		}
 		return graphql.Null
 	}
-	res := resTmp.(string)
+	res := resTmp.(*time.Time)
 	fc.Result = res
-	return ec.marshalNID2string(ctx, field.Selections, res)
+	return ec.marshalOTime2ᚖtimeᚐTime(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_FunctionVersion_functionId(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_FunctionRun_finishedAt(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "FunctionVersion",
+		Object:     "FunctionRun",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type ID does not have child fields")
+			return nil, errors.New("field of type Time does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _FunctionVersion_version(ctx context.Context, field graphql.CollectedField, obj *function.FunctionVersion) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_FunctionVersion_version(ctx, field)
+func (ec *executionContext) _FunctionRun_output(ctx context.Context, field graphql.CollectedField, obj *models.FunctionRun) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_FunctionRun_output(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -4804,38 +5323,35 @@ func (ec *executionContext) _FunctionVersion_version(ctx context.Context, field
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Version, nil
+		return obj.Output, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
-		if !graphql.HasFieldError(ctx, fc) {
-			ec.Errorf(ctx, "must not be null")
-		}
 		return graphql.Null
 	}
-	res := resTmp.(uint)
+	res := resTmp.(*string)
 	fc.Result = res
-	return ec.marshalNUint2uint(ctx, field.Selections, res)
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_FunctionVersion_version(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_FunctionRun_output(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "FunctionVersion",
+		Object:     "FunctionRun",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type Uint does not have child fields")
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _FunctionVersion_config(ctx context.Context, field graphql.CollectedField, obj *function.FunctionVersion) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_FunctionVersion_config(ctx, field)
+func (ec *executionContext) _FunctionRun_history(ctx context.Context, field graphql.CollectedField, obj *models.FunctionRun) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_FunctionRun_history(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -4848,7 +5364,7 @@ func (ec *executionContext) _FunctionVersion_config(ctx context.Context, field g
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Config, nil
+		return ec.resolvers.FunctionRun().History(rctx, obj)
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -4860,26 +5376,60 @@ func (ec *executionContext) _FunctionVersion_config(ctx context.Context, field g
 		}
 		return graphql.Null
 	}
-	res := resTmp.(string)
+	res := resTmp.([]*history_reader.RunHistory)
 	fc.Result = res
-	return ec.marshalNString2string(ctx, field.Selections, res)
+	return ec.marshalNRunHistoryItem2ᚕᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋhistory_readerᚐRunHistoryᚄ(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_FunctionVersion_config(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_FunctionRun_history(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "FunctionVersion",
+		Object:     "FunctionRun",
 		Field:      field,
-		IsMethod:   false,
-		IsResolver: false,
+		IsMethod:   true,
+		IsResolver: true,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type String does not have child fields")
+			switch field.Name {
+			case "attempt":
+				return ec.fieldContext_RunHistoryItem_attempt(ctx, field)
+			case "cancel":
+				return ec.fieldContext_RunHistoryItem_cancel(ctx, field)
+			case "createdAt":
+				return ec.fieldContext_RunHistoryItem_createdAt(ctx, field)
+			case "functionVersion":
+				return ec.fieldContext_RunHistoryItem_functionVersion(ctx, field)
+			case "groupID":
+				return ec.fieldContext_RunHistoryItem_groupID(ctx, field)
+			case "id":
+				return ec.fieldContext_RunHistoryItem_id(ctx, field)
+			case "result":
+				return ec.fieldContext_RunHistoryItem_result(ctx, field)
+			case "sleep":
+				return ec.fieldContext_RunHistoryItem_sleep(ctx, field)
+			case "stepName":
+				return ec.fieldContext_RunHistoryItem_stepName(ctx, field)
+			case "stepType":
+				return ec.fieldContext_RunHistoryItem_stepType(ctx, field)
+			case "type":
+				return ec.fieldContext_RunHistoryItem_type(ctx, field)
+			case "url":
+				return ec.fieldContext_RunHistoryItem_url(ctx, field)
+			case "waitForEvent":
+				return ec.fieldContext_RunHistoryItem_waitForEvent(ctx, field)
+			case "waitResult":
+				return ec.fieldContext_RunHistoryItem_waitResult(ctx, field)
+			case "invokeFunction":
+				return ec.fieldContext_RunHistoryItem_invokeFunction(ctx, field)
+			case "invokeFunctionResult":
+				return ec.fieldContext_RunHistoryItem_invokeFunctionResult(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type RunHistoryItem", field.Name)
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _FunctionVersion_validFrom(ctx context.Context, field graphql.CollectedField, obj *function.FunctionVersion) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_FunctionVersion_validFrom(ctx, field)
+func (ec *executionContext) _FunctionRun_historyItemOutput(ctx context.Context, field graphql.CollectedField, obj *models.FunctionRun) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_FunctionRun_historyItemOutput(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -4892,7 +5442,7 @@ func (ec *executionContext) _FunctionVersion_validFrom(ctx context.Context, fiel
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.ValidFrom, nil
+		return ec.resolvers.FunctionRun().HistoryItemOutput(rctx, obj, fc.Args["id"].(ulid.ULID))
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -4901,26 +5451,37 @@ func (ec *executionContext) _FunctionVersion_validFrom(ctx context.Context, fiel
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*time.Time)
+	res := resTmp.(*string)
 	fc.Result = res
-	return ec.marshalOTime2ᚖtimeᚐTime(ctx, field.Selections, res)
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_FunctionVersion_validFrom(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_FunctionRun_historyItemOutput(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "FunctionVersion",
+		Object:     "FunctionRun",
 		Field:      field,
-		IsMethod:   false,
-		IsResolver: false,
+		IsMethod:   true,
+		IsResolver: true,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type Time does not have child fields")
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_FunctionRun_historyItemOutput_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return
+	}
 	return fc, nil
 }
 
-func (ec *executionContext) _FunctionVersion_validTo(ctx context.Context, field graphql.CollectedField, obj *function.FunctionVersion) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_FunctionVersion_validTo(ctx, field)
+func (ec *executionContext) _FunctionRun_stepLogs(ctx context.Context, field graphql.CollectedField, obj *models.FunctionRun) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_FunctionRun_stepLogs(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -4933,35 +5494,65 @@ func (ec *executionContext) _FunctionVersion_validTo(ctx context.Context, field
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.ValidTo, nil
+		return ec.resolvers.FunctionRun().StepLogs(rctx, obj, fc.Args["stepID"].(*string))
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
 		return graphql.Null
 	}
-	res := resTmp.(*time.Time)
+	res := resTmp.([]*cqrs.StepLog)
 	fc.Result = res
-	return ec.marshalOTime2ᚖtimeᚐTime(ctx, field.Selections, res)
+	return ec.marshalNStepLog2ᚕᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋcqrsᚐStepLogᚄ(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_FunctionVersion_validTo(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_FunctionRun_stepLogs(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "FunctionVersion",
+		Object:     "FunctionRun",
 		Field:      field,
-		IsMethod:   false,
-		IsResolver: false,
+		IsMethod:   true,
+		IsResolver: true,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type Time does not have child fields")
+			switch field.Name {
+			case "runID":
+				return ec.fieldContext_StepLog_runID(ctx, field)
+			case "stepID":
+				return ec.fieldContext_StepLog_stepID(ctx, field)
+			case "attempt":
+				return ec.fieldContext_StepLog_attempt(ctx, field)
+			case "level":
+				return ec.fieldContext_StepLog_level(ctx, field)
+			case "message":
+				return ec.fieldContext_StepLog_message(ctx, field)
+			case "fields":
+				return ec.fieldContext_StepLog_fields(ctx, field)
+			case "timestamp":
+				return ec.fieldContext_StepLog_timestamp(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type StepLog", field.Name)
 		},
 	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_FunctionRun_stepLogs_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return
+	}
 	return fc, nil
 }
 
-func (ec *executionContext) _FunctionVersion_createdAt(ctx context.Context, field graphql.CollectedField, obj *function.FunctionVersion) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_FunctionVersion_createdAt(ctx, field)
+func (ec *executionContext) _FunctionRun_eventID(ctx context.Context, field graphql.CollectedField, obj *models.FunctionRun) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_FunctionRun_eventID(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -4974,7 +5565,7 @@ func (ec *executionContext) _FunctionVersion_createdAt(ctx context.Context, fiel
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.CreatedAt, nil
+		return obj.EventID, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -4986,26 +5577,26 @@ func (ec *executionContext) _FunctionVersion_createdAt(ctx context.Context, fiel
 		}
 		return graphql.Null
 	}
-	res := resTmp.(time.Time)
+	res := resTmp.(string)
 	fc.Result = res
-	return ec.marshalNTime2timeᚐTime(ctx, field.Selections, res)
+	return ec.marshalNID2string(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_FunctionVersion_createdAt(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_FunctionRun_eventID(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "FunctionVersion",
+		Object:     "FunctionRun",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type Time does not have child fields")
+			return nil, errors.New("field of type ID does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _FunctionVersion_updatedAt(ctx context.Context, field graphql.CollectedField, obj *function.FunctionVersion) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_FunctionVersion_updatedAt(ctx, field)
+func (ec *executionContext) _FunctionRun_cron(ctx context.Context, field graphql.CollectedField, obj *models.FunctionRun) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_FunctionRun_cron(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -5018,38 +5609,35 @@ func (ec *executionContext) _FunctionVersion_updatedAt(ctx context.Context, fiel
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.UpdatedAt, nil
+		return obj.Cron, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
-		if !graphql.HasFieldError(ctx, fc) {
-			ec.Errorf(ctx, "must not be null")
-		}
 		return graphql.Null
 	}
-	res := resTmp.(time.Time)
+	res := resTmp.(*string)
 	fc.Result = res
-	return ec.marshalNTime2timeᚐTime(ctx, field.Selections, res)
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_FunctionVersion_updatedAt(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_FunctionRun_cron(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "FunctionVersion",
+		Object:     "FunctionRun",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type Time does not have child fields")
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Mutation_createApp(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_Mutation_createApp(ctx, field)
+func (ec *executionContext) _FunctionRunSearchResult_runs(ctx context.Context, field graphql.CollectedField, obj *models.FunctionRunSearchResult) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_FunctionRunSearchResult_runs(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -5062,7 +5650,7 @@ func (ec *executionContext) _Mutation_createApp(ctx context.Context, field graph
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return ec.resolvers.Mutation().CreateApp(rctx, fc.Args["input"].(models.CreateAppInput))
+		return obj.Runs, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -5074,63 +5662,68 @@ func (ec *executionContext) _Mutation_createApp(ctx context.Context, field graph
 		}
 		return graphql.Null
 	}
-	res := resTmp.(*cqrs.App)
+	res := resTmp.([]*models.FunctionRun)
 	fc.Result = res
-	return ec.marshalNApp2ᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋcqrsᚐApp(ctx, field.Selections, res)
+	return ec.marshalNFunctionRun2ᚕᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋcoreapiᚋgraphᚋmodelsᚐFunctionRunᚄ(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_Mutation_createApp(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_FunctionRunSearchResult_runs(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Mutation",
+		Object:     "FunctionRunSearchResult",
 		Field:      field,
-		IsMethod:   true,
-		IsResolver: true,
+		IsMethod:   false,
+		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
 			switch field.Name {
 			case "id":
-				return ec.fieldContext_App_id(ctx, field)
-			case "name":
-				return ec.fieldContext_App_name(ctx, field)
-			case "sdkLanguage":
-				return ec.fieldContext_App_sdkLanguage(ctx, field)
-			case "sdkVersion":
-				return ec.fieldContext_App_sdkVersion(ctx, field)
-			case "framework":
-				return ec.fieldContext_App_framework(ctx, field)
-			case "url":
-				return ec.fieldContext_App_url(ctx, field)
-			case "checksum":
-				return ec.fieldContext_App_checksum(ctx, field)
-			case "error":
-				return ec.fieldContext_App_error(ctx, field)
-			case "functions":
-				return ec.fieldContext_App_functions(ctx, field)
-			case "connected":
-				return ec.fieldContext_App_connected(ctx, field)
-			case "functionCount":
-				return ec.fieldContext_App_functionCount(ctx, field)
-			case "autodiscovered":
-				return ec.fieldContext_App_autodiscovered(ctx, field)
+				return ec.fieldContext_FunctionRun_id(ctx, field)
+			case "functionID":
+				return ec.fieldContext_FunctionRun_functionID(ctx, field)
+			case "function":
+				return ec.fieldContext_FunctionRun_function(ctx, field)
+			case "workspace":
+				return ec.fieldContext_FunctionRun_workspace(ctx, field)
+			case "event":
+				return ec.fieldContext_FunctionRun_event(ctx, field)
+			case "events":
+				return ec.fieldContext_FunctionRun_events(ctx, field)
+			case "batchID":
+				return ec.fieldContext_FunctionRun_batchID(ctx, field)
+			case "batchCreatedAt":
+				return ec.fieldContext_FunctionRun_batchCreatedAt(ctx, field)
+			case "status":
+				return ec.fieldContext_FunctionRun_status(ctx, field)
+			case "waitingReason":
+				return ec.fieldContext_FunctionRun_waitingReason(ctx, field)
+			case "waitingFor":
+				return ec.fieldContext_FunctionRun_waitingFor(ctx, field)
+			case "pendingSteps":
+				return ec.fieldContext_FunctionRun_pendingSteps(ctx, field)
+			case "startedAt":
+				return ec.fieldContext_FunctionRun_startedAt(ctx, field)
+			case "finishedAt":
+				return ec.fieldContext_FunctionRun_finishedAt(ctx, field)
+			case "output":
+				return ec.fieldContext_FunctionRun_output(ctx, field)
+			case "history":
+				return ec.fieldContext_FunctionRun_history(ctx, field)
+			case "historyItemOutput":
+				return ec.fieldContext_FunctionRun_historyItemOutput(ctx, field)
+			case "stepLogs":
+				return ec.fieldContext_FunctionRun_stepLogs(ctx, field)
+			case "eventID":
+				return ec.fieldContext_FunctionRun_eventID(ctx, field)
+			case "cron":
+				return ec.fieldContext_FunctionRun_cron(ctx, field)
 			}
-			return nil, fmt.Errorf("no field named %q was found under type App", field.Name)
+			return nil, fmt.Errorf("no field named %q was found under type FunctionRun", field.Name)
 		},
 	}
-	defer func() {
-		if r := recover(); r != nil {
-			err = ec.Recover(ctx, r)
-			ec.Error(ctx, err)
-		}
-	}()
-	ctx = graphql.WithFieldContext(ctx, fc)
-	if fc.Args, err = ec.field_Mutation_createApp_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
-		ec.Error(ctx, err)
-		return
-	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Mutation_updateApp(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_Mutation_updateApp(ctx, field)
+func (ec *executionContext) _FunctionRunSearchResult_cursor(ctx context.Context, field graphql.CollectedField, obj *models.FunctionRunSearchResult) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_FunctionRunSearchResult_cursor(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -5143,75 +5736,35 @@ func (ec *executionContext) _Mutation_updateApp(ctx context.Context, field graph
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return ec.resolvers.Mutation().UpdateApp(rctx, fc.Args["input"].(models.UpdateAppInput))
+		return obj.Cursor, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
-		if !graphql.HasFieldError(ctx, fc) {
-			ec.Errorf(ctx, "must not be null")
-		}
 		return graphql.Null
 	}
-	res := resTmp.(*cqrs.App)
+	res := resTmp.(*string)
 	fc.Result = res
-	return ec.marshalNApp2ᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋcqrsᚐApp(ctx, field.Selections, res)
+	return ec.marshalOID2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_Mutation_updateApp(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_FunctionRunSearchResult_cursor(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Mutation",
+		Object:     "FunctionRunSearchResult",
 		Field:      field,
-		IsMethod:   true,
-		IsResolver: true,
+		IsMethod:   false,
+		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "id":
-				return ec.fieldContext_App_id(ctx, field)
-			case "name":
-				return ec.fieldContext_App_name(ctx, field)
-			case "sdkLanguage":
-				return ec.fieldContext_App_sdkLanguage(ctx, field)
-			case "sdkVersion":
-				return ec.fieldContext_App_sdkVersion(ctx, field)
-			case "framework":
-				return ec.fieldContext_App_framework(ctx, field)
-			case "url":
-				return ec.fieldContext_App_url(ctx, field)
-			case "checksum":
-				return ec.fieldContext_App_checksum(ctx, field)
-			case "error":
-				return ec.fieldContext_App_error(ctx, field)
-			case "functions":
-				return ec.fieldContext_App_functions(ctx, field)
-			case "connected":
-				return ec.fieldContext_App_connected(ctx, field)
-			case "functionCount":
-				return ec.fieldContext_App_functionCount(ctx, field)
-			case "autodiscovered":
-				return ec.fieldContext_App_autodiscovered(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type App", field.Name)
+			return nil, errors.New("field of type ID does not have child fields")
 		},
 	}
-	defer func() {
-		if r := recover(); r != nil {
-			err = ec.Recover(ctx, r)
-			ec.Error(ctx, err)
-		}
-	}()
-	ctx = graphql.WithFieldContext(ctx, fc)
-	if fc.Args, err = ec.field_Mutation_updateApp_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
-		ec.Error(ctx, err)
-		return
-	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Mutation_deleteApp(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_Mutation_deleteApp(ctx, field)
+func (ec *executionContext) _FunctionTrigger_type(ctx context.Context, field graphql.CollectedField, obj *models.FunctionTrigger) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_FunctionTrigger_type(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -5224,7 +5777,7 @@ func (ec *executionContext) _Mutation_deleteApp(ctx context.Context, field graph
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return ec.resolvers.Mutation().DeleteApp(rctx, fc.Args["id"].(string))
+		return obj.Type, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -5236,37 +5789,26 @@ func (ec *executionContext) _Mutation_deleteApp(ctx context.Context, field graph
 		}
 		return graphql.Null
 	}
-	res := resTmp.(string)
+	res := resTmp.(models.FunctionTriggerTypes)
 	fc.Result = res
-	return ec.marshalNString2string(ctx, field.Selections, res)
+	return ec.marshalNFunctionTriggerTypes2githubᚗcomᚋinngestᚋinngestᚋpkgᚋcoreapiᚋgraphᚋmodelsᚐFunctionTriggerTypes(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_Mutation_deleteApp(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_FunctionTrigger_type(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Mutation",
+		Object:     "FunctionTrigger",
 		Field:      field,
-		IsMethod:   true,
-		IsResolver: true,
+		IsMethod:   false,
+		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type String does not have child fields")
+			return nil, errors.New("field of type FunctionTriggerTypes does not have child fields")
 		},
 	}
-	defer func() {
-		if r := recover(); r != nil {
-			err = ec.Recover(ctx, r)
-			ec.Error(ctx, err)
-		}
-	}()
-	ctx = graphql.WithFieldContext(ctx, fc)
-	if fc.Args, err = ec.field_Mutation_deleteApp_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
-		ec.Error(ctx, err)
-		return
-	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Mutation_deleteAppByName(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_Mutation_deleteAppByName(ctx, field)
+func (ec *executionContext) _FunctionTrigger_value(ctx context.Context, field graphql.CollectedField, obj *models.FunctionTrigger) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_FunctionTrigger_value(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -5279,7 +5821,7 @@ func (ec *executionContext) _Mutation_deleteAppByName(ctx context.Context, field
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return ec.resolvers.Mutation().DeleteAppByName(rctx, fc.Args["name"].(string))
+		return obj.Value, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -5291,37 +5833,26 @@ func (ec *executionContext) _Mutation_deleteAppByName(ctx context.Context, field
 		}
 		return graphql.Null
 	}
-	res := resTmp.(bool)
+	res := resTmp.(string)
 	fc.Result = res
-	return ec.marshalNBoolean2bool(ctx, field.Selections, res)
+	return ec.marshalNString2string(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_Mutation_deleteAppByName(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_FunctionTrigger_value(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Mutation",
+		Object:     "FunctionTrigger",
 		Field:      field,
-		IsMethod:   true,
-		IsResolver: true,
+		IsMethod:   false,
+		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type Boolean does not have child fields")
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
-	defer func() {
-		if r := recover(); r != nil {
-			err = ec.Recover(ctx, r)
-			ec.Error(ctx, err)
-		}
-	}()
-	ctx = graphql.WithFieldContext(ctx, fc)
-	if fc.Args, err = ec.field_Mutation_deleteAppByName_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
-		ec.Error(ctx, err)
-		return
-	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Mutation_invokeFunction(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_Mutation_invokeFunction(ctx, field)
+func (ec *executionContext) _FunctionVersion_functionId(ctx context.Context, field graphql.CollectedField, obj *function.FunctionVersion) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_FunctionVersion_functionId(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -5334,46 +5865,38 @@ func (ec *executionContext) _Mutation_invokeFunction(ctx context.Context, field
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return ec.resolvers.Mutation().InvokeFunction(rctx, fc.Args["data"].(map[string]interface{}), fc.Args["functionSlug"].(string))
+		return obj.FunctionID, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
 		return graphql.Null
 	}
-	res := resTmp.(*bool)
+	res := resTmp.(string)
 	fc.Result = res
-	return ec.marshalOBoolean2ᚖbool(ctx, field.Selections, res)
+	return ec.marshalNID2string(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_Mutation_invokeFunction(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_FunctionVersion_functionId(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Mutation",
+		Object:     "FunctionVersion",
 		Field:      field,
-		IsMethod:   true,
-		IsResolver: true,
+		IsMethod:   false,
+		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type Boolean does not have child fields")
+			return nil, errors.New("field of type ID does not have child fields")
 		},
 	}
-	defer func() {
-		if r := recover(); r != nil {
-			err = ec.Recover(ctx, r)
-			ec.Error(ctx, err)
-		}
-	}()
-	ctx = graphql.WithFieldContext(ctx, fc)
-	if fc.Args, err = ec.field_Mutation_invokeFunction_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
-		ec.Error(ctx, err)
-		return
-	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Mutation_cancelRun(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_Mutation_cancelRun(ctx, field)
+func (ec *executionContext) _FunctionVersion_version(ctx context.Context, field graphql.CollectedField, obj *function.FunctionVersion) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_FunctionVersion_version(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -5386,7 +5909,7 @@ func (ec *executionContext) _Mutation_cancelRun(ctx context.Context, field graph
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return ec.resolvers.Mutation().CancelRun(rctx, fc.Args["runID"].(ulid.ULID))
+		return obj.Version, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -5398,17 +5921,1381 @@ func (ec *executionContext) _Mutation_cancelRun(ctx context.Context, field graph
 		}
 		return graphql.Null
 	}
-	res := resTmp.(*models.FunctionRun)
+	res := resTmp.(uint)
 	fc.Result = res
-	return ec.marshalNFunctionRun2ᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋcoreapiᚋgraphᚋmodelsᚐFunctionRun(ctx, field.Selections, res)
+	return ec.marshalNUint2uint(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_Mutation_cancelRun(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_FunctionVersion_version(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Mutation",
+		Object:     "FunctionVersion",
 		Field:      field,
-		IsMethod:   true,
-		IsResolver: true,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Uint does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _FunctionVersion_config(ctx context.Context, field graphql.CollectedField, obj *function.FunctionVersion) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_FunctionVersion_config(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Config, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(string)
+	fc.Result = res
+	return ec.marshalNString2string(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_FunctionVersion_config(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "FunctionVersion",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _FunctionVersion_validFrom(ctx context.Context, field graphql.CollectedField, obj *function.FunctionVersion) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_FunctionVersion_validFrom(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.ValidFrom, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*time.Time)
+	fc.Result = res
+	return ec.marshalOTime2ᚖtimeᚐTime(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_FunctionVersion_validFrom(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "FunctionVersion",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Time does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _FunctionVersion_validTo(ctx context.Context, field graphql.CollectedField, obj *function.FunctionVersion) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_FunctionVersion_validTo(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.ValidTo, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*time.Time)
+	fc.Result = res
+	return ec.marshalOTime2ᚖtimeᚐTime(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_FunctionVersion_validTo(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "FunctionVersion",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Time does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _FunctionVersion_createdAt(ctx context.Context, field graphql.CollectedField, obj *function.FunctionVersion) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_FunctionVersion_createdAt(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.CreatedAt, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(time.Time)
+	fc.Result = res
+	return ec.marshalNTime2timeᚐTime(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_FunctionVersion_createdAt(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "FunctionVersion",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Time does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _FunctionVersion_updatedAt(ctx context.Context, field graphql.CollectedField, obj *function.FunctionVersion) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_FunctionVersion_updatedAt(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.UpdatedAt, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(time.Time)
+	fc.Result = res
+	return ec.marshalNTime2timeᚐTime(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_FunctionVersion_updatedAt(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "FunctionVersion",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Time does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Mutation_createApp(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Mutation_createApp(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.Mutation().CreateApp(rctx, fc.Args["input"].(models.CreateAppInput))
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(*cqrs.App)
+	fc.Result = res
+	return ec.marshalNApp2ᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋcqrsᚐApp(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Mutation_createApp(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Mutation",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "id":
+				return ec.fieldContext_App_id(ctx, field)
+			case "name":
+				return ec.fieldContext_App_name(ctx, field)
+			case "sdkLanguage":
+				return ec.fieldContext_App_sdkLanguage(ctx, field)
+			case "sdkVersion":
+				return ec.fieldContext_App_sdkVersion(ctx, field)
+			case "framework":
+				return ec.fieldContext_App_framework(ctx, field)
+			case "url":
+				return ec.fieldContext_App_url(ctx, field)
+			case "checksum":
+				return ec.fieldContext_App_checksum(ctx, field)
+			case "error":
+				return ec.fieldContext_App_error(ctx, field)
+			case "functions":
+				return ec.fieldContext_App_functions(ctx, field)
+			case "connected":
+				return ec.fieldContext_App_connected(ctx, field)
+			case "functionCount":
+				return ec.fieldContext_App_functionCount(ctx, field)
+			case "autodiscovered":
+				return ec.fieldContext_App_autodiscovered(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type App", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Mutation_createApp_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Mutation_updateApp(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Mutation_updateApp(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.Mutation().UpdateApp(rctx, fc.Args["input"].(models.UpdateAppInput))
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(*cqrs.App)
+	fc.Result = res
+	return ec.marshalNApp2ᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋcqrsᚐApp(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Mutation_updateApp(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Mutation",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "id":
+				return ec.fieldContext_App_id(ctx, field)
+			case "name":
+				return ec.fieldContext_App_name(ctx, field)
+			case "sdkLanguage":
+				return ec.fieldContext_App_sdkLanguage(ctx, field)
+			case "sdkVersion":
+				return ec.fieldContext_App_sdkVersion(ctx, field)
+			case "framework":
+				return ec.fieldContext_App_framework(ctx, field)
+			case "url":
+				return ec.fieldContext_App_url(ctx, field)
+			case "checksum":
+				return ec.fieldContext_App_checksum(ctx, field)
+			case "error":
+				return ec.fieldContext_App_error(ctx, field)
+			case "functions":
+				return ec.fieldContext_App_functions(ctx, field)
+			case "connected":
+				return ec.fieldContext_App_connected(ctx, field)
+			case "functionCount":
+				return ec.fieldContext_App_functionCount(ctx, field)
+			case "autodiscovered":
+				return ec.fieldContext_App_autodiscovered(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type App", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Mutation_updateApp_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Mutation_deleteApp(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Mutation_deleteApp(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.Mutation().DeleteApp(rctx, fc.Args["id"].(string))
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(string)
+	fc.Result = res
+	return ec.marshalNString2string(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Mutation_deleteApp(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Mutation",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Mutation_deleteApp_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Mutation_deleteAppByName(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Mutation_deleteAppByName(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.Mutation().DeleteAppByName(rctx, fc.Args["name"].(string))
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(bool)
+	fc.Result = res
+	return ec.marshalNBoolean2bool(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Mutation_deleteAppByName(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Mutation",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Boolean does not have child fields")
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Mutation_deleteAppByName_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Mutation_invokeFunction(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Mutation_invokeFunction(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.Mutation().InvokeFunction(rctx, fc.Args["data"].(map[string]interface{}), fc.Args["functionSlug"].(string))
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*bool)
+	fc.Result = res
+	return ec.marshalOBoolean2ᚖbool(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Mutation_invokeFunction(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Mutation",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Boolean does not have child fields")
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Mutation_invokeFunction_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Mutation_cancelRun(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Mutation_cancelRun(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.Mutation().CancelRun(rctx, fc.Args["runID"].(ulid.ULID))
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(*models.FunctionRun)
+	fc.Result = res
+	return ec.marshalNFunctionRun2ᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋcoreapiᚋgraphᚋmodelsᚐFunctionRun(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Mutation_cancelRun(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Mutation",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "id":
+				return ec.fieldContext_FunctionRun_id(ctx, field)
+			case "functionID":
+				return ec.fieldContext_FunctionRun_functionID(ctx, field)
+			case "function":
+				return ec.fieldContext_FunctionRun_function(ctx, field)
+			case "workspace":
+				return ec.fieldContext_FunctionRun_workspace(ctx, field)
+			case "event":
+				return ec.fieldContext_FunctionRun_event(ctx, field)
+			case "events":
+				return ec.fieldContext_FunctionRun_events(ctx, field)
+			case "batchID":
+				return ec.fieldContext_FunctionRun_batchID(ctx, field)
+			case "batchCreatedAt":
+				return ec.fieldContext_FunctionRun_batchCreatedAt(ctx, field)
+			case "status":
+				return ec.fieldContext_FunctionRun_status(ctx, field)
+			case "waitingReason":
+				return ec.fieldContext_FunctionRun_waitingReason(ctx, field)
+			case "waitingFor":
+				return ec.fieldContext_FunctionRun_waitingFor(ctx, field)
+			case "pendingSteps":
+				return ec.fieldContext_FunctionRun_pendingSteps(ctx, field)
+			case "startedAt":
+				return ec.fieldContext_FunctionRun_startedAt(ctx, field)
+			case "finishedAt":
+				return ec.fieldContext_FunctionRun_finishedAt(ctx, field)
+			case "output":
+				return ec.fieldContext_FunctionRun_output(ctx, field)
+			case "history":
+				return ec.fieldContext_FunctionRun_history(ctx, field)
+			case "historyItemOutput":
+				return ec.fieldContext_FunctionRun_historyItemOutput(ctx, field)
+			case "stepLogs":
+				return ec.fieldContext_FunctionRun_stepLogs(ctx, field)
+			case "eventID":
+				return ec.fieldContext_FunctionRun_eventID(ctx, field)
+			case "cron":
+				return ec.fieldContext_FunctionRun_cron(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type FunctionRun", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Mutation_cancelRun_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Mutation_rerun(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Mutation_rerun(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.Mutation().Rerun(rctx, fc.Args["runID"].(ulid.ULID))
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(ulid.ULID)
+	fc.Result = res
+	return ec.marshalNULID2githubᚗcomᚋoklogᚋulidᚋv2ᚐULID(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Mutation_rerun(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Mutation",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type ULID does not have child fields")
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Mutation_rerun_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Mutation_requeueStep(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Mutation_requeueStep(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.Mutation().RequeueStep(rctx, fc.Args["runID"].(ulid.ULID), fc.Args["stepID"].(string))
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(bool)
+	fc.Result = res
+	return ec.marshalNBoolean2bool(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Mutation_requeueStep(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Mutation",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Boolean does not have child fields")
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Mutation_requeueStep_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Mutation_boostRunPriority(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Mutation_boostRunPriority(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.Mutation().BoostRunPriority(rctx, fc.Args["runID"].(ulid.ULID), fc.Args["seconds"].(int))
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(int)
+	fc.Result = res
+	return ec.marshalNInt2int(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Mutation_boostRunPriority(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Mutation",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Int does not have child fields")
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Mutation_boostRunPriority_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Mutation_updateFunctionThrottle(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Mutation_updateFunctionThrottle(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.Mutation().UpdateFunctionThrottle(rctx, fc.Args["functionID"].(string), fc.Args["throttle"].(*models.FunctionThrottleInput))
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(bool)
+	fc.Result = res
+	return ec.marshalNBoolean2bool(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Mutation_updateFunctionThrottle(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Mutation",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Boolean does not have child fields")
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Mutation_updateFunctionThrottle_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Mutation_pauseFunctionQueue(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Mutation_pauseFunctionQueue(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.Mutation().PauseFunctionQueue(rctx, fc.Args["functionID"].(string))
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(bool)
+	fc.Result = res
+	return ec.marshalNBoolean2bool(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Mutation_pauseFunctionQueue(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Mutation",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Boolean does not have child fields")
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Mutation_pauseFunctionQueue_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Mutation_resumeFunctionQueue(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Mutation_resumeFunctionQueue(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.Mutation().ResumeFunctionQueue(rctx, fc.Args["functionID"].(string))
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(bool)
+	fc.Result = res
+	return ec.marshalNBoolean2bool(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Mutation_resumeFunctionQueue(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Mutation",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Boolean does not have child fields")
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Mutation_resumeFunctionQueue_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Query_apps(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Query_apps(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.Query().Apps(rctx)
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.([]*cqrs.App)
+	fc.Result = res
+	return ec.marshalNApp2ᚕᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋcqrsᚐAppᚄ(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Query_apps(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Query",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "id":
+				return ec.fieldContext_App_id(ctx, field)
+			case "name":
+				return ec.fieldContext_App_name(ctx, field)
+			case "sdkLanguage":
+				return ec.fieldContext_App_sdkLanguage(ctx, field)
+			case "sdkVersion":
+				return ec.fieldContext_App_sdkVersion(ctx, field)
+			case "framework":
+				return ec.fieldContext_App_framework(ctx, field)
+			case "url":
+				return ec.fieldContext_App_url(ctx, field)
+			case "checksum":
+				return ec.fieldContext_App_checksum(ctx, field)
+			case "error":
+				return ec.fieldContext_App_error(ctx, field)
+			case "functions":
+				return ec.fieldContext_App_functions(ctx, field)
+			case "connected":
+				return ec.fieldContext_App_connected(ctx, field)
+			case "functionCount":
+				return ec.fieldContext_App_functionCount(ctx, field)
+			case "autodiscovered":
+				return ec.fieldContext_App_autodiscovered(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type App", field.Name)
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Query_stream(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Query_stream(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.Query().Stream(rctx, fc.Args["query"].(models.StreamQuery))
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.([]*models.StreamItem)
+	fc.Result = res
+	return ec.marshalNStreamItem2ᚕᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋcoreapiᚋgraphᚋmodelsᚐStreamItemᚄ(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Query_stream(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Query",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "id":
+				return ec.fieldContext_StreamItem_id(ctx, field)
+			case "trigger":
+				return ec.fieldContext_StreamItem_trigger(ctx, field)
+			case "type":
+				return ec.fieldContext_StreamItem_type(ctx, field)
+			case "createdAt":
+				return ec.fieldContext_StreamItem_createdAt(ctx, field)
+			case "runs":
+				return ec.fieldContext_StreamItem_runs(ctx, field)
+			case "inBatch":
+				return ec.fieldContext_StreamItem_inBatch(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type StreamItem", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Query_stream_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Query_event(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Query_event(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.Query().Event(rctx, fc.Args["query"].(models.EventQuery))
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*models.Event)
+	fc.Result = res
+	return ec.marshalOEvent2ᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋcoreapiᚋgraphᚋmodelsᚐEvent(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Query_event(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Query",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "id":
+				return ec.fieldContext_Event_id(ctx, field)
+			case "externalID":
+				return ec.fieldContext_Event_externalID(ctx, field)
+			case "workspace":
+				return ec.fieldContext_Event_workspace(ctx, field)
+			case "name":
+				return ec.fieldContext_Event_name(ctx, field)
+			case "createdAt":
+				return ec.fieldContext_Event_createdAt(ctx, field)
+			case "payload":
+				return ec.fieldContext_Event_payload(ctx, field)
+			case "schema":
+				return ec.fieldContext_Event_schema(ctx, field)
+			case "status":
+				return ec.fieldContext_Event_status(ctx, field)
+			case "pendingRuns":
+				return ec.fieldContext_Event_pendingRuns(ctx, field)
+			case "totalRuns":
+				return ec.fieldContext_Event_totalRuns(ctx, field)
+			case "raw":
+				return ec.fieldContext_Event_raw(ctx, field)
+			case "functionRuns":
+				return ec.fieldContext_Event_functionRuns(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type Event", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Query_event_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Query_events(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Query_events(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.Query().Events(rctx, fc.Args["query"].(models.EventsQuery))
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.([]*models.Event)
+	fc.Result = res
+	return ec.marshalOEvent2ᚕᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋcoreapiᚋgraphᚋmodelsᚐEventᚄ(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Query_events(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Query",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "id":
+				return ec.fieldContext_Event_id(ctx, field)
+			case "externalID":
+				return ec.fieldContext_Event_externalID(ctx, field)
+			case "workspace":
+				return ec.fieldContext_Event_workspace(ctx, field)
+			case "name":
+				return ec.fieldContext_Event_name(ctx, field)
+			case "createdAt":
+				return ec.fieldContext_Event_createdAt(ctx, field)
+			case "payload":
+				return ec.fieldContext_Event_payload(ctx, field)
+			case "schema":
+				return ec.fieldContext_Event_schema(ctx, field)
+			case "status":
+				return ec.fieldContext_Event_status(ctx, field)
+			case "pendingRuns":
+				return ec.fieldContext_Event_pendingRuns(ctx, field)
+			case "totalRuns":
+				return ec.fieldContext_Event_totalRuns(ctx, field)
+			case "raw":
+				return ec.fieldContext_Event_raw(ctx, field)
+			case "functionRuns":
+				return ec.fieldContext_Event_functionRuns(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type Event", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Query_events_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Query_functions(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Query_functions(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.Query().Functions(rctx)
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.([]*models.Function)
+	fc.Result = res
+	return ec.marshalOFunction2ᚕᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋcoreapiᚋgraphᚋmodelsᚐFunctionᚄ(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Query_functions(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Query",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "id":
+				return ec.fieldContext_Function_id(ctx, field)
+			case "name":
+				return ec.fieldContext_Function_name(ctx, field)
+			case "slug":
+				return ec.fieldContext_Function_slug(ctx, field)
+			case "config":
+				return ec.fieldContext_Function_config(ctx, field)
+			case "concurrency":
+				return ec.fieldContext_Function_concurrency(ctx, field)
+			case "triggers":
+				return ec.fieldContext_Function_triggers(ctx, field)
+			case "url":
+				return ec.fieldContext_Function_url(ctx, field)
+			case "appID":
+				return ec.fieldContext_Function_appID(ctx, field)
+			case "app":
+				return ec.fieldContext_Function_app(ctx, field)
+			case "queue":
+				return ec.fieldContext_Function_queue(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type Function", field.Name)
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Query_functionRun(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Query_functionRun(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.Query().FunctionRun(rctx, fc.Args["query"].(models.FunctionRunQuery))
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*models.FunctionRun)
+	fc.Result = res
+	return ec.marshalOFunctionRun2ᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋcoreapiᚋgraphᚋmodelsᚐFunctionRun(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Query_functionRun(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Query",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
 			switch field.Name {
 			case "id":
@@ -5429,6 +7316,8 @@ func (ec *executionContext) fieldContext_Mutation_cancelRun(ctx context.Context,
 				return ec.fieldContext_FunctionRun_batchCreatedAt(ctx, field)
 			case "status":
 				return ec.fieldContext_FunctionRun_status(ctx, field)
+			case "waitingReason":
+				return ec.fieldContext_FunctionRun_waitingReason(ctx, field)
 			case "waitingFor":
 				return ec.fieldContext_FunctionRun_waitingFor(ctx, field)
 			case "pendingSteps":
@@ -5443,6 +7332,8 @@ func (ec *executionContext) fieldContext_Mutation_cancelRun(ctx context.Context,
 				return ec.fieldContext_FunctionRun_history(ctx, field)
 			case "historyItemOutput":
 				return ec.fieldContext_FunctionRun_historyItemOutput(ctx, field)
+			case "stepLogs":
+				return ec.fieldContext_FunctionRun_stepLogs(ctx, field)
 			case "eventID":
 				return ec.fieldContext_FunctionRun_eventID(ctx, field)
 			case "cron":
@@ -5458,15 +7349,15 @@ func (ec *executionContext) fieldContext_Mutation_cancelRun(ctx context.Context,
 		}
 	}()
 	ctx = graphql.WithFieldContext(ctx, fc)
-	if fc.Args, err = ec.field_Mutation_cancelRun_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+	if fc.Args, err = ec.field_Query_functionRun_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
 		ec.Error(ctx, err)
 		return
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Mutation_rerun(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_Mutation_rerun(ctx, field)
+func (ec *executionContext) _Query_searchFunctionRuns(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Query_searchFunctionRuns(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -5479,7 +7370,7 @@ func (ec *executionContext) _Mutation_rerun(ctx context.Context, field graphql.C
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return ec.resolvers.Mutation().Rerun(rctx, fc.Args["runID"].(ulid.ULID))
+		return ec.resolvers.Query().SearchFunctionRuns(rctx, fc.Args["query"].(models.SearchFunctionRunsQuery))
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -5491,19 +7382,25 @@ func (ec *executionContext) _Mutation_rerun(ctx context.Context, field graphql.C
 		}
 		return graphql.Null
 	}
-	res := resTmp.(ulid.ULID)
+	res := resTmp.(*models.FunctionRunSearchResult)
 	fc.Result = res
-	return ec.marshalNULID2githubᚗcomᚋoklogᚋulidᚋv2ᚐULID(ctx, field.Selections, res)
+	return ec.marshalNFunctionRunSearchResult2ᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋcoreapiᚋgraphᚋmodelsᚐFunctionRunSearchResult(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_Mutation_rerun(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Query_searchFunctionRuns(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Mutation",
+		Object:     "Query",
 		Field:      field,
 		IsMethod:   true,
 		IsResolver: true,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type ULID does not have child fields")
+			switch field.Name {
+			case "runs":
+				return ec.fieldContext_FunctionRunSearchResult_runs(ctx, field)
+			case "cursor":
+				return ec.fieldContext_FunctionRunSearchResult_cursor(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type FunctionRunSearchResult", field.Name)
 		},
 	}
 	defer func() {
@@ -5513,15 +7410,15 @@ func (ec *executionContext) fieldContext_Mutation_rerun(ctx context.Context, fie
 		}
 	}()
 	ctx = graphql.WithFieldContext(ctx, fc)
-	if fc.Args, err = ec.field_Mutation_rerun_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+	if fc.Args, err = ec.field_Query_searchFunctionRuns_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
 		ec.Error(ctx, err)
 		return
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Query_apps(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_Query_apps(ctx, field)
+func (ec *executionContext) _Query___type(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Query___type(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -5534,64 +7431,68 @@ func (ec *executionContext) _Query_apps(ctx context.Context, field graphql.Colle
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return ec.resolvers.Query().Apps(rctx)
+		return ec.introspectType(fc.Args["name"].(string))
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
-		if !graphql.HasFieldError(ctx, fc) {
-			ec.Errorf(ctx, "must not be null")
-		}
 		return graphql.Null
 	}
-	res := resTmp.([]*cqrs.App)
+	res := resTmp.(*introspection.Type)
 	fc.Result = res
-	return ec.marshalNApp2ᚕᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋcqrsᚐAppᚄ(ctx, field.Selections, res)
+	return ec.marshalO__Type2ᚖgithubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚋintrospectionᚐType(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_Query_apps(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Query___type(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
 		Object:     "Query",
 		Field:      field,
 		IsMethod:   true,
-		IsResolver: true,
+		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
 			switch field.Name {
-			case "id":
-				return ec.fieldContext_App_id(ctx, field)
+			case "kind":
+				return ec.fieldContext___Type_kind(ctx, field)
 			case "name":
-				return ec.fieldContext_App_name(ctx, field)
-			case "sdkLanguage":
-				return ec.fieldContext_App_sdkLanguage(ctx, field)
-			case "sdkVersion":
-				return ec.fieldContext_App_sdkVersion(ctx, field)
-			case "framework":
-				return ec.fieldContext_App_framework(ctx, field)
-			case "url":
-				return ec.fieldContext_App_url(ctx, field)
-			case "checksum":
-				return ec.fieldContext_App_checksum(ctx, field)
-			case "error":
-				return ec.fieldContext_App_error(ctx, field)
-			case "functions":
-				return ec.fieldContext_App_functions(ctx, field)
-			case "connected":
-				return ec.fieldContext_App_connected(ctx, field)
-			case "functionCount":
-				return ec.fieldContext_App_functionCount(ctx, field)
-			case "autodiscovered":
-				return ec.fieldContext_App_autodiscovered(ctx, field)
+				return ec.fieldContext___Type_name(ctx, field)
+			case "description":
+				return ec.fieldContext___Type_description(ctx, field)
+			case "fields":
+				return ec.fieldContext___Type_fields(ctx, field)
+			case "interfaces":
+				return ec.fieldContext___Type_interfaces(ctx, field)
+			case "possibleTypes":
+				return ec.fieldContext___Type_possibleTypes(ctx, field)
+			case "enumValues":
+				return ec.fieldContext___Type_enumValues(ctx, field)
+			case "inputFields":
+				return ec.fieldContext___Type_inputFields(ctx, field)
+			case "ofType":
+				return ec.fieldContext___Type_ofType(ctx, field)
+			case "specifiedByURL":
+				return ec.fieldContext___Type_specifiedByURL(ctx, field)
 			}
-			return nil, fmt.Errorf("no field named %q was found under type App", field.Name)
+			return nil, fmt.Errorf("no field named %q was found under type __Type", field.Name)
 		},
 	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Query___type_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return
+	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Query_stream(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_Query_stream(ctx, field)
+func (ec *executionContext) _Query___schema(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Query___schema(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -5604,63 +7505,49 @@ func (ec *executionContext) _Query_stream(ctx context.Context, field graphql.Col
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return ec.resolvers.Query().Stream(rctx, fc.Args["query"].(models.StreamQuery))
+		return ec.introspectSchema()
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
-		if !graphql.HasFieldError(ctx, fc) {
-			ec.Errorf(ctx, "must not be null")
-		}
 		return graphql.Null
 	}
-	res := resTmp.([]*models.StreamItem)
+	res := resTmp.(*introspection.Schema)
 	fc.Result = res
-	return ec.marshalNStreamItem2ᚕᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋcoreapiᚋgraphᚋmodelsᚐStreamItemᚄ(ctx, field.Selections, res)
+	return ec.marshalO__Schema2ᚖgithubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚋintrospectionᚐSchema(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_Query_stream(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Query___schema(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
 		Object:     "Query",
 		Field:      field,
 		IsMethod:   true,
-		IsResolver: true,
+		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
 			switch field.Name {
-			case "id":
-				return ec.fieldContext_StreamItem_id(ctx, field)
-			case "trigger":
-				return ec.fieldContext_StreamItem_trigger(ctx, field)
-			case "type":
-				return ec.fieldContext_StreamItem_type(ctx, field)
-			case "createdAt":
-				return ec.fieldContext_StreamItem_createdAt(ctx, field)
-			case "runs":
-				return ec.fieldContext_StreamItem_runs(ctx, field)
-			case "inBatch":
-				return ec.fieldContext_StreamItem_inBatch(ctx, field)
+			case "description":
+				return ec.fieldContext___Schema_description(ctx, field)
+			case "types":
+				return ec.fieldContext___Schema_types(ctx, field)
+			case "queryType":
+				return ec.fieldContext___Schema_queryType(ctx, field)
+			case "mutationType":
+				return ec.fieldContext___Schema_mutationType(ctx, field)
+			case "subscriptionType":
+				return ec.fieldContext___Schema_subscriptionType(ctx, field)
+			case "directives":
+				return ec.fieldContext___Schema_directives(ctx, field)
 			}
-			return nil, fmt.Errorf("no field named %q was found under type StreamItem", field.Name)
+			return nil, fmt.Errorf("no field named %q was found under type __Schema", field.Name)
 		},
 	}
-	defer func() {
-		if r := recover(); r != nil {
-			err = ec.Recover(ctx, r)
-			ec.Error(ctx, err)
-		}
-	}()
-	ctx = graphql.WithFieldContext(ctx, fc)
-	if fc.Args, err = ec.field_Query_stream_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
-		ec.Error(ctx, err)
-		return
-	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Query_event(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_Query_event(ctx, field)
+func (ec *executionContext) _RunHistoryCancel_eventID(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistoryCancel) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_RunHistoryCancel_eventID(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -5673,7 +7560,7 @@ func (ec *executionContext) _Query_event(ctx context.Context, field graphql.Coll
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return ec.resolvers.Query().Event(rctx, fc.Args["query"].(models.EventQuery))
+		return obj.EventID, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -5682,63 +7569,26 @@ func (ec *executionContext) _Query_event(ctx context.Context, field graphql.Coll
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*models.Event)
+	res := resTmp.(*ulid.ULID)
 	fc.Result = res
-	return ec.marshalOEvent2ᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋcoreapiᚋgraphᚋmodelsᚐEvent(ctx, field.Selections, res)
+	return ec.marshalOULID2ᚖgithubᚗcomᚋoklogᚋulidᚋv2ᚐULID(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_Query_event(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_RunHistoryCancel_eventID(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Query",
+		Object:     "RunHistoryCancel",
 		Field:      field,
-		IsMethod:   true,
-		IsResolver: true,
+		IsMethod:   false,
+		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "id":
-				return ec.fieldContext_Event_id(ctx, field)
-			case "externalID":
-				return ec.fieldContext_Event_externalID(ctx, field)
-			case "workspace":
-				return ec.fieldContext_Event_workspace(ctx, field)
-			case "name":
-				return ec.fieldContext_Event_name(ctx, field)
-			case "createdAt":
-				return ec.fieldContext_Event_createdAt(ctx, field)
-			case "payload":
-				return ec.fieldContext_Event_payload(ctx, field)
-			case "schema":
-				return ec.fieldContext_Event_schema(ctx, field)
-			case "status":
-				return ec.fieldContext_Event_status(ctx, field)
-			case "pendingRuns":
-				return ec.fieldContext_Event_pendingRuns(ctx, field)
-			case "totalRuns":
-				return ec.fieldContext_Event_totalRuns(ctx, field)
-			case "raw":
-				return ec.fieldContext_Event_raw(ctx, field)
-			case "functionRuns":
-				return ec.fieldContext_Event_functionRuns(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type Event", field.Name)
+			return nil, errors.New("field of type ULID does not have child fields")
 		},
 	}
-	defer func() {
-		if r := recover(); r != nil {
-			err = ec.Recover(ctx, r)
-			ec.Error(ctx, err)
-		}
-	}()
-	ctx = graphql.WithFieldContext(ctx, fc)
-	if fc.Args, err = ec.field_Query_event_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
-		ec.Error(ctx, err)
-		return
-	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Query_events(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_Query_events(ctx, field)
+func (ec *executionContext) _RunHistoryCancel_expression(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistoryCancel) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_RunHistoryCancel_expression(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -5751,7 +7601,7 @@ func (ec *executionContext) _Query_events(ctx context.Context, field graphql.Col
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return ec.resolvers.Query().Events(rctx, fc.Args["query"].(models.EventsQuery))
+		return obj.Expression, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -5760,63 +7610,67 @@ func (ec *executionContext) _Query_events(ctx context.Context, field graphql.Col
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.([]*models.Event)
+	res := resTmp.(*string)
 	fc.Result = res
-	return ec.marshalOEvent2ᚕᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋcoreapiᚋgraphᚋmodelsᚐEventᚄ(ctx, field.Selections, res)
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_Query_events(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_RunHistoryCancel_expression(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Query",
+		Object:     "RunHistoryCancel",
 		Field:      field,
-		IsMethod:   true,
-		IsResolver: true,
+		IsMethod:   false,
+		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "id":
-				return ec.fieldContext_Event_id(ctx, field)
-			case "externalID":
-				return ec.fieldContext_Event_externalID(ctx, field)
-			case "workspace":
-				return ec.fieldContext_Event_workspace(ctx, field)
-			case "name":
-				return ec.fieldContext_Event_name(ctx, field)
-			case "createdAt":
-				return ec.fieldContext_Event_createdAt(ctx, field)
-			case "payload":
-				return ec.fieldContext_Event_payload(ctx, field)
-			case "schema":
-				return ec.fieldContext_Event_schema(ctx, field)
-			case "status":
-				return ec.fieldContext_Event_status(ctx, field)
-			case "pendingRuns":
-				return ec.fieldContext_Event_pendingRuns(ctx, field)
-			case "totalRuns":
-				return ec.fieldContext_Event_totalRuns(ctx, field)
-			case "raw":
-				return ec.fieldContext_Event_raw(ctx, field)
-			case "functionRuns":
-				return ec.fieldContext_Event_functionRuns(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type Event", field.Name)
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
+	return fc, nil
+}
+
+func (ec *executionContext) _RunHistoryCancel_userID(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistoryCancel) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_RunHistoryCancel_userID(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
 	defer func() {
 		if r := recover(); r != nil {
-			err = ec.Recover(ctx, r)
-			ec.Error(ctx, err)
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
 		}
 	}()
-	ctx = graphql.WithFieldContext(ctx, fc)
-	if fc.Args, err = ec.field_Query_events_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.UserID, nil
+	})
+	if err != nil {
 		ec.Error(ctx, err)
-		return
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*uuid.UUID)
+	fc.Result = res
+	return ec.marshalOUUID2ᚖgithubᚗcomᚋgoogleᚋuuidᚐUUID(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_RunHistoryCancel_userID(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "RunHistoryCancel",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type UUID does not have child fields")
+		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Query_functions(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_Query_functions(ctx, field)
+func (ec *executionContext) _RunHistoryInvokeFunction_eventID(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistoryInvokeFunction) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_RunHistoryInvokeFunction_eventID(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -5829,55 +7683,38 @@ func (ec *executionContext) _Query_functions(ctx context.Context, field graphql.
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return ec.resolvers.Query().Functions(rctx)
+		return obj.EventID, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
 		return graphql.Null
 	}
-	res := resTmp.([]*models.Function)
+	res := resTmp.(ulid.ULID)
 	fc.Result = res
-	return ec.marshalOFunction2ᚕᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋcoreapiᚋgraphᚋmodelsᚐFunctionᚄ(ctx, field.Selections, res)
+	return ec.marshalNULID2githubᚗcomᚋoklogᚋulidᚋv2ᚐULID(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_Query_functions(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_RunHistoryInvokeFunction_eventID(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Query",
+		Object:     "RunHistoryInvokeFunction",
 		Field:      field,
-		IsMethod:   true,
-		IsResolver: true,
+		IsMethod:   false,
+		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "id":
-				return ec.fieldContext_Function_id(ctx, field)
-			case "name":
-				return ec.fieldContext_Function_name(ctx, field)
-			case "slug":
-				return ec.fieldContext_Function_slug(ctx, field)
-			case "config":
-				return ec.fieldContext_Function_config(ctx, field)
-			case "concurrency":
-				return ec.fieldContext_Function_concurrency(ctx, field)
-			case "triggers":
-				return ec.fieldContext_Function_triggers(ctx, field)
-			case "url":
-				return ec.fieldContext_Function_url(ctx, field)
-			case "appID":
-				return ec.fieldContext_Function_appID(ctx, field)
-			case "app":
-				return ec.fieldContext_Function_app(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type Function", field.Name)
+			return nil, errors.New("field of type ULID does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Query_functionRun(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_Query_functionRun(ctx, field)
+func (ec *executionContext) _RunHistoryInvokeFunction_functionID(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistoryInvokeFunction) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_RunHistoryInvokeFunction_functionID(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -5890,84 +7727,38 @@ func (ec *executionContext) _Query_functionRun(ctx context.Context, field graphq
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return ec.resolvers.Query().FunctionRun(rctx, fc.Args["query"].(models.FunctionRunQuery))
+		return obj.FunctionID, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
 		return graphql.Null
 	}
-	res := resTmp.(*models.FunctionRun)
+	res := resTmp.(string)
 	fc.Result = res
-	return ec.marshalOFunctionRun2ᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋcoreapiᚋgraphᚋmodelsᚐFunctionRun(ctx, field.Selections, res)
+	return ec.marshalNString2string(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_Query_functionRun(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_RunHistoryInvokeFunction_functionID(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Query",
+		Object:     "RunHistoryInvokeFunction",
 		Field:      field,
-		IsMethod:   true,
-		IsResolver: true,
+		IsMethod:   false,
+		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "id":
-				return ec.fieldContext_FunctionRun_id(ctx, field)
-			case "functionID":
-				return ec.fieldContext_FunctionRun_functionID(ctx, field)
-			case "function":
-				return ec.fieldContext_FunctionRun_function(ctx, field)
-			case "workspace":
-				return ec.fieldContext_FunctionRun_workspace(ctx, field)
-			case "event":
-				return ec.fieldContext_FunctionRun_event(ctx, field)
-			case "events":
-				return ec.fieldContext_FunctionRun_events(ctx, field)
-			case "batchID":
-				return ec.fieldContext_FunctionRun_batchID(ctx, field)
-			case "batchCreatedAt":
-				return ec.fieldContext_FunctionRun_batchCreatedAt(ctx, field)
-			case "status":
-				return ec.fieldContext_FunctionRun_status(ctx, field)
-			case "waitingFor":
-				return ec.fieldContext_FunctionRun_waitingFor(ctx, field)
-			case "pendingSteps":
-				return ec.fieldContext_FunctionRun_pendingSteps(ctx, field)
-			case "startedAt":
-				return ec.fieldContext_FunctionRun_startedAt(ctx, field)
-			case "finishedAt":
-				return ec.fieldContext_FunctionRun_finishedAt(ctx, field)
-			case "output":
-				return ec.fieldContext_FunctionRun_output(ctx, field)
-			case "history":
-				return ec.fieldContext_FunctionRun_history(ctx, field)
-			case "historyItemOutput":
-				return ec.fieldContext_FunctionRun_historyItemOutput(ctx, field)
-			case "eventID":
-				return ec.fieldContext_FunctionRun_eventID(ctx, field)
-			case "cron":
-				return ec.fieldContext_FunctionRun_cron(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type FunctionRun", field.Name)
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
-	defer func() {
-		if r := recover(); r != nil {
-			err = ec.Recover(ctx, r)
-			ec.Error(ctx, err)
-		}
-	}()
-	ctx = graphql.WithFieldContext(ctx, fc)
-	if fc.Args, err = ec.field_Query_functionRun_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
-		ec.Error(ctx, err)
-		return
-	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Query___type(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_Query___type(ctx, field)
+func (ec *executionContext) _RunHistoryInvokeFunction_correlationID(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistoryInvokeFunction) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_RunHistoryInvokeFunction_correlationID(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -5980,68 +7771,38 @@ func (ec *executionContext) _Query___type(ctx context.Context, field graphql.Col
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return ec.introspectType(fc.Args["name"].(string))
+		return obj.CorrelationID, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
 		return graphql.Null
 	}
-	res := resTmp.(*introspection.Type)
+	res := resTmp.(string)
 	fc.Result = res
-	return ec.marshalO__Type2ᚖgithubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚋintrospectionᚐType(ctx, field.Selections, res)
+	return ec.marshalNString2string(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_Query___type(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_RunHistoryInvokeFunction_correlationID(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Query",
+		Object:     "RunHistoryInvokeFunction",
 		Field:      field,
-		IsMethod:   true,
+		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "kind":
-				return ec.fieldContext___Type_kind(ctx, field)
-			case "name":
-				return ec.fieldContext___Type_name(ctx, field)
-			case "description":
-				return ec.fieldContext___Type_description(ctx, field)
-			case "fields":
-				return ec.fieldContext___Type_fields(ctx, field)
-			case "interfaces":
-				return ec.fieldContext___Type_interfaces(ctx, field)
-			case "possibleTypes":
-				return ec.fieldContext___Type_possibleTypes(ctx, field)
-			case "enumValues":
-				return ec.fieldContext___Type_enumValues(ctx, field)
-			case "inputFields":
-				return ec.fieldContext___Type_inputFields(ctx, field)
-			case "ofType":
-				return ec.fieldContext___Type_ofType(ctx, field)
-			case "specifiedByURL":
-				return ec.fieldContext___Type_specifiedByURL(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type __Type", field.Name)
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
-	defer func() {
-		if r := recover(); r != nil {
-			err = ec.Recover(ctx, r)
-			ec.Error(ctx, err)
-		}
-	}()
-	ctx = graphql.WithFieldContext(ctx, fc)
-	if fc.Args, err = ec.field_Query___type_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
-		ec.Error(ctx, err)
-		return
-	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Query___schema(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_Query___schema(ctx, field)
+func (ec *executionContext) _RunHistoryInvokeFunction_timeout(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistoryInvokeFunction) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_RunHistoryInvokeFunction_timeout(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -6054,49 +7815,38 @@ func (ec *executionContext) _Query___schema(ctx context.Context, field graphql.C
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return ec.introspectSchema()
+		return obj.Timeout, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
 		return graphql.Null
 	}
-	res := resTmp.(*introspection.Schema)
+	res := resTmp.(time.Time)
 	fc.Result = res
-	return ec.marshalO__Schema2ᚖgithubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚋintrospectionᚐSchema(ctx, field.Selections, res)
+	return ec.marshalNTime2timeᚐTime(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_Query___schema(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_RunHistoryInvokeFunction_timeout(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "Query",
+		Object:     "RunHistoryInvokeFunction",
 		Field:      field,
-		IsMethod:   true,
+		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "description":
-				return ec.fieldContext___Schema_description(ctx, field)
-			case "types":
-				return ec.fieldContext___Schema_types(ctx, field)
-			case "queryType":
-				return ec.fieldContext___Schema_queryType(ctx, field)
-			case "mutationType":
-				return ec.fieldContext___Schema_mutationType(ctx, field)
-			case "subscriptionType":
-				return ec.fieldContext___Schema_subscriptionType(ctx, field)
-			case "directives":
-				return ec.fieldContext___Schema_directives(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type __Schema", field.Name)
+			return nil, errors.New("field of type Time does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _RunHistoryCancel_eventID(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistoryCancel) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_RunHistoryCancel_eventID(ctx, field)
+func (ec *executionContext) _RunHistoryInvokeFunctionResult_eventID(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistoryInvokeFunctionResult) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_RunHistoryInvokeFunctionResult_eventID(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -6123,9 +7873,9 @@ func (ec *executionContext) _RunHistoryCancel_eventID(ctx context.Context, field
 	return ec.marshalOULID2ᚖgithubᚗcomᚋoklogᚋulidᚋv2ᚐULID(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_RunHistoryCancel_eventID(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_RunHistoryInvokeFunctionResult_eventID(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "RunHistoryCancel",
+		Object:     "RunHistoryInvokeFunctionResult",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
@@ -6136,8 +7886,8 @@ func (ec *executionContext) fieldContext_RunHistoryCancel_eventID(ctx context.Co
 	return fc, nil
 }
 
-func (ec *executionContext) _RunHistoryCancel_expression(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistoryCancel) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_RunHistoryCancel_expression(ctx, field)
+func (ec *executionContext) _RunHistoryInvokeFunctionResult_timeout(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistoryInvokeFunctionResult) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_RunHistoryInvokeFunctionResult_timeout(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -6150,35 +7900,38 @@ func (ec *executionContext) _RunHistoryCancel_expression(ctx context.Context, fi
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Expression, nil
+		return obj.Timeout, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
 		return graphql.Null
 	}
-	res := resTmp.(*string)
+	res := resTmp.(bool)
 	fc.Result = res
-	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+	return ec.marshalNBoolean2bool(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_RunHistoryCancel_expression(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_RunHistoryInvokeFunctionResult_timeout(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "RunHistoryCancel",
+		Object:     "RunHistoryInvokeFunctionResult",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type String does not have child fields")
+			return nil, errors.New("field of type Boolean does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _RunHistoryCancel_userID(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistoryCancel) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_RunHistoryCancel_userID(ctx, field)
+func (ec *executionContext) _RunHistoryInvokeFunctionResult_runID(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistoryInvokeFunctionResult) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_RunHistoryInvokeFunctionResult_runID(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -6191,7 +7944,7 @@ func (ec *executionContext) _RunHistoryCancel_userID(ctx context.Context, field
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.UserID, nil
+		return obj.RunID, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -6200,26 +7953,26 @@ func (ec *executionContext) _RunHistoryCancel_userID(ctx context.Context, field
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*uuid.UUID)
+	res := resTmp.(*ulid.ULID)
 	fc.Result = res
-	return ec.marshalOUUID2ᚖgithubᚗcomᚋgoogleᚋuuidᚐUUID(ctx, field.Selections, res)
+	return ec.marshalOULID2ᚖgithubᚗcomᚋoklogᚋulidᚋv2ᚐULID(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_RunHistoryCancel_userID(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_RunHistoryInvokeFunctionResult_runID(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "RunHistoryCancel",
+		Object:     "RunHistoryInvokeFunctionResult",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type UUID does not have child fields")
+			return nil, errors.New("field of type ULID does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _RunHistoryInvokeFunction_eventID(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistoryInvokeFunction) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_RunHistoryInvokeFunction_eventID(ctx, field)
+func (ec *executionContext) _RunHistoryItem_attempt(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistory) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_RunHistoryItem_attempt(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -6232,7 +7985,7 @@ func (ec *executionContext) _RunHistoryInvokeFunction_eventID(ctx context.Contex
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.EventID, nil
+		return obj.Attempt, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -6244,26 +7997,26 @@ func (ec *executionContext) _RunHistoryInvokeFunction_eventID(ctx context.Contex
 		}
 		return graphql.Null
 	}
-	res := resTmp.(ulid.ULID)
+	res := resTmp.(int64)
 	fc.Result = res
-	return ec.marshalNULID2githubᚗcomᚋoklogᚋulidᚋv2ᚐULID(ctx, field.Selections, res)
+	return ec.marshalNInt2int64(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_RunHistoryInvokeFunction_eventID(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_RunHistoryItem_attempt(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "RunHistoryInvokeFunction",
+		Object:     "RunHistoryItem",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type ULID does not have child fields")
+			return nil, errors.New("field of type Int does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _RunHistoryInvokeFunction_functionID(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistoryInvokeFunction) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_RunHistoryInvokeFunction_functionID(ctx, field)
+func (ec *executionContext) _RunHistoryItem_cancel(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistory) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_RunHistoryItem_cancel(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -6276,38 +8029,43 @@ func (ec *executionContext) _RunHistoryInvokeFunction_functionID(ctx context.Con
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.FunctionID, nil
+		return obj.Cancel, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
-		if !graphql.HasFieldError(ctx, fc) {
-			ec.Errorf(ctx, "must not be null")
-		}
 		return graphql.Null
 	}
-	res := resTmp.(string)
+	res := resTmp.(*history_reader.RunHistoryCancel)
 	fc.Result = res
-	return ec.marshalNString2string(ctx, field.Selections, res)
+	return ec.marshalORunHistoryCancel2ᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋhistory_readerᚐRunHistoryCancel(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_RunHistoryInvokeFunction_functionID(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_RunHistoryItem_cancel(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "RunHistoryInvokeFunction",
+		Object:     "RunHistoryItem",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type String does not have child fields")
+			switch field.Name {
+			case "eventID":
+				return ec.fieldContext_RunHistoryCancel_eventID(ctx, field)
+			case "expression":
+				return ec.fieldContext_RunHistoryCancel_expression(ctx, field)
+			case "userID":
+				return ec.fieldContext_RunHistoryCancel_userID(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type RunHistoryCancel", field.Name)
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _RunHistoryInvokeFunction_correlationID(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistoryInvokeFunction) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_RunHistoryInvokeFunction_correlationID(ctx, field)
+func (ec *executionContext) _RunHistoryItem_createdAt(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistory) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_RunHistoryItem_createdAt(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -6320,7 +8078,7 @@ func (ec *executionContext) _RunHistoryInvokeFunction_correlationID(ctx context.
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.CorrelationID, nil
+		return obj.CreatedAt, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -6332,26 +8090,26 @@ func (ec *executionContext) _RunHistoryInvokeFunction_correlationID(ctx context.
 		}
 		return graphql.Null
 	}
-	res := resTmp.(string)
+	res := resTmp.(time.Time)
 	fc.Result = res
-	return ec.marshalNString2string(ctx, field.Selections, res)
+	return ec.marshalNTime2timeᚐTime(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_RunHistoryInvokeFunction_correlationID(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_RunHistoryItem_createdAt(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "RunHistoryInvokeFunction",
+		Object:     "RunHistoryItem",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type String does not have child fields")
+			return nil, errors.New("field of type Time does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _RunHistoryInvokeFunction_timeout(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistoryInvokeFunction) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_RunHistoryInvokeFunction_timeout(ctx, field)
+func (ec *executionContext) _RunHistoryItem_functionVersion(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistory) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_RunHistoryItem_functionVersion(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -6364,7 +8122,7 @@ func (ec *executionContext) _RunHistoryInvokeFunction_timeout(ctx context.Contex
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Timeout, nil
+		return obj.FunctionVersion, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -6376,26 +8134,26 @@ func (ec *executionContext) _RunHistoryInvokeFunction_timeout(ctx context.Contex
 		}
 		return graphql.Null
 	}
-	res := resTmp.(time.Time)
+	res := resTmp.(int64)
 	fc.Result = res
-	return ec.marshalNTime2timeᚐTime(ctx, field.Selections, res)
+	return ec.marshalNInt2int64(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_RunHistoryInvokeFunction_timeout(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_RunHistoryItem_functionVersion(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "RunHistoryInvokeFunction",
+		Object:     "RunHistoryItem",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type Time does not have child fields")
+			return nil, errors.New("field of type Int does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _RunHistoryInvokeFunctionResult_eventID(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistoryInvokeFunctionResult) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_RunHistoryInvokeFunctionResult_eventID(ctx, field)
+func (ec *executionContext) _RunHistoryItem_groupID(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistory) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_RunHistoryItem_groupID(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -6408,7 +8166,7 @@ func (ec *executionContext) _RunHistoryInvokeFunctionResult_eventID(ctx context.
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.EventID, nil
+		return obj.GroupID, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -6417,26 +8175,26 @@ func (ec *executionContext) _RunHistoryInvokeFunctionResult_eventID(ctx context.
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*ulid.ULID)
+	res := resTmp.(*uuid.UUID)
 	fc.Result = res
-	return ec.marshalOULID2ᚖgithubᚗcomᚋoklogᚋulidᚋv2ᚐULID(ctx, field.Selections, res)
+	return ec.marshalOUUID2ᚖgithubᚗcomᚋgoogleᚋuuidᚐUUID(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_RunHistoryInvokeFunctionResult_eventID(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_RunHistoryItem_groupID(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "RunHistoryInvokeFunctionResult",
+		Object:     "RunHistoryItem",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type ULID does not have child fields")
+			return nil, errors.New("field of type UUID does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _RunHistoryInvokeFunctionResult_timeout(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistoryInvokeFunctionResult) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_RunHistoryInvokeFunctionResult_timeout(ctx, field)
+func (ec *executionContext) _RunHistoryItem_id(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistory) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_RunHistoryItem_id(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -6449,7 +8207,7 @@ func (ec *executionContext) _RunHistoryInvokeFunctionResult_timeout(ctx context.
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Timeout, nil
+		return obj.ID, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -6461,26 +8219,26 @@ func (ec *executionContext) _RunHistoryInvokeFunctionResult_timeout(ctx context.
 		}
 		return graphql.Null
 	}
-	res := resTmp.(bool)
+	res := resTmp.(ulid.ULID)
 	fc.Result = res
-	return ec.marshalNBoolean2bool(ctx, field.Selections, res)
+	return ec.marshalNULID2githubᚗcomᚋoklogᚋulidᚋv2ᚐULID(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_RunHistoryInvokeFunctionResult_timeout(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_RunHistoryItem_id(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "RunHistoryInvokeFunctionResult",
+		Object:     "RunHistoryItem",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type Boolean does not have child fields")
+			return nil, errors.New("field of type ULID does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _RunHistoryInvokeFunctionResult_runID(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistoryInvokeFunctionResult) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_RunHistoryInvokeFunctionResult_runID(ctx, field)
+func (ec *executionContext) _RunHistoryItem_result(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistory) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_RunHistoryItem_result(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -6493,7 +8251,7 @@ func (ec *executionContext) _RunHistoryInvokeFunctionResult_runID(ctx context.Co
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.RunID, nil
+		return obj.Result, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -6502,26 +8260,42 @@ func (ec *executionContext) _RunHistoryInvokeFunctionResult_runID(ctx context.Co
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*ulid.ULID)
+	res := resTmp.(*history_reader.RunHistoryResult)
 	fc.Result = res
-	return ec.marshalOULID2ᚖgithubᚗcomᚋoklogᚋulidᚋv2ᚐULID(ctx, field.Selections, res)
+	return ec.marshalORunHistoryResult2ᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋhistory_readerᚐRunHistoryResult(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_RunHistoryInvokeFunctionResult_runID(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_RunHistoryItem_result(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "RunHistoryInvokeFunctionResult",
+		Object:     "RunHistoryItem",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type ULID does not have child fields")
+			switch field.Name {
+			case "durationMS":
+				return ec.fieldContext_RunHistoryResult_durationMS(ctx, field)
+			case "errorCode":
+				return ec.fieldContext_RunHistoryResult_errorCode(ctx, field)
+			case "framework":
+				return ec.fieldContext_RunHistoryResult_framework(ctx, field)
+			case "platform":
+				return ec.fieldContext_RunHistoryResult_platform(ctx, field)
+			case "sdkLanguage":
+				return ec.fieldContext_RunHistoryResult_sdkLanguage(ctx, field)
+			case "sdkVersion":
+				return ec.fieldContext_RunHistoryResult_sdkVersion(ctx, field)
+			case "sizeBytes":
+				return ec.fieldContext_RunHistoryResult_sizeBytes(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type RunHistoryResult", field.Name)
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _RunHistoryItem_attempt(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistory) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_RunHistoryItem_attempt(ctx, field)
+func (ec *executionContext) _RunHistoryItem_sleep(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistory) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_RunHistoryItem_sleep(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -6534,38 +8308,39 @@ func (ec *executionContext) _RunHistoryItem_attempt(ctx context.Context, field g
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Attempt, nil
+		return obj.Sleep, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
-		if !graphql.HasFieldError(ctx, fc) {
-			ec.Errorf(ctx, "must not be null")
-		}
 		return graphql.Null
 	}
-	res := resTmp.(int64)
+	res := resTmp.(*history_reader.RunHistorySleep)
 	fc.Result = res
-	return ec.marshalNInt2int64(ctx, field.Selections, res)
+	return ec.marshalORunHistorySleep2ᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋhistory_readerᚐRunHistorySleep(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_RunHistoryItem_attempt(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_RunHistoryItem_sleep(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
 		Object:     "RunHistoryItem",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type Int does not have child fields")
+			switch field.Name {
+			case "until":
+				return ec.fieldContext_RunHistorySleep_until(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type RunHistorySleep", field.Name)
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _RunHistoryItem_cancel(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistory) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_RunHistoryItem_cancel(ctx, field)
+func (ec *executionContext) _RunHistoryItem_stepName(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistory) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_RunHistoryItem_stepName(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -6578,7 +8353,7 @@ func (ec *executionContext) _RunHistoryItem_cancel(ctx context.Context, field gr
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Cancel, nil
+		return obj.StepName, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -6587,34 +8362,26 @@ func (ec *executionContext) _RunHistoryItem_cancel(ctx context.Context, field gr
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*history_reader.RunHistoryCancel)
+	res := resTmp.(*string)
 	fc.Result = res
-	return ec.marshalORunHistoryCancel2ᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋhistory_readerᚐRunHistoryCancel(ctx, field.Selections, res)
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_RunHistoryItem_cancel(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_RunHistoryItem_stepName(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
 		Object:     "RunHistoryItem",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "eventID":
-				return ec.fieldContext_RunHistoryCancel_eventID(ctx, field)
-			case "expression":
-				return ec.fieldContext_RunHistoryCancel_expression(ctx, field)
-			case "userID":
-				return ec.fieldContext_RunHistoryCancel_userID(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type RunHistoryCancel", field.Name)
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _RunHistoryItem_createdAt(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistory) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_RunHistoryItem_createdAt(ctx, field)
+func (ec *executionContext) _RunHistoryItem_stepType(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistory) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_RunHistoryItem_stepType(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -6627,38 +8394,35 @@ func (ec *executionContext) _RunHistoryItem_createdAt(ctx context.Context, field
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.CreatedAt, nil
+		return obj.StepType, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
-		if !graphql.HasFieldError(ctx, fc) {
-			ec.Errorf(ctx, "must not be null")
-		}
 		return graphql.Null
 	}
-	res := resTmp.(time.Time)
+	res := resTmp.(*enums.HistoryStepType)
 	fc.Result = res
-	return ec.marshalNTime2timeᚐTime(ctx, field.Selections, res)
+	return ec.marshalOHistoryStepType2ᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋenumsᚐHistoryStepType(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_RunHistoryItem_createdAt(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_RunHistoryItem_stepType(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
 		Object:     "RunHistoryItem",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type Time does not have child fields")
+			return nil, errors.New("field of type HistoryStepType does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _RunHistoryItem_functionVersion(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistory) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_RunHistoryItem_functionVersion(ctx, field)
+func (ec *executionContext) _RunHistoryItem_type(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistory) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_RunHistoryItem_type(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -6671,7 +8435,7 @@ func (ec *executionContext) _RunHistoryItem_functionVersion(ctx context.Context,
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.FunctionVersion, nil
+		return obj.Type, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -6683,26 +8447,26 @@ func (ec *executionContext) _RunHistoryItem_functionVersion(ctx context.Context,
 		}
 		return graphql.Null
 	}
-	res := resTmp.(int64)
+	res := resTmp.(enums.HistoryType)
 	fc.Result = res
-	return ec.marshalNInt2int64(ctx, field.Selections, res)
+	return ec.marshalNHistoryType2githubᚗcomᚋinngestᚋinngestᚋpkgᚋenumsᚐHistoryType(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_RunHistoryItem_functionVersion(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_RunHistoryItem_type(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
 		Object:     "RunHistoryItem",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type Int does not have child fields")
+			return nil, errors.New("field of type HistoryType does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _RunHistoryItem_groupID(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistory) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_RunHistoryItem_groupID(ctx, field)
+func (ec *executionContext) _RunHistoryItem_url(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistory) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_RunHistoryItem_url(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -6715,7 +8479,7 @@ func (ec *executionContext) _RunHistoryItem_groupID(ctx context.Context, field g
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.GroupID, nil
+		return obj.URL, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -6724,26 +8488,26 @@ func (ec *executionContext) _RunHistoryItem_groupID(ctx context.Context, field g
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*uuid.UUID)
+	res := resTmp.(*string)
 	fc.Result = res
-	return ec.marshalOUUID2ᚖgithubᚗcomᚋgoogleᚋuuidᚐUUID(ctx, field.Selections, res)
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_RunHistoryItem_groupID(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_RunHistoryItem_url(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
 		Object:     "RunHistoryItem",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type UUID does not have child fields")
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _RunHistoryItem_id(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistory) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_RunHistoryItem_id(ctx, field)
+func (ec *executionContext) _RunHistoryItem_waitForEvent(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistory) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_RunHistoryItem_waitForEvent(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -6756,38 +8520,43 @@ func (ec *executionContext) _RunHistoryItem_id(ctx context.Context, field graphq
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.ID, nil
+		return obj.WaitForEvent, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
-		if !graphql.HasFieldError(ctx, fc) {
-			ec.Errorf(ctx, "must not be null")
-		}
 		return graphql.Null
 	}
-	res := resTmp.(ulid.ULID)
+	res := resTmp.(*history_reader.RunHistoryWaitForEvent)
 	fc.Result = res
-	return ec.marshalNULID2githubᚗcomᚋoklogᚋulidᚋv2ᚐULID(ctx, field.Selections, res)
+	return ec.marshalORunHistoryWaitForEvent2ᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋhistory_readerᚐRunHistoryWaitForEvent(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_RunHistoryItem_id(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_RunHistoryItem_waitForEvent(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
 		Object:     "RunHistoryItem",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type ULID does not have child fields")
+			switch field.Name {
+			case "eventName":
+				return ec.fieldContext_RunHistoryWaitForEvent_eventName(ctx, field)
+			case "expression":
+				return ec.fieldContext_RunHistoryWaitForEvent_expression(ctx, field)
+			case "timeout":
+				return ec.fieldContext_RunHistoryWaitForEvent_timeout(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type RunHistoryWaitForEvent", field.Name)
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _RunHistoryItem_result(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistory) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_RunHistoryItem_result(ctx, field)
+func (ec *executionContext) _RunHistoryItem_waitResult(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistory) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_RunHistoryItem_waitResult(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -6800,7 +8569,7 @@ func (ec *executionContext) _RunHistoryItem_result(ctx context.Context, field gr
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Result, nil
+		return obj.WaitResult, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -6809,12 +8578,12 @@ func (ec *executionContext) _RunHistoryItem_result(ctx context.Context, field gr
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*history_reader.RunHistoryResult)
+	res := resTmp.(*history_reader.RunHistoryWaitResult)
 	fc.Result = res
-	return ec.marshalORunHistoryResult2ᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋhistory_readerᚐRunHistoryResult(ctx, field.Selections, res)
+	return ec.marshalORunHistoryWaitResult2ᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋhistory_readerᚐRunHistoryWaitResult(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_RunHistoryItem_result(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_RunHistoryItem_waitResult(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
 		Object:     "RunHistoryItem",
 		Field:      field,
@@ -6822,29 +8591,19 @@ func (ec *executionContext) fieldContext_RunHistoryItem_result(ctx context.Conte
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
 			switch field.Name {
-			case "durationMS":
-				return ec.fieldContext_RunHistoryResult_durationMS(ctx, field)
-			case "errorCode":
-				return ec.fieldContext_RunHistoryResult_errorCode(ctx, field)
-			case "framework":
-				return ec.fieldContext_RunHistoryResult_framework(ctx, field)
-			case "platform":
-				return ec.fieldContext_RunHistoryResult_platform(ctx, field)
-			case "sdkLanguage":
-				return ec.fieldContext_RunHistoryResult_sdkLanguage(ctx, field)
-			case "sdkVersion":
-				return ec.fieldContext_RunHistoryResult_sdkVersion(ctx, field)
-			case "sizeBytes":
-				return ec.fieldContext_RunHistoryResult_sizeBytes(ctx, field)
+			case "eventID":
+				return ec.fieldContext_RunHistoryWaitResult_eventID(ctx, field)
+			case "timeout":
+				return ec.fieldContext_RunHistoryWaitResult_timeout(ctx, field)
 			}
-			return nil, fmt.Errorf("no field named %q was found under type RunHistoryResult", field.Name)
+			return nil, fmt.Errorf("no field named %q was found under type RunHistoryWaitResult", field.Name)
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _RunHistoryItem_sleep(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistory) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_RunHistoryItem_sleep(ctx, field)
+func (ec *executionContext) _RunHistoryItem_invokeFunction(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistory) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_RunHistoryItem_invokeFunction(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -6857,7 +8616,7 @@ func (ec *executionContext) _RunHistoryItem_sleep(ctx context.Context, field gra
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Sleep, nil
+		return obj.InvokeFunction, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -6866,12 +8625,12 @@ func (ec *executionContext) _RunHistoryItem_sleep(ctx context.Context, field gra
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*history_reader.RunHistorySleep)
+	res := resTmp.(*history_reader.RunHistoryInvokeFunction)
 	fc.Result = res
-	return ec.marshalORunHistorySleep2ᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋhistory_readerᚐRunHistorySleep(ctx, field.Selections, res)
+	return ec.marshalORunHistoryInvokeFunction2ᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋhistory_readerᚐRunHistoryInvokeFunction(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_RunHistoryItem_sleep(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_RunHistoryItem_invokeFunction(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
 		Object:     "RunHistoryItem",
 		Field:      field,
@@ -6879,17 +8638,23 @@ func (ec *executionContext) fieldContext_RunHistoryItem_sleep(ctx context.Contex
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
 			switch field.Name {
-			case "until":
-				return ec.fieldContext_RunHistorySleep_until(ctx, field)
+			case "eventID":
+				return ec.fieldContext_RunHistoryInvokeFunction_eventID(ctx, field)
+			case "functionID":
+				return ec.fieldContext_RunHistoryInvokeFunction_functionID(ctx, field)
+			case "correlationID":
+				return ec.fieldContext_RunHistoryInvokeFunction_correlationID(ctx, field)
+			case "timeout":
+				return ec.fieldContext_RunHistoryInvokeFunction_timeout(ctx, field)
 			}
-			return nil, fmt.Errorf("no field named %q was found under type RunHistorySleep", field.Name)
+			return nil, fmt.Errorf("no field named %q was found under type RunHistoryInvokeFunction", field.Name)
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _RunHistoryItem_stepName(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistory) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_RunHistoryItem_stepName(ctx, field)
+func (ec *executionContext) _RunHistoryItem_invokeFunctionResult(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistory) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_RunHistoryItem_invokeFunctionResult(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -6902,7 +8667,7 @@ func (ec *executionContext) _RunHistoryItem_stepName(ctx context.Context, field
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.StepName, nil
+		return obj.InvokeFunctionResult, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -6911,26 +8676,34 @@ func (ec *executionContext) _RunHistoryItem_stepName(ctx context.Context, field
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*string)
+	res := resTmp.(*history_reader.RunHistoryInvokeFunctionResult)
 	fc.Result = res
-	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+	return ec.marshalORunHistoryInvokeFunctionResult2ᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋhistory_readerᚐRunHistoryInvokeFunctionResult(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_RunHistoryItem_stepName(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_RunHistoryItem_invokeFunctionResult(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
 		Object:     "RunHistoryItem",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type String does not have child fields")
+			switch field.Name {
+			case "eventID":
+				return ec.fieldContext_RunHistoryInvokeFunctionResult_eventID(ctx, field)
+			case "timeout":
+				return ec.fieldContext_RunHistoryInvokeFunctionResult_timeout(ctx, field)
+			case "runID":
+				return ec.fieldContext_RunHistoryInvokeFunctionResult_runID(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type RunHistoryInvokeFunctionResult", field.Name)
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _RunHistoryItem_stepType(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistory) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_RunHistoryItem_stepType(ctx, field)
+func (ec *executionContext) _RunHistoryResult_durationMS(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistoryResult) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_RunHistoryResult_durationMS(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -6943,35 +8716,38 @@ func (ec *executionContext) _RunHistoryItem_stepType(ctx context.Context, field
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.StepType, nil
+		return obj.DurationMS, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
 		return graphql.Null
 	}
-	res := resTmp.(*enums.HistoryStepType)
+	res := resTmp.(int)
 	fc.Result = res
-	return ec.marshalOHistoryStepType2ᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋenumsᚐHistoryStepType(ctx, field.Selections, res)
+	return ec.marshalNInt2int(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_RunHistoryItem_stepType(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_RunHistoryResult_durationMS(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "RunHistoryItem",
+		Object:     "RunHistoryResult",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type HistoryStepType does not have child fields")
+			return nil, errors.New("field of type Int does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _RunHistoryItem_type(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistory) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_RunHistoryItem_type(ctx, field)
+func (ec *executionContext) _RunHistoryResult_errorCode(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistoryResult) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_RunHistoryResult_errorCode(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -6984,38 +8760,35 @@ func (ec *executionContext) _RunHistoryItem_type(ctx context.Context, field grap
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Type, nil
+		return obj.ErrorCode, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
-		if !graphql.HasFieldError(ctx, fc) {
-			ec.Errorf(ctx, "must not be null")
-		}
 		return graphql.Null
 	}
-	res := resTmp.(enums.HistoryType)
+	res := resTmp.(*string)
 	fc.Result = res
-	return ec.marshalNHistoryType2githubᚗcomᚋinngestᚋinngestᚋpkgᚋenumsᚐHistoryType(ctx, field.Selections, res)
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_RunHistoryItem_type(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_RunHistoryResult_errorCode(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "RunHistoryItem",
+		Object:     "RunHistoryResult",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type HistoryType does not have child fields")
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _RunHistoryItem_url(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistory) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_RunHistoryItem_url(ctx, field)
+func (ec *executionContext) _RunHistoryResult_framework(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistoryResult) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_RunHistoryResult_framework(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -7028,7 +8801,7 @@ func (ec *executionContext) _RunHistoryItem_url(ctx context.Context, field graph
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.URL, nil
+		return obj.Framework, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -7042,9 +8815,9 @@ func (ec *executionContext) _RunHistoryItem_url(ctx context.Context, field graph
 	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_RunHistoryItem_url(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_RunHistoryResult_framework(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "RunHistoryItem",
+		Object:     "RunHistoryResult",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
@@ -7055,8 +8828,8 @@ func (ec *executionContext) fieldContext_RunHistoryItem_url(ctx context.Context,
 	return fc, nil
 }
 
-func (ec *executionContext) _RunHistoryItem_waitForEvent(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistory) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_RunHistoryItem_waitForEvent(ctx, field)
+func (ec *executionContext) _RunHistoryResult_platform(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistoryResult) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_RunHistoryResult_platform(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -7069,7 +8842,7 @@ func (ec *executionContext) _RunHistoryItem_waitForEvent(ctx context.Context, fi
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.WaitForEvent, nil
+		return obj.Platform, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -7078,34 +8851,26 @@ func (ec *executionContext) _RunHistoryItem_waitForEvent(ctx context.Context, fi
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*history_reader.RunHistoryWaitForEvent)
+	res := resTmp.(*string)
 	fc.Result = res
-	return ec.marshalORunHistoryWaitForEvent2ᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋhistory_readerᚐRunHistoryWaitForEvent(ctx, field.Selections, res)
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_RunHistoryItem_waitForEvent(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_RunHistoryResult_platform(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "RunHistoryItem",
+		Object:     "RunHistoryResult",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "eventName":
-				return ec.fieldContext_RunHistoryWaitForEvent_eventName(ctx, field)
-			case "expression":
-				return ec.fieldContext_RunHistoryWaitForEvent_expression(ctx, field)
-			case "timeout":
-				return ec.fieldContext_RunHistoryWaitForEvent_timeout(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type RunHistoryWaitForEvent", field.Name)
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _RunHistoryItem_waitResult(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistory) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_RunHistoryItem_waitResult(ctx, field)
+func (ec *executionContext) _RunHistoryResult_sdkLanguage(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistoryResult) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_RunHistoryResult_sdkLanguage(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -7118,41 +8883,38 @@ func (ec *executionContext) _RunHistoryItem_waitResult(ctx context.Context, fiel
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.WaitResult, nil
+		return obj.SDKLanguage, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
 		return graphql.Null
 	}
-	res := resTmp.(*history_reader.RunHistoryWaitResult)
+	res := resTmp.(string)
 	fc.Result = res
-	return ec.marshalORunHistoryWaitResult2ᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋhistory_readerᚐRunHistoryWaitResult(ctx, field.Selections, res)
+	return ec.marshalNString2string(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_RunHistoryItem_waitResult(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_RunHistoryResult_sdkLanguage(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "RunHistoryItem",
+		Object:     "RunHistoryResult",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "eventID":
-				return ec.fieldContext_RunHistoryWaitResult_eventID(ctx, field)
-			case "timeout":
-				return ec.fieldContext_RunHistoryWaitResult_timeout(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type RunHistoryWaitResult", field.Name)
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _RunHistoryItem_invokeFunction(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistory) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_RunHistoryItem_invokeFunction(ctx, field)
+func (ec *executionContext) _RunHistoryResult_sdkVersion(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistoryResult) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_RunHistoryResult_sdkVersion(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -7165,45 +8927,38 @@ func (ec *executionContext) _RunHistoryItem_invokeFunction(ctx context.Context,
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.InvokeFunction, nil
+		return obj.SDKVersion, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
 		return graphql.Null
 	}
-	res := resTmp.(*history_reader.RunHistoryInvokeFunction)
+	res := resTmp.(string)
 	fc.Result = res
-	return ec.marshalORunHistoryInvokeFunction2ᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋhistory_readerᚐRunHistoryInvokeFunction(ctx, field.Selections, res)
+	return ec.marshalNString2string(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_RunHistoryItem_invokeFunction(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_RunHistoryResult_sdkVersion(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "RunHistoryItem",
+		Object:     "RunHistoryResult",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "eventID":
-				return ec.fieldContext_RunHistoryInvokeFunction_eventID(ctx, field)
-			case "functionID":
-				return ec.fieldContext_RunHistoryInvokeFunction_functionID(ctx, field)
-			case "correlationID":
-				return ec.fieldContext_RunHistoryInvokeFunction_correlationID(ctx, field)
-			case "timeout":
-				return ec.fieldContext_RunHistoryInvokeFunction_timeout(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type RunHistoryInvokeFunction", field.Name)
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _RunHistoryItem_invokeFunctionResult(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistory) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_RunHistoryItem_invokeFunctionResult(ctx, field)
+func (ec *executionContext) _RunHistoryResult_sizeBytes(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistoryResult) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_RunHistoryResult_sizeBytes(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -7216,43 +8971,38 @@ func (ec *executionContext) _RunHistoryItem_invokeFunctionResult(ctx context.Con
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.InvokeFunctionResult, nil
+		return obj.SizeBytes, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
 		return graphql.Null
 	}
-	res := resTmp.(*history_reader.RunHistoryInvokeFunctionResult)
+	res := resTmp.(int)
 	fc.Result = res
-	return ec.marshalORunHistoryInvokeFunctionResult2ᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋhistory_readerᚐRunHistoryInvokeFunctionResult(ctx, field.Selections, res)
+	return ec.marshalNInt2int(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_RunHistoryItem_invokeFunctionResult(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_RunHistoryResult_sizeBytes(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "RunHistoryItem",
+		Object:     "RunHistoryResult",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "eventID":
-				return ec.fieldContext_RunHistoryInvokeFunctionResult_eventID(ctx, field)
-			case "timeout":
-				return ec.fieldContext_RunHistoryInvokeFunctionResult_timeout(ctx, field)
-			case "runID":
-				return ec.fieldContext_RunHistoryInvokeFunctionResult_runID(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type RunHistoryInvokeFunctionResult", field.Name)
+			return nil, errors.New("field of type Int does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _RunHistoryResult_durationMS(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistoryResult) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_RunHistoryResult_durationMS(ctx, field)
+func (ec *executionContext) _RunHistorySleep_until(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistorySleep) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_RunHistorySleep_until(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -7265,7 +9015,7 @@ func (ec *executionContext) _RunHistoryResult_durationMS(ctx context.Context, fi
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.DurationMS, nil
+		return obj.Until, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -7277,26 +9027,26 @@ func (ec *executionContext) _RunHistoryResult_durationMS(ctx context.Context, fi
 		}
 		return graphql.Null
 	}
-	res := resTmp.(int)
+	res := resTmp.(time.Time)
 	fc.Result = res
-	return ec.marshalNInt2int(ctx, field.Selections, res)
+	return ec.marshalNTime2timeᚐTime(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_RunHistoryResult_durationMS(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_RunHistorySleep_until(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "RunHistoryResult",
+		Object:     "RunHistorySleep",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type Int does not have child fields")
+			return nil, errors.New("field of type Time does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _RunHistoryResult_errorCode(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistoryResult) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_RunHistoryResult_errorCode(ctx, field)
+func (ec *executionContext) _RunHistoryWaitForEvent_eventName(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistoryWaitForEvent) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_RunHistoryWaitForEvent_eventName(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -7309,23 +9059,26 @@ func (ec *executionContext) _RunHistoryResult_errorCode(ctx context.Context, fie
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.ErrorCode, nil
+		return obj.EventName, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
 		return graphql.Null
 	}
-	res := resTmp.(*string)
+	res := resTmp.(string)
 	fc.Result = res
-	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+	return ec.marshalNString2string(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_RunHistoryResult_errorCode(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_RunHistoryWaitForEvent_eventName(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "RunHistoryResult",
+		Object:     "RunHistoryWaitForEvent",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
@@ -7336,8 +9089,8 @@ func (ec *executionContext) fieldContext_RunHistoryResult_errorCode(ctx context.
 	return fc, nil
 }
 
-func (ec *executionContext) _RunHistoryResult_framework(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistoryResult) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_RunHistoryResult_framework(ctx, field)
+func (ec *executionContext) _RunHistoryWaitForEvent_expression(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistoryWaitForEvent) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_RunHistoryWaitForEvent_expression(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -7350,7 +9103,7 @@ func (ec *executionContext) _RunHistoryResult_framework(ctx context.Context, fie
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Framework, nil
+		return obj.Expression, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -7364,9 +9117,9 @@ func (ec *executionContext) _RunHistoryResult_framework(ctx context.Context, fie
 	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_RunHistoryResult_framework(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_RunHistoryWaitForEvent_expression(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "RunHistoryResult",
+		Object:     "RunHistoryWaitForEvent",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
@@ -7377,8 +9130,8 @@ func (ec *executionContext) fieldContext_RunHistoryResult_framework(ctx context.
 	return fc, nil
 }
 
-func (ec *executionContext) _RunHistoryResult_platform(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistoryResult) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_RunHistoryResult_platform(ctx, field)
+func (ec *executionContext) _RunHistoryWaitForEvent_timeout(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistoryWaitForEvent) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_RunHistoryWaitForEvent_timeout(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -7391,35 +9144,38 @@ func (ec *executionContext) _RunHistoryResult_platform(ctx context.Context, fiel
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Platform, nil
+		return obj.Timeout, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
 		return graphql.Null
 	}
-	res := resTmp.(*string)
+	res := resTmp.(time.Time)
 	fc.Result = res
-	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+	return ec.marshalNTime2timeᚐTime(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_RunHistoryResult_platform(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_RunHistoryWaitForEvent_timeout(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "RunHistoryResult",
+		Object:     "RunHistoryWaitForEvent",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type String does not have child fields")
+			return nil, errors.New("field of type Time does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _RunHistoryResult_sdkLanguage(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistoryResult) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_RunHistoryResult_sdkLanguage(ctx, field)
+func (ec *executionContext) _RunHistoryWaitResult_eventID(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistoryWaitResult) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_RunHistoryWaitResult_eventID(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -7432,38 +9188,35 @@ func (ec *executionContext) _RunHistoryResult_sdkLanguage(ctx context.Context, f
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.SDKLanguage, nil
+		return obj.EventID, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
-		if !graphql.HasFieldError(ctx, fc) {
-			ec.Errorf(ctx, "must not be null")
-		}
 		return graphql.Null
 	}
-	res := resTmp.(string)
+	res := resTmp.(*ulid.ULID)
 	fc.Result = res
-	return ec.marshalNString2string(ctx, field.Selections, res)
+	return ec.marshalOULID2ᚖgithubᚗcomᚋoklogᚋulidᚋv2ᚐULID(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_RunHistoryResult_sdkLanguage(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_RunHistoryWaitResult_eventID(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "RunHistoryResult",
+		Object:     "RunHistoryWaitResult",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type String does not have child fields")
+			return nil, errors.New("field of type ULID does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _RunHistoryResult_sdkVersion(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistoryResult) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_RunHistoryResult_sdkVersion(ctx, field)
+func (ec *executionContext) _RunHistoryWaitResult_timeout(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistoryWaitResult) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_RunHistoryWaitResult_timeout(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -7476,7 +9229,7 @@ func (ec *executionContext) _RunHistoryResult_sdkVersion(ctx context.Context, fi
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.SDKVersion, nil
+		return obj.Timeout, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -7488,26 +9241,26 @@ func (ec *executionContext) _RunHistoryResult_sdkVersion(ctx context.Context, fi
 		}
 		return graphql.Null
 	}
-	res := resTmp.(string)
+	res := resTmp.(bool)
 	fc.Result = res
-	return ec.marshalNString2string(ctx, field.Selections, res)
+	return ec.marshalNBoolean2bool(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_RunHistoryResult_sdkVersion(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_RunHistoryWaitResult_timeout(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "RunHistoryResult",
+		Object:     "RunHistoryWaitResult",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type String does not have child fields")
+			return nil, errors.New("field of type Boolean does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _RunHistoryResult_sizeBytes(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistoryResult) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_RunHistoryResult_sizeBytes(ctx, field)
+func (ec *executionContext) _StepEvent_workspace(ctx context.Context, field graphql.CollectedField, obj *models.StepEvent) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_StepEvent_workspace(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -7520,38 +9273,39 @@ func (ec *executionContext) _RunHistoryResult_sizeBytes(ctx context.Context, fie
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.SizeBytes, nil
+		return obj.Workspace, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
-		if !graphql.HasFieldError(ctx, fc) {
-			ec.Errorf(ctx, "must not be null")
-		}
 		return graphql.Null
 	}
-	res := resTmp.(int)
+	res := resTmp.(*models.Workspace)
 	fc.Result = res
-	return ec.marshalNInt2int(ctx, field.Selections, res)
+	return ec.marshalOWorkspace2ᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋcoreapiᚋgraphᚋmodelsᚐWorkspace(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_RunHistoryResult_sizeBytes(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_StepEvent_workspace(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "RunHistoryResult",
+		Object:     "StepEvent",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type Int does not have child fields")
+			switch field.Name {
+			case "id":
+				return ec.fieldContext_Workspace_id(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type Workspace", field.Name)
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _RunHistorySleep_until(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistorySleep) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_RunHistorySleep_until(ctx, field)
+func (ec *executionContext) _StepEvent_functionRun(ctx context.Context, field graphql.CollectedField, obj *models.StepEvent) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_StepEvent_functionRun(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -7564,38 +9318,77 @@ func (ec *executionContext) _RunHistorySleep_until(ctx context.Context, field gr
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Until, nil
+		return obj.FunctionRun, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
-		if !graphql.HasFieldError(ctx, fc) {
-			ec.Errorf(ctx, "must not be null")
-		}
 		return graphql.Null
 	}
-	res := resTmp.(time.Time)
+	res := resTmp.(*models.FunctionRun)
 	fc.Result = res
-	return ec.marshalNTime2timeᚐTime(ctx, field.Selections, res)
+	return ec.marshalOFunctionRun2ᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋcoreapiᚋgraphᚋmodelsᚐFunctionRun(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_RunHistorySleep_until(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_StepEvent_functionRun(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "RunHistorySleep",
+		Object:     "StepEvent",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type Time does not have child fields")
+			switch field.Name {
+			case "id":
+				return ec.fieldContext_FunctionRun_id(ctx, field)
+			case "functionID":
+				return ec.fieldContext_FunctionRun_functionID(ctx, field)
+			case "function":
+				return ec.fieldContext_FunctionRun_function(ctx, field)
+			case "workspace":
+				return ec.fieldContext_FunctionRun_workspace(ctx, field)
+			case "event":
+				return ec.fieldContext_FunctionRun_event(ctx, field)
+			case "events":
+				return ec.fieldContext_FunctionRun_events(ctx, field)
+			case "batchID":
+				return ec.fieldContext_FunctionRun_batchID(ctx, field)
+			case "batchCreatedAt":
+				return ec.fieldContext_FunctionRun_batchCreatedAt(ctx, field)
+			case "status":
+				return ec.fieldContext_FunctionRun_status(ctx, field)
+			case "waitingReason":
+				return ec.fieldContext_FunctionRun_waitingReason(ctx, field)
+			case "waitingFor":
+				return ec.fieldContext_FunctionRun_waitingFor(ctx, field)
+			case "pendingSteps":
+				return ec.fieldContext_FunctionRun_pendingSteps(ctx, field)
+			case "startedAt":
+				return ec.fieldContext_FunctionRun_startedAt(ctx, field)
+			case "finishedAt":
+				return ec.fieldContext_FunctionRun_finishedAt(ctx, field)
+			case "output":
+				return ec.fieldContext_FunctionRun_output(ctx, field)
+			case "history":
+				return ec.fieldContext_FunctionRun_history(ctx, field)
+			case "historyItemOutput":
+				return ec.fieldContext_FunctionRun_historyItemOutput(ctx, field)
+			case "stepLogs":
+				return ec.fieldContext_FunctionRun_stepLogs(ctx, field)
+			case "eventID":
+				return ec.fieldContext_FunctionRun_eventID(ctx, field)
+			case "cron":
+				return ec.fieldContext_FunctionRun_cron(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type FunctionRun", field.Name)
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _RunHistoryWaitForEvent_eventName(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistoryWaitForEvent) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_RunHistoryWaitForEvent_eventName(ctx, field)
+func (ec *executionContext) _StepEvent_stepID(ctx context.Context, field graphql.CollectedField, obj *models.StepEvent) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_StepEvent_stepID(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -7608,26 +9401,23 @@ func (ec *executionContext) _RunHistoryWaitForEvent_eventName(ctx context.Contex
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.EventName, nil
+		return obj.StepID, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
-		if !graphql.HasFieldError(ctx, fc) {
-			ec.Errorf(ctx, "must not be null")
-		}
 		return graphql.Null
 	}
-	res := resTmp.(string)
+	res := resTmp.(*string)
 	fc.Result = res
-	return ec.marshalNString2string(ctx, field.Selections, res)
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_RunHistoryWaitForEvent_eventName(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_StepEvent_stepID(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "RunHistoryWaitForEvent",
+		Object:     "StepEvent",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
@@ -7638,8 +9428,8 @@ func (ec *executionContext) fieldContext_RunHistoryWaitForEvent_eventName(ctx co
 	return fc, nil
 }
 
-func (ec *executionContext) _RunHistoryWaitForEvent_expression(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistoryWaitForEvent) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_RunHistoryWaitForEvent_expression(ctx, field)
+func (ec *executionContext) _StepEvent_name(ctx context.Context, field graphql.CollectedField, obj *models.StepEvent) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_StepEvent_name(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -7652,7 +9442,7 @@ func (ec *executionContext) _RunHistoryWaitForEvent_expression(ctx context.Conte
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Expression, nil
+		return obj.Name, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -7666,9 +9456,9 @@ func (ec *executionContext) _RunHistoryWaitForEvent_expression(ctx context.Conte
 	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_RunHistoryWaitForEvent_expression(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_StepEvent_name(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "RunHistoryWaitForEvent",
+		Object:     "StepEvent",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
@@ -7679,8 +9469,8 @@ func (ec *executionContext) fieldContext_RunHistoryWaitForEvent_expression(ctx c
 	return fc, nil
 }
 
-func (ec *executionContext) _RunHistoryWaitForEvent_timeout(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistoryWaitForEvent) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_RunHistoryWaitForEvent_timeout(ctx, field)
+func (ec *executionContext) _StepEvent_type(ctx context.Context, field graphql.CollectedField, obj *models.StepEvent) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_StepEvent_type(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -7693,38 +9483,35 @@ func (ec *executionContext) _RunHistoryWaitForEvent_timeout(ctx context.Context,
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Timeout, nil
+		return obj.Type, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
-		if !graphql.HasFieldError(ctx, fc) {
-			ec.Errorf(ctx, "must not be null")
-		}
 		return graphql.Null
 	}
-	res := resTmp.(time.Time)
+	res := resTmp.(*models.StepEventType)
 	fc.Result = res
-	return ec.marshalNTime2timeᚐTime(ctx, field.Selections, res)
+	return ec.marshalOStepEventType2ᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋcoreapiᚋgraphᚋmodelsᚐStepEventType(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_RunHistoryWaitForEvent_timeout(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_StepEvent_type(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "RunHistoryWaitForEvent",
+		Object:     "StepEvent",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type Time does not have child fields")
+			return nil, errors.New("field of type StepEventType does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _RunHistoryWaitResult_eventID(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistoryWaitResult) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_RunHistoryWaitResult_eventID(ctx, field)
+func (ec *executionContext) _StepEvent_output(ctx context.Context, field graphql.CollectedField, obj *models.StepEvent) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_StepEvent_output(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -7737,7 +9524,7 @@ func (ec *executionContext) _RunHistoryWaitResult_eventID(ctx context.Context, f
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.EventID, nil
+		return obj.Output, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -7746,26 +9533,26 @@ func (ec *executionContext) _RunHistoryWaitResult_eventID(ctx context.Context, f
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*ulid.ULID)
+	res := resTmp.(*string)
 	fc.Result = res
-	return ec.marshalOULID2ᚖgithubᚗcomᚋoklogᚋulidᚋv2ᚐULID(ctx, field.Selections, res)
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_RunHistoryWaitResult_eventID(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_StepEvent_output(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "RunHistoryWaitResult",
+		Object:     "StepEvent",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type ULID does not have child fields")
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _RunHistoryWaitResult_timeout(ctx context.Context, field graphql.CollectedField, obj *history_reader.RunHistoryWaitResult) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_RunHistoryWaitResult_timeout(ctx, field)
+func (ec *executionContext) _StepEvent_createdAt(ctx context.Context, field graphql.CollectedField, obj *models.StepEvent) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_StepEvent_createdAt(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -7778,38 +9565,35 @@ func (ec *executionContext) _RunHistoryWaitResult_timeout(ctx context.Context, f
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Timeout, nil
+		return obj.CreatedAt, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
-		if !graphql.HasFieldError(ctx, fc) {
-			ec.Errorf(ctx, "must not be null")
-		}
 		return graphql.Null
 	}
-	res := resTmp.(bool)
+	res := resTmp.(*time.Time)
 	fc.Result = res
-	return ec.marshalNBoolean2bool(ctx, field.Selections, res)
+	return ec.marshalOTime2ᚖtimeᚐTime(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_RunHistoryWaitResult_timeout(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_StepEvent_createdAt(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "RunHistoryWaitResult",
+		Object:     "StepEvent",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type Boolean does not have child fields")
+			return nil, errors.New("field of type Time does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _StepEvent_workspace(ctx context.Context, field graphql.CollectedField, obj *models.StepEvent) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_StepEvent_workspace(ctx, field)
+func (ec *executionContext) _StepEvent_waitingFor(ctx context.Context, field graphql.CollectedField, obj *models.StepEvent) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_StepEvent_waitingFor(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -7822,7 +9606,7 @@ func (ec *executionContext) _StepEvent_workspace(ctx context.Context, field grap
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Workspace, nil
+		return obj.WaitingFor, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -7831,12 +9615,12 @@ func (ec *executionContext) _StepEvent_workspace(ctx context.Context, field grap
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*models.Workspace)
+	res := resTmp.(*models.StepEventWait)
 	fc.Result = res
-	return ec.marshalOWorkspace2ᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋcoreapiᚋgraphᚋmodelsᚐWorkspace(ctx, field.Selections, res)
+	return ec.marshalOStepEventWait2ᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋcoreapiᚋgraphᚋmodelsᚐStepEventWait(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_StepEvent_workspace(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_StepEvent_waitingFor(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
 		Object:     "StepEvent",
 		Field:      field,
@@ -7844,17 +9628,21 @@ func (ec *executionContext) fieldContext_StepEvent_workspace(ctx context.Context
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
 			switch field.Name {
-			case "id":
-				return ec.fieldContext_Workspace_id(ctx, field)
+			case "eventName":
+				return ec.fieldContext_StepEventWait_eventName(ctx, field)
+			case "expression":
+				return ec.fieldContext_StepEventWait_expression(ctx, field)
+			case "expiryTime":
+				return ec.fieldContext_StepEventWait_expiryTime(ctx, field)
 			}
-			return nil, fmt.Errorf("no field named %q was found under type Workspace", field.Name)
+			return nil, fmt.Errorf("no field named %q was found under type StepEventWait", field.Name)
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _StepEvent_functionRun(ctx context.Context, field graphql.CollectedField, obj *models.StepEvent) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_StepEvent_functionRun(ctx, field)
+func (ec *executionContext) _StepEventWait_eventName(ctx context.Context, field graphql.CollectedField, obj *models.StepEventWait) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_StepEventWait_eventName(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -7867,7 +9655,7 @@ func (ec *executionContext) _StepEvent_functionRun(ctx context.Context, field gr
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.FunctionRun, nil
+		return obj.EventName, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -7876,64 +9664,26 @@ func (ec *executionContext) _StepEvent_functionRun(ctx context.Context, field gr
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*models.FunctionRun)
+	res := resTmp.(*string)
 	fc.Result = res
-	return ec.marshalOFunctionRun2ᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋcoreapiᚋgraphᚋmodelsᚐFunctionRun(ctx, field.Selections, res)
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_StepEvent_functionRun(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_StepEventWait_eventName(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "StepEvent",
+		Object:     "StepEventWait",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "id":
-				return ec.fieldContext_FunctionRun_id(ctx, field)
-			case "functionID":
-				return ec.fieldContext_FunctionRun_functionID(ctx, field)
-			case "function":
-				return ec.fieldContext_FunctionRun_function(ctx, field)
-			case "workspace":
-				return ec.fieldContext_FunctionRun_workspace(ctx, field)
-			case "event":
-				return ec.fieldContext_FunctionRun_event(ctx, field)
-			case "events":
-				return ec.fieldContext_FunctionRun_events(ctx, field)
-			case "batchID":
-				return ec.fieldContext_FunctionRun_batchID(ctx, field)
-			case "batchCreatedAt":
-				return ec.fieldContext_FunctionRun_batchCreatedAt(ctx, field)
-			case "status":
-				return ec.fieldContext_FunctionRun_status(ctx, field)
-			case "waitingFor":
-				return ec.fieldContext_FunctionRun_waitingFor(ctx, field)
-			case "pendingSteps":
-				return ec.fieldContext_FunctionRun_pendingSteps(ctx, field)
-			case "startedAt":
-				return ec.fieldContext_FunctionRun_startedAt(ctx, field)
-			case "finishedAt":
-				return ec.fieldContext_FunctionRun_finishedAt(ctx, field)
-			case "output":
-				return ec.fieldContext_FunctionRun_output(ctx, field)
-			case "history":
-				return ec.fieldContext_FunctionRun_history(ctx, field)
-			case "historyItemOutput":
-				return ec.fieldContext_FunctionRun_historyItemOutput(ctx, field)
-			case "eventID":
-				return ec.fieldContext_FunctionRun_eventID(ctx, field)
-			case "cron":
-				return ec.fieldContext_FunctionRun_cron(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type FunctionRun", field.Name)
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _StepEvent_stepID(ctx context.Context, field graphql.CollectedField, obj *models.StepEvent) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_StepEvent_stepID(ctx, field)
+func (ec *executionContext) _StepEventWait_expression(ctx context.Context, field graphql.CollectedField, obj *models.StepEventWait) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_StepEventWait_expression(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -7946,7 +9696,7 @@ func (ec *executionContext) _StepEvent_stepID(ctx context.Context, field graphql
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.StepID, nil
+		return obj.Expression, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -7960,9 +9710,9 @@ func (ec *executionContext) _StepEvent_stepID(ctx context.Context, field graphql
 	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_StepEvent_stepID(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_StepEventWait_expression(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "StepEvent",
+		Object:     "StepEventWait",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
@@ -7973,8 +9723,8 @@ func (ec *executionContext) fieldContext_StepEvent_stepID(ctx context.Context, f
 	return fc, nil
 }
 
-func (ec *executionContext) _StepEvent_name(ctx context.Context, field graphql.CollectedField, obj *models.StepEvent) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_StepEvent_name(ctx, field)
+func (ec *executionContext) _StepEventWait_expiryTime(ctx context.Context, field graphql.CollectedField, obj *models.StepEventWait) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_StepEventWait_expiryTime(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -7987,35 +9737,38 @@ func (ec *executionContext) _StepEvent_name(ctx context.Context, field graphql.C
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Name, nil
+		return obj.ExpiryTime, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
 		return graphql.Null
 	}
-	res := resTmp.(*string)
+	res := resTmp.(time.Time)
 	fc.Result = res
-	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+	return ec.marshalNTime2timeᚐTime(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_StepEvent_name(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_StepEventWait_expiryTime(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "StepEvent",
+		Object:     "StepEventWait",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type String does not have child fields")
+			return nil, errors.New("field of type Time does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _StepEvent_type(ctx context.Context, field graphql.CollectedField, obj *models.StepEvent) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_StepEvent_type(ctx, field)
+func (ec *executionContext) _StepLog_runID(ctx context.Context, field graphql.CollectedField, obj *cqrs.StepLog) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_StepLog_runID(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -8028,35 +9781,38 @@ func (ec *executionContext) _StepEvent_type(ctx context.Context, field graphql.C
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Type, nil
+		return obj.RunID, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
 		return graphql.Null
 	}
-	res := resTmp.(*models.StepEventType)
+	res := resTmp.(ulid.ULID)
 	fc.Result = res
-	return ec.marshalOStepEventType2ᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋcoreapiᚋgraphᚋmodelsᚐStepEventType(ctx, field.Selections, res)
+	return ec.marshalNULID2githubᚗcomᚋoklogᚋulidᚋv2ᚐULID(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_StepEvent_type(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_StepLog_runID(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "StepEvent",
+		Object:     "StepLog",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type StepEventType does not have child fields")
+			return nil, errors.New("field of type ULID does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _StepEvent_output(ctx context.Context, field graphql.CollectedField, obj *models.StepEvent) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_StepEvent_output(ctx, field)
+func (ec *executionContext) _StepLog_stepID(ctx context.Context, field graphql.CollectedField, obj *cqrs.StepLog) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_StepLog_stepID(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -8069,23 +9825,26 @@ func (ec *executionContext) _StepEvent_output(ctx context.Context, field graphql
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Output, nil
+		return obj.StepID, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
 		return graphql.Null
 	}
-	res := resTmp.(*string)
+	res := resTmp.(string)
 	fc.Result = res
-	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+	return ec.marshalNString2string(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_StepEvent_output(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_StepLog_stepID(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "StepEvent",
+		Object:     "StepLog",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
@@ -8096,8 +9855,8 @@ func (ec *executionContext) fieldContext_StepEvent_output(ctx context.Context, f
 	return fc, nil
 }
 
-func (ec *executionContext) _StepEvent_createdAt(ctx context.Context, field graphql.CollectedField, obj *models.StepEvent) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_StepEvent_createdAt(ctx, field)
+func (ec *executionContext) _StepLog_attempt(ctx context.Context, field graphql.CollectedField, obj *cqrs.StepLog) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_StepLog_attempt(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -8110,35 +9869,38 @@ func (ec *executionContext) _StepEvent_createdAt(ctx context.Context, field grap
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.CreatedAt, nil
+		return obj.Attempt, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
 		return graphql.Null
 	}
-	res := resTmp.(*time.Time)
+	res := resTmp.(int)
 	fc.Result = res
-	return ec.marshalOTime2ᚖtimeᚐTime(ctx, field.Selections, res)
+	return ec.marshalNInt2int(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_StepEvent_createdAt(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_StepLog_attempt(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "StepEvent",
+		Object:     "StepLog",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type Time does not have child fields")
+			return nil, errors.New("field of type Int does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _StepEvent_waitingFor(ctx context.Context, field graphql.CollectedField, obj *models.StepEvent) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_StepEvent_waitingFor(ctx, field)
+func (ec *executionContext) _StepLog_level(ctx context.Context, field graphql.CollectedField, obj *cqrs.StepLog) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_StepLog_level(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -8151,43 +9913,38 @@ func (ec *executionContext) _StepEvent_waitingFor(ctx context.Context, field gra
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.WaitingFor, nil
+		return obj.Level, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
 		return graphql.Null
 	}
-	res := resTmp.(*models.StepEventWait)
+	res := resTmp.(string)
 	fc.Result = res
-	return ec.marshalOStepEventWait2ᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋcoreapiᚋgraphᚋmodelsᚐStepEventWait(ctx, field.Selections, res)
+	return ec.marshalNString2string(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_StepEvent_waitingFor(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_StepLog_level(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "StepEvent",
+		Object:     "StepLog",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			switch field.Name {
-			case "eventName":
-				return ec.fieldContext_StepEventWait_eventName(ctx, field)
-			case "expression":
-				return ec.fieldContext_StepEventWait_expression(ctx, field)
-			case "expiryTime":
-				return ec.fieldContext_StepEventWait_expiryTime(ctx, field)
-			}
-			return nil, fmt.Errorf("no field named %q was found under type StepEventWait", field.Name)
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _StepEventWait_eventName(ctx context.Context, field graphql.CollectedField, obj *models.StepEventWait) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_StepEventWait_eventName(ctx, field)
+func (ec *executionContext) _StepLog_message(ctx context.Context, field graphql.CollectedField, obj *cqrs.StepLog) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_StepLog_message(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -8200,23 +9957,26 @@ func (ec *executionContext) _StepEventWait_eventName(ctx context.Context, field
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.EventName, nil
+		return obj.Message, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
 		return graphql.Null
 	}
-	res := resTmp.(*string)
+	res := resTmp.(string)
 	fc.Result = res
-	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+	return ec.marshalNString2string(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_StepEventWait_eventName(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_StepLog_message(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "StepEventWait",
+		Object:     "StepLog",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
@@ -8227,8 +9987,8 @@ func (ec *executionContext) fieldContext_StepEventWait_eventName(ctx context.Con
 	return fc, nil
 }
 
-func (ec *executionContext) _StepEventWait_expression(ctx context.Context, field graphql.CollectedField, obj *models.StepEventWait) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_StepEventWait_expression(ctx, field)
+func (ec *executionContext) _StepLog_fields(ctx context.Context, field graphql.CollectedField, obj *cqrs.StepLog) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_StepLog_fields(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -8241,7 +10001,7 @@ func (ec *executionContext) _StepEventWait_expression(ctx context.Context, field
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Expression, nil
+		return obj.Fields, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -8250,26 +10010,26 @@ func (ec *executionContext) _StepEventWait_expression(ctx context.Context, field
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*string)
+	res := resTmp.(map[string]interface{})
 	fc.Result = res
-	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+	return ec.marshalOMap2map(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_StepEventWait_expression(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_StepLog_fields(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "StepEventWait",
+		Object:     "StepLog",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type String does not have child fields")
+			return nil, errors.New("field of type Map does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _StepEventWait_expiryTime(ctx context.Context, field graphql.CollectedField, obj *models.StepEventWait) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_StepEventWait_expiryTime(ctx, field)
+func (ec *executionContext) _StepLog_timestamp(ctx context.Context, field graphql.CollectedField, obj *cqrs.StepLog) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_StepLog_timestamp(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -8282,7 +10042,7 @@ func (ec *executionContext) _StepEventWait_expiryTime(ctx context.Context, field
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.ExpiryTime, nil
+		return obj.Timestamp, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -8299,9 +10059,9 @@ func (ec *executionContext) _StepEventWait_expiryTime(ctx context.Context, field
 	return ec.marshalNTime2timeᚐTime(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_StepEventWait_expiryTime(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_StepLog_timestamp(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "StepEventWait",
+		Object:     "StepLog",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
@@ -8542,6 +10302,8 @@ func (ec *executionContext) fieldContext_StreamItem_runs(ctx context.Context, fi
 				return ec.fieldContext_FunctionRun_batchCreatedAt(ctx, field)
 			case "status":
 				return ec.fieldContext_FunctionRun_status(ctx, field)
+			case "waitingReason":
+				return ec.fieldContext_FunctionRun_waitingReason(ctx, field)
 			case "waitingFor":
 				return ec.fieldContext_FunctionRun_waitingFor(ctx, field)
 			case "pendingSteps":
@@ -8556,6 +10318,8 @@ func (ec *executionContext) fieldContext_StreamItem_runs(ctx context.Context, fi
 				return ec.fieldContext_FunctionRun_history(ctx, field)
 			case "historyItemOutput":
 				return ec.fieldContext_FunctionRun_historyItemOutput(ctx, field)
+			case "stepLogs":
+				return ec.fieldContext_FunctionRun_stepLogs(ctx, field)
 			case "eventID":
 				return ec.fieldContext_FunctionRun_eventID(ctx, field)
 			case "cron":
@@ -10606,11 +12370,87 @@ func (ec *executionContext) unmarshalInputFunctionRunQuery(ctx context.Context,
 			if err != nil {
 				return it, err
 			}
-		case "functionRunId":
+		case "functionRunId":
+			var err error
+
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("functionRunId"))
+			it.FunctionRunID, err = ec.unmarshalNID2string(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		}
+	}
+
+	return it, nil
+}
+
+func (ec *executionContext) unmarshalInputFunctionRunsQuery(ctx context.Context, obj interface{}) (models.FunctionRunsQuery, error) {
+	var it models.FunctionRunsQuery
+	asMap := map[string]interface{}{}
+	for k, v := range obj.(map[string]interface{}) {
+		asMap[k] = v
+	}
+
+	if _, present := asMap["workspaceId"]; !present {
+		asMap["workspaceId"] = "local"
+	}
+
+	fieldsInOrder := [...]string{"workspaceId"}
+	for _, k := range fieldsInOrder {
+		v, ok := asMap[k]
+		if !ok {
+			continue
+		}
+		switch k {
+		case "workspaceId":
+			var err error
+
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("workspaceId"))
+			it.WorkspaceID, err = ec.unmarshalNID2string(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		}
+	}
+
+	return it, nil
+}
+
+func (ec *executionContext) unmarshalInputFunctionThrottleInput(ctx context.Context, obj interface{}) (models.FunctionThrottleInput, error) {
+	var it models.FunctionThrottleInput
+	asMap := map[string]interface{}{}
+	for k, v := range obj.(map[string]interface{}) {
+		asMap[k] = v
+	}
+
+	fieldsInOrder := [...]string{"limit", "burst", "period"}
+	for _, k := range fieldsInOrder {
+		v, ok := asMap[k]
+		if !ok {
+			continue
+		}
+		switch k {
+		case "limit":
+			var err error
+
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("limit"))
+			it.Limit, err = ec.unmarshalNInt2int(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		case "burst":
+			var err error
+
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("burst"))
+			it.Burst, err = ec.unmarshalOInt2ᚖint(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		case "period":
 			var err error
 
-			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("functionRunId"))
-			it.FunctionRunID, err = ec.unmarshalNID2string(ctx, v)
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("period"))
+			it.Period, err = ec.unmarshalNString2string(ctx, v)
 			if err != nil {
 				return it, err
 			}
@@ -10620,8 +12460,8 @@ func (ec *executionContext) unmarshalInputFunctionRunQuery(ctx context.Context,
 	return it, nil
 }
 
-func (ec *executionContext) unmarshalInputFunctionRunsQuery(ctx context.Context, obj interface{}) (models.FunctionRunsQuery, error) {
-	var it models.FunctionRunsQuery
+func (ec *executionContext) unmarshalInputSearchFunctionRunsQuery(ctx context.Context, obj interface{}) (models.SearchFunctionRunsQuery, error) {
+	var it models.SearchFunctionRunsQuery
 	asMap := map[string]interface{}{}
 	for k, v := range obj.(map[string]interface{}) {
 		asMap[k] = v
@@ -10630,8 +12470,14 @@ func (ec *executionContext) unmarshalInputFunctionRunsQuery(ctx context.Context,
 	if _, present := asMap["workspaceId"]; !present {
 		asMap["workspaceId"] = "local"
 	}
+	if _, present := asMap["order"]; !present {
+		asMap["order"] = "DESC"
+	}
+	if _, present := asMap["limit"]; !present {
+		asMap["limit"] = 20
+	}
 
-	fieldsInOrder := [...]string{"workspaceId"}
+	fieldsInOrder := [...]string{"workspaceId", "expression", "functionId", "status", "startedAfter", "startedBefore", "cursor", "order", "limit"}
 	for _, k := range fieldsInOrder {
 		v, ok := asMap[k]
 		if !ok {
@@ -10646,6 +12492,70 @@ func (ec *executionContext) unmarshalInputFunctionRunsQuery(ctx context.Context,
 			if err != nil {
 				return it, err
 			}
+		case "expression":
+			var err error
+
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("expression"))
+			it.Expression, err = ec.unmarshalOString2ᚖstring(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		case "functionId":
+			var err error
+
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("functionId"))
+			it.FunctionID, err = ec.unmarshalOID2ᚖstring(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		case "status":
+			var err error
+
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("status"))
+			it.Status, err = ec.unmarshalOFunctionRunStatus2ᚕgithubᚗcomᚋinngestᚋinngestᚋpkgᚋcoreapiᚋgraphᚋmodelsᚐFunctionRunStatusᚄ(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		case "startedAfter":
+			var err error
+
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("startedAfter"))
+			it.StartedAfter, err = ec.unmarshalOTime2ᚖtimeᚐTime(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		case "startedBefore":
+			var err error
+
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("startedBefore"))
+			it.StartedBefore, err = ec.unmarshalOTime2ᚖtimeᚐTime(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		case "cursor":
+			var err error
+
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("cursor"))
+			it.Cursor, err = ec.unmarshalOID2ᚖstring(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		case "order":
+			var err error
+
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("order"))
+			it.Order, err = ec.unmarshalNSortOrder2githubᚗcomᚋinngestᚋinngestᚋpkgᚋcoreapiᚋgraphᚋmodelsᚐSortOrder(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		case "limit":
+			var err error
+
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("limit"))
+			it.Limit, err = ec.unmarshalNInt2int(ctx, v)
+			if err != nil {
+				return it, err
+			}
 		}
 	}
 
@@ -11175,6 +13085,26 @@ func (ec *executionContext) _Function(ctx context.Context, sel ast.SelectionSet,
 				return res
 			}
 
+			out.Concurrently(i, func() graphql.Marshaler {
+				return innerFunc(ctx)
+
+			})
+		case "queue":
+			field := field
+
+			innerFunc := func(ctx context.Context) (res graphql.Marshaler) {
+				defer func() {
+					if r := recover(); r != nil {
+						ec.Error(ctx, ec.Recover(ctx, r))
+					}
+				}()
+				res = ec._Function_queue(ctx, field, obj)
+				if res == graphql.Null {
+					atomic.AddUint32(&invalids, 1)
+				}
+				return res
+			}
+
 			out.Concurrently(i, func() graphql.Marshaler {
 				return innerFunc(ctx)
 
@@ -11231,6 +13161,73 @@ func (ec *executionContext) _FunctionEvent(ctx context.Context, sel ast.Selectio
 	return out
 }
 
+var functionQueueImplementors = []string{"FunctionQueue"}
+
+func (ec *executionContext) _FunctionQueue(ctx context.Context, sel ast.SelectionSet, obj *queue.PartitionStats) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, functionQueueImplementors)
+	out := graphql.NewFieldSet(fields)
+	var invalids uint32
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("FunctionQueue")
+		case "ready":
+
+			out.Values[i] = ec._FunctionQueue_ready(ctx, field, obj)
+
+			if out.Values[i] == graphql.Null {
+				invalids++
+			}
+		case "scheduled":
+
+			out.Values[i] = ec._FunctionQueue_scheduled(ctx, field, obj)
+
+			if out.Values[i] == graphql.Null {
+				invalids++
+			}
+		case "inProgress":
+
+			out.Values[i] = ec._FunctionQueue_inProgress(ctx, field, obj)
+
+			if out.Values[i] == graphql.Null {
+				invalids++
+			}
+		case "concurrencyLimit":
+
+			out.Values[i] = ec._FunctionQueue_concurrencyLimit(ctx, field, obj)
+
+			if out.Values[i] == graphql.Null {
+				invalids++
+			}
+		case "concurrencyLimited":
+
+			out.Values[i] = ec._FunctionQueue_concurrencyLimited(ctx, field, obj)
+
+			if out.Values[i] == graphql.Null {
+				invalids++
+			}
+		case "oldestItemAt":
+
+			out.Values[i] = ec._FunctionQueue_oldestItemAt(ctx, field, obj)
+
+		case "paused":
+
+			out.Values[i] = ec._FunctionQueue_paused(ctx, field, obj)
+
+			if out.Values[i] == graphql.Null {
+				invalids++
+			}
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+	out.Dispatch()
+	if invalids > 0 {
+		return graphql.Null
+	}
+	return out
+}
+
 var functionRunImplementors = []string{"FunctionRun"}
 
 func (ec *executionContext) _FunctionRun(ctx context.Context, sel ast.SelectionSet, obj *models.FunctionRun) graphql.Marshaler {
@@ -11338,6 +13335,10 @@ func (ec *executionContext) _FunctionRun(ctx context.Context, sel ast.SelectionS
 
 			out.Values[i] = ec._FunctionRun_status(ctx, field, obj)
 
+		case "waitingReason":
+
+			out.Values[i] = ec._FunctionRun_waitingReason(ctx, field, obj)
+
 		case "waitingFor":
 			field := field
 
@@ -11417,6 +13418,26 @@ func (ec *executionContext) _FunctionRun(ctx context.Context, sel ast.SelectionS
 				return res
 			}
 
+			out.Concurrently(i, func() graphql.Marshaler {
+				return innerFunc(ctx)
+
+			})
+		case "stepLogs":
+			field := field
+
+			innerFunc := func(ctx context.Context) (res graphql.Marshaler) {
+				defer func() {
+					if r := recover(); r != nil {
+						ec.Error(ctx, ec.Recover(ctx, r))
+					}
+				}()
+				res = ec._FunctionRun_stepLogs(ctx, field, obj)
+				if res == graphql.Null {
+					atomic.AddUint32(&invalids, 1)
+				}
+				return res
+			}
+
 			out.Concurrently(i, func() graphql.Marshaler {
 				return innerFunc(ctx)
 
@@ -11443,6 +13464,38 @@ func (ec *executionContext) _FunctionRun(ctx context.Context, sel ast.SelectionS
 	return out
 }
 
+var functionRunSearchResultImplementors = []string{"FunctionRunSearchResult"}
+
+func (ec *executionContext) _FunctionRunSearchResult(ctx context.Context, sel ast.SelectionSet, obj *models.FunctionRunSearchResult) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, functionRunSearchResultImplementors)
+	out := graphql.NewFieldSet(fields)
+	var invalids uint32
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("FunctionRunSearchResult")
+		case "runs":
+
+			out.Values[i] = ec._FunctionRunSearchResult_runs(ctx, field, obj)
+
+			if out.Values[i] == graphql.Null {
+				invalids++
+			}
+		case "cursor":
+
+			out.Values[i] = ec._FunctionRunSearchResult_cursor(ctx, field, obj)
+
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+	out.Dispatch()
+	if invalids > 0 {
+		return graphql.Null
+	}
+	return out
+}
+
 var functionTriggerImplementors = []string{"FunctionTrigger"}
 
 func (ec *executionContext) _FunctionTrigger(ctx context.Context, sel ast.SelectionSet, obj *models.FunctionTrigger) graphql.Marshaler {
@@ -11618,6 +13671,51 @@ func (ec *executionContext) _Mutation(ctx context.Context, sel ast.SelectionSet)
 				return ec._Mutation_rerun(ctx, field)
 			})
 
+			if out.Values[i] == graphql.Null {
+				invalids++
+			}
+		case "requeueStep":
+
+			out.Values[i] = ec.OperationContext.RootResolverMiddleware(innerCtx, func(ctx context.Context) (res graphql.Marshaler) {
+				return ec._Mutation_requeueStep(ctx, field)
+			})
+
+			if out.Values[i] == graphql.Null {
+				invalids++
+			}
+		case "boostRunPriority":
+
+			out.Values[i] = ec.OperationContext.RootResolverMiddleware(innerCtx, func(ctx context.Context) (res graphql.Marshaler) {
+				return ec._Mutation_boostRunPriority(ctx, field)
+			})
+
+			if out.Values[i] == graphql.Null {
+				invalids++
+			}
+		case "updateFunctionThrottle":
+
+			out.Values[i] = ec.OperationContext.RootResolverMiddleware(innerCtx, func(ctx context.Context) (res graphql.Marshaler) {
+				return ec._Mutation_updateFunctionThrottle(ctx, field)
+			})
+
+			if out.Values[i] == graphql.Null {
+				invalids++
+			}
+		case "pauseFunctionQueue":
+
+			out.Values[i] = ec.OperationContext.RootResolverMiddleware(innerCtx, func(ctx context.Context) (res graphql.Marshaler) {
+				return ec._Mutation_pauseFunctionQueue(ctx, field)
+			})
+
+			if out.Values[i] == graphql.Null {
+				invalids++
+			}
+		case "resumeFunctionQueue":
+
+			out.Values[i] = ec.OperationContext.RootResolverMiddleware(innerCtx, func(ctx context.Context) (res graphql.Marshaler) {
+				return ec._Mutation_resumeFunctionQueue(ctx, field)
+			})
+
 			if out.Values[i] == graphql.Null {
 				invalids++
 			}
@@ -11774,6 +13872,29 @@ func (ec *executionContext) _Query(ctx context.Context, sel ast.SelectionSet) gr
 				return ec.OperationContext.RootResolverMiddleware(ctx, innerFunc)
 			}
 
+			out.Concurrently(i, func() graphql.Marshaler {
+				return rrm(innerCtx)
+			})
+		case "searchFunctionRuns":
+			field := field
+
+			innerFunc := func(ctx context.Context) (res graphql.Marshaler) {
+				defer func() {
+					if r := recover(); r != nil {
+						ec.Error(ctx, ec.Recover(ctx, r))
+					}
+				}()
+				res = ec._Query_searchFunctionRuns(ctx, field)
+				if res == graphql.Null {
+					atomic.AddUint32(&invalids, 1)
+				}
+				return res
+			}
+
+			rrm := func(ctx context.Context) graphql.Marshaler {
+				return ec.OperationContext.RootResolverMiddleware(ctx, innerFunc)
+			}
+
 			out.Concurrently(i, func() graphql.Marshaler {
 				return rrm(innerCtx)
 			})
@@ -12216,10 +14337,46 @@ func (ec *executionContext) _StepEvent(ctx context.Context, sel ast.SelectionSet
 
 			out.Values[i] = ec._StepEvent_createdAt(ctx, field, obj)
 
-		case "waitingFor":
+		case "waitingFor":
+
+			out.Values[i] = ec._StepEvent_waitingFor(ctx, field, obj)
+
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+	out.Dispatch()
+	if invalids > 0 {
+		return graphql.Null
+	}
+	return out
+}
+
+var stepEventWaitImplementors = []string{"StepEventWait"}
+
+func (ec *executionContext) _StepEventWait(ctx context.Context, sel ast.SelectionSet, obj *models.StepEventWait) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, stepEventWaitImplementors)
+	out := graphql.NewFieldSet(fields)
+	var invalids uint32
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("StepEventWait")
+		case "eventName":
+
+			out.Values[i] = ec._StepEventWait_eventName(ctx, field, obj)
+
+		case "expression":
+
+			out.Values[i] = ec._StepEventWait_expression(ctx, field, obj)
+
+		case "expiryTime":
 
-			out.Values[i] = ec._StepEvent_waitingFor(ctx, field, obj)
+			out.Values[i] = ec._StepEventWait_expiryTime(ctx, field, obj)
 
+			if out.Values[i] == graphql.Null {
+				invalids++
+			}
 		default:
 			panic("unknown field " + strconv.Quote(field.Name))
 		}
@@ -12231,27 +14388,58 @@ func (ec *executionContext) _StepEvent(ctx context.Context, sel ast.SelectionSet
 	return out
 }
 
-var stepEventWaitImplementors = []string{"StepEventWait"}
+var stepLogImplementors = []string{"StepLog"}
 
-func (ec *executionContext) _StepEventWait(ctx context.Context, sel ast.SelectionSet, obj *models.StepEventWait) graphql.Marshaler {
-	fields := graphql.CollectFields(ec.OperationContext, sel, stepEventWaitImplementors)
+func (ec *executionContext) _StepLog(ctx context.Context, sel ast.SelectionSet, obj *cqrs.StepLog) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, stepLogImplementors)
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
 	for i, field := range fields {
 		switch field.Name {
 		case "__typename":
-			out.Values[i] = graphql.MarshalString("StepEventWait")
-		case "eventName":
+			out.Values[i] = graphql.MarshalString("StepLog")
+		case "runID":
 
-			out.Values[i] = ec._StepEventWait_eventName(ctx, field, obj)
+			out.Values[i] = ec._StepLog_runID(ctx, field, obj)
 
-		case "expression":
+			if out.Values[i] == graphql.Null {
+				invalids++
+			}
+		case "stepID":
 
-			out.Values[i] = ec._StepEventWait_expression(ctx, field, obj)
+			out.Values[i] = ec._StepLog_stepID(ctx, field, obj)
 
-		case "expiryTime":
+			if out.Values[i] == graphql.Null {
+				invalids++
+			}
+		case "attempt":
 
-			out.Values[i] = ec._StepEventWait_expiryTime(ctx, field, obj)
+			out.Values[i] = ec._StepLog_attempt(ctx, field, obj)
+
+			if out.Values[i] == graphql.Null {
+				invalids++
+			}
+		case "level":
+
+			out.Values[i] = ec._StepLog_level(ctx, field, obj)
+
+			if out.Values[i] == graphql.Null {
+				invalids++
+			}
+		case "message":
+
+			out.Values[i] = ec._StepLog_message(ctx, field, obj)
+
+			if out.Values[i] == graphql.Null {
+				invalids++
+			}
+		case "fields":
+
+			out.Values[i] = ec._StepLog_fields(ctx, field, obj)
+
+		case "timestamp":
+
+			out.Values[i] = ec._StepLog_timestamp(ctx, field, obj)
 
 			if out.Values[i] == graphql.Null {
 				invalids++
@@ -12882,10 +15070,68 @@ func (ec *executionContext) marshalNFunction2ᚖgithubᚗcomᚋinngestᚋinngest
 	return ec._Function(ctx, sel, v)
 }
 
+func (ec *executionContext) marshalNFunctionQueue2githubᚗcomᚋinngestᚋinngestᚋpkgᚋexecutionᚋqueueᚐPartitionStats(ctx context.Context, sel ast.SelectionSet, v queue.PartitionStats) graphql.Marshaler {
+	return ec._FunctionQueue(ctx, sel, &v)
+}
+
+func (ec *executionContext) marshalNFunctionQueue2ᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋexecutionᚋqueueᚐPartitionStats(ctx context.Context, sel ast.SelectionSet, v *queue.PartitionStats) graphql.Marshaler {
+	if v == nil {
+		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
+			ec.Errorf(ctx, "the requested element is null which the schema does not allow")
+		}
+		return graphql.Null
+	}
+	return ec._FunctionQueue(ctx, sel, v)
+}
+
 func (ec *executionContext) marshalNFunctionRun2githubᚗcomᚋinngestᚋinngestᚋpkgᚋcoreapiᚋgraphᚋmodelsᚐFunctionRun(ctx context.Context, sel ast.SelectionSet, v models.FunctionRun) graphql.Marshaler {
 	return ec._FunctionRun(ctx, sel, &v)
 }
 
+func (ec *executionContext) marshalNFunctionRun2ᚕᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋcoreapiᚋgraphᚋmodelsᚐFunctionRunᚄ(ctx context.Context, sel ast.SelectionSet, v []*models.FunctionRun) graphql.Marshaler {
+	ret := make(graphql.Array, len(v))
+	var wg sync.WaitGroup
+	isLen1 := len(v) == 1
+	if !isLen1 {
+		wg.Add(len(v))
+	}
+	for i := range v {
+		i := i
+		fc := &graphql.FieldContext{
+			Index:  &i,
+			Result: &v[i],
+		}
+		ctx := graphql.WithFieldContext(ctx, fc)
+		f := func(i int) {
+			defer func() {
+				if r := recover(); r != nil {
+					ec.Error(ctx, ec.Recover(ctx, r))
+					ret = nil
+				}
+			}()
+			if !isLen1 {
+				defer wg.Done()
+			}
+			ret[i] = ec.marshalNFunctionRun2ᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋcoreapiᚋgraphᚋmodelsᚐFunctionRun(ctx, sel, v[i])
+		}
+		if isLen1 {
+			f(i)
+		} else {
+			go f(i)
+		}
+
+	}
+	wg.Wait()
+
+	for _, e := range ret {
+		if e == graphql.Null {
+			return graphql.Null
+		}
+	}
+
+	return ret
+}
+
 func (ec *executionContext) marshalNFunctionRun2ᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋcoreapiᚋgraphᚋmodelsᚐFunctionRun(ctx context.Context, sel ast.SelectionSet, v *models.FunctionRun) graphql.Marshaler {
 	if v == nil {
 		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
@@ -12901,6 +15147,30 @@ func (ec *executionContext) unmarshalNFunctionRunQuery2githubᚗcomᚋinngestᚋ
 	return res, graphql.ErrorOnPath(ctx, err)
 }
 
+func (ec *executionContext) unmarshalNFunctionRunStatus2githubᚗcomᚋinngestᚋinngestᚋpkgᚋcoreapiᚋgraphᚋmodelsᚐFunctionRunStatus(ctx context.Context, v interface{}) (models.FunctionRunStatus, error) {
+	var res models.FunctionRunStatus
+	err := res.UnmarshalGQL(v)
+	return res, graphql.ErrorOnPath(ctx, err)
+}
+
+func (ec *executionContext) marshalNFunctionRunStatus2githubᚗcomᚋinngestᚋinngestᚋpkgᚋcoreapiᚋgraphᚋmodelsᚐFunctionRunStatus(ctx context.Context, sel ast.SelectionSet, v models.FunctionRunStatus) graphql.Marshaler {
+	return v
+}
+
+func (ec *executionContext) marshalNFunctionRunSearchResult2githubᚗcomᚋinngestᚋinngestᚋpkgᚋcoreapiᚋgraphᚋmodelsᚐFunctionRunSearchResult(ctx context.Context, sel ast.SelectionSet, v models.FunctionRunSearchResult) graphql.Marshaler {
+	return ec._FunctionRunSearchResult(ctx, sel, &v)
+}
+
+func (ec *executionContext) marshalNFunctionRunSearchResult2ᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋcoreapiᚋgraphᚋmodelsᚐFunctionRunSearchResult(ctx context.Context, sel ast.SelectionSet, v *models.FunctionRunSearchResult) graphql.Marshaler {
+	if v == nil {
+		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
+			ec.Errorf(ctx, "the requested element is null which the schema does not allow")
+		}
+		return graphql.Null
+	}
+	return ec._FunctionRunSearchResult(ctx, sel, v)
+}
+
 func (ec *executionContext) marshalNFunctionTrigger2ᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋcoreapiᚋgraphᚋmodelsᚐFunctionTrigger(ctx context.Context, sel ast.SelectionSet, v *models.FunctionTrigger) graphql.Marshaler {
 	if v == nil {
 		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
@@ -13030,6 +15300,75 @@ func (ec *executionContext) marshalNRunHistoryItem2ᚖgithubᚗcomᚋinngestᚋi
 	return ec._RunHistoryItem(ctx, sel, v)
 }
 
+func (ec *executionContext) unmarshalNSearchFunctionRunsQuery2githubᚗcomᚋinngestᚋinngestᚋpkgᚋcoreapiᚋgraphᚋmodelsᚐSearchFunctionRunsQuery(ctx context.Context, v interface{}) (models.SearchFunctionRunsQuery, error) {
+	res, err := ec.unmarshalInputSearchFunctionRunsQuery(ctx, v)
+	return res, graphql.ErrorOnPath(ctx, err)
+}
+
+func (ec *executionContext) unmarshalNSortOrder2githubᚗcomᚋinngestᚋinngestᚋpkgᚋcoreapiᚋgraphᚋmodelsᚐSortOrder(ctx context.Context, v interface{}) (models.SortOrder, error) {
+	var res models.SortOrder
+	err := res.UnmarshalGQL(v)
+	return res, graphql.ErrorOnPath(ctx, err)
+}
+
+func (ec *executionContext) marshalNSortOrder2githubᚗcomᚋinngestᚋinngestᚋpkgᚋcoreapiᚋgraphᚋmodelsᚐSortOrder(ctx context.Context, sel ast.SelectionSet, v models.SortOrder) graphql.Marshaler {
+	return v
+}
+
+func (ec *executionContext) marshalNStepLog2ᚕᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋcqrsᚐStepLogᚄ(ctx context.Context, sel ast.SelectionSet, v []*cqrs.StepLog) graphql.Marshaler {
+	ret := make(graphql.Array, len(v))
+	var wg sync.WaitGroup
+	isLen1 := len(v) == 1
+	if !isLen1 {
+		wg.Add(len(v))
+	}
+	for i := range v {
+		i := i
+		fc := &graphql.FieldContext{
+			Index:  &i,
+			Result: &v[i],
+		}
+		ctx := graphql.WithFieldContext(ctx, fc)
+		f := func(i int) {
+			defer func() {
+				if r := recover(); r != nil {
+					ec.Error(ctx, ec.Recover(ctx, r))
+					ret = nil
+				}
+			}()
+			if !isLen1 {
+				defer wg.Done()
+			}
+			ret[i] = ec.marshalNStepLog2ᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋcqrsᚐStepLog(ctx, sel, v[i])
+		}
+		if isLen1 {
+			f(i)
+		} else {
+			go f(i)
+		}
+
+	}
+	wg.Wait()
+
+	for _, e := range ret {
+		if e == graphql.Null {
+			return graphql.Null
+		}
+	}
+
+	return ret
+}
+
+func (ec *executionContext) marshalNStepLog2ᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋcqrsᚐStepLog(ctx context.Context, sel ast.SelectionSet, v *cqrs.StepLog) graphql.Marshaler {
+	if v == nil {
+		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
+			ec.Errorf(ctx, "the requested element is null which the schema does not allow")
+		}
+		return graphql.Null
+	}
+	return ec._StepLog(ctx, sel, v)
+}
+
 func (ec *executionContext) marshalNStreamItem2ᚕᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋcoreapiᚋgraphᚋmodelsᚐStreamItemᚄ(ctx context.Context, sel ast.SelectionSet, v []*models.StreamItem) graphql.Marshaler {
 	ret := make(graphql.Array, len(v))
 	var wg sync.WaitGroup
@@ -13678,6 +16017,73 @@ func (ec *executionContext) marshalOFunctionRun2ᚖgithubᚗcomᚋinngestᚋinng
 	return ec._FunctionRun(ctx, sel, v)
 }
 
+func (ec *executionContext) unmarshalOFunctionRunStatus2ᚕgithubᚗcomᚋinngestᚋinngestᚋpkgᚋcoreapiᚋgraphᚋmodelsᚐFunctionRunStatusᚄ(ctx context.Context, v interface{}) ([]models.FunctionRunStatus, error) {
+	if v == nil {
+		return nil, nil
+	}
+	var vSlice []interface{}
+	if v != nil {
+		vSlice = graphql.CoerceList(v)
+	}
+	var err error
+	res := make([]models.FunctionRunStatus, len(vSlice))
+	for i := range vSlice {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithIndex(i))
+		res[i], err = ec.unmarshalNFunctionRunStatus2githubᚗcomᚋinngestᚋinngestᚋpkgᚋcoreapiᚋgraphᚋmodelsᚐFunctionRunStatus(ctx, vSlice[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+func (ec *executionContext) marshalOFunctionRunStatus2ᚕgithubᚗcomᚋinngestᚋinngestᚋpkgᚋcoreapiᚋgraphᚋmodelsᚐFunctionRunStatusᚄ(ctx context.Context, sel ast.SelectionSet, v []models.FunctionRunStatus) graphql.Marshaler {
+	if v == nil {
+		return graphql.Null
+	}
+	ret := make(graphql.Array, len(v))
+	var wg sync.WaitGroup
+	isLen1 := len(v) == 1
+	if !isLen1 {
+		wg.Add(len(v))
+	}
+	for i := range v {
+		i := i
+		fc := &graphql.FieldContext{
+			Index:  &i,
+			Result: &v[i],
+		}
+		ctx := graphql.WithFieldContext(ctx, fc)
+		f := func(i int) {
+			defer func() {
+				if r := recover(); r != nil {
+					ec.Error(ctx, ec.Recover(ctx, r))
+					ret = nil
+				}
+			}()
+			if !isLen1 {
+				defer wg.Done()
+			}
+			ret[i] = ec.marshalNFunctionRunStatus2githubᚗcomᚋinngestᚋinngestᚋpkgᚋcoreapiᚋgraphᚋmodelsᚐFunctionRunStatus(ctx, sel, v[i])
+		}
+		if isLen1 {
+			f(i)
+		} else {
+			go f(i)
+		}
+
+	}
+	wg.Wait()
+
+	for _, e := range ret {
+		if e == graphql.Null {
+			return graphql.Null
+		}
+	}
+
+	return ret
+}
+
 func (ec *executionContext) unmarshalOFunctionRunStatus2ᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋcoreapiᚋgraphᚋmodelsᚐFunctionRunStatus(ctx context.Context, v interface{}) (*models.FunctionRunStatus, error) {
 	if v == nil {
 		return nil, nil
@@ -13694,6 +16100,30 @@ func (ec *executionContext) marshalOFunctionRunStatus2ᚖgithubᚗcomᚋinngest
 	return v
 }
 
+func (ec *executionContext) unmarshalOFunctionRunWaitingReason2ᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋcoreapiᚋgraphᚋmodelsᚐFunctionRunWaitingReason(ctx context.Context, v interface{}) (*models.FunctionRunWaitingReason, error) {
+	if v == nil {
+		return nil, nil
+	}
+	var res = new(models.FunctionRunWaitingReason)
+	err := res.UnmarshalGQL(v)
+	return res, graphql.ErrorOnPath(ctx, err)
+}
+
+func (ec *executionContext) marshalOFunctionRunWaitingReason2ᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋcoreapiᚋgraphᚋmodelsᚐFunctionRunWaitingReason(ctx context.Context, sel ast.SelectionSet, v *models.FunctionRunWaitingReason) graphql.Marshaler {
+	if v == nil {
+		return graphql.Null
+	}
+	return v
+}
+
+func (ec *executionContext) unmarshalOFunctionThrottleInput2ᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋcoreapiᚋgraphᚋmodelsᚐFunctionThrottleInput(ctx context.Context, v interface{}) (*models.FunctionThrottleInput, error) {
+	if v == nil {
+		return nil, nil
+	}
+	res, err := ec.unmarshalInputFunctionThrottleInput(ctx, v)
+	return &res, graphql.ErrorOnPath(ctx, err)
+}
+
 func (ec *executionContext) marshalOFunctionTrigger2ᚕᚖgithubᚗcomᚋinngestᚋinngestᚋpkgᚋcoreapiᚋgraphᚋmodelsᚐFunctionTriggerᚄ(ctx context.Context, sel ast.SelectionSet, v []*models.FunctionTrigger) graphql.Marshaler {
 	if v == nil {
 		return graphql.Null
@@ -65,14 +65,15 @@ func MakeFunctionRun(f *cqrs.FunctionRun) *FunctionRun {
 
 	// TODO: Map GQL types to CQRS types and remove this.
 	r := &FunctionRun{
-		ID:         f.RunID.String(),
-		FunctionID: f.FunctionID.String(),
-		FinishedAt: f.EndedAt,
-		StartedAt:  &f.RunStartedAt,
-		EventID:    f.EventID.String(),
-		BatchID:    f.BatchID,
-		Status:     &status,
-		Cron:       f.Cron,
+		ID:            f.RunID.String(),
+		FunctionID:    f.FunctionID.String(),
+		FinishedAt:    f.EndedAt,
+		StartedAt:     &f.RunStartedAt,
+		EventID:       f.EventID.String(),
+		BatchID:       f.BatchID,
+		Status:        &status,
+		WaitingReason: ToFunctionRunWaitingReason(f.Status),
+		Cron:          f.Cron,
 	}
 	if len(f.Output) > 0 {
 		str := string(f.Output)
@@ -83,7 +84,7 @@ func MakeFunctionRun(f *cqrs.FunctionRun) *FunctionRun {
 
 func ToFunctionRunStatus(s enums.RunStatus) (FunctionRunStatus, error) {
 	switch s {
-	case enums.RunStatusRunning:
+	case enums.RunStatusRunning, enums.RunStatusWaitingForEvent, enums.RunStatusSleeping, enums.RunStatusHalted:
 		return FunctionRunStatusRunning, nil
 	case enums.RunStatusCompleted:
 		return FunctionRunStatusCompleted, nil
@@ -91,9 +92,46 @@ func ToFunctionRunStatus(s enums.RunStatus) (FunctionRunStatus, error) {
 		return FunctionRunStatusFailed, nil
 	case enums.RunStatusCancelled:
 		return FunctionRunStatusCancelled, nil
-	case enums.RunStatusScheduled:
+	case enums.RunStatusScheduled, enums.RunStatusThrottled:
 		return FunctionRunStatusQueued, nil
 	default:
 		return FunctionRunStatusRunning, fmt.Errorf("unknown run status: %d", s)
 	}
 }
+
+// ToFunctionRunWaitingReason returns why a run with the given status is waiting,
+// or nil if the run isn't waiting.  Waiting runs are reported as running or
+// queued via ToFunctionRunStatus.
+func ToFunctionRunWaitingReason(s enums.RunStatus) *FunctionRunWaitingReason {
+	var reason FunctionRunWaitingReason
+	switch s {
+	case enums.RunStatusSleeping:
+		reason = FunctionRunWaitingReasonSleeping
+	case enums.RunStatusWaitingForEvent:
+		reason = FunctionRunWaitingReasonWaitingForEvent
+	case enums.RunStatusThrottled:
+		reason = FunctionRunWaitingReasonThrottled
+	case enums.RunStatusHalted:
+		reason = FunctionRunWaitingReasonHalted
+	default:
+		return nil
+	}
+	return &reason
+}
+
+func FromFunctionRunStatus(s FunctionRunStatus) (enums.RunStatus, error) {
+	switch s {
+	case FunctionRunStatusRunning:
+		return enums.RunStatusRunning, nil
+	case FunctionRunStatusCompleted:
+		return enums.RunStatusCompleted, nil
+	case FunctionRunStatusFailed:
+		return enums.RunStatusFailed, nil
+	case FunctionRunStatusCancelled:
+		return enums.RunStatusCancelled, nil
+	case FunctionRunStatusQueued:
+		return enums.RunStatusScheduled, nil
+	default:
+		return enums.RunStatusUnknown, fmt.Errorf("unknown run status: %s", s)
+	}
+}
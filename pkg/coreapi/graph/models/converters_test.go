@@ -0,0 +1,33 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/inngest/inngest/pkg/enums"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToFunctionRunWaitingReason(t *testing.T) {
+	tests := []struct {
+		status enums.RunStatus
+		run    FunctionRunStatus
+		reason *FunctionRunWaitingReason
+	}{
+		{enums.RunStatusRunning, FunctionRunStatusRunning, nil},
+		{enums.RunStatusSleeping, FunctionRunStatusRunning, ptr(FunctionRunWaitingReasonSleeping)},
+		{enums.RunStatusWaitingForEvent, FunctionRunStatusRunning, ptr(FunctionRunWaitingReasonWaitingForEvent)},
+		{enums.RunStatusHalted, FunctionRunStatusRunning, ptr(FunctionRunWaitingReasonHalted)},
+		{enums.RunStatusThrottled, FunctionRunStatusQueued, ptr(FunctionRunWaitingReasonThrottled)},
+		{enums.RunStatusCompleted, FunctionRunStatusCompleted, nil},
+	}
+	for _, test := range tests {
+		status, err := ToFunctionRunStatus(test.status)
+		require.NoError(t, err)
+		require.Equal(t, test.run, status, test.status.String())
+		require.Equal(t, test.reason, ToFunctionRunWaitingReason(test.status), test.status.String())
+	}
+}
+
+func ptr(r FunctionRunWaitingReason) *FunctionRunWaitingReason {
+	return &r
+}
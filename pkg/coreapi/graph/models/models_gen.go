@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/inngest/inngest/pkg/cqrs"
+	"github.com/inngest/inngest/pkg/execution/queue"
 	"github.com/inngest/inngest/pkg/history_reader"
 	ulid "github.com/oklog/ulid/v2"
 )
@@ -53,15 +54,16 @@ type EventsQuery struct {
 }
 
 type Function struct {
-	ID          string             `json:"id"`
-	Name        string             `json:"name"`
-	Slug        string             `json:"slug"`
-	Config      string             `json:"config"`
-	Concurrency int                `json:"concurrency"`
-	Triggers    []*FunctionTrigger `json:"triggers,omitempty"`
-	URL         string             `json:"url"`
-	AppID       string             `json:"appID"`
-	App         *cqrs.App          `json:"app"`
+	ID          string                `json:"id"`
+	Name        string                `json:"name"`
+	Slug        string                `json:"slug"`
+	Config      string                `json:"config"`
+	Concurrency int                   `json:"concurrency"`
+	Triggers    []*FunctionTrigger    `json:"triggers,omitempty"`
+	URL         string                `json:"url"`
+	AppID       string                `json:"appID"`
+	App         *cqrs.App             `json:"app"`
+	Queue       *queue.PartitionStats `json:"queue"`
 }
 
 type FunctionEvent struct {
@@ -75,15 +77,17 @@ type FunctionEvent struct {
 func (FunctionEvent) IsFunctionRunEvent() {}
 
 type FunctionRun struct {
-	ID                string                       `json:"id"`
-	FunctionID        string                       `json:"functionID"`
-	Function          *Function                    `json:"function,omitempty"`
-	Workspace         *Workspace                   `json:"workspace,omitempty"`
-	Event             *Event                       `json:"event,omitempty"`
-	Events            []*Event                     `json:"events"`
-	BatchID           *ulid.ULID                   `json:"batchID,omitempty"`
-	BatchCreatedAt    *time.Time                   `json:"batchCreatedAt,omitempty"`
-	Status            *FunctionRunStatus           `json:"status,omitempty"`
+	ID             string             `json:"id"`
+	FunctionID     string             `json:"functionID"`
+	Function       *Function          `json:"function,omitempty"`
+	Workspace      *Workspace         `json:"workspace,omitempty"`
+	Event          *Event             `json:"event,omitempty"`
+	Events         []*Event           `json:"events"`
+	BatchID        *ulid.ULID         `json:"batchID,omitempty"`
+	BatchCreatedAt *time.Time         `json:"batchCreatedAt,omitempty"`
+	Status         *FunctionRunStatus `json:"status,omitempty"`
+	// waitingReason is set while the run is waiting, and is null otherwise.
+	WaitingReason     *FunctionRunWaitingReason    `json:"waitingReason,omitempty"`
 	WaitingFor        *StepEventWait               `json:"waitingFor,omitempty"`
 	PendingSteps      *int                         `json:"pendingSteps,omitempty"`
 	StartedAt         *time.Time                   `json:"startedAt,omitempty"`
@@ -91,6 +95,7 @@ type FunctionRun struct {
 	Output            *string                      `json:"output,omitempty"`
 	History           []*history_reader.RunHistory `json:"history"`
 	HistoryItemOutput *string                      `json:"historyItemOutput,omitempty"`
+	StepLogs          []*cqrs.StepLog              `json:"stepLogs"`
 	EventID           string                       `json:"eventID"`
 	Cron              *string                      `json:"cron,omitempty"`
 }
@@ -100,15 +105,42 @@ type FunctionRunQuery struct {
 	FunctionRunID string `json:"functionRunId"`
 }
 
+type FunctionRunSearchResult struct {
+	Runs []*FunctionRun `json:"runs"`
+	// Continues the search after this page, or null once every run has been
+	// searched.  Pages may contain fewer runs than the limit while a cursor is
+	// returned, as each search scans a bounded number of runs.
+	Cursor *string `json:"cursor,omitempty"`
+}
+
 type FunctionRunsQuery struct {
 	WorkspaceID string `json:"workspaceId"`
 }
 
+type FunctionThrottleInput struct {
+	Limit int  `json:"limit"`
+	Burst *int `json:"burst,omitempty"`
+	// The throttle period, eg. "1m" or "24h".
+	Period string `json:"period"`
+}
+
 type FunctionTrigger struct {
 	Type  FunctionTriggerTypes `json:"type"`
 	Value string               `json:"value"`
 }
 
+type SearchFunctionRunsQuery struct {
+	WorkspaceID   string              `json:"workspaceId"`
+	Expression    *string             `json:"expression,omitempty"`
+	FunctionID    *string             `json:"functionId,omitempty"`
+	Status        []FunctionRunStatus `json:"status,omitempty"`
+	StartedAfter  *time.Time          `json:"startedAfter,omitempty"`
+	StartedBefore *time.Time          `json:"startedBefore,omitempty"`
+	Cursor        *string             `json:"cursor,omitempty"`
+	Order         SortOrder           `json:"order"`
+	Limit         int                 `json:"limit"`
+}
+
 type StepEvent struct {
 	Workspace   *Workspace     `json:"workspace,omitempty"`
 	FunctionRun *FunctionRun   `json:"functionRun,omitempty"`
@@ -294,6 +326,53 @@ func (e FunctionRunStatus) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
+// FunctionRunWaitingReason describes why a running or queued run isn't
+// currently progressing.
+type FunctionRunWaitingReason string
+
+const (
+	FunctionRunWaitingReasonSleeping        FunctionRunWaitingReason = "SLEEPING"
+	FunctionRunWaitingReasonWaitingForEvent FunctionRunWaitingReason = "WAITING_FOR_EVENT"
+	FunctionRunWaitingReasonThrottled       FunctionRunWaitingReason = "THROTTLED"
+	FunctionRunWaitingReasonHalted          FunctionRunWaitingReason = "HALTED"
+)
+
+var AllFunctionRunWaitingReason = []FunctionRunWaitingReason{
+	FunctionRunWaitingReasonSleeping,
+	FunctionRunWaitingReasonWaitingForEvent,
+	FunctionRunWaitingReasonThrottled,
+	FunctionRunWaitingReasonHalted,
+}
+
+func (e FunctionRunWaitingReason) IsValid() bool {
+	switch e {
+	case FunctionRunWaitingReasonSleeping, FunctionRunWaitingReasonWaitingForEvent, FunctionRunWaitingReasonThrottled, FunctionRunWaitingReasonHalted:
+		return true
+	}
+	return false
+}
+
+func (e FunctionRunWaitingReason) String() string {
+	return string(e)
+}
+
+func (e *FunctionRunWaitingReason) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = FunctionRunWaitingReason(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid FunctionRunWaitingReason", str)
+	}
+	return nil
+}
+
+func (e FunctionRunWaitingReason) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
 type FunctionStatus string
 
 const (
@@ -380,6 +459,47 @@ func (e FunctionTriggerTypes) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
+type SortOrder string
+
+const (
+	SortOrderAsc  SortOrder = "ASC"
+	SortOrderDesc SortOrder = "DESC"
+)
+
+var AllSortOrder = []SortOrder{
+	SortOrderAsc,
+	SortOrderDesc,
+}
+
+func (e SortOrder) IsValid() bool {
+	switch e {
+	case SortOrderAsc, SortOrderDesc:
+		return true
+	}
+	return false
+}
+
+func (e SortOrder) String() string {
+	return string(e)
+}
+
+func (e *SortOrder) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = SortOrder(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid SortOrder", str)
+	}
+	return nil
+}
+
+func (e SortOrder) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
 type StepEventType string
 
 const (
@@ -11,6 +11,7 @@ import (
 	"github.com/inngest/inngest/pkg/coreapi/graph/models"
 	"github.com/inngest/inngest/pkg/cqrs"
 	"github.com/inngest/inngest/pkg/deploy"
+	"github.com/inngest/inngest/pkg/devserver/environment"
 	"github.com/inngest/inngest/pkg/event"
 )
 
@@ -21,15 +22,18 @@ func (r *mutationResolver) CreateApp(ctx context.Context, input models.CreateApp
 		input.URL = "http://" + input.URL
 	}
 
+	wsID := environment.WorkspaceIDFromContext(ctx)
+
 	// If we already have the app, return it.
-	if app, err := r.Data.GetAppByURL(ctx, input.URL); err == nil && app != nil {
+	if app, err := r.Data.GetAppByURL(ctx, wsID, input.URL); err == nil && app != nil {
 		return app, nil
 	}
 
 	// Create a new app which holds the error message.
 	params := cqrs.InsertAppParams{
-		ID:  uuid.New(),
-		Url: input.URL,
+		ID:          uuid.New(),
+		WorkspaceID: wsID,
+		Url:         input.URL,
 		Error: sql.NullString{
 			Valid:  true,
 			String: deploy.DeployErrUnreachable.Error(),
@@ -42,7 +46,7 @@ func (r *mutationResolver) CreateApp(ctx context.Context, input models.CreateApp
 	}
 
 	<-time.After(100 * time.Millisecond)
-	apps, err := r.Data.GetAllApps(ctx)
+	apps, err := r.Data.GetAllApps(ctx, wsID)
 	if err != nil {
 		return nil, err
 	}
@@ -76,7 +80,7 @@ func (r *mutationResolver) DeleteAppByName(
 	ctx context.Context,
 	name string,
 ) (bool, error) {
-	apps, err := r.Data.GetApps(ctx)
+	apps, err := r.Data.GetApps(ctx, environment.WorkspaceIDFromContext(ctx))
 	if err != nil {
 		return false, err
 	}
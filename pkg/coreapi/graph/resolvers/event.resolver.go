@@ -6,6 +6,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/inngest/inngest/pkg/coreapi/graph/models"
+	"github.com/inngest/inngest/pkg/devserver/environment"
 	"github.com/inngest/inngest/pkg/enums"
 	"github.com/oklog/ulid/v2"
 )
@@ -13,7 +14,7 @@ import (
 // TODO Duplicate code. Move to field-level resolvers and add dataloaders.
 func (r *eventResolver) FunctionRuns(ctx context.Context, obj *models.Event) ([]*models.FunctionRun, error) {
 	accountID := uuid.UUID{}
-	workspaceID := uuid.UUID{}
+	workspaceID := environment.WorkspaceIDFromContext(ctx)
 
 	runs, err := r.Data.GetFunctionRunsFromEvents(ctx, accountID, workspaceID, []ulid.ULID{obj.ID})
 	if err != nil {
@@ -39,7 +40,7 @@ func (r *eventResolver) PendingRuns(ctx context.Context, obj *models.Event) (*in
 	var pending int
 
 	for _, s := range state {
-		if s.Metadata().Status == enums.RunStatusRunning {
+		if status := s.Metadata().Status; status == enums.RunStatusRunning || enums.RunStatusWaiting(status) {
 			pending++
 		}
 	}
@@ -70,7 +71,7 @@ func (r *eventResolver) Status(ctx context.Context, obj *models.Event) (*models.
 			continue
 		}
 
-		if m.Status == enums.RunStatusRunning {
+		if m.Status == enums.RunStatusRunning || enums.RunStatusWaiting(m.Status) {
 			isRunning = true
 			continue
 		}
@@ -2,13 +2,90 @@ package resolvers
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	"github.com/google/uuid"
 	"github.com/inngest/inngest/pkg/coreapi/graph/models"
 	"github.com/inngest/inngest/pkg/cqrs"
+	"github.com/inngest/inngest/pkg/execution/queue"
+	"github.com/xhit/go-str2duration/v2"
 )
 
 func (r *functionResolver) App(ctx context.Context, obj *models.Function) (*cqrs.App, error) {
 	appID := uuid.MustParse(obj.AppID)
 	return r.Data.GetAppByID(ctx, appID)
 }
+
+func (r *functionResolver) Queue(ctx context.Context, obj *models.Function) (*queue.PartitionStats, error) {
+	stats, err := r.Resolver.Queue.PartitionStats(ctx, uuid.MustParse(obj.ID))
+	if err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+func (r *mutationResolver) UpdateFunctionThrottle(
+	ctx context.Context,
+	functionID string,
+	input *models.FunctionThrottleInput,
+) (bool, error) {
+	q, ok := r.Resolver.Queue.(queue.ThrottleUpdater)
+	if !ok {
+		return false, errors.New("the queue does not support updating throttles")
+	}
+
+	id, err := uuid.Parse(functionID)
+	if err != nil {
+		return false, fmt.Errorf("Invalid function ID: %w", err)
+	}
+
+	if input == nil {
+		// Remove the override.
+		return true, q.SetThrottle(ctx, id, nil)
+	}
+
+	period, err := str2duration.ParseDuration(input.Period)
+	if err != nil {
+		return false, fmt.Errorf("Invalid throttle period: %w", err)
+	}
+	burst := 1
+	if input.Burst != nil {
+		burst = *input.Burst
+	}
+
+	err = q.SetThrottle(ctx, id, &queue.Throttle{
+		Limit:  input.Limit,
+		Burst:  burst,
+		Period: int(period.Seconds()),
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *mutationResolver) PauseFunctionQueue(ctx context.Context, functionID string) (bool, error) {
+	return r.setFunctionQueuePaused(ctx, functionID, true)
+}
+
+func (r *mutationResolver) ResumeFunctionQueue(ctx context.Context, functionID string) (bool, error) {
+	return r.setFunctionQueuePaused(ctx, functionID, false)
+}
+
+func (r *mutationResolver) setFunctionQueuePaused(ctx context.Context, functionID string, paused bool) (bool, error) {
+	q, ok := r.Resolver.Queue.(queue.PartitionPauser)
+	if !ok {
+		return false, errors.New("the queue does not support pausing functions")
+	}
+
+	id, err := uuid.Parse(functionID)
+	if err != nil {
+		return false, fmt.Errorf("Invalid function ID: %w", err)
+	}
+
+	if err := q.SetPartitionPaused(ctx, id, paused); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -10,9 +10,12 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/inngest/inngest/pkg/coreapi/graph/models"
+	"github.com/inngest/inngest/pkg/cqrs"
+	"github.com/inngest/inngest/pkg/devserver/environment"
 	"github.com/inngest/inngest/pkg/enums"
 	"github.com/inngest/inngest/pkg/event"
 	"github.com/inngest/inngest/pkg/execution"
+	"github.com/inngest/inngest/pkg/execution/queue"
 	"github.com/inngest/inngest/pkg/history_reader"
 	"github.com/inngest/inngest/pkg/util"
 	"github.com/oklog/ulid/v2"
@@ -33,7 +36,7 @@ func (r *functionRunResolver) PendingSteps(ctx context.Context, obj *models.Func
 }
 
 func (r *functionRunResolver) Function(ctx context.Context, obj *models.FunctionRun) (*models.Function, error) {
-	fn, err := r.Data.GetFunctionByInternalUUID(ctx, uuid.UUID{}, uuid.MustParse(obj.FunctionID))
+	fn, err := r.Data.GetFunctionByInternalUUID(ctx, environment.WorkspaceIDFromContext(ctx), uuid.MustParse(obj.FunctionID))
 	if err != nil {
 		return nil, err
 	}
@@ -61,6 +64,18 @@ func (r *functionRunResolver) History(
 	)
 }
 
+func (r *functionRunResolver) StepLogs(
+	ctx context.Context,
+	obj *models.FunctionRun,
+	stepID *string,
+) ([]*cqrs.StepLog, error) {
+	runID, err := ulid.Parse(obj.ID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid run ID: %w", err)
+	}
+	return r.Data.GetStepLogs(ctx, runID, stepID)
+}
+
 func (r *functionRunResolver) HistoryItemOutput(
 	ctx context.Context,
 	obj *models.FunctionRun,
@@ -251,8 +266,9 @@ func (r *mutationResolver) Rerun(
 	runID ulid.ULID,
 ) (ulid.ULID, error) {
 	zero := ulid.ULID{}
-	accountID := uuid.New()
-	workspaceID := uuid.New()
+	// The dev server has no accounts.
+	accountID := uuid.UUID{}
+	workspaceID := environment.WorkspaceIDFromContext(ctx)
 
 	run, err := r.Data.GetFunctionRun(
 		ctx,
@@ -285,6 +301,8 @@ func (r *mutationResolver) Rerun(
 
 	identifier, err := r.Executor.Schedule(ctx, execution.ScheduleRequest{
 		Function: *fn,
+		// Reruns start within the original run's environment.
+		WorkspaceID: fnCQRS.WorkspaceID,
 		Events: []event.TrackedEvent{
 			// We need NewOSSTrackedEventWithID to ensure that the tracked event
 			// has the same ID as the original event. Calling NewOSSTrackedEvent
@@ -299,3 +317,35 @@ func (r *mutationResolver) Rerun(
 
 	return identifier.RunID, nil
 }
+
+func (r *mutationResolver) RequeueStep(
+	ctx context.Context,
+	runID ulid.ULID,
+	stepID string,
+) (bool, error) {
+	q, ok := r.Resolver.Queue.(queue.JobRequeuer)
+	if !ok {
+		return false, errors.New("the queue does not support requeueing steps")
+	}
+
+	err := q.RequeueRunStep(ctx, runID, stepID, time.Now())
+	if errors.Is(err, queue.ErrJobNotFound) {
+		return false, errors.New("no delayed or retrying job was found for this step")
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *mutationResolver) BoostRunPriority(
+	ctx context.Context,
+	runID ulid.ULID,
+	seconds int,
+) (int, error) {
+	q, ok := r.Resolver.Queue.(queue.JobRequeuer)
+	if !ok {
+		return 0, errors.New("the queue does not support boosting runs")
+	}
+	return q.BoostRunPriority(ctx, runID, time.Duration(seconds)*time.Second)
+}
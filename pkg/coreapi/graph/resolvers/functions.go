@@ -4,22 +4,28 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/google/uuid"
 	"github.com/inngest/inngest/pkg/coreapi/graph/models"
+	"github.com/inngest/inngest/pkg/cqrs"
+	"github.com/inngest/inngest/pkg/devserver/environment"
+	"github.com/inngest/inngest/pkg/expressions"
 	"github.com/inngest/inngest/pkg/history_reader"
+	"github.com/inngest/inngest/pkg/util"
 	"github.com/oklog/ulid/v2"
 )
 
 func (r *queryResolver) Functions(ctx context.Context) ([]*models.Function, error) {
-	all, err := r.Data.GetFunctions(ctx)
+	all, err := r.Data.GetFunctions(ctx, environment.WorkspaceIDFromContext(ctx))
 	if err != nil {
 		return nil, err
 	}
-	res := make([]*models.Function, len(all))
-	for n, i := range all {
-		res[n], err = models.MakeFunction(i)
+	res := []*models.Function{}
+	for _, i := range all {
+		fn, err := models.MakeFunction(i)
 		if err != nil {
 			return nil, err
 		}
+		res = append(res, fn)
 	}
 	return res, nil
 }
@@ -49,13 +55,70 @@ func (r *queryResolver) FunctionRun(ctx context.Context, query models.FunctionRu
 	}
 
 	return &models.FunctionRun{
-		ID:         runID.String(),
-		FunctionID: run.WorkflowID.String(),
-		FinishedAt: run.EndedAt,
-		StartedAt:  &run.StartedAt,
-		EventID:    run.EventID.String(),
-		BatchID:    run.BatchID,
-		Status:     &status,
-		Output:     run.Output,
+		ID:            runID.String(),
+		FunctionID:    run.WorkflowID.String(),
+		FinishedAt:    run.EndedAt,
+		StartedAt:     &run.StartedAt,
+		EventID:       run.EventID.String(),
+		BatchID:       run.BatchID,
+		Status:        &status,
+		WaitingReason: models.ToFunctionRunWaitingReason(run.Status),
+		Output:        run.Output,
 	}, nil
 }
+
+func (r *queryResolver) SearchFunctionRuns(ctx context.Context, query models.SearchFunctionRunsQuery) (*models.FunctionRunSearchResult, error) {
+	opts := cqrs.SearchFunctionRunsOpts{
+		Timebound: cqrs.Timebound{
+			After:  query.StartedAfter,
+			Before: query.StartedBefore,
+		},
+		Order: cqrs.SortOrderDesc,
+		Limit: util.Bound(query.Limit, 1, cqrs.MaxFunctionRunSearchResults),
+	}
+	if query.Order == models.SortOrderAsc {
+		opts.Order = cqrs.SortOrderAsc
+	}
+	if query.Expression != nil && *query.Expression != "" {
+		if err := expressions.Validate(ctx, *query.Expression); err != nil {
+			return nil, fmt.Errorf("Invalid search expression: %w", err)
+		}
+		opts.If = *query.Expression
+	}
+	if query.FunctionID != nil {
+		fnID, err := uuid.Parse(*query.FunctionID)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid function ID: %w", err)
+		}
+		opts.FunctionID = &fnID
+	}
+	if query.Cursor != nil {
+		cursor, err := cqrs.ParseFunctionRunCursor(*query.Cursor)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid cursor: %w", err)
+		}
+		opts.Cursor = cursor
+	}
+	for _, s := range query.Status {
+		status, err := models.FromFunctionRunStatus(s)
+		if err != nil {
+			return nil, err
+		}
+		opts.Statuses = append(opts.Statuses, status)
+	}
+
+	res, err := r.Data.SearchFunctionRuns(ctx, uuid.UUID{}, environment.WorkspaceIDFromContext(ctx), opts)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &models.FunctionRunSearchResult{Runs: make([]*models.FunctionRun, len(res.Runs))}
+	for n, run := range res.Runs {
+		out.Runs[n] = models.MakeFunctionRun(run)
+	}
+	if res.Cursor != nil {
+		cursor := res.Cursor.String()
+		out.Cursor = &cursor
+	}
+	return out, nil
+}
@@ -0,0 +1,105 @@
+package coreapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/inngest/inngest/pkg/cqrs"
+	"github.com/inngest/inngest/pkg/headers"
+	"github.com/inngest/inngest/pkg/publicerr"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+type eventKeyCtx struct{}
+
+// mutationScopes lists the scope required for each GraphQL mutation.  Mutations
+// not listed here require the admin scope.
+var mutationScopes = map[string]cqrs.EventKeyScope{
+	"cancelRun":      cqrs.ScopeRunsCancel,
+	"invokeFunction": cqrs.ScopeEventsWrite,
+}
+
+// withEventKey stores the event key sent within the request's Authorization
+// header in the request's context, for checking scopes of GraphQL operations.
+func withEventKey(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), eventKeyCtx{}, headers.BearerToken(r))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// operationScopes returns the scopes required to execute the given GraphQL
+// operation.
+func operationScopes(op *ast.OperationDefinition) []cqrs.EventKeyScope {
+	if op.Operation != ast.Mutation {
+		return []cqrs.EventKeyScope{cqrs.ScopeRunsRead}
+	}
+	scopes := []cqrs.EventKeyScope{}
+	for _, sel := range op.SelectionSet {
+		field, ok := sel.(*ast.Field)
+		if !ok {
+			scopes = append(scopes, cqrs.ScopeAdmin)
+			continue
+		}
+		scope, ok := mutationScopes[field.Name]
+		if !ok {
+			scope = cqrs.ScopeAdmin
+		}
+		scopes = append(scopes, scope)
+	}
+	return scopes
+}
+
+// requireScopes is GraphQL operation middleware which rejects operations whose
+// event key isn't granted each scope required by the operation.  As with the
+// REST API, scopes are only checked if RequireKeys is set, as the dev server UI
+// uses the GraphQL API without a key, and only once any event key exists.
+func (a CoreAPI) requireScopes(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+	op := graphql.GetOperationContext(ctx).Operation
+	if op == nil || !a.config.CoreAPI.RequireKeys {
+		return next(ctx)
+	}
+
+	key, _ := ctx.Value(eventKeyCtx{}).(string)
+	for _, scope := range operationScopes(op) {
+		_, err := cqrs.AuthorizeEventKey(ctx, a.data, key, scope)
+		switch {
+		case err == nil:
+			continue
+		case errors.Is(err, cqrs.ErrEventKeyNotFound):
+			return graphql.OneShot(graphql.ErrorResponse(ctx, "A valid event key is required"))
+		case errors.Is(err, cqrs.ErrEventKeyScope):
+			return graphql.OneShot(graphql.ErrorResponse(ctx, "Event key does not have the %s scope", scope))
+		default:
+			a.log.Error().Err(err).Msg("error authenticating event key")
+			return graphql.OneShot(graphql.ErrorResponse(ctx, "Unable to authenticate event key"))
+		}
+	}
+	return next(ctx)
+}
+
+// requireScope returns middleware which rejects requests that aren't authenticated
+// with an event key granted the given scope.  This is a no-op unless RequireKeys
+// is set.
+func (a CoreAPI) requireScope(scope cqrs.EventKeyScope) func(http.Handler) http.Handler {
+	if !a.config.CoreAPI.RequireKeys {
+		return func(next http.Handler) http.Handler { return next }
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, err := cqrs.AuthorizeEventKey(r.Context(), a.data, headers.BearerToken(r), scope)
+			switch {
+			case err == nil:
+				next.ServeHTTP(w, r)
+			case errors.Is(err, cqrs.ErrEventKeyNotFound):
+				_ = publicerr.WriteHTTP(w, publicerr.Wrap(err, 401, "A valid event key is required"))
+			case errors.Is(err, cqrs.ErrEventKeyScope):
+				_ = publicerr.WriteHTTP(w, publicerr.Errorf(403, "Event key does not have the %s scope", scope))
+			default:
+				_ = publicerr.WriteHTTP(w, publicerr.Wrap(err, 500, "Unable to authenticate event key"))
+			}
+		})
+	}
+}
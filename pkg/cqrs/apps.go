@@ -21,6 +21,9 @@ type App struct {
 	CreatedAt   time.Time
 	DeletedAt   time.Time
 	Url         string
+	// WorkspaceID is the workspace that the app is registered within, eg. a
+	// dev server environment.
+	WorkspaceID uuid.UUID
 }
 
 type AppManager interface {
@@ -29,14 +32,14 @@ type AppManager interface {
 }
 
 type AppReader interface {
-	// GetApps returns apps that have not been deleted.
-	GetApps(ctx context.Context) ([]*App, error)
-	// GetAppByChecksum returns an app by checksum.
-	GetAppByChecksum(ctx context.Context, checksum string) (*App, error)
-	// GetAppByURL returns an app by URL
-	GetAppByURL(ctx context.Context, url string) (*App, error)
-	// GetAllApps returns all apps.
-	GetAllApps(ctx context.Context) ([]*App, error)
+	// GetApps returns apps within the workspace that have not been deleted.
+	GetApps(ctx context.Context, workspaceID uuid.UUID) ([]*App, error)
+	// GetAppByChecksum returns an app within the workspace by checksum.
+	GetAppByChecksum(ctx context.Context, workspaceID uuid.UUID, checksum string) (*App, error)
+	// GetAppByURL returns an app within the workspace by URL
+	GetAppByURL(ctx context.Context, workspaceID uuid.UUID, url string) (*App, error)
+	// GetAllApps returns all apps within the workspace.
+	GetAllApps(ctx context.Context, workspaceID uuid.UUID) ([]*App, error)
 
 	GetAppByID(ctx context.Context, id uuid.UUID) (*App, error)
 }
@@ -64,6 +67,7 @@ type InsertAppParams struct {
 	Error       sql.NullString
 	Checksum    string
 	Url         string
+	WorkspaceID uuid.UUID
 }
 
 type UpdateAppErrorParams struct {
@@ -24,7 +24,10 @@ type Manager interface {
 	AppManager
 	FunctionRunManager
 	EventManager
+	EventKeyManager
+	CancellationReadWriter
 	HistoryManager
+	StepLogManager
 
 	// Trace / dev only
 	TraceReadWriter
@@ -33,6 +36,12 @@ type Manager interface {
 	WithTx(ctx context.Context) (TxManager, error)
 }
 
+// Pinger is an optional interface that managers can fulfil, checking
+// connectivity to the backing database.
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
 type TxManager interface {
 	Manager
 
@@ -46,3 +55,18 @@ type Timebound struct {
 	// Before is the upper bound to load data from, inclusive
 	Before *time.Time `json:"before,omitempty"`
 }
+
+// SortOrder represents the order that paginated results are returned in.
+type SortOrder string
+
+const (
+	// SortOrderDesc returns the newest results first.  This is the default.
+	SortOrderDesc SortOrder = "desc"
+	// SortOrderAsc returns the oldest results first.
+	SortOrderAsc SortOrder = "asc"
+)
+
+// Ascending returns whether results are sorted oldest first.
+func (s SortOrder) Ascending() bool {
+	return s == SortOrderAsc
+}
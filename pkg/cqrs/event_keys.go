@@ -0,0 +1,155 @@
+package cqrs
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+// ErrEventKeyNotFound is returned when an event key doesn't exist or has been revoked.
+var ErrEventKeyNotFound = fmt.Errorf("event key not found")
+
+// ErrEventKeyScope is returned when an event key doesn't have the scope required
+// for a request.
+var ErrEventKeyScope = fmt.Errorf("event key does not have the required scope")
+
+// EventKeyScope is a permission granted to an event key.
+type EventKeyScope string
+
+const (
+	// ScopeEventsWrite allows sending events to the event API.
+	ScopeEventsWrite EventKeyScope = "events:write"
+	// ScopeRunsRead allows reading events, functions, and runs.
+	ScopeRunsRead EventKeyScope = "runs:read"
+	// ScopeRunsCancel allows cancelling runs, including bulk cancellations.
+	ScopeRunsCancel EventKeyScope = "runs:cancel"
+	// ScopeAdmin grants every other scope, and allows managing keys, apps,
+	// and functions.
+	ScopeAdmin EventKeyScope = "admin"
+)
+
+// EventKeyScopes lists every valid scope.
+var EventKeyScopes = []EventKeyScope{
+	ScopeEventsWrite,
+	ScopeRunsRead,
+	ScopeRunsCancel,
+	ScopeAdmin,
+}
+
+// DefaultEventKeyScopes are granted to keys created without any scopes, matching
+// keys created before scopes existed.
+var DefaultEventKeyScopes = []EventKeyScope{ScopeEventsWrite}
+
+// Valid returns whether the scope is a known scope.
+func (s EventKeyScope) Valid() bool {
+	for _, v := range EventKeyScopes {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+type EventKeyManager interface {
+	EventKeyReader
+	EventKeyWriter
+}
+
+// EventKeyReader loads event ingest keys from a backing store.
+type EventKeyReader interface {
+	// GetEventKeys returns all event keys, including revoked keys.
+	GetEventKeys(ctx context.Context) ([]*EventKey, error)
+	// GetEventKeyByID returns a single event key, which may be revoked.
+	GetEventKeyByID(ctx context.Context, id ulid.ULID) (*EventKey, error)
+	// GetEventKeyByKey returns the active event key matching the given plaintext
+	// key, or ErrEventKeyNotFound.
+	GetEventKeyByKey(ctx context.Context, key string) (*EventKey, error)
+	// HasEventKeys returns whether any active event keys exist.
+	HasEventKeys(ctx context.Context) (bool, error)
+}
+
+type EventKeyWriter interface {
+	// CreateEventKey creates a new event key, returning the key including its
+	// plaintext value.
+	CreateEventKey(ctx context.Context, opts CreateEventKeyOpts) (*EventKey, error)
+	// RotateEventKey replaces the key's value, immediately invalidating the
+	// previous value.  The returned key includes the new plaintext value.
+	RotateEventKey(ctx context.Context, id ulid.ULID) (*EventKey, error)
+	// RevokeEventKey revokes an event key, immediately preventing the key from
+	// being used to send events.
+	RevokeEventKey(ctx context.Context, id ulid.ULID) (*EventKey, error)
+}
+
+type CreateEventKeyOpts struct {
+	// Name is a human readable name for the key.
+	Name string `json:"name"`
+	// Source optionally describes where events sent using the key come from,
+	// eg. "stripe webhooks" or "backend".
+	Source *string `json:"source,omitempty"`
+	// Scopes are the permissions granted to the key, defaulting to
+	// DefaultEventKeyScopes if empty.
+	Scopes []EventKeyScope `json:"scopes,omitempty"`
+	// Env optionally restricts the key to a named environment.  Events sent
+	// using the key are received within the environment, regardless of the
+	// environment selected by the request.
+	Env string `json:"env,omitempty"`
+}
+
+// EventKey represents a key used to send events to the event API.  Keys are
+// stored hashed:  the plaintext value is only available when the key is created
+// or rotated.
+type EventKey struct {
+	ID     ulid.ULID `json:"id"`
+	Name   string    `json:"name"`
+	Source *string   `json:"source,omitempty"`
+	// Scopes are the permissions granted to the key.
+	Scopes []EventKeyScope `json:"scopes"`
+	// Env is the environment that the key is restricted to, if any.
+	Env string `json:"env,omitempty"`
+	// Key is the plaintext key, only set when the key is created or rotated.
+	Key string `json:"key,omitempty"`
+	// Prefix is the start of the plaintext key, allowing keys to be identified
+	// without storing their value.
+	Prefix    string     `json:"prefix"`
+	CreatedAt time.Time  `json:"created_at"`
+	RotatedAt *time.Time `json:"rotated_at,omitempty"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+// IsRevoked returns whether the key has been revoked.
+func (k EventKey) IsRevoked() bool {
+	return k.RevokedAt != nil
+}
+
+// HasScope returns whether the key is granted the given scope.  Admin keys are
+// granted every scope.
+func (k EventKey) HasScope(scope EventKeyScope) bool {
+	for _, s := range k.Scopes {
+		if s == scope || s == ScopeAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+// AuthorizeEventKey returns the given plaintext key if it's an active key granted
+// the given scope, ErrEventKeyNotFound if the key doesn't exist, or ErrEventKeyScope
+// if the key is missing the scope.  Keys are only enforced once at least one key
+// has been created, so that any key can be used in development; until then, the
+// returned key is nil.
+func AuthorizeEventKey(ctx context.Context, r EventKeyReader, key string, scope EventKeyScope) (*EventKey, error) {
+	enforced, err := r.HasEventKeys(ctx)
+	if err != nil || !enforced {
+		return nil, err
+	}
+	k, err := r.GetEventKeyByKey(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if !k.HasScope(scope) {
+		return nil, ErrEventKeyScope
+	}
+	return k, nil
+}
@@ -80,8 +80,12 @@ type EventWriter interface {
 }
 
 type WorkspaceEventsOpts struct {
+	// Cursor is the internal ID of the last event in the previous page.
 	Cursor *ulid.ULID
 	Limit  int
+	// Order sorts events by the time they were received, defaulting to newest
+	// first.
+	Order SortOrder
 	// Name filters events to a given name.
 	Name *string
 	// Newest represents the newest time to load events from.  Events newer than
@@ -115,8 +119,10 @@ type EventReader interface {
 	GetEventsByInternalIDs(ctx context.Context, ids []ulid.ULID) ([]*Event, error)
 	GetEventBatchesByEventID(ctx context.Context, eventID ulid.ULID) ([]*EventBatch, error)
 	GetEventBatchByRunID(ctx context.Context, runID ulid.ULID) (*EventBatch, error)
+	// GetEventsTimebound returns events received within the given workspace.
 	GetEventsTimebound(
 		ctx context.Context,
+		workspaceID uuid.UUID,
 		t Timebound,
 		limit int,
 		includeInternal bool,
@@ -3,16 +3,22 @@ package cqrs
 import (
 	"context"
 
+	"github.com/google/uuid"
 	"github.com/inngest/inngest/pkg/inngest"
 )
 
 // ExecutionLoader is an interface which specifies all functions required to run
 // workers and executors.
 type ExecutionLoader interface {
-	// Functions returns all functions.
+	// Functions returns all functions, within every workspace.
 	Functions(ctx context.Context) ([]inngest.Function, error)
-	// FunctionsScheduled returns all scheduled functions available.
+	// FunctionsScheduled returns all scheduled functions available, within
+	// every workspace.
 	FunctionsScheduled(ctx context.Context) ([]inngest.Function, error)
-	// FunctionsByTrigger returns functions for the given trigger by event name.
-	FunctionsByTrigger(ctx context.Context, eventName string) ([]inngest.Function, error)
+	// FunctionsByTrigger returns functions within the given workspace for the
+	// given trigger by event name.
+	FunctionsByTrigger(ctx context.Context, workspaceID uuid.UUID, eventName string) ([]inngest.Function, error)
+	// FunctionWorkspaceID returns the ID of the workspace that the given
+	// function is registered within.
+	FunctionWorkspaceID(ctx context.Context, fnID uuid.UUID) (uuid.UUID, error)
 }
@@ -3,6 +3,9 @@ package cqrs
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -48,12 +51,100 @@ type FunctionRunManager interface {
 
 type FunctionRunWriter interface {
 	InsertFunctionRun(ctx context.Context, run FunctionRun) error
+	// UpdateFunctionRunStatus records the status of a run which hasn't finished,
+	// eg. when the run is sleeping or throttled.
+	UpdateFunctionRunStatus(ctx context.Context, runID ulid.ULID, status enums.RunStatus) error
 }
 
 type FunctionRunReader interface {
 	APIV1FunctionRunReader
+	FunctionRunSearcher
 
-	GetFunctionRunsTimebound(ctx context.Context, t Timebound, limit int) ([]*FunctionRun, error)
+	// GetFunctionRunsTimebound returns runs within the given workspace.
+	GetFunctionRunsTimebound(ctx context.Context, workspaceID uuid.UUID, t Timebound, limit int) ([]*FunctionRun, error)
+}
+
+// MaxFunctionRunSearchResults is the maximum number of runs returned when searching.
+const MaxFunctionRunSearchResults = 100
+
+// MaxFunctionRunSearchScan is the maximum number of runs scanned by a single
+// search.  Searches which filter runs after loading them stop once this many
+// runs have been scanned, returning a cursor to continue searching from.
+const MaxFunctionRunSearchScan = 10_000
+
+// SearchFunctionRunsOpts filters and paginates runs.
+type SearchFunctionRunsOpts struct {
+	// If is an optional expression evaluated against each run's triggering
+	// event, eg. `event.data.userId == "123"`.  Only matching runs are returned.
+	If string
+	// FunctionID optionally returns runs for a single function.
+	FunctionID *uuid.UUID
+	// Statuses optionally returns runs with the given statuses.
+	Statuses []enums.RunStatus
+	// Timebound bounds the time that runs started.
+	Timebound Timebound
+	// Cursor is the position of the last run in the previous page.
+	Cursor *FunctionRunCursor
+	// Order sorts runs by the time they started, defaulting to newest first.
+	Order SortOrder
+	// Limit is the maximum number of runs to return.
+	Limit int
+}
+
+// FunctionRunSearchResult is a page of runs found by searching.
+type FunctionRunSearchResult struct {
+	Runs []*FunctionRun
+	// Cursor continues the search after this page, or is nil once every run
+	// has been searched.  Pages may contain fewer runs than the limit while
+	// the cursor is set, as searches scan at most MaxFunctionRunSearchScan runs.
+	Cursor *FunctionRunCursor
+}
+
+// FunctionRunCursor is the position of a run within runs sorted by the time
+// they started.  Runs which started at the same time are sorted by their ID.
+type FunctionRunCursor struct {
+	RunStartedAt time.Time
+	RunID        ulid.ULID
+}
+
+// NewFunctionRunCursor returns the cursor positioned at the given run.
+func NewFunctionRunCursor(run *FunctionRun) *FunctionRunCursor {
+	return &FunctionRunCursor{RunStartedAt: run.RunStartedAt, RunID: run.RunID}
+}
+
+// String encodes the cursor as the run's start time, in nanoseconds, and its
+// ID, eg. "1700000000000000000:01HF...".
+func (c FunctionRunCursor) String() string {
+	return fmt.Sprintf("%d:%s", c.RunStartedAt.UnixNano(), c.RunID)
+}
+
+// ParseFunctionRunCursor parses a cursor encoded by FunctionRunCursor.String.
+func ParseFunctionRunCursor(s string) (*FunctionRunCursor, error) {
+	ts, id, ok := strings.Cut(s, ":")
+	if !ok {
+		return nil, fmt.Errorf("invalid cursor: %s", s)
+	}
+	nanos, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor time: %w", err)
+	}
+	runID, err := ulid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor run ID: %w", err)
+	}
+	return &FunctionRunCursor{RunStartedAt: time.Unix(0, nanos), RunID: runID}, nil
+}
+
+// FunctionRunSearcher finds and paginates function runs.
+type FunctionRunSearcher interface {
+	// SearchFunctionRuns returns runs matching the given options, sorted by the
+	// time they started.
+	SearchFunctionRuns(
+		ctx context.Context,
+		accountID uuid.UUID,
+		workspaceID uuid.UUID,
+		opts SearchFunctionRunsOpts,
+	) (*FunctionRunSearchResult, error)
 }
 
 type APIV1FunctionRunReader interface {
@@ -16,6 +16,10 @@ type Function struct {
 	Name      string          `json:"name"`
 	Config    json.RawMessage `json:"config"`
 	CreatedAt time.Time       `json:"created_at"`
+	// ArchivedAt is the time the function was archived, if archived.
+	ArchivedAt *time.Time `json:"archived_at,omitempty"`
+	// WorkspaceID is the workspace that the function is registered within.
+	WorkspaceID uuid.UUID `json:"workspace_id"`
 }
 
 func (f Function) InngestFunction() (*inngest.Function, error) {
@@ -24,6 +28,7 @@ func (f Function) InngestFunction() (*inngest.Function, error) {
 	if err != nil {
 		return nil, err
 	}
+	fn.ArchivedAt = f.ArchivedAt
 	return &fn, nil
 }
 
@@ -55,7 +60,10 @@ type DevFunctionManager interface {
 
 // DevFunctionReader is a development-only function reader.
 type DevFunctionReader interface {
-	GetFunctions(ctx context.Context) ([]*Function, error)
+	// GetFunctions returns functions within the given workspace.
+	GetFunctions(ctx context.Context, workspaceID uuid.UUID) ([]*Function, error)
+	// GetAllFunctions returns functions within every workspace.
+	GetAllFunctions(ctx context.Context) ([]*Function, error)
 }
 
 type DevFunctionWriter interface {
@@ -65,15 +73,28 @@ type DevFunctionWriter interface {
 	DeleteFunctionsByAppID(ctx context.Context, appID uuid.UUID) error
 	// DeleteFunctionsByIDs deletes all functions with the given IDs
 	DeleteFunctionsByIDs(ctx context.Context, ids []uuid.UUID) error
+
+	FunctionArchiver
+}
+
+// FunctionArchiver archives and unarchives functions.  Archived functions no
+// longer start new runs from events or crons, while existing runs complete and
+// the function's history remains queryable.
+type FunctionArchiver interface {
+	// ArchiveFunction archives a function, so that it no longer starts new runs.
+	ArchiveFunction(ctx context.Context, id uuid.UUID) (*Function, error)
+	// UnarchiveFunction unarchives a function, so that it starts new runs.
+	UnarchiveFunction(ctx context.Context, id uuid.UUID) (*Function, error)
 }
 
 type InsertFunctionParams struct {
-	ID        uuid.UUID
-	AppID     uuid.UUID
-	Name      string
-	Slug      string
-	Config    string
-	CreatedAt time.Time
+	ID          uuid.UUID
+	AppID       uuid.UUID
+	Name        string
+	Slug        string
+	Config      string
+	CreatedAt   time.Time
+	WorkspaceID uuid.UUID
 }
 
 type UpdateFunctionConfigParams struct {
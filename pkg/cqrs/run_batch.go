@@ -0,0 +1,35 @@
+package cqrs
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/oklog/ulid/v2"
+)
+
+// ErrRunBatchNotFound is returned when a run's batch of events can't be found,
+// eg. when the run has finished and its state has been deleted.
+var ErrRunBatchNotFound = fmt.Errorf("run batch not found")
+
+// RunBatchReader loads the batch of events which triggered a run.  SDKs use
+// this to fetch batches which aren't sent in full with each request.
+type RunBatchReader interface {
+	// GetRunBatch returns the run's events, in the order they were appended to
+	// the batch, or ErrRunBatchNotFound if the run can't be found.
+	GetRunBatch(
+		ctx context.Context,
+		accountID uuid.UUID,
+		workspaceID uuid.UUID,
+		runID ulid.ULID,
+	) (*RunBatch, error)
+}
+
+// RunBatch is the batch of events which triggered a run.
+type RunBatch struct {
+	RunID ulid.ULID `json:"run_id"`
+	// EventIDs lists the internal ID of each event.
+	EventIDs []ulid.ULID `json:"event_ids"`
+	// Events lists each event's payload.
+	Events []map[string]any `json:"events"`
+}
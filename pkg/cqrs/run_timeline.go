@@ -0,0 +1,271 @@
+package cqrs
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/inngest/inngest/pkg/enums"
+	"github.com/inngest/inngest/pkg/execution"
+	"github.com/inngest/inngest/pkg/execution/history"
+	"github.com/oklog/ulid/v2"
+)
+
+// Step types within a RunTimeline.
+const (
+	RunTimelineStepRun    = "run"
+	RunTimelineStepSend   = "send"
+	RunTimelineStepSleep  = "sleep"
+	RunTimelineStepWait   = "wait"
+	RunTimelineStepInvoke = "invoke"
+)
+
+// RunTimelineReader loads a run's timeline from a backing store.
+type RunTimelineReader interface {
+	// GetRunTimeline returns the run's trigger, each step and the run's result
+	// as a single timeline, ordered from oldest to newest.
+	GetRunTimeline(
+		ctx context.Context,
+		accountID uuid.UUID,
+		workspaceID uuid.UUID,
+		runID ulid.ULID,
+	) (*RunTimeline, error)
+}
+
+// RunTimeline represents a run's trigger, steps and result, merged from the run's
+// history so that clients don't need to reassemble steps from each history item.
+type RunTimeline struct {
+	RunID      ulid.ULID          `json:"run_id"`
+	FunctionID uuid.UUID          `json:"function_id"`
+	Trigger    RunTimelineTrigger `json:"trigger"`
+	// Steps are ordered by the time each step was first scheduled.
+	Steps []RunTimelineStep `json:"steps"`
+	// Result is nil until the run finishes.
+	Result *RunTimelineResult `json:"result,omitempty"`
+}
+
+// RunTimelineTrigger represents the event, batch or cron schedule which started
+// the run.
+type RunTimelineTrigger struct {
+	EventID       ulid.ULID  `json:"event_id"`
+	BatchID       *ulid.ULID `json:"batch_id,omitempty"`
+	Cron          *string    `json:"cron,omitempty"`
+	OriginalRunID *ulid.ULID `json:"original_run_id,omitempty"`
+	ScheduledAt   *time.Time `json:"scheduled_at,omitempty"`
+	StartedAt     time.Time  `json:"started_at"`
+}
+
+// RunTimelineStep represents a single step within a run, including each attempt.
+type RunTimelineStep struct {
+	GroupID *uuid.UUID `json:"group_id,omitempty"`
+	StepID  *string    `json:"step_id,omitempty"`
+	Name    *string    `json:"name,omitempty"`
+	// Type is the kind of step, eg. RunTimelineStepSleep.
+	Type string `json:"type"`
+	// Status is the type of the step's latest history item, eg. StepCompleted.
+	Status    enums.HistoryType `json:"status"`
+	StartedAt time.Time         `json:"started_at"`
+	// EndedAt is nil until the step completes or permanently fails.
+	EndedAt  *time.Time           `json:"ended_at,omitempty"`
+	Attempts []RunTimelineAttempt `json:"attempts"`
+
+	Sleep  *history.Sleep     `json:"sleep,omitempty"`
+	Wait   *RunTimelineWait   `json:"wait,omitempty"`
+	Invoke *RunTimelineInvoke `json:"invoke,omitempty"`
+}
+
+// RunTimelineAttempt represents the result of a single attempt of a step.
+type RunTimelineAttempt struct {
+	Attempt int64 `json:"attempt"`
+	// Status is StepCompleted, StepErrored or StepFailed.
+	Status     enums.HistoryType `json:"status"`
+	At         time.Time         `json:"at"`
+	DurationMS int               `json:"duration_ms"`
+	Output     *string           `json:"output,omitempty"`
+}
+
+// RunTimelineWait represents a step waiting for an event.
+type RunTimelineWait struct {
+	EventName  string    `json:"event_name"`
+	Expression *string   `json:"expression,omitempty"`
+	Timeout    time.Time `json:"timeout"`
+	// EventID is the ID of the matching event, once received.
+	EventID  *ulid.ULID `json:"event_id,omitempty"`
+	TimedOut bool       `json:"timed_out"`
+}
+
+// RunTimelineInvoke represents a step invoking another function.
+type RunTimelineInvoke struct {
+	FunctionID    string    `json:"function_id"`
+	EventID       ulid.ULID `json:"event_id"`
+	CorrelationID string    `json:"correlation_id"`
+	Timeout       time.Time `json:"timeout"`
+	// RunID is the ID of the invoked function's run, once the run finishes.
+	RunID    *ulid.ULID `json:"run_id,omitempty"`
+	TimedOut bool       `json:"timed_out"`
+}
+
+// RunTimelineResult represents the run's final status and output.
+type RunTimelineResult struct {
+	Status  enums.RunStatus          `json:"status"`
+	EndedAt *time.Time               `json:"ended_at,omitempty"`
+	Output  json.RawMessage          `json:"output,omitempty"`
+	Cancel  *execution.CancelRequest `json:"cancel,omitempty"`
+}
+
+// NewRunTimeline merges the run and its history into a single timeline.  History
+// items are grouped into steps by their group ID, as each step's items share the
+// group ID of the queue item which scheduled the step.
+func NewRunTimeline(run FunctionRun, items []history.History) *RunTimeline {
+	items = append([]history.History{}, items...)
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].CreatedAt.Before(items[j].CreatedAt)
+	})
+
+	tl := &RunTimeline{
+		RunID:      run.RunID,
+		FunctionID: run.FunctionID,
+		Trigger: RunTimelineTrigger{
+			EventID:       run.EventID,
+			BatchID:       run.BatchID,
+			Cron:          run.Cron,
+			OriginalRunID: run.OriginalRunID,
+			StartedAt:     run.RunStartedAt,
+		},
+		Steps: []RunTimelineStep{},
+	}
+
+	steps := map[string]int{}
+	for _, item := range items {
+		typ, err := enums.HistoryTypeString(item.Type)
+		if err != nil {
+			continue
+		}
+
+		switch typ {
+		case enums.HistoryTypeFunctionScheduled:
+			at := item.CreatedAt
+			tl.Trigger.ScheduledAt = &at
+			continue
+		case enums.HistoryTypeFunctionCancelled:
+			tl.Result = &RunTimelineResult{Cancel: item.Cancel}
+			continue
+		case enums.HistoryTypeNone,
+			enums.HistoryTypeFunctionStarted,
+			enums.HistoryTypeFunctionCompleted,
+			enums.HistoryTypeFunctionFailed,
+			enums.HistoryTypeFunctionStatusUpdated:
+			continue
+		}
+
+		key := ""
+		switch {
+		case item.GroupID != nil:
+			key = item.GroupID.String()
+		case item.StepID != nil:
+			key = *item.StepID
+		default:
+			key = item.ID.String()
+		}
+		n, ok := steps[key]
+		if !ok {
+			n = len(tl.Steps)
+			steps[key] = n
+			tl.Steps = append(tl.Steps, RunTimelineStep{
+				GroupID:   item.GroupID,
+				Type:      RunTimelineStepRun,
+				StartedAt: item.CreatedAt,
+				Attempts:  []RunTimelineAttempt{},
+			})
+		}
+		tl.Steps[n].apply(typ, item)
+	}
+
+	if enums.RunStatusEnded(run.Status) {
+		if tl.Result == nil {
+			tl.Result = &RunTimelineResult{}
+		}
+		tl.Result.Status = run.Status
+		tl.Result.EndedAt = run.EndedAt
+		tl.Result.Output = run.Output
+	}
+
+	return tl
+}
+
+// apply updates the step with the given history item.
+func (s *RunTimelineStep) apply(typ enums.HistoryType, item history.History) {
+	s.Status = typ
+	if item.StepName != nil && *item.StepName != "" {
+		s.Name = item.StepName
+	}
+	// Items which schedule and start a step use the step's edge as the step ID,
+	// and are replaced by the step's ID once the step returns.
+	if item.StepID != nil && (s.StepID == nil || typ != enums.HistoryTypeStepScheduled && typ != enums.HistoryTypeStepStarted) {
+		s.StepID = item.StepID
+	}
+	if item.StepType != nil {
+		switch *item.StepType {
+		case enums.HistoryStepTypeSend:
+			s.Type = RunTimelineStepSend
+		case enums.HistoryStepTypeSleep:
+			s.Type = RunTimelineStepSleep
+		case enums.HistoryStepTypeWait:
+			s.Type = RunTimelineStepWait
+		}
+	}
+
+	switch typ {
+	case enums.HistoryTypeStepSleeping:
+		s.Type = RunTimelineStepSleep
+		s.Sleep = item.Sleep
+	case enums.HistoryTypeStepWaiting:
+		s.Type = RunTimelineStepWait
+		if item.WaitForEvent != nil {
+			s.Wait = &RunTimelineWait{
+				EventName:  item.WaitForEvent.EventName,
+				Expression: item.WaitForEvent.Expression,
+				Timeout:    item.WaitForEvent.Timeout,
+			}
+		}
+	case enums.HistoryTypeStepInvoking:
+		s.Type = RunTimelineStepInvoke
+		if item.InvokeFunction != nil {
+			s.Invoke = &RunTimelineInvoke{
+				FunctionID:    item.InvokeFunction.FunctionID,
+				EventID:       item.InvokeFunction.EventID,
+				CorrelationID: item.InvokeFunction.CorrelationID,
+				Timeout:       item.InvokeFunction.Timeout,
+			}
+		}
+	case enums.HistoryTypeStepCompleted, enums.HistoryTypeStepErrored, enums.HistoryTypeStepFailed:
+		attempt := RunTimelineAttempt{
+			Attempt: item.Attempt,
+			Status:  typ,
+			At:      item.CreatedAt,
+		}
+		if item.Result != nil {
+			attempt.DurationMS = item.Result.DurationMS
+			if item.Result.Output != "" {
+				output := item.Result.Output
+				attempt.Output = &output
+			}
+		}
+		s.Attempts = append(s.Attempts, attempt)
+
+		if item.WaitResult != nil && s.Wait != nil {
+			s.Wait.EventID = item.WaitResult.EventID
+			s.Wait.TimedOut = item.WaitResult.Timeout
+		}
+		if item.InvokeFunctionResult != nil && s.Invoke != nil {
+			s.Invoke.RunID = item.InvokeFunctionResult.RunID
+			s.Invoke.TimedOut = item.InvokeFunctionResult.Timeout
+		}
+		if typ != enums.HistoryTypeStepErrored {
+			at := item.CreatedAt
+			s.EndedAt = &at
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package cqrs
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/inngest/inngest/pkg/enums"
+	"github.com/inngest/inngest/pkg/execution/history"
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRunTimeline(t *testing.T) {
+	start := time.Now().Add(-time.Minute)
+	at := func(n int) time.Time { return start.Add(time.Duration(n) * time.Second) }
+	strptr := func(s string) *string { return &s }
+
+	runID := ulid.Make()
+	childRunID := ulid.Make()
+	matchID := ulid.Make()
+	first, wait, invoke := uuid.New(), uuid.New(), uuid.New()
+	ended := at(10)
+
+	run := FunctionRun{
+		RunID:        runID,
+		RunStartedAt: start,
+		FunctionID:   uuid.New(),
+		EventID:      ulid.Make(),
+		Status:       enums.RunStatusCompleted,
+		EndedAt:      &ended,
+		Output:       json.RawMessage(`{"ok":true}`),
+	}
+
+	items := []history.History{
+		// Items are sorted by creation time.
+		{
+			Type:      enums.HistoryTypeStepCompleted.String(),
+			CreatedAt: at(3),
+			GroupID:   &first,
+			Attempt:   1,
+			StepID:    strptr("fetch"),
+			StepName:  strptr("fetch"),
+			Result:    &history.Result{Output: `{"id":1}`, DurationMS: 12},
+		},
+		{Type: enums.HistoryTypeFunctionScheduled.String(), CreatedAt: at(0), GroupID: &first},
+		{Type: enums.HistoryTypeFunctionStarted.String(), CreatedAt: at(1), GroupID: &first},
+		{
+			Type:      enums.HistoryTypeStepErrored.String(),
+			CreatedAt: at(2),
+			GroupID:   &first,
+			StepID:    strptr("fetch"),
+			StepName:  strptr("fetch"),
+			Result:    &history.Result{Output: `{"error":"timeout"}`},
+		},
+		{
+			Type:         enums.HistoryTypeStepWaiting.String(),
+			CreatedAt:    at(4),
+			GroupID:      &wait,
+			StepID:       strptr("approval"),
+			StepName:     strptr("approval"),
+			WaitForEvent: &history.WaitForEvent{EventName: "app/approved", Expression: strptr("async.data.id == event.data.id")},
+		},
+		{
+			Type:       enums.HistoryTypeStepCompleted.String(),
+			CreatedAt:  at(5),
+			GroupID:    &wait,
+			WaitResult: &history.WaitResult{EventID: &matchID},
+		},
+		{
+			Type:           enums.HistoryTypeStepInvoking.String(),
+			CreatedAt:      at(6),
+			GroupID:        &invoke,
+			StepID:         strptr("child"),
+			StepName:       strptr("child"),
+			InvokeFunction: &history.InvokeFunction{FunctionID: "app-child"},
+		},
+		{
+			Type:                 enums.HistoryTypeStepCompleted.String(),
+			CreatedAt:            at(7),
+			GroupID:              &invoke,
+			InvokeFunctionResult: &history.InvokeFunctionResult{RunID: &childRunID},
+			Result:               &history.Result{Output: `"done"`},
+		},
+		{Type: enums.HistoryTypeFunctionCompleted.String(), CreatedAt: at(10), GroupID: &invoke},
+	}
+
+	tl := NewRunTimeline(run, items)
+	require.Equal(t, runID, tl.RunID)
+	require.NotNil(t, tl.Trigger.ScheduledAt)
+	require.Equal(t, at(0), *tl.Trigger.ScheduledAt)
+	require.Equal(t, run.EventID, tl.Trigger.EventID)
+	require.Len(t, tl.Steps, 3)
+
+	step := tl.Steps[0]
+	require.Equal(t, RunTimelineStepRun, step.Type)
+	require.Equal(t, "fetch", *step.StepID)
+	require.Equal(t, enums.HistoryTypeStepCompleted, step.Status)
+	require.Len(t, step.Attempts, 2)
+	require.Equal(t, enums.HistoryTypeStepErrored, step.Attempts[0].Status)
+	require.Equal(t, int64(1), step.Attempts[1].Attempt)
+	require.Equal(t, `{"id":1}`, *step.Attempts[1].Output)
+	require.Equal(t, at(3), *step.EndedAt)
+
+	step = tl.Steps[1]
+	require.Equal(t, RunTimelineStepWait, step.Type)
+	require.Equal(t, "approval", *step.StepID)
+	require.Equal(t, "app/approved", step.Wait.EventName)
+	require.Equal(t, "async.data.id == event.data.id", *step.Wait.Expression)
+	require.Equal(t, matchID, *step.Wait.EventID)
+	require.False(t, step.Wait.TimedOut)
+
+	step = tl.Steps[2]
+	require.Equal(t, RunTimelineStepInvoke, step.Type)
+	require.Equal(t, "app-child", step.Invoke.FunctionID)
+	require.Equal(t, childRunID, *step.Invoke.RunID)
+
+	require.NotNil(t, tl.Result)
+	require.Equal(t, enums.RunStatusCompleted, tl.Result.Status)
+	require.JSONEq(t, `{"ok":true}`, string(tl.Result.Output))
+
+	t.Run("Running runs have no result", func(t *testing.T) {
+		run.Status = enums.RunStatusRunning
+		tl := NewRunTimeline(run, items[:4])
+		require.Nil(t, tl.Result)
+		require.Len(t, tl.Steps, 1)
+	})
+}
@@ -2,8 +2,12 @@ package sqlitecqrs
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -15,6 +19,7 @@ import (
 	"github.com/inngest/inngest/pkg/enums"
 	"github.com/inngest/inngest/pkg/execution/history"
 	"github.com/inngest/inngest/pkg/execution/state"
+	"github.com/inngest/inngest/pkg/expressions"
 	"github.com/inngest/inngest/pkg/inngest"
 	"github.com/inngest/inngest/pkg/util"
 	"github.com/jinzhu/copier"
@@ -44,6 +49,15 @@ type wrapper struct {
 	tx *sql.Tx
 }
 
+// Ping implements the cqrs.Pinger interface.
+func (w wrapper) Ping(ctx context.Context) error {
+	if w.db == nil {
+		// Transactions hold a connection which was checked when beginning the tx.
+		return nil
+	}
+	return w.db.PingContext(ctx)
+}
+
 // LoadFunction implements the state.FunctionLoader interface.
 func (w wrapper) LoadFunction(ctx context.Context, identifier state.Identifier) (*inngest.Function, error) {
 	// XXX: This doesn't store versions, as the dev server is currently ignorant to version.s
@@ -81,14 +95,20 @@ func (w wrapper) Rollback(ctx context.Context) error {
 // Apps
 //
 
-// GetApps returns apps that have not been deleted.
-func (w wrapper) GetApps(ctx context.Context) ([]*cqrs.App, error) {
-	return copyInto(ctx, w.q.GetApps, []*cqrs.App{})
+// GetApps returns apps within the workspace that have not been deleted.
+func (w wrapper) GetApps(ctx context.Context, workspaceID uuid.UUID) ([]*cqrs.App, error) {
+	f := func(ctx context.Context) ([]*sqlc.App, error) {
+		return w.q.GetApps(ctx, workspaceID)
+	}
+	return copyInto(ctx, f, []*cqrs.App{})
 }
 
-func (w wrapper) GetAppByChecksum(ctx context.Context, checksum string) (*cqrs.App, error) {
+func (w wrapper) GetAppByChecksum(ctx context.Context, workspaceID uuid.UUID, checksum string) (*cqrs.App, error) {
 	f := func(ctx context.Context) (*sqlc.App, error) {
-		return w.q.GetAppByChecksum(ctx, checksum)
+		return w.q.GetAppByChecksum(ctx, sqlc.GetAppByChecksumParams{
+			WorkspaceID: workspaceID,
+			Checksum:    checksum,
+		})
 	}
 	return copyInto(ctx, f, &cqrs.App{})
 }
@@ -100,19 +120,25 @@ func (w wrapper) GetAppByID(ctx context.Context, id uuid.UUID) (*cqrs.App, error
 	return copyInto(ctx, f, &cqrs.App{})
 }
 
-func (w wrapper) GetAppByURL(ctx context.Context, url string) (*cqrs.App, error) {
+func (w wrapper) GetAppByURL(ctx context.Context, workspaceID uuid.UUID, url string) (*cqrs.App, error) {
 	// Normalize the URL before inserting into the DB.
 	url = util.NormalizeAppURL(url, forceHTTPS)
 
 	f := func(ctx context.Context) (*sqlc.App, error) {
-		return w.q.GetAppByURL(ctx, url)
+		return w.q.GetAppByURL(ctx, sqlc.GetAppByURLParams{
+			WorkspaceID: workspaceID,
+			Url:         url,
+		})
 	}
 	return copyInto(ctx, f, &cqrs.App{})
 }
 
-// GetAllApps returns all apps.
-func (w wrapper) GetAllApps(ctx context.Context) ([]*cqrs.App, error) {
-	return copyInto(ctx, w.q.GetAllApps, []*cqrs.App{})
+// GetAllApps returns all apps within the workspace.
+func (w wrapper) GetAllApps(ctx context.Context, workspaceID uuid.UUID) ([]*cqrs.App, error) {
+	f := func(ctx context.Context) ([]*sqlc.App, error) {
+		return w.q.GetAllApps(ctx, workspaceID)
+	}
+	return copyInto(ctx, f, []*cqrs.App{})
 }
 
 // InsertApp creates a new app.
@@ -195,43 +221,57 @@ func (w wrapper) DeleteApp(ctx context.Context, id uuid.UUID) error {
 // Functions
 //
 
-func (w wrapper) GetAppFunctions(ctx context.Context, appID uuid.UUID) ([]*cqrs.Function, error) {
+func (w wrapper) GetAppFunctions(ctx context.Context, workspaceID, appID uuid.UUID) ([]*cqrs.Function, error) {
 	f := func(ctx context.Context) ([]*sqlc.Function, error) {
-		return w.q.GetAppFunctions(ctx, appID)
+		return w.q.GetAppFunctions(ctx, sqlc.GetAppFunctionsParams{
+			WorkspaceID: workspaceID,
+			AppID:       appID,
+		})
 	}
 	return copyInto(ctx, f, []*cqrs.Function{})
 }
 
 func (w wrapper) GetFunctionByExternalID(ctx context.Context, wsID uuid.UUID, appID, fnSlug string) (*cqrs.Function, error) {
 	f := func(ctx context.Context) (*sqlc.Function, error) {
-		return w.q.GetFunctionBySlug(ctx, fnSlug)
+		return w.q.GetFunctionBySlug(ctx, sqlc.GetFunctionBySlugParams{
+			WorkspaceID: wsID,
+			Slug:        fnSlug,
+		})
 	}
 	return copyInto(ctx, f, &cqrs.Function{})
 }
 
 func (w wrapper) GetFunctionByInternalUUID(ctx context.Context, wsID, fnID uuid.UUID) (*cqrs.Function, error) {
 	f := func(ctx context.Context) (*sqlc.Function, error) {
-		return w.q.GetFunctionByID(ctx, fnID)
+		return w.q.GetWorkspaceFunctionByID(ctx, sqlc.GetWorkspaceFunctionByIDParams{
+			WorkspaceID: wsID,
+			ID:          fnID,
+		})
 	}
 	return copyInto(ctx, f, &cqrs.Function{})
 }
 
-func (w wrapper) GetFunctions(ctx context.Context) ([]*cqrs.Function, error) {
-	return copyInto(ctx, w.q.GetFunctions, []*cqrs.Function{})
-}
-
-func (w wrapper) GetFunctionsByAppInternalID(ctx context.Context, workspaceID, appID uuid.UUID) ([]*cqrs.Function, error) {
+func (w wrapper) GetFunctions(ctx context.Context, workspaceID uuid.UUID) ([]*cqrs.Function, error) {
 	f := func(ctx context.Context) ([]*sqlc.Function, error) {
-		// Ingore the workspace ID for now.
-		return w.q.GetAppFunctions(ctx, appID)
+		return w.q.GetFunctions(ctx, workspaceID)
 	}
 	return copyInto(ctx, f, []*cqrs.Function{})
 }
 
+func (w wrapper) GetAllFunctions(ctx context.Context) ([]*cqrs.Function, error) {
+	return copyInto(ctx, w.q.GetAllFunctions, []*cqrs.Function{})
+}
+
+func (w wrapper) GetFunctionsByAppInternalID(ctx context.Context, workspaceID, appID uuid.UUID) ([]*cqrs.Function, error) {
+	return w.GetAppFunctions(ctx, workspaceID, appID)
+}
+
 func (w wrapper) GetFunctionsByAppExternalID(ctx context.Context, workspaceID uuid.UUID, appID string) ([]*cqrs.Function, error) {
 	f := func(ctx context.Context) ([]*sqlc.Function, error) {
-		// Ingore the workspace ID for now.
-		return w.q.GetAppFunctionsBySlug(ctx, appID)
+		return w.q.GetAppFunctionsBySlug(ctx, sqlc.GetAppFunctionsBySlugParams{
+			WorkspaceID: workspaceID,
+			Name:        appID,
+		})
 	}
 	return copyInto(ctx, f, []*cqrs.Function{})
 }
@@ -264,6 +304,24 @@ func (w wrapper) UpdateFunctionConfig(ctx context.Context, arg cqrs.UpdateFuncti
 	)
 }
 
+func (w wrapper) ArchiveFunction(ctx context.Context, id uuid.UUID) (*cqrs.Function, error) {
+	return w.updateFunctionArchivedAt(ctx, id, sql.NullTime{Time: time.Now(), Valid: true})
+}
+
+func (w wrapper) UnarchiveFunction(ctx context.Context, id uuid.UUID) (*cqrs.Function, error) {
+	return w.updateFunctionArchivedAt(ctx, id, sql.NullTime{})
+}
+
+func (w wrapper) updateFunctionArchivedAt(ctx context.Context, id uuid.UUID, at sql.NullTime) (*cqrs.Function, error) {
+	f := func(ctx context.Context) (*sqlc.Function, error) {
+		return w.q.UpdateFunctionArchivedAt(ctx, sqlc.UpdateFunctionArchivedAtParams{
+			ArchivedAt: at,
+			ID:         id,
+		})
+	}
+	return copyInto(ctx, f, &cqrs.Function{})
+}
+
 //
 // Events
 //
@@ -278,12 +336,13 @@ func (w wrapper) InsertEvent(ctx context.Context, e cqrs.Event) error {
 		return err
 	}
 	evt := sqlc.InsertEventParams{
-		InternalID: e.ID,
-		ReceivedAt: time.Now(),
-		EventID:    e.EventID,
-		EventName:  e.EventName,
-		EventData:  string(data),
-		EventUser:  string(user),
+		InternalID:  e.ID,
+		WorkspaceID: e.WorkspaceID,
+		ReceivedAt:  time.Now(),
+		EventID:     e.EventID,
+		EventName:   e.EventName,
+		EventData:   string(data),
+		EventUser:   string(user),
 		EventV: sql.NullString{
 			Valid:  e.EventVersion != "",
 			String: e.EventVersion,
@@ -363,7 +422,14 @@ func (w wrapper) GetEventsByInternalIDs(ctx context.Context, ids []ulid.ULID) ([
 }
 
 func (w wrapper) FindEvent(ctx context.Context, workspaceID uuid.UUID, internalID ulid.ULID) (*cqrs.Event, error) {
-	return w.GetEventByInternalID(ctx, internalID)
+	evt, err := w.GetEventByInternalID(ctx, internalID)
+	if err != nil {
+		return nil, err
+	}
+	if evt.WorkspaceID != workspaceID {
+		return nil, sql.ErrNoRows
+	}
+	return evt, nil
 }
 
 func (w wrapper) WorkspaceEvents(ctx context.Context, workspaceID uuid.UUID, opts *cqrs.WorkspaceEventsOpts) ([]cqrs.Event, error) {
@@ -372,6 +438,9 @@ func (w wrapper) WorkspaceEvents(ctx context.Context, workspaceID uuid.UUID, opt
 	}
 	if opts.Cursor == nil {
 		opts.Cursor = &endULID
+		if opts.Order.Ascending() {
+			opts.Cursor = &nilULID
+		}
 	}
 
 	var (
@@ -379,21 +448,41 @@ func (w wrapper) WorkspaceEvents(ctx context.Context, workspaceID uuid.UUID, opt
 		err  error
 	)
 
-	if opts.Name == nil {
+	switch {
+	case opts.Name == nil && opts.Order.Ascending():
+		evts, err = w.q.WorkspaceEventsAsc(ctx, sqlc.WorkspaceEventsAscParams{
+			WorkspaceID: workspaceID,
+			Cursor:      *opts.Cursor,
+			Before:      opts.Newest,
+			After:       opts.Oldest,
+			Limit:       int64(opts.Limit),
+		})
+	case opts.Name == nil:
 		params := sqlc.WorkspaceEventsParams{
-			Cursor: *opts.Cursor,
-			Before: opts.Newest,
-			After:  opts.Oldest,
-			Limit:  int64(opts.Limit),
+			WorkspaceID: workspaceID,
+			Cursor:      *opts.Cursor,
+			Before:      opts.Newest,
+			After:       opts.Oldest,
+			Limit:       int64(opts.Limit),
 		}
 		evts, err = w.q.WorkspaceEvents(ctx, params)
-	} else {
+	case opts.Order.Ascending():
+		evts, err = w.q.WorkspaceNamedEventsAsc(ctx, sqlc.WorkspaceNamedEventsAscParams{
+			WorkspaceID: workspaceID,
+			Name:        *opts.Name,
+			Cursor:      *opts.Cursor,
+			Before:      opts.Newest,
+			After:       opts.Oldest,
+			Limit:       int64(opts.Limit),
+		})
+	default:
 		params := sqlc.WorkspaceNamedEventsParams{
-			Name:   *opts.Name,
-			Cursor: *opts.Cursor,
-			Before: opts.Newest,
-			After:  opts.Oldest,
-			Limit:  int64(opts.Limit),
+			WorkspaceID: workspaceID,
+			Name:        *opts.Name,
+			Cursor:      *opts.Cursor,
+			Before:      opts.Newest,
+			After:       opts.Oldest,
+			Limit:       int64(opts.Limit),
 		}
 		evts, err = w.q.WorkspaceNamedEvents(ctx, params)
 	}
@@ -410,6 +499,7 @@ func (w wrapper) WorkspaceEvents(ctx context.Context, workspaceID uuid.UUID, opt
 
 func (w wrapper) GetEventsTimebound(
 	ctx context.Context,
+	workspaceID uuid.UUID,
 	t cqrs.Timebound,
 	limit int,
 	includeInternal bool,
@@ -424,6 +514,7 @@ func (w wrapper) GetEventsTimebound(
 	}
 
 	evts, err := w.q.GetEventsTimebound(ctx, sqlc.GetEventsTimeboundParams{
+		WorkspaceID:     workspaceID,
 		After:           after,
 		Before:          before,
 		IncludeInternal: strconv.FormatBool(includeInternal),
@@ -444,6 +535,7 @@ func (w wrapper) GetEventsTimebound(
 func convertEvent(obj *sqlc.Event) cqrs.Event {
 	evt := &cqrs.Event{
 		ID:           obj.InternalID,
+		WorkspaceID:  obj.WorkspaceID,
 		ReceivedAt:   obj.ReceivedAt,
 		EventID:      obj.EventID,
 		EventName:    obj.EventName,
@@ -476,6 +568,182 @@ func convertEventBatch(obj *sqlc.EventBatch) cqrs.EventBatch {
 	return *eb
 }
 
+//
+// Event keys
+//
+
+// eventKeyPrefixLen is the number of characters of an event key which are stored
+// in plaintext, allowing keys to be identified.
+const eventKeyPrefixLen = 8
+
+func (w wrapper) GetEventKeys(ctx context.Context) ([]*cqrs.EventKey, error) {
+	objs, err := w.q.GetEventKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]*cqrs.EventKey, len(objs))
+	for n, obj := range objs {
+		keys[n] = convertEventKey(obj)
+	}
+	return keys, nil
+}
+
+func (w wrapper) GetEventKeyByID(ctx context.Context, id ulid.ULID) (*cqrs.EventKey, error) {
+	obj, err := w.q.GetEventKeyByID(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, cqrs.ErrEventKeyNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return convertEventKey(obj), nil
+}
+
+func (w wrapper) GetEventKeyByKey(ctx context.Context, key string) (*cqrs.EventKey, error) {
+	obj, err := w.q.GetEventKeyByHash(ctx, hashEventKey(key))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, cqrs.ErrEventKeyNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return convertEventKey(obj), nil
+}
+
+func (w wrapper) HasEventKeys(ctx context.Context) (bool, error) {
+	count, err := w.q.CountActiveEventKeys(ctx)
+	return count > 0, err
+}
+
+func (w wrapper) CreateEventKey(ctx context.Context, opts cqrs.CreateEventKeyOpts) (*cqrs.EventKey, error) {
+	key, err := newEventKey()
+	if err != nil {
+		return nil, err
+	}
+
+	scopes := opts.Scopes
+	if len(scopes) == 0 {
+		scopes = cqrs.DefaultEventKeyScopes
+	}
+
+	params := sqlc.InsertEventKeyParams{
+		ID:        ulid.MustNew(ulid.Now(), rand.Reader),
+		Name:      opts.Name,
+		Hash:      hashEventKey(key),
+		Prefix:    key[:eventKeyPrefixLen],
+		CreatedAt: time.Now(),
+		Scopes:    encodeEventKeyScopes(scopes),
+	}
+	if opts.Source != nil {
+		params.Source = sql.NullString{String: *opts.Source, Valid: true}
+	}
+	if opts.Env != "" {
+		params.Env = sql.NullString{String: opts.Env, Valid: true}
+	}
+
+	obj, err := w.q.InsertEventKey(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	result := convertEventKey(obj)
+	result.Key = key
+	return result, nil
+}
+
+func (w wrapper) RotateEventKey(ctx context.Context, id ulid.ULID) (*cqrs.EventKey, error) {
+	key, err := newEventKey()
+	if err != nil {
+		return nil, err
+	}
+
+	obj, err := w.q.RotateEventKey(ctx, sqlc.RotateEventKeyParams{
+		ID:        id,
+		Hash:      hashEventKey(key),
+		Prefix:    key[:eventKeyPrefixLen],
+		RotatedAt: sql.NullTime{Time: time.Now(), Valid: true},
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, cqrs.ErrEventKeyNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	result := convertEventKey(obj)
+	result.Key = key
+	return result, nil
+}
+
+func (w wrapper) RevokeEventKey(ctx context.Context, id ulid.ULID) (*cqrs.EventKey, error) {
+	obj, err := w.q.RevokeEventKey(ctx, sqlc.RevokeEventKeyParams{
+		ID:        id,
+		RevokedAt: sql.NullTime{Time: time.Now(), Valid: true},
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, cqrs.ErrEventKeyNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return convertEventKey(obj), nil
+}
+
+// newEventKey returns a new random plaintext event key.
+func newEventKey() (string, error) {
+	byt := make([]byte, 32)
+	if _, err := rand.Read(byt); err != nil {
+		return "", fmt.Errorf("error generating event key: %w", err)
+	}
+	return hex.EncodeToString(byt), nil
+}
+
+// hashEventKey returns the hash of a plaintext event key, which is the value
+// stored in the database.
+func hashEventKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// encodeEventKeyScopes returns the comma separated scopes stored in the
+// database.
+func encodeEventKeyScopes(scopes []cqrs.EventKeyScope) string {
+	strs := make([]string, len(scopes))
+	for n, s := range scopes {
+		strs[n] = string(s)
+	}
+	return strings.Join(strs, ",")
+}
+
+func decodeEventKeyScopes(s string) []cqrs.EventKeyScope {
+	scopes := []cqrs.EventKeyScope{}
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			scopes = append(scopes, cqrs.EventKeyScope(v))
+		}
+	}
+	return scopes
+}
+
+func convertEventKey(obj *sqlc.EventKey) *cqrs.EventKey {
+	key := &cqrs.EventKey{
+		ID:        obj.ID,
+		Name:      obj.Name,
+		Scopes:    decodeEventKeyScopes(obj.Scopes),
+		Prefix:    obj.Prefix,
+		CreatedAt: obj.CreatedAt,
+		Env:       obj.Env.String,
+	}
+	if obj.Source.Valid {
+		key.Source = &obj.Source.String
+	}
+	if obj.RotatedAt.Valid {
+		key.RotatedAt = &obj.RotatedAt.Time
+	}
+	if obj.RevokedAt.Valid {
+		key.RevokedAt = &obj.RevokedAt.Time
+	}
+	return key
+}
+
 //
 // Function runs
 //
@@ -497,13 +765,23 @@ func (w wrapper) InsertFunctionRun(ctx context.Context, e cqrs.FunctionRun) erro
 	return w.q.InsertFunctionRun(ctx, run)
 }
 
+func (w wrapper) UpdateFunctionRunStatus(ctx context.Context, runID ulid.ULID, status enums.RunStatus) error {
+	return w.q.UpdateFunctionRunStatus(ctx, sqlc.UpdateFunctionRunStatusParams{
+		Status: sql.NullString{Valid: true, String: status.String()},
+		RunID:  runID,
+	})
+}
+
 func (w wrapper) GetFunctionRunsFromEvents(
 	ctx context.Context,
 	accountID uuid.UUID,
 	workspaceID uuid.UUID,
 	eventIDs []ulid.ULID,
 ) ([]*cqrs.FunctionRun, error) {
-	runs, err := w.q.GetFunctionRunsFromEvents(ctx, eventIDs)
+	runs, err := w.q.GetFunctionRunsFromEvents(ctx, sqlc.GetFunctionRunsFromEventsParams{
+		WorkspaceID: workspaceID,
+		EventIds:    eventIDs,
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -520,14 +798,17 @@ func (w wrapper) GetFunctionRun(
 	workspaceID uuid.UUID,
 	id ulid.ULID,
 ) (*cqrs.FunctionRun, error) {
-	item, err := w.q.GetFunctionRun(ctx, id)
+	item, err := w.q.GetFunctionRun(ctx, sqlc.GetFunctionRunParams{
+		WorkspaceID: workspaceID,
+		RunID:       id,
+	})
 	if err != nil {
 		return nil, err
 	}
 	return toCQRSRun(item.FunctionRun, item.FunctionFinish), nil
 }
 
-func (w wrapper) GetFunctionRunsTimebound(ctx context.Context, t cqrs.Timebound, limit int) ([]*cqrs.FunctionRun, error) {
+func (w wrapper) GetFunctionRunsTimebound(ctx context.Context, workspaceID uuid.UUID, t cqrs.Timebound, limit int) ([]*cqrs.FunctionRun, error) {
 	after := time.Time{}                           // after the beginning of time, eg all
 	before := time.Now().Add(time.Hour * 24 * 365) // before 1 year in the future, eg all
 	if t.After != nil {
@@ -538,9 +819,10 @@ func (w wrapper) GetFunctionRunsTimebound(ctx context.Context, t cqrs.Timebound,
 	}
 
 	runs, err := w.q.GetFunctionRunsTimebound(ctx, sqlc.GetFunctionRunsTimeboundParams{
-		Before: before,
-		After:  after,
-		Limit:  int64(limit),
+		WorkspaceID: workspaceID,
+		Before:      before,
+		After:       after,
+		Limit:       int64(limit),
 	})
 	if err != nil {
 		return nil, err
@@ -552,6 +834,189 @@ func (w wrapper) GetFunctionRunsTimebound(ctx context.Context, t cqrs.Timebound,
 	return result, nil
 }
 
+// searchPageSize is the number of runs scanned at a time when searching runs
+// by expression.
+const searchPageSize = 500
+
+func (w wrapper) SearchFunctionRuns(
+	ctx context.Context,
+	accountID uuid.UUID,
+	workspaceID uuid.UUID,
+	opts cqrs.SearchFunctionRunsOpts,
+) (*cqrs.FunctionRunSearchResult, error) {
+	var (
+		eval expressions.BooleanEvaluator
+		err  error
+	)
+	if opts.If != "" {
+		if eval, err = expressions.NewBooleanEvaluator(ctx, opts.If); err != nil {
+			return nil, err
+		}
+	}
+
+	limit := util.Bound(opts.Limit, 1, cqrs.MaxFunctionRunSearchResults)
+	page := limit
+	if eval != nil || opts.FunctionID != nil {
+		// Runs are filtered after loading, so scan more runs at a time.
+		page = searchPageSize
+	}
+
+	result := &cqrs.FunctionRunSearchResult{Runs: []*cqrs.FunctionRun{}}
+	cursor := opts.Cursor
+	scanned := 0
+	for {
+		runs, err := w.functionRunsByCursor(ctx, workspaceID, opts, cursor, page)
+		if err != nil {
+			return nil, err
+		}
+
+		matched := runs
+		if opts.FunctionID != nil {
+			matched = []*cqrs.FunctionRun{}
+			for _, run := range runs {
+				if run.FunctionID == *opts.FunctionID {
+					matched = append(matched, run)
+				}
+			}
+		}
+		if eval != nil {
+			if matched, err = w.filterRunsByEvent(ctx, eval, matched); err != nil {
+				return nil, err
+			}
+		}
+
+		for _, run := range matched {
+			result.Runs = append(result.Runs, run)
+			if len(result.Runs) == limit {
+				result.Cursor = cqrs.NewFunctionRunCursor(run)
+				return result, nil
+			}
+		}
+
+		if len(runs) < page {
+			return result, nil
+		}
+		cursor = cqrs.NewFunctionRunCursor(runs[len(runs)-1])
+
+		// Bound the work done by searches which rarely match, returning the
+		// cursor to continue from.
+		if scanned += len(runs); scanned >= cqrs.MaxFunctionRunSearchScan {
+			result.Cursor = cursor
+			return result, nil
+		}
+	}
+}
+
+// functionRunsByCursor loads a page of runs after the given cursor, in the given
+// order.
+func (w wrapper) functionRunsByCursor(ctx context.Context, workspaceID uuid.UUID, opts cqrs.SearchFunctionRunsOpts, cursor *cqrs.FunctionRunCursor, limit int) ([]*cqrs.FunctionRun, error) {
+	after := time.Time{}                           // after the beginning of time, eg all
+	before := time.Now().Add(time.Hour * 24 * 365) // before 1 year in the future, eg all
+	if opts.Timebound.After != nil {
+		after = *opts.Timebound.After
+	}
+	if opts.Timebound.Before != nil {
+		before = *opts.Timebound.Before
+	}
+
+	statuses := make([]string, 0, len(opts.Statuses))
+	for _, s := range opts.Statuses {
+		statuses = append(statuses, s.String())
+		// Waiting statuses are recorded for runs which aren't progressing, and
+		// are included when searching for the status they're waiting within.
+		switch s {
+		case enums.RunStatusRunning:
+			statuses = append(statuses, enums.RunStatusWaitingForEvent.String(), enums.RunStatusSleeping.String(), enums.RunStatusHalted.String())
+		case enums.RunStatusScheduled:
+			statuses = append(statuses, enums.RunStatusThrottled.String())
+		}
+	}
+
+	result := []*cqrs.FunctionRun{}
+	if opts.Order.Ascending() {
+		if cursor == nil {
+			cursor = &cqrs.FunctionRunCursor{RunStartedAt: after, RunID: nilULID}
+		}
+		runs, err := w.q.GetFunctionRunsByCursorAsc(ctx, sqlc.GetFunctionRunsByCursorAscParams{
+			WorkspaceID:     workspaceID,
+			CursorStartedAt: cursor.RunStartedAt,
+			Cursor:          cursor.RunID,
+			After:           after,
+			Before:          before,
+			AnyStatus:       len(statuses) == 0,
+			Statuses:        statuses,
+			Limit:           int64(limit),
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range runs {
+			result = append(result, toCQRSRun(item.FunctionRun, item.FunctionFinish))
+		}
+		return result, nil
+	}
+
+	if cursor == nil {
+		cursor = &cqrs.FunctionRunCursor{RunStartedAt: before, RunID: endULID}
+	}
+	runs, err := w.q.GetFunctionRunsByCursor(ctx, sqlc.GetFunctionRunsByCursorParams{
+		WorkspaceID:     workspaceID,
+		CursorStartedAt: cursor.RunStartedAt,
+		Cursor:          cursor.RunID,
+		After:           after,
+		Before:          before,
+		AnyStatus:       len(statuses) == 0,
+		Statuses:        statuses,
+		Limit:           int64(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range runs {
+		result = append(result, toCQRSRun(item.FunctionRun, item.FunctionFinish))
+	}
+	return result, nil
+}
+
+// filterRunsByEvent returns the runs whose triggering event matches the given
+// expression.
+func (w wrapper) filterRunsByEvent(ctx context.Context, eval expressions.BooleanEvaluator, runs []*cqrs.FunctionRun) ([]*cqrs.FunctionRun, error) {
+	if len(runs) == 0 {
+		return runs, nil
+	}
+
+	ids := make([]ulid.ULID, len(runs))
+	for n, run := range runs {
+		ids[n] = run.EventID
+	}
+	evts, err := w.q.GetEventsByInternalIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[ulid.ULID]*sqlc.Event, len(evts))
+	for _, evt := range evts {
+		byID[evt.InternalID] = evt
+	}
+
+	result := []*cqrs.FunctionRun{}
+	for _, run := range runs {
+		obj, ok := byID[run.EventID]
+		if !ok {
+			continue
+		}
+		evt := convertEvent(obj)
+		ok, _, err := eval.Evaluate(ctx, expressions.NewData(map[string]any{
+			"event": evt.GetEvent().Map(),
+		}))
+		if err != nil || !ok {
+			// Events which error, eg. from missing fields, don't match.
+			continue
+		}
+		result = append(result, run)
+	}
+	return result, nil
+}
+
 func (w wrapper) GetFunctionRunFinishesByRunIDs(
 	ctx context.Context,
 	accountID uuid.UUID,
@@ -581,12 +1046,166 @@ func (w wrapper) GetFunctionRunHistory(ctx context.Context, runID ulid.ULID) ([]
 	return nil, err
 }
 
+//
+// Cancellations
+//
+
+func (w wrapper) CreateCancellation(ctx context.Context, c cqrs.Cancellation) error {
+	params := sqlc.InsertCancellationParams{
+		ID:            c.ID,
+		WorkspaceID:   c.WorkspaceID,
+		FunctionID:    c.FunctionID,
+		FunctionSlug:  c.FunctionSlug,
+		StartedBefore: c.StartedBefore,
+		CreatedAt:     c.CreatedAt,
+	}
+	if params.CreatedAt.IsZero() {
+		params.CreatedAt = time.Now()
+	}
+	if c.Name != nil {
+		params.Name = sql.NullString{String: *c.Name, Valid: true}
+	}
+	if c.StartedAfter != nil {
+		params.StartedAfter = sql.NullTime{Time: *c.StartedAfter, Valid: true}
+	}
+	if c.If != nil {
+		params.Expression = sql.NullString{String: *c.If, Valid: true}
+	}
+	return w.q.InsertCancellation(ctx, params)
+}
+
+func (w wrapper) DeleteCancellation(ctx context.Context, c cqrs.Cancellation) error {
+	return w.q.DeleteCancellation(ctx, c.ID)
+}
+
+func (w wrapper) Cancellations(ctx context.Context, wsID uuid.UUID) ([]cqrs.Cancellation, error) {
+	objs, err := w.q.GetCancellations(ctx, wsID)
+	if err != nil {
+		return nil, err
+	}
+	return convertCancellations(objs), nil
+}
+
+func (w wrapper) Cancellation(ctx context.Context, wsID uuid.UUID, id ulid.ULID) (*cqrs.Cancellation, error) {
+	obj, err := w.q.GetCancellationByID(ctx, sqlc.GetCancellationByIDParams{
+		WorkspaceID: wsID,
+		ID:          id,
+	})
+	if err != nil {
+		return nil, err
+	}
+	c := convertCancellation(obj)
+	return &c, nil
+}
+
+func (w wrapper) CancellationsByFunction(ctx context.Context, wsID uuid.UUID, fnID uuid.UUID) ([]cqrs.Cancellation, error) {
+	objs, err := w.q.GetCancellationsByFunctionID(ctx, sqlc.GetCancellationsByFunctionIDParams{
+		WorkspaceID: wsID,
+		FunctionID:  fnID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return convertCancellations(objs), nil
+}
+
+func convertCancellations(objs []*sqlc.Cancellation) []cqrs.Cancellation {
+	result := make([]cqrs.Cancellation, len(objs))
+	for n, obj := range objs {
+		result[n] = convertCancellation(obj)
+	}
+	return result
+}
+
+func convertCancellation(obj *sqlc.Cancellation) cqrs.Cancellation {
+	c := cqrs.Cancellation{
+		ID:            obj.ID,
+		CreatedAt:     obj.CreatedAt,
+		WorkspaceID:   obj.WorkspaceID,
+		FunctionID:    obj.FunctionID,
+		FunctionSlug:  obj.FunctionSlug,
+		StartedBefore: obj.StartedBefore,
+	}
+	if obj.Name.Valid {
+		c.Name = &obj.Name.String
+	}
+	if obj.StartedAfter.Valid {
+		c.StartedAfter = &obj.StartedAfter.Time
+	}
+	if obj.Expression.Valid {
+		c.If = &obj.Expression.String
+	}
+	return c
+}
+
+//
+// Step logs
+//
+
+func (w wrapper) InsertStepLogs(ctx context.Context, logs []cqrs.StepLog) error {
+	for _, l := range logs {
+		params := sqlc.InsertStepLogParams{
+			RunID:     l.RunID,
+			StepID:    l.StepID,
+			Attempt:   int64(l.Attempt),
+			Level:     l.Level,
+			Message:   l.Message,
+			Timestamp: l.Timestamp,
+		}
+		if len(l.Fields) > 0 {
+			byt, err := json.Marshal(l.Fields)
+			if err != nil {
+				return fmt.Errorf("error marshalling step log fields: %w", err)
+			}
+			params.Fields = sql.NullString{String: string(byt), Valid: true}
+		}
+		if err := w.q.InsertStepLog(ctx, params); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w wrapper) GetStepLogs(ctx context.Context, runID ulid.ULID, stepID *string) ([]*cqrs.StepLog, error) {
+	var (
+		objs []*sqlc.StepLog
+		err  error
+	)
+	if stepID != nil {
+		objs, err = w.q.GetStepLogsByStepID(ctx, sqlc.GetStepLogsByStepIDParams{RunID: runID, StepID: *stepID})
+	} else {
+		objs, err = w.q.GetStepLogs(ctx, runID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	logs := []*cqrs.StepLog{}
+	for _, obj := range objs {
+		l := &cqrs.StepLog{
+			RunID:     obj.RunID,
+			StepID:    obj.StepID,
+			Attempt:   int(obj.Attempt),
+			Level:     obj.Level,
+			Message:   obj.Message,
+			Timestamp: obj.Timestamp,
+		}
+		if obj.Fields.Valid {
+			if err := json.Unmarshal([]byte(obj.Fields.String), &l.Fields); err != nil {
+				return nil, fmt.Errorf("error unmarshalling step log fields: %w", err)
+			}
+		}
+		logs = append(logs, l)
+	}
+	return logs, nil
+}
+
 func toCQRSRun(run sqlc.FunctionRun, finish sqlc.FunctionFinish) *cqrs.FunctionRun {
 	copied := cqrs.FunctionRun{
 		RunID:           run.RunID,
 		RunStartedAt:    run.RunStartedAt,
 		FunctionID:      run.FunctionID,
 		FunctionVersion: run.FunctionVersion,
+		WorkspaceID:     run.WorkspaceID,
 		EventID:         run.EventID,
 	}
 	if run.BatchID != nilULID {
@@ -598,6 +1217,11 @@ func toCQRSRun(run sqlc.FunctionRun, finish sqlc.FunctionFinish) *cqrs.FunctionR
 	if run.Cron.Valid {
 		copied.Cron = &run.Cron.String
 	}
+	// Runs are stored without a status until they start.
+	copied.Status = enums.RunStatusScheduled
+	if run.Status.Valid {
+		copied.Status, _ = enums.RunStatusString(run.Status.String)
+	}
 	if finish.Status.Valid {
 		copied.Status, _ = enums.RunStatusString(finish.Status.String)
 		copied.Output = json.RawMessage(finish.Output.String)
@@ -687,12 +1311,78 @@ func (w wrapper) GetSpansByTraceIDAndRunID(ctx context.Context, tid string, runI
 	return nil, fmt.Errorf("not implemented")
 }
 
+func (w wrapper) GetSpansByRunID(ctx context.Context, runID ulid.ULID) ([]*cqrs.Span, error) {
+	objs, err := w.q.GetTraceSpansByRunID(ctx, runID)
+	if err != nil {
+		return nil, err
+	}
+	spans := make([]*cqrs.Span, len(objs))
+	for n, obj := range objs {
+		spans[n] = convertSpan(obj)
+	}
+	return spans, nil
+}
+
 func (w wrapper) GetTraceRuns(ctx context.Context, opt cqrs.GetTraceRunOpt) ([]*cqrs.TraceRun, error) {
 	return nil, fmt.Errorf("not implemented")
 }
 
+func convertSpan(obj *sqlc.Trace) *cqrs.Span {
+	span := &cqrs.Span{
+		Timestamp:    obj.Timestamp,
+		TraceID:      string(obj.TraceID),
+		SpanID:       string(obj.SpanID),
+		SpanName:     obj.SpanName,
+		SpanKind:     obj.SpanKind,
+		ServiceName:  obj.ServiceName,
+		ScopeName:    obj.ScopeName,
+		ScopeVersion: obj.ScopeVersion,
+		Duration:     time.Duration(obj.Duration),
+		StatusCode:   obj.StatusCode,
+	}
+	if len(obj.ParentSpanID) > 0 {
+		id := string(obj.ParentSpanID)
+		span.ParentSpanID = &id
+	}
+	if len(obj.TraceState) > 0 {
+		state := string(obj.TraceState)
+		span.TraceState = &state
+	}
+	if obj.StatusMessage.Valid {
+		span.StatusMessage = &obj.StatusMessage.String
+	}
+	if obj.RunID != (ulid.ULID{}) {
+		span.RunID = &obj.RunID
+	}
+	// These are always marshalled by InsertSpan, so errors are ignored.
+	_ = json.Unmarshal(obj.ResourceAttributes, &span.ResourceAttributes)
+	_ = json.Unmarshal(obj.SpanAttributes, &span.SpanAttributes)
+	_ = json.Unmarshal(obj.Events, &span.Events)
+	_ = json.Unmarshal(obj.Links, &span.Links)
+	return span
+}
+
 // copyWriter allows running duck-db specific functions as CQRS functions, copying CQRS types to DDB types
 // automatically.
+// copyOpts are the options used when copying sqlc models into cqrs types.  Null
+// times are copied as nil pointers, rather than zero times.
+var copyOpts = copier.Option{
+	DeepCopy: true,
+	Converters: []copier.TypeConverter{
+		{
+			SrcType: sql.NullTime{},
+			DstType: &time.Time{},
+			Fn: func(src interface{}) (interface{}, error) {
+				t := src.(sql.NullTime)
+				if !t.Valid {
+					return (*time.Time)(nil), nil
+				}
+				return &t.Time, nil
+			},
+		},
+	},
+}
+
 func copyWriter[
 	PARAMS_IN any,
 	INTERNAL_PARAMS any,
@@ -715,7 +1405,7 @@ func copyWriter[
 		return out, err
 	}
 
-	err = copier.CopyWithOption(&out, in, copier.Option{DeepCopy: true})
+	err = copier.CopyWithOption(&out, in, copyOpts)
 	return out, err
 }
 
@@ -731,6 +1421,6 @@ func copyInto[
 	if err != nil {
 		return out, err
 	}
-	err = copier.CopyWithOption(&out, in, copier.Option{DeepCopy: true})
+	err = copier.CopyWithOption(&out, in, copyOpts)
 	return out, err
 }
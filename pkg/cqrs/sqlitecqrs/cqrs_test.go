@@ -0,0 +1,573 @@
+package sqlitecqrs
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/inngest/inngest/pkg/cqrs"
+	"github.com/inngest/inngest/pkg/cqrs/sqlitecqrs/sqlc"
+	"github.com/inngest/inngest/pkg/enums"
+	"github.com/inngest/inngest/pkg/execution/cancellation"
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventKeys(t *testing.T) {
+	ctx := context.Background()
+	db, err := New()
+	require.NoError(t, err)
+	mgr := NewCQRS(db)
+
+	ok, err := mgr.HasEventKeys(ctx)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	source := "backend"
+	key, err := mgr.CreateEventKey(ctx, cqrs.CreateEventKeyOpts{Name: "prod", Source: &source})
+	require.NoError(t, err)
+	require.NotEmpty(t, key.Key)
+	require.Equal(t, key.Key[:eventKeyPrefixLen], key.Prefix)
+	require.Equal(t, "backend", *key.Source)
+
+	ok, err = mgr.HasEventKeys(ctx)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	found, err := mgr.GetEventKeyByKey(ctx, key.Key)
+	require.NoError(t, err)
+	require.Equal(t, key.ID, found.ID)
+	require.Empty(t, found.Key, "plaintext keys must not be loaded")
+	require.Equal(t, cqrs.DefaultEventKeyScopes, found.Scopes)
+
+	t.Run("Scopes are stored", func(t *testing.T) {
+		scoped, err := mgr.CreateEventKey(ctx, cqrs.CreateEventKeyOpts{
+			Name:   "automation",
+			Scopes: []cqrs.EventKeyScope{cqrs.ScopeRunsRead, cqrs.ScopeRunsCancel},
+		})
+		require.NoError(t, err)
+
+		authorized, err := cqrs.AuthorizeEventKey(ctx, mgr, scoped.Key, cqrs.ScopeRunsCancel)
+		require.NoError(t, err)
+		require.Equal(t, scoped.ID, authorized.ID)
+		_, err = cqrs.AuthorizeEventKey(ctx, mgr, scoped.Key, cqrs.ScopeEventsWrite)
+		require.ErrorIs(t, err, cqrs.ErrEventKeyScope)
+
+		_, err = mgr.RevokeEventKey(ctx, scoped.ID)
+		require.NoError(t, err)
+	})
+
+	t.Run("Rotating invalidates the previous key", func(t *testing.T) {
+		rotated, err := mgr.RotateEventKey(ctx, key.ID)
+		require.NoError(t, err)
+		require.NotEqual(t, key.Key, rotated.Key)
+		require.NotNil(t, rotated.RotatedAt)
+
+		_, err = mgr.GetEventKeyByKey(ctx, key.Key)
+		require.ErrorIs(t, err, cqrs.ErrEventKeyNotFound)
+		_, err = mgr.GetEventKeyByKey(ctx, rotated.Key)
+		require.NoError(t, err)
+		key = rotated
+	})
+
+	t.Run("Revoking invalidates the key", func(t *testing.T) {
+		revoked, err := mgr.RevokeEventKey(ctx, key.ID)
+		require.NoError(t, err)
+		require.True(t, revoked.IsRevoked())
+
+		_, err = mgr.GetEventKeyByKey(ctx, key.Key)
+		require.ErrorIs(t, err, cqrs.ErrEventKeyNotFound)
+		_, err = mgr.RevokeEventKey(ctx, key.ID)
+		require.ErrorIs(t, err, cqrs.ErrEventKeyNotFound)
+		_, err = mgr.RotateEventKey(ctx, key.ID)
+		require.ErrorIs(t, err, cqrs.ErrEventKeyNotFound)
+
+		ok, err := mgr.HasEventKeys(ctx)
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		all, err := mgr.GetEventKeys(ctx)
+		require.NoError(t, err)
+		require.Len(t, all, 2)
+		require.Equal(t, "prod", all[0].Name)
+	})
+}
+
+func TestStepLogs(t *testing.T) {
+	ctx := context.Background()
+	db, err := New()
+	require.NoError(t, err)
+	mgr := NewCQRS(db)
+
+	runID := ulid.Make()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	err = mgr.InsertStepLogs(ctx, []cqrs.StepLog{
+		{RunID: runID, StepID: "a", Attempt: 0, Level: "info", Message: "first", Timestamp: now},
+		{RunID: runID, StepID: "b", Attempt: 1, Level: "error", Message: "second", Fields: map[string]interface{}{"user": "u1"}, Timestamp: now.Add(time.Second)},
+		{RunID: ulid.Make(), StepID: "a", Level: "info", Message: "other run", Timestamp: now},
+	})
+	require.NoError(t, err)
+
+	logs, err := mgr.GetStepLogs(ctx, runID, nil)
+	require.NoError(t, err)
+	require.Len(t, logs, 2)
+	require.Equal(t, "first", logs[0].Message)
+	require.Nil(t, logs[0].Fields)
+	require.Equal(t, "second", logs[1].Message)
+	require.Equal(t, 1, logs[1].Attempt)
+	require.Equal(t, map[string]interface{}{"user": "u1"}, logs[1].Fields)
+
+	step := "b"
+	logs, err = mgr.GetStepLogs(ctx, runID, &step)
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+	require.Equal(t, "error", logs[0].Level)
+}
+
+func TestCancellations(t *testing.T) {
+	ctx := context.Background()
+	db, err := New()
+	require.NoError(t, err)
+	mgr := NewCQRS(db)
+
+	wsID, fnID := uuid.UUID{}, uuid.New()
+	now := time.Now().UTC().Truncate(time.Second)
+	after := now.Add(-time.Hour)
+	expr := "event.data.user == 'u1'"
+
+	c := cqrs.Cancellation{
+		ID:            ulid.Make(),
+		WorkspaceID:   wsID,
+		FunctionID:    fnID,
+		FunctionSlug:  "my-fn",
+		StartedAfter:  &after,
+		StartedBefore: now,
+		If:            &expr,
+	}
+	require.NoError(t, mgr.CreateCancellation(ctx, c))
+
+	all, err := mgr.Cancellations(ctx, wsID)
+	require.NoError(t, err)
+	require.Len(t, all, 1)
+	require.Equal(t, c.ID, all[0].ID)
+	require.Equal(t, expr, *all[0].If)
+	require.True(t, after.Equal(*all[0].StartedAfter))
+
+	found, err := mgr.Cancellation(ctx, wsID, c.ID)
+	require.NoError(t, err)
+	require.Equal(t, "my-fn", found.FunctionSlug)
+
+	byFn, err := mgr.CancellationsByFunction(ctx, wsID, uuid.New())
+	require.NoError(t, err)
+	require.Empty(t, byFn)
+
+	t.Run("checker cancels matching runs", func(t *testing.T) {
+		checker := cancellation.NewChecker(cancellation.NewCQRSReader(mgr))
+		inRange := ulid.MustNew(ulid.Timestamp(now.Add(-time.Minute)), rand.Reader)
+
+		cancel, err := checker.IsCancelled(ctx, wsID, fnID, inRange, map[string]any{"data": map[string]any{"user": "u1"}})
+		require.NoError(t, err)
+		require.NotNil(t, cancel)
+		require.Equal(t, c.ID, cancel.ID)
+
+		cancel, err = checker.IsCancelled(ctx, wsID, fnID, inRange, map[string]any{"data": map[string]any{"user": "u2"}})
+		require.NoError(t, err)
+		require.Nil(t, cancel)
+
+		outOfRange := ulid.MustNew(ulid.Timestamp(now.Add(-2*time.Hour)), rand.Reader)
+		cancel, err = checker.IsCancelled(ctx, wsID, fnID, outOfRange, map[string]any{"data": map[string]any{"user": "u1"}})
+		require.NoError(t, err)
+		require.Nil(t, cancel)
+	})
+
+	require.NoError(t, mgr.DeleteCancellation(ctx, c))
+	all, err = mgr.Cancellations(ctx, wsID)
+	require.NoError(t, err)
+	require.Empty(t, all)
+}
+
+func TestArchiveFunction(t *testing.T) {
+	ctx := context.Background()
+	db, err := New()
+	require.NoError(t, err)
+	mgr := NewCQRS(db)
+
+	fnID := uuid.New()
+	_, err = mgr.InsertFunction(ctx, cqrs.InsertFunctionParams{
+		ID:        fnID,
+		AppID:     uuid.New(),
+		Name:      "my fn",
+		Slug:      "my-fn",
+		Config:    `{"name":"my fn","slug":"my-fn"}`,
+		CreatedAt: time.Now(),
+	})
+	require.NoError(t, err)
+
+	fn, err := mgr.ArchiveFunction(ctx, fnID)
+	require.NoError(t, err)
+	require.NotNil(t, fn.ArchivedAt)
+
+	// Archived functions are still loaded, so that runs can be skipped.
+	fns, err := mgr.Functions(ctx)
+	require.NoError(t, err)
+	require.Len(t, fns, 1)
+	require.True(t, fns[0].IsArchived())
+
+	found, err := mgr.GetFunctionByInternalUUID(ctx, uuid.UUID{}, fnID)
+	require.NoError(t, err)
+	inngestFn, err := found.InngestFunction()
+	require.NoError(t, err)
+	require.True(t, inngestFn.IsArchived())
+
+	fn, err = mgr.UnarchiveFunction(ctx, fnID)
+	require.NoError(t, err)
+	require.Nil(t, fn.ArchivedAt)
+
+	fns, err = mgr.Functions(ctx)
+	require.NoError(t, err)
+	require.False(t, fns[0].IsArchived())
+}
+
+func TestSearchFunctionRuns(t *testing.T) {
+	ctx := context.Background()
+	db, err := New()
+	require.NoError(t, err)
+	mgr := NewCQRS(db)
+
+	fnID := uuid.New()
+	now := time.Now().UTC().Truncate(time.Second)
+
+	insert := func(userID string, startedAt time.Time) ulid.ULID {
+		evtID := ulid.Make()
+		require.NoError(t, mgr.InsertEvent(ctx, cqrs.Event{
+			ID:        evtID,
+			EventID:   evtID.String(),
+			EventName: "order/created",
+			EventData: map[string]any{"userId": userID},
+			EventTS:   startedAt.UnixMilli(),
+		}))
+		runID := ulid.MustNew(ulid.Timestamp(startedAt), rand.Reader)
+		require.NoError(t, mgr.InsertFunctionRun(ctx, cqrs.FunctionRun{
+			RunID:        runID,
+			RunStartedAt: startedAt,
+			FunctionID:   fnID,
+			EventID:      evtID,
+		}))
+		return runID
+	}
+
+	older := insert("123", now.Add(-2*time.Minute))
+	_ = insert("456", now.Add(-time.Minute))
+	newer := insert("123", now)
+
+	t.Run("It returns matching runs, most recent first", func(t *testing.T) {
+		res, err := mgr.SearchFunctionRuns(ctx, uuid.UUID{}, uuid.UUID{}, cqrs.SearchFunctionRunsOpts{
+			If:         `event.data.userId == "123"`,
+			FunctionID: &fnID,
+			Limit:      10,
+		})
+		require.NoError(t, err)
+		require.Len(t, res.Runs, 2)
+		require.Equal(t, newer, res.Runs[0].RunID)
+		require.Equal(t, older, res.Runs[1].RunID)
+	})
+
+	t.Run("It respects limits and time bounds", func(t *testing.T) {
+		res, err := mgr.SearchFunctionRuns(ctx, uuid.UUID{}, uuid.UUID{}, cqrs.SearchFunctionRunsOpts{
+			If:         `event.data.userId == "123"`,
+			FunctionID: &fnID,
+			Limit:      1,
+		})
+		require.NoError(t, err)
+		require.Len(t, res.Runs, 1)
+		require.Equal(t, newer, res.Runs[0].RunID)
+		require.NotNil(t, res.Cursor)
+		require.Equal(t, newer, res.Cursor.RunID)
+
+		before := now.Add(-time.Minute)
+		res, err = mgr.SearchFunctionRuns(ctx, uuid.UUID{}, uuid.UUID{}, cqrs.SearchFunctionRunsOpts{
+			If:         `event.data.userId == "123"`,
+			FunctionID: &fnID,
+			Timebound:  cqrs.Timebound{Before: &before},
+			Limit:      10,
+		})
+		require.NoError(t, err)
+		require.Len(t, res.Runs, 1)
+		require.Equal(t, older, res.Runs[0].RunID)
+	})
+
+	t.Run("It filters by function", func(t *testing.T) {
+		other := uuid.New()
+		res, err := mgr.SearchFunctionRuns(ctx, uuid.UUID{}, uuid.UUID{}, cqrs.SearchFunctionRunsOpts{
+			If:         `event.data.userId == "123"`,
+			FunctionID: &other,
+			Limit:      10,
+		})
+		require.NoError(t, err)
+		require.Empty(t, res.Runs)
+		require.Nil(t, res.Cursor)
+	})
+
+	t.Run("It stops scanning at the scan limit, returning a cursor", func(t *testing.T) {
+		// Use a separate workspace, with a matching run older than enough
+		// non-matching runs to exceed the scan limit.
+		wsID := uuid.New()
+		matching := ulid.MustNew(ulid.Timestamp(now.Add(-time.Hour)), rand.Reader)
+		require.NoError(t, mgr.InsertFunctionRun(ctx, cqrs.FunctionRun{
+			RunID:        matching,
+			RunStartedAt: now.Add(-time.Hour),
+			FunctionID:   fnID,
+			WorkspaceID:  wsID,
+			EventID:      ulid.Make(),
+		}))
+		other := uuid.New()
+		for i := 0; i < cqrs.MaxFunctionRunSearchScan; i++ {
+			startedAt := now.Add(time.Duration(i) * time.Millisecond)
+			require.NoError(t, mgr.InsertFunctionRun(ctx, cqrs.FunctionRun{
+				RunID:        ulid.MustNew(ulid.Timestamp(startedAt), rand.Reader),
+				RunStartedAt: startedAt,
+				FunctionID:   other,
+				WorkspaceID:  wsID,
+				EventID:      ulid.Make(),
+			}))
+		}
+
+		res, err := mgr.SearchFunctionRuns(ctx, uuid.UUID{}, wsID, cqrs.SearchFunctionRunsOpts{
+			FunctionID: &fnID,
+			Limit:      10,
+		})
+		require.NoError(t, err)
+		require.Empty(t, res.Runs)
+		require.NotNil(t, res.Cursor)
+
+		// Continuing from the cursor finds the remaining run.
+		res, err = mgr.SearchFunctionRuns(ctx, uuid.UUID{}, wsID, cqrs.SearchFunctionRunsOpts{
+			FunctionID: &fnID,
+			Limit:      10,
+			Cursor:     res.Cursor,
+		})
+		require.NoError(t, err)
+		require.Len(t, res.Runs, 1)
+		require.Equal(t, matching, res.Runs[0].RunID)
+		require.Nil(t, res.Cursor)
+	})
+}
+
+func TestFunctionRunPagination(t *testing.T) {
+	ctx := context.Background()
+	db, err := New()
+	require.NoError(t, err)
+	mgr := NewCQRS(db)
+
+	fnID := uuid.New()
+	now := time.Now().UTC().Truncate(time.Second)
+
+	// Insert five runs, oldest first, where every other run has failed.
+	runIDs := []ulid.ULID{}
+	for i := 0; i < 5; i++ {
+		startedAt := now.Add(time.Duration(i-5) * time.Minute)
+		runID := ulid.MustNew(ulid.Timestamp(startedAt), rand.Reader)
+		require.NoError(t, mgr.InsertFunctionRun(ctx, cqrs.FunctionRun{
+			RunID:        runID,
+			RunStartedAt: startedAt,
+			FunctionID:   fnID,
+			EventID:      ulid.Make(),
+		}))
+		if i%2 == 0 {
+			require.NoError(t, mgr.(wrapper).q.InsertFunctionFinish(ctx, sqlc.InsertFunctionFinishParams{
+				RunID:              runID,
+				Status:             sql.NullString{String: enums.RunStatusFailed.String(), Valid: true},
+				Output:             sql.NullString{String: "{}", Valid: true},
+				CompletedStepCount: sql.NullInt64{Int64: 1, Valid: true},
+				CreatedAt:          sql.NullTime{Time: startedAt, Valid: true},
+			}))
+		} else {
+			require.NoError(t, mgr.UpdateFunctionRunStatus(ctx, runID, enums.RunStatusRunning))
+		}
+		runIDs = append(runIDs, runID)
+	}
+
+	page := func(opts cqrs.SearchFunctionRunsOpts) []ulid.ULID {
+		opts.FunctionID = &fnID
+		ids := []ulid.ULID{}
+		for {
+			res, err := mgr.SearchFunctionRuns(ctx, uuid.UUID{}, uuid.UUID{}, opts)
+			require.NoError(t, err)
+			for _, run := range res.Runs {
+				ids = append(ids, run.RunID)
+			}
+			if res.Cursor == nil {
+				return ids
+			}
+			opts.Cursor = res.Cursor
+		}
+	}
+
+	t.Run("It pages through runs newest first", func(t *testing.T) {
+		ids := page(cqrs.SearchFunctionRunsOpts{Limit: 2})
+		require.Equal(t, []ulid.ULID{runIDs[4], runIDs[3], runIDs[2], runIDs[1], runIDs[0]}, ids)
+	})
+
+	t.Run("It pages through runs oldest first", func(t *testing.T) {
+		ids := page(cqrs.SearchFunctionRunsOpts{Limit: 2, Order: cqrs.SortOrderAsc})
+		require.Equal(t, runIDs, ids)
+	})
+
+	t.Run("It filters runs by status", func(t *testing.T) {
+		ids := page(cqrs.SearchFunctionRunsOpts{Limit: 2, Statuses: []enums.RunStatus{enums.RunStatusFailed}})
+		require.Equal(t, []ulid.ULID{runIDs[4], runIDs[2], runIDs[0]}, ids)
+
+		ids = page(cqrs.SearchFunctionRunsOpts{Limit: 2, Statuses: []enums.RunStatus{enums.RunStatusRunning}})
+		require.Equal(t, []ulid.ULID{runIDs[3], runIDs[1]}, ids)
+	})
+
+	t.Run("It stores waiting statuses for unfinished runs", func(t *testing.T) {
+		require.NoError(t, mgr.UpdateFunctionRunStatus(ctx, runIDs[3], enums.RunStatusSleeping))
+		run, err := mgr.GetFunctionRun(ctx, uuid.UUID{}, uuid.UUID{}, runIDs[3])
+		require.NoError(t, err)
+		require.Equal(t, enums.RunStatusSleeping, run.Status)
+
+		// Waiting runs are still running.
+		ids := page(cqrs.SearchFunctionRunsOpts{Limit: 2, Statuses: []enums.RunStatus{enums.RunStatusRunning}})
+		require.Equal(t, []ulid.ULID{runIDs[3], runIDs[1]}, ids)
+
+		// Finished runs use their finish's status.
+		require.NoError(t, mgr.UpdateFunctionRunStatus(ctx, runIDs[4], enums.RunStatusSleeping))
+		run, err = mgr.GetFunctionRun(ctx, uuid.UUID{}, uuid.UUID{}, runIDs[4])
+		require.NoError(t, err)
+		require.Equal(t, enums.RunStatusFailed, run.Status)
+	})
+
+	t.Run("It lists runs which haven't started as scheduled", func(t *testing.T) {
+		// Runs which started at the same time are paged by their ID.
+		startedAt := now.Add(-10 * time.Minute)
+		scheduled := []ulid.ULID{}
+		for i := 0; i < 3; i++ {
+			runID := ulid.MustNew(ulid.Timestamp(startedAt), rand.Reader)
+			require.NoError(t, mgr.InsertFunctionRun(ctx, cqrs.FunctionRun{
+				RunID:        runID,
+				RunStartedAt: startedAt,
+				FunctionID:   fnID,
+				EventID:      ulid.Make(),
+			}))
+			scheduled = append(scheduled, runID)
+		}
+		sort.Slice(scheduled, func(i, j int) bool { return scheduled[i].Compare(scheduled[j]) < 0 })
+
+		ids := page(cqrs.SearchFunctionRunsOpts{Limit: 2, Order: cqrs.SortOrderAsc, Statuses: []enums.RunStatus{enums.RunStatusScheduled}})
+		require.Equal(t, scheduled, ids)
+	})
+}
+
+func TestWorkspaceEventsOrder(t *testing.T) {
+	ctx := context.Background()
+	db, err := New()
+	require.NoError(t, err)
+	mgr := NewCQRS(db)
+
+	name := "test/" + ulid.Make().String()
+	ids := []ulid.ULID{}
+	for i := 0; i < 3; i++ {
+		id := ulid.Make()
+		require.NoError(t, mgr.InsertEvent(ctx, cqrs.Event{ID: id, EventName: name, EventTS: time.Now().UnixMilli()}))
+		ids = append(ids, id)
+	}
+
+	evts, err := mgr.WorkspaceEvents(ctx, uuid.UUID{}, &cqrs.WorkspaceEventsOpts{Name: &name, Limit: 2, Order: cqrs.SortOrderAsc})
+	require.NoError(t, err)
+	require.Len(t, evts, 2)
+	require.Equal(t, ids[0], evts[0].ID)
+	require.Equal(t, ids[1], evts[1].ID)
+
+	evts, err = mgr.WorkspaceEvents(ctx, uuid.UUID{}, &cqrs.WorkspaceEventsOpts{Name: &name, Limit: 2, Order: cqrs.SortOrderAsc, Cursor: &evts[1].ID})
+	require.NoError(t, err)
+	require.Len(t, evts, 1)
+	require.Equal(t, ids[2], evts[0].ID)
+
+	evts, err = mgr.WorkspaceEvents(ctx, uuid.UUID{}, &cqrs.WorkspaceEventsOpts{Name: &name, Limit: 2})
+	require.NoError(t, err)
+	require.Equal(t, ids[2], evts[0].ID)
+	require.Equal(t, ids[1], evts[1].ID)
+}
+
+func TestWorkspaceScoping(t *testing.T) {
+	ctx := context.Background()
+	db, err := New()
+	require.NoError(t, err)
+	mgr := NewCQRS(db)
+
+	wsA, wsB := uuid.New(), uuid.New()
+	appID := uuid.New()
+	fnID := uuid.New()
+	_, err = mgr.InsertFunction(ctx, cqrs.InsertFunctionParams{
+		ID:          fnID,
+		AppID:       appID,
+		Name:        "scoped fn",
+		Slug:        "scoped-fn",
+		Config:      `{"name":"scoped fn","slug":"scoped-fn","triggers":[{"event":"test/scoped"}]}`,
+		CreatedAt:   time.Now(),
+		WorkspaceID: wsA,
+	})
+	require.NoError(t, err)
+
+	evtID := ulid.Make()
+	runID := ulid.Make()
+	require.NoError(t, mgr.InsertFunctionRun(ctx, cqrs.FunctionRun{
+		RunID:        runID,
+		RunStartedAt: time.Now(),
+		FunctionID:   fnID,
+		EventID:      evtID,
+		WorkspaceID:  wsA,
+	}))
+
+	t.Run("It reads functions within their workspace", func(t *testing.T) {
+		fns, err := mgr.GetFunctions(ctx, wsA)
+		require.NoError(t, err)
+		require.Len(t, fns, 1)
+		fns, err = mgr.GetFunctions(ctx, wsB)
+		require.NoError(t, err)
+		require.Empty(t, fns)
+
+		_, err = mgr.GetFunctionByInternalUUID(ctx, wsA, fnID)
+		require.NoError(t, err)
+		_, err = mgr.GetFunctionByInternalUUID(ctx, wsB, fnID)
+		require.ErrorIs(t, err, sql.ErrNoRows)
+
+		fns, err = mgr.GetFunctionsByAppInternalID(ctx, wsA, appID)
+		require.NoError(t, err)
+		require.Len(t, fns, 1)
+		fns, err = mgr.GetFunctionsByAppInternalID(ctx, wsB, appID)
+		require.NoError(t, err)
+		require.Empty(t, fns)
+
+		triggered, err := mgr.FunctionsByTrigger(ctx, wsB, "test/scoped")
+		require.NoError(t, err)
+		require.Empty(t, triggered)
+	})
+
+	t.Run("It reads runs within their workspace", func(t *testing.T) {
+		run, err := mgr.GetFunctionRun(ctx, uuid.UUID{}, wsA, runID)
+		require.NoError(t, err)
+		require.Equal(t, runID, run.RunID)
+		_, err = mgr.GetFunctionRun(ctx, uuid.UUID{}, wsB, runID)
+		require.ErrorIs(t, err, sql.ErrNoRows)
+
+		runs, err := mgr.GetFunctionRunsFromEvents(ctx, uuid.UUID{}, wsA, []ulid.ULID{evtID})
+		require.NoError(t, err)
+		require.Len(t, runs, 1)
+		runs, err = mgr.GetFunctionRunsFromEvents(ctx, uuid.UUID{}, wsB, []ulid.ULID{evtID})
+		require.NoError(t, err)
+		require.Empty(t, runs)
+
+		runs, err = mgr.GetFunctionRunsTimebound(ctx, wsA, cqrs.Timebound{}, 10)
+		require.NoError(t, err)
+		require.Len(t, runs, 1)
+		runs, err = mgr.GetFunctionRunsTimebound(ctx, wsB, cqrs.Timebound{}, 10)
+		require.NoError(t, err)
+		require.Empty(t, runs)
+	})
+}
@@ -4,19 +4,38 @@ import (
 	"context"
 	"encoding/json"
 
+	"github.com/google/uuid"
+	"github.com/inngest/inngest/pkg/cqrs"
 	"github.com/inngest/inngest/pkg/inngest"
 )
 
 // Functions returns all functions as inngest functions.
 func (w wrapper) Functions(ctx context.Context) ([]inngest.Function, error) {
-	all, _ := w.GetFunctions(ctx)
+	all, _ := w.GetAllFunctions(ctx)
+	return toInngestFunctions(all), nil
+}
+
+// FunctionWorkspaceID returns the ID of the workspace that the given function is
+// registered within.
+func (w wrapper) FunctionWorkspaceID(ctx context.Context, fnID uuid.UUID) (uuid.UUID, error) {
+	fn, err := w.q.GetFunctionByID(ctx, fnID)
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+	return fn.WorkspaceID, nil
+}
+
+func toInngestFunctions(all []*cqrs.Function) []inngest.Function {
 	funcs := make([]inngest.Function, len(all))
 	for n, i := range all {
 		f := inngest.Function{}
 		_ = json.Unmarshal([]byte(i.Config), &f)
+		// Archived functions are still loaded, so that runs are skipped
+		// when scheduling.
+		f.ArchivedAt = i.ArchivedAt
 		funcs[n] = f
 	}
-	return funcs, nil
+	return funcs
 }
 
 // FunctionsScheduled returns all scheduled functions available.
@@ -38,21 +57,23 @@ func (w wrapper) FunctionsScheduled(ctx context.Context) ([]inngest.Function, er
 	return all, nil
 }
 
-// FunctionsByTrigger returns functions for the given trigger by event name.
-func (w wrapper) FunctionsByTrigger(ctx context.Context, eventName string) ([]inngest.Function, error) {
+// FunctionsByTrigger returns functions within the given workspace for the given
+// trigger by event name.
+func (w wrapper) FunctionsByTrigger(ctx context.Context, workspaceID uuid.UUID, eventName string) ([]inngest.Function, error) {
 	// TODO: Make less naive by storing triggers and caching.
-	fns, err := w.Functions(ctx)
+	all, err := w.GetFunctions(ctx, workspaceID)
 	if err != nil {
 		return nil, err
 	}
-	all := []inngest.Function{}
-	for _, fn := range fns {
+
+	matched := []inngest.Function{}
+	for _, fn := range toInngestFunctions(all) {
 		for _, t := range fn.Triggers {
 			if t.EventTrigger != nil && t.Event == eventName {
-				all = append(all, fn)
+				matched = append(matched, fn)
 				break
 			}
 		}
 	}
-	return all, nil
+	return matched, nil
 }
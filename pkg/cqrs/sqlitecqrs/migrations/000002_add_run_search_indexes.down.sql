@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS events_internal_id;
+DROP INDEX IF EXISTS function_runs_event_id;
+DROP INDEX IF EXISTS function_runs_run_started_at;
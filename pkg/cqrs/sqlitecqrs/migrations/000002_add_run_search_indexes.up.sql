@@ -0,0 +1,5 @@
+-- Searching runs by their triggering event joins runs to events, most recent
+-- runs first.
+CREATE INDEX IF NOT EXISTS events_internal_id ON events (internal_id);
+CREATE INDEX IF NOT EXISTS function_runs_event_id ON function_runs (event_id);
+CREATE INDEX IF NOT EXISTS function_runs_run_started_at ON function_runs (run_started_at);
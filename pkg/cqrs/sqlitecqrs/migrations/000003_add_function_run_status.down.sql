@@ -0,0 +1 @@
+ALTER TABLE function_runs DROP COLUMN status;
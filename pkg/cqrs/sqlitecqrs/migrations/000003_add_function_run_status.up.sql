@@ -0,0 +1,3 @@
+-- Runs which haven't finished store why they aren't progressing, eg. sleeping
+-- or throttled.  Finished runs use their finish's status.
+ALTER TABLE function_runs ADD COLUMN status VARCHAR;
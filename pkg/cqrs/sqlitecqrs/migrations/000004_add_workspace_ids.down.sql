@@ -0,0 +1,3 @@
+ALTER TABLE function_runs DROP COLUMN workspace_id;
+ALTER TABLE functions DROP COLUMN workspace_id;
+ALTER TABLE apps DROP COLUMN workspace_id;
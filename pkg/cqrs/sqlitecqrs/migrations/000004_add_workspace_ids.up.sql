@@ -0,0 +1,7 @@
+-- Apps, functions, and runs belong to a workspace, so that the dev server can
+-- host many environments.  Existing rows belong to the default environment,
+-- whose workspace ID is the zero UUID.
+ALTER TABLE apps ADD COLUMN workspace_id CHAR(36) NOT NULL DEFAULT '00000000-0000-0000-0000-000000000000';
+ALTER TABLE functions ADD COLUMN workspace_id CHAR(36) NOT NULL DEFAULT '00000000-0000-0000-0000-000000000000';
+ALTER TABLE function_runs ADD COLUMN workspace_id CHAR(36) NOT NULL DEFAULT '00000000-0000-0000-0000-000000000000';
+UPDATE events SET workspace_id = '00000000-0000-0000-0000-000000000000' WHERE workspace_id IS NULL;
@@ -0,0 +1,15 @@
+-- Event keys are stored as a hash of the key, alongside a prefix used to
+-- identify the key in the UI.
+CREATE TABLE event_keys (
+	id CHAR(26) PRIMARY KEY,
+	name VARCHAR NOT NULL,
+	source VARCHAR,
+	hash CHAR(64) NOT NULL,
+	prefix VARCHAR NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	rotated_at TIMESTAMP,
+	revoked_at TIMESTAMP
+);
+
+-- Event keys may be restricted to a single environment.
+ALTER TABLE event_keys ADD COLUMN env VARCHAR;
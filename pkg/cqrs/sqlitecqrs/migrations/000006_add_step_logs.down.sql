@@ -0,0 +1 @@
+DROP TABLE step_logs;
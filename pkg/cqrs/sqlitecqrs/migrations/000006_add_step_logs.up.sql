@@ -0,0 +1,10 @@
+-- Logs written by SDKs while running steps.
+CREATE TABLE step_logs (
+	run_id CHAR(26) NOT NULL,
+	step_id VARCHAR NOT NULL,
+	attempt INT NOT NULL,
+	level VARCHAR NOT NULL,
+	message VARCHAR NOT NULL,
+	fields VARCHAR,
+	timestamp TIMESTAMP NOT NULL
+);
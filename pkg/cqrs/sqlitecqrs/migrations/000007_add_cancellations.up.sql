@@ -0,0 +1,13 @@
+-- Bulk cancellations, which cancel runs of a function started within a time
+-- range and optionally matching an expression.
+CREATE TABLE cancellations (
+	id CHAR(26) PRIMARY KEY,
+	workspace_id CHAR(36) NOT NULL,
+	function_id CHAR(36) NOT NULL,
+	function_slug VARCHAR NOT NULL,
+	name VARCHAR,
+	started_after TIMESTAMP,
+	started_before TIMESTAMP NOT NULL,
+	expression VARCHAR,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
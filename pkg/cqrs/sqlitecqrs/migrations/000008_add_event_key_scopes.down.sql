@@ -0,0 +1 @@
+ALTER TABLE event_keys DROP COLUMN scopes;
@@ -0,0 +1,3 @@
+-- Event keys are restricted to a comma separated list of scopes.  Existing keys
+-- may only send events.
+ALTER TABLE event_keys ADD COLUMN scopes VARCHAR NOT NULL DEFAULT 'events:write';
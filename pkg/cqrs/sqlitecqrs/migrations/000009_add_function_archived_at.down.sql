@@ -0,0 +1 @@
+ALTER TABLE functions DROP COLUMN archived_at;
@@ -0,0 +1,2 @@
+-- Archived functions are kept, but no longer triggered.
+ALTER TABLE functions ADD COLUMN archived_at TIMESTAMP;
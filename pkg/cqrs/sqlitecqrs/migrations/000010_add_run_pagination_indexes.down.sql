@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS function_runs_run_id;
+DROP INDEX IF EXISTS function_runs_workspace_id_run_started_at;
+DROP INDEX IF EXISTS function_finishes_run_id;
@@ -0,0 +1,5 @@
+-- Runs are paginated within a workspace by the time they started, and loaded
+-- by their ID along with their finishes.
+CREATE INDEX IF NOT EXISTS function_runs_run_id ON function_runs (run_id);
+CREATE INDEX IF NOT EXISTS function_runs_workspace_id_run_started_at ON function_runs (workspace_id, run_started_at, run_id);
+CREATE INDEX IF NOT EXISTS function_finishes_run_id ON function_finishes (run_id);
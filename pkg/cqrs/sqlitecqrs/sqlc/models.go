@@ -25,12 +25,25 @@ type App struct {
 	CreatedAt   time.Time
 	DeletedAt   sql.NullTime
 	Url         string
+	WorkspaceID uuid.UUID
+}
+
+type Cancellation struct {
+	ID            ulid.ULID
+	WorkspaceID   uuid.UUID
+	FunctionID    uuid.UUID
+	FunctionSlug  string
+	Name          sql.NullString
+	StartedAfter  sql.NullTime
+	StartedBefore time.Time
+	Expression    sql.NullString
+	CreatedAt     time.Time
 }
 
 type Event struct {
 	InternalID  ulid.ULID
 	AccountID   interface{}
-	WorkspaceID interface{}
+	WorkspaceID uuid.UUID
 	Source      sql.NullString
 	SourceID    interface{}
 	ReceivedAt  time.Time
@@ -54,13 +67,28 @@ type EventBatch struct {
 	EventIds    []byte
 }
 
-type Function struct {
-	ID        uuid.UUID
-	AppID     uuid.UUID
+type EventKey struct {
+	ID        ulid.ULID
 	Name      string
-	Slug      string
-	Config    string
+	Source    sql.NullString
+	Hash      string
+	Prefix    string
 	CreatedAt time.Time
+	RotatedAt sql.NullTime
+	RevokedAt sql.NullTime
+	Scopes    string
+	Env       sql.NullString
+}
+
+type Function struct {
+	ID          uuid.UUID
+	AppID       uuid.UUID
+	Name        string
+	Slug        string
+	Config      string
+	CreatedAt   time.Time
+	ArchivedAt  sql.NullTime
+	WorkspaceID uuid.UUID
 }
 
 type FunctionFinish struct {
@@ -81,6 +109,8 @@ type FunctionRun struct {
 	BatchID         ulid.ULID
 	OriginalRunID   ulid.ULID
 	Cron            sql.NullString
+	Status          sql.NullString
+	WorkspaceID     uuid.UUID
 }
 
 type History struct {
@@ -109,6 +139,16 @@ type History struct {
 	Result               sql.NullString
 }
 
+type StepLog struct {
+	RunID     ulid.ULID
+	StepID    string
+	Attempt   int64
+	Level     string
+	Message   string
+	Fields    sql.NullString
+	Timestamp time.Time
+}
+
 type Trace struct {
 	Timestamp          time.Time
 	TraceID            []byte
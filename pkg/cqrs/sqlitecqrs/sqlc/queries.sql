@@ -1,25 +1,25 @@
 -- name: InsertApp :one
 INSERT INTO apps
-	(id, name, sdk_language, sdk_version, framework, metadata, status, error, checksum, url) VALUES
-	(?, ?, ?, ?, ?, ?, ?, ?, ?, ?) RETURNING *;
+	(id, name, sdk_language, sdk_version, framework, metadata, status, error, checksum, url, workspace_id) VALUES
+	(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) RETURNING *;
 
 -- name: GetApp :one
 SELECT * FROM apps WHERE id = ?;
 
 -- name: GetApps :many
-SELECT * FROM apps WHERE deleted_at IS NULL;
+SELECT * FROM apps WHERE workspace_id = ? AND deleted_at IS NULL;
 
 -- name: GetAppByChecksum :one
-SELECT * FROM apps WHERE checksum = ? LIMIT 1;
+SELECT * FROM apps WHERE workspace_id = ? AND checksum = ? LIMIT 1;
 
 -- name: GetAppByID :one
 SELECT * FROM apps WHERE id = ? LIMIT 1;
 
 -- name: GetAppByURL :one
-SELECT * FROM apps WHERE url = ? LIMIT 1;
+SELECT * FROM apps WHERE workspace_id = ? AND url = ? LIMIT 1;
 
 -- name: GetAllApps :many
-SELECT * FROM apps;
+SELECT * FROM apps WHERE workspace_id = ?;
 
 -- name: DeleteApp :exec
 UPDATE apps SET deleted_at = NOW() WHERE id = ?;
@@ -42,28 +42,37 @@ UPDATE apps SET error = ? WHERE id = ? RETURNING *;
 -- note - this is very basic right now.
 -- name: InsertFunction :one
 INSERT INTO functions
-	(id, app_id, name, slug, config, created_at) VALUES
-	(?, ?, ?, ?, ?, ?) RETURNING *;
+	(id, app_id, name, slug, config, created_at, workspace_id) VALUES
+	(?, ?, ?, ?, ?, ?, ?) RETURNING *;
 
 -- name: GetFunctions :many
+SELECT * FROM functions WHERE workspace_id = ?;
+
+-- name: GetAllFunctions :many
 SELECT * FROM functions;
 
 -- name: GetAppFunctions :many
-SELECT * FROM functions WHERE app_id = ?;
+SELECT * FROM functions WHERE workspace_id = ? AND app_id = ?;
 
 -- name: GetAppFunctionsBySlug :many
-SELECT functions.* FROM functions JOIN apps ON apps.id = functions.app_id WHERE apps.name = ?;
+SELECT functions.* FROM functions JOIN apps ON apps.id = functions.app_id WHERE apps.workspace_id = ? AND apps.name = ?;
 
 -- name: GetFunctionByID :one
 SELECT * FROM functions WHERE id = ?;
 
+-- name: GetWorkspaceFunctionByID :one
+SELECT * FROM functions WHERE workspace_id = ? AND id = ?;
+
 -- name: GetFunctionBySlug :one
-SELECT * FROM functions WHERE slug = ?;
+SELECT * FROM functions WHERE workspace_id = ? AND slug = ?;
 
 
 -- name: UpdateFunctionConfig :one
 UPDATE functions SET config = ? WHERE id = ? RETURNING *;
 
+-- name: UpdateFunctionArchivedAt :one
+UPDATE functions SET archived_at = ? WHERE id = ? RETURNING *;
+
 -- name: DeleteFunctionsByAppID :exec
 DELETE FROM functions WHERE app_id = ?;
 
@@ -77,8 +86,11 @@ DELETE FROM functions WHERE id IN (sqlc.slice('ids'));
 
 -- name: InsertFunctionRun :exec
 INSERT INTO function_runs
-	(run_id, run_started_at, function_id, function_version, trigger_type, event_id, batch_id, original_run_id, cron) VALUES
-	(?, ?, ?, ?, ?, ?, ?, ?, ?);
+	(run_id, run_started_at, function_id, function_version, trigger_type, event_id, batch_id, original_run_id, cron, workspace_id) VALUES
+	(?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+
+-- name: UpdateFunctionRunStatus :exec
+UPDATE function_runs SET status = ? WHERE run_id = ?;
 
 -- name: InsertFunctionFinish :exec
 INSERT INTO function_finishes
@@ -89,19 +101,40 @@ INSERT INTO function_finishes
 SELECT sqlc.embed(function_runs), sqlc.embed(function_finishes)
   FROM function_runs
   LEFT JOIN function_finishes ON function_finishes.run_id = function_runs.run_id
-  WHERE function_runs.run_id = @run_id;
+  WHERE function_runs.workspace_id = @workspace_id AND function_runs.run_id = @run_id;
 
 -- name: GetFunctionRunsTimebound :many
 SELECT sqlc.embed(function_runs), sqlc.embed(function_finishes) FROM function_runs
 LEFT JOIN function_finishes ON function_finishes.run_id = function_runs.run_id
-WHERE function_runs.run_started_at > @after AND function_runs.run_started_at <= @before
+WHERE function_runs.workspace_id = @workspace_id
+	AND function_runs.run_started_at > @after AND function_runs.run_started_at <= @before
 ORDER BY function_runs.run_started_at DESC
 LIMIT ?;
 
+-- name: GetFunctionRunsByCursor :many
+SELECT sqlc.embed(function_runs), sqlc.embed(function_finishes) FROM function_runs
+LEFT JOIN function_finishes ON function_finishes.run_id = function_runs.run_id
+WHERE function_runs.workspace_id = @workspace_id
+	AND (function_runs.run_started_at < @cursor_started_at OR (function_runs.run_started_at = @cursor_started_at AND function_runs.run_id < @cursor))
+	AND function_runs.run_started_at > @after AND function_runs.run_started_at <= @before
+	AND (@any_status OR COALESCE(function_finishes.status, function_runs.status, 'Scheduled') IN (sqlc.slice('statuses')))
+ORDER BY function_runs.run_started_at DESC, function_runs.run_id DESC
+LIMIT ?;
+
+-- name: GetFunctionRunsByCursorAsc :many
+SELECT sqlc.embed(function_runs), sqlc.embed(function_finishes) FROM function_runs
+LEFT JOIN function_finishes ON function_finishes.run_id = function_runs.run_id
+WHERE function_runs.workspace_id = @workspace_id
+	AND (function_runs.run_started_at > @cursor_started_at OR (function_runs.run_started_at = @cursor_started_at AND function_runs.run_id > @cursor))
+	AND function_runs.run_started_at > @after AND function_runs.run_started_at <= @before
+	AND (@any_status OR COALESCE(function_finishes.status, function_runs.status, 'Scheduled') IN (sqlc.slice('statuses')))
+ORDER BY function_runs.run_started_at ASC, function_runs.run_id ASC
+LIMIT ?;
+
 -- name: GetFunctionRunsFromEvents :many
 SELECT sqlc.embed(function_runs), sqlc.embed(function_finishes) FROM function_runs
 LEFT JOIN function_finishes ON function_finishes.run_id = function_runs.run_id
-WHERE function_runs.event_id IN (sqlc.slice('event_ids'));
+WHERE function_runs.workspace_id = ? AND function_runs.event_id IN (sqlc.slice('event_ids'));
 
 -- name: GetFunctionRunFinishesByRunIDs :many
 SELECT * FROM function_finishes WHERE run_id IN (sqlc.slice('run_ids'));
@@ -112,8 +145,8 @@ SELECT * FROM function_finishes WHERE run_id IN (sqlc.slice('run_ids'));
 
 -- name: InsertEvent :exec
 INSERT INTO events
-	(internal_id, received_at, event_id, event_name, event_data, event_user, event_v, event_ts) VALUES
-	(?, ?, ?, ?, ?, ?, ?, ?);
+	(internal_id, workspace_id, received_at, event_id, event_name, event_data, event_user, event_v, event_ts) VALUES
+	(?, ?, ?, ?, ?, ?, ?, ?, ?);
 
 -- name: InsertEventBatch :exec
 INSERT INTO event_batches
@@ -137,7 +170,8 @@ SELECT DISTINCT e.*
 FROM events AS e
 LEFT OUTER JOIN function_runs AS r ON r.event_id = e.internal_id
 WHERE
-	e.received_at > @after
+	e.workspace_id = @workspace_id
+	AND e.received_at > @after
 	AND e.received_at <= @before
 	AND (
 		-- Include internal events that triggered a run (e.g. an onFailure
@@ -153,10 +187,16 @@ ORDER BY e.received_at DESC
 LIMIT ?;
 
 -- name: WorkspaceEvents :many
-SELECT * FROM events WHERE internal_id < @cursor AND received_at <= @before AND received_at >= @after ORDER BY internal_id DESC LIMIT ?;
+SELECT * FROM events WHERE workspace_id = @workspace_id AND internal_id < @cursor AND received_at <= @before AND received_at >= @after ORDER BY internal_id DESC LIMIT ?;
 
 -- name: WorkspaceNamedEvents :many
-SELECT * FROM events WHERE internal_id < @cursor AND received_at <= @before AND received_at >= @after AND event_name = @name ORDER BY internal_id DESC LIMIT ?;
+SELECT * FROM events WHERE workspace_id = @workspace_id AND internal_id < @cursor AND received_at <= @before AND received_at >= @after AND event_name = @name ORDER BY internal_id DESC LIMIT ?;
+
+-- name: WorkspaceEventsAsc :many
+SELECT * FROM events WHERE workspace_id = @workspace_id AND internal_id > @cursor AND received_at <= @before AND received_at >= @after ORDER BY internal_id ASC LIMIT ?;
+
+-- name: WorkspaceNamedEventsAsc :many
+SELECT * FROM events WHERE workspace_id = @workspace_id AND internal_id > @cursor AND received_at <= @before AND received_at >= @after AND event_name = @name ORDER BY internal_id ASC LIMIT ?;
 
 --
 -- History
@@ -186,3 +226,69 @@ INSERT INTO trace_runs
 	(account_id, workspace_id, app_id, function_id, trace_id, run_id, queued_at, started_at, ended_at, duration, status, source_id, trigger_ids, output, is_batch, is_debounce)
 VALUES
 	(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+
+-- name: GetTraceSpansByRunID :many
+SELECT * FROM traces WHERE trace_id IN (SELECT DISTINCT trace_id FROM traces WHERE run_id = ?) ORDER BY timestamp ASC;
+
+--
+-- Event keys
+--
+
+-- name: InsertEventKey :one
+INSERT INTO event_keys
+	(id, name, source, hash, prefix, created_at, scopes, env) VALUES
+	(?, ?, ?, ?, ?, ?, ?, ?) RETURNING *;
+
+-- name: GetEventKeys :many
+SELECT * FROM event_keys ORDER BY created_at ASC;
+
+-- name: GetEventKeyByID :one
+SELECT * FROM event_keys WHERE id = ?;
+
+-- name: GetEventKeyByHash :one
+SELECT * FROM event_keys WHERE hash = ? AND revoked_at IS NULL;
+
+-- name: CountActiveEventKeys :one
+SELECT COUNT(*) FROM event_keys WHERE revoked_at IS NULL;
+
+-- name: RotateEventKey :one
+UPDATE event_keys SET hash = ?, prefix = ?, rotated_at = ? WHERE id = ? AND revoked_at IS NULL RETURNING *;
+
+-- name: RevokeEventKey :one
+UPDATE event_keys SET revoked_at = ? WHERE id = ? AND revoked_at IS NULL RETURNING *;
+
+--
+-- Step logs
+--
+
+-- name: InsertStepLog :exec
+INSERT INTO step_logs
+	(run_id, step_id, attempt, level, message, fields, timestamp) VALUES
+	(?, ?, ?, ?, ?, ?, ?);
+
+-- name: GetStepLogs :many
+SELECT * FROM step_logs WHERE run_id = ? ORDER BY timestamp ASC;
+
+-- name: GetStepLogsByStepID :many
+SELECT * FROM step_logs WHERE run_id = ? AND step_id = ? ORDER BY timestamp ASC;
+
+--
+-- Cancellations
+--
+
+-- name: InsertCancellation :exec
+INSERT INTO cancellations
+	(id, workspace_id, function_id, function_slug, name, started_after, started_before, expression, created_at) VALUES
+	(?, ?, ?, ?, ?, ?, ?, ?, ?);
+
+-- name: GetCancellations :many
+SELECT * FROM cancellations WHERE workspace_id = ? ORDER BY created_at ASC;
+
+-- name: GetCancellationByID :one
+SELECT * FROM cancellations WHERE workspace_id = ? AND id = ?;
+
+-- name: GetCancellationsByFunctionID :many
+SELECT * FROM cancellations WHERE workspace_id = ? AND function_id = ? ORDER BY created_at ASC;
+
+-- name: DeleteCancellation :exec
+DELETE FROM cancellations WHERE id = ?;
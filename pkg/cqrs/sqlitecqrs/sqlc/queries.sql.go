@@ -15,6 +15,17 @@ import (
 	ulid "github.com/oklog/ulid/v2"
 )
 
+const countActiveEventKeys = `-- name: CountActiveEventKeys :one
+SELECT COUNT(*) FROM event_keys WHERE revoked_at IS NULL
+`
+
+func (q *Queries) CountActiveEventKeys(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countActiveEventKeys)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const deleteApp = `-- name: DeleteApp :exec
 UPDATE apps SET deleted_at = NOW() WHERE id = ?
 `
@@ -24,6 +35,15 @@ func (q *Queries) DeleteApp(ctx context.Context, id uuid.UUID) error {
 	return err
 }
 
+const deleteCancellation = `-- name: DeleteCancellation :exec
+DELETE FROM cancellations WHERE id = ?
+`
+
+func (q *Queries) DeleteCancellation(ctx context.Context, id ulid.ULID) error {
+	_, err := q.db.ExecContext(ctx, deleteCancellation, id)
+	return err
+}
+
 const deleteFunctionsByAppID = `-- name: DeleteFunctionsByAppID :exec
 DELETE FROM functions WHERE app_id = ?
 `
@@ -53,11 +73,11 @@ func (q *Queries) DeleteFunctionsByIDs(ctx context.Context, ids []uuid.UUID) err
 }
 
 const getAllApps = `-- name: GetAllApps :many
-SELECT id, name, sdk_language, sdk_version, framework, metadata, status, error, checksum, created_at, deleted_at, url FROM apps
+SELECT id, name, sdk_language, sdk_version, framework, metadata, status, error, checksum, created_at, deleted_at, url, workspace_id FROM apps WHERE workspace_id = ?
 `
 
-func (q *Queries) GetAllApps(ctx context.Context) ([]*App, error) {
-	rows, err := q.db.QueryContext(ctx, getAllApps)
+func (q *Queries) GetAllApps(ctx context.Context, workspaceID uuid.UUID) ([]*App, error) {
+	rows, err := q.db.QueryContext(ctx, getAllApps, workspaceID)
 	if err != nil {
 		return nil, err
 	}
@@ -78,6 +98,43 @@ func (q *Queries) GetAllApps(ctx context.Context) ([]*App, error) {
 			&i.CreatedAt,
 			&i.DeletedAt,
 			&i.Url,
+			&i.WorkspaceID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getAllFunctions = `-- name: GetAllFunctions :many
+SELECT id, app_id, name, slug, config, created_at, archived_at, workspace_id FROM functions
+`
+
+func (q *Queries) GetAllFunctions(ctx context.Context) ([]*Function, error) {
+	rows, err := q.db.QueryContext(ctx, getAllFunctions)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*Function
+	for rows.Next() {
+		var i Function
+		if err := rows.Scan(
+			&i.ID,
+			&i.AppID,
+			&i.Name,
+			&i.Slug,
+			&i.Config,
+			&i.CreatedAt,
+			&i.ArchivedAt,
+			&i.WorkspaceID,
 		); err != nil {
 			return nil, err
 		}
@@ -93,7 +150,7 @@ func (q *Queries) GetAllApps(ctx context.Context) ([]*App, error) {
 }
 
 const getApp = `-- name: GetApp :one
-SELECT id, name, sdk_language, sdk_version, framework, metadata, status, error, checksum, created_at, deleted_at, url FROM apps WHERE id = ?
+SELECT id, name, sdk_language, sdk_version, framework, metadata, status, error, checksum, created_at, deleted_at, url, workspace_id FROM apps WHERE id = ?
 `
 
 func (q *Queries) GetApp(ctx context.Context, id uuid.UUID) (*App, error) {
@@ -112,16 +169,22 @@ func (q *Queries) GetApp(ctx context.Context, id uuid.UUID) (*App, error) {
 		&i.CreatedAt,
 		&i.DeletedAt,
 		&i.Url,
+		&i.WorkspaceID,
 	)
 	return &i, err
 }
 
 const getAppByChecksum = `-- name: GetAppByChecksum :one
-SELECT id, name, sdk_language, sdk_version, framework, metadata, status, error, checksum, created_at, deleted_at, url FROM apps WHERE checksum = ? LIMIT 1
+SELECT id, name, sdk_language, sdk_version, framework, metadata, status, error, checksum, created_at, deleted_at, url, workspace_id FROM apps WHERE workspace_id = ? AND checksum = ? LIMIT 1
 `
 
-func (q *Queries) GetAppByChecksum(ctx context.Context, checksum string) (*App, error) {
-	row := q.db.QueryRowContext(ctx, getAppByChecksum, checksum)
+type GetAppByChecksumParams struct {
+	WorkspaceID uuid.UUID
+	Checksum    string
+}
+
+func (q *Queries) GetAppByChecksum(ctx context.Context, arg GetAppByChecksumParams) (*App, error) {
+	row := q.db.QueryRowContext(ctx, getAppByChecksum, arg.WorkspaceID, arg.Checksum)
 	var i App
 	err := row.Scan(
 		&i.ID,
@@ -136,12 +199,13 @@ func (q *Queries) GetAppByChecksum(ctx context.Context, checksum string) (*App,
 		&i.CreatedAt,
 		&i.DeletedAt,
 		&i.Url,
+		&i.WorkspaceID,
 	)
 	return &i, err
 }
 
 const getAppByID = `-- name: GetAppByID :one
-SELECT id, name, sdk_language, sdk_version, framework, metadata, status, error, checksum, created_at, deleted_at, url FROM apps WHERE id = ? LIMIT 1
+SELECT id, name, sdk_language, sdk_version, framework, metadata, status, error, checksum, created_at, deleted_at, url, workspace_id FROM apps WHERE id = ? LIMIT 1
 `
 
 func (q *Queries) GetAppByID(ctx context.Context, id uuid.UUID) (*App, error) {
@@ -160,16 +224,22 @@ func (q *Queries) GetAppByID(ctx context.Context, id uuid.UUID) (*App, error) {
 		&i.CreatedAt,
 		&i.DeletedAt,
 		&i.Url,
+		&i.WorkspaceID,
 	)
 	return &i, err
 }
 
 const getAppByURL = `-- name: GetAppByURL :one
-SELECT id, name, sdk_language, sdk_version, framework, metadata, status, error, checksum, created_at, deleted_at, url FROM apps WHERE url = ? LIMIT 1
+SELECT id, name, sdk_language, sdk_version, framework, metadata, status, error, checksum, created_at, deleted_at, url, workspace_id FROM apps WHERE workspace_id = ? AND url = ? LIMIT 1
 `
 
-func (q *Queries) GetAppByURL(ctx context.Context, url string) (*App, error) {
-	row := q.db.QueryRowContext(ctx, getAppByURL, url)
+type GetAppByURLParams struct {
+	WorkspaceID uuid.UUID
+	Url         string
+}
+
+func (q *Queries) GetAppByURL(ctx context.Context, arg GetAppByURLParams) (*App, error) {
+	row := q.db.QueryRowContext(ctx, getAppByURL, arg.WorkspaceID, arg.Url)
 	var i App
 	err := row.Scan(
 		&i.ID,
@@ -184,16 +254,22 @@ func (q *Queries) GetAppByURL(ctx context.Context, url string) (*App, error) {
 		&i.CreatedAt,
 		&i.DeletedAt,
 		&i.Url,
+		&i.WorkspaceID,
 	)
 	return &i, err
 }
 
 const getAppFunctions = `-- name: GetAppFunctions :many
-SELECT id, app_id, name, slug, config, created_at FROM functions WHERE app_id = ?
+SELECT id, app_id, name, slug, config, created_at, archived_at, workspace_id FROM functions WHERE workspace_id = ? AND app_id = ?
 `
 
-func (q *Queries) GetAppFunctions(ctx context.Context, appID uuid.UUID) ([]*Function, error) {
-	rows, err := q.db.QueryContext(ctx, getAppFunctions, appID)
+type GetAppFunctionsParams struct {
+	WorkspaceID uuid.UUID
+	AppID       uuid.UUID
+}
+
+func (q *Queries) GetAppFunctions(ctx context.Context, arg GetAppFunctionsParams) ([]*Function, error) {
+	rows, err := q.db.QueryContext(ctx, getAppFunctions, arg.WorkspaceID, arg.AppID)
 	if err != nil {
 		return nil, err
 	}
@@ -208,6 +284,8 @@ func (q *Queries) GetAppFunctions(ctx context.Context, appID uuid.UUID) ([]*Func
 			&i.Slug,
 			&i.Config,
 			&i.CreatedAt,
+			&i.ArchivedAt,
+			&i.WorkspaceID,
 		); err != nil {
 			return nil, err
 		}
@@ -223,11 +301,16 @@ func (q *Queries) GetAppFunctions(ctx context.Context, appID uuid.UUID) ([]*Func
 }
 
 const getAppFunctionsBySlug = `-- name: GetAppFunctionsBySlug :many
-SELECT functions.id, functions.app_id, functions.name, functions.slug, functions.config, functions.created_at FROM functions JOIN apps ON apps.id = functions.app_id WHERE apps.name = ?
+SELECT functions.id, functions.app_id, functions.name, functions.slug, functions.config, functions.created_at, functions.archived_at, functions.workspace_id FROM functions JOIN apps ON apps.id = functions.app_id WHERE apps.workspace_id = ? AND apps.name = ?
 `
 
-func (q *Queries) GetAppFunctionsBySlug(ctx context.Context, name string) ([]*Function, error) {
-	rows, err := q.db.QueryContext(ctx, getAppFunctionsBySlug, name)
+type GetAppFunctionsBySlugParams struct {
+	WorkspaceID uuid.UUID
+	Name        string
+}
+
+func (q *Queries) GetAppFunctionsBySlug(ctx context.Context, arg GetAppFunctionsBySlugParams) ([]*Function, error) {
+	rows, err := q.db.QueryContext(ctx, getAppFunctionsBySlug, arg.WorkspaceID, arg.Name)
 	if err != nil {
 		return nil, err
 	}
@@ -242,6 +325,8 @@ func (q *Queries) GetAppFunctionsBySlug(ctx context.Context, name string) ([]*Fu
 			&i.Slug,
 			&i.Config,
 			&i.CreatedAt,
+			&i.ArchivedAt,
+			&i.WorkspaceID,
 		); err != nil {
 			return nil, err
 		}
@@ -257,11 +342,11 @@ func (q *Queries) GetAppFunctionsBySlug(ctx context.Context, name string) ([]*Fu
 }
 
 const getApps = `-- name: GetApps :many
-SELECT id, name, sdk_language, sdk_version, framework, metadata, status, error, checksum, created_at, deleted_at, url FROM apps WHERE deleted_at IS NULL
+SELECT id, name, sdk_language, sdk_version, framework, metadata, status, error, checksum, created_at, deleted_at, url, workspace_id FROM apps WHERE workspace_id = ? AND deleted_at IS NULL
 `
 
-func (q *Queries) GetApps(ctx context.Context) ([]*App, error) {
-	rows, err := q.db.QueryContext(ctx, getApps)
+func (q *Queries) GetApps(ctx context.Context, workspaceID uuid.UUID) ([]*App, error) {
+	rows, err := q.db.QueryContext(ctx, getApps, workspaceID)
 	if err != nil {
 		return nil, err
 	}
@@ -282,6 +367,112 @@ func (q *Queries) GetApps(ctx context.Context) ([]*App, error) {
 			&i.CreatedAt,
 			&i.DeletedAt,
 			&i.Url,
+			&i.WorkspaceID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getCancellationByID = `-- name: GetCancellationByID :one
+SELECT id, workspace_id, function_id, function_slug, name, started_after, started_before, expression, created_at FROM cancellations WHERE workspace_id = ? AND id = ?
+`
+
+type GetCancellationByIDParams struct {
+	WorkspaceID uuid.UUID
+	ID          ulid.ULID
+}
+
+func (q *Queries) GetCancellationByID(ctx context.Context, arg GetCancellationByIDParams) (*Cancellation, error) {
+	row := q.db.QueryRowContext(ctx, getCancellationByID, arg.WorkspaceID, arg.ID)
+	var i Cancellation
+	err := row.Scan(
+		&i.ID,
+		&i.WorkspaceID,
+		&i.FunctionID,
+		&i.FunctionSlug,
+		&i.Name,
+		&i.StartedAfter,
+		&i.StartedBefore,
+		&i.Expression,
+		&i.CreatedAt,
+	)
+	return &i, err
+}
+
+const getCancellations = `-- name: GetCancellations :many
+SELECT id, workspace_id, function_id, function_slug, name, started_after, started_before, expression, created_at FROM cancellations WHERE workspace_id = ? ORDER BY created_at ASC
+`
+
+func (q *Queries) GetCancellations(ctx context.Context, workspaceID uuid.UUID) ([]*Cancellation, error) {
+	rows, err := q.db.QueryContext(ctx, getCancellations, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*Cancellation
+	for rows.Next() {
+		var i Cancellation
+		if err := rows.Scan(
+			&i.ID,
+			&i.WorkspaceID,
+			&i.FunctionID,
+			&i.FunctionSlug,
+			&i.Name,
+			&i.StartedAfter,
+			&i.StartedBefore,
+			&i.Expression,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getCancellationsByFunctionID = `-- name: GetCancellationsByFunctionID :many
+SELECT id, workspace_id, function_id, function_slug, name, started_after, started_before, expression, created_at FROM cancellations WHERE workspace_id = ? AND function_id = ? ORDER BY created_at ASC
+`
+
+type GetCancellationsByFunctionIDParams struct {
+	WorkspaceID uuid.UUID
+	FunctionID  uuid.UUID
+}
+
+func (q *Queries) GetCancellationsByFunctionID(ctx context.Context, arg GetCancellationsByFunctionIDParams) ([]*Cancellation, error) {
+	rows, err := q.db.QueryContext(ctx, getCancellationsByFunctionID, arg.WorkspaceID, arg.FunctionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*Cancellation
+	for rows.Next() {
+		var i Cancellation
+		if err := rows.Scan(
+			&i.ID,
+			&i.WorkspaceID,
+			&i.FunctionID,
+			&i.FunctionSlug,
+			&i.Name,
+			&i.StartedAfter,
+			&i.StartedBefore,
+			&i.Expression,
+			&i.CreatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -378,6 +569,88 @@ func (q *Queries) GetEventByInternalID(ctx context.Context, internalID ulid.ULID
 	return &i, err
 }
 
+const getEventKeyByHash = `-- name: GetEventKeyByHash :one
+SELECT id, name, source, hash, prefix, created_at, rotated_at, revoked_at, scopes, env FROM event_keys WHERE hash = ? AND revoked_at IS NULL
+`
+
+func (q *Queries) GetEventKeyByHash(ctx context.Context, hash string) (*EventKey, error) {
+	row := q.db.QueryRowContext(ctx, getEventKeyByHash, hash)
+	var i EventKey
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Source,
+		&i.Hash,
+		&i.Prefix,
+		&i.CreatedAt,
+		&i.RotatedAt,
+		&i.RevokedAt,
+		&i.Scopes,
+		&i.Env,
+	)
+	return &i, err
+}
+
+const getEventKeyByID = `-- name: GetEventKeyByID :one
+SELECT id, name, source, hash, prefix, created_at, rotated_at, revoked_at, scopes, env FROM event_keys WHERE id = ?
+`
+
+func (q *Queries) GetEventKeyByID(ctx context.Context, id ulid.ULID) (*EventKey, error) {
+	row := q.db.QueryRowContext(ctx, getEventKeyByID, id)
+	var i EventKey
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Source,
+		&i.Hash,
+		&i.Prefix,
+		&i.CreatedAt,
+		&i.RotatedAt,
+		&i.RevokedAt,
+		&i.Scopes,
+		&i.Env,
+	)
+	return &i, err
+}
+
+const getEventKeys = `-- name: GetEventKeys :many
+SELECT id, name, source, hash, prefix, created_at, rotated_at, revoked_at, scopes, env FROM event_keys ORDER BY created_at ASC
+`
+
+func (q *Queries) GetEventKeys(ctx context.Context) ([]*EventKey, error) {
+	rows, err := q.db.QueryContext(ctx, getEventKeys)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*EventKey
+	for rows.Next() {
+		var i EventKey
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Source,
+			&i.Hash,
+			&i.Prefix,
+			&i.CreatedAt,
+			&i.RotatedAt,
+			&i.RevokedAt,
+			&i.Scopes,
+			&i.Env,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getEventsByInternalIDs = `-- name: GetEventsByInternalIDs :many
 SELECT internal_id, account_id, workspace_id, source, source_id, received_at, event_id, event_name, event_data, event_user, event_v, event_ts FROM events WHERE internal_id IN (/*SLICE:ids*/?)
 `
@@ -433,7 +706,8 @@ SELECT DISTINCT e.internal_id, e.account_id, e.workspace_id, e.source, e.source_
 FROM events AS e
 LEFT OUTER JOIN function_runs AS r ON r.event_id = e.internal_id
 WHERE
-	e.received_at > ?
+	e.workspace_id = ?
+	AND e.received_at > ?
 	AND e.received_at <= ?
 	AND (
 		-- Include internal events that triggered a run (e.g. an onFailure
@@ -450,6 +724,7 @@ LIMIT ?
 `
 
 type GetEventsTimeboundParams struct {
+	WorkspaceID     uuid.UUID
 	After           time.Time
 	Before          time.Time
 	IncludeInternal interface{}
@@ -458,6 +733,7 @@ type GetEventsTimeboundParams struct {
 
 func (q *Queries) GetEventsTimebound(ctx context.Context, arg GetEventsTimeboundParams) ([]*Event, error) {
 	rows, err := q.db.QueryContext(ctx, getEventsTimebound,
+		arg.WorkspaceID,
 		arg.After,
 		arg.Before,
 		arg.IncludeInternal,
@@ -498,7 +774,7 @@ func (q *Queries) GetEventsTimebound(ctx context.Context, arg GetEventsTimebound
 }
 
 const getFunctionByID = `-- name: GetFunctionByID :one
-SELECT id, app_id, name, slug, config, created_at FROM functions WHERE id = ?
+SELECT id, app_id, name, slug, config, created_at, archived_at, workspace_id FROM functions WHERE id = ?
 `
 
 func (q *Queries) GetFunctionByID(ctx context.Context, id uuid.UUID) (*Function, error) {
@@ -511,16 +787,23 @@ func (q *Queries) GetFunctionByID(ctx context.Context, id uuid.UUID) (*Function,
 		&i.Slug,
 		&i.Config,
 		&i.CreatedAt,
+		&i.ArchivedAt,
+		&i.WorkspaceID,
 	)
 	return &i, err
 }
 
 const getFunctionBySlug = `-- name: GetFunctionBySlug :one
-SELECT id, app_id, name, slug, config, created_at FROM functions WHERE slug = ?
+SELECT id, app_id, name, slug, config, created_at, archived_at, workspace_id FROM functions WHERE workspace_id = ? AND slug = ?
 `
 
-func (q *Queries) GetFunctionBySlug(ctx context.Context, slug string) (*Function, error) {
-	row := q.db.QueryRowContext(ctx, getFunctionBySlug, slug)
+type GetFunctionBySlugParams struct {
+	WorkspaceID uuid.UUID
+	Slug        string
+}
+
+func (q *Queries) GetFunctionBySlug(ctx context.Context, arg GetFunctionBySlugParams) (*Function, error) {
+	row := q.db.QueryRowContext(ctx, getFunctionBySlug, arg.WorkspaceID, arg.Slug)
 	var i Function
 	err := row.Scan(
 		&i.ID,
@@ -529,24 +812,31 @@ func (q *Queries) GetFunctionBySlug(ctx context.Context, slug string) (*Function
 		&i.Slug,
 		&i.Config,
 		&i.CreatedAt,
+		&i.ArchivedAt,
+		&i.WorkspaceID,
 	)
 	return &i, err
 }
 
 const getFunctionRun = `-- name: GetFunctionRun :one
-SELECT function_runs.run_id, function_runs.run_started_at, function_runs.function_id, function_runs.function_version, function_runs.trigger_type, function_runs.event_id, function_runs.batch_id, function_runs.original_run_id, function_runs.cron, function_finishes.run_id, function_finishes.status, function_finishes.output, function_finishes.completed_step_count, function_finishes.created_at
+SELECT function_runs.run_id, function_runs.run_started_at, function_runs.function_id, function_runs.function_version, function_runs.trigger_type, function_runs.event_id, function_runs.batch_id, function_runs.original_run_id, function_runs.cron, function_runs.status, function_runs.workspace_id, function_finishes.run_id, function_finishes.status, function_finishes.output, function_finishes.completed_step_count, function_finishes.created_at
   FROM function_runs
   LEFT JOIN function_finishes ON function_finishes.run_id = function_runs.run_id
-  WHERE function_runs.run_id = ?1
+  WHERE function_runs.workspace_id = ?1 AND function_runs.run_id = ?2
 `
 
+type GetFunctionRunParams struct {
+	WorkspaceID uuid.UUID
+	RunID       ulid.ULID
+}
+
 type GetFunctionRunRow struct {
 	FunctionRun    FunctionRun
 	FunctionFinish FunctionFinish
 }
 
-func (q *Queries) GetFunctionRun(ctx context.Context, runID ulid.ULID) (*GetFunctionRunRow, error) {
-	row := q.db.QueryRowContext(ctx, getFunctionRun, runID)
+func (q *Queries) GetFunctionRun(ctx context.Context, arg GetFunctionRunParams) (*GetFunctionRunRow, error) {
+	row := q.db.QueryRowContext(ctx, getFunctionRun, arg.WorkspaceID, arg.RunID)
 	var i GetFunctionRunRow
 	err := row.Scan(
 		&i.FunctionRun.RunID,
@@ -558,6 +848,8 @@ func (q *Queries) GetFunctionRun(ctx context.Context, runID ulid.ULID) (*GetFunc
 		&i.FunctionRun.BatchID,
 		&i.FunctionRun.OriginalRunID,
 		&i.FunctionRun.Cron,
+		&i.FunctionRun.Status,
+		&i.FunctionRun.WorkspaceID,
 		&i.FunctionFinish.RunID,
 		&i.FunctionFinish.Status,
 		&i.FunctionFinish.Output,
@@ -661,36 +953,60 @@ func (q *Queries) GetFunctionRunHistory(ctx context.Context, runID ulid.ULID) ([
 	return items, nil
 }
 
-const getFunctionRunsFromEvents = `-- name: GetFunctionRunsFromEvents :many
-SELECT function_runs.run_id, function_runs.run_started_at, function_runs.function_id, function_runs.function_version, function_runs.trigger_type, function_runs.event_id, function_runs.batch_id, function_runs.original_run_id, function_runs.cron, function_finishes.run_id, function_finishes.status, function_finishes.output, function_finishes.completed_step_count, function_finishes.created_at FROM function_runs
+const getFunctionRunsByCursor = `-- name: GetFunctionRunsByCursor :many
+SELECT function_runs.run_id, function_runs.run_started_at, function_runs.function_id, function_runs.function_version, function_runs.trigger_type, function_runs.event_id, function_runs.batch_id, function_runs.original_run_id, function_runs.cron, function_runs.status, function_runs.workspace_id, function_finishes.run_id, function_finishes.status, function_finishes.output, function_finishes.completed_step_count, function_finishes.created_at FROM function_runs
 LEFT JOIN function_finishes ON function_finishes.run_id = function_runs.run_id
-WHERE function_runs.event_id IN (/*SLICE:event_ids*/?)
+WHERE function_runs.workspace_id = ?
+	AND (function_runs.run_started_at < ? OR (function_runs.run_started_at = ? AND function_runs.run_id < ?))
+	AND function_runs.run_started_at > ? AND function_runs.run_started_at <= ?
+	AND (? OR COALESCE(function_finishes.status, function_runs.status, 'Scheduled') IN (/*SLICE:statuses*/?))
+ORDER BY function_runs.run_started_at DESC, function_runs.run_id DESC
+LIMIT ?
 `
 
-type GetFunctionRunsFromEventsRow struct {
+type GetFunctionRunsByCursorParams struct {
+	WorkspaceID     uuid.UUID
+	CursorStartedAt time.Time
+	Cursor          ulid.ULID
+	After           time.Time
+	Before          time.Time
+	AnyStatus       bool
+	Statuses        []string
+	Limit           int64
+}
+
+type GetFunctionRunsByCursorRow struct {
 	FunctionRun    FunctionRun
 	FunctionFinish FunctionFinish
 }
 
-func (q *Queries) GetFunctionRunsFromEvents(ctx context.Context, eventIds []ulid.ULID) ([]*GetFunctionRunsFromEventsRow, error) {
-	query := getFunctionRunsFromEvents
+func (q *Queries) GetFunctionRunsByCursor(ctx context.Context, arg GetFunctionRunsByCursorParams) ([]*GetFunctionRunsByCursorRow, error) {
+	query := getFunctionRunsByCursor
 	var queryParams []interface{}
-	if len(eventIds) > 0 {
-		for _, v := range eventIds {
+	queryParams = append(queryParams, arg.WorkspaceID)
+	queryParams = append(queryParams, arg.CursorStartedAt)
+	queryParams = append(queryParams, arg.CursorStartedAt)
+	queryParams = append(queryParams, arg.Cursor)
+	queryParams = append(queryParams, arg.After)
+	queryParams = append(queryParams, arg.Before)
+	queryParams = append(queryParams, arg.AnyStatus)
+	if len(arg.Statuses) > 0 {
+		for _, v := range arg.Statuses {
 			queryParams = append(queryParams, v)
 		}
-		query = strings.Replace(query, "/*SLICE:event_ids*/?", strings.Repeat(",?", len(eventIds))[1:], 1)
+		query = strings.Replace(query, "/*SLICE:statuses*/?", strings.Repeat(",?", len(arg.Statuses))[1:], 1)
 	} else {
-		query = strings.Replace(query, "/*SLICE:event_ids*/?", "NULL", 1)
+		query = strings.Replace(query, "/*SLICE:statuses*/?", "NULL", 1)
 	}
+	queryParams = append(queryParams, arg.Limit)
 	rows, err := q.db.QueryContext(ctx, query, queryParams...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []*GetFunctionRunsFromEventsRow
+	var items []*GetFunctionRunsByCursorRow
 	for rows.Next() {
-		var i GetFunctionRunsFromEventsRow
+		var i GetFunctionRunsByCursorRow
 		if err := rows.Scan(
 			&i.FunctionRun.RunID,
 			&i.FunctionRun.RunStartedAt,
@@ -701,6 +1017,8 @@ func (q *Queries) GetFunctionRunsFromEvents(ctx context.Context, eventIds []ulid
 			&i.FunctionRun.BatchID,
 			&i.FunctionRun.OriginalRunID,
 			&i.FunctionRun.Cron,
+			&i.FunctionRun.Status,
+			&i.FunctionRun.WorkspaceID,
 			&i.FunctionFinish.RunID,
 			&i.FunctionFinish.Status,
 			&i.FunctionFinish.Output,
@@ -720,34 +1038,60 @@ func (q *Queries) GetFunctionRunsFromEvents(ctx context.Context, eventIds []ulid
 	return items, nil
 }
 
-const getFunctionRunsTimebound = `-- name: GetFunctionRunsTimebound :many
-SELECT function_runs.run_id, function_runs.run_started_at, function_runs.function_id, function_runs.function_version, function_runs.trigger_type, function_runs.event_id, function_runs.batch_id, function_runs.original_run_id, function_runs.cron, function_finishes.run_id, function_finishes.status, function_finishes.output, function_finishes.completed_step_count, function_finishes.created_at FROM function_runs
+const getFunctionRunsByCursorAsc = `-- name: GetFunctionRunsByCursorAsc :many
+SELECT function_runs.run_id, function_runs.run_started_at, function_runs.function_id, function_runs.function_version, function_runs.trigger_type, function_runs.event_id, function_runs.batch_id, function_runs.original_run_id, function_runs.cron, function_runs.status, function_runs.workspace_id, function_finishes.run_id, function_finishes.status, function_finishes.output, function_finishes.completed_step_count, function_finishes.created_at FROM function_runs
 LEFT JOIN function_finishes ON function_finishes.run_id = function_runs.run_id
-WHERE function_runs.run_started_at > ? AND function_runs.run_started_at <= ?
-ORDER BY function_runs.run_started_at DESC
+WHERE function_runs.workspace_id = ?
+	AND (function_runs.run_started_at > ? OR (function_runs.run_started_at = ? AND function_runs.run_id > ?))
+	AND function_runs.run_started_at > ? AND function_runs.run_started_at <= ?
+	AND (? OR COALESCE(function_finishes.status, function_runs.status, 'Scheduled') IN (/*SLICE:statuses*/?))
+ORDER BY function_runs.run_started_at ASC, function_runs.run_id ASC
 LIMIT ?
 `
 
-type GetFunctionRunsTimeboundParams struct {
-	After  time.Time
-	Before time.Time
-	Limit  int64
+type GetFunctionRunsByCursorAscParams struct {
+	WorkspaceID     uuid.UUID
+	CursorStartedAt time.Time
+	Cursor          ulid.ULID
+	After           time.Time
+	Before          time.Time
+	AnyStatus       bool
+	Statuses        []string
+	Limit           int64
 }
 
-type GetFunctionRunsTimeboundRow struct {
+type GetFunctionRunsByCursorAscRow struct {
 	FunctionRun    FunctionRun
 	FunctionFinish FunctionFinish
 }
 
-func (q *Queries) GetFunctionRunsTimebound(ctx context.Context, arg GetFunctionRunsTimeboundParams) ([]*GetFunctionRunsTimeboundRow, error) {
-	rows, err := q.db.QueryContext(ctx, getFunctionRunsTimebound, arg.After, arg.Before, arg.Limit)
+func (q *Queries) GetFunctionRunsByCursorAsc(ctx context.Context, arg GetFunctionRunsByCursorAscParams) ([]*GetFunctionRunsByCursorAscRow, error) {
+	query := getFunctionRunsByCursorAsc
+	var queryParams []interface{}
+	queryParams = append(queryParams, arg.WorkspaceID)
+	queryParams = append(queryParams, arg.CursorStartedAt)
+	queryParams = append(queryParams, arg.CursorStartedAt)
+	queryParams = append(queryParams, arg.Cursor)
+	queryParams = append(queryParams, arg.After)
+	queryParams = append(queryParams, arg.Before)
+	queryParams = append(queryParams, arg.AnyStatus)
+	if len(arg.Statuses) > 0 {
+		for _, v := range arg.Statuses {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:statuses*/?", strings.Repeat(",?", len(arg.Statuses))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:statuses*/?", "NULL", 1)
+	}
+	queryParams = append(queryParams, arg.Limit)
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []*GetFunctionRunsTimeboundRow
+	var items []*GetFunctionRunsByCursorAscRow
 	for rows.Next() {
-		var i GetFunctionRunsTimeboundRow
+		var i GetFunctionRunsByCursorAscRow
 		if err := rows.Scan(
 			&i.FunctionRun.RunID,
 			&i.FunctionRun.RunStartedAt,
@@ -758,6 +1102,8 @@ func (q *Queries) GetFunctionRunsTimebound(ctx context.Context, arg GetFunctionR
 			&i.FunctionRun.BatchID,
 			&i.FunctionRun.OriginalRunID,
 			&i.FunctionRun.Cron,
+			&i.FunctionRun.Status,
+			&i.FunctionRun.WorkspaceID,
 			&i.FunctionFinish.RunID,
 			&i.FunctionFinish.Status,
 			&i.FunctionFinish.Output,
@@ -777,30 +1123,129 @@ func (q *Queries) GetFunctionRunsTimebound(ctx context.Context, arg GetFunctionR
 	return items, nil
 }
 
-const getFunctions = `-- name: GetFunctions :many
-SELECT id, app_id, name, slug, config, created_at FROM functions
+const getFunctionRunsFromEvents = `-- name: GetFunctionRunsFromEvents :many
+SELECT function_runs.run_id, function_runs.run_started_at, function_runs.function_id, function_runs.function_version, function_runs.trigger_type, function_runs.event_id, function_runs.batch_id, function_runs.original_run_id, function_runs.cron, function_runs.status, function_runs.workspace_id, function_finishes.run_id, function_finishes.status, function_finishes.output, function_finishes.completed_step_count, function_finishes.created_at FROM function_runs
+LEFT JOIN function_finishes ON function_finishes.run_id = function_runs.run_id
+WHERE function_runs.workspace_id = ? AND function_runs.event_id IN (/*SLICE:event_ids*/?)
 `
 
-func (q *Queries) GetFunctions(ctx context.Context) ([]*Function, error) {
-	rows, err := q.db.QueryContext(ctx, getFunctions)
+type GetFunctionRunsFromEventsParams struct {
+	WorkspaceID uuid.UUID
+	EventIds    []ulid.ULID
+}
+
+type GetFunctionRunsFromEventsRow struct {
+	FunctionRun    FunctionRun
+	FunctionFinish FunctionFinish
+}
+
+func (q *Queries) GetFunctionRunsFromEvents(ctx context.Context, arg GetFunctionRunsFromEventsParams) ([]*GetFunctionRunsFromEventsRow, error) {
+	query := getFunctionRunsFromEvents
+	var queryParams []interface{}
+	queryParams = append(queryParams, arg.WorkspaceID)
+	if len(arg.EventIds) > 0 {
+		for _, v := range arg.EventIds {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:event_ids*/?", strings.Repeat(",?", len(arg.EventIds))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:event_ids*/?", "NULL", 1)
+	}
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []*Function
+	var items []*GetFunctionRunsFromEventsRow
 	for rows.Next() {
-		var i Function
+		var i GetFunctionRunsFromEventsRow
 		if err := rows.Scan(
-			&i.ID,
-			&i.AppID,
-			&i.Name,
-			&i.Slug,
-			&i.Config,
-			&i.CreatedAt,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, &i)
+			&i.FunctionRun.RunID,
+			&i.FunctionRun.RunStartedAt,
+			&i.FunctionRun.FunctionID,
+			&i.FunctionRun.FunctionVersion,
+			&i.FunctionRun.TriggerType,
+			&i.FunctionRun.EventID,
+			&i.FunctionRun.BatchID,
+			&i.FunctionRun.OriginalRunID,
+			&i.FunctionRun.Cron,
+			&i.FunctionRun.Status,
+			&i.FunctionRun.WorkspaceID,
+			&i.FunctionFinish.RunID,
+			&i.FunctionFinish.Status,
+			&i.FunctionFinish.Output,
+			&i.FunctionFinish.CompletedStepCount,
+			&i.FunctionFinish.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFunctionRunsTimebound = `-- name: GetFunctionRunsTimebound :many
+SELECT function_runs.run_id, function_runs.run_started_at, function_runs.function_id, function_runs.function_version, function_runs.trigger_type, function_runs.event_id, function_runs.batch_id, function_runs.original_run_id, function_runs.cron, function_runs.status, function_runs.workspace_id, function_finishes.run_id, function_finishes.status, function_finishes.output, function_finishes.completed_step_count, function_finishes.created_at FROM function_runs
+LEFT JOIN function_finishes ON function_finishes.run_id = function_runs.run_id
+WHERE function_runs.workspace_id = ?
+	AND function_runs.run_started_at > ? AND function_runs.run_started_at <= ?
+ORDER BY function_runs.run_started_at DESC
+LIMIT ?
+`
+
+type GetFunctionRunsTimeboundParams struct {
+	WorkspaceID uuid.UUID
+	After       time.Time
+	Before      time.Time
+	Limit       int64
+}
+
+type GetFunctionRunsTimeboundRow struct {
+	FunctionRun    FunctionRun
+	FunctionFinish FunctionFinish
+}
+
+func (q *Queries) GetFunctionRunsTimebound(ctx context.Context, arg GetFunctionRunsTimeboundParams) ([]*GetFunctionRunsTimeboundRow, error) {
+	rows, err := q.db.QueryContext(ctx, getFunctionRunsTimebound,
+		arg.WorkspaceID,
+		arg.After,
+		arg.Before,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*GetFunctionRunsTimeboundRow
+	for rows.Next() {
+		var i GetFunctionRunsTimeboundRow
+		if err := rows.Scan(
+			&i.FunctionRun.RunID,
+			&i.FunctionRun.RunStartedAt,
+			&i.FunctionRun.FunctionID,
+			&i.FunctionRun.FunctionVersion,
+			&i.FunctionRun.TriggerType,
+			&i.FunctionRun.EventID,
+			&i.FunctionRun.BatchID,
+			&i.FunctionRun.OriginalRunID,
+			&i.FunctionRun.Cron,
+			&i.FunctionRun.Status,
+			&i.FunctionRun.WorkspaceID,
+			&i.FunctionFinish.RunID,
+			&i.FunctionFinish.Status,
+			&i.FunctionFinish.Output,
+			&i.FunctionFinish.CompletedStepCount,
+			&i.FunctionFinish.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
 	}
 	if err := rows.Close(); err != nil {
 		return nil, err
@@ -811,6 +1256,188 @@ func (q *Queries) GetFunctions(ctx context.Context) ([]*Function, error) {
 	return items, nil
 }
 
+const getFunctions = `-- name: GetFunctions :many
+SELECT id, app_id, name, slug, config, created_at, archived_at, workspace_id FROM functions WHERE workspace_id = ?
+`
+
+func (q *Queries) GetFunctions(ctx context.Context, workspaceID uuid.UUID) ([]*Function, error) {
+	rows, err := q.db.QueryContext(ctx, getFunctions, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*Function
+	for rows.Next() {
+		var i Function
+		if err := rows.Scan(
+			&i.ID,
+			&i.AppID,
+			&i.Name,
+			&i.Slug,
+			&i.Config,
+			&i.CreatedAt,
+			&i.ArchivedAt,
+			&i.WorkspaceID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getStepLogs = `-- name: GetStepLogs :many
+SELECT run_id, step_id, attempt, level, message, fields, timestamp FROM step_logs WHERE run_id = ? ORDER BY timestamp ASC
+`
+
+func (q *Queries) GetStepLogs(ctx context.Context, runID ulid.ULID) ([]*StepLog, error) {
+	rows, err := q.db.QueryContext(ctx, getStepLogs, runID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*StepLog
+	for rows.Next() {
+		var i StepLog
+		if err := rows.Scan(
+			&i.RunID,
+			&i.StepID,
+			&i.Attempt,
+			&i.Level,
+			&i.Message,
+			&i.Fields,
+			&i.Timestamp,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getStepLogsByStepID = `-- name: GetStepLogsByStepID :many
+SELECT run_id, step_id, attempt, level, message, fields, timestamp FROM step_logs WHERE run_id = ? AND step_id = ? ORDER BY timestamp ASC
+`
+
+type GetStepLogsByStepIDParams struct {
+	RunID  ulid.ULID
+	StepID string
+}
+
+func (q *Queries) GetStepLogsByStepID(ctx context.Context, arg GetStepLogsByStepIDParams) ([]*StepLog, error) {
+	rows, err := q.db.QueryContext(ctx, getStepLogsByStepID, arg.RunID, arg.StepID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*StepLog
+	for rows.Next() {
+		var i StepLog
+		if err := rows.Scan(
+			&i.RunID,
+			&i.StepID,
+			&i.Attempt,
+			&i.Level,
+			&i.Message,
+			&i.Fields,
+			&i.Timestamp,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTraceSpansByRunID = `-- name: GetTraceSpansByRunID :many
+SELECT timestamp, trace_id, span_id, parent_span_id, trace_state, span_name, span_kind, service_name, resource_attributes, scope_name, scope_version, span_attributes, duration, status_code, status_message, events, links, run_id FROM traces WHERE trace_id IN (SELECT DISTINCT trace_id FROM traces WHERE run_id = ?) ORDER BY timestamp ASC
+`
+
+func (q *Queries) GetTraceSpansByRunID(ctx context.Context, runID ulid.ULID) ([]*Trace, error) {
+	rows, err := q.db.QueryContext(ctx, getTraceSpansByRunID, runID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*Trace
+	for rows.Next() {
+		var i Trace
+		if err := rows.Scan(
+			&i.Timestamp,
+			&i.TraceID,
+			&i.SpanID,
+			&i.ParentSpanID,
+			&i.TraceState,
+			&i.SpanName,
+			&i.SpanKind,
+			&i.ServiceName,
+			&i.ResourceAttributes,
+			&i.ScopeName,
+			&i.ScopeVersion,
+			&i.SpanAttributes,
+			&i.Duration,
+			&i.StatusCode,
+			&i.StatusMessage,
+			&i.Events,
+			&i.Links,
+			&i.RunID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWorkspaceFunctionByID = `-- name: GetWorkspaceFunctionByID :one
+SELECT id, app_id, name, slug, config, created_at, archived_at, workspace_id FROM functions WHERE workspace_id = ? AND id = ?
+`
+
+type GetWorkspaceFunctionByIDParams struct {
+	WorkspaceID uuid.UUID
+	ID          uuid.UUID
+}
+
+func (q *Queries) GetWorkspaceFunctionByID(ctx context.Context, arg GetWorkspaceFunctionByIDParams) (*Function, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceFunctionByID, arg.WorkspaceID, arg.ID)
+	var i Function
+	err := row.Scan(
+		&i.ID,
+		&i.AppID,
+		&i.Name,
+		&i.Slug,
+		&i.Config,
+		&i.CreatedAt,
+		&i.ArchivedAt,
+		&i.WorkspaceID,
+	)
+	return &i, err
+}
+
 const hardDeleteApp = `-- name: HardDeleteApp :exec
 DELETE FROM apps WHERE id = ?
 `
@@ -822,8 +1449,8 @@ func (q *Queries) HardDeleteApp(ctx context.Context, id uuid.UUID) error {
 
 const insertApp = `-- name: InsertApp :one
 INSERT INTO apps
-	(id, name, sdk_language, sdk_version, framework, metadata, status, error, checksum, url) VALUES
-	(?, ?, ?, ?, ?, ?, ?, ?, ?, ?) RETURNING id, name, sdk_language, sdk_version, framework, metadata, status, error, checksum, created_at, deleted_at, url
+	(id, name, sdk_language, sdk_version, framework, metadata, status, error, checksum, url, workspace_id) VALUES
+	(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) RETURNING id, name, sdk_language, sdk_version, framework, metadata, status, error, checksum, created_at, deleted_at, url, workspace_id
 `
 
 type InsertAppParams struct {
@@ -837,6 +1464,7 @@ type InsertAppParams struct {
 	Error       sql.NullString
 	Checksum    string
 	Url         string
+	WorkspaceID uuid.UUID
 }
 
 func (q *Queries) InsertApp(ctx context.Context, arg InsertAppParams) (*App, error) {
@@ -851,6 +1479,7 @@ func (q *Queries) InsertApp(ctx context.Context, arg InsertAppParams) (*App, err
 		arg.Error,
 		arg.Checksum,
 		arg.Url,
+		arg.WorkspaceID,
 	)
 	var i App
 	err := row.Scan(
@@ -866,32 +1495,69 @@ func (q *Queries) InsertApp(ctx context.Context, arg InsertAppParams) (*App, err
 		&i.CreatedAt,
 		&i.DeletedAt,
 		&i.Url,
+		&i.WorkspaceID,
 	)
 	return &i, err
 }
 
+const insertCancellation = `-- name: InsertCancellation :exec
+INSERT INTO cancellations
+	(id, workspace_id, function_id, function_slug, name, started_after, started_before, expression, created_at) VALUES
+	(?, ?, ?, ?, ?, ?, ?, ?, ?)
+`
+
+type InsertCancellationParams struct {
+	ID            ulid.ULID
+	WorkspaceID   uuid.UUID
+	FunctionID    uuid.UUID
+	FunctionSlug  string
+	Name          sql.NullString
+	StartedAfter  sql.NullTime
+	StartedBefore time.Time
+	Expression    sql.NullString
+	CreatedAt     time.Time
+}
+
+// Cancellations
+func (q *Queries) InsertCancellation(ctx context.Context, arg InsertCancellationParams) error {
+	_, err := q.db.ExecContext(ctx, insertCancellation,
+		arg.ID,
+		arg.WorkspaceID,
+		arg.FunctionID,
+		arg.FunctionSlug,
+		arg.Name,
+		arg.StartedAfter,
+		arg.StartedBefore,
+		arg.Expression,
+		arg.CreatedAt,
+	)
+	return err
+}
+
 const insertEvent = `-- name: InsertEvent :exec
 
 INSERT INTO events
-	(internal_id, received_at, event_id, event_name, event_data, event_user, event_v, event_ts) VALUES
-	(?, ?, ?, ?, ?, ?, ?, ?)
+	(internal_id, workspace_id, received_at, event_id, event_name, event_data, event_user, event_v, event_ts) VALUES
+	(?, ?, ?, ?, ?, ?, ?, ?, ?)
 `
 
 type InsertEventParams struct {
-	InternalID ulid.ULID
-	ReceivedAt time.Time
-	EventID    string
-	EventName  string
-	EventData  string
-	EventUser  string
-	EventV     sql.NullString
-	EventTs    time.Time
+	InternalID  ulid.ULID
+	WorkspaceID uuid.UUID
+	ReceivedAt  time.Time
+	EventID     string
+	EventName   string
+	EventData   string
+	EventUser   string
+	EventV      sql.NullString
+	EventTs     time.Time
 }
 
 // Events
 func (q *Queries) InsertEvent(ctx context.Context, arg InsertEventParams) error {
 	_, err := q.db.ExecContext(ctx, insertEvent,
 		arg.InternalID,
+		arg.WorkspaceID,
 		arg.ReceivedAt,
 		arg.EventID,
 		arg.EventName,
@@ -936,21 +1602,66 @@ func (q *Queries) InsertEventBatch(ctx context.Context, arg InsertEventBatchPara
 	return err
 }
 
+const insertEventKey = `-- name: InsertEventKey :one
+INSERT INTO event_keys
+	(id, name, source, hash, prefix, created_at, scopes, env) VALUES
+	(?, ?, ?, ?, ?, ?, ?, ?) RETURNING id, name, source, hash, prefix, created_at, rotated_at, revoked_at, scopes, env
+`
+
+type InsertEventKeyParams struct {
+	ID        ulid.ULID
+	Name      string
+	Source    sql.NullString
+	Hash      string
+	Prefix    string
+	CreatedAt time.Time
+	Scopes    string
+	Env       sql.NullString
+}
+
+func (q *Queries) InsertEventKey(ctx context.Context, arg InsertEventKeyParams) (*EventKey, error) {
+	row := q.db.QueryRowContext(ctx, insertEventKey,
+		arg.ID,
+		arg.Name,
+		arg.Source,
+		arg.Hash,
+		arg.Prefix,
+		arg.CreatedAt,
+		arg.Scopes,
+		arg.Env,
+	)
+	var i EventKey
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Source,
+		&i.Hash,
+		&i.Prefix,
+		&i.CreatedAt,
+		&i.RotatedAt,
+		&i.RevokedAt,
+		&i.Scopes,
+		&i.Env,
+	)
+	return &i, err
+}
+
 const insertFunction = `-- name: InsertFunction :one
 
 
 INSERT INTO functions
-	(id, app_id, name, slug, config, created_at) VALUES
-	(?, ?, ?, ?, ?, ?) RETURNING id, app_id, name, slug, config, created_at
+	(id, app_id, name, slug, config, created_at, workspace_id) VALUES
+	(?, ?, ?, ?, ?, ?, ?) RETURNING id, app_id, name, slug, config, created_at, archived_at, workspace_id
 `
 
 type InsertFunctionParams struct {
-	ID        uuid.UUID
-	AppID     uuid.UUID
-	Name      string
-	Slug      string
-	Config    string
-	CreatedAt time.Time
+	ID          uuid.UUID
+	AppID       uuid.UUID
+	Name        string
+	Slug        string
+	Config      string
+	CreatedAt   time.Time
+	WorkspaceID uuid.UUID
 }
 
 // functions
@@ -964,6 +1675,7 @@ func (q *Queries) InsertFunction(ctx context.Context, arg InsertFunctionParams)
 		arg.Slug,
 		arg.Config,
 		arg.CreatedAt,
+		arg.WorkspaceID,
 	)
 	var i Function
 	err := row.Scan(
@@ -973,6 +1685,8 @@ func (q *Queries) InsertFunction(ctx context.Context, arg InsertFunctionParams)
 		&i.Slug,
 		&i.Config,
 		&i.CreatedAt,
+		&i.ArchivedAt,
+		&i.WorkspaceID,
 	)
 	return &i, err
 }
@@ -1005,8 +1719,8 @@ func (q *Queries) InsertFunctionFinish(ctx context.Context, arg InsertFunctionFi
 const insertFunctionRun = `-- name: InsertFunctionRun :exec
 
 INSERT INTO function_runs
-	(run_id, run_started_at, function_id, function_version, trigger_type, event_id, batch_id, original_run_id, cron) VALUES
-	(?, ?, ?, ?, ?, ?, ?, ?, ?)
+	(run_id, run_started_at, function_id, function_version, trigger_type, event_id, batch_id, original_run_id, cron, workspace_id) VALUES
+	(?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 `
 
 type InsertFunctionRunParams struct {
@@ -1019,6 +1733,7 @@ type InsertFunctionRunParams struct {
 	BatchID         ulid.ULID
 	OriginalRunID   ulid.ULID
 	Cron            sql.NullString
+	WorkspaceID     uuid.UUID
 }
 
 // function runs
@@ -1033,6 +1748,7 @@ func (q *Queries) InsertFunctionRun(ctx context.Context, arg InsertFunctionRunPa
 		arg.BatchID,
 		arg.OriginalRunID,
 		arg.Cron,
+		arg.WorkspaceID,
 	)
 	return err
 }
@@ -1100,6 +1816,36 @@ func (q *Queries) InsertHistory(ctx context.Context, arg InsertHistoryParams) er
 	return err
 }
 
+const insertStepLog = `-- name: InsertStepLog :exec
+INSERT INTO step_logs
+	(run_id, step_id, attempt, level, message, fields, timestamp) VALUES
+	(?, ?, ?, ?, ?, ?, ?)
+`
+
+type InsertStepLogParams struct {
+	RunID     ulid.ULID
+	StepID    string
+	Attempt   int64
+	Level     string
+	Message   string
+	Fields    sql.NullString
+	Timestamp time.Time
+}
+
+// Step logs
+func (q *Queries) InsertStepLog(ctx context.Context, arg InsertStepLogParams) error {
+	_, err := q.db.ExecContext(ctx, insertStepLog,
+		arg.RunID,
+		arg.StepID,
+		arg.Attempt,
+		arg.Level,
+		arg.Message,
+		arg.Fields,
+		arg.Timestamp,
+	)
+	return err
+}
+
 const insertTrace = `-- name: InsertTrace :exec
 
 INSERT INTO traces
@@ -1202,8 +1948,69 @@ func (q *Queries) InsertTraceRun(ctx context.Context, arg InsertTraceRunParams)
 	return err
 }
 
+const revokeEventKey = `-- name: RevokeEventKey :one
+UPDATE event_keys SET revoked_at = ? WHERE id = ? AND revoked_at IS NULL RETURNING id, name, source, hash, prefix, created_at, rotated_at, revoked_at, scopes, env
+`
+
+type RevokeEventKeyParams struct {
+	RevokedAt sql.NullTime
+	ID        ulid.ULID
+}
+
+func (q *Queries) RevokeEventKey(ctx context.Context, arg RevokeEventKeyParams) (*EventKey, error) {
+	row := q.db.QueryRowContext(ctx, revokeEventKey, arg.RevokedAt, arg.ID)
+	var i EventKey
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Source,
+		&i.Hash,
+		&i.Prefix,
+		&i.CreatedAt,
+		&i.RotatedAt,
+		&i.RevokedAt,
+		&i.Scopes,
+		&i.Env,
+	)
+	return &i, err
+}
+
+const rotateEventKey = `-- name: RotateEventKey :one
+UPDATE event_keys SET hash = ?, prefix = ?, rotated_at = ? WHERE id = ? AND revoked_at IS NULL RETURNING id, name, source, hash, prefix, created_at, rotated_at, revoked_at, scopes, env
+`
+
+type RotateEventKeyParams struct {
+	Hash      string
+	Prefix    string
+	RotatedAt sql.NullTime
+	ID        ulid.ULID
+}
+
+func (q *Queries) RotateEventKey(ctx context.Context, arg RotateEventKeyParams) (*EventKey, error) {
+	row := q.db.QueryRowContext(ctx, rotateEventKey,
+		arg.Hash,
+		arg.Prefix,
+		arg.RotatedAt,
+		arg.ID,
+	)
+	var i EventKey
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Source,
+		&i.Hash,
+		&i.Prefix,
+		&i.CreatedAt,
+		&i.RotatedAt,
+		&i.RevokedAt,
+		&i.Scopes,
+		&i.Env,
+	)
+	return &i, err
+}
+
 const updateAppError = `-- name: UpdateAppError :one
-UPDATE apps SET error = ? WHERE id = ? RETURNING id, name, sdk_language, sdk_version, framework, metadata, status, error, checksum, created_at, deleted_at, url
+UPDATE apps SET error = ? WHERE id = ? RETURNING id, name, sdk_language, sdk_version, framework, metadata, status, error, checksum, created_at, deleted_at, url, workspace_id
 `
 
 type UpdateAppErrorParams struct {
@@ -1227,12 +2034,13 @@ func (q *Queries) UpdateAppError(ctx context.Context, arg UpdateAppErrorParams)
 		&i.CreatedAt,
 		&i.DeletedAt,
 		&i.Url,
+		&i.WorkspaceID,
 	)
 	return &i, err
 }
 
 const updateAppURL = `-- name: UpdateAppURL :one
-UPDATE apps SET url = ? WHERE id = ? RETURNING id, name, sdk_language, sdk_version, framework, metadata, status, error, checksum, created_at, deleted_at, url
+UPDATE apps SET url = ? WHERE id = ? RETURNING id, name, sdk_language, sdk_version, framework, metadata, status, error, checksum, created_at, deleted_at, url, workspace_id
 `
 
 type UpdateAppURLParams struct {
@@ -1256,12 +2064,38 @@ func (q *Queries) UpdateAppURL(ctx context.Context, arg UpdateAppURLParams) (*Ap
 		&i.CreatedAt,
 		&i.DeletedAt,
 		&i.Url,
+		&i.WorkspaceID,
+	)
+	return &i, err
+}
+
+const updateFunctionArchivedAt = `-- name: UpdateFunctionArchivedAt :one
+UPDATE functions SET archived_at = ? WHERE id = ? RETURNING id, app_id, name, slug, config, created_at, archived_at, workspace_id
+`
+
+type UpdateFunctionArchivedAtParams struct {
+	ArchivedAt sql.NullTime
+	ID         uuid.UUID
+}
+
+func (q *Queries) UpdateFunctionArchivedAt(ctx context.Context, arg UpdateFunctionArchivedAtParams) (*Function, error) {
+	row := q.db.QueryRowContext(ctx, updateFunctionArchivedAt, arg.ArchivedAt, arg.ID)
+	var i Function
+	err := row.Scan(
+		&i.ID,
+		&i.AppID,
+		&i.Name,
+		&i.Slug,
+		&i.Config,
+		&i.CreatedAt,
+		&i.ArchivedAt,
+		&i.WorkspaceID,
 	)
 	return &i, err
 }
 
 const updateFunctionConfig = `-- name: UpdateFunctionConfig :one
-UPDATE functions SET config = ? WHERE id = ? RETURNING id, app_id, name, slug, config, created_at
+UPDATE functions SET config = ? WHERE id = ? RETURNING id, app_id, name, slug, config, created_at, archived_at, workspace_id
 `
 
 type UpdateFunctionConfigParams struct {
@@ -1279,23 +2113,95 @@ func (q *Queries) UpdateFunctionConfig(ctx context.Context, arg UpdateFunctionCo
 		&i.Slug,
 		&i.Config,
 		&i.CreatedAt,
+		&i.ArchivedAt,
+		&i.WorkspaceID,
 	)
 	return &i, err
 }
 
+const updateFunctionRunStatus = `-- name: UpdateFunctionRunStatus :exec
+UPDATE function_runs SET status = ? WHERE run_id = ?
+`
+
+type UpdateFunctionRunStatusParams struct {
+	Status sql.NullString
+	RunID  ulid.ULID
+}
+
+func (q *Queries) UpdateFunctionRunStatus(ctx context.Context, arg UpdateFunctionRunStatusParams) error {
+	_, err := q.db.ExecContext(ctx, updateFunctionRunStatus, arg.Status, arg.RunID)
+	return err
+}
+
 const workspaceEvents = `-- name: WorkspaceEvents :many
-SELECT internal_id, account_id, workspace_id, source, source_id, received_at, event_id, event_name, event_data, event_user, event_v, event_ts FROM events WHERE internal_id < ? AND received_at <= ? AND received_at >= ? ORDER BY internal_id DESC LIMIT ?
+SELECT internal_id, account_id, workspace_id, source, source_id, received_at, event_id, event_name, event_data, event_user, event_v, event_ts FROM events WHERE workspace_id = ? AND internal_id < ? AND received_at <= ? AND received_at >= ? ORDER BY internal_id DESC LIMIT ?
 `
 
 type WorkspaceEventsParams struct {
-	Cursor ulid.ULID
-	Before time.Time
-	After  time.Time
-	Limit  int64
+	WorkspaceID uuid.UUID
+	Cursor      ulid.ULID
+	Before      time.Time
+	After       time.Time
+	Limit       int64
 }
 
 func (q *Queries) WorkspaceEvents(ctx context.Context, arg WorkspaceEventsParams) ([]*Event, error) {
 	rows, err := q.db.QueryContext(ctx, workspaceEvents,
+		arg.WorkspaceID,
+		arg.Cursor,
+		arg.Before,
+		arg.After,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*Event
+	for rows.Next() {
+		var i Event
+		if err := rows.Scan(
+			&i.InternalID,
+			&i.AccountID,
+			&i.WorkspaceID,
+			&i.Source,
+			&i.SourceID,
+			&i.ReceivedAt,
+			&i.EventID,
+			&i.EventName,
+			&i.EventData,
+			&i.EventUser,
+			&i.EventV,
+			&i.EventTs,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const workspaceEventsAsc = `-- name: WorkspaceEventsAsc :many
+SELECT internal_id, account_id, workspace_id, source, source_id, received_at, event_id, event_name, event_data, event_user, event_v, event_ts FROM events WHERE workspace_id = ? AND internal_id > ? AND received_at <= ? AND received_at >= ? ORDER BY internal_id ASC LIMIT ?
+`
+
+type WorkspaceEventsAscParams struct {
+	WorkspaceID uuid.UUID
+	Cursor      ulid.ULID
+	Before      time.Time
+	After       time.Time
+	Limit       int64
+}
+
+func (q *Queries) WorkspaceEventsAsc(ctx context.Context, arg WorkspaceEventsAscParams) ([]*Event, error) {
+	rows, err := q.db.QueryContext(ctx, workspaceEventsAsc,
+		arg.WorkspaceID,
 		arg.Cursor,
 		arg.Before,
 		arg.After,
@@ -1336,19 +2242,77 @@ func (q *Queries) WorkspaceEvents(ctx context.Context, arg WorkspaceEventsParams
 }
 
 const workspaceNamedEvents = `-- name: WorkspaceNamedEvents :many
-SELECT internal_id, account_id, workspace_id, source, source_id, received_at, event_id, event_name, event_data, event_user, event_v, event_ts FROM events WHERE internal_id < ? AND received_at <= ? AND received_at >= ? AND event_name = ? ORDER BY internal_id DESC LIMIT ?
+SELECT internal_id, account_id, workspace_id, source, source_id, received_at, event_id, event_name, event_data, event_user, event_v, event_ts FROM events WHERE workspace_id = ? AND internal_id < ? AND received_at <= ? AND received_at >= ? AND event_name = ? ORDER BY internal_id DESC LIMIT ?
 `
 
 type WorkspaceNamedEventsParams struct {
-	Cursor ulid.ULID
-	Before time.Time
-	After  time.Time
-	Name   string
-	Limit  int64
+	WorkspaceID uuid.UUID
+	Cursor      ulid.ULID
+	Before      time.Time
+	After       time.Time
+	Name        string
+	Limit       int64
 }
 
 func (q *Queries) WorkspaceNamedEvents(ctx context.Context, arg WorkspaceNamedEventsParams) ([]*Event, error) {
 	rows, err := q.db.QueryContext(ctx, workspaceNamedEvents,
+		arg.WorkspaceID,
+		arg.Cursor,
+		arg.Before,
+		arg.After,
+		arg.Name,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*Event
+	for rows.Next() {
+		var i Event
+		if err := rows.Scan(
+			&i.InternalID,
+			&i.AccountID,
+			&i.WorkspaceID,
+			&i.Source,
+			&i.SourceID,
+			&i.ReceivedAt,
+			&i.EventID,
+			&i.EventName,
+			&i.EventData,
+			&i.EventUser,
+			&i.EventV,
+			&i.EventTs,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const workspaceNamedEventsAsc = `-- name: WorkspaceNamedEventsAsc :many
+SELECT internal_id, account_id, workspace_id, source, source_id, received_at, event_id, event_name, event_data, event_user, event_v, event_ts FROM events WHERE workspace_id = ? AND internal_id > ? AND received_at <= ? AND received_at >= ? AND event_name = ? ORDER BY internal_id ASC LIMIT ?
+`
+
+type WorkspaceNamedEventsAscParams struct {
+	WorkspaceID uuid.UUID
+	Cursor      ulid.ULID
+	Before      time.Time
+	After       time.Time
+	Name        string
+	Limit       int64
+}
+
+func (q *Queries) WorkspaceNamedEventsAsc(ctx context.Context, arg WorkspaceNamedEventsAscParams) ([]*Event, error) {
+	rows, err := q.db.QueryContext(ctx, workspaceNamedEventsAsc,
+		arg.WorkspaceID,
 		arg.Cursor,
 		arg.Before,
 		arg.After,
@@ -10,7 +10,8 @@ CREATE TABLE apps (
 	checksum VARCHAR NOT NULL,
 	created_at TIMESTAMP NOT NULL,
 	deleted_at TIMESTAMP,
-	url VARCHAR NOT NULL
+	url VARCHAR NOT NULL,
+	workspace_id CHAR(36) NOT NULL DEFAULT '00000000-0000-0000-0000-000000000000'
 );
 
 CREATE TABLE events (
@@ -34,7 +35,9 @@ CREATE TABLE functions (
 	name VARCHAR NOT NULL,
 	slug VARCHAR NOT NULL,
 	config VARCHAR NOT NULL,
-	created_at TIMESTAMP NOT NULL
+	created_at TIMESTAMP NOT NULL,
+	archived_at TIMESTAMP,
+	workspace_id CHAR(36) NOT NULL DEFAULT '00000000-0000-0000-0000-000000000000'
 );
 
 CREATE TABLE function_runs (
@@ -46,9 +49,17 @@ CREATE TABLE function_runs (
 	event_id CHAR(26) NOT NULL,
 	batch_id CHAR(26),
 	original_run_id CHAR(26),
-	cron VARCHAR
+	cron VARCHAR,
+	status VARCHAR,
+	workspace_id CHAR(36) NOT NULL DEFAULT '00000000-0000-0000-0000-000000000000'
 );
 
+CREATE INDEX events_internal_id ON events (internal_id);
+CREATE INDEX function_runs_run_id ON function_runs (run_id);
+CREATE INDEX function_runs_event_id ON function_runs (event_id);
+CREATE INDEX function_runs_run_started_at ON function_runs (run_started_at);
+CREATE INDEX function_runs_workspace_id_run_started_at ON function_runs (workspace_id, run_started_at, run_id);
+
 CREATE TABLE function_finishes (
 	run_id BLOB,
 	-- Ignoring not null because of https://github.com/sqlc-dev/sqlc/issues/2806#issuecomment-1750038624
@@ -58,6 +69,8 @@ CREATE TABLE function_finishes (
 	created_at TIMESTAMP
 );
 
+CREATE INDEX function_finishes_run_id ON function_finishes (run_id);
+
 CREATE TABLE history (
 	id BLOB,
 	created_at TIMESTAMP NOT NULL,
@@ -137,3 +150,38 @@ CREATE TABLE trace_runs (
 	is_batch BOOLEAN NOT NULL,
 	is_debounce BOOLEAN NOT NULL
 );
+
+CREATE TABLE event_keys (
+	id CHAR(26) PRIMARY KEY,
+	name VARCHAR NOT NULL,
+	source VARCHAR,
+	hash CHAR(64) NOT NULL,
+	prefix VARCHAR NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	rotated_at TIMESTAMP,
+	revoked_at TIMESTAMP,
+	scopes VARCHAR NOT NULL DEFAULT 'events:write', -- comma separated
+	env VARCHAR
+);
+
+CREATE TABLE step_logs (
+	run_id CHAR(26) NOT NULL,
+	step_id VARCHAR NOT NULL,
+	attempt INT NOT NULL,
+	level VARCHAR NOT NULL,
+	message VARCHAR NOT NULL,
+	fields VARCHAR,
+	timestamp TIMESTAMP NOT NULL
+);
+
+CREATE TABLE cancellations (
+	id CHAR(26) PRIMARY KEY,
+	workspace_id CHAR(36) NOT NULL,
+	function_id CHAR(36) NOT NULL,
+	function_slug VARCHAR NOT NULL,
+	name VARCHAR,
+	started_after TIMESTAMP,
+	started_before TIMESTAMP NOT NULL,
+	expression VARCHAR,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -35,6 +35,12 @@ func New() (*sql.DB, error) {
 	return db, err
 }
 
+// Migrate migrates the given database to the latest schema, eg. databases
+// restored from snapshots taken before later migrations.
+func Migrate(db *sql.DB) error {
+	return up(db)
+}
+
 // FS contains the filesystem of the stdlib, containing all migrations in subdirs
 // relative to this package.
 //
@@ -0,0 +1,36 @@
+package cqrs
+
+import (
+	"context"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+type StepLogManager interface {
+	StepLogReader
+	StepLogWriter
+}
+
+type StepLogWriter interface {
+	// InsertStepLogs stores log lines emitted by the SDK during a step.
+	InsertStepLogs(ctx context.Context, logs []StepLog) error
+}
+
+type StepLogReader interface {
+	// GetStepLogs returns log lines for the given function run, ordered from
+	// oldest to newest.  If stepID is non-nil, only logs for the given step are
+	// returned.
+	GetStepLogs(ctx context.Context, runID ulid.ULID, stepID *string) ([]*StepLog, error)
+}
+
+// StepLog is a structured log line emitted by the SDK during a step.
+type StepLog struct {
+	RunID     ulid.ULID              `json:"runID"`
+	StepID    string                 `json:"stepID"`
+	Attempt   int                    `json:"attempt"`
+	Level     string                 `json:"level"`
+	Message   string                 `json:"message"`
+	Fields    map[string]interface{} `json:"fields,omitempty"`
+	Timestamp time.Time              `json:"timestamp"`
+}
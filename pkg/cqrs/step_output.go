@@ -0,0 +1,46 @@
+package cqrs
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/oklog/ulid/v2"
+)
+
+// Sources of a StepOutput.
+const (
+	StepOutputSourceState   = "state"
+	StepOutputSourceHistory = "history"
+)
+
+// ErrStepOutputNotFound is returned when a step has no stored output, eg. when
+// the step hasn't finished.
+var ErrStepOutputNotFound = fmt.Errorf("step output not found")
+
+// StepOutputReader loads a step's stored output from a backing store.
+type StepOutputReader interface {
+	// GetStepOutput returns the output or error of the step with the given ID,
+	// or ErrStepOutputNotFound if the step has no stored output.
+	GetStepOutput(
+		ctx context.Context,
+		accountID uuid.UUID,
+		workspaceID uuid.UUID,
+		runID ulid.ULID,
+		stepID string,
+	) (*StepOutput, error)
+}
+
+// StepOutput represents a step's stored output, or its error if the step
+// failed.
+type StepOutput struct {
+	RunID  ulid.ULID
+	StepID string
+	// Source is the storage that the output was loaded from, eg.
+	// StepOutputSourceState.
+	Source string
+	// Output is the step's marshalled output, if the step succeeded.
+	Output []byte
+	// Error is the step's error, if the step failed.
+	Error []byte
+}
@@ -80,6 +80,9 @@ type TraceWriter interface {
 type TraceReader interface {
 	// GetSpansByTraceIDAndRunID retrieves spans based on their traceID and runID
 	GetSpansByTraceIDAndRunID(ctx context.Context, tid string, runID ulid.ULID) ([]*Span, error)
+	// GetSpansByRunID retrieves every span within the traces containing the given
+	// run, ordered by start time.
+	GetSpansByRunID(ctx context.Context, runID ulid.ULID) ([]*Span, error)
 	// GetTraceRuns retrieves a list of TraceRun based on the options specified
 	GetTraceRuns(ctx context.Context, opt GetTraceRunOpt) ([]*TraceRun, error)
 }
@@ -5,6 +5,16 @@ package config
 	log: {
 		level:  ("trace" | "debug" | "info" | "warn" | "error") | *"info"
 		format: "json" | "console" | *"json"
+
+		// dedup suppresses repeated logs with the same level, message and
+		// function, eg. warnings emitted for every pause or retry.  levels maps
+		// each level to the number of repeated logs written within each window
+		// of seconds;  further logs are suppressed and summarized once the
+		// window ends.  Levels without a limit are never suppressed.
+		dedup: {
+			window: int & >0 | *10
+			levels: {[("trace" | "debug" | "info" | "warn" | "error")]: int & >0} | *{}
+		}
 	}
 
 	// EventAPI is used to configure the API for listening to events.
@@ -19,12 +29,40 @@ package config
 		// NOTE: Some event stream implementations have their own limits
 		// (eg. SQS is 256kb).
 		maxSize: >=1024 | *(512 * 1024)
+
+		// maxBulkEvents is the maximum number of newline-delimited events
+		// accepted in a single request to the bulk endpoint, /e/{key}/bulk.
+		// Events past this limit are rejected.
+		maxBulkEvents: >0 | *10000
+
+		// grpcPort serves the gRPC EventService on the given port, for
+		// producers which send many events and benefit from connection reuse.
+		// Disabled if unset.
+		grpcPort?: >0 & <=65535
+
+		// rateLimit limits requests sent to the event API for each event key
+		// and for each IP address.  Requests over the limit are rejected with
+		// an HTTP 429 and a Retry-After header.  Limits are disabled if unset.
+		rateLimit?: {
+			key?: #EventAPIRateLimit
+			ip?:  #EventAPIRateLimit
+		}
+
+		// dropUnmatchedEvents drops events which don't trigger any function,
+		// batch, or waiting run, instead of storing them.  This reduces storage
+		// for installs which ingest high-volume streams of events.  Dropped
+		// events aren't visible in the UI or API.
+		dropUnmatchedEvents: bool | *false
 	}
 
 	// CoreAPI is used to configure the API for manging the system
 	coreAPI: {
 		addr: string | *"0.0.0.0"
 		port: >0 & <=65535 | *8300
+		// requireKeys requires REST and GraphQL requests to be authenticated
+		// with an event key granted the request's scope, once any event key
+		// exists.
+		requireKeys: bool | *false
 	}
 
 	execution: {
@@ -48,6 +86,127 @@ package config
 		// result in large logs and sensitive data being printed
 		// to stderr, and is only intended for development.
 		logOutput: bool | *false
+
+		// drainTimeout is the number of seconds in-progress steps have to finish
+		// when the executor shuts down.  Steps which don't finish in time are
+		// released back to the queue for another executor to run.
+		drainTimeout: int | *25
+
+		// idempotencyPeriod is how long a run's triggering event or batch ID
+		// prevents duplicate runs once the run finishes, eg. "48h".  Functions
+		// may override this.  Defaults to 24 hours.
+		idempotencyPeriod?: string
+
+		// cancelTimeout is how long cancellations last, eg. "30d", or "never"
+		// for cancellations which never expire.  Cancellations and functions may
+		// override this.  Defaults to 1 year.
+		cancelTimeout?: string
+
+		// finishEvents buffers the events sent when runs finish, eg.
+		// "inngest/function.finished", so that events from many runs are
+		// published together.  Events are published once flushSize events are
+		// buffered, or after flushInterval milliseconds.
+		finishEvents?: {
+			flushSize:     int | *0
+			flushInterval: int | *50
+		}
+
+		// lifecycleEvents sends "inngest/run.started", "inngest/run.cancelled"
+		// and "inngest/step.failed" events as runs change state, so that
+		// functions can be triggered by them.  Defaults to false.
+		lifecycleEvents: bool | *false
+
+		// traceSampleRatio is the ratio of runs, between 0 and 1, whose traces
+		// are exported.  Spans which record errors are always exported, so that
+		// failures remain visible.  Functions may override this.  Defaults to
+		// exporting every trace.
+		traceSampleRatio?: number & >=0 & <=1
+
+		// aggregator bounds the in-memory trees used to match events against
+		// pause expressions.  maxTrees is the number of workspace event trees
+		// held in memory, evicting the least recently used.  Trees with more
+		// than maxTreeSize expressions are evicted, and their pauses are
+		// evaluated individually;  0 disables the limit.
+		aggregator?: {
+			maxTrees:    int | *100
+			maxTreeSize: int | *0
+		}
+
+		// pauseExpressions bounds the evaluation of each pause's expression when
+		// matching events against pauses, so that pathological expressions can't
+		// stall event handling.  Pauses whose expressions take longer than
+		// timeout milliseconds, or exceed the CEL runtime costLimit, are skipped.
+		pauseExpressions?: {
+			timeout:   int & >0 | *100
+			costLimit: int & >0 | *1000000
+		}
+
+		// batches limits the batches held for functions which batch events, so
+		// that functions can't exhaust redis with pending batches.  Each function
+		// may hold at most maxPending batches which haven't yet started, where 0
+		// is unlimited.  Further events either start runs immediately, in batches
+		// of their own, or are rejected with an error, as per onLimit.
+		batches?: {
+			maxPending: int & >=0 | *0
+			onLimit:    *"run" | "reject"
+		}
+
+		// concurrencyKeyCardinality is the number of distinct keys that each of a
+		// function's custom concurrency limits may use within an hour before the
+		// function is logged and counted as generating unbounded keys, eg. as its
+		// keys use unique IDs.  0 disables the limit, though the number of keys
+		// is still recorded as a metric.
+		concurrencyKeyCardinality: int & >=0 | *0
+
+		// queueCompression is the encoded size, in bytes, at which the payloads
+		// of queue items are compressed to reduce memory usage.  0 disables
+		// compression.
+		queueCompression: int & >=0 | *0
+
+		// appConcurrency limits the steps running concurrently across every
+		// function within each app, so that one app's functions can't exceed a
+		// bound together.  limit applies to every app, and apps overrides the
+		// limit for individual apps by app name.  0 disables the limit.
+		appConcurrency?: {
+			limit: int & >=0 | *0
+			apps?: [string]: int & >=0
+		}
+
+		// redact lists JSON paths, eg. "data.user.email", which are scrubbed from
+		// event payloads and step outputs before they're saved to state or
+		// recorded in traces.  A "*" segment matches every key or array item.
+		redact?: {
+			events?: [...string]
+			outputs?: [...string]
+		}
+
+		// ai configures the model providers called by the executor when functions
+		// make inference requests.  The openai and anthropic providers are
+		// available by default, reading API keys from the OPENAI_API_KEY and
+		// ANTHROPIC_API_KEY env vars.  timeout is the number of seconds an
+		// inference request may take, defaulting to 10 minutes.
+		ai?: {
+			providers?: [string]: {
+				url?:       string
+				apiKey?:    string
+				apiKeyEnv?: string
+			}
+			timeout?: int
+		}
+
+		// gateway configures the HTTP requests made by the executor on behalf of
+		// functions.  allowedHosts lists the hosts that may be requested, where a
+		// "*." prefix matches every subdomain;  if empty, every host is allowed.
+		// Private, loopback and link-local addresses are denied unless
+		// allowPrivateNetworks is set.  Responses larger than maxResponseSize bytes fail, defaulting to 4MB.
+		// timeout is the number of seconds a request may take, defaulting to
+		// 5 minutes.
+		gateway?: {
+			allowedHosts?:         [...string]
+			allowPrivateNetworks?: bool
+			maxResponseSize?:      int
+			timeout?:              int
+		}
 	}
 
 	// eventstream is used to configure the event stream pub/sub implementation.  This
@@ -73,6 +232,18 @@ package config
 		service: #DataStoreService | *{backend: "inmemory"}
 		// This struct is retained for any shared settings
 	}
+	// redis configures the keys stored within Redis.  namespace prefixes every
+	// key, including state, queue, and rate limit keys, so that many installs or
+	// environments can safely share a Redis instance or cluster.
+	redis: {
+		namespace: string | *""
+	}
+}
+
+// EventAPIRateLimit allows up to limit requests every period, eg. "1s" or "1m".
+#EventAPIRateLimit: {
+	limit:  >0
+	period: string | *"1s"
 }
 
 // @TODO: Add custom redis driver, add Kafka.
@@ -150,6 +321,15 @@ package config
 	maxRetries?: >=-1 | *3
 	poolSize?:   >=1
 
+	// addrs lists the endpoints used to discover a Redis Cluster, used instead
+	// of host and port.
+	addrs?: [...string]
+	// cluster enables Redis Cluster support.  The keyPrefix must then contain a
+	// hash tag, eg. "{queue}", so that every queue key lives within one slot.
+	cluster: bool | *false
+	// sentinel discovers the primary via Sentinel, following it on failover.
+	sentinel?: #RedisSentinel
+
 	// keyPrefix is the prefix used for all redis keys stored
 	keyPrefix: string | *""
 }
@@ -182,8 +362,53 @@ package config
 	maxRetries?: >=-1 | *3
 	poolSize?:   >=1
 
+	// addrs lists the endpoints used to discover a Redis Cluster, used instead
+	// of host and port.
+	addrs?: [...string]
+	// cluster enables Redis Cluster support.  Run state is hash tagged by run
+	// ID, so that runs are distributed across the cluster's shards.
+	cluster: bool | *false
+	// sentinel discovers the primary via Sentinel, following it on failover.
+	sentinel?: #RedisSentinel
+
 	// keyPrefix is the prefix used for all redis keys stored
 	keyPrefix: string | *"inngest:state"
+
+	// pauseShards lists the Redis instances used to store pauses, sharded by
+	// workspace and event name.  At least one shard must be listed if set;  if
+	// unset, pauses are stored alongside run state.
+	pauseShards?: [#RedisConnection, ...#RedisConnection]
+
+	// replicas lists the read replicas used when loading run state.  Only
+	// connection fields are used.
+	replicas?: [...#RedisReplica]
+	// replicaLag is the age, in milliseconds, of runs which are read from the
+	// primary rather than replicas.
+	replicaLag?: int & >0 | *5000
+}
+
+// RedisSentinel configures the Sentinel deployment monitoring the Redis primary.
+// Sentinel cannot be used alongside cluster mode.
+#RedisSentinel: {
+	// masterSet is the name of the primary monitored by Sentinel.
+	masterSet: string & !=""
+	// addrs lists the Sentinel endpoints.
+	addrs: [string, ...string]
+	username?: string
+	password?: string
+}
+
+// RedisReplica is a read replica of the Redis state store.
+#RedisReplica: #RedisConnection
+
+// RedisConnection configures the connection to a single Redis instance.
+#RedisConnection: {
+	dsn?:      string
+	host:      string | *"localhost"
+	port:      >0 & <=65535 | *6379
+	db:        >=0 | *0
+	username?: string
+	password?: string
 }
 
 // # DataStore
@@ -217,4 +442,40 @@ package config
 	name:        "http"
 	timeout?:    int | *7200 // 2 hours
 	signingKey?: string
+	// previousSigningKey is the signing key being rotated out, accepted when
+	// verifying responses and syncs.
+	previousSigningKey?: string
+	// signatureTolerance is the maximum age, in seconds, of signed responses
+	// and syncs before they're rejected.
+	signatureTolerance?: int & >0 | *300
+	// tls configures mutual TLS for requests to every app.
+	tls?: #TLS
+	// proxy configures the proxy used for requests to every app.
+	proxy?: #Proxy
+	// apps configures requests to specific apps, keyed by the host of the
+	// app's URL, eg. "payments.internal:8443".  App configuration overrides
+	// the global configuration.
+	apps?: [host=string]: {
+		tls?:   #TLS
+		proxy?: #Proxy
+	}
+}
+
+// TLS configures mutual TLS for requests to apps.
+#TLS: {
+	// certFile and keyFile are paths to the PEM encoded client certificate
+	// and key presented to apps.
+	certFile?: string
+	keyFile?:  string
+	// caFile is a path to a PEM encoded CA bundle used to verify apps.
+	caFile?: string
+}
+
+// Proxy configures an outbound HTTP(S) proxy.
+#Proxy: {
+	httpProxy?:  string
+	httpsProxy?: string
+	// noProxy lists hosts which are requested directly, in the same format
+	// as the NO_PROXY environment variable.
+	noProxy?: string
 }
@@ -22,8 +22,14 @@ package v1
 		count:  uint & >=1 | *1
 		period: string
 	}
+	// throttleScope shares the throttle between functions within the environment
+	// or account.  By default each function is throttled independently.
+	throttleScope?: "fn" | "env" | "account"
 
 	cancel?: [...#Cancel]
+	// cancelTimeout is the timeout of cancellations which don't specify their
+	// own timeout, eg. "30d", or "never" for cancellations which never expire.
+	cancelTimeout?: string
 }
 
 #EventTrigger: {
@@ -124,7 +130,7 @@ package v1
 	// event is the event name that will cancel this function
 	event: string
 	// timeout is the time at which the function can be cancelled, defaulting
-	// to the max runtime length.
+	// to the max runtime length, or "never" for cancellations which never expire.
 	timeout?: string
 	// if is an optional expression to match when cancelling the function.
 	if?: string
@@ -1,6 +1,7 @@
 package devserver
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
 	_ "embed"
@@ -17,6 +18,9 @@ import (
 	"github.com/inngest/inngest/pkg/api/tel"
 	"github.com/inngest/inngest/pkg/consts"
 	"github.com/inngest/inngest/pkg/cqrs"
+	"github.com/inngest/inngest/pkg/devserver/environment"
+	"github.com/inngest/inngest/pkg/execution/driver/httpdriver"
+	"github.com/inngest/inngest/pkg/execution/queue"
 	"github.com/inngest/inngest/pkg/headers"
 	"github.com/inngest/inngest/pkg/inngest"
 	"github.com/inngest/inngest/pkg/inngest/log"
@@ -25,6 +29,7 @@ import (
 	"github.com/inngest/inngest/pkg/publicerr"
 	"github.com/inngest/inngest/pkg/sdk"
 	"github.com/oklog/ulid/v2"
+	"github.com/xhit/go-str2duration/v2"
 	ptrace "go.opentelemetry.io/collector/pdata/ptrace"
 )
 
@@ -58,12 +63,48 @@ func (a *devapi) addRoutes() {
 		return http.HandlerFunc(fn)
 	})
 	a.Use(headers.StaticHeadersMiddleware(headers.ServerKindDev))
+	// Requests act within the environment selected by the environment header.
+	a.Use(environment.Middleware)
 
 	a.Get("/dev", a.Info)
+	// Queue backlogs, recent run counts and state store health, eg. for inngest status.
+	a.Get("/dev/status", a.GetStatus)
+	// Liveness and readiness checks for orchestration platforms.  These return a
+	// 503 with each component's status when unhealthy.
+	a.Get("/healthz", a.GetHealth)
+	a.Get("/readyz", a.GetReadiness)
 	a.Post("/dev/traces", a.OTLPTrace)
+	// Export a run's trace as OTLP JSON, for importing into Jaeger or Grafana.
+	a.Get("/dev/runs/{runID}/trace", a.ExportTrace)
 	a.Post("/fn/register", a.Register)
 	// This allows tests to remove apps by URL
 	a.Delete("/fn/remove", a.RemoveApp)
+	// Invoke a function directly, optionally waiting for its output.
+	a.Post("/fn/{slug}/invoke", a.Invoke)
+	// Snapshots allow tests and bug reports to save and restore all dev server state.
+	a.Get("/dev/snapshot", a.GetSnapshot)
+	a.Post("/dev/snapshot", a.RestoreSnapshot)
+	// Export and import app and function configuration, eg. GET /dev/config?format=yaml
+	a.Get("/dev/config", a.ExportConfig)
+	a.Post("/dev/config", a.ImportConfig)
+	// Fast-forward sleeps and waitForEvent timeouts, eg. POST /dev/clock/advance?by=2h
+	a.Post("/dev/clock/advance", a.AdvanceClock)
+	// Tail accepted events over a websocket, eg. /dev/events/feed?prefix=app/
+	a.Get("/dev/events/feed", a.EventFeed)
+	// Halt runs before steps and continue them, eg. PUT {"steps": ["*"]} to step
+	// through each step of a run.
+	a.Get("/dev/runs/{runID}/breakpoints", a.GetRunBreakpoints)
+	a.Put("/dev/runs/{runID}/breakpoints", a.SetRunBreakpoints)
+	a.Post("/dev/runs/{runID}/continue", a.ContinueRun)
+	a.Get("/dev/functions/{functionID}/breakpoints", a.GetFunctionBreakpoints)
+	a.Put("/dev/functions/{functionID}/breakpoints", a.SetFunctionBreakpoints)
+	// Inspect recorded SDK requests and replay runs against them, when
+	// started with --record.
+	a.Get("/dev/runs/{runID}/recording", a.GetRecording)
+	a.Post("/dev/runs/{runID}/replay", a.ReplayRun)
+	// Tail a run's lifecycle events, step outputs and errors, eg. for inngest logs.
+	a.Get("/dev/runs/{runID}/timeline", a.GetTimeline)
+	a.Get("/dev/runs/{runID}/timeline/feed", a.TimelineFeed)
 
 	// Go embeds files relative to the current source, which embeds
 	// all under ./static.  We remove the ./static
@@ -112,12 +153,12 @@ func (a devapi) Info(w http.ResponseWriter, r *http.Request) {
 	a.devserver.handlerLock.Lock()
 	defer a.devserver.handlerLock.Unlock()
 
-	all, _ := a.devserver.data.GetFunctions(r.Context())
-	funcs := make([]inngest.Function, len(all))
-	for n, i := range all {
+	all, _ := a.devserver.data.GetFunctions(r.Context(), environment.WorkspaceIDFromContext(r.Context()))
+	funcs := []inngest.Function{}
+	for _, i := range all {
 		f := inngest.Function{}
 		_ = json.Unmarshal([]byte(i.Config), &f)
-		funcs[n] = f
+		funcs = append(funcs, f)
 	}
 
 	ir := InfoResponse{
@@ -145,7 +186,28 @@ func (a devapi) Register(w http.ResponseWriter, r *http.Request) {
 	a.devserver.handlerLock.Lock()
 	defer a.devserver.handlerLock.Unlock()
 
-	req, err := sdk.FromReadCloser(r.Body, sdk.FromReadCloserOpts{})
+	byt, err := io.ReadAll(r.Body)
+	if err != nil {
+		a.err(ctx, w, 400, fmt.Errorf("Invalid request: %w", err))
+		return
+	}
+
+	// Verify syncs against the current and previous signing keys, so that apps
+	// can sync using either key while keys are rotated.  Syncs must be signed
+	// once a signing key is set.
+	if verifier := a.devserver.opts.signatureVerifier(); len(verifier.Keys) > 0 {
+		sig := r.Header.Get(headers.HeaderKeySignature)
+		if sig == "" {
+			a.err(ctx, w, 401, fmt.Errorf("Invalid sync signature: %w", httpdriver.ErrMissingSignature))
+			return
+		}
+		if err := verifier.Verify(ctx, sig, r.Header, byt); err != nil {
+			a.err(ctx, w, 401, fmt.Errorf("Invalid sync signature: %w", err))
+			return
+		}
+	}
+
+	req, err := sdk.FromReadCloser(io.NopCloser(bytes.NewReader(byt)), sdk.FromReadCloserOpts{})
 	if err != nil {
 		logger.From(ctx).Warn().Msgf("Invalid request:\n%s", err)
 		a.err(ctx, w, 400, fmt.Errorf("Invalid request: %w", err))
@@ -174,7 +236,10 @@ func (a devapi) register(ctx context.Context, r sdk.RegisterRequest) (err error)
 		return publicerr.Wrap(err, 400, "Invalid request")
 	}
 
-	if app, err := a.devserver.data.GetAppByChecksum(ctx, sum); err == nil {
+	// Apps are registered within the environment selected by the request.
+	wsID := environment.WorkspaceIDFromContext(ctx)
+
+	if app, err := a.devserver.data.GetAppByChecksum(ctx, wsID, sum); err == nil {
 		if !app.Error.Valid {
 			// Skip registration since the app was already successfully
 			// registered.
@@ -199,15 +264,18 @@ func (a devapi) register(ctx context.Context, r sdk.RegisterRequest) (err error)
 	//
 	// We need to do this as we always create an app when entering the URL
 	// via the UI.  This is a dev-server specific quirk.
-	app, err := a.devserver.data.GetAppByURL(ctx, r.URL)
+	app, err := a.devserver.data.GetAppByURL(ctx, wsID, r.URL)
 	if err == nil && app != nil {
+		// The app re-synced, so it's no longer draining.
+		a.devserver.drains.remove(app.ID)
 		_ = a.devserver.data.DeleteApp(ctx, app.ID)
 	}
 
 	// We need a UUID to register functions with.
 	appParams := cqrs.InsertAppParams{
 		// Use a deterministic ID for the app in dev.
-		ID:          uuid.NewSHA1(uuid.NameSpaceOID, []byte(r.URL)),
+		ID:          environment.ScopedID(wsID, uuid.NewSHA1(uuid.NameSpaceOID, []byte(r.URL))),
+		WorkspaceID: wsID,
 		Name:        r.AppName,
 		SdkLanguage: r.SDKLanguage(),
 		SdkVersion:  r.SDKVersion(),
@@ -236,15 +304,17 @@ func (a devapi) register(ctx context.Context, r sdk.RegisterRequest) (err error)
 		err = tx.Commit(ctx)
 		if err != nil {
 			logger.From(ctx).Error().Err(err).Msg("error registering functions")
+			return
+		}
+		if !appParams.Error.Valid {
+			// Steps for runs which already started continue to use any
+			// previous URL for the app during the drain period.
+			if err := a.devserver.drainPreviousApps(ctx, appParams.Name, appParams.Url); err != nil {
+				logger.From(ctx).Error().Err(err).Msg("error draining previous app")
+			}
 		}
 	}()
 
-	// Get a list of all functions
-	existing, _ := tx.GetFunctionsByAppInternalID(ctx, uuid.UUID{}, appParams.ID)
-	// And get a list of functions that we've upserted.  We'll delete all existing functions not in
-	// this set.
-	seen := map[uuid.UUID]struct{}{}
-
 	// XXX (tonyhb): If we're authenticated, we can match the signing key against the workspace's
 	// signing key and warn if the user has an invalid key.
 	funcs, err := r.Parse(ctx)
@@ -252,42 +322,57 @@ func (a devapi) register(ctx context.Context, r sdk.RegisterRequest) (err error)
 		return publicerr.Wrap(err, 400, "At least one function is invalid")
 	}
 
+	if err = upsertFunctions(ctx, tx, wsID, appParams.ID, funcs); err != nil {
+		return publicerr.Wrap(err, 500, "Error saving functions")
+	}
+	return nil
+}
+
+// upsertFunctions creates or updates each of the app's given functions within the
+// transaction, deleting any of the app's existing functions which weren't given.
+func upsertFunctions(ctx context.Context, tx cqrs.TxManager, wsID, appID uuid.UUID, funcs []*inngest.Function) error {
+	// Get a list of all functions
+	existing, _ := tx.GetFunctionsByAppInternalID(ctx, wsID, appID)
+	// And get a list of functions that we've upserted.  We'll delete all existing functions not in
+	// this set.
+	seen := map[uuid.UUID]struct{}{}
+
 	// For each function,
 	for _, fn := range funcs {
-		// Create a new UUID for the function.
-		fn.ID = inngest.DeterministicUUID(*fn)
+		// Create a new UUID for the function, unique to the environment.
+		fn.ID = environment.ScopedID(wsID, inngest.DeterministicUUID(*fn))
 
 		// Mark as seen.
 		seen[fn.ID] = struct{}{}
 
 		config, err := json.Marshal(fn)
 		if err != nil {
-			return publicerr.Wrap(err, 500, "Error marshalling function")
+			return fmt.Errorf("error marshalling function %s: %w", fn.Slug, err)
 		}
 
-		if _, err := tx.GetFunctionByInternalUUID(ctx, uuid.UUID{}, fn.ID); err == nil {
+		if _, err := tx.GetFunctionByInternalUUID(ctx, wsID, fn.ID); err == nil {
 			// Update the function config.
 			_, err = tx.UpdateFunctionConfig(ctx, cqrs.UpdateFunctionConfigParams{
 				ID:     fn.ID,
 				Config: string(config),
 			})
 			if err != nil {
-				return publicerr.Wrap(err, 500, "Error updating function config")
+				return fmt.Errorf("error updating function %s: %w", fn.Slug, err)
 			}
 			continue
 		}
 
 		_, err = tx.InsertFunction(ctx, cqrs.InsertFunctionParams{
-			ID:        fn.ID,
-			Name:      fn.Name,
-			Slug:      fn.Slug,
-			AppID:     appParams.ID,
-			Config:    string(config),
-			CreatedAt: time.Now(),
+			ID:          fn.ID,
+			WorkspaceID: wsID,
+			Name:        fn.Name,
+			Slug:        fn.Slug,
+			AppID:       appID,
+			Config:      string(config),
+			CreatedAt:   time.Now(),
 		})
 		if err != nil {
-			err = fmt.Errorf("Function %s is invalid: %w", fn.Slug, err)
-			return publicerr.Wrap(err, 500, "Error saving function")
+			return fmt.Errorf("Function %s is invalid: %w", fn.Slug, err)
 		}
 	}
 
@@ -302,8 +387,8 @@ func (a devapi) register(ctx context.Context, r sdk.RegisterRequest) (err error)
 		return nil
 	}
 
-	if err = tx.DeleteFunctionsByIDs(ctx, deletes); err != nil {
-		return publicerr.Wrap(err, 500, "Error deleting removed function")
+	if err := tx.DeleteFunctionsByIDs(ctx, deletes); err != nil {
+		return fmt.Errorf("error deleting removed functions: %w", err)
 	}
 	return nil
 }
@@ -473,7 +558,7 @@ func (a devapi) RemoveApp(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	url := r.FormValue("url")
 
-	app, err := a.devserver.data.GetAppByURL(ctx, url)
+	app, err := a.devserver.data.GetAppByURL(ctx, environment.WorkspaceIDFromContext(ctx), url)
 	if err != nil {
 		_ = publicerr.WriteHTTP(w, publicerr.Wrapf(err, 404, "App not found: %s", url))
 		return
@@ -504,3 +589,117 @@ type InfoResponse struct {
 	Functions     []inngest.Function `json:"functions"`
 	Handlers      []SDKHandler       `json:"handlers"`
 }
+
+// GetSnapshot returns a snapshot of all dev server state, which can be restored via
+// RestoreSnapshot or the `--snapshot` flag.
+func (a devapi) GetSnapshot(w http.ResponseWriter, r *http.Request) {
+	snapshot, err := a.devserver.Snapshot(r.Context())
+	if err != nil {
+		_ = publicerr.WriteHTTP(w, publicerr.Wrap(err, 500, "Error creating snapshot"))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="inngest-snapshot.json"`)
+	_ = json.NewEncoder(w).Encode(snapshot)
+}
+
+// RestoreSnapshot replaces all dev server state with the snapshot in the request body.
+func (a devapi) RestoreSnapshot(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	ctx := r.Context()
+
+	snapshot := Snapshot{}
+	if err := json.NewDecoder(r.Body).Decode(&snapshot); err != nil {
+		a.err(ctx, w, 400, fmt.Errorf("Invalid snapshot: %w", err))
+		return
+	}
+	if err := a.devserver.Restore(ctx, snapshot); err != nil {
+		_ = publicerr.WriteHTTP(w, publicerr.Wrap(err, 500, "Error restoring snapshot"))
+		return
+	}
+	_, _ = w.Write([]byte(`{"ok":true}`))
+}
+
+// ExportConfig returns every synced app and its functions' configuration as a
+// JSON or YAML bundle, which can be imported into another dev server.
+func (a devapi) ExportConfig(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = ConfigBundleFormatJSON
+	}
+
+	bundle, err := a.devserver.ExportConfig(r.Context())
+	if err != nil {
+		_ = publicerr.WriteHTTP(w, publicerr.Wrap(err, 500, "Error exporting config"))
+		return
+	}
+	byt, err := MarshalConfigBundle(*bundle, format)
+	if err != nil {
+		_ = publicerr.WriteHTTP(w, publicerr.Wrap(err, 400, "Error exporting config"))
+		return
+	}
+
+	contentType := "application/json; charset=utf-8"
+	if format == ConfigBundleFormatYAML {
+		contentType = "application/yaml; charset=utf-8"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="inngest-config.%s"`, format))
+	_, _ = w.Write(byt)
+}
+
+// ImportConfig creates or replaces the apps and functions within the JSON or
+// YAML bundle in the request body.
+func (a devapi) ImportConfig(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	ctx := r.Context()
+
+	byt, err := io.ReadAll(r.Body)
+	if err != nil {
+		a.err(ctx, w, 400, fmt.Errorf("Error reading config: %w", err))
+		return
+	}
+	bundle, err := UnmarshalConfigBundle(byt)
+	if err != nil {
+		a.err(ctx, w, 400, err)
+		return
+	}
+
+	if err := a.devserver.ImportConfig(ctx, *bundle); err != nil {
+		_ = publicerr.WriteHTTP(w, publicerr.Wrap(err, 400, "Error importing config"))
+		return
+	}
+	_, _ = w.Write([]byte(`{"ok":true}`))
+}
+
+// clockAdvancer is implemented by queues which support fast-forwarding queue items.
+type clockAdvancer interface {
+	AdvanceTime(ctx context.Context, by time.Duration, kinds ...string) (int, error)
+}
+
+// AdvanceClock fast-forwards all scheduled sleeps and waitForEvent timeouts by the
+// duration given in the "by" query parameter, allowing long sleeps to be tested
+// locally.
+func (a devapi) AdvanceClock(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	by, err := str2duration.ParseDuration(r.FormValue("by"))
+	if err != nil || by <= 0 {
+		a.err(ctx, w, 400, fmt.Errorf("Invalid duration: %s", r.FormValue("by")))
+		return
+	}
+
+	q, ok := a.devserver.queue.(clockAdvancer)
+	if !ok {
+		a.err(ctx, w, 400, fmt.Errorf("The queue does not support advancing time"))
+		return
+	}
+
+	n, err := q.AdvanceTime(ctx, by, queue.KindSleep, queue.KindPause)
+	if err != nil {
+		_ = publicerr.WriteHTTP(w, publicerr.Wrap(err, 500, "Error advancing clock"))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_ = json.NewEncoder(w).Encode(map[string]any{"ok": true, "rescheduled": n})
+}
@@ -0,0 +1,49 @@
+package devserver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/inngest/inngest/pkg/execution/driver/httpdriver"
+	"github.com/inngest/inngest/pkg/headers"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterSignatures(t *testing.T) {
+	a := devapi{devserver: &devserver{
+		handlerLock: &sync.Mutex{},
+		opts: StartOpts{
+			SigningKey:         "current",
+			PreviousSigningKey: "previous",
+		},
+	}}
+	body := `{"invalid":`
+
+	do := func(sig string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPut, "/fn/register", strings.NewReader(body))
+		if sig != "" {
+			req.Header.Set(headers.HeaderKeySignature, sig)
+		}
+		rec := httptest.NewRecorder()
+		a.Register(rec, req)
+		return rec
+	}
+
+	// Syncs must be signed once a signing key is set.
+	rec := do("")
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+	require.Contains(t, rec.Body.String(), httpdriver.ErrMissingSignature.Error())
+
+	rec = do(httpdriver.Sign(context.Background(), []byte("unknown"), []byte(body)))
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	// Syncs signed with either key are verified before they're parsed.
+	for _, key := range []string{"current", "previous"} {
+		rec = do(httpdriver.Sign(context.Background(), []byte(key), []byte(body)))
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+	}
+}
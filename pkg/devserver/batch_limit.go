@@ -0,0 +1,79 @@
+package devserver
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/inngest/inngest/pkg/api"
+	"github.com/inngest/inngest/pkg/cqrs"
+	"github.com/inngest/inngest/pkg/devserver/environment"
+	"github.com/inngest/inngest/pkg/event"
+	"github.com/inngest/inngest/pkg/execution/batch"
+	"github.com/inngest/inngest/pkg/execution/executor"
+	"github.com/inngest/inngest/pkg/expressions"
+	"github.com/inngest/inngest/pkg/inngest"
+	"github.com/inngest/inngest/pkg/publicerr"
+)
+
+// checkBatchLimits rejects events which would be batched for a function that
+// already holds its maximum number of pending batches.  This rejects events when
+// they're sent, rather than failing the event once it's handled.
+func checkBatchLimits(data cqrs.ExecutionLoader, batcher batch.OverflowChecker) api.EventChecker {
+	return func(ctx context.Context, evt *event.Event) error {
+		fns, err := data.FunctionsByTrigger(ctx, environment.WorkspaceIDFromContext(ctx), evt.Name)
+		if err != nil {
+			return publicerr.Wrap(err, http.StatusInternalServerError, "Error loading functions")
+		}
+
+		evtMap := evt.Map()
+		for _, fn := range fns {
+			t, ok := triggeredBy(ctx, fn, evt.Name, evtMap)
+			if !ok {
+				continue
+			}
+			// Batching may be configured by the trigger.
+			fn = fn.ForTrigger(t)
+			if !fn.IsBatchEnabled() {
+				continue
+			}
+
+			overflows, err := batcher.Overflows(ctx, fn)
+			if err != nil {
+				return publicerr.Wrap(err, http.StatusInternalServerError, "Error checking batches")
+			}
+			if overflows {
+				return publicerr.Wrapf(
+					executor.ErrBatchLimitExceeded,
+					http.StatusTooManyRequests,
+					"Function %s holds its maximum number of pending batches",
+					fn.GetSlug(),
+				)
+			}
+		}
+		return nil
+	}
+}
+
+// triggeredBy returns the trigger by which the given event triggers the function,
+// as when the runner schedules functions, or false if the event doesn't trigger
+// the function.
+func triggeredBy(ctx context.Context, fn inngest.Function, name string, evt map[string]any) (inngest.Trigger, bool) {
+	for _, t := range fn.Triggers {
+		if t.EventTrigger == nil || t.Event != name {
+			continue
+		}
+		if t.Expression != nil {
+			ok, _, err := expressions.EvaluateBoolean(ctx, *t.Expression, map[string]any{"event": evt})
+			if err != nil || !ok {
+				continue
+			}
+		}
+		if t.Sample != nil {
+			if ok, err := t.Sample.Sampled(ctx, evt); err != nil || !ok {
+				continue
+			}
+		}
+		return t, true
+	}
+	return inngest.Trigger{}, false
+}
@@ -0,0 +1,81 @@
+package devserver
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/inngest/inngest/pkg/cqrs"
+	"github.com/inngest/inngest/pkg/event"
+	"github.com/inngest/inngest/pkg/execution/executor"
+	"github.com/inngest/inngest/pkg/inngest"
+	"github.com/inngest/inngest/pkg/publicerr"
+	"github.com/stretchr/testify/require"
+)
+
+type triggerLoader struct {
+	cqrs.ExecutionLoader
+	fns []inngest.Function
+}
+
+func (l triggerLoader) FunctionsByTrigger(ctx context.Context, wsID uuid.UUID, name string) ([]inngest.Function, error) {
+	return l.fns, nil
+}
+
+type overflowChecker map[uuid.UUID]bool
+
+func (o overflowChecker) Overflows(ctx context.Context, fn inngest.Function) (bool, error) {
+	if !fn.IsBatchEnabled() {
+		return false, errors.New("function isn't batched")
+	}
+	return o[fn.ID], nil
+}
+
+func TestCheckBatchLimits(t *testing.T) {
+	ctx := context.Background()
+	expr := "event.data.batch == true"
+	batched := inngest.Function{
+		ID:         uuid.New(),
+		Slug:       "batched",
+		Triggers:   []inngest.Trigger{{EventTrigger: &inngest.EventTrigger{Event: "test/event", Expression: &expr}}},
+		EventBatch: &inngest.EventBatchConfig{MaxSize: 10, Timeout: "5s"},
+	}
+	unbatched := inngest.Function{
+		ID:       uuid.New(),
+		Slug:     "unbatched",
+		Triggers: []inngest.Trigger{{EventTrigger: &inngest.EventTrigger{Event: "test/event"}}},
+	}
+	// Batching may be configured by the trigger alone.
+	triggerBatched := inngest.Function{
+		ID:   uuid.New(),
+		Slug: "trigger-batched",
+		Triggers: []inngest.Trigger{{EventTrigger: &inngest.EventTrigger{
+			Event:      "test/trigger",
+			EventBatch: &inngest.EventBatchConfig{MaxSize: 10, Timeout: "5s"},
+		}}},
+	}
+	overflows := overflowChecker{batched.ID: true, unbatched.ID: true, triggerBatched.ID: true}
+	check := checkBatchLimits(triggerLoader{fns: []inngest.Function{batched, unbatched, triggerBatched}}, overflows)
+
+	// Events which would be batched for a function holding its maximum number
+	// of pending batches are rejected.
+	err := check(ctx, &event.Event{Name: "test/event", Data: map[string]any{"batch": true}})
+	require.ErrorIs(t, err, executor.ErrBatchLimitExceeded)
+	perr := publicerr.Error{}
+	require.True(t, errors.As(err, &perr))
+	require.Equal(t, http.StatusTooManyRequests, perr.Status)
+
+	// Events which don't trigger the batched function are accepted.
+	require.NoError(t, check(ctx, &event.Event{Name: "test/event", Data: map[string]any{"batch": false}}))
+
+	// As are events once the function can hold another batch.
+	overflows[batched.ID] = false
+	require.NoError(t, check(ctx, &event.Event{Name: "test/event", Data: map[string]any{"batch": true}}))
+
+	err = check(ctx, &event.Event{Name: "test/trigger"})
+	require.ErrorIs(t, err, executor.ErrBatchLimitExceeded)
+	overflows[triggerBatched.ID] = false
+	require.NoError(t, check(ctx, &event.Event{Name: "test/trigger"}))
+}
@@ -0,0 +1,329 @@
+package devserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/inngest/inngest/pkg/cqrs"
+	"github.com/inngest/inngest/pkg/enums"
+	"github.com/inngest/inngest/pkg/execution/executor"
+	"github.com/inngest/inngest/pkg/execution/queue"
+	"github.com/inngest/inngest/pkg/execution/state"
+	"github.com/inngest/inngest/pkg/execution/state/redis_state"
+	"github.com/inngest/inngest/pkg/logger"
+	"github.com/inngest/inngest/pkg/publicerr"
+	"github.com/oklog/ulid/v2"
+)
+
+// breakpointTimeout is the maximum time a step is halted at a breakpoint, after
+// which the step continues automatically.
+const breakpointTimeout = 24 * time.Hour
+
+// breakpointMetadataKey is the queue item metadata key which marks items that
+// were halted at a breakpoint.  The value identifies the halted step attempt.
+const breakpointMetadataKey = "breakpoint"
+
+// breakpointQueue is the queue which holds halted steps.
+type breakpointQueue interface {
+	queue.Producer
+	ScheduledJobs(ctx context.Context, workspaceID, workflowID uuid.UUID, runID *ulid.ULID, limit int64) ([]queue.ScheduledJob, error)
+	RequeueByJobID(ctx context.Context, partitionName string, jobID string, at time.Time) error
+}
+
+// breakpoints halts runs before designated steps so that runs can be debugged
+// from the dev UI.  Breakpoints are set for individual runs, or for functions so
+// that every run of the function halts.
+//
+// Halted steps are held in the queue as items scheduled breakpointTimeout in
+// the future, so that they're kept across restarts and snapshots.  Continuing a
+// step requeues its item to run immediately.  Halted runs have the
+// RunStatusHalted status until their next step runs.
+type breakpoints struct {
+	lock sync.Mutex
+	q    breakpointQueue
+	sm   state.Manager
+	cqrs cqrs.FunctionRunWriter
+	// steps maps run and function IDs to the step IDs which halt.
+	steps map[string]map[string]struct{}
+}
+
+// haltedStep is a step held at a breakpoint.
+type haltedStep struct {
+	StepID   string    `json:"step_id"`
+	Attempt  int       `json:"attempt"`
+	HaltedAt time.Time `json:"halted_at"`
+	// jobID and partition identify the queue item holding the step.
+	jobID     string
+	partition string
+}
+
+func newBreakpoints(q breakpointQueue, sm state.Manager, w cqrs.FunctionRunWriter) *breakpoints {
+	return &breakpoints{
+		q:     q,
+		sm:    sm,
+		cqrs:  w,
+		steps: map[string]map[string]struct{}{},
+	}
+}
+
+var _ executor.Breakpoints = &breakpoints{}
+
+// breakpointValue identifies a single attempt of a step.  This is stored in the
+// metadata of halted items, which are kept when the item is continued, such that
+// continued steps never halt again.  Retries have a new attempt and halt again.
+func breakpointValue(item queue.Item, stepID string) string {
+	return fmt.Sprintf("%d:%s", item.Attempt, stepID)
+}
+
+// breakpointJobID returns the job ID of the item holding a halted step.
+func breakpointJobID(runID ulid.ULID, value string) string {
+	return fmt.Sprintf("%s:breakpoint:%s", runID, value)
+}
+
+func (b *breakpoints) Halt(ctx context.Context, item queue.Item, stepID string) bool {
+	value := breakpointValue(item, stepID)
+	if item.Metadata[breakpointMetadataKey] == value {
+		// The step was halted and has been continued.
+		return false
+	}
+
+	b.lock.Lock()
+	matches := b.matches(item.Identifier.RunID.String(), stepID) || b.matches(item.Identifier.WorkflowID.String(), stepID)
+	b.lock.Unlock()
+	if !matches {
+		return false
+	}
+
+	// The item being processed is dequeued once halted, so a copy is enqueued to
+	// hold the step.
+	now := time.Now()
+	runID := item.Identifier.RunID
+	jobID := breakpointJobID(runID, value)
+	halted := item
+	halted.JobID = &jobID
+	halted.Metadata = map[string]string{
+		breakpointMetadataKey: value,
+		"halted_at":           strconv.FormatInt(now.UnixMilli(), 10),
+	}
+	for k, v := range item.Metadata {
+		if _, ok := halted.Metadata[k]; !ok {
+			halted.Metadata[k] = v
+		}
+	}
+	err := b.q.Enqueue(ctx, halted, now.Add(breakpointTimeout))
+	if err != nil && err != redis_state.ErrQueueItemExists {
+		// Run the step rather than dropping it.
+		logger.StdlibLogger(ctx).Error("error halting step", "error", err, "run_id", runID, "step_id", stepID)
+		return false
+	}
+
+	ok, err := state.TransitionStatus(
+		ctx,
+		b.sm,
+		item.Identifier,
+		enums.RunStatusHalted,
+		enums.RunStatusScheduled,
+		enums.RunStatusRunning,
+		enums.RunStatusWaitingForEvent,
+		enums.RunStatusSleeping,
+	)
+	if err != nil {
+		logger.StdlibLogger(ctx).Error("error setting halted status", "error", err, "run_id", runID)
+	}
+	if ok {
+		if err := b.cqrs.UpdateFunctionRunStatus(ctx, runID, enums.RunStatusHalted); err != nil {
+			logger.StdlibLogger(ctx).Error("error updating run status", "error", err, "run_id", runID)
+		}
+	}
+	return true
+}
+
+func (b *breakpoints) matches(id, stepID string) bool {
+	steps := b.steps[id]
+	if _, ok := steps[executor.BreakpointAll]; ok {
+		return true
+	}
+	_, ok := steps[stepID]
+	return ok
+}
+
+// Set replaces the breakpoints for the given run or function ID.  An empty list
+// of steps removes all breakpoints.
+func (b *breakpoints) Set(id string, stepIDs []string) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if len(stepIDs) == 0 {
+		delete(b.steps, id)
+		return
+	}
+	steps := map[string]struct{}{}
+	for _, s := range stepIDs {
+		steps[s] = struct{}{}
+	}
+	b.steps[id] = steps
+}
+
+// Get returns the sorted breakpoints for the given run or function ID.
+func (b *breakpoints) Get(id string) []string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	steps := []string{}
+	for s := range b.steps[id] {
+		steps = append(steps, s)
+	}
+	sort.Strings(steps)
+	return steps
+}
+
+// Halted returns the steps held at breakpoints for the given run, read from the
+// queue.
+func (b *breakpoints) Halted(ctx context.Context, runID ulid.ULID) ([]haltedStep, error) {
+	md, err := b.sm.Metadata(ctx, runID)
+	if err != nil {
+		return nil, err
+	}
+	id := md.Identifier
+	jobs, err := b.q.ScheduledJobs(ctx, id.WorkspaceID, id.WorkflowID, &runID, 100)
+	if err != nil {
+		return nil, err
+	}
+
+	halted := []haltedStep{}
+	for _, j := range jobs {
+		value, ok := j.Metadata[breakpointMetadataKey]
+		if !ok {
+			continue
+		}
+		_, stepID, _ := strings.Cut(value, ":")
+		at, _ := strconv.ParseInt(j.Metadata["halted_at"], 10, 64)
+		halted = append(halted, haltedStep{
+			StepID:    stepID,
+			Attempt:   j.Attempt,
+			HaltedAt:  time.UnixMilli(at),
+			jobID:     breakpointJobID(runID, value),
+			partition: j.FunctionID.String(),
+		})
+	}
+	return halted, nil
+}
+
+// Continue requeues the run's halted steps to run immediately, or only the given
+// step if stepID is not empty, returning the number of steps continued.
+func (b *breakpoints) Continue(ctx context.Context, runID ulid.ULID, stepID string) (int, error) {
+	halted, err := b.Halted(ctx, runID)
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, h := range halted {
+		if stepID != "" && h.StepID != stepID {
+			continue
+		}
+		if err := b.q.RequeueByJobID(ctx, h.partition, h.jobID, time.Now()); err != nil {
+			logger.StdlibLogger(ctx).Error("error continuing halted step", "error", err, "run_id", runID, "step_id", h.StepID)
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
+
+type breakpointsRequest struct {
+	// Steps lists the step IDs to halt before, or "*" to halt before every step.
+	Steps []string `json:"steps"`
+}
+
+type breakpointsResponse struct {
+	Steps  []string     `json:"steps"`
+	Halted []haltedStep `json:"halted,omitempty"`
+}
+
+// GetRunBreakpoints returns a run's breakpoints and the steps halted at them.
+func (a devapi) GetRunBreakpoints(w http.ResponseWriter, r *http.Request) {
+	runID, err := ulid.Parse(chi.URLParam(r, "runID"))
+	if err != nil {
+		_ = publicerr.WriteHTTP(w, publicerr.Wrap(err, 400, "Invalid run ID"))
+		return
+	}
+	bp := a.devserver.breakpoints
+	halted, err := bp.Halted(r.Context(), runID)
+	if err != nil {
+		_ = publicerr.WriteHTTP(w, publicerr.Wrap(err, 500, "Error loading halted steps"))
+		return
+	}
+	writeBreakpoints(w, breakpointsResponse{Steps: bp.Get(runID.String()), Halted: halted})
+}
+
+// SetRunBreakpoints replaces the breakpoints for a single run.
+func (a devapi) SetRunBreakpoints(w http.ResponseWriter, r *http.Request) {
+	runID, err := ulid.Parse(chi.URLParam(r, "runID"))
+	if err != nil {
+		_ = publicerr.WriteHTTP(w, publicerr.Wrap(err, 400, "Invalid run ID"))
+		return
+	}
+	a.setBreakpoints(w, r, runID.String())
+}
+
+// GetFunctionBreakpoints returns the breakpoints for every run of a function.
+func (a devapi) GetFunctionBreakpoints(w http.ResponseWriter, r *http.Request) {
+	fnID, err := uuid.Parse(chi.URLParam(r, "functionID"))
+	if err != nil {
+		_ = publicerr.WriteHTTP(w, publicerr.Wrap(err, 400, "Invalid function ID"))
+		return
+	}
+	writeBreakpoints(w, breakpointsResponse{Steps: a.devserver.breakpoints.Get(fnID.String())})
+}
+
+// SetFunctionBreakpoints replaces the breakpoints for every run of a function.
+func (a devapi) SetFunctionBreakpoints(w http.ResponseWriter, r *http.Request) {
+	fnID, err := uuid.Parse(chi.URLParam(r, "functionID"))
+	if err != nil {
+		_ = publicerr.WriteHTTP(w, publicerr.Wrap(err, 400, "Invalid function ID"))
+		return
+	}
+	a.setBreakpoints(w, r, fnID.String())
+}
+
+func (a devapi) setBreakpoints(w http.ResponseWriter, r *http.Request, id string) {
+	req := breakpointsRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		_ = publicerr.WriteHTTP(w, publicerr.Wrap(err, 400, "Invalid breakpoints"))
+		return
+	}
+	a.devserver.breakpoints.Set(id, req.Steps)
+	writeBreakpoints(w, breakpointsResponse{Steps: a.devserver.breakpoints.Get(id)})
+}
+
+// ContinueRun continues a run's steps which are halted at breakpoints, eg.
+// POST /dev/runs/{runID}/continue?step=<id> to continue a single step.
+func (a devapi) ContinueRun(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	runID, err := ulid.Parse(chi.URLParam(r, "runID"))
+	if err != nil {
+		_ = publicerr.WriteHTTP(w, publicerr.Wrap(err, 400, "Invalid run ID"))
+		return
+	}
+	n, err := a.devserver.breakpoints.Continue(ctx, runID, r.FormValue("step"))
+	if err != nil {
+		_ = publicerr.WriteHTTP(w, publicerr.Wrap(err, 500, "Error continuing run"))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_ = json.NewEncoder(w).Encode(map[string]any{"ok": true, "continued": n})
+}
+
+func writeBreakpoints(w http.ResponseWriter, resp breakpointsResponse) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_ = json.NewEncoder(w).Encode(resp)
+}
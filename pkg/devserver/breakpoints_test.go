@@ -0,0 +1,156 @@
+package devserver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/inngest/inngest/pkg/cqrs"
+	"github.com/inngest/inngest/pkg/enums"
+	"github.com/inngest/inngest/pkg/execution/executor"
+	"github.com/inngest/inngest/pkg/execution/queue"
+	"github.com/inngest/inngest/pkg/execution/state"
+	"github.com/inngest/inngest/pkg/execution/state/redis_state"
+	"github.com/inngest/inngest/pkg/inngest"
+	"github.com/oklog/ulid/v2"
+	"github.com/redis/rueidis"
+	"github.com/stretchr/testify/require"
+)
+
+type breakpointFunctionLoader struct{}
+
+func (breakpointFunctionLoader) LoadFunction(ctx context.Context, id state.Identifier) (*inngest.Function, error) {
+	return &inngest.Function{ID: id.WorkflowID}, nil
+}
+
+type statusRecorder struct {
+	statuses map[ulid.ULID]enums.RunStatus
+}
+
+func (s *statusRecorder) InsertFunctionRun(ctx context.Context, run cqrs.FunctionRun) error {
+	return nil
+}
+
+func (s *statusRecorder) UpdateFunctionRunStatus(ctx context.Context, runID ulid.ULID, status enums.RunStatus) error {
+	s.statuses[runID] = status
+	return nil
+}
+
+func TestBreakpoints(t *testing.T) {
+	ctx := context.Background()
+	r := miniredis.RunT(t)
+	rc, err := rueidis.NewClient(rueidis.ClientOption{
+		InitAddress:  []string{r.Addr()},
+		DisableCache: true,
+	})
+	require.NoError(t, err)
+	t.Cleanup(rc.Close)
+
+	sm, err := redis_state.New(
+		ctx,
+		redis_state.WithRedisClient(rc),
+		redis_state.WithKeyPrefix("{state}:"),
+		redis_state.WithFunctionLoader(breakpointFunctionLoader{}),
+	)
+	require.NoError(t, err)
+	kg := &redis_state.DefaultQueueKeyGenerator{Prefix: "{queue}"}
+	q := redis_state.NewQueue(rc, redis_state.WithQueueKeyGenerator(kg))
+	runs := &statusRecorder{statuses: map[ulid.ULID]enums.RunStatus{}}
+	bp := newBreakpoints(q, sm, runs)
+
+	// newRun creates a running run, returning the item for its first step.
+	newRun := func(fnID uuid.UUID) queue.Item {
+		id := state.Identifier{
+			WorkflowID:  fnID,
+			WorkspaceID: uuid.New(),
+			RunID:       ulid.Make(),
+		}
+		_, err := sm.New(ctx, state.Input{Identifier: id, EventBatchData: []map[string]any{{"name": "test/event"}}})
+		require.NoError(t, err)
+		require.NoError(t, sm.SetStatus(ctx, id, enums.RunStatusRunning))
+		return queue.Item{
+			GroupID:     uuid.NewString(),
+			WorkspaceID: id.WorkspaceID,
+			Kind:        queue.KindEdge,
+			Identifier:  id,
+			Payload:     queue.PayloadEdge{Edge: inngest.Edge{Incoming: "step-a"}},
+		}
+	}
+
+	// storedItem reads the item holding the halted step from the queue.
+	storedItem := func(h haltedStep) queue.Item {
+		qi := &redis_state.QueueItem{}
+		cmd := rc.B().Hget().Key(kg.QueueItem()).Field(redis_state.HashID(ctx, h.jobID)).Build()
+		require.NoError(t, rc.Do(ctx, cmd).DecodeJSON(qi))
+		return qi.Data
+	}
+
+	item := newRun(uuid.New())
+	runID := item.Identifier.RunID
+
+	// Without breakpoints, steps run.
+	require.False(t, bp.Halt(ctx, item, "step-a"))
+
+	bp.Set(runID.String(), []string{"step-a"})
+	require.Equal(t, []string{"step-a"}, bp.Get(runID.String()))
+	require.False(t, bp.Halt(ctx, item, "step-b"))
+	require.True(t, bp.Halt(ctx, item, "step-a"))
+
+	// The halted step is held in the queue, and the run is marked as halted.
+	halted, err := bp.Halted(ctx, runID)
+	require.NoError(t, err)
+	require.Len(t, halted, 1)
+	require.Equal(t, "step-a", halted[0].StepID)
+	require.WithinDuration(t, time.Now(), halted[0].HaltedAt, time.Minute)
+	md, err := sm.Metadata(ctx, runID)
+	require.NoError(t, err)
+	require.Equal(t, enums.RunStatusHalted, md.Status)
+	require.Equal(t, enums.RunStatusHalted, runs.statuses[runID])
+
+	// Halted steps are read from the queue, so they're kept across restarts.
+	restarted := newBreakpoints(q, sm, runs)
+	halted, err = restarted.Halted(ctx, runID)
+	require.NoError(t, err)
+	require.Len(t, halted, 1)
+
+	// Halting the same attempt again doesn't hold the step twice.
+	require.True(t, bp.Halt(ctx, item, "step-a"))
+	halted, err = bp.Halted(ctx, runID)
+	require.NoError(t, err)
+	require.Len(t, halted, 1)
+
+	// Continuing requeues the held item to run immediately, which then runs.
+	n, err := restarted.Continue(ctx, runID, "")
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	continued := storedItem(halted[0])
+	halted, err = bp.Halted(ctx, runID)
+	require.NoError(t, err)
+	require.Empty(t, halted)
+	require.False(t, bp.Halt(ctx, continued, "step-a"))
+
+	// Retries of the step halt again.
+	retry := continued
+	retry.Attempt = 1
+	require.True(t, bp.Halt(ctx, retry, "step-a"))
+
+	// Function breakpoints halt every step of every run.
+	other := newRun(item.Identifier.WorkflowID)
+	bp.Set(item.Identifier.WorkflowID.String(), []string{executor.BreakpointAll})
+	require.True(t, bp.Halt(ctx, other, "step-c"))
+	require.True(t, bp.Halt(ctx, other, "step-d"))
+
+	// Steps may be continued individually.
+	n, err = bp.Continue(ctx, other.Identifier.RunID, "step-d")
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	halted, err = bp.Halted(ctx, other.Identifier.RunID)
+	require.NoError(t, err)
+	require.Len(t, halted, 1)
+	require.Equal(t, "step-c", halted[0].StepID)
+
+	bp.Set(item.Identifier.WorkflowID.String(), nil)
+	require.Empty(t, bp.Get(item.Identifier.WorkflowID.String()))
+}
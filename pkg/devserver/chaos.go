@@ -0,0 +1,150 @@
+package devserver
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/inngest/inngest/pkg/execution/driver"
+	"github.com/inngest/inngest/pkg/execution/queue"
+	"github.com/inngest/inngest/pkg/execution/state"
+	"github.com/inngest/inngest/pkg/inngest"
+	"github.com/oklog/ulid/v2"
+)
+
+var (
+	// ErrChaosDriver is the error recorded for steps failed by chaos testing.
+	ErrChaosDriver = fmt.Errorf("chaos: injected driver error")
+	// ErrChaosPauseDropped is returned when chaos testing drops a pause consume.
+	ErrChaosPauseDropped = fmt.Errorf("chaos: dropped pause consume")
+)
+
+// ChaosOpts injects artificial failures into the dev server, allowing SDK authors
+// to verify that functions handle retries and are idempotent.  Rates are between
+// 0 and 1, and delays are the maximum delay applied:  each delay is randomized
+// up to the maximum.
+type ChaosOpts struct {
+	// DriverErrorRate is the ratio of step requests which fail with a 500
+	// instead of being sent to the SDK.
+	DriverErrorRate float64 `json:"driver_error_rate"`
+	// LeaseDelay delays each queue lease.
+	LeaseDelay time.Duration `json:"lease_delay"`
+	// PauseDropRate is the ratio of pause consumes which fail, leaving the
+	// pause in place.
+	PauseDropRate float64 `json:"pause_drop_rate"`
+	// StateWriteDelay delays each write to run state.
+	StateWriteDelay time.Duration `json:"state_write_delay"`
+}
+
+// Enabled returns whether any failures are injected.
+func (c ChaosOpts) Enabled() bool {
+	return c.DriverErrorRate > 0 || c.LeaseDelay > 0 || c.PauseDropRate > 0 || c.StateWriteDelay > 0
+}
+
+// Validate returns an error if the options are invalid.
+func (c ChaosOpts) Validate() error {
+	if c.DriverErrorRate < 0 || c.DriverErrorRate > 1 {
+		return fmt.Errorf("chaos driver error rate must be between 0 and 1")
+	}
+	if c.PauseDropRate < 0 || c.PauseDropRate > 1 {
+		return fmt.Errorf("chaos pause drop rate must be between 0 and 1")
+	}
+	if c.LeaseDelay < 0 || c.StateWriteDelay < 0 {
+		return fmt.Errorf("chaos delays must not be negative")
+	}
+	return nil
+}
+
+// chaos returns whether an injected failure with the given rate occurs.
+func chaos(rate float64) bool {
+	return rate > 0 && rand.Float64() < rate
+}
+
+// chaosDelay sleeps for a random duration up to max, or until ctx is done.
+func chaosDelay(ctx context.Context, max time.Duration) {
+	if max <= 0 {
+		return
+	}
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Duration(rand.Int63n(int64(max)))):
+	}
+}
+
+// chaosDriver fails a ratio of step requests with a 500 without calling the SDK.
+type chaosDriver struct {
+	driver.Driver
+	rate float64
+}
+
+func (c chaosDriver) Execute(ctx context.Context, s state.State, item queue.Item, edge inngest.Edge, step inngest.Step, stackIndex, attempt int) (*state.DriverResponse, error) {
+	if !chaos(c.rate) {
+		return c.Driver.Execute(ctx, s, item, edge, step, stackIndex, attempt)
+	}
+	resp := &state.DriverResponse{
+		Step:       step,
+		StatusCode: 500,
+		Output:     ErrChaosDriver.Error(),
+	}
+	resp.SetError(ErrChaosDriver)
+	return resp, nil
+}
+
+// chaosState drops a ratio of pause consumes and delays writes to run state.
+type chaosState struct {
+	state.Manager
+	opts ChaosOpts
+}
+
+var (
+	_ state.BulkCreator           = chaosState{}
+	_ state.ResponseMetadataSaver = chaosState{}
+)
+
+func (c chaosState) New(ctx context.Context, input state.Input) (state.State, error) {
+	chaosDelay(ctx, c.opts.StateWriteDelay)
+	return c.Manager.New(ctx, input)
+}
+
+func (c chaosState) NewMany(ctx context.Context, inputs []state.Input) ([]state.State, []error) {
+	chaosDelay(ctx, c.opts.StateWriteDelay)
+	if creator, ok := c.Manager.(state.BulkCreator); ok {
+		return creator.NewMany(ctx, inputs)
+	}
+	states := make([]state.State, len(inputs))
+	errs := make([]error, len(inputs))
+	for n, input := range inputs {
+		states[n], errs[n] = c.Manager.New(ctx, input)
+	}
+	return states, errs
+}
+
+func (c chaosState) UpdateMetadata(ctx context.Context, runID ulid.ULID, md state.MetadataUpdate) error {
+	chaosDelay(ctx, c.opts.StateWriteDelay)
+	return c.Manager.UpdateMetadata(ctx, runID, md)
+}
+
+func (c chaosState) SaveResponse(ctx context.Context, i state.Identifier, stepID, marshalledOutput string) error {
+	chaosDelay(ctx, c.opts.StateWriteDelay)
+	return c.Manager.SaveResponse(ctx, i, stepID, marshalledOutput)
+}
+
+func (c chaosState) SaveResponseWithMetadata(ctx context.Context, i state.Identifier, stepID, marshalledOutput string, md state.MetadataUpdate) error {
+	chaosDelay(ctx, c.opts.StateWriteDelay)
+	if saver, ok := c.Manager.(state.ResponseMetadataSaver); ok {
+		return saver.SaveResponseWithMetadata(ctx, i, stepID, marshalledOutput, md)
+	}
+	if err := c.Manager.UpdateMetadata(ctx, i.RunID, md); err != nil {
+		return err
+	}
+	return c.Manager.SaveResponse(ctx, i, stepID, marshalledOutput)
+}
+
+func (c chaosState) ConsumePause(ctx context.Context, id uuid.UUID, data any) error {
+	if chaos(c.opts.PauseDropRate) {
+		return ErrChaosPauseDropped
+	}
+	return c.Manager.ConsumePause(ctx, id, data)
+}
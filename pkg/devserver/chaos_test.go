@@ -0,0 +1,54 @@
+package devserver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/inngest/inngest/pkg/execution/driver"
+	"github.com/inngest/inngest/pkg/execution/queue"
+	"github.com/inngest/inngest/pkg/execution/state"
+	"github.com/inngest/inngest/pkg/inngest"
+	"github.com/stretchr/testify/require"
+)
+
+type okDriver struct{ driver.Driver }
+
+func (okDriver) Execute(ctx context.Context, s state.State, item queue.Item, edge inngest.Edge, step inngest.Step, stackIndex, attempt int) (*state.DriverResponse, error) {
+	return &state.DriverResponse{Step: step, StatusCode: 200}, nil
+}
+
+func TestChaosDriver(t *testing.T) {
+	ctx := context.Background()
+	step := inngest.Step{ID: "step"}
+
+	resp, err := chaosDriver{Driver: okDriver{}}.Execute(ctx, nil, queue.Item{}, inngest.Edge{}, step, 0, 0)
+	require.NoError(t, err)
+	require.Equal(t, 200, resp.StatusCode)
+
+	resp, err = chaosDriver{Driver: okDriver{}, rate: 1}.Execute(ctx, nil, queue.Item{}, inngest.Edge{}, step, 0, 0)
+	require.NoError(t, err)
+	require.Equal(t, 500, resp.StatusCode)
+	require.Equal(t, ErrChaosDriver.Error(), resp.Error())
+	require.True(t, resp.Retryable())
+}
+
+func TestChaosStatePauseDrops(t *testing.T) {
+	s := chaosState{opts: ChaosOpts{PauseDropRate: 1}}
+	err := s.ConsumePause(context.Background(), uuid.New(), nil)
+	require.ErrorIs(t, err, ErrChaosPauseDropped)
+}
+
+func TestChaosOptsValidate(t *testing.T) {
+	require.NoError(t, ChaosOpts{}.Validate())
+	require.False(t, ChaosOpts{}.Enabled())
+
+	opts := ChaosOpts{DriverErrorRate: 0.5, LeaseDelay: time.Second}
+	require.NoError(t, opts.Validate())
+	require.True(t, opts.Enabled())
+
+	require.Error(t, ChaosOpts{DriverErrorRate: 1.5}.Validate())
+	require.Error(t, ChaosOpts{PauseDropRate: -1}.Validate())
+	require.Error(t, ChaosOpts{StateWriteDelay: -time.Second}.Validate())
+}
@@ -0,0 +1,35 @@
+package devserver
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/inngest/inngest/pkg/config"
+	"github.com/inngest/inngest/pkg/cqrs"
+	"github.com/inngest/inngest/pkg/execution/state/redis_state"
+)
+
+// appReader loads apps by ID, for resolving app-specific concurrency limits.
+type appReader interface {
+	GetAppByID(ctx context.Context, id uuid.UUID) (*cqrs.App, error)
+}
+
+// appConcurrencyKeyGenerator limits the steps running concurrently within each
+// app as per the given config.  Items are keyed by app ID, and apps are only
+// loaded when limits are set for individual apps.
+func appConcurrencyKeyGenerator(c config.AppConcurrency, apps appReader) redis_state.AppConcurrencyKeyGenerator {
+	return func(ctx context.Context, i redis_state.QueueItem) (string, int) {
+		appID := i.Data.Identifier.AppID
+		if appID == uuid.Nil {
+			return "", 0
+		}
+		if len(c.Apps) == 0 {
+			return appID.String(), c.Limit
+		}
+		app, err := apps.GetAppByID(ctx, appID)
+		if err != nil {
+			return appID.String(), c.Limit
+		}
+		return appID.String(), c.AppLimit(app.Name)
+	}
+}
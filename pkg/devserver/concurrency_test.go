@@ -0,0 +1,117 @@
+package devserver
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/inngest/inngest/pkg/config"
+	"github.com/inngest/inngest/pkg/cqrs"
+	"github.com/inngest/inngest/pkg/execution/queue"
+	"github.com/inngest/inngest/pkg/execution/state"
+	"github.com/inngest/inngest/pkg/execution/state/redis_state"
+	"github.com/oklog/ulid/v2"
+	"github.com/redis/rueidis"
+	"github.com/stretchr/testify/require"
+)
+
+// appsByID stores apps keyed by ID.
+type appsByID map[uuid.UUID]*cqrs.App
+
+func (a appsByID) GetAppByID(ctx context.Context, id uuid.UUID) (*cqrs.App, error) {
+	app, ok := a[id]
+	if !ok {
+		return nil, fmt.Errorf("app not found")
+	}
+	return app, nil
+}
+
+func TestAppConcurrency(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	r := miniredis.RunT(t)
+	rc, err := rueidis.NewClient(rueidis.ClientOption{
+		InitAddress:  []string{r.Addr()},
+		DisableCache: true,
+	})
+	require.NoError(t, err)
+	defer rc.Close()
+
+	small, large, unlimited := uuid.New(), uuid.New(), uuid.New()
+	apps := appsByID{
+		small:     {ID: small, Name: "small"},
+		large:     {ID: large, Name: "large"},
+		unlimited: {ID: unlimited, Name: "unlimited"},
+	}
+	conf := config.AppConcurrency{
+		Limit: 1,
+		Apps:  map[string]int{"large": 2, "unlimited": 0},
+	}
+
+	q := redis_state.NewQueue(
+		rc,
+		redis_state.WithNumWorkers(20),
+		redis_state.WithPollTick(10*time.Millisecond),
+		redis_state.WithQueueKeyGenerator(&redis_state.DefaultQueueKeyGenerator{Prefix: "{queue}"}),
+		redis_state.WithAppConcurrencyKeyGenerator(appConcurrencyKeyGenerator(conf, apps)),
+	)
+
+	var (
+		l       sync.Mutex
+		running = map[uuid.UUID]int{}
+		peak    = map[uuid.UUID]int{}
+		done    = map[uuid.UUID]int{}
+	)
+	go func() {
+		_ = q.Run(ctx, func(ctx context.Context, _ queue.RunInfo, item queue.Item) error {
+			appID := item.Identifier.AppID
+			l.Lock()
+			running[appID]++
+			if running[appID] > peak[appID] {
+				peak[appID] = running[appID]
+			}
+			l.Unlock()
+
+			<-time.After(100 * time.Millisecond)
+
+			l.Lock()
+			running[appID]--
+			done[appID]++
+			l.Unlock()
+			return nil
+		})
+	}()
+
+	// Enqueue items for two functions within each app, so that the functions
+	// share the app's limit.
+	for appID := range apps {
+		fns := []uuid.UUID{uuid.New(), uuid.New()}
+		for n := 0; n < 6; n++ {
+			err := q.Enqueue(ctx, queue.Item{
+				Identifier: state.Identifier{
+					WorkflowID: fns[n%2],
+					AppID:      appID,
+					RunID:      ulid.Make(),
+				},
+			}, time.Now())
+			require.NoError(t, err)
+		}
+	}
+
+	require.Eventually(t, func() bool {
+		l.Lock()
+		defer l.Unlock()
+		return done[small] == 6 && done[large] == 6 && done[unlimited] == 6
+	}, 15*time.Second, 50*time.Millisecond)
+
+	l.Lock()
+	defer l.Unlock()
+	require.Equal(t, 1, peak[small])
+	require.Equal(t, 2, peak[large])
+	require.Greater(t, peak[unlimited], 2)
+}
@@ -0,0 +1,218 @@
+package devserver
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/inngest/inngest/pkg/cqrs"
+	"github.com/inngest/inngest/pkg/devserver/environment"
+	"github.com/inngest/inngest/pkg/inngest"
+	"gopkg.in/yaml.v3"
+)
+
+const configBundleVersion = 1
+
+const (
+	ConfigBundleFormatJSON = "json"
+	ConfigBundleFormatYAML = "yaml"
+)
+
+// ConfigBundle is a portable export of every synced app and its functions'
+// configuration, allowing local environments to be shared and reproduced in
+// another dev server.
+//
+// Unlike snapshots, bundles contain no events, runs or in-flight state.
+type ConfigBundle struct {
+	Version   int               `json:"version"`
+	CreatedAt time.Time         `json:"createdAt"`
+	Apps      []ConfigBundleApp `json:"apps"`
+}
+
+// ConfigBundleApp represents a single synced app within a config bundle.
+type ConfigBundleApp struct {
+	Name        string             `json:"name"`
+	URL         string             `json:"url"`
+	SDKLanguage string             `json:"sdkLanguage"`
+	SDKVersion  string             `json:"sdkVersion"`
+	Framework   string             `json:"framework,omitempty"`
+	Functions   []inngest.Function `json:"functions"`
+}
+
+// ExportConfig returns a bundle of every synced app and its functions within the
+// context's environment.
+func (d *devserver) ExportConfig(ctx context.Context) (*ConfigBundle, error) {
+	wsID := environment.WorkspaceIDFromContext(ctx)
+	apps, err := d.data.GetApps(ctx, wsID)
+	if err != nil {
+		return nil, fmt.Errorf("error loading apps: %w", err)
+	}
+
+	bundle := &ConfigBundle{
+		Version:   configBundleVersion,
+		CreatedAt: time.Now(),
+		Apps:      []ConfigBundleApp{},
+	}
+	for _, app := range apps {
+		fns, err := d.data.GetFunctionsByAppInternalID(ctx, wsID, app.ID)
+		if err != nil {
+			return nil, fmt.Errorf("error loading functions for app %s: %w", app.Name, err)
+		}
+
+		exported := ConfigBundleApp{
+			Name:        app.Name,
+			URL:         app.Url,
+			SDKLanguage: app.SdkLanguage,
+			SDKVersion:  app.SdkVersion,
+			Framework:   app.Framework.String,
+			Functions:   []inngest.Function{},
+		}
+		for _, fn := range fns {
+			f, err := fn.InngestFunction()
+			if err != nil {
+				return nil, fmt.Errorf("error loading function %s: %w", fn.Slug, err)
+			}
+			exported.Functions = append(exported.Functions, *f)
+		}
+		bundle.Apps = append(bundle.Apps, exported)
+	}
+	return bundle, nil
+}
+
+// ImportConfig creates or replaces each app and its functions within the given
+// bundle, as if each app was synced within the context's environment.  Apps
+// which aren't in the bundle are left as-is.
+func (d *devserver) ImportConfig(ctx context.Context, b ConfigBundle) error {
+	if b.Version != configBundleVersion {
+		return fmt.Errorf("unsupported config bundle version: %d", b.Version)
+	}
+
+	d.handlerLock.Lock()
+	defer d.handlerLock.Unlock()
+	for _, app := range b.Apps {
+		if err := d.importApp(ctx, app); err != nil {
+			return fmt.Errorf("error importing app %s: %w", app.Name, err)
+		}
+	}
+	if d.runner != nil {
+		// Schedule any imported cron functions.
+		return d.runner.InitializeCrons(ctx)
+	}
+	return nil
+}
+
+func (d *devserver) importApp(ctx context.Context, app ConfigBundleApp) (err error) {
+	for _, fn := range app.Functions {
+		if len(fn.Steps) == 0 {
+			return fmt.Errorf("function %s has no steps", fn.Slug)
+		}
+		if err := fn.Validate(ctx); err != nil {
+			return fmt.Errorf("function %s is invalid: %w", fn.Slug, err)
+		}
+	}
+
+	wsID := environment.WorkspaceIDFromContext(ctx)
+
+	// The checksum never matches an SDK's registration, so that apps re-sync
+	// once they're running.
+	byt, err := json.Marshal(app)
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(byt)
+
+	appID := environment.ScopedID(wsID, uuid.NewSHA1(uuid.NameSpaceOID, []byte(app.URL)))
+	tx, err := d.data.WithTx(ctx)
+	if err != nil {
+		return err
+	}
+	var replaced *cqrs.App
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+			return
+		}
+		if err = tx.Commit(ctx); err == nil && replaced != nil {
+			d.drains.remove(replaced.ID)
+		}
+	}()
+
+	// Imported apps replace any app with the same URL, as when syncing.  This
+	// happens within the import's transaction, so that failed imports keep the
+	// existing app.
+	if existing, gerr := tx.GetAppByURL(ctx, wsID, app.URL); gerr == nil && existing != nil {
+		if err = tx.DeleteApp(ctx, existing.ID); err != nil {
+			return err
+		}
+		replaced = existing
+	}
+
+	_, err = tx.InsertApp(ctx, cqrs.InsertAppParams{
+		ID:          appID,
+		WorkspaceID: wsID,
+		Name:        app.Name,
+		SdkLanguage: app.SDKLanguage,
+		SdkVersion:  app.SDKVersion,
+		Framework: sql.NullString{
+			String: app.Framework,
+			Valid:  app.Framework != "",
+		},
+		Url:      app.URL,
+		Checksum: hex.EncodeToString(sum[:]),
+	})
+	if err != nil {
+		return err
+	}
+
+	funcs := make([]*inngest.Function, len(app.Functions))
+	for i := range app.Functions {
+		fn := app.Functions[i]
+		funcs[i] = &fn
+	}
+	return upsertFunctions(ctx, tx, wsID, appID, funcs)
+}
+
+// MarshalConfigBundle encodes the bundle as JSON or YAML.  YAML bundles use the
+// same field names as JSON bundles.
+func MarshalConfigBundle(b ConfigBundle, format string) ([]byte, error) {
+	byt, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	switch format {
+	case ConfigBundleFormatJSON, "":
+		return byt, nil
+	case ConfigBundleFormatYAML:
+		var generic any
+		if err := json.Unmarshal(byt, &generic); err != nil {
+			return nil, err
+		}
+		return yaml.Marshal(generic)
+	default:
+		return nil, fmt.Errorf("unknown config bundle format: %s", format)
+	}
+}
+
+// UnmarshalConfigBundle decodes a JSON or YAML bundle.
+func UnmarshalConfigBundle(byt []byte) (*ConfigBundle, error) {
+	// JSON is valid YAML, so decode either format as YAML before converting
+	// to JSON, respecting each type's JSON field names.
+	var generic any
+	if err := yaml.Unmarshal(byt, &generic); err != nil {
+		return nil, fmt.Errorf("error parsing config bundle: %w", err)
+	}
+	byt, err := json.Marshal(generic)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing config bundle: %w", err)
+	}
+	b := &ConfigBundle{}
+	if err := json.Unmarshal(byt, b); err != nil {
+		return nil, fmt.Errorf("error parsing config bundle: %w", err)
+	}
+	return b, nil
+}
@@ -0,0 +1,107 @@
+package devserver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/inngest/inngest/pkg/cqrs/sqlitecqrs"
+	"github.com/inngest/inngest/pkg/inngest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigBundle(t *testing.T) {
+	ctx := context.Background()
+	db, err := sqlitecqrs.New()
+	require.NoError(t, err)
+	data := sqlitecqrs.NewCQRS(db)
+	d := newService(StartOpts{}, nil, data, nil)
+
+	url := "http://localhost:3100/api/inngest"
+	fn := inngest.Function{
+		Name:     "fn",
+		Slug:     "config-fn",
+		Triggers: []inngest.Trigger{{EventTrigger: &inngest.EventTrigger{Event: "test/config"}}},
+		Steps:    []inngest.Step{{ID: "step", Name: "step", URI: url}},
+	}
+	require.NoError(t, d.ImportConfig(ctx, ConfigBundle{
+		Version: configBundleVersion,
+		Apps: []ConfigBundleApp{{
+			Name:        "config-app",
+			URL:         url,
+			SDKLanguage: "js",
+			SDKVersion:  "3.0.0",
+			Functions:   []inngest.Function{fn},
+		}},
+	}))
+
+	// findApp returns the test's app within the bundle.  The database is shared
+	// between tests, so other apps may exist.
+	findApp := func(b *ConfigBundle) *ConfigBundleApp {
+		for _, app := range b.Apps {
+			if app.URL == url {
+				return &app
+			}
+		}
+		return nil
+	}
+
+	bundle, err := d.ExportConfig(ctx)
+	require.NoError(t, err)
+	app := findApp(bundle)
+	require.NotNil(t, app)
+	require.Equal(t, "config-app", app.Name)
+	require.Equal(t, "3.0.0", app.SDKVersion)
+	require.Len(t, app.Functions, 1)
+	require.Equal(t, "config-fn", app.Functions[0].Slug)
+	require.Equal(t, inngest.DeterministicUUID(fn), app.Functions[0].ID)
+
+	for _, format := range []string{ConfigBundleFormatJSON, ConfigBundleFormatYAML} {
+		t.Run("It round trips "+format, func(t *testing.T) {
+			byt, err := MarshalConfigBundle(*bundle, format)
+			require.NoError(t, err)
+			decoded, err := UnmarshalConfigBundle(byt)
+			require.NoError(t, err)
+			require.Equal(t, bundle.Version, decoded.Version)
+			require.True(t, bundle.CreatedAt.Equal(decoded.CreatedAt))
+			require.Equal(t, app, findApp(decoded))
+		})
+	}
+
+	t.Run("It replaces apps and removes missing functions", func(t *testing.T) {
+		other := fn
+		other.Slug = "config-other-fn"
+		other.Name = "other"
+		require.NoError(t, d.ImportConfig(ctx, ConfigBundle{
+			Version: configBundleVersion,
+			Apps: []ConfigBundleApp{{
+				Name:      "config-app",
+				URL:       url,
+				Functions: []inngest.Function{other},
+			}},
+		}))
+
+		bundle, err := d.ExportConfig(ctx)
+		require.NoError(t, err)
+		app := findApp(bundle)
+		require.NotNil(t, app)
+		require.Len(t, app.Functions, 1)
+		require.Equal(t, "config-other-fn", app.Functions[0].Slug)
+
+		_, err = data.GetFunctionByInternalUUID(ctx, uuid.UUID{}, inngest.DeterministicUUID(fn))
+		require.Error(t, err)
+	})
+
+	t.Run("It rejects invalid bundles", func(t *testing.T) {
+		err := d.ImportConfig(ctx, ConfigBundle{Version: 99})
+		require.Error(t, err)
+
+		invalid := fn
+		invalid.Triggers = nil
+		err = d.ImportConfig(ctx, ConfigBundle{
+			Version: configBundleVersion,
+			Apps:    []ConfigBundleApp{{Name: "invalid", URL: "http://localhost:3101", Functions: []inngest.Function{invalid}}},
+		})
+		require.Error(t, err)
+	})
+}
@@ -2,8 +2,10 @@ package devserver
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"time"
 
 	"github.com/alicebob/miniredis/v2"
@@ -12,18 +14,24 @@ import (
 	"github.com/inngest/inngest/pkg/config"
 	_ "github.com/inngest/inngest/pkg/config/defaults"
 	"github.com/inngest/inngest/pkg/consts"
+	"github.com/inngest/inngest/pkg/cqrs"
 	"github.com/inngest/inngest/pkg/cqrs/sqlitecqrs"
 	"github.com/inngest/inngest/pkg/deploy"
 	"github.com/inngest/inngest/pkg/event"
 	"github.com/inngest/inngest/pkg/execution"
+	"github.com/inngest/inngest/pkg/execution/aigateway"
 	"github.com/inngest/inngest/pkg/execution/batch"
+	"github.com/inngest/inngest/pkg/execution/cancellation"
 	"github.com/inngest/inngest/pkg/execution/debounce"
 	"github.com/inngest/inngest/pkg/execution/driver"
 	"github.com/inngest/inngest/pkg/execution/driver/httpdriver"
+	"github.com/inngest/inngest/pkg/execution/driver/recorder"
 	"github.com/inngest/inngest/pkg/execution/executor"
+	"github.com/inngest/inngest/pkg/execution/gateway"
 	"github.com/inngest/inngest/pkg/execution/history"
 	"github.com/inngest/inngest/pkg/execution/queue"
 	"github.com/inngest/inngest/pkg/execution/ratelimit"
+	"github.com/inngest/inngest/pkg/execution/redact"
 	"github.com/inngest/inngest/pkg/execution/runner"
 	"github.com/inngest/inngest/pkg/execution/state"
 	"github.com/inngest/inngest/pkg/execution/state/redis_state"
@@ -52,6 +60,45 @@ type StartOpts struct {
 	PollInterval  int           `json:"poll_interval"`
 	Tick          time.Duration `json:"tick"`
 	RetryInterval int           `json:"retry_interval"`
+	// URLsFile, if set, is the path to a file listing SDK URLs in addition to
+	// URLs.  The file is reloaded when it changes, syncing added apps and
+	// removing deleted apps.
+	URLsFile string `json:"urls_file"`
+	// SnapshotPath, if set, restores the dev server from the snapshot file
+	// at the given path on startup.
+	SnapshotPath string `json:"snapshot_path"`
+	// SigningKey, if set, signs requests to SDKs.  Signed responses and syncs
+	// are verified using this key or PreviousSigningKey.
+	SigningKey string `json:"-"`
+	// PreviousSigningKey is the signing key being rotated out, accepted when
+	// verifying signed responses and syncs.
+	PreviousSigningKey string `json:"-"`
+	// SignatureTolerance is the maximum age, in seconds, of signed responses
+	// and syncs.  Zero uses the default tolerance.
+	SignatureTolerance int `json:"signature_tolerance"`
+	// DrainPeriod is the number of seconds that an app's previous URL keeps
+	// running steps for already-started runs after the app re-syncs at a new
+	// URL.  New runs use the new URL.  Zero disables draining.
+	DrainPeriod int `json:"drain_period"`
+	// Chaos injects artificial failures for testing retries and idempotency.
+	Chaos ChaosOpts `json:"chaos"`
+	// Record records each SDK request and response in memory, allowing runs
+	// to be replayed against their recorded responses.
+	Record bool `json:"record"`
+}
+
+// signatureVerifier returns the verifier for signed responses and syncs, which
+// accepts the current and previous signing keys.
+func (o StartOpts) signatureVerifier() httpdriver.SignatureVerifier {
+	c := httpdriver.Config{
+		SigningKey:         o.SigningKey,
+		PreviousSigningKey: o.PreviousSigningKey,
+		SignatureTolerance: o.SignatureTolerance,
+	}
+	return httpdriver.SignatureVerifier{
+		Keys:      c.SigningKeys(),
+		Tolerance: c.Tolerance(),
+	}
 }
 
 // Create and start a new dev server.  The dev server is used during (surprise surprise)
@@ -85,16 +132,26 @@ func start(ctx context.Context, opts StartOpts) error {
 		opts.Tick = defaultTick
 	}
 
+	if err := opts.Chaos.Validate(); err != nil {
+		return err
+	}
+	if opts.Chaos.Enabled() {
+		logger.From(ctx).Warn().Interface("chaos", opts.Chaos).Msg("chaos testing enabled: injecting failures into execution")
+	}
+
 	// Initialize the devserver
 	dbcqrs := sqlitecqrs.NewCQRS(db)
 	hd := sqlitecqrs.NewHistoryDriver(db)
 	loader := dbcqrs.(state.FunctionLoader)
 
-	rc, err := createInmemoryRedis(ctx, opts.Tick)
+	rc, mr, err := createInmemoryRedis(ctx, opts.Tick)
 	if err != nil {
 		return err
 	}
 
+	// Namespace every key so that installs can share a redis instance.
+	ns := opts.Config.Redis.Namespace
+
 	var sm state.Manager
 	t := runner.NewTracker()
 	sm, err = redis_state.New(
@@ -102,7 +159,7 @@ func start(ctx context.Context, opts StartOpts) error {
 		redis_state.WithFunctionLoader(loader),
 		redis_state.WithRedisClient(rc),
 		redis_state.WithKeyGenerator(redis_state.DefaultKeyFunc{
-			Prefix: "{state}",
+			Prefix: redis_state.NamespacedPrefix(ns, "{state}"),
 		}),
 	)
 	if err != nil {
@@ -110,13 +167,23 @@ func start(ctx context.Context, opts StartOpts) error {
 	}
 
 	queueKG := &redis_state.DefaultQueueKeyGenerator{
-		Prefix: "{queue}",
+		Prefix: redis_state.NamespacedPrefix(ns, "{queue}"),
 	}
+	// overrides reads custom concurrency limits set via the API.  It's assigned
+	// once the queue is created.
+	var overrides queue.ConcurrencyLimitUpdater
 	queueOpts := []redis_state.QueueOpt{
 		redis_state.WithIdempotencyTTL(time.Hour),
 		redis_state.WithNumWorkers(100),
 		redis_state.WithPollTick(opts.Tick),
+		redis_state.WithDrainTimeout(opts.Config.Execution.DrainTimeoutDuration()),
 		redis_state.WithQueueKeyGenerator(queueKG),
+		// Allow throttles to be updated via the API without re-syncing apps.
+		redis_state.WithThrottleOverrides(),
+		// Allow custom concurrency limits to be updated via the API.
+		redis_state.WithConcurrencyOverrides(),
+		redis_state.WithPayloadCompression(opts.Config.Execution.QueueCompression),
+		redis_state.WithQueueLifecycles(throttleLifecycle{sm: sm, cqrs: dbcqrs}),
 		redis_state.WithCustomConcurrencyKeyGenerator(func(ctx context.Context, i redis_state.QueueItem) []state.CustomConcurrency {
 			fn, err := dbcqrs.GetFunctionByInternalUUID(ctx, i.Data.Identifier.WorkspaceID, i.Data.Identifier.WorkflowID)
 			if err != nil {
@@ -127,34 +194,24 @@ func start(ctx context.Context, opts StartOpts) error {
 			if err != nil {
 				return i.Data.Identifier.CustomConcurrencyKeys
 			}
-
-			if f.Concurrency != nil {
-				for _, c := range f.Concurrency.Limits {
-					if !c.IsCustomLimit() {
-						continue
-					}
-					// If there's a concurrency key with the same hash, use the new function's
-					// concurrency limits.
-					//
-					// NOTE:  This is accidentally quadratic but is okay as we bound concurrency
-					// keys to a low value (2-3).
-					for _, actual := range i.Data.Identifier.CustomConcurrencyKeys {
-						if actual.Hash != "" && actual.Hash == c.Hash {
-							actual.Limit = c.Limit
-						}
-					}
-				}
+			var limits map[string]int
+			if overrides != nil {
+				// Errors fall back to the function's limits.
+				limits, _ = overrides.ConcurrencyLimitOverrides(ctx, i.Data.Identifier.WorkflowID)
 			}
-			return i.Data.Identifier.CustomConcurrencyKeys
+			// Use the latest function's limits for any concurrency keys with the
+			// same hash, unless they've been overridden.
+			return state.LatestConcurrencyLimits(i.Data.Identifier.CustomConcurrencyKeys, *f, limits)
 		}),
 		redis_state.WithAccountConcurrencyKeyGenerator(func(ctx context.Context, i redis_state.QueueItem) (string, int) {
 			// NOTE: In the dev server there are no account concurrency limits.
 			return i.Queue(), consts.DefaultConcurrencyLimit
 		}),
+		redis_state.WithAppConcurrencyKeyGenerator(appConcurrencyKeyGenerator(opts.Config.Execution.AppConcurrency, dbcqrs)),
 		redis_state.WithPartitionConcurrencyKeyGenerator(func(ctx context.Context, p redis_state.QueuePartition) (string, int) {
 			// Ensure that we return the correct concurrency values per
 			// partition.
-			funcs, err := dbcqrs.GetFunctions(ctx)
+			funcs, err := dbcqrs.GetAllFunctions(ctx)
 			if err != nil {
 				return p.Queue(), consts.DefaultConcurrencyLimit
 			}
@@ -170,60 +227,145 @@ func start(ctx context.Context, opts StartOpts) error {
 			return p.Queue(), consts.DefaultConcurrencyLimit
 		}),
 	}
+	if opts.Chaos.LeaseDelay > 0 {
+		queueOpts = append(queueOpts, redis_state.WithLeaseDelay(opts.Chaos.LeaseDelay))
+	}
 	if opts.RetryInterval > 0 {
 		queueOpts = append(queueOpts, redis_state.WithBackoffFunc(
 			backoff.GetLinearBackoffFunc(time.Duration(opts.RetryInterval)*time.Second),
 		))
 	}
 	queue := redis_state.NewQueue(rc, queueOpts...)
+	overrides = queue
 
-	rl := ratelimit.New(ctx, rc, "{ratelimit}:")
+	rl := ratelimit.New(ctx, rc, redis_state.NamespacedPrefix(ns, "{ratelimit}:"))
 
-	batcher := batch.NewRedisBatchManager(rc, queueKG, queue)
+	batcher := batch.NewRedisBatchManager(rc, queueKG, queue,
+		batch.WithMaxPendingBatches(opts.Config.Execution.Batches.MaxPending),
+	)
 	debouncer := debounce.NewRedisDebouncer(rc, queueKG, queue)
 
 	// Create a new expression aggregator, using Redis to load evaluables.
-	agg := expressions.NewAggregator(ctx, 100, sm.(expressions.EvaluableLoader), nil)
+	agg := expressions.NewAggregator(
+		ctx,
+		opts.Config.Execution.Aggregator.MaxTreeCount(),
+		sm.(expressions.EvaluableLoader),
+		nil,
+		expressions.WithMaxTreeSize(opts.Config.Execution.Aggregator.MaxTreeSize),
+	)
+
+	if opts.PreviousSigningKey != "" && opts.SigningKey == "" {
+		return fmt.Errorf("a previous signing key requires a signing key")
+	}
+
+	var recordings recorder.Store
+	if opts.Record {
+		recordings = recorder.NewMemoryStore(recordedRuns)
+	}
 
 	var drivers = []driver.Driver{}
 	for _, driverConfig := range opts.Config.Execution.Drivers {
+		if c, ok := driverConfig.(*httpdriver.Config); ok {
+			if opts.SigningKey != "" {
+				c.SigningKey = opts.SigningKey
+				c.PreviousSigningKey = opts.PreviousSigningKey
+			}
+			if opts.SignatureTolerance > 0 {
+				c.SignatureTolerance = opts.SignatureTolerance
+			}
+			// Sync apps using the same proxies as the driver.
+			if proxy := c.ProxyFunc(); proxy != nil {
+				transport := http.DefaultTransport.(*http.Transport).Clone()
+				transport.Proxy = proxy
+				deploy.Client.Transport = awsgateway.NewTransformTripper(transport)
+			}
+		}
 		d, err := driverConfig.NewDriver()
 		if err != nil {
 			return err
 		}
+		if opts.Chaos.DriverErrorRate > 0 {
+			d = chaosDriver{Driver: d, rate: opts.Chaos.DriverErrorRate}
+		}
+		if recordings != nil {
+			// Record outside of chaos testing so that injected failures are
+			// replayed.
+			d = recorder.New(d, recordings)
+		}
 		drivers = append(drivers, d)
 	}
 	pb, err := pubsub.NewPublisher(ctx, opts.Config.EventStream.Service)
 	if err != nil {
 		return fmt.Errorf("failed to create publisher: %w", err)
 	}
+	timeline := newRunTimeline()
+	listeners := []execution.LifecycleListener{
+		history.NewLifecycleListener(
+			nil,
+			hd,
+			memory_writer.NewWriter(),
+			timeline,
+		),
+		lifecycle{
+			sm:         sm,
+			cqrs:       dbcqrs,
+			pb:         pb,
+			eventTopic: opts.Config.EventStream.Service.Concrete.TopicName(),
+		},
+	}
+	if opts.Config.Execution.LifecycleEvents {
+		listeners = append(listeners, executor.NewLifecycleEventListener(
+			sm,
+			getLifecycleEventHandler(pb, opts.Config.EventStream.Service.Concrete.TopicName()),
+		))
+	}
+
+	// Chaos testing only affects execution, so that APIs read state as-is.
+	execState := sm
+	if opts.Chaos.PauseDropRate > 0 || opts.Chaos.StateWriteDelay > 0 {
+		execState = chaosState{Manager: sm, opts: opts.Chaos}
+	}
+
+	bp := newBreakpoints(queue, sm, dbcqrs)
+
 	exec, err := executor.NewExecutor(
-		executor.WithStateManager(sm),
+		executor.WithStateManager(execState),
 		executor.WithRuntimeDrivers(
 			drivers...,
 		),
 		executor.WithExpressionAggregator(agg),
+		executor.WithPauseExpressionBudget(expressions.Budget{
+			Timeout:   opts.Config.Execution.PauseExpressions.TimeoutDuration(),
+			CostLimit: opts.Config.Execution.PauseExpressions.MaxCost(),
+		}),
 		executor.WithQueue(queue),
 		executor.WithLogger(logger.From(ctx)),
 		executor.WithFunctionLoader(loader),
-		executor.WithLifecycleListeners(
-			history.NewLifecycleListener(
-				nil,
-				hd,
-				memory_writer.NewWriter(),
-			),
-			lifecycle{
-				sm:         sm,
-				cqrs:       dbcqrs,
-				pb:         pb,
-				eventTopic: opts.Config.EventStream.Service.Concrete.TopicName(),
-			},
-		),
+		executor.WithFunctionReader(dbcqrs),
+		executor.WithLifecycleListeners(listeners...),
 		executor.WithStepLimits(func(id state.Identifier) int { return consts.DefaultMaxStepLimit }),
+		executor.WithParallelStepLimits(func(id state.Identifier) int { return consts.DefaultMaxParallelStepLimit }),
+		executor.WithIdempotencyPeriod(func(ctx context.Context, workspaceID uuid.UUID) time.Duration {
+			return opts.Config.Execution.IdempotencyPeriodDuration()
+		}),
+		executor.WithCancelTimeout(func(ctx context.Context, workspaceID uuid.UUID) time.Duration {
+			return opts.Config.Execution.CancelTimeoutDuration()
+		}),
+		executor.WithTraceSampleRatio(func(ctx context.Context, workspaceID uuid.UUID) *float64 {
+			return opts.Config.Execution.TraceSampleRatio
+		}),
 		executor.WithInvokeNotFoundHandler(getInvokeNotFoundHandler(ctx, pb, opts.Config.EventStream.Service.Concrete.TopicName())),
 		executor.WithSendingEventHandler(getSendingEventHandler(ctx, pb, opts.Config.EventStream.Service.Concrete.TopicName())),
 		executor.WithDebouncer(debouncer),
 		executor.WithBatcher(batcher),
+		executor.WithRejectBatchOverflow(opts.Config.Execution.Batches.RejectOnLimit()),
+		executor.WithConcurrencyKeyCardinalityLimit(int64(opts.Config.Execution.ConcurrencyKeyCardinality)),
+		executor.WithBreakpoints(bp),
+		executor.WithRedactHook(redact.New(opts.Config.Execution.Redact)),
+		executor.WithAIGateway(aigateway.New(opts.Config.Execution.AI)),
+		executor.WithGateway(gateway.New(opts.Config.Execution.Gateway)),
+		executor.WithCancellationChecker(cancellation.NewChecker(cancellation.NewCQRSReader(dbcqrs))),
+		executor.WithFailureHandlerLoader(getFailureHandlerLoader(dbcqrs)),
 	)
 	if err != nil {
 		return err
@@ -240,11 +382,14 @@ func start(ctx context.Context, opts StartOpts) error {
 		executor.WithServiceDebouncer(debouncer),
 	)
 
+	// Functions within draining apps don't start new runs.
+	drains := newAppDrains()
+
 	runner := runner.NewService(
 		opts.Config,
 		runner.WithCQRS(dbcqrs),
 		runner.WithExecutor(exec),
-		runner.WithExecutionManager(dbcqrs),
+		runner.WithExecutionManager(drainingManager{Manager: dbcqrs, drains: drains}),
 		runner.WithEventManager(event.NewManager()),
 		runner.WithStateManager(sm),
 		runner.WithRunnerQueue(queue),
@@ -261,11 +406,25 @@ func start(ctx context.Context, opts StartOpts) error {
 	ds.state = sm
 	ds.queue = queue
 	ds.executor = exec
+	ds.db = db
+	ds.redis = mr
+	ds.rateLimiter = rl
+	ds.batcher = batcher
+	ds.drains = drains
+	ds.breakpoints = bp
+	ds.recordings = recordings
+	ds.timeline = timeline
+
+	if opts.SnapshotPath != "" {
+		if err := ds.RestoreFile(ctx, opts.SnapshotPath); err != nil {
+			return err
+		}
+	}
 
 	return service.StartAll(ctx, ds, runner, executorSvc)
 }
 
-func createInmemoryRedis(ctx context.Context, tick time.Duration) (rueidis.Client, error) {
+func createInmemoryRedis(ctx context.Context, tick time.Duration) (rueidis.Client, *miniredis.Miniredis, error) {
 	r := miniredis.NewMiniRedis()
 	_ = r.Start()
 	rc, err := rueidis.NewClient(rueidis.ClientOption{
@@ -273,7 +432,7 @@ func createInmemoryRedis(ctx context.Context, tick time.Duration) (rueidis.Clien
 		DisableCache: true,
 	})
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// If tick is lower than 250ms, tick every 100ms.  This lets us save
@@ -288,12 +447,12 @@ func createInmemoryRedis(ctx context.Context, tick time.Duration) (rueidis.Clien
 			r.FastForward(poll)
 		}
 	}()
-	return rc, nil
+	return rc, r, nil
 }
 
 func getSendingEventHandler(ctx context.Context, pb pubsub.Publisher, topic string) execution.HandleSendingEvent {
 	return func(ctx context.Context, evt event.Event, item queue.Item) error {
-		trackedEvent := event.NewOSSTrackedEvent(evt)
+		trackedEvent := event.NewOSSTrackedEventForWorkspace(evt, item.WorkspaceID)
 		byt, err := json.Marshal(trackedEvent)
 		if err != nil {
 			return fmt.Errorf("error marshalling invocation event: %w", err)
@@ -322,6 +481,49 @@ func getSendingEventHandler(ctx context.Context, pb pubsub.Publisher, topic stri
 	}
 }
 
+func getLifecycleEventHandler(pb pubsub.Publisher, topic string) executor.LifecycleEventHandler {
+	return func(ctx context.Context, id state.Identifier, evts []event.Event) error {
+		for _, evt := range evts {
+			trackedEvent := event.NewOSSTrackedEventForWorkspace(evt, id.WorkspaceID)
+			byt, err := json.Marshal(trackedEvent)
+			if err != nil {
+				return fmt.Errorf("error marshalling lifecycle event: %w", err)
+			}
+
+			err = pb.Publish(
+				ctx,
+				topic,
+				pubsub.Message{
+					Name:      event.EventReceivedName,
+					Data:      string(byt),
+					Timestamp: trackedEvent.GetEvent().Time(),
+				},
+			)
+			if err != nil {
+				return fmt.Errorf("error publishing lifecycle event: %w", err)
+			}
+		}
+		return nil
+	}
+}
+
+// getFailureHandlerLoader loads onFailure handlers from the failed run's app, as
+// function slugs are only unique within each app.
+func getFailureHandlerLoader(r cqrs.FunctionReader) func(ctx context.Context, id state.Identifier, slug string) (*cqrs.Function, error) {
+	return func(ctx context.Context, id state.Identifier, slug string) (*cqrs.Function, error) {
+		fns, err := r.GetFunctionsByAppInternalID(ctx, id.WorkspaceID, id.AppID)
+		if err != nil {
+			return nil, err
+		}
+		for _, fn := range fns {
+			if fn != nil && fn.Slug == slug {
+				return fn, nil
+			}
+		}
+		return nil, sql.ErrNoRows
+	}
+}
+
 func getInvokeNotFoundHandler(ctx context.Context, pb pubsub.Publisher, topic string) execution.InvokeNotFoundHandler {
 	return func(ctx context.Context, opts execution.InvokeNotFoundHandlerOpts, evts []event.Event) error {
 		eg := errgroup.Group{}
@@ -329,7 +531,7 @@ func getInvokeNotFoundHandler(ctx context.Context, pb pubsub.Publisher, topic st
 		for _, e := range evts {
 			evt := e
 			eg.Go(func() error {
-				trackedEvent := event.NewOSSTrackedEvent(evt)
+				trackedEvent := event.NewOSSTrackedEventForWorkspace(evt, opts.OriginalEvent.GetWorkspaceID())
 				byt, err := json.Marshal(trackedEvent)
 				if err != nil {
 					return fmt.Errorf("error marshalling function finished event: %w", err)
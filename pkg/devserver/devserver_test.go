@@ -0,0 +1,53 @@
+package devserver
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/inngest/inngest/pkg/cqrs"
+	"github.com/inngest/inngest/pkg/cqrs/sqlitecqrs"
+	"github.com/inngest/inngest/pkg/execution/state"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFailureHandlerLoader(t *testing.T) {
+	ctx := context.Background()
+	db, err := sqlitecqrs.New()
+	require.NoError(t, err)
+	data := sqlitecqrs.NewCQRS(db)
+
+	// Both apps define a handler with the same slug.
+	insert := func(name string) (uuid.UUID, uuid.UUID) {
+		appID := uuid.New()
+		_, err := data.InsertApp(ctx, cqrs.InsertAppParams{ID: appID, Name: name, Url: "http://localhost/" + name})
+		require.NoError(t, err)
+		fnID := uuid.New()
+		_, err = data.InsertFunction(ctx, cqrs.InsertFunctionParams{
+			ID:        fnID,
+			AppID:     appID,
+			Name:      "handler",
+			Slug:      "handler",
+			Config:    "{}",
+			CreatedAt: time.Now(),
+		})
+		require.NoError(t, err)
+		return appID, fnID
+	}
+	appA, fnA := insert("a")
+	appB, fnB := insert("b")
+
+	load := getFailureHandlerLoader(data)
+	fn, err := load(ctx, state.Identifier{AppID: appA}, "handler")
+	require.NoError(t, err)
+	require.Equal(t, fnA, fn.ID)
+
+	fn, err = load(ctx, state.Identifier{AppID: appB}, "handler")
+	require.NoError(t, err)
+	require.Equal(t, fnB, fn.ID)
+
+	_, err = load(ctx, state.Identifier{AppID: appA}, "missing")
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
@@ -0,0 +1,208 @@
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/inngest/inngest/pkg/util"
+)
+
+// maxPatternURLs is the maximum number of URLs that a single pattern may expand
+// to, preventing typos from scanning every port.
+const maxPatternURLs = 1024
+
+var (
+	// patterns lists the URL patterns probed during discovery.
+	patterns    = []string{}
+	patternLock sync.Mutex
+
+	// patternURLs maps each URL discovered via DiscoverPatterns to the pattern
+	// it matched, so that URLs are pruned once their pattern is removed.  This
+	// is guarded by urlLock.
+	patternURLs = map[string]string{}
+)
+
+// IsPattern returns whether the given SDK URL is a pattern matching many URLs,
+// rather than a single URL.
+func IsPattern(u string) bool {
+	_, port, path := splitURL(u)
+	return strings.Contains(port, "-") || port == "*" || strings.HasSuffix(path, "/*")
+}
+
+// ExpandURL expands a URL pattern into every URL it matches.  Patterns may contain:
+//
+//   - a port range, eg. "http://localhost:3000-3005/api/inngest"
+//   - a wildcard port, eg. "http://localhost:*/api/inngest", which matches the
+//     default discovery ports
+//   - a wildcard path, eg. "http://localhost:3000/*", which matches the default
+//     discovery paths
+//
+// URLs which aren't patterns are returned as-is.
+func ExpandURL(u string) ([]string, error) {
+	host, portSpec, path := splitURL(u)
+	if host == "" {
+		return nil, fmt.Errorf("invalid sdk url: %s", u)
+	}
+	scheme := "http"
+	if idx := strings.Index(u, "://"); idx > 0 {
+		scheme = u[:idx]
+	}
+
+	ports := []string{portSpec}
+	switch {
+	case portSpec == "*":
+		ports = make([]string, len(Ports))
+		for n, p := range Ports {
+			ports[n] = strconv.Itoa(p)
+		}
+	case strings.Contains(portSpec, "-"):
+		from, to, _ := strings.Cut(portSpec, "-")
+		start, err := strconv.Atoi(from)
+		if err != nil {
+			return nil, fmt.Errorf("invalid port range in sdk url %s: %w", u, err)
+		}
+		end, err := strconv.Atoi(to)
+		if err != nil {
+			return nil, fmt.Errorf("invalid port range in sdk url %s: %w", u, err)
+		}
+		if start <= 0 || end > 65535 || end < start {
+			return nil, fmt.Errorf("invalid port range in sdk url: %s", u)
+		}
+		ports = []string{}
+		for p := start; p <= end; p++ {
+			ports = append(ports, strconv.Itoa(p))
+		}
+	}
+
+	paths := []string{path}
+	if strings.HasSuffix(path, "/*") {
+		prefix := strings.TrimSuffix(path, "/*")
+		paths = make([]string, len(Paths))
+		for n, p := range Paths {
+			paths[n] = prefix + p
+		}
+	}
+
+	if len(ports)*len(paths) > maxPatternURLs {
+		return nil, fmt.Errorf("sdk url %s matches more than %d urls", u, maxPatternURLs)
+	}
+
+	result := []string{}
+	for _, port := range ports {
+		addr := host
+		if port != "" {
+			addr = net.JoinHostPort(host, port)
+		}
+		for _, path := range paths {
+			result = append(result, scheme+"://"+addr+path)
+		}
+	}
+	return result, nil
+}
+
+// SetPatterns replaces the URL patterns that are probed by DiscoverPatterns,
+// pruning URLs discovered via removed patterns.  This returns the pruned URLs.
+func SetPatterns(p []string) []string {
+	patternLock.Lock()
+	patterns = p
+	patternLock.Unlock()
+
+	set := map[string]struct{}{}
+	for _, pattern := range p {
+		set[pattern] = struct{}{}
+	}
+
+	urlLock.Lock()
+	defer urlLock.Unlock()
+	pruned := []string{}
+	for u, pattern := range patternURLs {
+		if _, ok := set[pattern]; ok {
+			continue
+		}
+		delete(patternURLs, u)
+		delete(urls, u)
+		pruned = append(pruned, u)
+	}
+	return pruned
+}
+
+// DiscoverPatterns probes every URL matching the patterns set via SetPatterns,
+// adding URLs hosting an SDK to the list of discovered URLs.
+func DiscoverPatterns(ctx context.Context) map[string]struct{} {
+	type match struct {
+		url, pattern string
+	}
+
+	patternLock.Lock()
+	all := []match{}
+	for _, p := range patterns {
+		expanded, err := ExpandURL(p)
+		if err != nil {
+			continue
+		}
+		for _, u := range expanded {
+			all = append(all, match{url: u, pattern: p})
+		}
+	}
+	patternLock.Unlock()
+
+	found := make(chan match, len(all))
+	wg := sync.WaitGroup{}
+	for _, m := range all {
+		wg.Add(1)
+		go func(m match) {
+			defer wg.Done()
+			if err := checkURL(ctx, m.url); err == nil {
+				found <- match{url: util.NormalizeAppURL(m.url, false), pattern: m.pattern}
+			}
+		}(m)
+	}
+	wg.Wait()
+	close(found)
+
+	// Patterns may have been removed while probing.
+	patternLock.Lock()
+	current := map[string]struct{}{}
+	for _, p := range patterns {
+		current[p] = struct{}{}
+	}
+	patternLock.Unlock()
+
+	urlLock.Lock()
+	for m := range found {
+		if _, ok := current[m.pattern]; !ok {
+			continue
+		}
+		if _, ok := urls[m.url]; ok {
+			// URLs discovered by other means, eg. autodiscovery, aren't owned
+			// by the pattern.
+			continue
+		}
+		urls[m.url] = struct{}{}
+		patternURLs[m.url] = m.pattern
+	}
+	urlLock.Unlock()
+
+	return URLs()
+}
+
+// splitURL returns the host, port, and path of the given URL.  This doesn't use
+// url.Parse, as patterns contain invalid ports.
+func splitURL(u string) (host, port, path string) {
+	if idx := strings.Index(u, "://"); idx >= 0 {
+		u = u[idx+3:]
+	}
+	addr := u
+	if idx := strings.Index(u, "/"); idx >= 0 {
+		addr, path = u[:idx], u[idx:]
+	}
+	host = addr
+	if idx := strings.LastIndex(addr, ":"); idx >= 0 && !strings.HasSuffix(addr, "]") {
+		host, port = addr[:idx], addr[idx+1:]
+	}
+	return strings.Trim(host, "[]"), port, path
+}
@@ -0,0 +1,63 @@
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandURL(t *testing.T) {
+	urls, err := ExpandURL("http://localhost:3000-3002/api/inngest")
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"http://localhost:3000/api/inngest",
+		"http://localhost:3001/api/inngest",
+		"http://localhost:3002/api/inngest",
+	}, urls)
+
+	urls, err = ExpandURL("http://localhost:*/api/inngest")
+	require.NoError(t, err)
+	require.Len(t, urls, len(Ports))
+
+	urls, err = ExpandURL("https://example.com/*")
+	require.NoError(t, err)
+	require.Len(t, urls, len(Paths))
+	require.Equal(t, "https://example.com/api/inngest", urls[0])
+
+	urls, err = ExpandURL("http://localhost:3000/api/inngest")
+	require.NoError(t, err)
+	require.Equal(t, []string{"http://localhost:3000/api/inngest"}, urls)
+	require.False(t, IsPattern("http://localhost:3000/api/inngest"))
+	require.True(t, IsPattern("http://localhost:3000-3005/api/inngest"))
+
+	_, err = ExpandURL("http://localhost:3005-3000/api/inngest")
+	require.Error(t, err)
+	_, err = ExpandURL("http://localhost:1-65535/api/inngest")
+	require.Error(t, err)
+}
+
+func TestDiscoverPatterns(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("x-inngest-sdk", "inngest-js:v3.0.0")
+	}))
+	t.Cleanup(srv.Close)
+	_, port, err := net.SplitHostPort(strings.TrimPrefix(srv.URL, "http://"))
+	require.NoError(t, err)
+
+	pattern := fmt.Sprintf("http://127.0.0.1:%s-%s/api/inngest", port, port)
+	url := fmt.Sprintf("http://localhost:%s/api/inngest", port)
+
+	require.Empty(t, SetPatterns([]string{pattern}))
+	require.Contains(t, DiscoverPatterns(context.Background()), url)
+
+	// URLs are pruned once their pattern is removed.
+	require.Equal(t, []string{url}, SetPatterns([]string{}))
+	require.NotContains(t, URLs(), url)
+	require.NotContains(t, DiscoverPatterns(context.Background()), url)
+}
@@ -0,0 +1,163 @@
+package devserver
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/inngest/inngest/pkg/cqrs"
+	"github.com/inngest/inngest/pkg/devserver/environment"
+	"github.com/inngest/inngest/pkg/inngest"
+	"github.com/inngest/inngest/pkg/logger"
+)
+
+// appDrains tracks apps which are draining after the app re-synced at a new URL.
+// Functions within draining apps no longer start new runs, while steps for runs
+// which have already started are still sent to the app's previous URL until the
+// drain period ends and the previous app is removed.
+type appDrains struct {
+	lock sync.Mutex
+	// apps maps the IDs of draining apps to the time their drain ends.
+	apps map[uuid.UUID]time.Time
+	// fns maps the IDs of functions within draining apps to their app IDs.
+	fns map[uuid.UUID]uuid.UUID
+}
+
+func newAppDrains() *appDrains {
+	return &appDrains{
+		apps: map[uuid.UUID]time.Time{},
+		fns:  map[uuid.UUID]uuid.UUID{},
+	}
+}
+
+func (a *appDrains) add(appID uuid.UUID, fnIDs []uuid.UUID, until time.Time) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	a.apps[appID] = until
+	for _, id := range fnIDs {
+		a.fns[id] = appID
+	}
+}
+
+// remove stops draining the given app, returning whether the app was draining.
+func (a *appDrains) remove(appID uuid.UUID) bool {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	if _, ok := a.apps[appID]; !ok {
+		return false
+	}
+	delete(a.apps, appID)
+	for fnID, id := range a.fns {
+		if id == appID {
+			delete(a.fns, fnID)
+		}
+	}
+	return true
+}
+
+func (a *appDrains) isAppDraining(appID uuid.UUID) bool {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	_, ok := a.apps[appID]
+	return ok
+}
+
+// filter removes functions within draining apps from the given functions.
+func (a *appDrains) filter(fns []inngest.Function) []inngest.Function {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	if len(a.fns) == 0 {
+		return fns
+	}
+	result := make([]inngest.Function, 0, len(fns))
+	for _, fn := range fns {
+		if _, ok := a.fns[fn.ID]; !ok {
+			result = append(result, fn)
+		}
+	}
+	return result
+}
+
+// drainingManager wraps a cqrs.Manager, excluding functions within draining apps
+// when loading functions to start runs.  Loading a single function by its ID is
+// unaffected, so that runs which have already started continue to execute.
+type drainingManager struct {
+	cqrs.Manager
+	drains *appDrains
+}
+
+func (d drainingManager) Functions(ctx context.Context) ([]inngest.Function, error) {
+	fns, err := d.Manager.Functions(ctx)
+	return d.drains.filter(fns), err
+}
+
+func (d drainingManager) FunctionsScheduled(ctx context.Context) ([]inngest.Function, error) {
+	fns, err := d.Manager.FunctionsScheduled(ctx)
+	return d.drains.filter(fns), err
+}
+
+func (d drainingManager) FunctionsByTrigger(ctx context.Context, workspaceID uuid.UUID, eventName string) ([]inngest.Function, error) {
+	fns, err := d.Manager.FunctionsByTrigger(ctx, workspaceID, eventName)
+	return d.drains.filter(fns), err
+}
+
+// drainPreviousApps starts draining apps with the given name hosted at a different
+// URL, ie. previous deployments of an app which has re-synced at a new URL, within
+// the context's environment.  This is a no-op unless a drain period is configured.
+func (d *devserver) drainPreviousApps(ctx context.Context, name, url string) error {
+	period := time.Duration(d.opts.DrainPeriod) * time.Second
+	if period <= 0 || name == "" {
+		return nil
+	}
+
+	wsID := environment.WorkspaceIDFromContext(ctx)
+	apps, err := d.data.GetApps(ctx, wsID)
+	if err != nil {
+		return err
+	}
+
+	for _, app := range apps {
+		if app.Name != name || app.Url == url || d.drains.isAppDraining(app.ID) {
+			continue
+		}
+
+		fns, err := d.data.GetFunctionsByAppInternalID(ctx, wsID, app.ID)
+		if err != nil {
+			return err
+		}
+		ids := make([]uuid.UUID, len(fns))
+		for n, fn := range fns {
+			ids[n] = fn.ID
+		}
+
+		d.drains.add(app.ID, ids, time.Now().Add(period))
+		logger.From(ctx).Info().
+			Str("app", app.Name).
+			Str("url", app.Url).
+			Str("new_url", url).
+			Dur("period", period).
+			Msg("draining previous app url")
+
+		appID := app.ID
+		time.AfterFunc(period, func() {
+			d.finishDrain(context.Background(), appID)
+		})
+	}
+	return nil
+}
+
+// finishDrain removes a previous app once its drain period ends.  Apps which
+// re-synced while draining are no longer draining and are kept.
+func (d *devserver) finishDrain(ctx context.Context, appID uuid.UUID) {
+	if !d.drains.remove(appID) {
+		return
+	}
+	if err := d.data.DeleteFunctionsByAppID(ctx, appID); err != nil {
+		logger.From(ctx).Error().Err(err).Str("app_id", appID.String()).Msg("error deleting drained functions")
+		return
+	}
+	if err := d.data.DeleteApp(ctx, appID); err != nil {
+		logger.From(ctx).Error().Err(err).Str("app_id", appID.String()).Msg("error deleting drained app")
+	}
+}
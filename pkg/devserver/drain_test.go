@@ -0,0 +1,76 @@
+package devserver
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/inngest/inngest/pkg/cqrs"
+	"github.com/inngest/inngest/pkg/cqrs/sqlitecqrs"
+	"github.com/inngest/inngest/pkg/inngest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDrainPreviousApps(t *testing.T) {
+	ctx := context.Background()
+	db, err := sqlitecqrs.New()
+	require.NoError(t, err)
+	data := sqlitecqrs.NewCQRS(db)
+
+	d := newService(StartOpts{DrainPeriod: 1}, nil, data, nil)
+	loader := drainingManager{Manager: data, drains: d.drains}
+
+	insert := func(url string) (uuid.UUID, uuid.UUID) {
+		appID := uuid.New()
+		_, err := data.InsertApp(ctx, cqrs.InsertAppParams{ID: appID, Name: "app", Url: url})
+		require.NoError(t, err)
+
+		fn := inngest.Function{
+			ID:       uuid.New(),
+			Name:     "fn",
+			Slug:     "app-fn",
+			Triggers: []inngest.Trigger{{EventTrigger: &inngest.EventTrigger{Event: "test/event"}}},
+			Steps:    []inngest.Step{{ID: "step", URI: url}},
+		}
+		config, err := json.Marshal(fn)
+		require.NoError(t, err)
+		_, err = data.InsertFunction(ctx, cqrs.InsertFunctionParams{
+			ID:        fn.ID,
+			AppID:     appID,
+			Name:      fn.Name,
+			Slug:      fn.Slug,
+			Config:    string(config),
+			CreatedAt: time.Now(),
+		})
+		require.NoError(t, err)
+		return appID, fn.ID
+	}
+
+	oldApp, oldFn := insert("http://localhost:3000/api/inngest")
+	_, newFn := insert("http://localhost:3001/api/inngest")
+
+	fns, err := loader.FunctionsByTrigger(ctx, uuid.UUID{}, "test/event")
+	require.NoError(t, err)
+	require.Len(t, fns, 2)
+
+	require.NoError(t, d.drainPreviousApps(ctx, "app", "http://localhost:3001/api/inngest"))
+
+	// Only the new deployment starts runs, while the previous function can
+	// still be loaded for started runs.
+	fns, err = loader.FunctionsByTrigger(ctx, uuid.UUID{}, "test/event")
+	require.NoError(t, err)
+	require.Len(t, fns, 1)
+	require.Equal(t, newFn, fns[0].ID)
+	_, err = data.GetFunctionByInternalUUID(ctx, uuid.UUID{}, oldFn)
+	require.NoError(t, err)
+
+	// The previous app is removed once the drain period ends.
+	require.Eventually(t, func() bool {
+		_, err := data.GetAppByID(ctx, oldApp)
+		return err != nil && !d.drains.isAppDraining(oldApp)
+	}, 5*time.Second, 50*time.Millisecond)
+	_, err = data.GetFunctionByInternalUUID(ctx, uuid.UUID{}, oldFn)
+	require.Error(t, err)
+}
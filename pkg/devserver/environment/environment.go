@@ -0,0 +1,127 @@
+package environment
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/inngest/inngest/pkg/headers"
+	"github.com/inngest/inngest/pkg/publicerr"
+)
+
+const (
+	// Default is the environment used by requests which don't select an
+	// environment.  Its workspace ID is the zero UUID, so that apps and runs
+	// created before environments existed remain within the default environment.
+	Default = "production"
+
+	// QueryParam selects an environment via a request's query string, for
+	// clients which can't set the environment header, eg. browsers.
+	QueryParam = "env"
+
+	// maxNameLength is the maximum length of an environment's name.
+	maxNameLength = 64
+)
+
+var (
+	// ErrInvalidName is returned when an environment's name is invalid.
+	ErrInvalidName = fmt.Errorf("invalid environment name")
+
+	// namespace namespaces the deterministic workspace IDs of environments.
+	namespace = uuid.MustParse("4f0c2a1e-8d3b-5a76-9e41-7c2d6b8f1a35")
+
+	validName = regexp.MustCompile(`^[a-z0-9][a-z0-9._/-]*$`)
+)
+
+type ctxKey struct{}
+
+// Normalize returns the canonical form of an environment's name.  Names are
+// case insensitive, and an empty name is the default environment.
+func Normalize(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return Default
+	}
+	return name
+}
+
+// Validate returns ErrInvalidName if the given name can't be used as an
+// environment's name.
+func Validate(name string) error {
+	name = Normalize(name)
+	if len(name) > maxNameLength || !validName.MatchString(name) {
+		return fmt.Errorf("%w: %q", ErrInvalidName, name)
+	}
+	return nil
+}
+
+// WorkspaceID returns the workspace ID for the given environment.  IDs are
+// deterministic, so that each environment's apps and runs are found after the
+// dev server restarts.
+func WorkspaceID(name string) uuid.UUID {
+	name = Normalize(name)
+	if name == Default {
+		return uuid.UUID{}
+	}
+	return uuid.NewSHA1(namespace, []byte(name))
+}
+
+// ScopedID returns the ID of an app or function within the given workspace.
+// Apps and functions are registered with IDs derived from their URL or config,
+// so IDs must be scoped for the same app to be registered within many
+// environments.  IDs within the default environment are unchanged.
+func ScopedID(workspaceID, id uuid.UUID) uuid.UUID {
+	if workspaceID == (uuid.UUID{}) {
+		return id
+	}
+	return uuid.NewSHA1(workspaceID, id[:])
+}
+
+// WithName returns a context which acts within the given environment.
+func WithName(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, Normalize(name))
+}
+
+// Name returns the environment that the context acts within, defaulting to
+// Default.
+func Name(ctx context.Context) string {
+	if name, ok := ctx.Value(ctxKey{}).(string); ok {
+		return name
+	}
+	return Default
+}
+
+// WorkspaceIDFromContext returns the workspace ID of the environment that the
+// context acts within.
+func WorkspaceIDFromContext(ctx context.Context) uuid.UUID {
+	return WorkspaceID(Name(ctx))
+}
+
+// FromRequest returns the environment selected by the request's environment
+// header, falling back to the "env" query parameter.
+func FromRequest(r *http.Request) (string, error) {
+	name := r.Header.Get(headers.HeaderKeyEnv)
+	if name == "" {
+		name = r.URL.Query().Get(QueryParam)
+	}
+	if err := Validate(name); err != nil {
+		return "", err
+	}
+	return Normalize(name), nil
+}
+
+// Middleware sets the environment selected by each request within the
+// request's context, rejecting requests which select an invalid environment.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name, err := FromRequest(r)
+		if err != nil {
+			_ = publicerr.WriteHTTP(w, publicerr.Wrap(err, 400, "Invalid environment"))
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(WithName(r.Context(), name)))
+	})
+}
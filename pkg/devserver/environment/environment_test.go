@@ -0,0 +1,71 @@
+package environment
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/inngest/inngest/pkg/headers"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkspaceID(t *testing.T) {
+	require.Equal(t, uuid.UUID{}, WorkspaceID(""))
+	require.Equal(t, uuid.UUID{}, WorkspaceID(Default))
+	require.NotEqual(t, uuid.UUID{}, WorkspaceID("staging"))
+	// Names are case insensitive, and IDs are deterministic.
+	require.Equal(t, WorkspaceID("staging"), WorkspaceID(" Staging "))
+	require.NotEqual(t, WorkspaceID("staging"), WorkspaceID("feature/x"))
+
+	id := uuid.New()
+	require.Equal(t, id, ScopedID(uuid.UUID{}, id))
+	require.NotEqual(t, id, ScopedID(WorkspaceID("staging"), id))
+	require.Equal(t, ScopedID(WorkspaceID("staging"), id), ScopedID(WorkspaceID("staging"), id))
+}
+
+func TestValidate(t *testing.T) {
+	for _, name := range []string{"", "production", "feature/my-branch", "v1.2_x"} {
+		require.NoError(t, Validate(name), name)
+	}
+	for _, name := range []string{"-branch", "my branch", "a?b", strings.Repeat("a", 65)} {
+		require.ErrorIs(t, Validate(name), ErrInvalidName, name)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	var got string
+	h := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = Name(r.Context())
+	}))
+
+	serve := func(r *http.Request) int {
+		got = ""
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	require.Equal(t, http.StatusOK, serve(r))
+	require.Equal(t, Default, got)
+
+	r = httptest.NewRequest(http.MethodGet, "/?env=staging", nil)
+	require.Equal(t, http.StatusOK, serve(r))
+	require.Equal(t, "staging", got)
+
+	// The header takes precedence over the query parameter.
+	r = httptest.NewRequest(http.MethodGet, "/?env=staging", nil)
+	r.Header.Set(headers.HeaderKeyEnv, "Feature/X")
+	require.Equal(t, http.StatusOK, serve(r))
+	require.Equal(t, "feature/x", got)
+
+	r = httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set(headers.HeaderKeyEnv, "not valid")
+	require.Equal(t, http.StatusBadRequest, serve(r))
+	require.Equal(t, "", got)
+
+	require.Equal(t, Default, Name(context.Background()))
+}
@@ -0,0 +1,28 @@
+package devserver
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/inngest/inngest/pkg/api/apiv1"
+	"github.com/inngest/inngest/pkg/devserver/environment"
+)
+
+// environmentAuth authenticates v1 API requests within the environment selected
+// by each request.  The dev server has no accounts.
+type environmentAuth struct {
+	workspaceID uuid.UUID
+}
+
+func (e environmentAuth) AccountID() uuid.UUID {
+	return uuid.UUID{}
+}
+
+func (e environmentAuth) WorkspaceID() uuid.UUID {
+	return e.workspaceID
+}
+
+// environmentAuthFinder returns the auth for the environment within the context.
+func environmentAuthFinder(ctx context.Context) (apiv1.V1Auth, error) {
+	return environmentAuth{workspaceID: environment.WorkspaceIDFromContext(ctx)}, nil
+}
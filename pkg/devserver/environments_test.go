@@ -0,0 +1,69 @@
+package devserver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/inngest/inngest/pkg/cqrs/sqlitecqrs"
+	"github.com/inngest/inngest/pkg/devserver/environment"
+	"github.com/inngest/inngest/pkg/inngest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvironmentIsolation(t *testing.T) {
+	ctx := context.Background()
+	db, err := sqlitecqrs.New()
+	require.NoError(t, err)
+	data := sqlitecqrs.NewCQRS(db)
+	d := newService(StartOpts{}, nil, data, nil)
+
+	url := "http://localhost:3200/api/inngest"
+	bundle := func(slug string) ConfigBundle {
+		return ConfigBundle{
+			Version: configBundleVersion,
+			Apps: []ConfigBundleApp{{
+				Name: "env-app",
+				URL:  url,
+				Functions: []inngest.Function{{
+					Name:     slug,
+					Slug:     slug,
+					Triggers: []inngest.Trigger{{EventTrigger: &inngest.EventTrigger{Event: "test/env"}}},
+					Steps:    []inngest.Step{{ID: "step", Name: "step", URI: url}},
+				}},
+			}},
+		}
+	}
+
+	// The same app is synced within the default and a branch environment.
+	branch := environment.WithName(ctx, "feature/env")
+	require.NoError(t, d.ImportConfig(ctx, bundle("env-default-fn")))
+	require.NoError(t, d.ImportConfig(branch, bundle("env-branch-fn")))
+
+	slugs := func(ctx context.Context) []string {
+		b, err := d.ExportConfig(ctx)
+		require.NoError(t, err)
+		out := []string{}
+		for _, app := range b.Apps {
+			if app.URL != url {
+				continue
+			}
+			for _, fn := range app.Functions {
+				out = append(out, fn.Slug)
+			}
+		}
+		return out
+	}
+	require.Equal(t, []string{"env-default-fn"}, slugs(ctx))
+	require.Equal(t, []string{"env-branch-fn"}, slugs(branch))
+
+	// Events only trigger functions within their environment.
+	wsID := environment.WorkspaceIDFromContext(branch)
+	fns, err := data.FunctionsByTrigger(ctx, wsID, "test/env")
+	require.NoError(t, err)
+	require.Len(t, fns, 1)
+	require.Equal(t, "env-branch-fn", fns[0].Slug)
+
+	found, err := data.FunctionWorkspaceID(ctx, fns[0].ID)
+	require.NoError(t, err)
+	require.Equal(t, wsID, found)
+}
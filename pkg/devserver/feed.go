@@ -0,0 +1,143 @@
+package devserver
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/inngest/inngest/pkg/event"
+	"github.com/inngest/inngest/pkg/logger"
+	"github.com/inngest/inngest/pkg/pubsub"
+)
+
+const (
+	// feedBufferSize is the number of events buffered for each feed subscriber.
+	// Events are dropped for subscribers that fall further behind.
+	feedBufferSize = 100
+	// feedPingInterval is how often pings are sent to keep connections alive.
+	feedPingInterval = 30 * time.Second
+)
+
+var feedUpgrader = websocket.Upgrader{
+	// The dev server UI and CLIs may connect from any origin.
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// eventFeed broadcasts accepted events to live subscribers, allowing the dev
+// server UI and CLIs to tail events without polling.
+type eventFeed struct {
+	l    sync.Mutex
+	subs map[chan event.Event]string
+}
+
+func newEventFeed() *eventFeed {
+	return &eventFeed{subs: map[chan event.Event]string{}}
+}
+
+// subscribe returns a channel which receives every event whose name starts with
+// the given prefix, and a func to unsubscribe.
+func (f *eventFeed) subscribe(prefix string) (<-chan event.Event, func()) {
+	ch := make(chan event.Event, feedBufferSize)
+	f.l.Lock()
+	f.subs[ch] = prefix
+	f.l.Unlock()
+
+	return ch, func() {
+		f.l.Lock()
+		delete(f.subs, ch)
+		f.l.Unlock()
+	}
+}
+
+// publish sends the event to all matching subscribers without blocking.
+func (f *eventFeed) publish(evt event.Event) {
+	f.l.Lock()
+	defer f.l.Unlock()
+	for ch, prefix := range f.subs {
+		if !strings.HasPrefix(evt.Name, prefix) {
+			continue
+		}
+		select {
+		case ch <- evt:
+		default:
+			// The subscriber is too slow;  drop the event rather than blocking
+			// other subscribers.
+		}
+	}
+}
+
+// streamEvents subscribes to the event stream, publishing every accepted event
+// to the dev server's event feed.  This blocks until the context is cancelled.
+func (d *devserver) streamEvents(ctx context.Context) {
+	sub, err := pubsub.NewSubscriber(ctx, d.opts.Config.EventStream.Service)
+	if err != nil {
+		logger.From(ctx).Error().Err(err).Msg("error creating event feed subscriber")
+		return
+	}
+
+	err = sub.Subscribe(ctx, d.opts.Config.EventStream.Service.TopicName(), func(ctx context.Context, m pubsub.Message) error {
+		if m.Name != event.EventReceivedName {
+			return nil
+		}
+		tracked, err := event.NewOSSTrackedEventFromString(m.Data)
+		if err != nil {
+			// Never retry malformed messages.
+			return nil
+		}
+		d.feed.publish(tracked.GetEvent())
+		return nil
+	})
+	if err != nil {
+		logger.From(ctx).Error().Err(err).Msg("error subscribing to event feed")
+	}
+}
+
+// EventFeed streams accepted events over a websocket as JSON, optionally
+// filtered by the given event name prefix, eg. /dev/events/feed?prefix=app/
+func (a devapi) EventFeed(w http.ResponseWriter, r *http.Request) {
+	conn, err := feedUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade writes the error response.
+		return
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	evts, unsubscribe := a.devserver.feed.subscribe(r.URL.Query().Get("prefix"))
+	defer unsubscribe()
+
+	// Read and discard client messages so that close frames are handled, and stop
+	// streaming once the client disconnects.
+	go func() {
+		defer cancel()
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ping := time.NewTicker(feedPingInterval)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ping.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(time.Second)); err != nil {
+				return
+			}
+		case evt := <-evts:
+			if err := conn.WriteJSON(evt); err != nil {
+				logger.From(ctx).Debug().Err(err).Msg("error writing to event feed")
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package devserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/inngest/inngest/pkg/event"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventFeed(t *testing.T) {
+	t.Run("It filters events by name prefix", func(t *testing.T) {
+		f := newEventFeed()
+		all, unsubAll := f.subscribe("")
+		defer unsubAll()
+		app, unsubApp := f.subscribe("app/")
+
+		f.publish(event.Event{Name: "app/user.created"})
+		f.publish(event.Event{Name: "other/user.created"})
+
+		require.Len(t, all, 2)
+		require.Len(t, app, 1)
+		require.Equal(t, "app/user.created", (<-app).Name)
+
+		unsubApp()
+		f.publish(event.Event{Name: "app/user.deleted"})
+		require.Len(t, app, 0)
+		require.Len(t, all, 3)
+	})
+
+	t.Run("It streams events over a websocket", func(t *testing.T) {
+		d := newService(StartOpts{}, nil, nil, nil)
+		api := devapi{devserver: d}
+		srv := httptest.NewServer(http.HandlerFunc(api.EventFeed))
+		defer srv.Close()
+
+		url := "ws" + strings.TrimPrefix(srv.URL, "http") + "?prefix=app/"
+		conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+		require.NoError(t, err)
+		defer conn.Close()
+
+		require.Eventually(t, func() bool {
+			d.feed.l.Lock()
+			defer d.feed.l.Unlock()
+			return len(d.feed.subs) == 1
+		}, time.Second, 10*time.Millisecond)
+
+		d.feed.publish(event.Event{Name: "other/user.created"})
+		d.feed.publish(event.Event{Name: "app/user.created", Data: map[string]any{"id": "1"}})
+
+		var evt event.Event
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+		require.NoError(t, conn.ReadJSON(&evt))
+		require.Equal(t, "app/user.created", evt.Name)
+		require.Equal(t, "1", evt.Data["id"])
+
+		// Disconnecting unsubscribes from the feed.
+		conn.Close()
+		require.Eventually(t, func() bool {
+			d.feed.l.Lock()
+			defer d.feed.l.Unlock()
+			return len(d.feed.subs) == 0
+		}, time.Second, 10*time.Millisecond)
+	})
+}
@@ -0,0 +1,140 @@
+package devserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/inngest/inngest/pkg/cqrs"
+	"github.com/inngest/inngest/pkg/execution/queue"
+	"github.com/oklog/ulid/v2"
+)
+
+const (
+	// healthCheckTimeout is the maximum time each component check may take.
+	healthCheckTimeout = 5 * time.Second
+	// healthMinScanAge is the minimum time allowed since the queue's last scan
+	// before the executor is reported as unhealthy.
+	healthMinScanAge = 30 * time.Second
+
+	HealthStatusOK          = "ok"
+	HealthStatusUnavailable = "unavailable"
+)
+
+// Health reports the status of each of the dev server's components.
+type Health struct {
+	// Status is "ok" if every component is healthy, or "unavailable" otherwise.
+	Status     string                     `json:"status"`
+	Components map[string]ComponentHealth `json:"components"`
+}
+
+// ComponentHealth reports the status of a single component, eg. the state store.
+type ComponentHealth struct {
+	Healthy bool `json:"healthy"`
+	// Latency is the round trip time of the component's check, in milliseconds.
+	Latency float64 `json:"latency_ms"`
+	Error   string  `json:"error,omitempty"`
+	// Liveness is the queue's last scan and lease, reported for the executor.
+	Liveness *queue.Liveness `json:"liveness,omitempty"`
+}
+
+// liveness checks that the executor's queue loop is still scanning for jobs.  A
+// restart is the only remedy for a stalled loop, so this is used for /healthz.
+func (d *devserver) liveness(ctx context.Context, now time.Time) Health {
+	h := Health{Components: map[string]ComponentHealth{}}
+	if reporter, ok := d.queue.(queue.HealthReporter); ok {
+		h.Components["executor"] = d.checkExecutor(reporter, now)
+	}
+	return h.summarize()
+}
+
+// readiness checks each component required to accept events and run functions,
+// used for /readyz.
+func (d *devserver) readiness(ctx context.Context, now time.Time) Health {
+	h := d.liveness(ctx, now)
+
+	h.Components["state"] = checkComponent(ctx, func(ctx context.Context) error {
+		// Check the state store with a read for a run which doesn't exist.
+		_, err := d.state.Exists(ctx, ulid.Make())
+		return err
+	})
+	if reporter, ok := d.queue.(queue.HealthReporter); ok {
+		h.Components["queue"] = checkComponent(ctx, reporter.Ping)
+	}
+	if pinger, ok := d.data.(cqrs.Pinger); ok {
+		h.Components["database"] = checkComponent(ctx, pinger.Ping)
+	}
+	return h.summarize()
+}
+
+// checkExecutor reports the executor as unhealthy if the queue hasn't scanned
+// for jobs recently.
+func (d *devserver) checkExecutor(reporter queue.HealthReporter, now time.Time) ComponentHealth {
+	l := reporter.Liveness()
+	c := ComponentHealth{Healthy: true, Liveness: &l}
+
+	maxAge := healthMinScanAge
+	if tick := 100 * d.opts.Tick; tick > maxAge {
+		maxAge = tick
+	}
+	switch age := now.Sub(l.LastScanAt); {
+	case l.LastScanAt.IsZero():
+		c.Healthy = false
+		c.Error = "The queue has not started scanning for jobs"
+	case age > maxAge:
+		c.Healthy = false
+		c.Error = fmt.Sprintf("The queue last scanned for jobs %s ago", age.Round(time.Second))
+	}
+	return c
+}
+
+// checkComponent runs the check, recording its latency and any error.
+func checkComponent(ctx context.Context, check func(ctx context.Context) error) ComponentHealth {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check(ctx)
+	c := ComponentHealth{
+		Healthy: err == nil,
+		Latency: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		c.Error = err.Error()
+	}
+	return c
+}
+
+// summarize sets the overall status from each component.
+func (h Health) summarize() Health {
+	h.Status = HealthStatusOK
+	for _, c := range h.Components {
+		if !c.Healthy {
+			h.Status = HealthStatusUnavailable
+		}
+	}
+	return h
+}
+
+// GetHealth reports whether the executor's queue loop is alive, returning a 503
+// if the loop has stalled.
+func (a devapi) GetHealth(w http.ResponseWriter, r *http.Request) {
+	writeHealth(w, a.devserver.liveness(r.Context(), time.Now()))
+}
+
+// GetReadiness reports whether the state store, queue, database and executor are
+// each available, returning a 503 if any component is unhealthy.
+func (a devapi) GetReadiness(w http.ResponseWriter, r *http.Request) {
+	writeHealth(w, a.devserver.readiness(r.Context(), time.Now()))
+}
+
+func writeHealth(w http.ResponseWriter, h Health) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	if h.Status != HealthStatusOK {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	_ = json.NewEncoder(w).Encode(h)
+}
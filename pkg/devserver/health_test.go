@@ -0,0 +1,82 @@
+package devserver
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/inngest/inngest/pkg/cqrs/sqlitecqrs"
+	"github.com/inngest/inngest/pkg/execution/queue"
+	"github.com/stretchr/testify/require"
+)
+
+// healthQueue is a queue.Queue which only implements queue.HealthReporter.
+type healthQueue struct {
+	queue.Queue
+	liveness queue.Liveness
+	err      error
+}
+
+func (h healthQueue) Ping(ctx context.Context) error { return h.err }
+
+func (h healthQueue) Liveness() queue.Liveness { return h.liveness }
+
+func TestHealth(t *testing.T) {
+	ctx := context.Background()
+	db, err := sqlitecqrs.New()
+	require.NoError(t, err)
+	now := time.Now()
+
+	d := &devserver{
+		data:  sqlitecqrs.NewCQRS(db),
+		state: existsState{},
+		queue: healthQueue{liveness: queue.Liveness{LastScanAt: now.Add(-time.Second)}},
+	}
+
+	h := d.readiness(ctx, now)
+	require.Equal(t, HealthStatusOK, h.Status)
+	require.ElementsMatch(t, []string{"executor", "state", "queue", "database"}, keys(h.Components))
+
+	t.Run("Stalled queues fail liveness", func(t *testing.T) {
+		d.queue = healthQueue{liveness: queue.Liveness{LastScanAt: now.Add(-time.Hour)}}
+		h := d.liveness(ctx, now)
+		require.Equal(t, HealthStatusUnavailable, h.Status)
+		require.Len(t, h.Components, 1)
+		require.False(t, h.Components["executor"].Healthy)
+		require.Contains(t, h.Components["executor"].Error, "1h0m0s ago")
+
+		d.queue = healthQueue{}
+		h = d.liveness(ctx, now)
+		require.Equal(t, HealthStatusUnavailable, h.Status)
+		require.Contains(t, h.Components["executor"].Error, "not started")
+	})
+
+	t.Run("Unavailable components fail readiness only", func(t *testing.T) {
+		d.queue = healthQueue{liveness: queue.Liveness{LastScanAt: now}, err: fmt.Errorf("connection refused")}
+		d.state = existsState{err: fmt.Errorf("connection refused")}
+		require.Equal(t, HealthStatusOK, d.liveness(ctx, now).Status)
+
+		h := d.readiness(ctx, now)
+		require.Equal(t, HealthStatusUnavailable, h.Status)
+		require.True(t, h.Components["executor"].Healthy)
+		require.True(t, h.Components["database"].Healthy)
+		require.Equal(t, "connection refused", h.Components["queue"].Error)
+		require.Equal(t, "connection refused", h.Components["state"].Error)
+
+		rec := httptest.NewRecorder()
+		devapi{devserver: d}.GetReadiness(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+		require.Equal(t, http.StatusServiceUnavailable, rec.Code)
+		require.Contains(t, rec.Body.String(), `"status":"unavailable"`)
+	})
+}
+
+func keys[T any](m map[string]T) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	return out
+}
@@ -0,0 +1,180 @@
+package devserver
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/inngest/inngest/pkg/cqrs"
+	"github.com/inngest/inngest/pkg/devserver/environment"
+	"github.com/inngest/inngest/pkg/enums"
+	"github.com/inngest/inngest/pkg/event"
+	"github.com/inngest/inngest/pkg/publicerr"
+	"github.com/oklog/ulid/v2"
+	"github.com/xhit/go-str2duration/v2"
+)
+
+const (
+	// defaultInvokeTimeout is the time that a blocking invoke waits for the
+	// run to finish if no timeout is specified.
+	defaultInvokeTimeout = 30 * time.Second
+	// maxInvokeTimeout is the maximum time that a blocking invoke may wait.
+	maxInvokeTimeout = 15 * time.Minute
+	// invokePollInterval is how often the run is checked while waiting for it
+	// to finish.
+	invokePollInterval = 100 * time.Millisecond
+)
+
+// InvokeRequest is the request body used to invoke a function.
+type InvokeRequest struct {
+	// ID is an optional idempotency ID for the invocation event.
+	ID   string         `json:"id,omitempty"`
+	Data map[string]any `json:"data,omitempty"`
+	User map[string]any `json:"user,omitempty"`
+}
+
+// InvokeResponse is returned when invoking a function.
+type InvokeResponse struct {
+	// EventID is the internal ID of the invocation event.
+	EventID string `json:"event_id"`
+	// RunID is the ID of the run, if the run was scheduled before the response
+	// was returned.  This is always set when waiting for the run.
+	RunID *ulid.ULID `json:"run_id,omitempty"`
+	// Status is the run's status, if the run has been scheduled.
+	Status string `json:"status,omitempty"`
+	// Output is the run's output, if the run finished.
+	Output json.RawMessage `json:"output,omitempty"`
+}
+
+// Invoke invokes a function by its slug, eg. POST /fn/my-app-my-fn/invoke.  The
+// request body is used as the invocation event's payload.
+//
+// By default this returns as soon as the invocation event is sent.  Passing
+// ?wait=true blocks until the run finishes and returns its output, up to the
+// ?timeout duration (eg. "2m"), defaulting to 30 seconds.  If the run hasn't
+// finished within the timeout this returns a 202 with the run's current status.
+func (a devapi) Invoke(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	ctx := r.Context()
+	slug := chi.URLParam(r, "slug")
+
+	wait := r.URL.Query().Get("wait") == "true"
+	timeout := defaultInvokeTimeout
+	if t := r.URL.Query().Get("timeout"); t != "" {
+		parsed, err := str2duration.ParseDuration(t)
+		if err != nil || parsed <= 0 {
+			a.err(ctx, w, 400, fmt.Errorf("Invalid timeout: %s", t))
+			return
+		}
+		timeout = min(parsed, maxInvokeTimeout)
+	}
+
+	req := InvokeRequest{}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			a.err(ctx, w, 400, fmt.Errorf("Invalid request: %w", err))
+			return
+		}
+	}
+
+	if err := a.findFunction(ctx, slug); err != nil {
+		_ = publicerr.WriteHTTP(w, err)
+		return
+	}
+
+	evt := event.NewInvocationEvent(event.NewInvocationEventOpts{
+		Event: event.Event{
+			ID:   req.ID,
+			Data: req.Data,
+			User: req.User,
+		},
+		FnID: slug,
+	})
+	id, err := a.devserver.handleEvent(ctx, &evt)
+	if err != nil {
+		_ = publicerr.WriteHTTP(w, publicerr.Wrap(err, 500, "Error sending invocation event"))
+		return
+	}
+
+	resp := InvokeResponse{EventID: id}
+	status := http.StatusOK
+	if wait {
+		eventID, err := ulid.Parse(id)
+		if err != nil {
+			_ = publicerr.WriteHTTP(w, publicerr.Wrap(err, 500, "Invalid invocation event ID"))
+			return
+		}
+		run, err := a.waitForRun(ctx, eventID, timeout)
+		if err != nil {
+			_ = publicerr.WriteHTTP(w, publicerr.Wrap(err, 500, "Error loading run"))
+			return
+		}
+		if run == nil || run.EndedAt == nil {
+			status = http.StatusAccepted
+		}
+		if run != nil {
+			resp.RunID = &run.RunID
+			resp.Status = run.Status.String()
+			resp.Output = run.Output
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// findFunction returns a 404 error if no function with the given slug has been
+// registered within the context's environment.
+func (a devapi) findFunction(ctx context.Context, slug string) error {
+	_, err := a.devserver.data.GetFunctionByExternalID(ctx, environment.WorkspaceIDFromContext(ctx), "", slug)
+	if errors.Is(err, sql.ErrNoRows) {
+		return publicerr.Errorf(404, "Function not found: %s", slug)
+	}
+	if err != nil {
+		return publicerr.Wrap(err, 500, "Error loading functions")
+	}
+	return nil
+}
+
+// waitForRun waits for the run triggered by the given event to finish, returning
+// the latest state of the run once it finishes or the timeout elapses.  This
+// returns a nil run if the run wasn't scheduled within the timeout.
+func (a devapi) waitForRun(ctx context.Context, eventID ulid.ULID, timeout time.Duration) (*cqrs.FunctionRun, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	t := time.NewTicker(invokePollInterval)
+	defer t.Stop()
+
+	var run *cqrs.FunctionRun
+	for {
+		select {
+		case <-ctx.Done():
+			return run, nil
+		case <-t.C:
+		}
+
+		// The dev server has no accounts.
+		runs, err := a.devserver.data.GetFunctionRunsFromEvents(ctx, uuid.UUID{}, environment.WorkspaceIDFromContext(ctx), []ulid.ULID{eventID})
+		if err != nil {
+			if ctx.Err() != nil {
+				return run, nil
+			}
+			return nil, err
+		}
+		if len(runs) == 0 {
+			continue
+		}
+		run = runs[0]
+		if run.EndedAt != nil && run.Status != enums.RunStatusRunning {
+			return run, nil
+		}
+	}
+}
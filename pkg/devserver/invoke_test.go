@@ -0,0 +1,63 @@
+package devserver
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/inngest/inngest/pkg/cqrs"
+	"github.com/inngest/inngest/pkg/cqrs/sqlitecqrs"
+	"github.com/inngest/inngest/pkg/cqrs/sqlitecqrs/sqlc"
+	"github.com/inngest/inngest/pkg/enums"
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWaitForRun(t *testing.T) {
+	ctx := context.Background()
+	db, err := sqlitecqrs.New()
+	require.NoError(t, err)
+	data := sqlitecqrs.NewCQRS(db)
+	a := devapi{devserver: &devserver{data: data}}
+
+	eventID := ulid.MustNew(ulid.Now(), rand.Reader)
+
+	// The run hasn't been scheduled.
+	run, err := a.waitForRun(ctx, eventID, 250*time.Millisecond)
+	require.NoError(t, err)
+	require.Nil(t, run)
+
+	runID := ulid.MustNew(ulid.Now(), rand.Reader)
+	require.NoError(t, data.InsertFunctionRun(ctx, cqrs.FunctionRun{
+		RunID:        runID,
+		RunStartedAt: time.Now(),
+		EventID:      eventID,
+	}))
+
+	// The run is in progress.
+	run, err = a.waitForRun(ctx, eventID, 250*time.Millisecond)
+	require.NoError(t, err)
+	require.Equal(t, runID, run.RunID)
+	require.Nil(t, run.EndedAt)
+
+	finished := make(chan error, 1)
+	go func() {
+		<-time.After(200 * time.Millisecond)
+		finished <- sqlc.New(db).InsertFunctionFinish(ctx, sqlc.InsertFunctionFinishParams{
+			RunID:              runID,
+			Status:             sql.NullString{String: enums.RunStatusCompleted.String(), Valid: true},
+			CreatedAt:          sql.NullTime{Time: time.Now(), Valid: true},
+			Output:             sql.NullString{String: `{"ok":true}`, Valid: true},
+			CompletedStepCount: sql.NullInt64{Int64: 1, Valid: true},
+		})
+	}()
+
+	run, err = a.waitForRun(ctx, eventID, 5*time.Second)
+	require.NoError(t, err)
+	require.NoError(t, <-finished)
+	require.NotNil(t, run.EndedAt)
+	require.Equal(t, enums.RunStatusCompleted, run.Status)
+	require.JSONEq(t, `{"ok":true}`, string(run.Output))
+}
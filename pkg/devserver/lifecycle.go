@@ -2,11 +2,17 @@ package devserver
 
 import (
 	"context"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/inngest/inngest/pkg/cqrs"
+	"github.com/inngest/inngest/pkg/enums"
 	"github.com/inngest/inngest/pkg/execution"
 	"github.com/inngest/inngest/pkg/execution/queue"
 	"github.com/inngest/inngest/pkg/execution/state"
+	"github.com/inngest/inngest/pkg/execution/state/redis_state"
+	"github.com/inngest/inngest/pkg/inngest"
+	"github.com/inngest/inngest/pkg/logger"
 	"github.com/inngest/inngest/pkg/pubsub"
 	"github.com/oklog/ulid/v2"
 )
@@ -30,6 +36,7 @@ func (l lifecycle) OnFunctionScheduled(
 		RunID:         id.RunID,
 		RunStartedAt:  ulid.Time(id.RunID.Time()),
 		FunctionID:    id.WorkflowID,
+		WorkspaceID:   id.WorkspaceID,
 		EventID:       id.EventID,
 		Cron:          s.CronSchedule(),
 		OriginalRunID: id.OriginalRunID,
@@ -54,3 +61,121 @@ func (l lifecycle) OnFunctionScheduled(
 		}
 	}
 }
+
+func (l lifecycle) OnStepFinished(
+	ctx context.Context,
+	id state.Identifier,
+	item queue.Item,
+	edge inngest.Edge,
+	step inngest.Step,
+	resp state.DriverResponse,
+) {
+	logs := []cqrs.StepLog{}
+	add := func(stepID string, lines []state.LogLine) {
+		for _, line := range lines {
+			if line.Timestamp.IsZero() {
+				line.Timestamp = time.Now()
+			}
+			logs = append(logs, cqrs.StepLog{
+				RunID:     id.RunID,
+				StepID:    stepID,
+				Attempt:   item.Attempt,
+				Level:     line.Level,
+				Message:   line.Message,
+				Fields:    line.Fields,
+				Timestamp: line.Timestamp,
+			})
+		}
+	}
+	for _, op := range resp.Generator {
+		if op == nil {
+			continue
+		}
+		add(op.ID, op.Logs)
+	}
+	// Responses which error outside of a step, or which the SDK didn't
+	// respond to with opcodes, carry their logs on the response itself.
+	stepID := step.ID
+	if edge.IncomingGeneratorStep != "" {
+		stepID = edge.IncomingGeneratorStep
+	}
+	add(stepID, resp.Logs)
+	if len(logs) == 0 {
+		return
+	}
+	if err := l.cqrs.InsertStepLogs(ctx, logs); err != nil {
+		logger.From(ctx).Error().Err(err).Str("run_id", id.RunID.String()).Msg("error storing step logs")
+	}
+}
+
+func (l lifecycle) OnFunctionStarted(ctx context.Context, id state.Identifier, item queue.Item, s state.State) {
+	l.syncStatus(ctx, id)
+}
+
+func (l lifecycle) OnStepStarted(
+	ctx context.Context,
+	id state.Identifier,
+	item queue.Item,
+	edge inngest.Edge,
+	step inngest.Step,
+	s state.State,
+) {
+	l.syncStatus(ctx, id)
+}
+
+func (l lifecycle) OnSleep(ctx context.Context, id state.Identifier, item queue.Item, gen state.GeneratorOpcode, until time.Time) {
+	l.syncStatus(ctx, id)
+}
+
+func (l lifecycle) OnWaitForEvent(ctx context.Context, id state.Identifier, item queue.Item, gen state.GeneratorOpcode) {
+	l.syncStatus(ctx, id)
+}
+
+func (l lifecycle) OnInvokeFunction(
+	ctx context.Context,
+	id state.Identifier,
+	item queue.Item,
+	gen state.GeneratorOpcode,
+	eventID ulid.ULID,
+	correlationID string,
+) {
+	l.syncStatus(ctx, id)
+}
+
+// syncStatus copies the run's status from the state store, such that runs list
+// why they aren't progressing, eg. when sleeping.  Lifecycles are called
+// concurrently, so the latest status is always loaded rather than set directly.
+func (l lifecycle) syncStatus(ctx context.Context, id state.Identifier) {
+	md, err := l.sm.Metadata(ctx, id.RunID)
+	if err != nil || enums.RunStatusEnded(md.Status) {
+		// Finished runs store their status when finishing.
+		return
+	}
+	if err := l.cqrs.UpdateFunctionRunStatus(ctx, id.RunID, md.Status); err != nil {
+		logger.From(ctx).Error().Err(err).Str("run_id", id.RunID.String()).Msg("error updating run status")
+	}
+}
+
+// throttleLifecycle marks scheduled runs as throttled when their first step
+// can't start due to the function's throttle.
+type throttleLifecycle struct {
+	sm   state.Manager
+	cqrs cqrs.Manager
+}
+
+func (throttleLifecycle) OnConcurrencyLimitReached(ctx context.Context, fnID uuid.UUID) {}
+
+func (l throttleLifecycle) OnThrottled(ctx context.Context, item redis_state.QueueItem) {
+	if item.Data.Kind != queue.KindStart {
+		return
+	}
+	id := item.Data.Identifier
+	ok, err := state.TransitionStatus(ctx, l.sm, id, enums.RunStatusThrottled, enums.RunStatusScheduled)
+	if err != nil || !ok {
+		// The run has already been marked as throttled, or has started.
+		return
+	}
+	if err := l.cqrs.UpdateFunctionRunStatus(ctx, id.RunID, enums.RunStatusThrottled); err != nil {
+		logger.From(ctx).Error().Err(err).Str("run_id", id.RunID.String()).Msg("error updating run status")
+	}
+}
@@ -0,0 +1,113 @@
+package devserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/inngest/inngest/pkg/devserver/environment"
+	"github.com/inngest/inngest/pkg/event"
+	"github.com/inngest/inngest/pkg/execution"
+	"github.com/inngest/inngest/pkg/execution/driver/recorder"
+	"github.com/inngest/inngest/pkg/publicerr"
+	"github.com/oklog/ulid/v2"
+)
+
+// recordedRuns is the maximum number of runs whose SDK requests are recorded.
+// Recordings for the least recently used runs are dropped.
+const recordedRuns = 1_000
+
+var errRecordingDisabled = fmt.Errorf("recording is disabled; start the dev server with --record")
+
+// GetRecording returns the SDK requests and responses recorded for a run.
+func (a devapi) GetRecording(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if a.devserver.recordings == nil {
+		_ = publicerr.WriteHTTP(w, publicerr.Wrap(errRecordingDisabled, 400, "Recording is disabled"))
+		return
+	}
+	runID, err := ulid.Parse(chi.URLParam(r, "runID"))
+	if err != nil {
+		_ = publicerr.WriteHTTP(w, publicerr.Wrap(err, 400, "Invalid run ID"))
+		return
+	}
+	exchanges, err := a.devserver.recordings.Exchanges(ctx, runID)
+	if err != nil {
+		_ = publicerr.WriteHTTP(w, publicerr.Wrap(err, 500, "Error loading recording"))
+		return
+	}
+	if exchanges == nil {
+		exchanges = []recorder.Exchange{}
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_ = json.NewEncoder(w).Encode(exchanges)
+}
+
+// ReplayRun starts a new run of a recorded run's function and event, which is
+// executed against the recorded SDK responses without calling the SDK.
+func (a devapi) ReplayRun(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if a.devserver.recordings == nil {
+		_ = publicerr.WriteHTTP(w, publicerr.Wrap(errRecordingDisabled, 400, "Recording is disabled"))
+		return
+	}
+	runID, err := ulid.Parse(chi.URLParam(r, "runID"))
+	if err != nil {
+		_ = publicerr.WriteHTTP(w, publicerr.Wrap(err, 400, "Invalid run ID"))
+		return
+	}
+
+	exchanges, err := a.devserver.recordings.Exchanges(ctx, runID)
+	if err != nil {
+		_ = publicerr.WriteHTTP(w, publicerr.Wrap(err, 500, "Error loading recording"))
+		return
+	}
+	if len(exchanges) == 0 {
+		_ = publicerr.WriteHTTP(w, publicerr.Wrap(fmt.Errorf("run %s has no recording", runID), 404, "Run has no recording"))
+		return
+	}
+
+	// The dev server has no accounts.
+	wsID := environment.WorkspaceIDFromContext(ctx)
+	run, err := a.devserver.data.GetFunctionRun(ctx, uuid.UUID{}, wsID, runID)
+	if err != nil {
+		_ = publicerr.WriteHTTP(w, publicerr.Wrap(err, 404, "Run not found"))
+		return
+	}
+	fnCQRS, err := a.devserver.data.GetFunctionByInternalUUID(ctx, wsID, run.FunctionID)
+	if err != nil {
+		_ = publicerr.WriteHTTP(w, publicerr.Wrap(err, 404, "Function not found"))
+		return
+	}
+	fn, err := fnCQRS.InngestFunction()
+	if err != nil {
+		_ = publicerr.WriteHTTP(w, publicerr.Wrap(err, 500, "Error loading function"))
+		return
+	}
+	evt, err := a.devserver.data.GetEventByInternalID(ctx, run.EventID)
+	if err != nil {
+		_ = publicerr.WriteHTTP(w, publicerr.Wrap(err, 404, "Event not found"))
+		return
+	}
+
+	id, err := a.devserver.executor.Schedule(ctx, execution.ScheduleRequest{
+		Function:    *fn,
+		WorkspaceID: fnCQRS.WorkspaceID,
+		Events: []event.TrackedEvent{
+			event.NewOSSTrackedEventWithID(evt.Event(), evt.InternalID()),
+		},
+		OriginalRunID: &run.RunID,
+		Context: map[string]any{
+			recorder.ReplayContextKey: runID.String(),
+		},
+	})
+	if err != nil {
+		_ = publicerr.WriteHTTP(w, publicerr.Wrap(err, 500, "Error replaying run"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_ = json.NewEncoder(w).Encode(map[string]any{"ok": true, "run_id": id.RunID})
+}
@@ -0,0 +1,39 @@
+package devserver
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/inngest/inngest/pkg/cqrs"
+	"github.com/inngest/inngest/pkg/execution/state"
+	"github.com/oklog/ulid/v2"
+)
+
+// runBatchReader loads run batches from the state store, so that batches are
+// available to SDKs for as long as the run's state exists.
+type runBatchReader struct {
+	state state.StateLoader
+}
+
+var _ cqrs.RunBatchReader = runBatchReader{}
+
+func (r runBatchReader) GetRunBatch(
+	ctx context.Context,
+	accountID uuid.UUID,
+	workspaceID uuid.UUID,
+	runID ulid.ULID,
+) (*cqrs.RunBatch, error) {
+	s, err := r.state.Load(ctx, runID)
+	if err != nil {
+		return nil, cqrs.ErrRunBatchNotFound
+	}
+	id := s.Identifier()
+	if id.WorkspaceID != workspaceID {
+		return nil, cqrs.ErrRunBatchNotFound
+	}
+	return &cqrs.RunBatch{
+		RunID:    runID,
+		EventIDs: id.EventIDs,
+		Events:   s.Events(),
+	}, nil
+}
@@ -0,0 +1,119 @@
+package devserver
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/inngest/inngest/pkg/devserver/discovery"
+)
+
+// sdkURLs tracks the SDK URLs that the dev server syncs, from the -u flag and an
+// optional URL file which is reloaded whenever it changes.
+type sdkURLs struct {
+	// flags lists the URLs passed via the -u flag.
+	flags []string
+	// path is the path to the URL file, if any.
+	path string
+	// modTime is the modification time of the URL file when it was last read.
+	modTime time.Time
+	// current lists every URL and pattern, from both flags and the URL file.
+	current map[string]struct{}
+}
+
+func newSDKURLs(flags []string, path string) *sdkURLs {
+	return &sdkURLs{flags: flags, path: path, current: map[string]struct{}{}}
+}
+
+// reload reloads the URL file if it has changed since the last reload, returning
+// the URLs and patterns added and removed since the last call.  The first call
+// returns every URL as added.
+func (s *sdkURLs) reload() (added, removed []string, err error) {
+	entries := append([]string{}, s.flags...)
+
+	if s.path != "" {
+		stat, err := os.Stat(s.path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error reading sdk url file: %w", err)
+		}
+		if !stat.ModTime().After(s.modTime) {
+			return nil, nil, nil
+		}
+		byt, err := os.ReadFile(s.path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error reading sdk url file: %w", err)
+		}
+		s.modTime = stat.ModTime()
+		entries = append(entries, parseURLFile(byt)...)
+	}
+
+	next := map[string]struct{}{}
+	for _, e := range entries {
+		e = normalizeSDKURL(e)
+		if !discovery.IsPattern(e) {
+			next[e] = struct{}{}
+			continue
+		}
+		if _, err := discovery.ExpandURL(e); err != nil {
+			return nil, nil, err
+		}
+		next[e] = struct{}{}
+	}
+
+	for u := range next {
+		if _, ok := s.current[u]; !ok {
+			added = append(added, u)
+		}
+	}
+	for u := range s.current {
+		if _, ok := next[u]; !ok {
+			removed = append(removed, u)
+		}
+	}
+	s.current = next
+	return added, removed, nil
+}
+
+// patterns returns the current URL patterns.
+func (s *sdkURLs) patterns() []string {
+	result := []string{}
+	for u := range s.current {
+		if discovery.IsPattern(u) {
+			result = append(result, u)
+		}
+	}
+	return result
+}
+
+// has returns whether the URL is currently listed.
+func (s *sdkURLs) has(u string) bool {
+	_, ok := s.current[u]
+	return ok
+}
+
+// parseURLFile returns the URLs within a URL file, which lists a URL or pattern on
+// each line.  Blank lines and lines starting with "#" are ignored.
+func parseURLFile(byt []byte) []string {
+	result := []string{}
+	scanner := bufio.NewScanner(bytes.NewReader(byt))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		result = append(result, line)
+	}
+	return result
+}
+
+// normalizeSDKURL adds a protocol to SDK URLs without one.  We use http, since very
+// few apps use https during development.
+func normalizeSDKURL(u string) string {
+	if !strings.Contains(u, "://") {
+		return "http://" + u
+	}
+	return u
+}
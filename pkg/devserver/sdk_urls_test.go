@@ -0,0 +1,44 @@
+package devserver
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSDKURLsReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "urls.txt")
+	require.NoError(t, os.WriteFile(path, []byte("# apps\nlocalhost:3000/api/inngest\n\nhttp://localhost:4000-4002/api/inngest\n"), 0600))
+
+	urls := newSDKURLs([]string{"http://localhost:5000/api/inngest"}, path)
+	added, removed, err := urls.reload()
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{
+		"http://localhost:5000/api/inngest",
+		"http://localhost:3000/api/inngest",
+		"http://localhost:4000-4002/api/inngest",
+	}, added)
+	require.Empty(t, removed)
+	require.Equal(t, []string{"http://localhost:4000-4002/api/inngest"}, urls.patterns())
+
+	// Nothing changes until the file is modified.
+	added, removed, err = urls.reload()
+	require.NoError(t, err)
+	require.Empty(t, added)
+	require.Empty(t, removed)
+
+	require.NoError(t, os.WriteFile(path, []byte("http://localhost:3001/api/inngest\n"), 0600))
+	later := time.Now().Add(time.Second)
+	require.NoError(t, os.Chtimes(path, later, later))
+
+	added, removed, err = urls.reload()
+	require.NoError(t, err)
+	require.Equal(t, []string{"http://localhost:3001/api/inngest"}, added)
+	require.ElementsMatch(t, []string{
+		"http://localhost:3000/api/inngest",
+		"http://localhost:4000-4002/api/inngest",
+	}, removed)
+}
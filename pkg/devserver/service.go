@@ -7,10 +7,10 @@ import (
 	"fmt"
 	"net"
 	"os"
-	"strings"
 	"sync"
 	"time"
 
+	"github.com/alicebob/miniredis/v2"
 	"github.com/coocood/freecache"
 	"github.com/eko/gocache/lib/v4/cache"
 	freecachestore "github.com/eko/gocache/store/freecache/v4"
@@ -24,11 +24,16 @@ import (
 	"github.com/inngest/inngest/pkg/cqrs"
 	"github.com/inngest/inngest/pkg/deploy"
 	"github.com/inngest/inngest/pkg/devserver/discovery"
+	"github.com/inngest/inngest/pkg/devserver/environment"
 	"github.com/inngest/inngest/pkg/event"
 	"github.com/inngest/inngest/pkg/execution"
+	"github.com/inngest/inngest/pkg/execution/batch"
+	"github.com/inngest/inngest/pkg/execution/driver/recorder"
 	"github.com/inngest/inngest/pkg/execution/queue"
+	"github.com/inngest/inngest/pkg/execution/ratelimit"
 	"github.com/inngest/inngest/pkg/execution/runner"
 	"github.com/inngest/inngest/pkg/execution/state"
+	"github.com/inngest/inngest/pkg/history_drivers/memory_reader"
 	"github.com/inngest/inngest/pkg/inngest/log"
 	"github.com/inngest/inngest/pkg/logger"
 	"github.com/inngest/inngest/pkg/pubsub"
@@ -44,6 +49,8 @@ func newService(opts StartOpts, runner runner.Runner, data cqrs.Manager, pb pubs
 		opts:        opts,
 		handlerLock: &sync.Mutex{},
 		publisher:   pb,
+		drains:      newAppDrains(),
+		feed:        newEventFeed(),
 	}
 }
 
@@ -65,12 +72,36 @@ type devserver struct {
 	queue     queue.Queue
 	executor  execution.Executor
 	publisher pubsub.Publisher
+	// rateLimiter enforces the event API's rate limits.
+	rateLimiter ratelimit.RateLimiter
+	// batcher batches events for functions which batch events.
+	batcher batch.BatchManager
+
+	// db and redis are the dev server's backing stores, used when
+	// snapshotting and restoring state.
+	db    *sql.DB
+	redis *miniredis.Miniredis
 
 	apiservice service.Service
 
 	// handlers are updated by the API (d.apiservice) when registering functions.
 	handlers    []SDKHandler
 	handlerLock *sync.Mutex
+
+	// drains tracks previous app deployments which are draining.
+	drains *appDrains
+
+	// feed broadcasts accepted events to websocket subscribers.
+	feed *eventFeed
+
+	// breakpoints halts runs before designated steps for debugging.
+	breakpoints *breakpoints
+
+	// recordings stores recorded SDK requests, if recording is enabled.
+	recordings recorder.Store
+
+	// timeline broadcasts each run's history to timeline subscribers.
+	timeline *runTimeline
 }
 
 func (devserver) Name() string {
@@ -88,12 +119,32 @@ func (d *devserver) Pre(ctx context.Context) error {
 		caching := apiv1.NewCacheMiddleware(cache)
 
 		apiv1.AddRoutes(r, apiv1.Opts{
+			// Requests act within the environment selected by each request.
+			AuthFinder:        environmentAuthFinder,
 			CachingMiddleware: caching,
 			EventReader:       d.data,
+			EventWriter:       d.data,
 			FunctionReader:    d.data,
 			FunctionRunReader: d.data,
 			JobQueueReader:    d.queue.(queue.JobQueueReader),
 			Executor:          d.executor,
+			EventKeyManager:   d.data,
+			FunctionArchiver:  d.data,
+			// Concurrency limits of queued jobs can be updated without
+			// re-syncing apps.
+			ConcurrencyLimitUpdater: d.queue.(queue.ConcurrencyLimitUpdater),
+			RequireEventKeys:        d.opts.Config.CoreAPI.RequireKeys,
+			// Cancellations are stored in the dev server's database and checked by
+			// the executor before each step.
+			CancellationReadWriter: d.data,
+			// Runs can be searched by their triggering event's payload.
+			FunctionRunSearcher: d.data,
+			// Run timelines are merged from the in-memory run history.
+			RunTimelineReader: memory_reader.NewReader(),
+			// Step outputs are loaded from state, falling back to run history.
+			StepOutputReader: stepOutputReader{state: d.state},
+			// SDKs fetch batches which are loaded lazily from the state store.
+			RunBatchReader: runBatchReader{state: d.state},
 		})
 	})
 
@@ -119,11 +170,21 @@ func (d *devserver) Pre(ctx context.Context) error {
 	//
 	// Merge the dev server API (for handling files & registration) with the data
 	// API into the event API router.
+	var checkEvent api.EventChecker
+	if checker, ok := d.batcher.(batch.OverflowChecker); ok && d.opts.Config.Execution.Batches.RejectOnLimit() {
+		// Events which can't be batched are rejected when they're sent.
+		checkEvent = checkBatchLimits(d.data, checker)
+	}
 	d.apiservice = api.NewService(
 		d.opts.Config,
-		api.Mount{At: "/", Router: devAPI},
-		api.Mount{At: "/v0", Router: core.Router},
-		api.Mount{At: "/debug", Handler: middleware.Profiler()},
+		api.WithMounts(
+			api.Mount{At: "/", Router: devAPI},
+			api.Mount{At: "/v0", Router: core.Router},
+			api.Mount{At: "/debug", Handler: middleware.Profiler()},
+		),
+		api.WithEventKeys(d.data),
+		api.WithRateLimiter(d.rateLimiter),
+		api.WithEventChecker(checkEvent),
 	)
 
 	// Autodiscover the URLs that are hosting Inngest SDKs on the local machine.
@@ -135,6 +196,8 @@ func (d *devserver) Pre(ctx context.Context) error {
 func (d *devserver) Run(ctx context.Context) error {
 	// Start polling the SDKs as the APIs are going live.
 	go d.pollSDKs(ctx)
+	// Stream accepted events to the live event feed.
+	go d.streamEvents(ctx)
 
 	// Add a nice output to the terminal.
 	if isatty.IsTerminal(os.Stdout.Fd()) {
@@ -189,6 +252,7 @@ func (d *devserver) runDiscovery(ctx context.Context) {
 		if d.opts.Autodiscover {
 			_ = discovery.Autodiscover(ctx)
 		}
+		_ = discovery.DiscoverPatterns(ctx)
 
 		<-time.After(pollInterval)
 	}
@@ -199,27 +263,10 @@ func (d *devserver) runDiscovery(ctx context.Context) {
 func (d *devserver) pollSDKs(ctx context.Context) {
 	pollInterval := time.Duration(d.opts.PollInterval) * time.Second
 
-	// Initially, add every app started with the `-u` flag
-	for _, url := range d.opts.URLs {
-		// URLs must contain a protocol. If not, add http since very few apps
-		// use https during development
-		if !strings.Contains(url, "://") {
-			url = "http://" + url
-		}
-
-		// Create a new app which holds the error message.
-		params := cqrs.InsertAppParams{
-			ID:  uuid.New(),
-			Url: url,
-			Error: sql.NullString{
-				Valid:  true,
-				String: deploy.DeployErrUnreachable.Error(),
-			},
-		}
-		if _, err := d.data.InsertApp(ctx, params); err != nil {
-			log.From(ctx).Error().Err(err).Msg("error inserting app from scan")
-		}
-	}
+	// Initially, add every app started with the `-u` flag or listed in the URL
+	// file.  The URL file is reloaded while polling.
+	urls := newSDKURLs(d.opts.URLs, d.opts.URLsFile)
+	d.syncSDKURLs(ctx, urls)
 
 	// Then poll for every added app (including apps added via the `-u` flag and via the
 	// UI), plus run autodiscovery.
@@ -228,11 +275,15 @@ func (d *devserver) pollSDKs(ctx context.Context) {
 			return
 		}
 
-		urls := map[string]struct{}{}
-		if apps, err := d.data.GetApps(ctx); err == nil {
+		d.syncSDKURLs(ctx, urls)
+
+		// SDKs sync within the default environment when pinged, so only apps
+		// within the default environment are polled.
+		seen := map[string]struct{}{}
+		if apps, err := d.data.GetApps(ctx, uuid.UUID{}); err == nil {
 			for _, app := range apps {
 				// We've seen this URL.
-				urls[app.Url] = struct{}{}
+				seen[app.Url] = struct{}{}
 
 				if !d.opts.Poll && len(app.Error.String) == 0 {
 					continue
@@ -254,27 +305,82 @@ func (d *devserver) pollSDKs(ctx context.Context) {
 		}
 
 		// Attempt to add new apps for each discovered URL that's _not_ already
-		// an app.
-		if d.opts.Autodiscover {
-			for u := range discovery.URLs() {
-				if _, ok := urls[u]; ok {
-					continue
-				}
+		// an app.  This includes URLs matching patterns from the `-u` flag, which
+		// are discovered even if autodiscovery is disabled.
+		for u := range discovery.URLs() {
+			if _, ok := seen[u]; ok {
+				continue
+			}
 
-				res := deploy.Ping(ctx, u)
+			res := deploy.Ping(ctx, u)
 
-				// If there was an SDK error then we should still ensure the app
-				// exists. Otherwise, users will have a harder time figuring out
-				// why the Dev Server can't find their app.
-				if res.Err != nil && res.IsSDK {
-					upsertErroredApp(ctx, d.data, u, res.Err)
-				}
+			// If there was an SDK error then we should still ensure the app
+			// exists. Otherwise, users will have a harder time figuring out
+			// why the Dev Server can't find their app.
+			if res.Err != nil && res.IsSDK {
+				upsertErroredApp(ctx, d.data, u, res.Err)
 			}
 		}
 		<-time.After(pollInterval)
 	}
 }
 
+// syncSDKURLs reloads the SDK URLs, creating apps for added URLs and deleting apps
+// for removed URLs within the default environment.  Apps are created with an error
+// until they sync, so that added apps are pinged immediately.  URL patterns are
+// discovered via runDiscovery.
+func (d *devserver) syncSDKURLs(ctx context.Context, urls *sdkURLs) {
+	added, removed, err := urls.reload()
+	if err != nil {
+		log.From(ctx).Warn().Err(err).Msg("error loading sdk urls")
+		return
+	}
+	if len(added) == 0 && len(removed) == 0 {
+		return
+	}
+
+	// Delete apps for URLs which were only discovered via removed patterns.
+	for _, url := range discovery.SetPatterns(urls.patterns()) {
+		if urls.has(url) {
+			continue
+		}
+		if app, err := d.data.GetAppByURL(ctx, uuid.UUID{}, url); err == nil {
+			_ = d.data.DeleteApp(ctx, app.ID)
+		}
+	}
+
+	for _, url := range removed {
+		if discovery.IsPattern(url) {
+			continue
+		}
+		if app, err := d.data.GetAppByURL(ctx, uuid.UUID{}, url); err == nil {
+			_ = d.data.DeleteApp(ctx, app.ID)
+		}
+	}
+
+	for _, url := range added {
+		if discovery.IsPattern(url) {
+			continue
+		}
+		if _, err := d.data.GetAppByURL(ctx, uuid.UUID{}, url); err == nil {
+			continue
+		}
+
+		// Create a new app which holds the error message.
+		params := cqrs.InsertAppParams{
+			ID:  uuid.New(),
+			Url: url,
+			Error: sql.NullString{
+				Valid:  true,
+				String: deploy.DeployErrUnreachable.Error(),
+			},
+		}
+		if _, err := d.data.InsertApp(ctx, params); err != nil {
+			log.From(ctx).Error().Err(err).Msg("error inserting app from scan")
+		}
+	}
+}
+
 func (d *devserver) handleEvent(ctx context.Context, e *event.Event) (string, error) {
 	// ctx is the request context, so we need to re-add
 	// the caller here.
@@ -283,7 +389,7 @@ func (d *devserver) handleEvent(ctx context.Context, e *event.Event) (string, er
 
 	l.Debug().Str("event", e.Name).Msg("handling event")
 
-	trackedEvent := event.NewOSSTrackedEvent(*e)
+	trackedEvent := event.NewOSSTrackedEventForWorkspace(*e, environment.WorkspaceIDFromContext(ctx))
 
 	byt, err := json.Marshal(trackedEvent)
 	if err != nil {
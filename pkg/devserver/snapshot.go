@@ -0,0 +1,392 @@
+package devserver
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/inngest/inngest/pkg/cqrs/sqlitecqrs"
+)
+
+const snapshotVersion = 1
+
+// Snapshot represents the entire state of a dev server at a point in time:  all
+// apps, functions, events and runs stored in the database plus all in-flight run
+// state, pauses and queue items stored in Redis.
+//
+// Snapshots are used to create reproducible bug reports and fixtures for
+// integration tests.
+type Snapshot struct {
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"createdAt"`
+	// Migration is the database's schema migration version.  Snapshots taken
+	// before later migrations are migrated when restored, while snapshots taken
+	// by newer dev servers can't be restored.
+	Migration uint `json:"migration"`
+	// Database is a copy of the dev server's SQLite database.
+	Database []byte `json:"database"`
+	// Redis contains every key stored in the dev server's in-memory Redis.
+	Redis []SnapshotKey `json:"redis"`
+}
+
+// SnapshotKey represents a single Redis key within a snapshot.
+type SnapshotKey struct {
+	Key  string `json:"key"`
+	Type string `json:"type"`
+	// TTL is the key's remaining time to live, or zero if the key does not expire.
+	TTL time.Duration `json:"ttl,omitempty"`
+
+	String string             `json:"string,omitempty"`
+	List   []string           `json:"list,omitempty"`
+	Set    []string           `json:"set,omitempty"`
+	Hash   map[string]string  `json:"hash,omitempty"`
+	ZSet   map[string]float64 `json:"zset,omitempty"`
+}
+
+// Snapshot captures the current state of the dev server.
+func (d *devserver) Snapshot(ctx context.Context) (*Snapshot, error) {
+	if d.db == nil || d.redis == nil {
+		return nil, fmt.Errorf("snapshots are not supported by this dev server")
+	}
+
+	migration, err := migrationVersion(ctx, d.db)
+	if err != nil {
+		return nil, fmt.Errorf("error reading database migration: %w", err)
+	}
+	byt, err := snapshotDatabase(ctx, d.db)
+	if err != nil {
+		return nil, fmt.Errorf("error snapshotting database: %w", err)
+	}
+	keys, err := snapshotRedis(d.redis)
+	if err != nil {
+		return nil, fmt.Errorf("error snapshotting redis: %w", err)
+	}
+
+	return &Snapshot{
+		Version:   snapshotVersion,
+		CreatedAt: time.Now(),
+		Migration: migration,
+		Database:  byt,
+		Redis:     keys,
+	}, nil
+}
+
+// Restore replaces the current state of the dev server with the given snapshot.
+func (d *devserver) Restore(ctx context.Context, s Snapshot) error {
+	if d.db == nil || d.redis == nil {
+		return fmt.Errorf("snapshots are not supported by this dev server")
+	}
+	if s.Version != snapshotVersion {
+		return fmt.Errorf("unsupported snapshot version: %d", s.Version)
+	}
+
+	d.handlerLock.Lock()
+	defer d.handlerLock.Unlock()
+
+	if err := d.restore(ctx, s); err != nil {
+		return err
+	}
+	// Reload crons for the restored functions.
+	if err := d.runner.InitializeCrons(ctx); err != nil {
+		return fmt.Errorf("error initializing crons: %w", err)
+	}
+	return nil
+}
+
+// RestoreFile restores the dev server from a snapshot file written by Snapshot.  This
+// is called on startup, prior to the runner initializing crons.
+func (d *devserver) RestoreFile(ctx context.Context, path string) error {
+	byt, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("error reading snapshot: %w", err)
+	}
+	s := Snapshot{}
+	if err := json.Unmarshal(byt, &s); err != nil {
+		return fmt.Errorf("error parsing snapshot: %w", err)
+	}
+	if s.Version != snapshotVersion {
+		return fmt.Errorf("unsupported snapshot version: %d", s.Version)
+	}
+	return d.restore(ctx, s)
+}
+
+func (d *devserver) restore(ctx context.Context, s Snapshot) error {
+	// Check the migration before restoring anything, so that a snapshot from a
+	// newer dev server leaves the current state untouched.
+	current, err := migrationVersion(ctx, d.db)
+	if err != nil {
+		return fmt.Errorf("error reading database migration: %w", err)
+	}
+	if s.Migration > current {
+		return fmt.Errorf("snapshot database migration %d is newer than this dev server's migration %d", s.Migration, current)
+	}
+	if err := restoreDatabase(ctx, d.db, s.Database); err != nil {
+		return fmt.Errorf("error restoring database: %w", err)
+	}
+	if err := restoreRedis(d.redis, s.Redis); err != nil {
+		return fmt.Errorf("error restoring redis: %w", err)
+	}
+	return nil
+}
+
+func snapshotDatabase(ctx context.Context, db *sql.DB) ([]byte, error) {
+	dir, err := os.MkdirTemp("", "inngest-snapshot-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "snapshot.db")
+	if _, err := db.ExecContext(ctx, "VACUUM INTO ?", path); err != nil {
+		return nil, err
+	}
+	return os.ReadFile(path)
+}
+
+// migrationVersion returns the database's schema migration version.
+func migrationVersion(ctx context.Context, db *sql.DB) (uint, error) {
+	var version uint
+	err := db.QueryRowContext(ctx, "SELECT version FROM migrations LIMIT 1").Scan(&version)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	return version, err
+}
+
+// migrateSnapshot migrates the snapshot's database to the current schema, so
+// that snapshots taken before later migrations can be restored.  Snapshots
+// from newer schemas are rejected.
+func migrateSnapshot(ctx context.Context, db *sql.DB, path string) error {
+	current, err := migrationVersion(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	snapshot, err := sql.Open("sqlite", path)
+	if err != nil {
+		return err
+	}
+	defer snapshot.Close()
+
+	version, err := migrationVersion(ctx, snapshot)
+	if err != nil {
+		return fmt.Errorf("error reading snapshot migration: %w", err)
+	}
+	switch {
+	case version > current:
+		return fmt.Errorf("snapshot database migration %d is newer than this dev server's migration %d", version, current)
+	case version < current:
+		if err := sqlitecqrs.Migrate(snapshot); err != nil {
+			return fmt.Errorf("error migrating snapshot from migration %d: %w", version, err)
+		}
+	}
+	return nil
+}
+
+// tableColumns returns the names of the table's columns within the given schema.
+func tableColumns(ctx context.Context, conn *sql.Conn, schema, table string) ([]string, error) {
+	rows, err := conn.QueryContext(ctx, fmt.Sprintf("SELECT name FROM pragma_table_info(%s, %s)", quoteLiteral(table), quoteLiteral(schema)))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns := []string{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		columns = append(columns, name)
+	}
+	return columns, rows.Err()
+}
+
+// restoreColumns returns the quoted columns to copy from the snapshot's table.
+// Columns are listed explicitly, as tables are copied by column name rather
+// than position.
+func restoreColumns(ctx context.Context, conn *sql.Conn, table string) (string, error) {
+	current, err := tableColumns(ctx, conn, "main", table)
+	if err != nil {
+		return "", err
+	}
+	snapshot, err := tableColumns(ctx, conn, "snapshot", table)
+	if err != nil {
+		return "", err
+	}
+
+	exists := make(map[string]bool, len(current))
+	for _, c := range current {
+		exists[c] = true
+	}
+	quoted := make([]string, 0, len(snapshot))
+	for _, c := range snapshot {
+		if !exists[c] {
+			return "", fmt.Errorf("snapshot column %s.%s does not exist", table, c)
+		}
+		quoted = append(quoted, fmt.Sprintf("%q", c))
+	}
+	return strings.Join(quoted, ", "), nil
+}
+
+func quoteLiteral(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+func restoreDatabase(ctx context.Context, db *sql.DB, byt []byte) error {
+	dir, err := os.MkdirTemp("", "inngest-snapshot-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "snapshot.db")
+	if err := os.WriteFile(path, byt, 0600); err != nil {
+		return err
+	}
+	if err := migrateSnapshot(ctx, db, path); err != nil {
+		return err
+	}
+
+	// ATTACH applies to a single connection, so pin one for the restore.
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "ATTACH DATABASE ? AS snapshot", path); err != nil {
+		return err
+	}
+	defer func() {
+		_, _ = conn.ExecContext(context.Background(), "DETACH DATABASE snapshot")
+	}()
+
+	rows, err := conn.QueryContext(
+		ctx,
+		"SELECT name FROM snapshot.sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' AND name != 'migrations'",
+	)
+	if err != nil {
+		return err
+	}
+	tables := []string{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return err
+		}
+		tables = append(tables, name)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	columns := make(map[string]string, len(tables))
+	for _, t := range tables {
+		if columns[t], err = restoreColumns(ctx, conn, t); err != nil {
+			return fmt.Errorf("error restoring table %s: %w", t, err)
+		}
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	for _, t := range tables {
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf("DELETE FROM main.%q", t)); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("error clearing table %s: %w", t, err)
+		}
+		query := fmt.Sprintf("INSERT INTO main.%q (%s) SELECT %s FROM snapshot.%q", t, columns[t], columns[t], t)
+		if _, err := tx.ExecContext(ctx, query); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("error restoring table %s: %w", t, err)
+		}
+	}
+	return tx.Commit()
+}
+
+func snapshotRedis(r *miniredis.Miniredis) ([]SnapshotKey, error) {
+	keys := r.Keys()
+	sort.Strings(keys)
+
+	result := make([]SnapshotKey, 0, len(keys))
+	for _, k := range keys {
+		item := SnapshotKey{
+			Key:  k,
+			Type: r.Type(k),
+			TTL:  r.TTL(k),
+		}
+
+		var err error
+		switch item.Type {
+		case "string":
+			item.String, err = r.Get(k)
+		case "list":
+			item.List, err = r.List(k)
+		case "set":
+			item.Set, err = r.Members(k)
+		case "hash":
+			var fields []string
+			if fields, err = r.HKeys(k); err == nil {
+				item.Hash = make(map[string]string, len(fields))
+				for _, f := range fields {
+					item.Hash[f] = r.HGet(k, f)
+				}
+			}
+		case "zset":
+			item.ZSet, err = r.SortedSet(k)
+		default:
+			return nil, fmt.Errorf("unsupported type for key %s: %s", k, item.Type)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading key %s: %w", k, err)
+		}
+		result = append(result, item)
+	}
+	return result, nil
+}
+
+func restoreRedis(r *miniredis.Miniredis, keys []SnapshotKey) error {
+	r.FlushAll()
+
+	for _, item := range keys {
+		var err error
+		switch item.Type {
+		case "string":
+			err = r.Set(item.Key, item.String)
+		case "list":
+			_, err = r.Push(item.Key, item.List...)
+		case "set":
+			_, err = r.SetAdd(item.Key, item.Set...)
+		case "hash":
+			for f, v := range item.Hash {
+				r.HSet(item.Key, f, v)
+			}
+		case "zset":
+			for member, score := range item.ZSet {
+				if _, err = r.ZAdd(item.Key, score, member); err != nil {
+					break
+				}
+			}
+		default:
+			err = fmt.Errorf("unsupported type: %s", item.Type)
+		}
+		if err != nil {
+			return fmt.Errorf("error restoring key %s: %w", item.Key, err)
+		}
+		if item.TTL > 0 {
+			r.SetTTL(item.Key, item.TTL)
+		}
+	}
+	return nil
+}
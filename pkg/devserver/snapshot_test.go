@@ -0,0 +1,141 @@
+package devserver
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/inngest/inngest/pkg/cqrs/sqlitecqrs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshotRedis(t *testing.T) {
+	r := miniredis.RunT(t)
+	require.NoError(t, r.Set("str", "value"))
+	r.SetTTL("str", time.Hour)
+	_, _ = r.Push("list", "a", "b")
+	_, _ = r.SetAdd("set", "a", "b")
+	r.HSet("hash", "f", "v")
+	_, _ = r.ZAdd("zset", 1.5, "m")
+
+	keys, err := snapshotRedis(r)
+	require.NoError(t, err)
+	require.Len(t, keys, 5)
+
+	r.FlushAll()
+	require.NoError(t, r.Set("other", "x"))
+	require.NoError(t, restoreRedis(r, keys))
+
+	require.False(t, r.Exists("other"))
+	val, _ := r.Get("str")
+	require.Equal(t, "value", val)
+	require.Equal(t, time.Hour, r.TTL("str"))
+	list, _ := r.List("list")
+	require.Equal(t, []string{"a", "b"}, list)
+	require.Equal(t, "v", r.HGet("hash", "f"))
+	score, _ := r.ZScore("zset", "m")
+	require.Equal(t, 1.5, score)
+}
+
+func TestSnapshotDatabase(t *testing.T) {
+	ctx := context.Background()
+	db, err := sqlitecqrs.New()
+	require.NoError(t, err)
+
+	_, err = db.ExecContext(ctx, "INSERT INTO events (internal_id, event_id, event_name, event_data, event_user, event_ts) VALUES (?, ?, ?, ?, ?, ?)", []byte("01"), "evt", "test/event", "{}", "{}", time.Now())
+	require.NoError(t, err)
+
+	byt, err := snapshotDatabase(ctx, db)
+	require.NoError(t, err)
+	require.NotEmpty(t, byt)
+
+	_, err = db.ExecContext(ctx, "DELETE FROM events")
+	require.NoError(t, err)
+
+	require.NoError(t, restoreDatabase(ctx, db, byt))
+
+	var count int
+	require.NoError(t, db.QueryRowContext(ctx, "SELECT COUNT(*) FROM events").Scan(&count))
+	require.Equal(t, 1, count)
+}
+
+func TestSnapshotDatabaseMigration(t *testing.T) {
+	ctx := context.Background()
+	db, err := sqlitecqrs.New()
+	require.NoError(t, err)
+
+	appID, fnID := uuid.NewString(), uuid.NewString()
+	_, err = db.ExecContext(ctx, "INSERT INTO apps (id, name, sdk_language, sdk_version, status, url, checksum) VALUES (?, ?, ?, ?, ?, ?, ?)", appID, "app", "go", "1", "ok", "http://localhost", "sum")
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, "INSERT INTO functions (id, app_id, name, slug, config, created_at) VALUES (?, ?, ?, ?, ?, ?)", fnID, appID, "fn", "fn", "{}", time.Now())
+	require.NoError(t, err)
+
+	byt, err := snapshotDatabase(ctx, db)
+	require.NoError(t, err)
+
+	current, err := migrationVersion(ctx, db)
+	require.NoError(t, err)
+
+	// Downgrade the snapshot to before functions were archivable, putting
+	// archived_at's absence in the middle of the table's columns.
+	old := rewriteSnapshot(t, byt, []string{
+		"DROP INDEX IF EXISTS function_runs_run_id",
+		"DROP INDEX IF EXISTS function_runs_workspace_id_run_started_at",
+		"DROP INDEX IF EXISTS function_finishes_run_id",
+		"ALTER TABLE functions DROP COLUMN archived_at",
+		"UPDATE migrations SET version = 8",
+	})
+
+	_, err = db.ExecContext(ctx, "DELETE FROM functions WHERE id = ?", fnID)
+	require.NoError(t, err)
+	require.NoError(t, restoreDatabase(ctx, db, old))
+
+	var (
+		slug     string
+		archived sql.NullTime
+	)
+	require.NoError(t, db.QueryRowContext(ctx, "SELECT slug, archived_at FROM functions WHERE id = ?", fnID).Scan(&slug, &archived))
+	require.Equal(t, "fn", slug)
+	require.False(t, archived.Valid)
+
+	t.Run("newer snapshots are rejected", func(t *testing.T) {
+		newer := rewriteSnapshot(t, byt, []string{
+			"DELETE FROM functions WHERE id = '" + fnID + "'",
+			"UPDATE migrations SET version = 99",
+		})
+		require.Error(t, restoreDatabase(ctx, db, newer))
+
+		d := &devserver{db: db}
+		err := d.restore(ctx, Snapshot{Version: snapshotVersion, Migration: current + 1, Database: byt})
+		require.ErrorContains(t, err, "newer than this dev server")
+
+		// Nothing is restored from rejected snapshots.
+		var count int
+		require.NoError(t, db.QueryRowContext(ctx, "SELECT COUNT(*) FROM functions WHERE id = ?", fnID).Scan(&count))
+		require.Equal(t, 1, count)
+	})
+}
+
+// rewriteSnapshot runs the given statements against a copy of the snapshot
+// database, returning the modified snapshot.
+func rewriteSnapshot(t *testing.T, byt []byte, stmts []string) []byte {
+	path := filepath.Join(t.TempDir(), "snapshot.db")
+	require.NoError(t, os.WriteFile(path, byt, 0600))
+
+	db, err := sql.Open("sqlite", path)
+	require.NoError(t, err)
+	for _, stmt := range stmts {
+		_, err := db.Exec(stmt)
+		require.NoError(t, err, stmt)
+	}
+	require.NoError(t, db.Close())
+
+	out, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return out
+}
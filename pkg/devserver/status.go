@@ -0,0 +1,132 @@
+package devserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/inngest/inngest/pkg/cqrs"
+	"github.com/inngest/inngest/pkg/devserver/environment"
+	"github.com/inngest/inngest/pkg/enums"
+	"github.com/inngest/inngest/pkg/execution/queue"
+	"github.com/inngest/inngest/pkg/publicerr"
+	"github.com/oklog/ulid/v2"
+)
+
+const (
+	// statusWindow is the period that run counts are reported for.
+	statusWindow = time.Hour
+	// statusMaxRuns is the maximum number of runs counted within the window.
+	statusMaxRuns = 100_000
+)
+
+// Status summarizes the dev server's queue, runs and state store.
+type Status struct {
+	Functions []FunctionStatus `json:"functions"`
+	State     StateStatus      `json:"state"`
+	// Since is the start of the window that run counts are reported for.
+	Since time.Time `json:"since"`
+}
+
+// FunctionStatus summarizes a function's queue and recent runs.
+type FunctionStatus struct {
+	ID   string `json:"id"`
+	Slug string `json:"slug"`
+	Name string `json:"name"`
+	// Queue is the function's queue backlog, including the oldest pending item.
+	Queue queue.PartitionStats `json:"queue"`
+	// Runs counts the runs started within the status window by status, eg.
+	// {"Running": 2, "Failed": 1}.
+	Runs map[string]int `json:"runs"`
+}
+
+// RunCount returns the number of runs with the given status.
+func (f FunctionStatus) RunCount(status enums.RunStatus) int {
+	return f.Runs[status.String()]
+}
+
+// StateStatus reports the state store's health.
+type StateStatus struct {
+	Healthy bool `json:"healthy"`
+	// Latency is the round trip time of a state store read, in milliseconds.
+	Latency float64 `json:"latency_ms"`
+	Error   string  `json:"error,omitempty"`
+}
+
+// status returns the dev server's current status within the context's
+// environment.
+func (d *devserver) status(ctx context.Context, now time.Time) (*Status, error) {
+	since := now.Add(-statusWindow)
+	result := &Status{Functions: []FunctionStatus{}, Since: since}
+
+	wsID := environment.WorkspaceIDFromContext(ctx)
+	fns, err := d.data.GetFunctions(ctx, wsID)
+	if err != nil {
+		return nil, fmt.Errorf("error loading functions: %w", err)
+	}
+	runs, err := d.data.GetFunctionRunsTimebound(ctx, wsID, cqrs.Timebound{After: &since}, statusMaxRuns)
+	if err != nil {
+		return nil, fmt.Errorf("error loading runs: %w", err)
+	}
+
+	counts := map[string]map[string]int{}
+	for _, run := range runs {
+		id := run.FunctionID.String()
+		if counts[id] == nil {
+			counts[id] = map[string]int{}
+		}
+		counts[id][run.Status.String()]++
+	}
+
+	reader, _ := d.queue.(queue.JobQueueReader)
+	for _, fn := range fns {
+		if fn.ArchivedAt != nil {
+			continue
+		}
+		status := FunctionStatus{
+			ID:    fn.ID.String(),
+			Slug:  fn.Slug,
+			Name:  fn.Name,
+			Queue: queue.PartitionStats{FunctionID: fn.ID},
+			Runs:  counts[fn.ID.String()],
+		}
+		if status.Runs == nil {
+			status.Runs = map[string]int{}
+		}
+		if reader != nil {
+			if status.Queue, err = reader.PartitionStats(ctx, fn.ID); err != nil {
+				return nil, fmt.Errorf("error loading queue for %s: %w", fn.Slug, err)
+			}
+		}
+		result.Functions = append(result.Functions, status)
+	}
+	sort.Slice(result.Functions, func(i, j int) bool {
+		return result.Functions[i].Slug < result.Functions[j].Slug
+	})
+
+	// Check the state store with a read for a run which doesn't exist.
+	start := time.Now()
+	_, err = d.state.Exists(ctx, ulid.Make())
+	result.State.Latency = float64(time.Since(start).Microseconds()) / 1000
+	result.State.Healthy = err == nil
+	if err != nil {
+		result.State.Error = err.Error()
+	}
+
+	return result, nil
+}
+
+// GetStatus returns each function's queue backlog and recent run counts, and the
+// state store's health.
+func (a devapi) GetStatus(w http.ResponseWriter, r *http.Request) {
+	status, err := a.devserver.status(r.Context(), time.Now())
+	if err != nil {
+		_ = publicerr.WriteHTTP(w, publicerr.Wrap(err, 500, "Error loading status"))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_ = json.NewEncoder(w).Encode(status)
+}
@@ -0,0 +1,103 @@
+package devserver
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/inngest/inngest/pkg/cqrs"
+	"github.com/inngest/inngest/pkg/cqrs/sqlitecqrs"
+	"github.com/inngest/inngest/pkg/cqrs/sqlitecqrs/sqlc"
+	"github.com/inngest/inngest/pkg/enums"
+	"github.com/inngest/inngest/pkg/execution/state"
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/require"
+)
+
+// existsState is a state.Manager which only implements Exists.
+type existsState struct {
+	state.Manager
+	err error
+}
+
+func (e existsState) Exists(ctx context.Context, runID ulid.ULID) (bool, error) {
+	return false, e.err
+}
+
+func TestStatus(t *testing.T) {
+	ctx := context.Background()
+	db, err := sqlitecqrs.New()
+	require.NoError(t, err)
+	data := sqlitecqrs.NewCQRS(db)
+	d := &devserver{data: data, state: existsState{}}
+
+	fn, err := data.InsertFunction(ctx, cqrs.InsertFunctionParams{
+		ID:        uuid.New(),
+		AppID:     uuid.New(),
+		Name:      "Test",
+		Slug:      "test-fn",
+		Config:    "{}",
+		CreatedAt: time.Now(),
+	})
+	require.NoError(t, err)
+
+	insertRun := func(startedAt time.Time, status *enums.RunStatus) {
+		runID := ulid.MustNew(ulid.Timestamp(startedAt), rand.Reader)
+		require.NoError(t, data.InsertFunctionRun(ctx, cqrs.FunctionRun{
+			RunID:        runID,
+			RunStartedAt: startedAt,
+			FunctionID:   fn.ID,
+			EventID:      ulid.Make(),
+		}))
+		if status == nil {
+			return
+		}
+		if !enums.RunStatusEnded(*status) {
+			require.NoError(t, data.UpdateFunctionRunStatus(ctx, runID, *status))
+			return
+		}
+		require.NoError(t, sqlc.New(db).InsertFunctionFinish(ctx, sqlc.InsertFunctionFinishParams{
+			RunID:              runID,
+			Status:             sql.NullString{String: status.String(), Valid: true},
+			CreatedAt:          sql.NullTime{Time: time.Now(), Valid: true},
+			Output:             sql.NullString{String: "{}", Valid: true},
+			CompletedStepCount: sql.NullInt64{Int64: 1, Valid: true},
+		}))
+	}
+	failed, running := enums.RunStatusFailed, enums.RunStatusRunning
+	insertRun(time.Now().Add(-time.Minute), &running)
+	insertRun(time.Now().Add(-time.Minute), &running)
+	insertRun(time.Now().Add(-time.Minute), &failed)
+	// Runs which haven't started are queued.
+	insertRun(time.Now().Add(-time.Minute), nil)
+	// Runs outside of the status window aren't counted.
+	insertRun(time.Now().Add(-2*time.Hour), &failed)
+
+	status, err := d.status(ctx, time.Now())
+	require.NoError(t, err)
+	require.True(t, status.State.Healthy)
+	// The database is shared with other tests, which may insert functions.
+	var fnStatus *FunctionStatus
+	for n := range status.Functions {
+		if status.Functions[n].ID == fn.ID.String() {
+			fnStatus = &status.Functions[n]
+		}
+	}
+	require.NotNil(t, fnStatus)
+	require.Equal(t, "test-fn", fnStatus.Slug)
+	require.Equal(t, 2, fnStatus.RunCount(enums.RunStatusRunning))
+	require.Equal(t, 1, fnStatus.RunCount(enums.RunStatusFailed))
+	require.Equal(t, 1, fnStatus.RunCount(enums.RunStatusScheduled))
+	require.Equal(t, 0, fnStatus.RunCount(enums.RunStatusCompleted))
+
+	// State store errors are reported as unhealthy.
+	d.state = existsState{err: fmt.Errorf("connection refused")}
+	status, err = d.status(ctx, time.Now())
+	require.NoError(t, err)
+	require.False(t, status.State.Healthy)
+	require.Equal(t, "connection refused", status.State.Error)
+}
@@ -0,0 +1,71 @@
+package devserver
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/google/uuid"
+	"github.com/inngest/inngest/pkg/cqrs"
+	"github.com/inngest/inngest/pkg/enums"
+	"github.com/inngest/inngest/pkg/execution/state"
+	"github.com/inngest/inngest/pkg/history_drivers/memory_store"
+	"github.com/oklog/ulid/v2"
+)
+
+// stepOutputReader loads step outputs from the state store, falling back to the
+// in-memory run history for runs whose state has been deleted, eg. after
+// cancelling.
+type stepOutputReader struct {
+	state state.StateLoader
+}
+
+var _ cqrs.StepOutputReader = stepOutputReader{}
+
+func (r stepOutputReader) GetStepOutput(
+	ctx context.Context,
+	accountID uuid.UUID,
+	workspaceID uuid.UUID,
+	runID ulid.ULID,
+	stepID string,
+) (*cqrs.StepOutput, error) {
+	out := &cqrs.StepOutput{RunID: runID, StepID: stepID}
+
+	if s, err := r.state.Load(ctx, runID); err == nil {
+		out.Source = cqrs.StepOutputSourceState
+		if err, ok := s.Errors()[stepID]; ok && err != nil {
+			out.Error = []byte(err.Error())
+			return out, nil
+		}
+		if data, ok := s.Actions()[stepID]; ok {
+			byt, err := json.Marshal(data)
+			if err != nil {
+				return nil, err
+			}
+			out.Output = byt
+			return out, nil
+		}
+	}
+
+	store := memory_store.Singleton
+	store.Mu.RLock()
+	defer store.Mu.RUnlock()
+
+	// Use the step's latest final result, as errored attempts are retried.
+	history := store.Data[runID].History
+	for n := len(history) - 1; n >= 0; n-- {
+		h := history[n]
+		if h.StepID == nil || *h.StepID != stepID || h.Result == nil {
+			continue
+		}
+		out.Source = cqrs.StepOutputSourceHistory
+		switch h.Type {
+		case enums.HistoryTypeStepCompleted.String():
+			out.Output = []byte(h.Result.Output)
+			return out, nil
+		case enums.HistoryTypeStepFailed.String():
+			out.Error = []byte(h.Result.Output)
+			return out, nil
+		}
+	}
+	return nil, cqrs.ErrStepOutputNotFound
+}
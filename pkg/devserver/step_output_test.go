@@ -0,0 +1,86 @@
+package devserver
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/inngest/inngest/pkg/cqrs"
+	"github.com/inngest/inngest/pkg/enums"
+	"github.com/inngest/inngest/pkg/execution/history"
+	"github.com/inngest/inngest/pkg/execution/state"
+	"github.com/inngest/inngest/pkg/history_drivers/memory_writer"
+	"github.com/inngest/inngest/pkg/inngest"
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/require"
+)
+
+// loadState is a state.StateLoader which only loads the given states.
+type loadState struct {
+	state.StateLoader
+	states map[ulid.ULID]state.State
+}
+
+func (l loadState) Load(ctx context.Context, runID ulid.ULID) (state.State, error) {
+	if s, ok := l.states[runID]; ok {
+		return s, nil
+	}
+	return nil, fmt.Errorf("run not found")
+}
+
+func TestStepOutputReader(t *testing.T) {
+	ctx := context.Background()
+	runID, deletedID := ulid.Make(), ulid.Make()
+	r := stepOutputReader{state: loadState{states: map[ulid.ULID]state.State{
+		runID: state.NewStateInstance(
+			inngest.Function{},
+			state.Identifier{RunID: runID},
+			state.Metadata{},
+			nil,
+			map[string]any{"a": map[string]any{"ok": true}},
+			map[string]error{"b": fmt.Errorf("boom")},
+			[]string{"a", "b"},
+		),
+	}}}
+
+	o, err := r.GetStepOutput(ctx, uuid.UUID{}, uuid.UUID{}, runID, "a")
+	require.NoError(t, err)
+	require.Equal(t, cqrs.StepOutputSourceState, o.Source)
+	require.Equal(t, `{"ok":true}`, string(o.Output))
+
+	o, err = r.GetStepOutput(ctx, uuid.UUID{}, uuid.UUID{}, runID, "b")
+	require.NoError(t, err)
+	require.Nil(t, o.Output)
+	require.Equal(t, "boom", string(o.Error))
+
+	t.Run("Runs without state use their history", func(t *testing.T) {
+		writer := memory_writer.NewWriter()
+		for _, h := range []struct {
+			typ    enums.HistoryType
+			output string
+		}{
+			{enums.HistoryTypeStepErrored, `"retrying"`},
+			{enums.HistoryTypeStepCompleted, `"done"`},
+		} {
+			step := "c"
+			require.NoError(t, writer.Write(ctx, history.History{
+				ID:        ulid.Make(),
+				CreatedAt: time.Now(),
+				RunID:     deletedID,
+				Type:      h.typ.String(),
+				StepID:    &step,
+				Result:    &history.Result{Output: h.output},
+			}))
+		}
+
+		o, err := r.GetStepOutput(ctx, uuid.UUID{}, uuid.UUID{}, deletedID, "c")
+		require.NoError(t, err)
+		require.Equal(t, cqrs.StepOutputSourceHistory, o.Source)
+		require.Equal(t, `"done"`, string(o.Output))
+
+		_, err = r.GetStepOutput(ctx, uuid.UUID{}, uuid.UUID{}, deletedID, "missing")
+		require.ErrorIs(t, err, cqrs.ErrStepOutputNotFound)
+	})
+}
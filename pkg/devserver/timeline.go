@@ -0,0 +1,219 @@
+package devserver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/gorilla/websocket"
+	"github.com/inngest/inngest/pkg/enums"
+	"github.com/inngest/inngest/pkg/execution/history"
+	"github.com/inngest/inngest/pkg/history_drivers/memory_store"
+	"github.com/inngest/inngest/pkg/logger"
+	"github.com/inngest/inngest/pkg/publicerr"
+	"github.com/oklog/ulid/v2"
+)
+
+// TimelineEntry is a single entry within a run's timeline, eg. a step starting
+// or the function finishing.
+type TimelineEntry struct {
+	ID        ulid.ULID `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	// Type is the history type, eg. "StepCompleted".
+	Type     string  `json:"type"`
+	StepID   *string `json:"step_id,omitempty"`
+	StepName *string `json:"step_name,omitempty"`
+	Attempt  int64   `json:"attempt"`
+	// Output is the step or function's output, or its error for failures.
+	Output       *string               `json:"output,omitempty"`
+	Sleep        *history.Sleep        `json:"sleep,omitempty"`
+	WaitForEvent *history.WaitForEvent `json:"wait_for_event,omitempty"`
+}
+
+// Finished returns whether the entry ends the run's timeline.
+func (t TimelineEntry) Finished() bool {
+	switch t.Type {
+	case enums.HistoryTypeFunctionCompleted.String(),
+		enums.HistoryTypeFunctionFailed.String(),
+		enums.HistoryTypeFunctionCancelled.String():
+		return true
+	}
+	return false
+}
+
+func newTimelineEntry(h history.History) TimelineEntry {
+	entry := TimelineEntry{
+		ID:           h.ID,
+		CreatedAt:    h.CreatedAt,
+		Type:         h.Type,
+		StepID:       h.StepID,
+		StepName:     h.StepName,
+		Attempt:      h.Attempt,
+		Sleep:        h.Sleep,
+		WaitForEvent: h.WaitForEvent,
+	}
+	if h.Result != nil && h.Result.Output != "" {
+		entry.Output = &h.Result.Output
+	}
+	return entry
+}
+
+// runTimeline broadcasts history entries to subscribers of each run, allowing
+// CLIs to tail runs as they execute.
+type runTimeline struct {
+	l    sync.Mutex
+	subs map[chan TimelineEntry]ulid.ULID
+}
+
+func newRunTimeline() *runTimeline {
+	return &runTimeline{subs: map[chan TimelineEntry]ulid.ULID{}}
+}
+
+var _ history.Driver = &runTimeline{}
+
+// subscribe returns a channel which receives every entry for the given run, and
+// a func to unsubscribe.
+func (t *runTimeline) subscribe(runID ulid.ULID) (<-chan TimelineEntry, func()) {
+	ch := make(chan TimelineEntry, feedBufferSize)
+	t.l.Lock()
+	t.subs[ch] = runID
+	t.l.Unlock()
+
+	return ch, func() {
+		t.l.Lock()
+		delete(t.subs, ch)
+		t.l.Unlock()
+	}
+}
+
+// Write publishes the history item to the run's subscribers without blocking,
+// implementing history.Driver.
+func (t *runTimeline) Write(ctx context.Context, h history.History) error {
+	t.l.Lock()
+	defer t.l.Unlock()
+	for ch, runID := range t.subs {
+		if runID != h.RunID {
+			continue
+		}
+		select {
+		case ch <- newTimelineEntry(h):
+		default:
+			// Drop entries for slow subscribers, as with the event feed.
+		}
+	}
+	return nil
+}
+
+func (t *runTimeline) Close() error {
+	return nil
+}
+
+// timelineEntries returns the run's recorded timeline.
+func timelineEntries(runID ulid.ULID) []TimelineEntry {
+	store := memory_store.Singleton
+	store.Mu.RLock()
+	defer store.Mu.RUnlock()
+
+	entries := []TimelineEntry{}
+	for _, h := range store.Data[runID].History {
+		entries = append(entries, newTimelineEntry(h))
+	}
+	return entries
+}
+
+// GetTimeline returns a run's timeline of lifecycle events, step outputs and
+// errors.
+func (a devapi) GetTimeline(w http.ResponseWriter, r *http.Request) {
+	runID, err := ulid.Parse(chi.URLParam(r, "runID"))
+	if err != nil {
+		_ = publicerr.WriteHTTP(w, publicerr.Wrap(err, 400, "Invalid run ID"))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_ = json.NewEncoder(w).Encode(timelineEntries(runID))
+}
+
+// TimelineFeed streams a run's timeline over a websocket as JSON.  Existing
+// entries are sent first, followed by new entries as the run executes.  The
+// connection is closed once the run finishes.
+func (a devapi) TimelineFeed(w http.ResponseWriter, r *http.Request) {
+	runID, err := ulid.Parse(chi.URLParam(r, "runID"))
+	if err != nil {
+		_ = publicerr.WriteHTTP(w, publicerr.Wrap(err, 400, "Invalid run ID"))
+		return
+	}
+
+	conn, err := feedUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade writes the error response.
+		return
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	// Subscribe before loading existing entries so that no entries are missed.
+	// Entries written in between are sent twice, so they're deduplicated by ID.
+	entries, unsubscribe := a.devserver.timeline.subscribe(runID)
+	defer unsubscribe()
+
+	sent := map[ulid.ULID]struct{}{}
+	send := func(entry TimelineEntry) bool {
+		if _, ok := sent[entry.ID]; ok {
+			return true
+		}
+		sent[entry.ID] = struct{}{}
+		if err := conn.WriteJSON(entry); err != nil {
+			logger.From(ctx).Debug().Err(err).Msg("error writing to timeline feed")
+			return false
+		}
+		if entry.Finished() {
+			_ = conn.WriteControl(
+				websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseNormalClosure, "run finished"),
+				time.Now().Add(time.Second),
+			)
+			return false
+		}
+		return true
+	}
+
+	for _, entry := range timelineEntries(runID) {
+		if !send(entry) {
+			return
+		}
+	}
+
+	// Read and discard client messages so that close frames are handled, and stop
+	// streaming once the client disconnects.
+	go func() {
+		defer cancel()
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ping := time.NewTicker(feedPingInterval)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ping.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(time.Second)); err != nil {
+				return
+			}
+		case entry := <-entries:
+			if !send(entry) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package devserver
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/gorilla/websocket"
+	"github.com/inngest/inngest/pkg/enums"
+	"github.com/inngest/inngest/pkg/execution/history"
+	"github.com/inngest/inngest/pkg/history_drivers/memory_writer"
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimelineFeed(t *testing.T) {
+	ctx := context.Background()
+	d := newService(StartOpts{}, nil, nil, nil)
+	d.timeline = newRunTimeline()
+	api := devapi{devserver: d}
+
+	r := chi.NewRouter()
+	r.Get("/runs/{runID}/timeline/feed", api.TimelineFeed)
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	runID := ulid.Make()
+	item := func(typ enums.HistoryType, output string) history.History {
+		h := history.History{
+			ID:        ulid.Make(),
+			CreatedAt: time.Now(),
+			RunID:     runID,
+			Type:      typ.String(),
+		}
+		if output != "" {
+			h.Result = &history.Result{Output: output}
+		}
+		return h
+	}
+	// Write to both the history store and timeline, as with the dev server's
+	// history lifecycle listener.
+	writer := memory_writer.NewWriter()
+	write := func(h history.History) {
+		require.NoError(t, writer.Write(ctx, h))
+		require.NoError(t, d.timeline.Write(ctx, h))
+	}
+
+	started := item(enums.HistoryTypeFunctionStarted, "")
+	write(started)
+
+	url := "ws" + strings.TrimPrefix(srv.URL, "http") + "/runs/" + runID.String() + "/timeline/feed"
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	read := func() TimelineEntry {
+		entry := TimelineEntry{}
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+		require.NoError(t, conn.ReadJSON(&entry))
+		return entry
+	}
+
+	// Existing entries are sent first.
+	require.Equal(t, started.ID, read().ID)
+
+	require.Eventually(t, func() bool {
+		d.timeline.l.Lock()
+		defer d.timeline.l.Unlock()
+		return len(d.timeline.subs) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	// Entries for other runs are ignored, and entries which were already sent
+	// aren't sent again.
+	require.NoError(t, d.timeline.Write(ctx, history.History{ID: ulid.Make(), RunID: ulid.Make()}))
+	require.NoError(t, d.timeline.Write(ctx, started))
+
+	step := item(enums.HistoryTypeStepCompleted, `{"ok":true}`)
+	write(step)
+	entry := read()
+	require.Equal(t, step.ID, entry.ID)
+	require.Equal(t, `{"ok":true}`, *entry.Output)
+
+	// The feed closes once the run finishes.
+	write(item(enums.HistoryTypeFunctionCompleted, ""))
+	require.True(t, read().Finished())
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+	_, _, err = conn.ReadMessage()
+	require.True(t, websocket.IsCloseError(err, websocket.CloseNormalClosure))
+}
@@ -0,0 +1,217 @@
+package devserver
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/inngest/inngest/pkg/cqrs"
+	"github.com/inngest/inngest/pkg/publicerr"
+	"github.com/oklog/ulid/v2"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// ExportTrace returns every span within the given run's trace as an OTLP JSON
+// file, eg. GET /dev/runs/{runID}/trace.  The file can be imported into Jaeger
+// or Grafana to inspect execution timing.
+func (a devapi) ExportTrace(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	runID, err := ulid.Parse(chi.URLParam(r, "runID"))
+	if err != nil {
+		_ = publicerr.WriteHTTP(w, publicerr.Wrap(err, 400, "Invalid run ID"))
+		return
+	}
+
+	spans, err := a.devserver.data.GetSpansByRunID(ctx, runID)
+	if err != nil {
+		_ = publicerr.WriteHTTP(w, publicerr.Wrap(err, 500, "Error loading spans"))
+		return
+	}
+	if len(spans) == 0 {
+		_ = publicerr.WriteHTTP(w, publicerr.Errorf(404, "No spans found for run: %s", runID))
+		return
+	}
+
+	traces, err := spansToOTLP(dedupSpans(spans))
+	if err != nil {
+		_ = publicerr.WriteHTTP(w, publicerr.Wrap(err, 500, "Error converting spans"))
+		return
+	}
+	byt, err := (&ptrace.JSONMarshaler{}).MarshalTraces(traces)
+	if err != nil {
+		_ = publicerr.WriteHTTP(w, publicerr.Wrap(err, 500, "Error marshalling trace"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="trace-%s.json"`, runID))
+	_, _ = w.Write(byt)
+}
+
+// spansToOTLP converts stored spans into OTLP traces, grouping spans by their
+// resource and instrumentation scope.
+func spansToOTLP(spans []*cqrs.Span) (ptrace.Traces, error) {
+	traces := ptrace.NewTraces()
+	resources := map[string]ptrace.ResourceSpans{}
+	scopes := map[string]ptrace.ScopeSpans{}
+
+	for _, s := range spans {
+		rkey := s.ServiceName + "\x00" + mapKey(s.ResourceAttributes)
+		rs, ok := resources[rkey]
+		if !ok {
+			rs = traces.ResourceSpans().AppendEmpty()
+			putAttrs(rs.Resource().Attributes(), s.ResourceAttributes)
+			if s.ServiceName != "" {
+				rs.Resource().Attributes().PutStr("service.name", s.ServiceName)
+			}
+			resources[rkey] = rs
+		}
+
+		skey := rkey + "\x00" + s.ScopeName + "\x00" + s.ScopeVersion
+		ss, ok := scopes[skey]
+		if !ok {
+			ss = rs.ScopeSpans().AppendEmpty()
+			ss.Scope().SetName(s.ScopeName)
+			ss.Scope().SetVersion(s.ScopeVersion)
+			scopes[skey] = ss
+		}
+
+		span := ss.Spans().AppendEmpty()
+		traceID, err := parseTraceID(s.TraceID)
+		if err != nil {
+			return traces, err
+		}
+		spanID, err := parseSpanID(s.SpanID)
+		if err != nil {
+			return traces, err
+		}
+		span.SetTraceID(traceID)
+		span.SetSpanID(spanID)
+		if s.ParentSpanID != nil {
+			if parent, err := parseSpanID(*s.ParentSpanID); err == nil {
+				span.SetParentSpanID(parent)
+			}
+		}
+		if s.TraceState != nil {
+			span.TraceState().FromRaw(*s.TraceState)
+		}
+		span.SetName(s.SpanName)
+		span.SetKind(spanKind(s.SpanKind))
+		span.SetStartTimestamp(pcommon.NewTimestampFromTime(s.Timestamp))
+		span.SetEndTimestamp(pcommon.NewTimestampFromTime(s.Timestamp.Add(s.Duration)))
+		putAttrs(span.Attributes(), s.SpanAttributes)
+
+		span.Status().SetCode(statusCode(s.StatusCode))
+		if s.StatusMessage != nil {
+			span.Status().SetMessage(*s.StatusMessage)
+		}
+
+		for _, e := range s.Events {
+			evt := span.Events().AppendEmpty()
+			evt.SetName(e.Name)
+			evt.SetTimestamp(pcommon.NewTimestampFromTime(e.Timestamp))
+			putAttrs(evt.Attributes(), e.Attributes)
+		}
+		for _, l := range s.Links {
+			link := span.Links().AppendEmpty()
+			if id, err := parseTraceID(l.TraceID); err == nil {
+				link.SetTraceID(id)
+			}
+			if id, err := parseSpanID(l.SpanID); err == nil {
+				link.SetSpanID(id)
+			}
+			link.TraceState().FromRaw(l.TraceState)
+			putAttrs(link.Attributes(), l.Attributes)
+		}
+	}
+
+	return traces, nil
+}
+
+// dedupSpans removes duplicate spans, which are stored each time a span is
+// exported, keeping the longest span as with ingestion.
+func dedupSpans(spans []*cqrs.Span) []*cqrs.Span {
+	idx := map[string]int{}
+	result := []*cqrs.Span{}
+	for _, s := range spans {
+		key := s.TraceID + ":" + s.SpanID
+		n, ok := idx[key]
+		if !ok {
+			idx[key] = len(result)
+			result = append(result, s)
+			continue
+		}
+		if s.Duration >= result[n].Duration {
+			result[n] = s
+		}
+	}
+	return result
+}
+
+func putAttrs(dst pcommon.Map, src map[string]string) {
+	for k, v := range src {
+		dst.PutStr(k, v)
+	}
+}
+
+// mapKey returns a stable key for the given map, used to group spans with the
+// same resource attributes.
+func mapKey(m map[string]string) string {
+	keys := make([]string, 0, len(m))
+	for k, v := range m {
+		keys = append(keys, k+"="+v)
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, "\x00")
+}
+
+func parseTraceID(s string) (pcommon.TraceID, error) {
+	id := pcommon.TraceID{}
+	byt, err := hex.DecodeString(s)
+	if err != nil || len(byt) != len(id) {
+		return id, fmt.Errorf("invalid trace id: %s", s)
+	}
+	copy(id[:], byt)
+	return id, nil
+}
+
+func parseSpanID(s string) (pcommon.SpanID, error) {
+	id := pcommon.SpanID{}
+	byt, err := hex.DecodeString(s)
+	if err != nil || len(byt) != len(id) {
+		return id, fmt.Errorf("invalid span id: %s", s)
+	}
+	copy(id[:], byt)
+	return id, nil
+}
+
+// spanKind returns the span kind for the string stored when ingesting spans.
+func spanKind(s string) ptrace.SpanKind {
+	for _, k := range []ptrace.SpanKind{
+		ptrace.SpanKindInternal,
+		ptrace.SpanKindServer,
+		ptrace.SpanKindClient,
+		ptrace.SpanKindProducer,
+		ptrace.SpanKindConsumer,
+	} {
+		if k.String() == s {
+			return k
+		}
+	}
+	return ptrace.SpanKindUnspecified
+}
+
+// statusCode returns the status code for the string stored when ingesting spans.
+func statusCode(s string) ptrace.StatusCode {
+	for _, c := range []ptrace.StatusCode{ptrace.StatusCodeOk, ptrace.StatusCodeError} {
+		if c.String() == s {
+			return c
+		}
+	}
+	return ptrace.StatusCodeUnset
+}
@@ -0,0 +1,85 @@
+package devserver
+
+import (
+	"context"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	"github.com/inngest/inngest/pkg/cqrs"
+	"github.com/inngest/inngest/pkg/cqrs/sqlitecqrs"
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+func TestSpansToOTLP(t *testing.T) {
+	ctx := context.Background()
+	db, err := sqlitecqrs.New()
+	require.NoError(t, err)
+	data := sqlitecqrs.NewCQRS(db)
+
+	runID := ulid.MustNew(ulid.Now(), rand.Reader)
+	parent := "0102030405060708"
+	start := time.Now().Truncate(time.Millisecond)
+	spans := []*cqrs.Span{
+		{
+			Timestamp:      start,
+			TraceID:        "0102030405060708090a0b0c0d0e0f10",
+			SpanID:         parent,
+			SpanName:       "function",
+			SpanKind:       ptrace.SpanKindServer.String(),
+			ServiceName:    "devserver",
+			ScopeName:      "run",
+			SpanAttributes: map[string]string{"run_id": runID.String()},
+			Duration:       time.Second,
+			StatusCode:     ptrace.StatusCodeOk.String(),
+			RunID:          &runID,
+		},
+		{
+			// A child span without a run ID is still within the run's trace.
+			Timestamp:    start.Add(100 * time.Millisecond),
+			TraceID:      "0102030405060708090a0b0c0d0e0f10",
+			SpanID:       "1112131415161718",
+			ParentSpanID: &parent,
+			SpanName:     "step",
+			SpanKind:     ptrace.SpanKindInternal.String(),
+			ServiceName:  "devserver",
+			ScopeName:    "step",
+			Duration:     500 * time.Millisecond,
+			StatusCode:   ptrace.StatusCodeError.String(),
+			Events:       []cqrs.SpanEvent{{Timestamp: start, Name: "retry"}},
+		},
+	}
+	for _, s := range spans {
+		require.NoError(t, data.InsertSpan(ctx, s))
+	}
+
+	stored, err := data.GetSpansByRunID(ctx, runID)
+	require.NoError(t, err)
+	require.Len(t, stored, 2)
+
+	traces, err := spansToOTLP(dedupSpans(stored))
+	require.NoError(t, err)
+	byt, err := (&ptrace.JSONMarshaler{}).MarshalTraces(traces)
+	require.NoError(t, err)
+
+	// The exported file must be valid OTLP JSON.
+	parsed, err := (&ptrace.JSONUnmarshaler{}).UnmarshalTraces(byt)
+	require.NoError(t, err)
+	require.Equal(t, 2, parsed.SpanCount())
+	require.Equal(t, 1, parsed.ResourceSpans().Len())
+
+	rs := parsed.ResourceSpans().At(0)
+	require.Equal(t, 2, rs.ScopeSpans().Len())
+	fn := rs.ScopeSpans().At(0).Spans().At(0)
+	require.Equal(t, "function", fn.Name())
+	require.Equal(t, ptrace.SpanKindServer, fn.Kind())
+	require.Equal(t, time.Second, fn.EndTimestamp().AsTime().Sub(fn.StartTimestamp().AsTime()))
+
+	step := rs.ScopeSpans().At(1).Spans().At(0)
+	require.Equal(t, "step", step.Name())
+	require.Equal(t, parent, step.ParentSpanID().String())
+	require.Equal(t, ptrace.StatusCodeError, step.Status().Code())
+	require.Equal(t, 1, step.Events().Len())
+}
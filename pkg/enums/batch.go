@@ -11,4 +11,7 @@ const (
 	BatchNew
 	// BatchFull represents a full batch
 	BatchFull
+	// BatchOverflow represents an item which wasn't appended, as the function
+	// already holds its maximum number of pending batches
+	BatchOverflow
 )
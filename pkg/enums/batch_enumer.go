@@ -8,11 +8,11 @@ import (
 	"strings"
 )
 
-const _BatchName = "AppendNewFull"
+const _BatchName = "AppendNewFullOverflow"
 
-var _BatchIndex = [...]uint8{0, 6, 9, 13}
+var _BatchIndex = [...]uint8{0, 6, 9, 13, 21}
 
-const _BatchLowerName = "appendnewfull"
+const _BatchLowerName = "appendnewfulloverflow"
 
 func (i Batch) String() string {
 	if i < 0 || i >= Batch(len(_BatchIndex)-1) {
@@ -28,23 +28,27 @@ func _BatchNoOp() {
 	_ = x[BatchAppend-(0)]
 	_ = x[BatchNew-(1)]
 	_ = x[BatchFull-(2)]
+	_ = x[BatchOverflow-(3)]
 }
 
-var _BatchValues = []Batch{BatchAppend, BatchNew, BatchFull}
+var _BatchValues = []Batch{BatchAppend, BatchNew, BatchFull, BatchOverflow}
 
 var _BatchNameToValueMap = map[string]Batch{
-	_BatchName[0:6]:       BatchAppend,
-	_BatchLowerName[0:6]:  BatchAppend,
-	_BatchName[6:9]:       BatchNew,
-	_BatchLowerName[6:9]:  BatchNew,
-	_BatchName[9:13]:      BatchFull,
-	_BatchLowerName[9:13]: BatchFull,
+	_BatchName[0:6]:        BatchAppend,
+	_BatchLowerName[0:6]:   BatchAppend,
+	_BatchName[6:9]:        BatchNew,
+	_BatchLowerName[6:9]:   BatchNew,
+	_BatchName[9:13]:       BatchFull,
+	_BatchLowerName[9:13]:  BatchFull,
+	_BatchName[13:21]:      BatchOverflow,
+	_BatchLowerName[13:21]: BatchOverflow,
 }
 
 var _BatchNames = []string{
 	_BatchName[0:6],
 	_BatchName[6:9],
 	_BatchName[9:13],
+	_BatchName[13:21],
 }
 
 // BatchString retrieves an enum value from the enum constants string name.
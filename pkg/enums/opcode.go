@@ -14,4 +14,6 @@ const (
 	OpcodeSleep
 	OpcodeWaitForEvent
 	OpcodeInvokeFunction
+	OpcodeAIGateway // An inference request, performed by the executor on behalf of the SDK.
+	OpcodeGateway   // An HTTP request, performed by the executor on behalf of the SDK.
 )
@@ -37,18 +37,34 @@ const (
 	// This is unlikely to happen during normal execution, and more likely when converting between
 	// the status code
 	RunStatusUnknown RunStatus = 6
+	// RunStatusWaitingForEvent indicates that the function is running, but is
+	// waiting for an event or an invoked function before continuing.
+	RunStatusWaitingForEvent RunStatus = 7
+	// RunStatusSleeping indicates that the function is running, but is sleeping
+	// until a step's scheduled time.
+	RunStatusSleeping RunStatus = 8
+	// RunStatusThrottled indicates that the function is scheduled, but can't
+	// start until capacity is available within the function's throttle.
+	RunStatusThrottled RunStatus = 9
+	// RunStatusHalted indicates that the function is running, but is halted at a
+	// breakpoint until the step is continued, eg. in the dev server.
+	RunStatusHalted RunStatus = 10
 )
 
 var (
 	// NOTE: assign larger number status codes RunStatus
 	// This can be used for cases where you need the numbers to be in ascending order based on status
 	runStatusCode = map[RunStatus]int64{
-		RunStatusOverflowed: 50,
-		RunStatusScheduled:  100,
-		RunStatusRunning:    200,
-		RunStatusCompleted:  300,
-		RunStatusFailed:     400,
-		RunStatusCancelled:  500,
+		RunStatusOverflowed:      50,
+		RunStatusScheduled:       100,
+		RunStatusThrottled:       150,
+		RunStatusRunning:         200,
+		RunStatusWaitingForEvent: 210,
+		RunStatusSleeping:        220,
+		RunStatusHalted:          230,
+		RunStatusCompleted:       300,
+		RunStatusFailed:          400,
+		RunStatusCancelled:       500,
 	}
 
 	codeStatusMap = map[int64]RunStatus{}
@@ -70,6 +86,12 @@ func RunStatusEnded(s RunStatus) bool {
 	return false
 }
 
+// RunStatusWaiting returns whether the function hasn't ended, but isn't
+// progressing as it's waiting, eg. sleeping or throttled.
+func RunStatusWaiting(s RunStatus) bool {
+	return s == RunStatusWaitingForEvent || s == RunStatusSleeping || s == RunStatusThrottled || s == RunStatusHalted
+}
+
 func (r RunStatus) MarshalBinary() ([]byte, error) {
 	byt := []byte{}
 	return strconv.AppendInt(byt, int64(r), 10), nil
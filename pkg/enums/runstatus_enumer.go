@@ -10,11 +10,11 @@ import (
 	"strings"
 )
 
-const _RunStatusName = "RunningCompletedFailedCancelledOverflowedScheduledUnknown"
+const _RunStatusName = "RunningCompletedFailedCancelledOverflowedScheduledUnknownWaitingForEventSleepingThrottledHalted"
 
-var _RunStatusIndex = [...]uint8{0, 7, 16, 22, 31, 41, 50, 57}
+var _RunStatusIndex = [...]uint8{0, 7, 16, 22, 31, 41, 50, 57, 72, 80, 89, 95}
 
-const _RunStatusLowerName = "runningcompletedfailedcancelledoverflowedscheduledunknown"
+const _RunStatusLowerName = "runningcompletedfailedcancelledoverflowedscheduledunknownwaitingforeventsleepingthrottledhalted"
 
 func (i RunStatus) String() string {
 	if i < 0 || i >= RunStatus(len(_RunStatusIndex)-1) {
@@ -34,9 +34,13 @@ func _RunStatusNoOp() {
 	_ = x[RunStatusOverflowed-(4)]
 	_ = x[RunStatusScheduled-(5)]
 	_ = x[RunStatusUnknown-(6)]
+	_ = x[RunStatusWaitingForEvent-(7)]
+	_ = x[RunStatusSleeping-(8)]
+	_ = x[RunStatusThrottled-(9)]
+	_ = x[RunStatusHalted-(10)]
 }
 
-var _RunStatusValues = []RunStatus{RunStatusRunning, RunStatusCompleted, RunStatusFailed, RunStatusCancelled, RunStatusOverflowed, RunStatusScheduled, RunStatusUnknown}
+var _RunStatusValues = []RunStatus{RunStatusRunning, RunStatusCompleted, RunStatusFailed, RunStatusCancelled, RunStatusOverflowed, RunStatusScheduled, RunStatusUnknown, RunStatusWaitingForEvent, RunStatusSleeping, RunStatusThrottled, RunStatusHalted}
 
 var _RunStatusNameToValueMap = map[string]RunStatus{
 	_RunStatusName[0:7]:        RunStatusRunning,
@@ -53,6 +57,14 @@ var _RunStatusNameToValueMap = map[string]RunStatus{
 	_RunStatusLowerName[41:50]: RunStatusScheduled,
 	_RunStatusName[50:57]:      RunStatusUnknown,
 	_RunStatusLowerName[50:57]: RunStatusUnknown,
+	_RunStatusName[57:72]:      RunStatusWaitingForEvent,
+	_RunStatusLowerName[57:72]: RunStatusWaitingForEvent,
+	_RunStatusName[72:80]:      RunStatusSleeping,
+	_RunStatusLowerName[72:80]: RunStatusSleeping,
+	_RunStatusName[80:89]:      RunStatusThrottled,
+	_RunStatusLowerName[80:89]: RunStatusThrottled,
+	_RunStatusName[89:95]:      RunStatusHalted,
+	_RunStatusLowerName[89:95]: RunStatusHalted,
 }
 
 var _RunStatusNames = []string{
@@ -63,6 +75,10 @@ var _RunStatusNames = []string{
 	_RunStatusName[31:41],
 	_RunStatusName[41:50],
 	_RunStatusName[50:57],
+	_RunStatusName[57:72],
+	_RunStatusName[72:80],
+	_RunStatusName[80:89],
+	_RunStatusName[89:95],
 }
 
 // RunStatusString retrieves an enum value from the enum constants string name.
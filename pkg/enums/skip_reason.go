@@ -0,0 +1,17 @@
+//go:generate go run github.com/dmarkham/enumer -trimprefix=SkipReason -type=SkipReason -json -text
+
+package enums
+
+// SkipReason represents the reason a function run was skipped.
+type SkipReason int
+
+const (
+	// SkipReasonNone is the zero value, used when a run isn't skipped.
+	SkipReasonNone SkipReason = iota
+	// SkipReasonFunctionPaused is used when runs are skipped as the function
+	// is paused.
+	SkipReasonFunctionPaused
+	// SkipReasonFunctionArchived is used when runs are skipped as the function
+	// is archived.
+	SkipReasonFunctionArchived
+)
@@ -16,10 +16,22 @@ const (
 	EventReceivedName = "event/event.received"
 	FnFailedName      = "inngest/function.failed"
 	FnFinishedName    = "inngest/function.finished"
+	// FnLimitedName is sent when a run is stopped for exceeding its limits, eg.
+	// its step limit.
+	FnLimitedName = "inngest/function.limited"
+	// WaitTimeoutName is sent when a step waiting for an event times out
+	// without receiving the event.
+	WaitTimeoutName = "inngest/wait.timeout"
 	// InvokeEventName is the event name used to invoke specific functions via an
 	// API.  Note that invoking functions still sends an event in the usual manner.
 	InvokeFnName = "inngest/function.invoked"
 	FnCronName   = "inngest/scheduled.timer"
+
+	// RunStartedName, RunCancelledName and StepFailedName are sent as runs
+	// change state, if lifecycle events are enabled.
+	RunStartedName   = "inngest/run.started"
+	RunCancelledName = "inngest/run.cancelled"
+	StepFailedName   = "inngest/step.failed"
 )
 
 var (
@@ -126,6 +138,17 @@ func (e Event) CorrelationID() string {
 	return ""
 }
 
+// IsRunLifecycleEvent returns true if the given event name is sent by Inngest as
+// a run changes state, eg. when the run starts or finishes.  Runs triggered by
+// these events don't send further lifecycle events, preventing loops.
+func IsRunLifecycleEvent(name string) bool {
+	switch name {
+	case FnFinishedName, FnFailedName, RunStartedName, RunCancelledName, StepFailedName:
+		return true
+	}
+	return false
+}
+
 // IsFinishedEvent returns true if the event is a function finished event.
 func (e Event) IsFinishedEvent() bool {
 	return e.Name == FnFinishedName
@@ -137,6 +160,11 @@ func (e Event) IsFinishedEvent() bool {
 type InngestMetadata struct {
 	InvokeFnID          string `json:"fn_id"`
 	InvokeCorrelationId string `json:"correlation_id,omitempty"`
+	// TraceParent and TraceState store the W3C trace context that the event
+	// was sent within, linking runs triggered by the event to the caller's
+	// trace.
+	TraceParent string `json:"traceparent,omitempty"`
+	TraceState  string `json:"tracestate,omitempty"`
 }
 
 func (e Event) InngestMetadata() *InngestMetadata {
@@ -175,6 +203,14 @@ func NewOSSTrackedEventWithID(e Event, id ulid.ULID) TrackedEvent {
 	}
 }
 
+// NewOSSTrackedEventForWorkspace returns a tracked event received within the given
+// workspace, eg. a dev server environment.
+func NewOSSTrackedEventForWorkspace(e Event, workspaceID uuid.UUID) TrackedEvent {
+	evt := NewOSSTrackedEvent(e).(ossTrackedEvent)
+	evt.WorkspaceID = workspaceID
+	return evt
+}
+
 func NewOSSTrackedEventFromString(data string) (*ossTrackedEvent, error) {
 	evt := &ossTrackedEvent{}
 	if err := json.Unmarshal([]byte(data), evt); err != nil {
@@ -187,6 +223,9 @@ func NewOSSTrackedEventFromString(data string) (*ossTrackedEvent, error) {
 type ossTrackedEvent struct {
 	Id    ulid.ULID `json:"internal_id"`
 	Event Event     `json:"event"`
+	// WorkspaceID is the workspace that the event was received within.  This
+	// is the zero UUID unless the dev server hosts many environments.
+	WorkspaceID uuid.UUID `json:"workspace_id"`
 }
 
 func (o ossTrackedEvent) GetEvent() Event {
@@ -198,8 +237,7 @@ func (o ossTrackedEvent) GetInternalID() ulid.ULID {
 }
 
 func (o ossTrackedEvent) GetWorkspaceID() uuid.UUID {
-	// There are no workspaces in OSS yet.
-	return uuid.UUID{}
+	return o.WorkspaceID
 }
 
 type NewInvocationEventOpts struct {
@@ -0,0 +1,176 @@
+package aigateway
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/inngest/inngest/pkg/consts"
+)
+
+const (
+	ProviderOpenAI    = "openai"
+	ProviderAnthropic = "anthropic"
+
+	// DefaultTimeout is the maximum time an inference request may take, unless
+	// configured otherwise.
+	DefaultTimeout = 2 * time.Minute
+	// MaxTimeout caps the configured timeout.  Inference requests are made while
+	// the executor processes a queue item, holding a queue worker for the
+	// request's duration, so they must not run for as long as SDK requests.
+	MaxTimeout = 5 * time.Minute
+)
+
+// defaults lists the default endpoint and credential env var for each provider.
+var defaults = map[string]Provider{
+	ProviderOpenAI: {
+		URL:       "https://api.openai.com/v1/chat/completions",
+		APIKeyEnv: "OPENAI_API_KEY",
+	},
+	ProviderAnthropic: {
+		URL:       "https://api.anthropic.com/v1/messages",
+		APIKeyEnv: "ANTHROPIC_API_KEY",
+	},
+}
+
+// Config configures the model providers that functions may call.
+type Config struct {
+	// Providers configures each provider by name, eg. "openai".  The openai and
+	// anthropic providers are available by default, using credentials from
+	// the OPENAI_API_KEY and ANTHROPIC_API_KEY env vars.
+	Providers map[string]Provider `json:"providers"`
+	// Timeout is the number of seconds an inference request may take,
+	// defaulting to DefaultTimeout and capped at MaxTimeout.
+	Timeout int `json:"timeout"`
+}
+
+// TimeoutDuration returns the inference timeout, using the default if unset.
+func (c Config) TimeoutDuration() time.Duration {
+	if c.Timeout <= 0 {
+		return DefaultTimeout
+	}
+	return min(time.Duration(c.Timeout)*time.Second, MaxTimeout)
+}
+
+// Provider configures a model provider's endpoint and credentials.
+type Provider struct {
+	// URL is the provider's inference endpoint.
+	URL string `json:"url"`
+	// APIKey is the provider's API key.
+	APIKey string `json:"apiKey"`
+	// APIKeyEnv is the env var read for the API key if APIKey is empty.
+	APIKeyEnv string `json:"apiKeyEnv"`
+}
+
+// Request is an inference request sent from a function.
+type Request struct {
+	// Provider is the name of the model provider, eg. "openai".
+	Provider string `json:"provider"`
+	// Body is the request body sent to the provider, eg. the model and its
+	// messages.  This is sent as-is.
+	Body json.RawMessage `json:"body"`
+}
+
+// Response is the result of an inference request.
+type Response struct {
+	// StatusCode is the provider's HTTP status code.
+	StatusCode int
+	// Body is the provider's response body.
+	Body json.RawMessage
+}
+
+// Retryable returns whether the request may succeed if retried.
+func (r Response) Retryable() bool {
+	return r.StatusCode == http.StatusTooManyRequests || r.StatusCode >= 500
+}
+
+// OK returns whether the request succeeded.
+func (r Response) OK() bool {
+	return r.StatusCode >= 200 && r.StatusCode <= 299
+}
+
+// Gateway performs inference requests on behalf of functions.
+type Gateway interface {
+	Infer(ctx context.Context, req Request) (*Response, error)
+}
+
+// New returns a Gateway which calls the configured providers.
+func New(c Config) Gateway {
+	providers := map[string]Provider{}
+	for name, p := range defaults {
+		providers[name] = p
+	}
+	for name, p := range c.Providers {
+		def := providers[name]
+		if p.URL == "" {
+			p.URL = def.URL
+		}
+		if p.APIKeyEnv == "" {
+			p.APIKeyEnv = def.APIKeyEnv
+		}
+		providers[name] = p
+	}
+
+	return &gateway{
+		providers: providers,
+		client:    &http.Client{Timeout: c.TimeoutDuration()},
+	}
+}
+
+type gateway struct {
+	providers map[string]Provider
+	client    *http.Client
+}
+
+func (g *gateway) Infer(ctx context.Context, req Request) (*Response, error) {
+	p, ok := g.providers[req.Provider]
+	if !ok || p.URL == "" {
+		return nil, fmt.Errorf("unknown ai provider: %s", req.Provider)
+	}
+	key := p.APIKey
+	if key == "" && p.APIKeyEnv != "" {
+		key = os.Getenv(p.APIKeyEnv)
+	}
+	if key == "" {
+		return nil, fmt.Errorf("no api key configured for ai provider: %s", req.Provider)
+	}
+
+	r, err := http.NewRequestWithContext(ctx, http.MethodPost, p.URL, bytes.NewReader(req.Body))
+	if err != nil {
+		return nil, fmt.Errorf("error creating ai request: %w", err)
+	}
+	r.Header.Set("Content-Type", "application/json")
+	switch req.Provider {
+	case ProviderAnthropic:
+		r.Header.Set("x-api-key", key)
+		r.Header.Set("anthropic-version", "2023-06-01")
+	default:
+		r.Header.Set("Authorization", "Bearer "+key)
+	}
+
+	resp, err := g.client.Do(r)
+	if err != nil {
+		return nil, fmt.Errorf("error making ai request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	byt, err := io.ReadAll(io.LimitReader(resp.Body, consts.MaxBodySize+1))
+	if err != nil {
+		return nil, fmt.Errorf("error reading ai response: %w", err)
+	}
+	if len(byt) > consts.MaxBodySize {
+		return nil, fmt.Errorf("ai response exceeds the maximum size of %d bytes", consts.MaxBodySize)
+	}
+	if !json.Valid(byt) {
+		// Wrap non-JSON responses, eg. proxy errors, so that they can be
+		// stored as step output.
+		byt, _ = json.Marshal(string(byt))
+	}
+
+	return &Response{StatusCode: resp.StatusCode, Body: byt}, nil
+}
@@ -0,0 +1,83 @@
+package aigateway
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInfer(t *testing.T) {
+	ctx := context.Background()
+
+	var (
+		header http.Header
+		body   []byte
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		body, _ = io.ReadAll(r.Body)
+		if r.URL.Path == "/limited" {
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte("slow down"))
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":"resp-1"}`))
+	}))
+	defer srv.Close()
+
+	t.Setenv("TEST_ANTHROPIC_KEY", "env-key")
+	g := New(Config{
+		Providers: map[string]Provider{
+			ProviderOpenAI:    {URL: srv.URL, APIKey: "openai-key"},
+			ProviderAnthropic: {URL: srv.URL, APIKeyEnv: "TEST_ANTHROPIC_KEY"},
+			"limited":         {URL: srv.URL + "/limited", APIKey: "key"},
+			"nokey":           {URL: srv.URL},
+		},
+	})
+
+	t.Run("it sends the body with bearer credentials", func(t *testing.T) {
+		req := json.RawMessage(`{"model":"gpt-4o","messages":[]}`)
+		resp, err := g.Infer(ctx, Request{Provider: ProviderOpenAI, Body: req})
+		require.NoError(t, err)
+		require.True(t, resp.OK())
+		require.JSONEq(t, `{"id":"resp-1"}`, string(resp.Body))
+		require.JSONEq(t, string(req), string(body))
+		require.Equal(t, "Bearer openai-key", header.Get("Authorization"))
+	})
+
+	t.Run("it uses provider specific auth headers and env credentials", func(t *testing.T) {
+		_, err := g.Infer(ctx, Request{Provider: ProviderAnthropic, Body: json.RawMessage(`{}`)})
+		require.NoError(t, err)
+		require.Equal(t, "env-key", header.Get("x-api-key"))
+		require.Empty(t, header.Get("Authorization"))
+	})
+
+	t.Run("it wraps non-JSON error responses", func(t *testing.T) {
+		resp, err := g.Infer(ctx, Request{Provider: "limited", Body: json.RawMessage(`{}`)})
+		require.NoError(t, err)
+		require.False(t, resp.OK())
+		require.True(t, resp.Retryable())
+		require.Equal(t, `"slow down"`, string(resp.Body))
+	})
+
+	t.Run("it errors without credentials or a known provider", func(t *testing.T) {
+		_, err := g.Infer(ctx, Request{Provider: "nokey", Body: json.RawMessage(`{}`)})
+		require.ErrorContains(t, err, "no api key")
+
+		_, err = g.Infer(ctx, Request{Provider: "unknown", Body: json.RawMessage(`{}`)})
+		require.ErrorContains(t, err, "unknown ai provider")
+	})
+}
+
+func TestTimeoutDuration(t *testing.T) {
+	require.Equal(t, DefaultTimeout, Config{}.TimeoutDuration())
+	require.Equal(t, 30*time.Second, Config{Timeout: 30}.TimeoutDuration())
+	// Long timeouts would hold queue workers, so they're capped.
+	require.Equal(t, MaxTimeout, Config{Timeout: 3600}.TimeoutDuration())
+}
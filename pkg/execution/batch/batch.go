@@ -19,6 +19,7 @@ import (
 //  2. Append the batch item to the key
 //     2a. If this is the first item in the batch, schedule a job to run after the provided timeout.
 //     2b. If this is the last item and the batch is full, start execution immediately, and mark the batch as started
+//     2c. If the batch is sliding and the item isn't the first, reschedule the job to run after the timeout.
 //  3. When time is up for 2a, check if the batch has already started or not
 //     3a. If batch has already started, do nothing and exit immediately
 //  4. If batch has not started,
@@ -29,6 +30,10 @@ import (
 // NOTE:
 //
 //	#4 needs to happen in one transaction in order to make sure there will not be any race conditions.
+//
+//	Functions may allow many batches in flight, in which case items are appended to the next
+//	batch slot while earlier slots hold full batches that haven't started.  Ordering across
+//	batches is best-effort.
 type BatchManager interface {
 	Append(ctx context.Context, bi BatchItem, fn inngest.Function) (*BatchAppendResult, error)
 	RetrieveItems(ctx context.Context, batchID ulid.ULID) ([]BatchItem, error)
@@ -37,6 +42,15 @@ type BatchManager interface {
 	ExpireKeys(ctx context.Context, batchID ulid.ULID) error
 }
 
+// OverflowChecker is an optional interface that batch managers can fulfil,
+// checking whether items can be batched before they're appended, eg. so that
+// events are rejected when they're received.
+type OverflowChecker interface {
+	// Overflows returns whether an item appended to the function's batches
+	// would overflow the function's maximum number of pending batches.
+	Overflows(ctx context.Context, fn inngest.Function) (bool, error)
+}
+
 // BatchItem represents the item that are being batched.
 type BatchItem struct {
 	AccountID       uuid.UUID   `json:"acctID"`
@@ -47,6 +61,9 @@ type BatchItem struct {
 	EventID         ulid.ULID   `json:"evtID"`
 	Event           event.Event `json:"evt"`
 	Version         int         `json:"v"`
+	// Trigger is the index of the function trigger which matched the event, if
+	// the trigger configures batching.
+	Trigger *int `json:"trigger,omitempty"`
 }
 
 func (b BatchItem) GetWorkspaceID() uuid.UUID {
@@ -69,12 +86,19 @@ type BatchAppendResult struct {
 	//   full: The batch is full and ready for execution
 	Status  enums.Batch `json:"status"`
 	BatchID string      `json:"batchID,omitempty"`
+	// CreatedAt is the unix timestamp in milliseconds at which the first item
+	// was appended to the batch.  This is zero for batches created before the
+	// timestamp was recorded.
+	CreatedAt int64 `json:"createdAt,string,omitempty"`
 }
 
 type ScheduleBatchOpts struct {
 	ScheduleBatchPayload
 
 	At time.Time `json:"at"`
+	// Reschedule moves the existing job for the batch to At, if the job has
+	// already been scheduled.  This is used by sliding batches.
+	Reschedule bool `json:"-"`
 }
 
 type ScheduleBatchPayload struct {
@@ -84,4 +108,8 @@ type ScheduleBatchPayload struct {
 	AppID           uuid.UUID `json:"appID"`
 	FunctionID      uuid.UUID `json:"fnID"`
 	FunctionVersion int       `json:"fnV"`
+	// Trigger is the index of the function trigger which matched the batched
+	// event, selecting the trigger whose batching config applies to the batch.
+	// This is nil for batches configured by the function itself.
+	Trigger *int `json:"trigger,omitempty"`
 }
@@ -3,6 +3,9 @@
 --
 
 local batchPointerKey = KEYS[1]      -- key to the batch pointer
+local batchPendingKey = KEYS[2]      -- key to the function's pending batches
+-- KEYS[3..n] are the pointers for the function's additional batch slots, if
+-- the function allows many batches in flight.
 
 local batchLimit = tonumber(ARGV[1]) -- max size configured for this batch
 local event = ARGV[2]                -- event to be appended to the batch
@@ -11,6 +14,10 @@ local prefix = ARGV[4]               -- the prefix used for redis
 
 local batchStatusAppending = ARGV[5]
 local batchStatusStarted = ARGV[6]
+local nowMS = ARGV[7]                -- current time in milliseconds, stored when the batch is created
+local maxPending = tonumber(ARGV[8]) -- max number of pending batches for the function, or 0 if unlimited
+local pendingTTL = tonumber(ARGV[9]) -- max age in milliseconds of a pending batch, after which it's no longer counted
+local dryRun = ARGV[10] == "1"       -- only check whether the event would overflow the function's pending batches
 
 -- helper functions
 -- $include(helpers.lua)
@@ -28,40 +35,113 @@ local function get_or_create_batch_key(key)
   return val
 end
 
--- start execution
-local batchID = get_or_create_batch_key(batchPointerKey)
-local resp = { status = "append", batchID = batchID }
-
 -- NOTE: these need to be identical to the ones in the queue key generator
 --   * Batch
 --   * BatchMetadata
 local keyfmt = "%s:batches:%s"
+
+-- functions with many batches in flight fill the first slot, only appending to
+-- the next slot while every earlier slot holds a full batch which hasn't yet
+-- started.  the last slot is always used if every other slot is full.
+local slots = { KEYS[1] }
+for i = 3, #KEYS do
+  table.insert(slots, KEYS[i])
+end
+
+local function is_full(key)
+  local val = redis.call("GET", key)
+  if is_empty(val) then
+    return false
+  end
+  return redis.call("LLEN", string.format(keyfmt, prefix, val)) >= batchLimit
+end
+
+local isLastSlot = true
+for i, key in ipairs(slots) do
+  if i == #slots or not is_full(key) then
+    batchPointerKey = key
+    isLastSlot = i == #slots
+    break
+  end
+end
+
+-- appending to an empty batch creates a new pending batch, which is rejected
+-- if the function already holds its maximum number of pending batches.
+local function overflows(batchKey)
+  if maxPending <= 0 or redis.call("LLEN", batchKey) > 0 then
+    return false
+  end
+  -- ignore batches which were never started, eg. if their keys expired.
+  redis.call("ZREMRANGEBYSCORE", batchPendingKey, "-inf", tonumber(nowMS) - pendingTTL)
+  return redis.call("ZCARD", batchPendingKey) >= maxPending
+end
+
+if dryRun then
+  local val = redis.call("GET", batchPointerKey)
+  if is_empty(val) then
+    val = newULID
+  end
+  if overflows(string.format(keyfmt, prefix, val)) then
+    return cjson.encode({ status = "overflow" })
+  end
+  return cjson.encode({ status = "append" })
+end
+
+-- start execution
+local batchID = get_or_create_batch_key(batchPointerKey)
+local resp = { status = "append", batchID = batchID }
+
 local batchKey = string.format(keyfmt, prefix, batchID)
 local batchMetadataKey = string.format("%s:metadata", batchKey)
 
+if overflows(batchKey) then
+  return cjson.encode({ status = "overflow" })
+end
+
 -- set the batch status if it doesn't exist but don't overwrite
 -- this is necessary for functions that never enabled batch before
 if is_status_empty(batchMetadataKey) then
   set_batch_status(batchMetadataKey, batchStatusAppending)
 end
 
+-- record the pointer used for this batch, as functions may have many batches
+-- in flight.  the pointer is updated when the batch starts.
+if is_meta_empty(batchMetadataKey, "pointer") then
+  redis.call("HSET", batchMetadataKey, "pointer", batchPointerKey)
+end
+
 -- append event to batch
 local len = redis.call("RPUSH", batchKey, event)
 
 if len == 1 then
   -- newly started batch
+  redis.call("HSET", batchMetadataKey, "createdAt", nowMS)
+  redis.call("ZADD", batchPendingKey, nowMS, batchID)
+  redis.call("PEXPIRE", batchPendingKey, pendingTTL)
   resp = { status = "new", batchID = batchID }
 end
 
+-- include when the batch was created, so that sliding batches can cap
+-- their timeout.
+local createdAt = redis.call("HGET", batchMetadataKey, "createdAt")
+if not is_empty(createdAt) then
+  resp["createdAt"] = createdAt
+end
+
 -- if batch is full
 if len >= batchLimit then
   if not is_status_empty(batchMetadataKey) then
     set_batch_status(batchMetadataKey, batchStatusStarted)
   end
 
-  -- change poiner so following ops don't append to this batch anymore
-  update_pointer(batchPointerKey, newULID)
-  resp = { status = "full", batchID = batchID }
+  -- change poiner so following ops don't append to this batch anymore.  full
+  -- batches in earlier slots keep their pointer until they start, so that
+  -- following ops append to the next slot instead.
+  if isLastSlot then
+    update_pointer(batchPointerKey, newULID)
+  end
+  redis.call("ZREM", batchPendingKey, batchID)
+  resp = { status = "full", batchID = batchID, createdAt = resp["createdAt"] }
 end
 
 return cjson.encode(resp)
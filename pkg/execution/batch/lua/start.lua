@@ -6,22 +6,37 @@
 --   1: Already started
 --
 local batchMetadataKey = KEYS[1] -- key for batch metadata
-local batchPointerKey = KEYS[2]  -- key for pointer
+local batchPointerKey = KEYS[2]  -- key for pointer, if the batch didn't record its pointer
+local batchPendingKey = KEYS[3]  -- key for the function's pending batches
 
 local batchStatusStarted = ARGV[1]
 local newBatchID = ARGV[2] -- the ULID for a new batch
+local batchID = ARGV[3]    -- the ULID for the batch being started
 
 -- $include(helpers.lua)
 
 local status = get_batch_status(batchMetadataKey)
 
+-- the batch is no longer pending once it starts
+redis.call("ZREM", batchPendingKey, batchID)
+
+-- functions with many batches in flight store the pointer for each batch.
+local pointer = redis.call("HGET", batchMetadataKey, "pointer")
+if not is_empty(pointer) then
+  batchPointerKey = pointer
+end
+
+-- only move the pointer if it still references this batch.  full batches keep
+-- their pointer until they start, even though they're already marked as started.
+if redis.call("GET", batchPointerKey) == batchID then
+  update_pointer(batchPointerKey, newBatchID)
+end
+
 -- return if already started
 if status == batchStatusStarted then
   return 1
 end
 
-update_pointer(batchPointerKey, newBatchID)
-
 if is_status_empty(batchMetadataKey) then
   -- status doesn't exist, something is wrong, abort
   return -1
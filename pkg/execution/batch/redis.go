@@ -19,18 +19,37 @@ import (
 	"github.com/redis/rueidis"
 )
 
-func NewRedisBatchManager(r rueidis.Client, k redis_state.BatchKeyGenerator, q redis_state.QueueManager) BatchManager {
-	return redisBatchManager{
+// RedisBatchManagerOpt configures the redis batch manager.
+type RedisBatchManagerOpt func(b *redisBatchManager)
+
+// WithMaxPendingBatches limits the number of pending batches, which hold items
+// but haven't yet started, that each function may hold at once.  Appending an
+// item which would create a new batch past the limit returns enums.BatchOverflow
+// without appending the item.  Zero disables the limit.
+func WithMaxPendingBatches(n int) RedisBatchManagerOpt {
+	return func(b *redisBatchManager) {
+		b.maxPending = n
+	}
+}
+
+func NewRedisBatchManager(r rueidis.Client, k redis_state.BatchKeyGenerator, q redis_state.QueueManager, opts ...RedisBatchManagerOpt) BatchManager {
+	b := redisBatchManager{
 		r: r,
 		k: k,
 		q: q,
 	}
+	for _, opt := range opts {
+		opt(&b)
+	}
+	return b
 }
 
 type redisBatchManager struct {
 	r rueidis.Client
 	k redis_state.BatchKeyGenerator
 	q redis_state.QueueManager
+
+	maxPending int
 }
 
 // Append add an item to a batch, and handle things slightly differently based on the batch sitation after
@@ -45,7 +64,29 @@ type redisBatchManager struct {
 //
 //  3. Neither #1 or #2
 //     No-op
+//
+// If the item would create a new batch and the function already holds its
+// maximum number of pending batches, the item isn't appended and the result's
+// status is enums.BatchOverflow.
 func (b redisBatchManager) Append(ctx context.Context, bi BatchItem, fn inngest.Function) (*BatchAppendResult, error) {
+	return b.append(ctx, bi, fn, false)
+}
+
+// Overflows returns whether an item appended to the function's batches would
+// overflow the function's maximum number of pending batches, without appending
+// an item.
+func (b redisBatchManager) Overflows(ctx context.Context, fn inngest.Function) (bool, error) {
+	if b.maxPending <= 0 || fn.EventBatch == nil {
+		return false, nil
+	}
+	res, err := b.append(ctx, BatchItem{FunctionID: fn.ID}, fn, true)
+	if err != nil {
+		return false, err
+	}
+	return res.Status == enums.BatchOverflow, nil
+}
+
+func (b redisBatchManager) append(ctx context.Context, bi BatchItem, fn inngest.Function, dryRun bool) (*BatchAppendResult, error) {
 	config := fn.EventBatch
 	if config == nil {
 		// TODO: this should not happen, report this to sentry or logs
@@ -55,6 +96,13 @@ func (b redisBatchManager) Append(ctx context.Context, bi BatchItem, fn inngest.
 	// script keys
 	keys := []string{
 		b.k.BatchPointer(ctx, bi.FunctionID),
+		b.k.BatchPending(ctx, bi.FunctionID),
+	}
+	// Events are appended to the first batch slot, and only spill over to the
+	// function's other slots while earlier slots hold full batches which haven't
+	// started.  Ordering across batches is best-effort.
+	for slot := 1; slot < config.InFlight(); slot++ {
+		keys = append(keys, b.k.BatchPointerSlot(ctx, bi.FunctionID, slot))
 	}
 
 	// script args
@@ -66,6 +114,10 @@ func (b redisBatchManager) Append(ctx context.Context, bi BatchItem, fn inngest.
 		b.k.QueuePrefix(),
 		enums.BatchStatusPending,
 		enums.BatchStatusStarted,
+		time.Now().UnixMilli(),
+		b.maxPending,
+		consts.MaxBatchTTL.Milliseconds(),
+		dryRun,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("error preparing batch: %w", err)
@@ -121,10 +173,12 @@ func (b redisBatchManager) StartExecution(ctx context.Context, fnID uuid.UUID, b
 	keys := []string{
 		b.k.BatchMetadata(ctx, batchID),
 		b.k.BatchPointer(ctx, fnID),
+		b.k.BatchPending(ctx, fnID),
 	}
 	args := []string{
 		enums.BatchStatusStarted.String(),
 		ulid.Make().String(),
+		batchID.String(),
 	}
 
 	status, err := scripts["start"].Exec(
@@ -150,6 +204,8 @@ func (b redisBatchManager) StartExecution(ctx context.Context, fnID uuid.UUID, b
 }
 
 // ScheduleExecution enqueues a job to run the batch job after the specified duration.
+// If the job already exists and opts.Reschedule is set, the existing job is moved to
+// run at opts.At instead.
 func (b redisBatchManager) ScheduleExecution(ctx context.Context, opts ScheduleBatchOpts) error {
 	jobID := fmt.Sprintf("%s:%s", opts.WorkspaceID, opts.BatchID)
 	maxAttempts := 20
@@ -171,6 +227,21 @@ func (b redisBatchManager) ScheduleExecution(ctx context.Context, opts ScheduleB
 		MaxAttempts: &maxAttempts,
 		Payload:     opts.ScheduleBatchPayload,
 	}, opts.At)
+	if err == redis_state.ErrQueueItemExists && opts.Reschedule {
+		err = b.q.RequeueByJobID(ctx, opts.FunctionID.String(), jobID, opts.At)
+		if err == redis_state.ErrQueueItemAlreadyLeased {
+			// The batch is already starting, so the job can't be moved.
+			log.From(ctx).
+				Debug().
+				Interface("job_id", jobID).
+				Msg("scheduled batch already in progress")
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("error rescheduling batch: %w", err)
+		}
+		return nil
+	}
 	if err == redis_state.ErrQueueItemExists {
 		log.From(ctx).
 			Debug().
@@ -0,0 +1,227 @@
+package batch
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/inngest/inngest/pkg/enums"
+	"github.com/inngest/inngest/pkg/event"
+	"github.com/inngest/inngest/pkg/execution/state/redis_state"
+	"github.com/inngest/inngest/pkg/inngest"
+	"github.com/oklog/ulid/v2"
+	"github.com/redis/rueidis"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestBatchManager(t *testing.T, opts ...RedisBatchManagerOpt) (BatchManager, rueidis.Client, *redis_state.DefaultQueueKeyGenerator) {
+	r := miniredis.RunT(t)
+	rc, err := rueidis.NewClient(rueidis.ClientOption{
+		InitAddress:  []string{r.Addr()},
+		DisableCache: true,
+	})
+	require.NoError(t, err)
+	t.Cleanup(rc.Close)
+
+	kg := &redis_state.DefaultQueueKeyGenerator{Prefix: "{queue}"}
+	q := redis_state.NewQueue(rc, redis_state.WithQueueKeyGenerator(kg))
+	return NewRedisBatchManager(rc, kg, q, opts...), rc, kg
+}
+
+func TestSlidingBatchSchedule(t *testing.T) {
+	ctx := context.Background()
+	bm, rc, kg := newTestBatchManager(t)
+
+	fn := inngest.Function{
+		ID:   uuid.New(),
+		Slug: "sliding",
+		EventBatch: &inngest.EventBatchConfig{
+			MaxSize: 10,
+			Timeout: "5s",
+			Mode:    inngest.BatchModeSliding,
+		},
+	}
+	item := func() BatchItem {
+		return BatchItem{
+			WorkspaceID: uuid.New(),
+			FunctionID:  fn.ID,
+			EventID:     ulid.Make(),
+			Event:       event.Event{Name: "test/event"},
+		}
+	}
+
+	now := time.Now()
+	first, err := bm.Append(ctx, item(), fn)
+	require.NoError(t, err)
+	require.Equal(t, enums.BatchNew, first.Status)
+	require.InDelta(t, now.UnixMilli(), first.CreatedAt, 1000)
+
+	second, err := bm.Append(ctx, item(), fn)
+	require.NoError(t, err)
+	require.Equal(t, enums.BatchAppend, second.Status)
+	require.Equal(t, first.BatchID, second.BatchID)
+	require.Equal(t, first.CreatedAt, second.CreatedAt)
+
+	opts := ScheduleBatchOpts{
+		ScheduleBatchPayload: ScheduleBatchPayload{
+			BatchID:    ulid.MustParse(first.BatchID),
+			FunctionID: fn.ID,
+		},
+		At: now.Add(5 * time.Second),
+	}
+	jobAt := func() int64 {
+		jobID := redis_state.HashID(ctx, fmt.Sprintf("%s:%s", opts.WorkspaceID, opts.BatchID))
+		qi := redis_state.QueueItem{}
+		require.NoError(t, rc.Do(ctx, rc.B().Hget().Key(kg.QueueItem()).Field(jobID).Build()).DecodeJSON(&qi))
+		return qi.AtMS
+	}
+
+	require.NoError(t, bm.ScheduleExecution(ctx, opts))
+	require.Equal(t, opts.At.UnixMilli(), jobAt())
+
+	// Without rescheduling, the existing job is left as-is.
+	opts.At = now.Add(8 * time.Second)
+	require.NoError(t, bm.ScheduleExecution(ctx, opts))
+	require.Equal(t, now.Add(5*time.Second).UnixMilli(), jobAt())
+
+	// Rescheduling moves the existing job.
+	opts.Reschedule = true
+	require.NoError(t, bm.ScheduleExecution(ctx, opts))
+	require.Equal(t, opts.At.UnixMilli(), jobAt())
+}
+
+func TestBatchesInFlight(t *testing.T) {
+	ctx := context.Background()
+	bm, rc, kg := newTestBatchManager(t)
+
+	fn := inngest.Function{
+		ID:   uuid.New(),
+		Slug: "in-flight",
+		EventBatch: &inngest.EventBatchConfig{
+			MaxSize:     2,
+			Timeout:     "5s",
+			MaxInFlight: 3,
+		},
+	}
+
+	appendEvent := func() *BatchAppendResult {
+		res, err := bm.Append(ctx, BatchItem{
+			FunctionID: fn.ID,
+			EventID:    ulid.Make(),
+			Event:      event.Event{Name: "test/event"},
+		}, fn)
+		require.NoError(t, err)
+		return res
+	}
+	pointer := func(slot int) string {
+		key := kg.BatchPointerSlot(ctx, fn.ID, slot)
+		id, err := rc.Do(ctx, rc.B().Get().Key(key).Build()).ToString()
+		require.NoError(t, err)
+		return id
+	}
+
+	// Events fill the first slot before using any other slot.
+	first := appendEvent()
+	require.Equal(t, enums.BatchNew, first.Status)
+	require.Equal(t, enums.BatchFull, appendEvent().Status)
+	require.Equal(t, first.BatchID, pointer(0))
+	exists, err := rc.Do(ctx, rc.B().Exists().Key(kg.BatchPointerSlot(ctx, fn.ID, 1)).Build()).AsBool()
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	// While the first batch hasn't started, events spill over to the next slot.
+	second := appendEvent()
+	require.Equal(t, enums.BatchNew, second.Status)
+	require.NotEqual(t, first.BatchID, second.BatchID)
+	require.Equal(t, second.BatchID, pointer(1))
+	require.Equal(t, enums.BatchFull, appendEvent().Status)
+
+	// Full batches in the last slot move its pointer immediately.
+	third := appendEvent()
+	require.Equal(t, third.BatchID, pointer(2))
+	require.Equal(t, enums.BatchFull, appendEvent().Status)
+	require.NotEqual(t, third.BatchID, pointer(2))
+
+	// Once the first batch starts, events are appended to the first slot again.
+	_, err = bm.StartExecution(ctx, fn.ID, ulid.MustParse(first.BatchID))
+	require.NoError(t, err)
+	require.NotEqual(t, first.BatchID, pointer(0))
+
+	res := appendEvent()
+	require.Equal(t, enums.BatchNew, res.Status)
+	require.Equal(t, pointer(0), res.BatchID)
+	require.Equal(t, second.BatchID, pointer(1))
+}
+
+func TestMaxPendingBatches(t *testing.T) {
+	ctx := context.Background()
+	bm, _, _ := newTestBatchManager(t, WithMaxPendingBatches(1))
+
+	fn := inngest.Function{
+		ID:   uuid.New(),
+		Slug: "max-pending",
+		EventBatch: &inngest.EventBatchConfig{
+			MaxSize:     2,
+			Timeout:     "5s",
+			MaxInFlight: 3,
+		},
+	}
+	appendItem := func() *BatchAppendResult {
+		res, err := bm.Append(ctx, BatchItem{
+			FunctionID: fn.ID,
+			EventID:    ulid.Make(),
+			Event:      event.Event{Name: "test/event"},
+		}, fn)
+		require.NoError(t, err)
+		return res
+	}
+
+	// The first batch fills, and the next event creates a pending batch in the
+	// second slot.
+	full := appendItem()
+	require.Equal(t, enums.BatchFull, appendItem().Status)
+	pending := appendItem()
+	require.Equal(t, enums.BatchNew, pending.Status)
+
+	// Once the full batch starts the first slot is free, but creating another
+	// batch would exceed the function's pending batches.
+	overflows, err := bm.(OverflowChecker).Overflows(ctx, fn)
+	require.NoError(t, err)
+	require.False(t, overflows)
+	_, err = bm.StartExecution(ctx, fn.ID, ulid.MustParse(full.BatchID))
+	require.NoError(t, err)
+	overflows, err = bm.(OverflowChecker).Overflows(ctx, fn)
+	require.NoError(t, err)
+	require.True(t, overflows)
+	res := appendItem()
+	require.Equal(t, enums.BatchOverflow, res.Status)
+	require.Empty(t, res.BatchID)
+
+	// Starting the pending batch allows another batch to be created.
+	_, err = bm.StartExecution(ctx, fn.ID, ulid.MustParse(pending.BatchID))
+	require.NoError(t, err)
+	require.Equal(t, enums.BatchNew, appendItem().Status)
+
+	t.Run("full batches are not pending", func(t *testing.T) {
+		bm, _, _ := newTestBatchManager(t, WithMaxPendingBatches(1))
+		fn := inngest.Function{
+			ID:         uuid.New(),
+			Slug:       "full",
+			EventBatch: &inngest.EventBatchConfig{MaxSize: 2, Timeout: "5s"},
+		}
+		statuses := []enums.Batch{}
+		for i := 0; i < 3; i++ {
+			res, err := bm.Append(ctx, BatchItem{
+				FunctionID: fn.ID,
+				EventID:    ulid.Make(),
+				Event:      event.Event{Name: "test/event"},
+			}, fn)
+			require.NoError(t, err)
+			statuses = append(statuses, res.Status)
+		}
+		require.Equal(t, []enums.Batch{enums.BatchNew, enums.BatchFull, enums.BatchNew}, statuses)
+	})
+}
@@ -0,0 +1,40 @@
+package cancellation
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/inngest/inngest/pkg/cqrs"
+)
+
+// NewCQRSReader loads cancellations from the given cqrs store, eg. the dev server's
+// database.
+func NewCQRSReader(r cqrs.CancellationReader) Reader {
+	return cqrsReader{r}
+}
+
+type cqrsReader struct {
+	r cqrs.CancellationReader
+}
+
+func (c cqrsReader) ReadAt(ctx context.Context, wsID uuid.UUID, fnID uuid.UUID, at time.Time) ([]cqrs.Cancellation, error) {
+	all, err := c.r.CancellationsByFunction(ctx, wsID, fnID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []cqrs.Cancellation{}
+	for _, c := range all {
+		if at.After(c.StartedBefore) {
+			// This cancellation is only for functions prior to the given point
+			// in time, so ignore.
+			continue
+		}
+		if c.StartedAfter != nil && at.Before(*c.StartedAfter) {
+			continue
+		}
+		result = append(result, c)
+	}
+	return result, nil
+}
@@ -0,0 +1,27 @@
+package driver
+
+import (
+	"context"
+
+	"github.com/inngest/inngest/pkg/execution/state"
+)
+
+// CheckpointFunc persists opcodes streamed by an SDK while its request is still
+// in progress.  Checkpointed opcodes only ever contain step outputs, ie.
+// OpcodeStep and OpcodeStepRun.
+type CheckpointFunc func(ctx context.Context, ops []*state.GeneratorOpcode) error
+
+type checkpointCtxKey struct{}
+
+// WithCheckpointer returns a context which allows drivers to checkpoint step
+// outputs as they're streamed, for drivers which support checkpointing.
+func WithCheckpointer(ctx context.Context, f CheckpointFunc) context.Context {
+	return context.WithValue(ctx, checkpointCtxKey{}, f)
+}
+
+// Checkpointer returns the checkpoint func stored in the context via
+// WithCheckpointer, or nil if checkpointing isn't supported.
+func Checkpointer(ctx context.Context) CheckpointFunc {
+	f, _ := ctx.Value(checkpointCtxKey{}).(CheckpointFunc)
+	return f
+}
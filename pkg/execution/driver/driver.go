@@ -26,15 +26,15 @@ type Driver interface {
 	) (*state.DriverResponse, error)
 }
 
-// MarshalV1 marshals state as an input to driver runtimes.
-func MarshalV1(
+// NewRequestV1 creates the request sent to driver runtimes from the run's state.
+func NewRequestV1(
 	ctx context.Context,
 	s state.State,
 	step inngest.Step,
 	stackIndex int,
 	env string,
 	attempt int,
-) ([]byte, error) {
+) *SDKRequest {
 	md := s.Metadata()
 
 	req := &SDKRequest{
@@ -56,6 +56,17 @@ func MarshalV1(
 		Version: md.RequestVersion,
 	}
 
+	if state.HasLazyEvents(s) {
+		// Only the first event was loaded, so the SDK fetches the batch from the
+		// API using the internal ID of each event.
+		id := s.Identifier()
+		req.Context.LazyEvents = true
+		req.Context.EventIDs = make([]string, len(id.EventIDs))
+		for n, evtID := range id.EventIDs {
+			req.Context.EventIDs[n] = evtID.String()
+		}
+	}
+
 	// empty the attrs that consume the most
 	if req.IsBodySizeTooLarge() {
 		req.Events = []map[string]any{}
@@ -64,6 +75,20 @@ func MarshalV1(
 		req.Context.UseAPI = true
 	}
 
+	return req
+}
+
+// MarshalV1 marshals state as an input to driver runtimes.
+func MarshalV1(
+	ctx context.Context,
+	s state.State,
+	step inngest.Step,
+	stackIndex int,
+	env string,
+	attempt int,
+) ([]byte, error) {
+	req := NewRequestV1(ctx, s, step, stackIndex, env, attempt)
+
 	j, err := json.Marshal(req)
 	if err != nil {
 		return nil, fmt.Errorf("error marshalling request to JSON: %w", err)
@@ -0,0 +1,55 @@
+package driver
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/inngest/inngest/pkg/execution/state"
+	"github.com/inngest/inngest/pkg/inngest"
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshalV1LazyBatchEvents(t *testing.T) {
+	ctx := context.Background()
+	fn := inngest.Function{ID: uuid.New()}
+	evtIDs := []ulid.ULID{ulid.Make(), ulid.Make(), ulid.Make()}
+	id := state.Identifier{WorkflowID: fn.ID, RunID: ulid.Make(), EventIDs: evtIDs}
+	events := []map[string]any{
+		{"id": "a", "name": "test/event", "data": map[string]any{"n": 1}},
+		{"id": "b", "name": "test/event", "data": map[string]any{"n": 2}},
+		{"id": "c", "name": "test/event", "data": map[string]any{"n": 3}},
+	}
+
+	marshal := func(s state.State) SDKRequest {
+		byt, err := MarshalV1(ctx, s, inngest.Step{ID: "step"}, 0, "", 0)
+		require.NoError(t, err)
+		req := SDKRequest{}
+		require.NoError(t, json.Unmarshal(byt, &req))
+		return req
+	}
+
+	// States with the full batch send every event.
+	req := marshal(state.NewStateInstance(fn, id, state.Metadata{}, events, map[string]any{}, map[string]error{}, []string{}))
+	require.Len(t, req.Events, 3)
+	require.False(t, req.Context.LazyEvents)
+	require.Empty(t, req.Context.EventIDs)
+
+	// States loaded lazily send the internal ID of each event for fetching the
+	// batch.
+	req = marshal(state.NewStateInstance(fn, id, state.Metadata{}, events[:1], map[string]any{}, map[string]error{}, []string{}))
+	require.Len(t, req.Events, 1)
+	require.Equal(t, "a", req.Events[0]["id"])
+	require.Equal(t, "a", req.Event["id"])
+	require.True(t, req.Context.LazyEvents)
+	require.Equal(t, []string{evtIDs[0].String(), evtIDs[1].String(), evtIDs[2].String()}, req.Context.EventIDs)
+
+	// Runs with a single event are unaffected.
+	single := id
+	single.EventIDs = evtIDs[:1]
+	req = marshal(state.NewStateInstance(fn, single, state.Metadata{}, events[:1], map[string]any{}, map[string]error{}, []string{}))
+	require.Len(t, req.Events, 1)
+	require.False(t, req.Context.LazyEvents)
+}
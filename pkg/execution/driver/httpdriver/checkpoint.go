@@ -0,0 +1,93 @@
+package httpdriver
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/inngest/inngest/pkg/enums"
+	"github.com/inngest/inngest/pkg/execution/driver"
+	"github.com/inngest/inngest/pkg/execution/state"
+)
+
+// headerCheckpoint is sent in requests to advertise that step outputs may be
+// checkpointed, and is set to "true" in responses by SDKs which stream
+// checkpoints.
+const headerCheckpoint = "x-inngest-checkpoint"
+
+// isCheckpointStream returns whether the response is a checkpointed generator
+// response.
+//
+// Checkpointed responses are 206 responses containing newline-delimited arrays
+// of opcodes, each written by the SDK as soon as it's available.  Every line
+// but the last must contain only step outputs, ie. OpcodeStep and
+// OpcodeStepRun, so that a single request can run several steps before
+// returning its final opcodes.
+func isCheckpointStream(resp *http.Response) bool {
+	return resp.StatusCode == 206 && resp.Header.Get(headerCheckpoint) == "true"
+}
+
+// readCheckpoints reads a checkpointed response, checkpointing lines which
+// contain only step outputs as they're read.  This returns the last line,
+// which is handled as a regular generator response, and the IDs of every
+// checkpointed step.
+//
+// Steps checkpointed before an error reading the response remain saved, so
+// that retries of the request memoize them.
+func readCheckpoints(ctx context.Context, r io.Reader, checkpoint driver.CheckpointFunc) ([]byte, []string, error) {
+	var (
+		body  []byte
+		ids   []string
+		final bool
+	)
+
+	br := bufio.NewReader(r)
+	for {
+		line, rerr := br.ReadBytes('\n')
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			if final {
+				return nil, ids, fmt.Errorf("checkpointed response continued after its final opcodes")
+			}
+			ops, err := ParseGenerator(ctx, line, false)
+			if err != nil {
+				return nil, ids, err
+			}
+			body = line
+
+			if isCheckpoint(ops) {
+				if err := checkpoint(ctx, ops); err != nil {
+					return nil, ids, fmt.Errorf("error checkpointing steps: %w", err)
+				}
+				for _, op := range ops {
+					ids = append(ids, op.ID)
+				}
+			} else {
+				final = true
+			}
+		}
+
+		if errors.Is(rerr, io.EOF) {
+			return body, ids, nil
+		}
+		if rerr != nil {
+			return nil, ids, fmt.Errorf("error reading checkpointed response: %w", rerr)
+		}
+	}
+}
+
+// isCheckpoint returns whether the opcodes may be checkpointed.
+func isCheckpoint(ops []*state.GeneratorOpcode) bool {
+	if len(ops) == 0 {
+		return false
+	}
+	for _, op := range ops {
+		if op == nil || (op.Op != enums.OpcodeStep && op.Op != enums.OpcodeStepRun) {
+			return false
+		}
+	}
+	return true
+}
@@ -1,8 +1,14 @@
 package httpdriver
 
 import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
 	"github.com/inngest/inngest/pkg/config/registration"
 	"github.com/inngest/inngest/pkg/execution/driver"
+	"golang.org/x/net/http/httpproxy"
 )
 
 func init() {
@@ -12,8 +18,56 @@ func init() {
 // Config represents driver configuration for use when configuring hosted
 // services via config.cue
 type Config struct {
+	// SigningKey signs requests to SDKs.
 	SigningKey string
-	Timeout    int
+	// PreviousSigningKey is the signing key being rotated out.  Responses and
+	// syncs signed with either key are accepted, allowing SDKs to move to the
+	// new key without downtime.
+	PreviousSigningKey string
+	// SignatureTolerance is the maximum age, in seconds, of signed responses
+	// and syncs before they're rejected.  This defaults to 5 minutes, and may
+	// need increasing for apps with skewed clocks.
+	SignatureTolerance int
+	Timeout            int
+	// TLS configures mutual TLS for requests to every app.
+	TLS *TLSConfig
+	// Proxy configures the proxy used for requests to every app.
+	Proxy *ProxyConfig
+	// Apps configures requests to specific apps, keyed by the host of the app's
+	// URL, eg. "payments.internal:8443".  App configuration overrides the
+	// global configuration.
+	Apps map[string]AppConfig
+}
+
+// AppConfig configures requests to a single app.
+type AppConfig struct {
+	// TLS configures mutual TLS for requests to the app.
+	TLS *TLSConfig
+	// Proxy configures the proxy used for requests to the app.
+	Proxy *ProxyConfig
+}
+
+// ProxyConfig configures an outbound HTTP(S) proxy.
+type ProxyConfig struct {
+	// HTTPProxy and HTTPSProxy are the URLs of the proxies used for http and
+	// https requests respectively.
+	HTTPProxy  string
+	HTTPSProxy string
+	// NoProxy lists hosts which are requested directly, in the same format
+	// as the NO_PROXY environment variable.
+	NoProxy string
+}
+
+// TLSConfig configures mutual TLS for requests to apps.
+type TLSConfig struct {
+	// CertFile and KeyFile are paths to the PEM encoded client certificate
+	// and key presented to apps.
+	CertFile string
+	KeyFile  string
+	// CAFile is a path to a PEM encoded CA bundle used to verify apps.  If
+	// set, apps are only trusted if their certificates are signed by this
+	// bundle.
+	CAFile string
 }
 
 // RuntimeName returns the runtime field that should invoke this driver.
@@ -23,5 +77,99 @@ func (Config) RuntimeName() string { return "http" }
 func (Config) DriverName() string { return "http" }
 
 func (c Config) NewDriver() (driver.Driver, error) {
-	return DefaultExecutor, nil
+	if c.SigningKey == "" && c.TLS == nil && c.Proxy == nil && len(c.Apps) == 0 {
+		return DefaultExecutor, nil
+	}
+
+	e := &executor{
+		Client:             DefaultExecutor.Client,
+		signingKey:         []byte(c.SigningKey),
+		previousSigningKey: []byte(c.PreviousSigningKey),
+		signatureTolerance: c.Tolerance(),
+		clients:            map[string]*http.Client{},
+	}
+
+	proxy := c.ProxyFunc()
+	if c.TLS != nil || proxy != nil {
+		client, err := newClient(c.TLS, proxy)
+		if err != nil {
+			return nil, fmt.Errorf("error configuring http driver tls: %w", err)
+		}
+		e.Client = client
+	}
+
+	for host, app := range c.Apps {
+		if app.TLS == nil {
+			// The app uses the global client, whose proxy handles any
+			// app-specific proxy config.
+			continue
+		}
+		client, err := newClient(app.TLS, proxy)
+		if err != nil {
+			return nil, fmt.Errorf("error configuring http driver tls for %s: %w", host, err)
+		}
+		e.clients[host] = client
+	}
+
+	return e, nil
+}
+
+// ProxyFunc returns a function which selects the proxy for requests to apps,
+// for use with http.Transport.  This returns nil if no proxies are configured.
+func (c Config) ProxyFunc() func(*http.Request) (*url.URL, error) {
+	apps := map[string]func(*url.URL) (*url.URL, error){}
+	for host, app := range c.Apps {
+		if app.Proxy != nil {
+			apps[host] = app.Proxy.proxyFunc()
+		}
+	}
+	if c.Proxy == nil && len(apps) == 0 {
+		return nil
+	}
+
+	var global func(*url.URL) (*url.URL, error)
+	if c.Proxy != nil {
+		global = c.Proxy.proxyFunc()
+	}
+
+	return func(req *http.Request) (*url.URL, error) {
+		if f, ok := apps[req.URL.Host]; ok {
+			return f(req.URL)
+		}
+		if f, ok := apps[req.URL.Hostname()]; ok {
+			return f(req.URL)
+		}
+		if global != nil {
+			return global(req.URL)
+		}
+		return nil, nil
+	}
+}
+
+func (p ProxyConfig) proxyFunc() func(*url.URL) (*url.URL, error) {
+	return (&httpproxy.Config{
+		HTTPProxy:  p.HTTPProxy,
+		HTTPSProxy: p.HTTPSProxy,
+		NoProxy:    p.NoProxy,
+	}).ProxyFunc()
+}
+
+// SigningKeys returns every configured signing key which may sign responses and
+// syncs, starting with the current key.
+func (c Config) SigningKeys() [][]byte {
+	keys := [][]byte{}
+	for _, k := range []string{c.SigningKey, c.PreviousSigningKey} {
+		if k != "" {
+			keys = append(keys, []byte(k))
+		}
+	}
+	return keys
+}
+
+// Tolerance returns the maximum age of signed responses and syncs.
+func (c Config) Tolerance() time.Duration {
+	if c.SignatureTolerance <= 0 {
+		return DefaultSignatureTolerance
+	}
+	return time.Duration(c.SignatureTolerance) * time.Second
 }
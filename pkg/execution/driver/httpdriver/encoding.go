@@ -0,0 +1,87 @@
+package httpdriver
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+const (
+	headerAcceptEncoding  = "Accept-Encoding"
+	headerContentEncoding = "Content-Encoding"
+	encodingGzip          = "gzip"
+
+	// gzipMinSize is the minimum request body size which is compressed.  Small
+	// bodies aren't worth the overhead.
+	gzipMinSize = 64 * 1024
+)
+
+// gzipHosts records the hosts of apps which accept gzipped request bodies.
+// Apps advertise support by including "gzip" in an Accept-Encoding header in
+// their responses, as per RFC 7694.
+var gzipHosts = &sync.Map{}
+
+// acceptsGzip returns whether the app at the given host accepts gzipped requests.
+func acceptsGzip(host string) bool {
+	_, ok := gzipHosts.Load(host)
+	return ok
+}
+
+// recordEncodings records the request encodings an app accepts from the headers
+// of its response.
+func recordEncodings(host string, h http.Header) {
+	for _, v := range h.Values(headerAcceptEncoding) {
+		for _, enc := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(enc), encodingGzip) {
+				gzipHosts.Store(host, struct{}{})
+				return
+			}
+		}
+	}
+	if len(h.Values(headerAcceptEncoding)) > 0 {
+		// The app has explicitly listed its encodings without gzip.
+		gzipHosts.Delete(host)
+	}
+}
+
+// streamBody returns a reader which writes the body as it's read, without
+// buffering the body in memory.
+func streamBody(body BodyWriter) io.ReadCloser {
+	pr, pw := io.Pipe()
+	go func() {
+		_ = pw.CloseWithError(body(pw))
+	}()
+	return pr
+}
+
+// gzipBody returns a reader which compresses the body as it's read, without
+// buffering the compressed body in memory.
+func gzipBody(body BodyWriter) io.ReadCloser {
+	return streamBody(func(w io.Writer) error {
+		zw := gzip.NewWriter(w)
+		if err := body(zw); err != nil {
+			return err
+		}
+		return zw.Close()
+	})
+}
+
+// decodeBody returns a reader for the response body, decompressing the body as
+// it's read if the app gzipped its response.
+func decodeBody(resp *http.Response) (io.ReadCloser, error) {
+	switch strings.ToLower(resp.Header.Get(headerContentEncoding)) {
+	case "", "identity":
+		return resp.Body, nil
+	case encodingGzip:
+		zr, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("error reading gzipped response body: %w", err)
+		}
+		return zr, nil
+	default:
+		return nil, fmt.Errorf("unsupported response content encoding: %s", resp.Header.Get(headerContentEncoding))
+	}
+}
@@ -3,6 +3,7 @@ package httpdriver
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -55,8 +56,23 @@ var (
 )
 
 type executor struct {
-	Client     *http.Client
-	signingKey []byte
+	Client             *http.Client
+	signingKey         []byte
+	previousSigningKey []byte
+	signatureTolerance time.Duration
+	// clients stores app-specific clients keyed by the app's host.
+	clients map[string]*http.Client
+}
+
+// client returns the HTTP client used to make requests to the given URL.
+func (e executor) client(u *url.URL) *http.Client {
+	if c, ok := e.clients[u.Host]; ok {
+		return c
+	}
+	if c, ok := e.clients[u.Hostname()]; ok {
+		return c
+	}
+	return e.Client
 }
 
 // RuntimeType fulfiils the inngest.Runtime interface.
@@ -70,17 +86,14 @@ func (e executor) Execute(ctx context.Context, s state.State, item queue.Item, e
 		return nil, err
 	}
 
-	input, err := driver.MarshalV1(ctx, s, step, idx, "", attempt)
-	if err != nil {
-		return nil, err
-	}
-
-	return DoRequest(ctx, e.Client, Request{
-		SigningKey: e.signingKey,
-		URL:        *uri,
-		Input:      input,
-		Edge:       edge,
-		Step:       step,
+	return DoRequest(ctx, e.client(uri), Request{
+		SigningKey:         e.signingKey,
+		PreviousSigningKey: e.previousSigningKey,
+		SignatureTolerance: e.signatureTolerance,
+		URL:                *uri,
+		Payload:            driver.NewRequestV1(ctx, s, step, idx, "", attempt),
+		Edge:               edge,
+		Step:               step,
 	})
 }
 
@@ -93,12 +106,21 @@ type Request struct {
 	// Signature, if set, is the signature to use for the request.  If unset,
 	// the SigningKey below will be used to sign the input.
 	Signature string
-	// SigningKey, if set, signs the input using this key.
+	// SigningKey, if set, signs the input using this key.  Signed responses
+	// are verified using this key or PreviousSigningKey.
 	SigningKey []byte
-	URL        url.URL
-	Input      []byte
-	Edge       inngest.Edge
-	Step       inngest.Step
+	// PreviousSigningKey is the signing key being rotated out, which is
+	// accepted when verifying signed responses.
+	PreviousSigningKey []byte
+	// SignatureTolerance is the maximum age of signed responses, defaulting
+	// to DefaultSignatureTolerance.
+	SignatureTolerance time.Duration
+	URL                url.URL
+	Input              []byte
+	// Payload, if set, is encoded as the request body in place of Input.
+	Payload *driver.SDKRequest
+	Edge    inngest.Edge
+	Step    inngest.Step
 }
 
 // DoRequest executes the HTTP request with the given input.
@@ -132,17 +154,21 @@ func DoRequest(ctx context.Context, c *http.Client, r Request) (*state.DriverRes
 			Step:           r.Step,
 			Duration:       resp.duration,
 			Output:         string(resp.body),
-			OutputSize:     len(resp.body),
+			OutputSize:     resp.size,
 			NoRetry:        resp.noRetry,
 			RetryAt:        resp.retryAt,
 			RequestVersion: resp.requestVersion,
 			StatusCode:     resp.statusCode,
 			SDK:            resp.sdk,
 			Header:         resp.header,
+			Checkpointed:   resp.checkpointed,
 		}
-		dr.Generator, err = ParseGenerator(ctx, resp.body, resp.noRetry)
-		if err != nil {
-			return nil, err
+		dr.Generator = resp.generator
+		if dr.Generator == nil {
+			dr.Generator, err = ParseGenerator(ctx, resp.body, resp.noRetry)
+			if err != nil {
+				return nil, err
+			}
 		}
 
 		// NOTE: Generator responses never set dr.Err, as we assume that the
@@ -164,13 +190,14 @@ func DoRequest(ctx context.Context, c *http.Client, r Request) (*state.DriverRes
 		Step:           r.Step,
 		Output:         body,
 		Duration:       resp.duration,
-		OutputSize:     len(resp.body),
+		OutputSize:     resp.size,
 		NoRetry:        resp.noRetry,
 		RetryAt:        resp.retryAt,
 		RequestVersion: resp.requestVersion,
 		StatusCode:     resp.statusCode,
 		SDK:            resp.sdk,
 		Header:         resp.header,
+		Logs:           parseLogs(resp.body),
 	}
 	if resp.statusCode < 200 || resp.statusCode > 299 {
 		// Add an error to driver.Response if the status code isn't 2XX.
@@ -206,21 +233,70 @@ func do(ctx context.Context, c *http.Client, r Request) (*response, error) {
 	ctx, cancel := context.WithTimeout(ctx, consts.MaxFunctionTimeout)
 	defer cancel()
 
+	// Payloads are encoded once, and the encoded body is reused to size, sign
+	// and send the request.
+	if r.Payload != nil {
+		input, err := json.Marshal(r.Payload)
+		if err != nil {
+			return nil, fmt.Errorf("error marshalling request to JSON: %w", err)
+		}
+		r.Input = input
+	}
+
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.URL.String(), bytes.NewBuffer(r.Input))
 	if err != nil {
 		return nil, fmt.Errorf("error creating request: %w", err)
 	}
-	req.Header.Add("Content-Type", "application/json")
+
+	reqBody := bytesBody(r.Input)
+	// Add any headers injected by driver middleware.  These are added first so
+	// that the driver's own headers, such as the signature, take precedence.
+	for k, v := range driver.Headers(ctx) {
+		req.Header[k] = append([]string{}, v...)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	// Request compressed responses.  Setting this explicitly disables the transport's
+	// transparent decompression, so that we decompress as we read the body.
+	req.Header.Set(headerAcceptEncoding, encodingGzip)
+	// Advertise that the SDK may stream step outputs as checkpoints.
+	checkpoint := driver.Checkpointer(ctx)
+	if checkpoint != nil {
+		req.Header.Set(headerCheckpoint, "true")
+	}
+
+	// Compress large inputs, eg. those with lots of memoized state, if the app
+	// accepts compressed requests.
+	if len(r.Input) >= gzipMinSize && acceptsGzip(r.URL.Host) {
+		req.Body = gzipBody(reqBody)
+		req.GetBody = func() (io.ReadCloser, error) {
+			return gzipBody(reqBody), nil
+		}
+		req.ContentLength = -1
+		req.Header.Set(headerContentEncoding, encodingGzip)
+	}
 
 	// Always close the request after reading the body, ensuring the connection is not recycled.
 	req.Close = true
 
 	if len(r.SigningKey) > 0 {
-		req.Header.Add("X-Inngest-Signature", Sign(ctx, r.SigningKey, r.Input))
+		// Advertise v2 signature support, and use v2 signatures for apps which
+		// have advertised support in turn.  V2 signatures cover every header
+		// set so far.
+		req.Header.Set(headerSignatureVersions, signatureV1+","+signatureV2)
+		var sig string
+		if acceptsSignatureV2(r.URL.Host) {
+			sig, err = SignV2Stream(ctx, r.SigningKey, req.Header, reqBody)
+		} else {
+			sig, err = SignStream(ctx, r.SigningKey, reqBody)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error signing request: %w", err)
+		}
+		req.Header.Set(headerSignature, sig)
 	}
 	if len(r.Signature) > 0 {
 		// Use this if provided, and override any sig added.
-		req.Header.Add("X-Inngest-Signature", r.Signature)
+		req.Header.Set(headerSignature, r.Signature)
 	}
 
 	// Add `traceparent` and `tracestate` headers to the request from `ctx`
@@ -268,9 +344,71 @@ func do(ctx context.Context, c *http.Client, r Request) (*response, error) {
 		}
 	}
 
-	byt, err := io.ReadAll(io.LimitReader(resp.Body, consts.MaxBodySize))
+	recordEncodings(r.URL.Host, resp.Header)
+	recordSignatureVersions(r.URL.Host, resp.Header)
+
+	reader, err := decodeBody(resp)
 	if err != nil {
-		return nil, fmt.Errorf("error reading response body: %w", err)
+		return nil, err
+	}
+
+	var (
+		byt          []byte
+		size         int
+		generator    []*state.GeneratorOpcode
+		checkpointed []string
+		sig          = resp.Header.Get(headerSignature)
+		signed       = len(r.SigningKey) > 0 || len(r.PreviousSigningKey) > 0
+		stream       = checkpoint != nil && isCheckpointStream(resp)
+	)
+
+	// Responses must be signed once a signing key is set, so that removing the
+	// signature can't bypass verification.
+	if signed && sig == "" {
+		return nil, fmt.Errorf("Invalid response signature from SDK: %w", ErrMissingSignature)
+	}
+
+	switch {
+	case stream && !signed:
+		// Checkpoint steps as they're streamed.
+		byt, checkpointed, err = readCheckpoints(ctx, io.LimitReader(reader, consts.MaxBodySize), checkpoint)
+		if err != nil {
+			return nil, err
+		}
+	case resp.StatusCode == 206 && !signed:
+		// Decode generator opcodes as they're read.  Signed responses must be
+		// verified before they're parsed.
+		noRetry := !shouldRetry(resp.StatusCode, resp.Header.Get(headerNoRetry), resp.Header.Get(headerSDK))
+		byt, size, generator, err = DecodeGenerator(ctx, io.LimitReader(reader, consts.MaxBodySize), noRetry)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		byt, err = io.ReadAll(io.LimitReader(reader, consts.MaxBodySize))
+		if err != nil {
+			return nil, fmt.Errorf("error reading response body: %w", err)
+		}
+	}
+
+	// SDKs may sign their responses.  Accept responses signed with either the
+	// current or previous signing key so that keys can be rotated across apps.
+	if signed {
+		verifier := SignatureVerifier{
+			Keys:      [][]byte{r.SigningKey, r.PreviousSigningKey},
+			Tolerance: r.SignatureTolerance,
+		}
+		if err := verifier.Verify(ctx, sig, resp.Header, byt); err != nil {
+			return nil, fmt.Errorf("Invalid response signature from SDK: %w", err)
+		}
+	}
+
+	if stream && signed {
+		// Signed streams are only checkpointed once the entire response has
+		// been verified, so that unverified output is never saved.
+		byt, checkpointed, err = readCheckpoints(ctx, bytes.NewReader(byt), checkpoint)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
@@ -330,6 +468,10 @@ func do(ctx context.Context, c *http.Client, r Request) (*response, error) {
 			headers[k] = v
 		}
 	}
+	if generator == nil {
+		// Decoded generator responses retain only a prefix of the body.
+		size = len(body)
+	}
 
 	// Check the retry status from the headers and versions.
 	noRetry = !shouldRetry(statusCode, headers[headerNoRetry], headers[headerSDK])
@@ -348,6 +490,7 @@ func do(ctx context.Context, c *http.Client, r Request) (*response, error) {
 	rv, _ := strconv.Atoi(headers[headerRequestVersion])
 	return &response{
 		body:           body,
+		size:           size,
 		statusCode:     statusCode,
 		duration:       dur,
 		retryAt:        retryAt,
@@ -355,12 +498,18 @@ func do(ctx context.Context, c *http.Client, r Request) (*response, error) {
 		requestVersion: rv,
 		sdk:            headers[headerSDK],
 		header:         resp.Header,
+		generator:      generator,
+		checkpointed:   checkpointed,
 	}, err
 
 }
 
 type response struct {
-	body           []byte
+	// body is the response body.  Only a prefix of the body is retained for
+	// generator responses decoded as they're read.
+	body []byte
+	// size is the size of the entire response body.
+	size           int
 	statusCode     int
 	duration       time.Duration
 	requestVersion int
@@ -376,4 +525,10 @@ type response struct {
 	sdk string
 
 	header http.Header
+	// generator contains the opcodes decoded from generator responses, if the
+	// response was decoded as it was read.
+	generator []*state.GeneratorOpcode
+	// checkpointed lists the IDs of steps checkpointed while reading a
+	// checkpointed response.
+	checkpointed []string
 }
@@ -1,18 +1,32 @@
 package httpdriver
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/json"
+	"encoding/pem"
 	"io"
+	"math/big"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/inngest/inngest/pkg/consts"
 	"github.com/inngest/inngest/pkg/enums"
+	"github.com/inngest/inngest/pkg/execution/driver"
 	"github.com/inngest/inngest/pkg/execution/state"
 	"github.com/stretchr/testify/require"
 )
@@ -78,6 +92,21 @@ func TestRetryAfter(t *testing.T) {
 	}
 }
 
+func TestErrorLogs(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(500)
+		_, _ = w.Write([]byte(`{"name":"Error","message":"boom","logs":[{"level":"error","msg":"failed","fields":{"user":"u1"}}]}`))
+	}))
+	defer ts.Close()
+
+	// Logs sent alongside errors thrown from function code are kept, as these
+	// responses don't include any opcodes.
+	dr, err := DoRequest(context.Background(), DefaultClient, Request{URL: parseURL(ts.URL), Input: []byte(`{}`)})
+	require.Error(t, err)
+	require.NotNil(t, dr.Err)
+	require.Equal(t, []state.LogLine{{Level: "error", Message: "failed", Fields: map[string]any{"user": "u1"}}}, dr.Logs)
+}
+
 func TestParseRetry(t *testing.T) {
 	now := time.Now().Truncate(time.Second).UTC()
 
@@ -184,3 +213,459 @@ func TestParseStream(t *testing.T) {
 		require.Equal(t, r, *actual)
 	})
 }
+
+func TestValidateSignature(t *testing.T) {
+	ctx := context.Background()
+	body := []byte(`{"event":{"name":"hi","data":{}}}`)
+	current, previous := []byte("current"), []byte("previous")
+
+	sig := Sign(ctx, previous, body)
+	require.NoError(t, ValidateSignature(ctx, sig, body, current, previous))
+	require.ErrorIs(t, ValidateSignature(ctx, sig, body, current), ErrInvalidSignature)
+	require.ErrorIs(t, ValidateSignature(ctx, sig, []byte("{}"), current, previous), ErrInvalidSignature)
+	require.ErrorIs(t, ValidateSignature(ctx, "lol", body, current), ErrInvalidSignature)
+
+	old := time.Now().Add(-time.Hour).Unix()
+	expired := "t=" + strconv.FormatInt(old, 10) + "&s=" + sign(current, body, old)
+	require.ErrorIs(t, ValidateSignature(ctx, expired, body, current), ErrExpiredSignature)
+}
+
+func TestSignatureVerifier(t *testing.T) {
+	ctx := context.Background()
+	body := []byte(`{"event":{"name":"hi","data":{}}}`)
+	current, previous := []byte("current"), []byte("previous")
+
+	t.Run("It uses the configured tolerance", func(t *testing.T) {
+		old := time.Now().Add(-10 * time.Minute).Unix()
+		sig := "t=" + strconv.FormatInt(old, 10) + "&s=" + sign(current, body, old)
+		require.ErrorIs(t, SignatureVerifier{Keys: [][]byte{current}}.Verify(ctx, sig, nil, body), ErrExpiredSignature)
+		require.NoError(t, SignatureVerifier{Keys: [][]byte{current}, Tolerance: time.Hour}.Verify(ctx, sig, nil, body))
+
+		future := time.Now().Add(10 * time.Minute).Unix()
+		sig = "t=" + strconv.FormatInt(future, 10) + "&s=" + sign(current, body, future)
+		require.ErrorIs(t, SignatureVerifier{Keys: [][]byte{current}}.Verify(ctx, sig, nil, body), ErrExpiredSignature)
+	})
+
+	t.Run("It verifies v2 signatures covering headers", func(t *testing.T) {
+		h := http.Header{}
+		h.Set("Content-Type", "application/json")
+		h.Set("X-Inngest-Env", "prod")
+		sig := SignV2(ctx, previous, h, body)
+
+		values, err := url.ParseQuery(sig)
+		require.NoError(t, err)
+		require.Equal(t, "2", values.Get("v"))
+		require.Equal(t, KeyID(previous), values.Get("k"))
+		require.Equal(t, "content-type,x-inngest-env", values.Get("h"))
+
+		v := SignatureVerifier{Keys: [][]byte{current, previous}}
+		require.NoError(t, v.Verify(ctx, sig, h, body))
+		require.ErrorIs(t, v.Verify(ctx, sig, h, []byte("{}")), ErrInvalidSignature)
+		require.ErrorIs(t, SignatureVerifier{Keys: [][]byte{current}}.Verify(ctx, sig, h, body), ErrInvalidSignature)
+
+		// Changing a signed header invalidates the signature.
+		changed := h.Clone()
+		changed.Set("X-Inngest-Env", "test")
+		require.ErrorIs(t, v.Verify(ctx, sig, changed, body), ErrInvalidSignature)
+		// Unsigned headers may change.
+		changed = h.Clone()
+		changed.Set("Traceparent", "00-abc")
+		require.NoError(t, v.Verify(ctx, sig, changed, body))
+	})
+}
+
+func TestSignatureNegotiation(t *testing.T) {
+	input := []byte(`{"event":{"name":"hi","data":{}}}`)
+	output := []byte(`"ok"`)
+	key := []byte("current")
+
+	var versions []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		byt, _ := io.ReadAll(r.Body)
+		require.Equal(t, "v1,v2", r.Header.Get(headerSignatureVersions))
+		sig := r.Header.Get(headerSignature)
+		values, _ := url.ParseQuery(sig)
+		versions = append(versions, values.Get("v"))
+		require.NoError(t, SignatureVerifier{Keys: [][]byte{key}}.Verify(r.Context(), sig, r.Header, byt))
+
+		// Advertise v2 support, and sign the response using v2.
+		w.Header().Set(headerSignatureVersions, "v1, v2")
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set(headerSignature, SignV2(r.Context(), key, http.Header{"Content-Type": {"application/json"}}, output))
+		_, _ = w.Write(output)
+	}))
+	defer ts.Close()
+
+	req := Request{URL: parseURL(ts.URL), Input: input, SigningKey: key}
+	for i := 0; i < 2; i++ {
+		res, err := do(context.Background(), DefaultClient, req)
+		require.NoError(t, err)
+		require.Equal(t, output, res.body)
+	}
+	// The first request uses a v1 signature, as the app hasn't advertised v2 support.
+	require.Equal(t, []string{"", "2"}, versions)
+}
+
+func TestSignedResponses(t *testing.T) {
+	input := []byte(`{"event":{"name":"hi","data":{}}}`)
+	output := []byte(`"ok"`)
+	current, previous := []byte("current"), []byte("previous")
+
+	responseKey := previous
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		byt, _ := io.ReadAll(r.Body)
+		// Requests are always signed using the current key.
+		require.NoError(t, ValidateSignature(r.Context(), r.Header.Get(headerSignature), byt, current))
+		if responseKey != nil {
+			w.Header().Set(headerSignature, Sign(r.Context(), responseKey, output))
+		}
+		_, _ = w.Write(output)
+	}))
+	defer ts.Close()
+
+	req := Request{
+		URL:                parseURL(ts.URL),
+		Input:              input,
+		SigningKey:         current,
+		PreviousSigningKey: previous,
+	}
+	res, err := do(context.Background(), DefaultClient, req)
+	require.NoError(t, err)
+	require.Equal(t, output, res.body)
+
+	responseKey = []byte("unknown")
+	_, err = do(context.Background(), DefaultClient, req)
+	require.ErrorIs(t, err, ErrInvalidSignature)
+
+	// Unsigned responses are rejected once a signing key is set.
+	responseKey = nil
+	_, err = do(context.Background(), DefaultClient, req)
+	require.ErrorIs(t, err, ErrMissingSignature)
+}
+
+func TestMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca, caKey := newTestCert(t, nil, nil, dir, "ca")
+
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`"ok"`))
+	}))
+	pool := x509.NewCertPool()
+	pool.AddCert(ca)
+	ts.TLS = &tls.Config{ClientCAs: pool, ClientAuth: tls.RequireAndVerifyClientCert}
+	ts.StartTLS()
+	defer ts.Close()
+
+	// Trust the test server's certificate.
+	serverCA := filepath.Join(dir, "server-ca.pem")
+	err := os.WriteFile(serverCA, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw}), 0600)
+	require.NoError(t, err)
+
+	u := parseURL(ts.URL)
+	req := Request{URL: u, Input: []byte(`{}`)}
+
+	t.Run("Without a client certificate", func(t *testing.T) {
+		client, err := newClient(&TLSConfig{CAFile: serverCA}, nil)
+		require.NoError(t, err)
+		_, err = do(context.Background(), client, req)
+		require.Error(t, err)
+	})
+
+	t.Run("With a per-app client certificate", func(t *testing.T) {
+		newTestCert(t, ca, caKey, dir, "client")
+		d, err := Config{
+			Apps: map[string]AppConfig{
+				u.Host: {
+					TLS: &TLSConfig{
+						CertFile: filepath.Join(dir, "client.pem"),
+						KeyFile:  filepath.Join(dir, "client-key.pem"),
+						CAFile:   serverCA,
+					},
+				},
+			},
+		}.NewDriver()
+		require.NoError(t, err)
+
+		e := d.(*executor)
+		require.Equal(t, DefaultClient, e.client(&url.URL{Host: "other:80"}))
+		res, err := do(context.Background(), e.client(&u), req)
+		require.NoError(t, err)
+		require.Equal(t, 200, res.statusCode)
+	})
+}
+
+// newTestCert creates a certificate signed by the given parent, or a self-signed
+// CA if parent is nil, writing the PEM encoded certificate and key to dir.
+func newTestCert(t *testing.T, parent *x509.Certificate, parentKey *ecdsa.PrivateKey, dir, name string) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	if parent == nil {
+		tmpl.IsCA = true
+		tmpl.BasicConstraintsValid = true
+		tmpl.KeyUsage = x509.KeyUsageCertSign
+		parent, parentKey = tmpl, key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, &key.PublicKey, parentKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	err = os.WriteFile(filepath.Join(dir, name+".pem"), pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600)
+	require.NoError(t, err)
+	err = os.WriteFile(filepath.Join(dir, name+"-key.pem"), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600)
+	require.NoError(t, err)
+	return cert, key
+}
+
+func TestProxyFunc(t *testing.T) {
+	require.Nil(t, Config{}.ProxyFunc())
+
+	proxy := Config{
+		Proxy: &ProxyConfig{
+			HTTPProxy:  "http://proxy.corp:3128",
+			HTTPSProxy: "http://proxy.corp:3129",
+			NoProxy:    "internal.corp",
+		},
+		Apps: map[string]AppConfig{
+			"payments.example.com": {Proxy: &ProxyConfig{HTTPSProxy: "http://payments-proxy:8080"}},
+		},
+	}.ProxyFunc()
+	require.NotNil(t, proxy)
+
+	tests := map[string]string{
+		"http://app.example.com/api/inngest":       "http://proxy.corp:3128",
+		"https://app.example.com/api/inngest":      "http://proxy.corp:3129",
+		"https://svc.internal.corp/api/inngest":    "",
+		"https://payments.example.com/api/inngest": "http://payments-proxy:8080",
+	}
+	for target, expected := range tests {
+		req, err := http.NewRequest(http.MethodPost, target, nil)
+		require.NoError(t, err)
+		u, err := proxy(req)
+		require.NoError(t, err)
+		if expected == "" {
+			require.Nil(t, u, target)
+			continue
+		}
+		require.Equal(t, expected, u.String(), target)
+	}
+}
+
+func TestGzipNegotiation(t *testing.T) {
+	input := []byte(`{"steps":"` + string(bytes.Repeat([]byte("a"), gzipMinSize)) + `"}`)
+	output := []byte(`"ok"`)
+
+	var encodings []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		encodings = append(encodings, r.Header.Get(headerContentEncoding))
+
+		var body io.Reader = r.Body
+		if r.Header.Get(headerContentEncoding) == encodingGzip {
+			zr, err := gzip.NewReader(r.Body)
+			require.NoError(t, err)
+			body = zr
+		}
+		byt, err := io.ReadAll(body)
+		require.NoError(t, err)
+		require.Equal(t, input, byt)
+
+		// Advertise support for gzipped requests, and gzip the response.
+		require.Equal(t, encodingGzip, r.Header.Get(headerAcceptEncoding))
+		w.Header().Set(headerAcceptEncoding, encodingGzip)
+		w.Header().Set(headerContentEncoding, encodingGzip)
+		zw := gzip.NewWriter(w)
+		_, _ = zw.Write(output)
+		_ = zw.Close()
+	}))
+	defer ts.Close()
+
+	req := Request{URL: parseURL(ts.URL), Input: input}
+	for i := 0; i < 2; i++ {
+		res, err := do(context.Background(), DefaultClient, req)
+		require.NoError(t, err)
+		require.Equal(t, output, res.body)
+	}
+	// The first request is sent uncompressed, as the app hasn't advertised gzip support.
+	require.Equal(t, []string{"", encodingGzip}, encodings)
+}
+
+func TestCheckpointedResponses(t *testing.T) {
+	input := []byte(`{"event":{"name":"hi","data":{}}}`)
+	checkpointed := make(chan []*state.GeneratorOpcode, 2)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "true", r.Header.Get(headerCheckpoint))
+		w.Header().Set(headerCheckpoint, "true")
+		w.WriteHeader(206)
+
+		_, _ = w.Write([]byte(`[{"op":"StepRun","id":"a","data":1}]` + "\n"))
+		w.(http.Flusher).Flush()
+		// The first step must be checkpointed before the request finishes.
+		select {
+		case <-checkpointed:
+		case <-time.After(5 * time.Second):
+			t.Error("step wasn't checkpointed while streaming")
+		}
+
+		_, _ = w.Write([]byte(`[{"op":"StepRun","id":"b","data":2}]` + "\n"))
+		_, _ = w.Write([]byte(`[{"op":"Sleep","id":"c","name":"1s"}]` + "\n"))
+	}))
+	defer ts.Close()
+
+	ctx := driver.WithCheckpointer(context.Background(), func(ctx context.Context, ops []*state.GeneratorOpcode) error {
+		checkpointed <- ops
+		return nil
+	})
+
+	res, err := DoRequest(ctx, DefaultClient, Request{URL: parseURL(ts.URL), Input: input})
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b"}, res.Checkpointed)
+	require.Len(t, res.Generator, 1)
+	require.Equal(t, enums.OpcodeSleep, res.Generator[0].Op)
+
+	ops := <-checkpointed
+	require.Len(t, ops, 1)
+	require.Equal(t, "b", ops[0].ID)
+
+	t.Run("opcodes after the final opcodes error", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set(headerCheckpoint, "true")
+			w.WriteHeader(206)
+			_, _ = w.Write([]byte(`[{"op":"Sleep","id":"c","name":"1s"}]` + "\n"))
+			_, _ = w.Write([]byte(`[{"op":"StepRun","id":"a","data":1}]` + "\n"))
+		}))
+		defer ts.Close()
+
+		_, err := DoRequest(ctx, DefaultClient, Request{URL: parseURL(ts.URL), Input: input})
+		require.Error(t, err)
+	})
+
+	t.Run("without a checkpointer", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Empty(t, r.Header.Get(headerCheckpoint))
+			w.WriteHeader(206)
+			_, _ = w.Write([]byte(`[{"op":"StepRun","id":"a","data":1}]`))
+		}))
+		defer ts.Close()
+
+		res, err := DoRequest(context.Background(), DefaultClient, Request{URL: parseURL(ts.URL), Input: input})
+		require.NoError(t, err)
+		require.Empty(t, res.Checkpointed)
+		require.Len(t, res.Generator, 1)
+	})
+}
+
+// countingOutput is a step output which counts the times it's encoded.
+type countingOutput struct {
+	calls int
+}
+
+func (c *countingOutput) MarshalJSON() ([]byte, error) {
+	c.calls++
+	return []byte(`"<counted>"`), nil
+}
+
+func TestPayloadRequest(t *testing.T) {
+	key := []byte("signing-key")
+	output := &countingOutput{}
+	payload := &driver.SDKRequest{
+		Actions: map[string]any{"a": "<b>&</b>", "b": output},
+		Context: &driver.SDKRequestContext{StepID: "step"},
+	}
+	expected, err := json.Marshal(payload)
+	require.NoError(t, err)
+	output.calls = 0
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.EqualValues(t, len(body), r.ContentLength)
+		require.NoError(t, ValidateSignature(r.Context(), r.Header.Get(headerSignature), body, key))
+
+		// The payload is encoded as with json.Marshal.
+		require.Equal(t, string(expected), string(body))
+
+		resp := []byte(`[{"op":"Step","id":"step","data":"ok"}, {"op":"Sleep","id":"sleep"}]`)
+		w.Header().Set(headerSignature, Sign(r.Context(), key, resp))
+		w.WriteHeader(206)
+		_, _ = w.Write(resp)
+	}))
+	defer ts.Close()
+
+	res, err := do(context.Background(), DefaultClient, Request{
+		URL:        parseURL(ts.URL),
+		SigningKey: key,
+		Payload:    payload,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 206, res.statusCode)
+	// The payload is encoded once to size, sign and send the request.
+	require.Equal(t, 1, output.calls)
+	// Signed responses are decoded once they've been verified.
+	generator, err := ParseGenerator(context.Background(), res.body, false)
+	require.NoError(t, err)
+	require.Len(t, generator, 2)
+	require.Equal(t, enums.OpcodeStep, generator[0].Op)
+	require.Equal(t, enums.OpcodeSleep, generator[1].Op)
+}
+
+func TestDecodeGenerator(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		body string
+		ops  []enums.Opcode
+		err  bool
+	}{
+		{body: `{"op":"Step","id":"a"}`, ops: []enums.Opcode{enums.OpcodeStep}},
+		{body: `[{"op":"Step","id":"a"},{"op":"Sleep","id":"b"}]`, ops: []enums.Opcode{enums.OpcodeStep, enums.OpcodeSleep}},
+		{body: `[]`, ops: []enums.Opcode{enums.OpcodeNone}},
+		{body: `null`, ops: []enums.Opcode{enums.OpcodeNone}},
+		{body: `[{"op":"Step"}] []`, err: true},
+		{body: `[{"op":"Step"}`, err: true},
+		{body: ``, err: true},
+	}
+	for _, test := range tests {
+		raw, size, ops, err := DecodeGenerator(ctx, bytes.NewReader([]byte(test.body)), false)
+		if test.err {
+			require.Error(t, err, test.body)
+			continue
+		}
+		require.NoError(t, err, test.body)
+		require.Equal(t, test.body, string(raw))
+		require.Equal(t, len(test.body), size)
+
+		// Decoding matches parsing the buffered response.
+		parsed, err := ParseGenerator(ctx, raw, false)
+		require.NoError(t, err)
+		require.Equal(t, parsed, ops)
+		for n, op := range ops {
+			require.Equal(t, test.ops[n], op.Op)
+		}
+	}
+
+	t.Run("only a prefix of large responses is retained", func(t *testing.T) {
+		data := strings.Repeat("a", 4*rawPrefixSize)
+		body := `[{"op":"Step","id":"a","data":"` + data + `"}]`
+		raw, size, ops, err := DecodeGenerator(ctx, strings.NewReader(body), false)
+		require.NoError(t, err)
+		require.Equal(t, body[:rawPrefixSize], string(raw))
+		require.Equal(t, len(body), size)
+		require.Len(t, ops, 1)
+		require.Equal(t, enums.OpcodeStep, ops[0].Op)
+
+		// Errors report the retained prefix.
+		_, _, _, err = DecodeGenerator(ctx, strings.NewReader(body+" []"), false)
+		require.ErrorContains(t, err, `[{\"op\":\"Step\"`)
+	})
+}
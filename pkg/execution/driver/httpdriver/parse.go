@@ -1,9 +1,12 @@
 package httpdriver
 
 import (
+	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"strconv"
 	"time"
 
@@ -19,19 +22,118 @@ import (
 // an OpcodeStepError was raised with a NonRetriableError.
 func ParseGenerator(ctx context.Context, byt []byte, noRetry bool) ([]*state.GeneratorOpcode, error) {
 	generators, err := parseGenerator(ctx, byt, noRetry)
+	if nerr := setNoRetry(generators, noRetry); nerr != nil {
+		return generators, nerr
+	}
+	return generators, err
+}
+
+// rawPrefixSize is the size of the raw response retained by DecodeGenerator for
+// error reporting.
+const rawPrefixSize = 4 * 1024
+
+// DecodeGenerator decodes generator responses as they're read from r, as with
+// ParseGenerator.  Opcodes are decoded one at a time, and only the first
+// rawPrefixSize bytes of the raw response are returned alongside the response's
+// size and opcodes, so that the response is never buffered in its entirety.
+func DecodeGenerator(ctx context.Context, r io.Reader, noRetry bool) ([]byte, int, []*state.GeneratorOpcode, error) {
+	raw := &prefixWriter{max: rawPrefixSize}
+	br := bufio.NewReader(io.TeeReader(r, raw))
+
+	var generators []*state.GeneratorOpcode
+	first, err := br.Peek(1)
+	switch {
+	case errors.Is(err, io.EOF):
+		return nil, 0, nil, ErrEmptyResponse
+	case err != nil:
+		return nil, 0, nil, fmt.Errorf("error reading generator opcode response: %w", err)
+	case first[0] == '{' || first[0] == '[':
+		if generators, err = decodeGenerator(json.NewDecoder(br), first[0]); err != nil {
+			return nil, 0, nil, fmt.Errorf("error reading generator opcode response %q: %w", raw.buf, err)
+		}
+	default:
+		generators = []*state.GeneratorOpcode{{Op: enums.OpcodeNone}}
+	}
+
+	// Read the remainder of the response so that the response's size is known.
+	if _, err := io.Copy(io.Discard, br); err != nil {
+		return nil, 0, nil, fmt.Errorf("error reading generator opcode response: %w", err)
+	}
+	if err := setNoRetry(generators, noRetry); err != nil {
+		return raw.buf, raw.n, generators, err
+	}
+	return raw.buf, raw.n, generators, nil
+}
+
+// prefixWriter retains the first max bytes written, counting every byte.
+type prefixWriter struct {
+	buf []byte
+	max int
+	n   int
+}
+
+func (p *prefixWriter) Write(b []byte) (int, error) {
+	p.n += len(b)
+	if room := p.max - len(p.buf); room > 0 {
+		p.buf = append(p.buf, b[:min(room, len(b))]...)
+	}
+	return len(b), nil
+}
+
+func decodeGenerator(dec *json.Decoder, first byte) ([]*state.GeneratorOpcode, error) {
+	// 0.x.x SDKs return a single opcode.
+	if first == '{' {
+		gen := &state.GeneratorOpcode{}
+		if err := dec.Decode(gen); err != nil {
+			return nil, err
+		}
+		return []*state.GeneratorOpcode{gen}, decodeEnd(dec)
+	}
+
+	// 1.x.x+ SDKs return an array of opcodes, which are decoded one at a time.
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	gen := []*state.GeneratorOpcode{}
+	for dec.More() {
+		op := &state.GeneratorOpcode{}
+		if err := dec.Decode(op); err != nil {
+			return nil, err
+		}
+		gen = append(gen, op)
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	if len(gen) == 0 {
+		gen = []*state.GeneratorOpcode{{Op: enums.OpcodeNone}}
+	}
+	return gen, decodeEnd(dec)
+}
+
+// decodeEnd ensures that nothing follows the decoded opcodes.
+func decodeEnd(dec *json.Decoder) error {
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return fmt.Errorf("invalid data after opcodes")
+	}
+	return nil
+}
+
+// setNoRetry sets whether step errors are retried on each OpcodeStepError.
+func setNoRetry(generators []*state.GeneratorOpcode, noRetry bool) error {
 	for n, item := range generators {
 		// Ensure that we set no retries on the opcode error directly.
 		// This is needed for the executor to check how to handle the error.
 		if item.Op == enums.OpcodeStepError {
 			if item.Error == nil {
-				return generators, fmt.Errorf("OpcodeStepError received without Error field set: %+v", item)
+				return fmt.Errorf("OpcodeStepError received without Error field set: %+v", item)
 			}
 
 			item.Error.NoRetry = noRetry
 			generators[n] = item
 		}
 	}
-	return generators, err
+	return nil
 }
 
 func parseGenerator(ctx context.Context, byt []byte, noRetry bool) ([]*state.GeneratorOpcode, error) {
@@ -136,6 +238,21 @@ func parseRetry(retry string) (time.Time, error) {
 	return dateutil.ParseString(retry)
 }
 
+// parseLogs returns the log lines included in a non-generator response, eg.
+// alongside the error thrown from function code.
+func parseLogs(byt []byte) []state.LogLine {
+	if len(byt) == 0 || byt[0] != '{' {
+		return nil
+	}
+	body := struct {
+		Logs []state.LogLine `json:"logs"`
+	}{}
+	if err := json.Unmarshal(byt, &body); err != nil {
+		return nil
+	}
+	return body.Logs
+}
+
 func parseResponse(byt []byte) any {
 	if len(byt) == 0 {
 		return nil
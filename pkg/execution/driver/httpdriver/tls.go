@@ -0,0 +1,58 @@
+package httpdriver
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+)
+
+// newClient returns a new HTTP client which uses the given TLS config and proxy
+// for requests.  Either may be nil.
+func newClient(c *TLSConfig, proxy func(*http.Request) (*url.URL, error)) (*http.Client, error) {
+	transport := DefaultTransport.Clone()
+	transport.Proxy = proxy
+	if c != nil {
+		cfg, err := c.tlsConfig()
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig = cfg
+	}
+	return &http.Client{
+		Timeout:       DefaultClient.Timeout,
+		CheckRedirect: checkRedirect,
+		Transport:     transport,
+	}, nil
+}
+
+func (c TLSConfig) tlsConfig() (*tls.Config, error) {
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if c.CertFile != "" || c.KeyFile != "" {
+		if c.CertFile == "" || c.KeyFile == "" {
+			return nil, fmt.Errorf("both a certificate and key file are required")
+		}
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("error loading client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	if c.CAFile != "" {
+		byt, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading ca file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(byt) {
+			return nil, fmt.Errorf("no certificates found in ca file %s", c.CAFile)
+		}
+		cfg.RootCAs = pool
+	}
+
+	return cfg, nil
+}
@@ -6,7 +6,10 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
@@ -17,6 +20,17 @@ const (
 	headerSDK            = "x-inngest-sdk"
 	headerRequestVersion = "x-inngest-req-version"
 	headerNoRetry        = "x-inngest-no-retry"
+	headerSignature      = "x-inngest-signature"
+
+	// DefaultSignatureTolerance is the maximum age of a signature before it's
+	// rejected, preventing replay attacks, unless configured otherwise.
+	DefaultSignatureTolerance = 5 * time.Minute
+)
+
+var (
+	ErrInvalidSignature = fmt.Errorf("invalid signature")
+	ErrExpiredSignature = fmt.Errorf("signature expired")
+	ErrMissingSignature = fmt.Errorf("missing signature")
 )
 
 // Sign signs the body with a private key, ensuring that HTTP handlers can verify
@@ -27,15 +41,123 @@ func Sign(ctx context.Context, key, body []byte) string {
 	}
 
 	now := time.Now().Unix()
+	return fmt.Sprintf("t=%d&s=%s", now, sign(key, body, now))
+}
+
+// ValidateSignature validates a signature generated by Sign or SignV2 for the
+// given body, using the default tolerance.  The signature is valid if it was
+// created using any of the given keys, allowing signing keys to be rotated:
+// pass the current key followed by the previous key.
+//
+// V2 signatures which cover headers must be validated using a SignatureVerifier.
+func ValidateSignature(ctx context.Context, sig string, body []byte, keys ...[]byte) error {
+	return SignatureVerifier{Keys: keys}.Verify(ctx, sig, nil, body)
+}
+
+// SignatureVerifier verifies v1 and v2 signatures.
+type SignatureVerifier struct {
+	// Keys are the keys accepted when verifying signatures, starting with the
+	// current key.
+	Keys [][]byte
+	// Tolerance is the maximum age of a signature, defaulting to
+	// DefaultSignatureTolerance.
+	Tolerance time.Duration
+}
+
+// Verify validates the signature for the given headers and body.  Headers are
+// only used to validate v2 signatures.
+func (v SignatureVerifier) Verify(ctx context.Context, sig string, h http.Header, body []byte) error {
+	values, err := url.ParseQuery(sig)
+	if err != nil || values.Get("t") == "" || values.Get("s") == "" {
+		return ErrInvalidSignature
+	}
+
+	ts, err := strconv.ParseInt(values.Get("t"), 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	tolerance := v.Tolerance
+	if tolerance <= 0 {
+		tolerance = DefaultSignatureTolerance
+	}
+	if age := time.Since(time.Unix(ts, 0)); age > tolerance || age < -tolerance {
+		return ErrExpiredSignature
+	}
+
+	actual, err := hex.DecodeString(values.Get("s"))
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	switch values.Get("v") {
+	case "", "1":
+		for _, key := range v.Keys {
+			if len(key) == 0 {
+				continue
+			}
+			expected, _ := hex.DecodeString(sign(key, body, ts))
+			if hmac.Equal(expected, actual) {
+				return nil
+			}
+		}
+	case "2":
+		names := signedHeaderNames(values.Get("h"))
+		for _, key := range v.Keys {
+			if len(key) == 0 || KeyID(key) != values.Get("k") {
+				continue
+			}
+			expected, _ := hex.DecodeString(signV2(key, names, h, body, ts))
+			if hmac.Equal(expected, actual) {
+				return nil
+			}
+		}
+	}
+	return ErrInvalidSignature
+}
+
+// SignStream signs the body written by the given func, as with Sign, without
+// buffering the body.
+func SignStream(ctx context.Context, key []byte, body BodyWriter) (string, error) {
+	if key == nil {
+		return "", nil
+	}
+
+	now := time.Now().Unix()
+	s, err := signStream(key, body, now)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("t=%d&s=%s", now, s), nil
+}
+
+// BodyWriter writes a request body to w.  Bodies may be written more than once,
+// and must be identical each time.
+type BodyWriter func(w io.Writer) error
+
+// bytesBody returns a BodyWriter which writes the given bytes.
+func bytesBody(body []byte) BodyWriter {
+	return func(w io.Writer) error {
+		_, err := w.Write(body)
+		return err
+	}
+}
+
+func sign(key, body []byte, ts int64) string {
+	s, _ := signStream(key, bytesBody(body), ts)
+	return s
+}
+
+func signStream(key []byte, body BodyWriter, ts int64) (string, error) {
 	mac := hmac.New(sha256.New, key)
 
-	_, _ = mac.Write(body)
+	if err := body(mac); err != nil {
+		return "", err
+	}
 	// Write the timestamp as a unix timestamp to the hmac to prevent
 	// timing attacks.
-	_, _ = mac.Write([]byte(fmt.Sprintf("%d", now)))
+	_, _ = mac.Write([]byte(fmt.Sprintf("%d", ts)))
 
-	sig := hex.EncodeToString(mac.Sum(nil))
-	return fmt.Sprintf("t=%d&s=%s", now, sig)
+	return hex.EncodeToString(mac.Sum(nil)), nil
 }
 
 func checkRedirect(req *http.Request, via []*http.Request) (err error) {
@@ -0,0 +1,78 @@
+package driver
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/inngest/inngest/pkg/execution/queue"
+	"github.com/inngest/inngest/pkg/execution/state"
+	"github.com/inngest/inngest/pkg/inngest"
+)
+
+// ExecuteFunc is the signature of Driver.Execute.
+type ExecuteFunc func(
+	ctx context.Context,
+	s state.State,
+	item queue.Item,
+	edge inngest.Edge,
+	step inngest.Step,
+	stackIndex int,
+	attempt int,
+) (*state.DriverResponse, error)
+
+// Middleware wraps driver execution, allowing callers to customize requests and
+// responses without modifying drivers.  Middleware may call next zero or more
+// times, inspect or mutate the response, or return its own error.
+type Middleware func(next ExecuteFunc) ExecuteFunc
+
+// Chain wraps the given driver with the given middleware.  Middleware runs in the
+// order given:  the first middleware is the outermost, and is invoked first.
+func Chain(d Driver, mw ...Middleware) Driver {
+	if len(mw) == 0 {
+		return d
+	}
+	exec := d.Execute
+	for i := len(mw) - 1; i >= 0; i-- {
+		exec = mw[i](exec)
+	}
+	return chained{Driver: d, exec: exec}
+}
+
+type chained struct {
+	Driver
+	exec ExecuteFunc
+}
+
+func (c chained) Execute(
+	ctx context.Context,
+	s state.State,
+	item queue.Item,
+	edge inngest.Edge,
+	step inngest.Step,
+	stackIndex int,
+	attempt int,
+) (*state.DriverResponse, error) {
+	return c.exec(ctx, s, item, edge, step, stackIndex, attempt)
+}
+
+type headersCtxKey struct{}
+
+// WithHeaders returns a context which adds the given headers to outgoing driver
+// requests, for drivers which support headers.  Headers are merged with any
+// headers already stored in the context.
+func WithHeaders(ctx context.Context, h http.Header) context.Context {
+	merged := Headers(ctx).Clone()
+	if merged == nil {
+		merged = http.Header{}
+	}
+	for k, v := range h {
+		merged[http.CanonicalHeaderKey(k)] = append([]string{}, v...)
+	}
+	return context.WithValue(ctx, headersCtxKey{}, merged)
+}
+
+// Headers returns the headers stored in the context via WithHeaders.
+func Headers(ctx context.Context) http.Header {
+	h, _ := ctx.Value(headersCtxKey{}).(http.Header)
+	return h
+}
@@ -0,0 +1,58 @@
+package driver
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/inngest/inngest/pkg/execution/queue"
+	"github.com/inngest/inngest/pkg/execution/state"
+	"github.com/inngest/inngest/pkg/inngest"
+	"github.com/stretchr/testify/require"
+)
+
+type testDriver struct {
+	headers http.Header
+}
+
+func (testDriver) RuntimeType() string { return "test" }
+
+func (d *testDriver) Execute(ctx context.Context, s state.State, item queue.Item, edge inngest.Edge, step inngest.Step, idx, attempt int) (*state.DriverResponse, error) {
+	d.headers = Headers(ctx)
+	return &state.DriverResponse{Output: "ok"}, nil
+}
+
+func TestChain(t *testing.T) {
+	var order []string
+	named := func(name string) Middleware {
+		return func(next ExecuteFunc) ExecuteFunc {
+			return func(ctx context.Context, s state.State, item queue.Item, edge inngest.Edge, step inngest.Step, idx, attempt int) (*state.DriverResponse, error) {
+				order = append(order, name)
+				ctx = WithHeaders(ctx, http.Header{"x-" + name: []string{name}})
+				resp, err := next(ctx, s, item, edge, step, idx, attempt)
+				order = append(order, name)
+				return resp, err
+			}
+		}
+	}
+	mutate := func(next ExecuteFunc) ExecuteFunc {
+		return func(ctx context.Context, s state.State, item queue.Item, edge inngest.Edge, step inngest.Step, idx, attempt int) (*state.DriverResponse, error) {
+			resp, err := next(ctx, s, item, edge, step, idx, attempt)
+			resp.Output = "mutated"
+			return resp, err
+		}
+	}
+
+	d := &testDriver{}
+	require.Same(t, Driver(d), Chain(d))
+
+	wrapped := Chain(d, named("a"), named("b"), mutate)
+	require.Equal(t, "test", wrapped.RuntimeType())
+
+	resp, err := wrapped.Execute(context.Background(), nil, queue.Item{}, inngest.Edge{}, inngest.Step{}, 0, 0)
+	require.NoError(t, err)
+	require.Equal(t, "mutated", resp.Output)
+	require.Equal(t, []string{"a", "b", "b", "a"}, order)
+	require.Equal(t, "a", d.headers.Get("X-A"))
+	require.Equal(t, "b", d.headers.Get("X-B"))
+}
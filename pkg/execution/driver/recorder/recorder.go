@@ -0,0 +1,172 @@
+// Package recorder records the requests and responses of driver executions, and
+// replays runs against recorded responses without calling the SDK.  This allows
+// execution bugs to be reproduced deterministically.
+package recorder
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/inngest/inngest/pkg/execution/driver"
+	"github.com/inngest/inngest/pkg/execution/queue"
+	"github.com/inngest/inngest/pkg/execution/state"
+	"github.com/inngest/inngest/pkg/inngest"
+	"github.com/karlseguin/ccache/v2"
+	"github.com/oklog/ulid/v2"
+)
+
+// ReplayContextKey is the run context key storing the ID of the recorded run that
+// a run replays.  Runs scheduled with this key are executed against the recorded
+// run's responses.
+const ReplayContextKey = "inngest_replay_of"
+
+// ErrNoRecording is returned when replaying a step which has no recorded response.
+var ErrNoRecording = fmt.Errorf("no recorded response for step")
+
+// Exchange is a single recorded driver request and its response.
+type Exchange struct {
+	// Key identifies the step request, matching requests in replayed runs to
+	// their recorded responses.
+	Key string `json:"key"`
+	// URL is the URL of the step requested.
+	URL string `json:"url"`
+	// Request is the marshalled request payload for the step.
+	Request json.RawMessage `json:"request,omitempty"`
+	// Response is the driver's response.
+	Response *state.DriverResponse `json:"response,omitempty"`
+	// Error is the driver's error, if the driver errored.
+	Error string `json:"error,omitempty"`
+	// At is the time that the request was made.
+	At time.Time `json:"at"`
+}
+
+// Store stores the exchanges recorded for each run.
+type Store interface {
+	// Record appends the exchange to the run's recording.
+	Record(ctx context.Context, runID ulid.ULID, e Exchange) error
+	// Exchanges returns the run's recorded exchanges, in order.
+	Exchanges(ctx context.Context, runID ulid.ULID) ([]Exchange, error)
+}
+
+// NewMemoryStore returns a Store which keeps the recordings of the most recent
+// runs in memory, holding up to maxRuns recordings.
+func NewMemoryStore(maxRuns int64) Store {
+	return &memoryStore{
+		runs: ccache.New(ccache.Configure().MaxSize(maxRuns).ItemsToPrune(uint32(maxRuns)/4 + 1)),
+	}
+}
+
+type memoryStore struct {
+	lock sync.Mutex
+	runs *ccache.Cache
+}
+
+func (m *memoryStore) Record(ctx context.Context, runID ulid.ULID, e Exchange) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	existing := []Exchange{}
+	if item := m.runs.Get(runID.String()); item != nil {
+		existing = item.Value().([]Exchange)
+	}
+	// ccache items don't expire as the cache is an LRU, so the TTL is unused.
+	m.runs.Set(runID.String(), append(existing, e), time.Hour)
+	return nil
+}
+
+func (m *memoryStore) Exchanges(ctx context.Context, runID ulid.ULID) ([]Exchange, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	item := m.runs.Get(runID.String())
+	if item == nil {
+		return nil, nil
+	}
+	return append([]Exchange{}, item.Value().([]Exchange)...), nil
+}
+
+// New returns a driver which records each of d's requests and responses within
+// the store, and replays runs scheduled with ReplayContextKey against their
+// recordings.
+func New(d driver.Driver, store Store) driver.Driver {
+	return &recorder{Driver: d, store: store}
+}
+
+type recorder struct {
+	driver.Driver
+	store Store
+}
+
+// requestKey identifies a step request within a run.  Step IDs are deterministic
+// so requests made when replaying a run have the same keys as the recording.
+func requestKey(edge inngest.Edge, attempt int) string {
+	return fmt.Sprintf("%s:%s:%s:%d", edge.Outgoing, edge.Incoming, edge.IncomingGeneratorStep, attempt)
+}
+
+func (r *recorder) Execute(ctx context.Context, s state.State, item queue.Item, edge inngest.Edge, step inngest.Step, idx, attempt int) (*state.DriverResponse, error) {
+	key := requestKey(edge, attempt)
+
+	if source, ok := s.Metadata().Context[ReplayContextKey].(string); ok {
+		return r.replay(ctx, source, key)
+	}
+
+	req, err := driver.MarshalV1(ctx, s, step, idx, "", attempt)
+	if err != nil {
+		return nil, err
+	}
+
+	at := time.Now()
+	resp, err := r.Driver.Execute(ctx, s, item, edge, step, idx, attempt)
+
+	e := Exchange{
+		Key:     key,
+		URL:     step.URI,
+		Request: req,
+		At:      at,
+	}
+	if resp != nil {
+		// Copy the response, as the executor modifies responses after they're
+		// returned.
+		recorded := *resp
+		e.Response = &recorded
+	}
+	if err != nil {
+		e.Error = err.Error()
+	}
+	_ = r.store.Record(ctx, s.RunID(), e)
+	return resp, err
+}
+
+// replay returns the recorded response for the given request.  If the request was
+// made more than once in the recorded run, eg. as a lease expired, the most recent
+// response is used.
+func (r *recorder) replay(ctx context.Context, source, key string) (*state.DriverResponse, error) {
+	sourceID, err := ulid.Parse(source)
+	if err != nil {
+		return nil, fmt.Errorf("invalid replayed run ID: %w", err)
+	}
+	exchanges, err := r.store.Exchanges(ctx, sourceID)
+	if err != nil {
+		return nil, err
+	}
+
+	var e *Exchange
+	for n := range exchanges {
+		if exchanges[n].Key == key {
+			e = &exchanges[n]
+		}
+	}
+	if e == nil || (e.Response == nil && e.Error == "") {
+		resp := &state.DriverResponse{NoRetry: true}
+		resp.SetError(fmt.Errorf("%w: %s", ErrNoRecording, key))
+		return resp, nil
+	}
+	if e.Error != "" {
+		return nil, fmt.Errorf("%s", e.Error)
+	}
+	// Copy the response so that the executor's changes to it don't affect the
+	// recording.
+	resp := *e.Response
+	return &resp, nil
+}
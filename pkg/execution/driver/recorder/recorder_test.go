@@ -0,0 +1,100 @@
+package recorder
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/inngest/inngest/pkg/execution/driver"
+	"github.com/inngest/inngest/pkg/execution/driver/mockdriver"
+	"github.com/inngest/inngest/pkg/execution/queue"
+	"github.com/inngest/inngest/pkg/execution/state"
+	"github.com/inngest/inngest/pkg/inngest"
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	ctx := context.Background()
+	fn := inngest.Function{ID: uuid.New()}
+	step := inngest.Step{ID: "step", URI: "http://localhost:3000/api/inngest"}
+	edge := inngest.Edge{Outgoing: inngest.TriggerName, Incoming: "step"}
+	events := []map[string]any{{"name": "test/event", "data": map[string]any{"n": 1}}}
+
+	mock := &mockdriver.Mock{
+		Responses: map[string]state.DriverResponse{
+			"step": {Step: step, Output: "ok", StatusCode: 200},
+		},
+	}
+	store := NewMemoryStore(10)
+	d := New(mock, store)
+
+	// Record a run.
+	id := state.Identifier{WorkflowID: fn.ID, RunID: ulid.Make()}
+	s := state.NewStateInstance(fn, id, state.Metadata{}, events, map[string]any{}, map[string]error{}, []string{})
+	resp, err := d.Execute(ctx, s, queue.Item{}, edge, step, 0, 0)
+	require.NoError(t, err)
+	require.Equal(t, "ok", resp.Output)
+	require.Equal(t, 1, mock.ExecutedLen())
+
+	exchanges, err := store.Exchanges(ctx, id.RunID)
+	require.NoError(t, err)
+	require.Len(t, exchanges, 1)
+	require.Equal(t, step.URI, exchanges[0].URL)
+	require.Equal(t, "ok", exchanges[0].Response.Output)
+	req := driver.SDKRequest{}
+	require.NoError(t, json.Unmarshal(exchanges[0].Request, &req))
+	require.Len(t, req.Events, 1)
+
+	// Modifying the returned response doesn't modify the recording.
+	resp.Output = "modified"
+	exchanges, _ = store.Exchanges(ctx, id.RunID)
+	require.Equal(t, "ok", exchanges[0].Response.Output)
+
+	// Replay the run without calling the SDK.
+	mock.Executed = nil
+	replayID := state.Identifier{WorkflowID: fn.ID, RunID: ulid.Make()}
+	md := state.Metadata{Context: map[string]any{ReplayContextKey: id.RunID.String()}}
+	replay := state.NewStateInstance(fn, replayID, md, events, map[string]any{}, map[string]error{}, []string{})
+	resp, err = d.Execute(ctx, replay, queue.Item{}, edge, step, 0, 0)
+	require.NoError(t, err)
+	require.Equal(t, "ok", resp.Output)
+	require.Equal(t, 0, mock.ExecutedLen())
+
+	// Replays aren't recorded.
+	exchanges, err = store.Exchanges(ctx, replayID.RunID)
+	require.NoError(t, err)
+	require.Empty(t, exchanges)
+
+	// Requests which weren't recorded fail without retrying.
+	resp, err = d.Execute(ctx, replay, queue.Item{}, edge, step, 0, 1)
+	require.NoError(t, err)
+	require.True(t, resp.NoRetry)
+	require.NotNil(t, resp.Err)
+	require.Contains(t, *resp.Err, ErrNoRecording.Error())
+	require.Equal(t, 0, mock.ExecutedLen())
+}
+
+func TestRecordDriverError(t *testing.T) {
+	ctx := context.Background()
+	fn := inngest.Function{ID: uuid.New()}
+	step := inngest.Step{ID: "step"}
+	edge := inngest.Edge{Outgoing: inngest.TriggerName, Incoming: "step"}
+	events := []map[string]any{{"name": "test/event"}}
+
+	mock := &mockdriver.Mock{Errors: map[string]error{"step": errors.New("connection refused")}}
+	store := NewMemoryStore(10)
+	d := New(mock, store)
+
+	id := state.Identifier{WorkflowID: fn.ID, RunID: ulid.Make()}
+	s := state.NewStateInstance(fn, id, state.Metadata{}, events, map[string]any{}, map[string]error{}, []string{})
+	_, err := d.Execute(ctx, s, queue.Item{}, edge, step, 0, 0)
+	require.Error(t, err)
+
+	md := state.Metadata{Context: map[string]any{ReplayContextKey: id.RunID.String()}}
+	replay := state.NewStateInstance(fn, state.Identifier{WorkflowID: fn.ID, RunID: ulid.Make()}, md, events, map[string]any{}, map[string]error{}, []string{})
+	_, err = d.Execute(ctx, replay, queue.Item{}, edge, step, 0, 0)
+	require.EqualError(t, err, "connection refused")
+}
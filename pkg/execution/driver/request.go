@@ -67,6 +67,17 @@ type SDKRequestContext struct {
 	// size limits.
 	UseAPI bool `json:"use_api"`
 
+	// LazyEvents indicates that `Events` only contains the first event of
+	// the run's batch.  The SDK must retrieve the full batch from the API,
+	// via GET /v1/runs/{runID}/batch, when the function uses it.  This is
+	// only set for functions whose SDK declares the lazy_batch_events
+	// capability.
+	LazyEvents bool `json:"lazy_events,omitempty"`
+
+	// EventIDs lists the internal ID of every event in the run's batch when
+	// events are loaded lazily.
+	EventIDs []string `json:"event_ids,omitempty"`
+
 	// XXX: Pass in opentracing context within ctx.
 }
 
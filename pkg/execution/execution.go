@@ -55,6 +55,12 @@ type Executor interface {
 	// given time. Filtering of events in any way must be handled prior scheduling.
 	Schedule(ctx context.Context, r ScheduleRequest) (*state.Identifier, error)
 
+	// ScheduleMany schedules many functions at once, eg. every function
+	// triggered by a single event, batching state and queue operations where
+	// supported.  The returned identifiers and errors correspond to each
+	// request.
+	ScheduleMany(ctx context.Context, r []ScheduleRequest) ([]*state.Identifier, []error)
+
 	// Execute runs the given function via the execution drivers.  If the
 	// from ID is "$trigger" this is treated as a new workflow invocation from the
 	// trigger, and all functions that are direct children of the trigger will be
@@ -118,6 +124,11 @@ type Executor interface {
 	// always add to a list of listeners vs replace listeners.
 	AddLifecycleListener(l LifecycleListener)
 
+	// CloseLifecycleListeners waits for in-progress lifecycle listener calls to
+	// finish, or for the context to be done.  This is used to flush lifecycles,
+	// eg. history writes, when shutting down.
+	CloseLifecycleListeners(ctx context.Context) error
+
 	// SetFinishHandler sets the finish handler, called when a function run finishes.
 	SetFinishHandler(f FinishHandler)
 
@@ -196,6 +207,8 @@ type ResumeRequest struct {
 	// functions directly.
 	RunID    *ulid.ULID
 	StepName string
+	// Timeout is true when the pause is resumed because it expired.
+	Timeout bool
 }
 
 func (r *ResumeRequest) Error() string {
@@ -0,0 +1,21 @@
+package executor
+
+import (
+	"context"
+
+	"github.com/inngest/inngest/pkg/execution/queue"
+)
+
+// BreakpointAll is the breakpoint step ID which halts before every step, allowing
+// runs to be stepped through manually.
+const BreakpointAll = "*"
+
+// Breakpoints halts runs before executing designated steps, allowing runs to be
+// debugged interactively.
+type Breakpoints interface {
+	// Halt is called before executing the step with the given ID.  If this
+	// returns true the step is not executed and the item is dequeued;  the
+	// implementation must hold a copy of the item, eg. in the queue, and run it
+	// to continue the run.
+	Halt(ctx context.Context, item queue.Item, stepID string) bool
+}
@@ -0,0 +1,131 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/inngest/inngest/pkg/consts"
+	"github.com/inngest/inngest/pkg/enums"
+	"github.com/inngest/inngest/pkg/execution/driver"
+	"github.com/inngest/inngest/pkg/execution/queue"
+	"github.com/inngest/inngest/pkg/execution/redact"
+	"github.com/inngest/inngest/pkg/execution/state"
+	"github.com/inngest/inngest/pkg/inngest"
+	"github.com/inngest/inngest/pkg/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// checkpointer returns a func which saves step outputs streamed by the SDK
+// during the item's request, before the request finishes.  This allows a single
+// request to run several steps without a round trip per step, while ensuring
+// that finished steps are memoized if the request later fails.
+//
+// Each checkpointed step is handled as if the SDK had returned it in its own
+// response, minus enqueueing the next step:  the run's step limit applies, and
+// the step is traced and passed to lifecycle listeners before being saved.
+func (e *executor) checkpointer(item queue.Item, edge inngest.Edge, step inngest.Step, s state.State, f *inngest.Function) driver.CheckpointFunc {
+	var (
+		l     sync.Mutex
+		steps = len(s.Actions())
+		limit = e.stepLimit(item.Identifier, f)
+	)
+	return func(ctx context.Context, ops []*state.GeneratorOpcode) error {
+		l.Lock()
+		defer l.Unlock()
+
+		for _, op := range ops {
+			if steps >= limit {
+				// Stop the request;  the retry fails the run as it's validated.
+				return fmt.Errorf("%w: checkpointing step %s", state.ErrFunctionOverflowed, op.ID)
+			}
+			saved, err := e.checkpointStep(ctx, *op, item, edge, step)
+			if err != nil {
+				return err
+			}
+			if saved {
+				steps++
+			}
+		}
+		return nil
+	}
+}
+
+// checkpointStep handles a single checkpointed step, returning whether the
+// step was saved.  A retried request may checkpoint a step which was saved by
+// a previous attempt, in which case the step is ignored.
+func (e *executor) checkpointStep(ctx context.Context, gen state.GeneratorOpcode, item queue.Item, edge inngest.Edge, step inngest.Step) (bool, error) {
+	output, err := gen.Output()
+	if err != nil {
+		return false, err
+	}
+
+	err = e.saveGeneratorStep(ctx, gen, item)
+	if errors.Is(err, state.ErrDuplicateResponse) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	resp := state.DriverResponse{
+		Step:       step,
+		Generator:  []*state.GeneratorOpcode{&gen},
+		Output:     output,
+		OutputSize: len(output),
+		StatusCode: 206,
+	}
+
+	_, span := telemetry.NewSpan(ctx,
+		telemetry.WithScope(consts.OtelScopeExecution),
+		telemetry.WithName(gen.UserDefinedName()),
+		telemetry.WithSpanAttributes(
+			attribute.Bool(consts.OtelUserTraceFilterKey, true),
+			attribute.String(consts.OtelSysAccountID, item.Identifier.AccountID.String()),
+			attribute.String(consts.OtelSysWorkspaceID, item.Identifier.WorkspaceID.String()),
+			attribute.String(consts.OtelSysAppID, item.Identifier.AppID.String()),
+			attribute.String(consts.OtelSysFunctionID, item.Identifier.WorkflowID.String()),
+			attribute.String(consts.OtelAttrSDKRunID, item.Identifier.RunID.String()),
+			attribute.Int(consts.OtelSysStepAttempt, item.Attempt),
+			attribute.String(consts.OtelSysStepGroupID, item.GroupID),
+			attribute.Int(consts.OtelSysStepStatusCode, resp.StatusCode),
+			attribute.Int(consts.OtelSysStepOutputSizeBytes, resp.OutputSize),
+			attribute.String(consts.OtelSysStepDisplayName, gen.UserDefinedName()),
+			attribute.String(consts.OtelSysStepOpcode, enums.OpcodeStepRun.String()),
+		),
+	)
+	if byt, err := json.Marshal(output); err == nil {
+		span.AddEvent(string(e.redacted(ctx, redact.KindOutput, byt)), trace.WithAttributes(
+			attribute.Bool(consts.OtelSysStepOutput, true),
+		))
+	}
+	span.End()
+
+	for _, l := range e.listeners() {
+		go l.OnStepFinished(context.WithoutCancel(ctx), item.Identifier, item, edge, step, resp)
+	}
+	return true, nil
+}
+
+type checkpointedCtxKey struct{}
+
+// withCheckpointed stores the IDs of steps checkpointed during a request in the
+// context, so that handling the request's response doesn't save them again.
+func withCheckpointed(ctx context.Context, ids []string) context.Context {
+	set := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		set[id] = struct{}{}
+	}
+	return context.WithValue(ctx, checkpointedCtxKey{}, set)
+}
+
+// isCheckpointed returns whether the step was checkpointed during the request
+// whose response is being handled.
+func isCheckpointed(ctx context.Context, stepID string) bool {
+	set, _ := ctx.Value(checkpointedCtxKey{}).(map[string]struct{})
+	_, ok := set[stepID]
+	return ok
+}
@@ -0,0 +1,75 @@
+package executor
+
+import (
+	"context"
+	"crypto/rand"
+	"sync"
+	"testing"
+
+	"github.com/inngest/inngest/pkg/enums"
+	"github.com/inngest/inngest/pkg/execution"
+	"github.com/inngest/inngest/pkg/execution/queue"
+	"github.com/inngest/inngest/pkg/execution/state"
+	"github.com/inngest/inngest/pkg/inngest"
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/require"
+)
+
+// responseManager is a state.Manager which records saved step outputs.
+type responseManager struct {
+	state.Manager
+	saved map[string]string
+}
+
+func (m *responseManager) SaveResponse(ctx context.Context, id state.Identifier, stepID, output string) error {
+	if _, ok := m.saved[stepID]; ok {
+		return state.ErrDuplicateResponse
+	}
+	m.saved[stepID] = output
+	return nil
+}
+
+// finishedListener records the steps passed to OnStepFinished.
+type finishedListener struct {
+	execution.NoopLifecyceListener
+	l     sync.Mutex
+	steps []string
+}
+
+func (f *finishedListener) OnStepFinished(ctx context.Context, id state.Identifier, item queue.Item, edge inngest.Edge, step inngest.Step, resp state.DriverResponse) {
+	f.l.Lock()
+	defer f.l.Unlock()
+	for _, op := range resp.Generator {
+		f.steps = append(f.steps, op.ID)
+	}
+}
+
+func TestCheckpointer(t *testing.T) {
+	ctx := context.Background()
+	id := state.Identifier{RunID: ulid.MustNew(ulid.Now(), rand.Reader)}
+	item := queue.Item{Identifier: id}
+	f := &inngest.Function{StepLimits: &inngest.StepLimits{Steps: 3}}
+	s := state.NewStateInstance(*f, id, state.Metadata{}, []map[string]any{{"name": "test/event"}}, map[string]any{"a": "done"}, map[string]error{}, []string{"a"})
+
+	sm := &responseManager{saved: map[string]string{"a": `{"data":"done"}`}}
+	l := &finishedListener{}
+	e := &executor{sm: sm, lifecycles: []execution.LifecycleListener{l}, lifecycleWG: &sync.WaitGroup{}}
+	checkpoint := e.checkpointer(item, inngest.Edge{Incoming: "step"}, inngest.Step{ID: "step"}, s, f)
+
+	op := func(id string) *state.GeneratorOpcode {
+		return &state.GeneratorOpcode{Op: enums.OpcodeStepRun, ID: id, Data: []byte(`{"data":"ok"}`)}
+	}
+
+	// Checkpointed steps are saved and passed to lifecycles.  Steps saved by a
+	// previous attempt are ignored.
+	require.NoError(t, checkpoint(ctx, []*state.GeneratorOpcode{op("a"), op("b")}))
+	require.Contains(t, sm.saved, "b")
+	e.lifecycleWG.Wait()
+	require.Equal(t, []string{"b"}, l.steps)
+
+	// Checkpoints are stopped once the run reaches its step limit.
+	err := checkpoint(ctx, []*state.GeneratorOpcode{op("c"), op("d")})
+	require.ErrorIs(t, err, state.ErrFunctionOverflowed)
+	require.Contains(t, sm.saved, "c")
+	require.NotContains(t, sm.saved, "d")
+}
@@ -0,0 +1,102 @@
+package executor
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/inngest/inngest/pkg/execution/queue"
+)
+
+var enqueueBatchCtxKey = enqueueBatchCtxType{}
+
+type enqueueBatchCtxType struct{}
+
+// enqueueBatch batches the enqueues of generators which are handled in parallel,
+// so that planning many parallel steps enqueues each step within a single round
+// trip.  Each generator's enqueue blocks until every generator within the batch
+// has either enqueued or finished.
+type enqueueBatch struct {
+	ctx      context.Context
+	producer queue.BulkProducer
+
+	mu sync.Mutex
+	// pending is the number of generators which haven't yet enqueued or finished.
+	pending int
+	items   []queue.ItemAt
+	results []chan error
+}
+
+func newEnqueueBatch(ctx context.Context, producer queue.BulkProducer, size int) *enqueueBatch {
+	return &enqueueBatch{
+		ctx:      ctx,
+		producer: producer,
+		pending:  size,
+	}
+}
+
+// member returns a context which enqueues a single generator's item via the batch,
+// and a func which must be called once the generator has been handled.
+func (b *enqueueBatch) member(ctx context.Context) (context.Context, func()) {
+	m := &enqueueBatchMember{batch: b}
+	return context.WithValue(ctx, enqueueBatchCtxKey, m), m.done
+}
+
+// flush enqueues every item within the batch once all generators have enqueued
+// or finished.  This must be called with the lock held.
+func (b *enqueueBatch) flush() {
+	b.pending--
+	if b.pending > 0 || len(b.items) == 0 {
+		return
+	}
+	for n, err := range b.producer.EnqueueMany(b.ctx, b.items) {
+		b.results[n] <- err
+	}
+}
+
+// enqueueBatchMember records whether a single generator has joined its batch.
+type enqueueBatchMember struct {
+	batch  *enqueueBatch
+	joined bool
+}
+
+// add adds the item to the batch, returning a channel which receives the item's
+// enqueue error.  This returns nil if the generator has already joined the batch,
+// in which case the item must be enqueued directly.
+func (m *enqueueBatchMember) add(item queue.Item, at time.Time) chan error {
+	m.batch.mu.Lock()
+	defer m.batch.mu.Unlock()
+	if m.joined {
+		return nil
+	}
+	m.joined = true
+
+	ch := make(chan error, 1)
+	m.batch.items = append(m.batch.items, queue.ItemAt{Item: item, At: at})
+	m.batch.results = append(m.batch.results, ch)
+	m.batch.flush()
+	return ch
+}
+
+// done marks the generator as handled, allowing the batch to be enqueued if the
+// generator never enqueued an item.
+func (m *enqueueBatchMember) done() {
+	m.batch.mu.Lock()
+	defer m.batch.mu.Unlock()
+	if m.joined {
+		return
+	}
+	m.joined = true
+	m.batch.flush()
+}
+
+// enqueue enqueues the item, via the context's enqueue batch if the item is
+// enqueued when handling a group of parallel generators.
+func (e *executor) enqueue(ctx context.Context, item queue.Item, at time.Time) error {
+	if m, ok := ctx.Value(enqueueBatchCtxKey).(*enqueueBatchMember); ok {
+		if ch := m.add(item, at); ch != nil {
+			return <-ch
+		}
+	}
+	return e.queue.Enqueue(ctx, item, at)
+}
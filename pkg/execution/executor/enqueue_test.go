@@ -0,0 +1,126 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/inngest/inngest/pkg/enums"
+	"github.com/inngest/inngest/pkg/execution/gateway"
+	"github.com/inngest/inngest/pkg/execution/queue"
+	"github.com/inngest/inngest/pkg/execution/state"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/errgroup"
+)
+
+type batchQueue struct {
+	queue.Queue
+
+	mu       sync.Mutex
+	enqueued []string
+	batches  [][]string
+}
+
+func (b *batchQueue) Enqueue(ctx context.Context, item queue.Item, at time.Time) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.enqueued = append(b.enqueued, item.GroupID)
+	return nil
+}
+
+func (b *batchQueue) EnqueueMany(ctx context.Context, items []queue.ItemAt) []error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	batch := []string{}
+	errs := make([]error, len(items))
+	for n, item := range items {
+		batch = append(batch, item.Item.GroupID)
+		if item.Item.GroupID == "fail" {
+			errs[n] = fmt.Errorf("failed")
+		}
+	}
+	b.batches = append(b.batches, batch)
+	return errs
+}
+
+func TestEnqueueBatch(t *testing.T) {
+	ctx := context.Background()
+	q := &batchQueue{}
+	e := &executor{queue: q}
+
+	// Items aren't batched outside of generator groups.
+	require.NoError(t, e.enqueue(ctx, queue.Item{GroupID: "single"}, time.Now()))
+	require.Equal(t, []string{"single"}, q.enqueued)
+	q.enqueued = nil
+
+	batch := newEnqueueBatch(ctx, q, 4)
+	eg := errgroup.Group{}
+	for _, id := range []string{"a", "b", "fail"} {
+		id := id
+		opCtx, done := batch.member(ctx)
+		eg.Go(func() error {
+			defer done()
+			err := e.enqueue(opCtx, queue.Item{GroupID: id}, time.Now())
+			if id == "fail" {
+				require.Error(t, err)
+				return nil
+			}
+			require.NoError(t, err)
+			// Further items from the same generator are enqueued directly.
+			return e.enqueue(opCtx, queue.Item{GroupID: id + "-next"}, time.Now())
+		})
+	}
+	// A generator which never enqueues must not block the batch.
+	_, done := batch.member(ctx)
+	eg.Go(func() error {
+		done()
+		return nil
+	})
+	require.NoError(t, eg.Wait())
+
+	require.Len(t, q.batches, 1)
+	require.ElementsMatch(t, []string{"a", "b", "fail"}, q.batches[0])
+	require.ElementsMatch(t, []string{"a-next", "b-next"}, q.enqueued)
+}
+
+type slowGateway struct {
+	release chan struct{}
+}
+
+func (s slowGateway) Fetch(ctx context.Context, req gateway.Request) (*gateway.Response, error) {
+	<-s.release
+	return nil, fmt.Errorf("timeout")
+}
+
+func TestEnqueueBatchSlowGenerator(t *testing.T) {
+	ctx := context.Background()
+	q := &batchQueue{}
+	g := slowGateway{release: make(chan struct{})}
+	e := &executor{queue: q, gateway: g, lifecycleWG: &sync.WaitGroup{}}
+
+	group := OpcodeGroup{Opcodes: []*state.GeneratorOpcode{
+		{ID: "a", Op: enums.OpcodeStepPlanned},
+		{ID: "b", Op: enums.OpcodeStepPlanned},
+		{ID: "fetch", Op: enums.OpcodeGateway, Opts: map[string]any{"url": "https://example.com"}},
+	}}
+	item := queue.Item{Payload: queue.PayloadEdge{}}
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- e.handleGeneratorGroup(ctx, group, &state.DriverResponse{}, item)
+	}()
+
+	// Planned steps are enqueued without waiting for the slow generator.
+	require.Eventually(t, func() bool {
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		return len(q.batches) == 1
+	}, time.Second, 10*time.Millisecond)
+	require.Len(t, q.batches[0], 2)
+
+	close(g.release)
+	require.ErrorIs(t, <-errs, ErrHandledStepError)
+	require.Empty(t, q.enqueued)
+}
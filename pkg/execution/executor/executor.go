@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"runtime/debug"
 	"strconv"
 	"strings"
 	"sync"
@@ -16,14 +17,18 @@ import (
 	"github.com/fatih/structs"
 	"github.com/google/uuid"
 	"github.com/inngest/inngest/pkg/consts"
+	"github.com/inngest/inngest/pkg/cqrs"
 	"github.com/inngest/inngest/pkg/enums"
 	"github.com/inngest/inngest/pkg/event"
 	"github.com/inngest/inngest/pkg/execution"
+	"github.com/inngest/inngest/pkg/execution/aigateway"
 	"github.com/inngest/inngest/pkg/execution/batch"
 	"github.com/inngest/inngest/pkg/execution/cancellation"
 	"github.com/inngest/inngest/pkg/execution/debounce"
 	"github.com/inngest/inngest/pkg/execution/driver"
+	"github.com/inngest/inngest/pkg/execution/gateway"
 	"github.com/inngest/inngest/pkg/execution/queue"
+	"github.com/inngest/inngest/pkg/execution/redact"
 	"github.com/inngest/inngest/pkg/execution/state"
 	"github.com/inngest/inngest/pkg/execution/state/redis_state"
 	"github.com/inngest/inngest/pkg/expressions"
@@ -33,7 +38,6 @@ import (
 	"github.com/inngest/inngest/pkg/telemetry"
 	"github.com/oklog/ulid/v2"
 	"github.com/rs/zerolog"
-	"github.com/xhit/go-str2duration/v2"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/propagation"
@@ -56,6 +60,15 @@ var (
 	// step should be safely retried.
 	ErrHandledStepError = fmt.Errorf("handled step error")
 
+	// ErrDriverPanic is returned when a driver or driver middleware panics.  The
+	// step is retried as per any other error.
+	ErrDriverPanic = fmt.Errorf("driver panicked")
+
+	// ErrBatchLimitExceeded is returned when an event can't be batched as the
+	// function already holds its maximum number of pending batches, and the
+	// executor rejects such events.
+	ErrBatchLimitExceeded = fmt.Errorf("function has too many pending batches")
+
 	PauseHandleConcurrency = 100
 )
 
@@ -75,6 +88,11 @@ var (
 func NewExecutor(opts ...ExecutorOpt) (execution.Executor, error) {
 	m := &executor{
 		runtimeDrivers: map[string]driver.Driver{},
+		lifecycleWG:    &sync.WaitGroup{},
+		pauseExprBudget: expressions.Budget{
+			Timeout:   consts.DefaultPauseExpressionTimeout,
+			CostLimit: consts.DefaultPauseExpressionCostLimit,
+		},
 	}
 
 	for _, o := range opts {
@@ -176,6 +194,55 @@ func WithStepLimits(limit func(id state.Identifier) int) ExecutorOpt {
 	}
 }
 
+// WithFailureHandlerLoader sets the loader used to find functions' onFailure
+// handlers, given the failed run's identifier and the handler's slug.
+func WithFailureHandlerLoader(l func(ctx context.Context, id state.Identifier, slug string) (*cqrs.Function, error)) ExecutorOpt {
+	return func(e execution.Executor) error {
+		e.(*executor).failureHandlerLoader = l
+		return nil
+	}
+}
+
+// WithParallelStepLimits sets the maximum number of steps a run may plan at once.
+// Functions may override this limit via their StepLimits config.
+func WithParallelStepLimits(limit func(id state.Identifier) int) ExecutorOpt {
+	return func(e execution.Executor) error {
+		e.(*executor).parallelsteplimit = limit
+		return nil
+	}
+}
+
+// WithIdempotencyPeriod sets the idempotency period for runs in the given
+// workspace, used when a function doesn't specify its own period.  Returning 0
+// uses consts.FunctionIdempotencyPeriod.
+func WithIdempotencyPeriod(period func(ctx context.Context, workspaceID uuid.UUID) time.Duration) ExecutorOpt {
+	return func(e execution.Executor) error {
+		e.(*executor).idempotencyPeriod = period
+		return nil
+	}
+}
+
+// WithCancelTimeout sets how long cancellations last for runs in the given
+// workspace, used when neither the cancellation nor its function specify a
+// timeout.  Returning 0 uses consts.CancelTimeout, and returning
+// consts.CancelTimeoutNever creates cancellations which never expire.
+func WithCancelTimeout(timeout func(ctx context.Context, workspaceID uuid.UUID) time.Duration) ExecutorOpt {
+	return func(e execution.Executor) error {
+		e.(*executor).cancelTimeout = timeout
+		return nil
+	}
+}
+
+// WithTraceSampleRatio sets the ratio of runs whose traces are exported for the
+// given workspace, used when a function doesn't specify its own ratio.  Returning
+// nil exports every run's trace.
+func WithTraceSampleRatio(ratio func(ctx context.Context, workspaceID uuid.UUID) *float64) ExecutorOpt {
+	return func(e execution.Executor) error {
+		e.(*executor).traceSampleRatio = ratio
+		return nil
+	}
+}
+
 func WithDebouncer(d debounce.Debouncer) ExecutorOpt {
 	return func(e execution.Executor) error {
 		e.(*executor).debouncer = d
@@ -190,6 +257,37 @@ func WithBatcher(b batch.BatchManager) ExecutorOpt {
 	}
 }
 
+// WithRejectBatchOverflow rejects events which can't be batched as the function
+// already holds its maximum number of pending batches, returning
+// ErrBatchLimitExceeded.  By default, such events start runs immediately in
+// batches of their own.
+func WithRejectBatchOverflow(reject bool) ExecutorOpt {
+	return func(e execution.Executor) error {
+		e.(*executor).rejectBatchOverflow = reject
+		return nil
+	}
+}
+
+// WithConcurrencyKeyCardinalityLimit sets the number of distinct custom
+// concurrency keys that each of a function's limits may use within an hour
+// before the function is logged and counted as generating unbounded keys.
+// Zero disables the limit, though cardinality is still recorded.
+func WithConcurrencyKeyCardinalityLimit(n int64) ExecutorOpt {
+	return func(e execution.Executor) error {
+		e.(*executor).concurrencyKeyCardinality = n
+		return nil
+	}
+}
+
+// WithBreakpoints halts runs before executing steps designated by the given
+// breakpoints, eg. when debugging runs in the dev server.
+func WithBreakpoints(b Breakpoints) ExecutorOpt {
+	return func(e execution.Executor) error {
+		e.(*executor).breakpoints = b
+		return nil
+	}
+}
+
 // WithEvaluatorFactory allows customizing of the expression evaluator factory function.
 func WithEvaluatorFactory(f func(ctx context.Context, expr string) (expressions.Evaluator, error)) ExecutorOpt {
 	return func(e execution.Executor) error {
@@ -198,6 +296,25 @@ func WithEvaluatorFactory(f func(ctx context.Context, expr string) (expressions.
 	}
 }
 
+// WithFunctionReader sets the reader used to resolve functions invoked by their
+// app and function slugs, eg. "my-app/my-fn".
+func WithFunctionReader(r cqrs.FunctionReader) ExecutorOpt {
+	return func(e execution.Executor) error {
+		e.(*executor).fnReader = r
+		return nil
+	}
+}
+
+// WithPauseExpressionBudget bounds the time and cost of evaluating each pause's
+// expression when handling events.  Pauses whose expressions exceed the budget
+// are skipped.
+func WithPauseExpressionBudget(b expressions.Budget) ExecutorOpt {
+	return func(e execution.Executor) error {
+		e.(*executor).pauseExprBudget = b
+		return nil
+	}
+}
+
 // WithRuntimeDrivers specifies the drivers available to use when executing steps
 // of a function.
 //
@@ -217,6 +334,44 @@ func WithRuntimeDrivers(drivers ...driver.Driver) ExecutorOpt {
 	}
 }
 
+// WithDriverMiddleware registers middleware which wraps every driver's Execute
+// call, eg. to log requests, inject auth headers via driver.WithHeaders, mutate
+// responses, or record metrics.  Middleware runs in the order registered, with
+// the first middleware being the outermost.
+func WithDriverMiddleware(mw ...driver.Middleware) ExecutorOpt {
+	return func(e execution.Executor) error {
+		e.(*executor).driverMiddleware = append(e.(*executor).driverMiddleware, mw...)
+		return nil
+	}
+}
+
+// WithRedactHook sets a hook which scrubs sensitive data from event payloads and
+// step outputs before they're saved to the state store or recorded in traces.
+func WithRedactHook(h redact.Hook) ExecutorOpt {
+	return func(e execution.Executor) error {
+		e.(*executor).redact = h
+		return nil
+	}
+}
+
+// WithAIGateway sets the gateway used to make inference requests for
+// OpcodeAIGateway.  Inference requests fail if this isn't set.
+func WithAIGateway(g aigateway.Gateway) ExecutorOpt {
+	return func(e execution.Executor) error {
+		e.(*executor).aiGateway = g
+		return nil
+	}
+}
+
+// WithGateway sets the gateway used to make HTTP requests for OpcodeGateway.
+// Gateway requests fail if this isn't set.
+func WithGateway(g gateway.Gateway) ExecutorOpt {
+	return func(e execution.Executor) error {
+		e.(*executor).gateway = g
+		return nil
+	}
+}
+
 // executor represents a built-in executor for running workflows.
 type executor struct {
 	log *zerolog.Logger
@@ -229,17 +384,56 @@ type executor struct {
 	queue                 queue.Queue
 	debouncer             debounce.Debouncer
 	batcher               batch.BatchManager
+	rejectBatchOverflow   bool
 	fl                    state.FunctionLoader
+	fnReader              cqrs.FunctionReader
 	evalFactory           func(ctx context.Context, expr string) (expressions.Evaluator, error)
 	runtimeDrivers        map[string]driver.Driver
+	driverMiddleware      []driver.Middleware
+	redact                redact.Hook
+	aiGateway             aigateway.Gateway
+	gateway               gateway.Gateway
 	finishHandler         execution.FinishHandler
 	invokeNotFoundHandler execution.InvokeNotFoundHandler
 	handleSendingEvent    execution.HandleSendingEvent
 	cancellationChecker   cancellation.Checker
+	failureHandlerLoader  func(ctx context.Context, id state.Identifier, slug string) (*cqrs.Function, error)
 
 	lifecycles []execution.LifecycleListener
+	// lifecycleWG tracks in-progress lifecycle listener calls.
+	lifecycleWG *sync.WaitGroup
+
+	steplimit         func(id state.Identifier) int
+	parallelsteplimit func(id state.Identifier) int
+	// idempotencyPeriod returns the default idempotency period for a workspace.
+	idempotencyPeriod func(ctx context.Context, workspaceID uuid.UUID) time.Duration
+	// cancelTimeout returns the default cancellation timeout for a workspace.
+	cancelTimeout func(ctx context.Context, workspaceID uuid.UUID) time.Duration
+	// traceSampleRatio returns the default trace sample ratio for a workspace.
+	traceSampleRatio func(ctx context.Context, workspaceID uuid.UUID) *float64
+	// pauseExprBudget bounds the evaluation of each pause's expression.
+	pauseExprBudget expressions.Budget
+	// concurrencyKeyCardinality is the number of distinct custom concurrency
+	// keys which each limit may use before the function is flagged.
+	concurrencyKeyCardinality int64
+	// breakpoints, if set, halts runs before executing designated steps.
+	breakpoints Breakpoints
+}
+
+// redacted returns data with sensitive fields scrubbed by the redact hook, if set.
+func (e *executor) redacted(ctx context.Context, kind redact.Kind, data []byte) []byte {
+	if e.redact == nil {
+		return data
+	}
+	return e.redact(ctx, kind, data)
+}
 
-	steplimit func(id state.Identifier) int
+// redactedString is redacted for string data.
+func (e *executor) redactedString(ctx context.Context, kind redact.Kind, data string) string {
+	if e.redact == nil {
+		return data
+	}
+	return string(e.redact(ctx, kind, []byte(data)))
 }
 
 func (e *executor) SetFinishHandler(f execution.FinishHandler) {
@@ -264,12 +458,145 @@ func (e *executor) AddLifecycleListener(l execution.LifecycleListener) {
 	e.lifecycles = append(e.lifecycles, l)
 }
 
-// Execute loads a workflow and the current run state, then executes the
-// function's step via the necessary driver.
+func (e *executor) CloseLifecycleListeners(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		e.lifecycleWG.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return fmt.Errorf("error waiting for lifecycle listeners: %w", ctx.Err())
+	}
+
+	// Flush any pending writes now that all calls have finished.
+	var err error
+	for _, l := range e.lifecycles {
+		if cerr := l.Close(); cerr != nil {
+			err = errors.Join(err, fmt.Errorf("error closing lifecycle listener: %w", cerr))
+		}
+	}
+	return err
+}
+
+// Schedule creates a new function run by initializing blank function state and
+// placing the run in the queue.
 //
 // If this function has a debounce config, this will return ErrFunctionDebounced instead
 // of an identifier as the function is not scheduled immediately.
 func (e *executor) Schedule(ctx context.Context, req execution.ScheduleRequest) (*state.Identifier, error) {
+	run, err := e.prepareRun(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer run.span.End()
+
+	s, err := e.sm.New(run.ctx, run.input)
+	if err := e.runCreated(run, err); err != nil {
+		return nil, err
+	}
+	if err := e.saveCancellations(run.ctx, run.req, run.id, run.eventIDs, run.mapped); err != nil {
+		return &run.id, err
+	}
+	err = e.queue.Enqueue(run.ctx, run.item, run.at)
+	if err := e.runEnqueued(run, s, err); err != nil {
+		return nil, err
+	}
+	return &run.id, nil
+}
+
+// ScheduleMany schedules many functions at once, eg. each function triggered by
+// an event.  If the state store and queue support bulk operations, each run's
+// state is created and each start job is enqueued in a single pipelined round
+// trip, rather than scheduling each function separately.
+//
+// The returned identifiers and errors correspond to each request.
+func (e *executor) ScheduleMany(ctx context.Context, reqs []execution.ScheduleRequest) ([]*state.Identifier, []error) {
+	ids := make([]*state.Identifier, len(reqs))
+	errs := make([]error, len(reqs))
+
+	creator, ok := e.sm.(state.BulkCreator)
+	producer, pok := e.queue.(queue.BulkProducer)
+	if !ok || !pok || len(reqs) == 1 {
+		for n, req := range reqs {
+			ids[n], errs[n] = e.Schedule(ctx, req)
+		}
+		return ids, errs
+	}
+
+	// Prepare each run, skipping debounced or skipped functions.
+	runs := []*pendingRun{}
+	idx := []int{}
+	for n, req := range reqs {
+		run, err := e.prepareRun(ctx, req)
+		if err != nil {
+			errs[n] = err
+			continue
+		}
+		defer run.span.End()
+		runs = append(runs, run)
+		idx = append(idx, n)
+	}
+	if len(runs) == 0 {
+		return ids, errs
+	}
+
+	inputs := make([]state.Input, len(runs))
+	for n, run := range runs {
+		inputs[n] = run.input
+	}
+	states, newErrs := creator.NewMany(ctx, inputs)
+
+	// Enqueue start jobs for each run whose state was created.
+	created := []int{}
+	items := []queue.ItemAt{}
+	for n, run := range runs {
+		if err := e.runCreated(run, newErrs[n]); err != nil {
+			errs[idx[n]] = err
+			continue
+		}
+		if err := e.saveCancellations(run.ctx, run.req, run.id, run.eventIDs, run.mapped); err != nil {
+			ids[idx[n]], errs[idx[n]] = &run.id, err
+			continue
+		}
+		created = append(created, n)
+		items = append(items, queue.ItemAt{Item: run.item, At: run.at})
+	}
+	if len(items) == 0 {
+		return ids, errs
+	}
+
+	enqueueErrs := producer.EnqueueMany(ctx, items)
+	for i, n := range created {
+		run := runs[n]
+		if err := e.runEnqueued(run, states[n], enqueueErrs[i]); err != nil {
+			errs[idx[n]] = err
+			continue
+		}
+		ids[idx[n]] = &run.id
+	}
+	return ids, errs
+}
+
+// pendingRun is a run prepared for scheduling, whose state hasn't been created
+// and whose start job hasn't been enqueued.
+type pendingRun struct {
+	// ctx is the context used when creating the run, carrying the run's trace
+	// sampling.
+	ctx      context.Context
+	req      execution.ScheduleRequest
+	id       state.Identifier
+	eventIDs []ulid.ULID
+	mapped   []map[string]any
+	span     *telemetry.Span
+	input    state.Input
+	item     queue.Item
+	at       time.Time
+}
+
+// prepareRun creates the identifier, state input and start job for a new run.
+func (e *executor) prepareRun(ctx context.Context, req execution.ScheduleRequest) (*pendingRun, error) {
 	if req.Function.Debounce != nil && !req.PreventDebounce {
 		err := e.debouncer.Debounce(ctx, debounce.DebounceItem{
 			AccountID:       req.AccountID,
@@ -296,6 +623,20 @@ func (e *executor) Schedule(ctx context.Context, req execution.ScheduleRequest)
 		// Use the given idempotency key
 		key = *req.IdempotencyKey
 	}
+	if req.Function.Idempotency != nil && len(req.Events) == 1 {
+		// Functions may specify an idempotency expression, which takes precedence
+		// over the event ID.
+		fnKey, err := req.Function.IdempotencyKey(ctx, req.Events[0].GetEvent().Map())
+		if err != nil {
+			logger.StdlibLogger(ctx).Warn(
+				"error evaluating idempotency key",
+				"error", err,
+				"function_id", req.Function.ID,
+			)
+		} else if fnKey != "" {
+			key = fnKey
+		}
+	}
 	if req.OriginalRunID != nil {
 		// If this is a rerun then we want to use the run ID as the key. If we
 		// used the event or batch ID as the key then we wouldn't be able to
@@ -332,11 +673,26 @@ func (e *executor) Schedule(ctx context.Context, req execution.ScheduleRequest)
 		ReplayID:        req.ReplayID,
 	}
 
-	isPaused := req.FunctionPausedAt != nil && req.FunctionPausedAt.Before(time.Now())
-	if isPaused {
-		for _, e := range e.lifecycles {
+	// Functions may retain idempotency keys for longer or shorter than the
+	// default, allowing users to choose their deduplication window.
+	id.IdempotencyPeriod = req.Function.IdempotencyPeriodDuration()
+	if id.IdempotencyPeriod == 0 && e.idempotencyPeriod != nil {
+		id.IdempotencyPeriod = e.idempotencyPeriod(ctx, req.WorkspaceID)
+	}
+
+	// Decide the run's sampling up front so that every span of the run, across
+	// each step, is consistently exported or dropped.
+	id.TraceSampleRatio = req.Function.TraceSampleRatio
+	if id.TraceSampleRatio == nil && e.traceSampleRatio != nil {
+		id.TraceSampleRatio = e.traceSampleRatio(ctx, req.WorkspaceID)
+	}
+	ctx = telemetry.WithSampleRatio(ctx, id.TraceSampleRatio)
+
+	if reason := skipReason(req); reason != enums.SkipReasonNone {
+		for _, e := range e.listeners() {
 			go e.OnFunctionSkipped(context.WithoutCancel(ctx), id, execution.SkipState{
 				CronSchedule: req.Events[0].GetEvent().CronSchedule(),
+				Reason:       reason,
 			})
 		}
 		return nil, ErrFunctionSkipped
@@ -347,6 +703,8 @@ func (e *executor) Schedule(ctx context.Context, req execution.ScheduleRequest)
 		telemetry.WithScope(consts.OtelScopeTrigger),
 		telemetry.WithName(consts.OtelSpanTrigger),
 		telemetry.WithTimestamp(ulid.Time(runID.Time())),
+		// Link to the traces that the triggering events were sent within.
+		telemetry.WithLinks(telemetry.EventLinks(req.Events...)...),
 		telemetry.WithSpanAttributes(
 			attribute.Bool(consts.OtelUserTraceFilterKey, true),
 			attribute.String(consts.OtelSysAccountID, req.AccountID.String()),
@@ -359,8 +717,6 @@ func (e *executor) Schedule(ctx context.Context, req execution.ScheduleRequest)
 			attribute.Int64(consts.OtelSysFunctionStatusCode, enums.RunStatusScheduled.ToCode()),
 		),
 	)
-	defer span.End()
-
 	if req.BatchID != nil {
 		span.SetAttributes(attribute.String(consts.OtelSysBatchID, req.BatchID.String()))
 	}
@@ -384,7 +740,7 @@ func (e *executor) Schedule(ctx context.Context, req execution.ScheduleRequest)
 
 		// serialize this data to the span at the same time
 		if byt, err := json.Marshal(evt); err == nil {
-			span.AddEvent(string(byt), trace.WithAttributes(
+			span.AddEvent(string(e.redacted(ctx, redact.KindEvent, byt)), trace.WithAttributes(
 				attribute.Bool(consts.OtelSysEventData, true),
 			))
 		}
@@ -413,11 +769,18 @@ func (e *executor) Schedule(ctx context.Context, req execution.ScheduleRequest)
 			// The raw keys are stored in the function state so that we don't need to re-evaluate
 			// keys and input each time, as they're constant through the function run.
 			id.CustomConcurrencyKeys = append(id.CustomConcurrencyKeys, state.CustomConcurrency{
-				Key:   limit.Evaluate(ctx, scopeID, mapped[0]),
-				Hash:  limit.Hash,
-				Limit: limit.Limit,
+				Key:     limit.Evaluate(ctx, scopeID, mapped[0]),
+				Hash:    limit.Hash,
+				Limit:   limit.Limit,
+				Ordered: limit.Ordered,
 			})
 		}
+
+		// Count the distinct keys used by the function's custom limits without
+		// delaying the run.
+		if len(id.CustomConcurrencyKeys) > 0 {
+			go e.recordConcurrencyKeyCardinality(context.WithoutCancel(ctx), req.Function, id.CustomConcurrencyKeys)
+		}
 	}
 
 	// Evaluate the run priority based off of the input event data.
@@ -437,85 +800,6 @@ func (e *executor) Schedule(ctx context.Context, req execution.ScheduleRequest)
 
 	spanID := telemetry.NewSpanID(ctx)
 
-	// Create a new function.
-	s, err := e.sm.New(ctx, state.Input{
-		Identifier:     id,
-		EventBatchData: mapped,
-		Context:        stateMetadata,
-		SpanID:         spanID.String(),
-	})
-	if err == state.ErrIdentifierExists {
-		_ = span.Cancel(ctx)
-		// This function was already created.
-		return nil, state.ErrIdentifierExists
-	}
-
-	if err != nil {
-		span.SetStatus(codes.Error, err.Error())
-		return nil, fmt.Errorf("error creating run state: %w", err)
-	}
-
-	// Create cancellation pauses immediately, only if this is a non-batch event.
-	if req.BatchID == nil {
-		for _, c := range req.Function.Cancel {
-			pauseID := uuid.New()
-			expires := time.Now().Add(consts.CancelTimeout)
-			if c.Timeout != nil {
-				dur, err := str2duration.ParseDuration(*c.Timeout)
-				if err != nil {
-					return &id, fmt.Errorf("error parsing cancel duration: %w", err)
-				}
-				expires = time.Now().Add(dur)
-			}
-
-			// Evaluate the expression.  This lets us inspect the expression's attributes
-			// so that we can store only the attrs used in the expression in the pause,
-			// saving space, bandwidth, etc.
-			expr := generateCancelExpression(eventIDs[0], c.If)
-			eval, err := expressions.NewExpressionEvaluator(ctx, expr)
-			if err != nil {
-				return &id, err
-			}
-			ed := expressions.NewData(map[string]any{"event": req.Events[0].GetEvent().Map()})
-			data := eval.FilteredAttributes(ctx, ed).Map()
-
-			// The triggering event ID should be the first ID in the batch.
-			triggeringID := req.Events[0].GetInternalID().String()
-
-			// Remove `event` data from the expression and replace with actual event
-			// data as values, now that we have the event.
-			//
-			// This improves performance in matching, as we can then use the values within
-			// aggregate trees.
-			interpolated, err := expressions.Interpolate(ctx, expr, map[string]any{
-				"event": mapped[0],
-			})
-			if err != nil {
-				logger.StdlibLogger(ctx).Warn(
-					"error interpolating cancellation expression",
-					"error", err,
-					"expression", expr,
-				)
-			}
-
-			pause := state.Pause{
-				WorkspaceID:       req.WorkspaceID,
-				Identifier:        id,
-				ID:                pauseID,
-				Expires:           state.Time(expires),
-				Event:             &c.Event,
-				Expression:        &interpolated,
-				ExpressionData:    data,
-				Cancel:            true,
-				TriggeringEventID: &triggeringID,
-			}
-			err = e.sm.SavePause(ctx, pause)
-			if err != nil {
-				return &id, fmt.Errorf("error saving pause: %w", err)
-			}
-		}
-	}
-
 	at := time.Now()
 	if req.BatchID == nil {
 		evtTs := time.UnixMilli(req.Events[0].GetEvent().Timestamp)
@@ -529,22 +813,7 @@ func (e *executor) Schedule(ctx context.Context, req execution.ScheduleRequest)
 		at = *req.At
 	}
 
-	var throttle *queue.Throttle
-	if req.Function.Throttle != nil {
-		throttleKey := redis_state.HashID(ctx, req.Function.ID.String())
-		if req.Function.Throttle.Key != nil {
-			val, _, _ := expressions.Evaluate(ctx, *req.Function.Throttle.Key, map[string]any{
-				"event": mapped[0],
-			})
-			throttleKey = throttleKey + "-" + redis_state.HashID(ctx, fmt.Sprintf("%v", val))
-		}
-		throttle = &queue.Throttle{
-			Key:    throttleKey,
-			Limit:  int(req.Function.Throttle.Limit),
-			Burst:  int(req.Function.Throttle.Burst),
-			Period: int(req.Function.Throttle.Period.Seconds()),
-		}
-	}
+	throttle := scheduleThrottle(ctx, req, mapped[0])
 
 	// Prefix the workflow to the job ID so that no invocation can accidentally
 	// cause idempotency issues across users/functions.
@@ -562,23 +831,201 @@ func (e *executor) Schedule(ctx context.Context, req execution.ScheduleRequest)
 		Payload: queue.PayloadEdge{
 			Edge: inngest.SourceEdge,
 		},
-		Throttle: throttle,
+		Throttle:       throttle,
+		RequestTimeout: req.Function.RequestTimeoutDuration(),
+	}
+
+	return &pendingRun{
+		ctx:      ctx,
+		req:      req,
+		id:       id,
+		eventIDs: eventIDs,
+		mapped:   mapped,
+		span:     span,
+		input: state.Input{
+			Identifier:     id,
+			EventBatchData: mapped,
+			Context:        stateMetadata,
+			SpanID:         spanID.String(),
+		},
+		item: item,
+		at:   at,
+	}, nil
+}
+
+// runCreated handles the result of creating the run's state.
+func (e *executor) runCreated(run *pendingRun, err error) error {
+	if err == state.ErrIdentifierExists {
+		_ = run.span.Cancel(run.ctx)
+		// This function was already created.
+		return state.ErrIdentifierExists
+	}
+	if err != nil {
+		run.span.SetStatus(codes.Error, err.Error())
+		return fmt.Errorf("error creating run state: %w", err)
+	}
+	return nil
+}
+
+// saveCancellations saves a pause for each of the function's cancellation
+// triggers.
+func (e *executor) saveCancellations(ctx context.Context, req execution.ScheduleRequest, id state.Identifier, eventIDs []ulid.ULID, mapped []map[string]any) error {
+	// Create cancellation pauses immediately, only if this is a non-batch event.
+	if req.BatchID != nil {
+		return nil
+	}
+	for _, c := range req.Function.Cancel {
+		pauseID := uuid.New()
+		timeout, err := e.cancellationTimeout(ctx, req.WorkspaceID, req.Function, c)
+		if err != nil {
+			return err
+		}
+		var expires time.Time
+		if timeout != consts.CancelTimeoutNever {
+			expires = time.Now().Add(timeout)
+		}
+
+		// Evaluate the expression.  This lets us inspect the expression's attributes
+		// so that we can store only the attrs used in the expression in the pause,
+		// saving space, bandwidth, etc.
+		expr := generateCancelExpression(eventIDs[0], c.If)
+		eval, err := expressions.NewExpressionEvaluator(ctx, expr)
+		if err != nil {
+			return err
+		}
+		ed := expressions.NewData(map[string]any{"event": req.Events[0].GetEvent().Map()})
+		data := eval.FilteredAttributes(ctx, ed).Map()
+
+		// The triggering event ID should be the first ID in the batch.
+		triggeringID := req.Events[0].GetInternalID().String()
+
+		// Remove `event` data from the expression and replace with actual event
+		// data as values, now that we have the event.
+		//
+		// This improves performance in matching, as we can then use the values within
+		// aggregate trees.
+		interpolated, err := expressions.Interpolate(ctx, expr, map[string]any{
+			"event": mapped[0],
+		})
+		if err != nil {
+			logger.StdlibLogger(ctx).Warn(
+				"error interpolating cancellation expression",
+				"error", err,
+				"expression", expr,
+			)
+		}
+
+		pause := state.Pause{
+			WorkspaceID:       req.WorkspaceID,
+			Identifier:        id,
+			ID:                pauseID,
+			Expires:           state.Time(expires),
+			NeverExpires:      timeout == consts.CancelTimeoutNever,
+			Event:             &c.Event,
+			Expression:        &interpolated,
+			ExpressionData:    data,
+			Cancel:            true,
+			TriggeringEventID: &triggeringID,
+		}
+		err = e.sm.SavePause(ctx, pause)
+		if err != nil {
+			return fmt.Errorf("error saving pause: %w", err)
+		}
+	}
+	return nil
+}
+
+// cancellationTimeout returns how long the given cancellation lasts, using the
+// cancellation's own timeout, then its function's, then its workspace's.
+func (e *executor) cancellationTimeout(ctx context.Context, workspaceID uuid.UUID, fn inngest.Function, c inngest.Cancel) (time.Duration, error) {
+	if c.Timeout != nil {
+		dur, err := inngest.ParseCancelTimeout(*c.Timeout)
+		if err != nil {
+			return 0, fmt.Errorf("error parsing cancel duration: %w", err)
+		}
+		return dur, nil
+	}
+	if dur := fn.CancelTimeoutDuration(); dur != 0 {
+		return dur, nil
+	}
+	if e.cancelTimeout != nil {
+		if dur := e.cancelTimeout(ctx, workspaceID); dur != 0 {
+			return dur, nil
+		}
+	}
+	return consts.CancelTimeout, nil
+}
+
+// scheduleThrottle returns the throttle for the run's start job, or nil if the
+// function isn't throttled.
+func scheduleThrottle(ctx context.Context, req execution.ScheduleRequest, evt map[string]any) *queue.Throttle {
+	if req.Function.Throttle == nil {
+		return nil
+	}
+
+	// Ensure we bind the throttle to the correct scope.  Function scoped keys
+	// are unprefixed so that they match keys for runs enqueued before scopes.
+	throttleKey := redis_state.HashID(ctx, req.Function.ID.String())
+	switch req.Function.ThrottleScope {
+	case enums.ConcurrencyScopeAccount:
+		throttleKey = "a:" + redis_state.HashID(ctx, req.AccountID.String())
+	case enums.ConcurrencyScopeEnv:
+		throttleKey = "e:" + redis_state.HashID(ctx, req.WorkspaceID.String())
 	}
-	err = e.queue.Enqueue(ctx, item, at)
+	if req.Function.Throttle.Key != nil {
+		val, _, _ := expressions.Evaluate(ctx, *req.Function.Throttle.Key, map[string]any{
+			"event": evt,
+		})
+		throttleKey = throttleKey + "-" + redis_state.HashID(ctx, fmt.Sprintf("%v", val))
+	}
+	return &queue.Throttle{
+		Key:    throttleKey,
+		Limit:  int(req.Function.Throttle.Limit),
+		Burst:  int(req.Function.Throttle.Burst),
+		Period: int(req.Function.Throttle.Period.Seconds()),
+	}
+}
+
+// runEnqueued handles the result of enqueueing the run's start job, notifying
+// listeners once the run is scheduled.
+func (e *executor) runEnqueued(run *pendingRun, s state.State, err error) error {
 	if err == redis_state.ErrQueueItemExists {
-		_ = span.Cancel(ctx)
-		return nil, state.ErrIdentifierExists
+		_ = run.span.Cancel(run.ctx)
+		return state.ErrIdentifierExists
 	}
 	if err != nil {
-		span.SetStatus(codes.Error, err.Error())
-		return nil, fmt.Errorf("error enqueueing source edge '%v': %w", queueKey, err)
+		run.span.SetStatus(codes.Error, err.Error())
+		return fmt.Errorf("error enqueueing source edge '%v': %w", *run.item.JobID, err)
 	}
 
-	for _, e := range e.lifecycles {
-		go e.OnFunctionScheduled(context.WithoutCancel(ctx), id, item, s)
+	for _, l := range e.listeners() {
+		go l.OnFunctionScheduled(context.WithoutCancel(run.ctx), run.id, run.item, s)
 	}
+	return nil
+}
 
-	return &id, nil
+// load loads the run's state for executing a step.  Functions whose SDK fetches
+// batches from the API only load the first event of the run's batch.
+func (e *executor) load(ctx context.Context, runID ulid.ULID, f *inngest.Function) (state.State, error) {
+	if f.EventBatch != nil && f.EventBatch.Lazy {
+		if l, ok := e.sm.(state.LazyLoader); ok {
+			return l.LoadLazy(ctx, runID)
+		}
+	}
+	return e.sm.Load(ctx, runID)
+}
+
+// skipReason returns the reason that the given run should be skipped, or
+// SkipReasonNone if the run should be scheduled.  Runs are skipped when the
+// function is paused or archived.
+func skipReason(req execution.ScheduleRequest) enums.SkipReason {
+	if req.FunctionPausedAt != nil && req.FunctionPausedAt.Before(time.Now()) {
+		return enums.SkipReasonFunctionPaused
+	}
+	if req.Function.IsArchived() {
+		return enums.SkipReasonFunctionArchived
+	}
+	return enums.SkipReasonNone
 }
 
 // Execute loads a workflow and the current run state, then executes the
@@ -588,7 +1035,12 @@ func (e *executor) Execute(ctx context.Context, id state.Identifier, item queue.
 		return nil, fmt.Errorf("no function loader specified running step")
 	}
 
-	s, err := e.sm.Load(ctx, id.RunID)
+	f, err := e.fl.LoadFunction(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("error loading function for run: %w", err)
+	}
+
+	s, err := e.load(ctx, id.RunID, f)
 	if err != nil {
 		return nil, err
 	}
@@ -603,9 +1055,22 @@ func (e *executor) Execute(ctx context.Context, id state.Identifier, item queue.
 		start = md.StartedAt
 	}
 
-	f, err := e.fl.LoadFunction(ctx, id)
-	if err != nil {
-		return nil, fmt.Errorf("error loading function for run: %w", err)
+	// Custom concurrency keys are evaluated when the run is scheduled.  Re-resolve
+	// their limits from the latest function version so that any jobs enqueued from
+	// this step use the current limits, including any limits overridden via the
+	// queue.
+	var limits map[string]int
+	if updater, ok := e.queue.(queue.ConcurrencyLimitUpdater); ok {
+		if limits, err = updater.ConcurrencyLimitOverrides(ctx, id.WorkflowID); err != nil {
+			logger.From(ctx).Warn().Err(err).Msg("error loading concurrency limit overrides")
+		}
+	}
+	id.CustomConcurrencyKeys = state.LatestConcurrencyLimits(id.CustomConcurrencyKeys, *f, limits)
+	item.Identifier.CustomConcurrencyKeys = state.LatestConcurrencyLimits(item.Identifier.CustomConcurrencyKeys, *f, limits)
+
+	// Items enqueued when resuming pauses don't carry the function's request timeout.
+	if item.RequestTimeout == 0 {
+		item.RequestTimeout = f.RequestTimeoutDuration()
 	}
 
 	// Validate that the run can execute.
@@ -662,7 +1127,7 @@ func (e *executor) Execute(ctx context.Context, id state.Identifier, item queue.
 	}
 	for _, evt := range s.Events() {
 		if byt, err := json.Marshal(evt); err == nil {
-			fnSpan.AddEvent(string(byt), trace.WithAttributes(
+			fnSpan.AddEvent(string(e.redacted(ctx, redact.KindEvent, byt)), trace.WithAttributes(
 				attribute.Bool(consts.OtelSysEventData, true),
 			))
 		}
@@ -749,7 +1214,7 @@ func (e *executor) Execute(ctx context.Context, id state.Identifier, item queue.
 				log.From(ctx).Error().Err(err).Msg("error updating metadata on function start")
 			}
 
-			for _, e := range e.lifecycles {
+			for _, e := range e.listeners() {
 				go e.OnFunctionStarted(context.WithoutCancel(ctx), id, item, s)
 			}
 		}
@@ -769,12 +1234,27 @@ func (e *executor) Execute(ctx context.Context, id state.Identifier, item queue.
 		}, nil
 	}
 
+	if e.breakpoints != nil && e.breakpoints.Halt(ctx, item, incoming) {
+		// The step is held at a breakpoint until it's continued, which
+		// runs the copy of the item held by the breakpoints.
+		log.From(ctx).Debug().Str("run_id", id.RunID.String()).Str("step_id", incoming).Msg("halted at breakpoint")
+		return nil, nil
+	}
+
+	// Store the loaded state, including the start time set above, so that
+	// handling the response doesn't reload the entire state from the store.
+	md.StartedAt = start
+	ctx = withContextState(ctx, stateWithResponse(s, md, "", ""))
+
 	resp, err := e.run(ctx, id, item, edge, s, stackIndex, f)
+	if resp != nil {
+		recordStepMetrics(ctx, s.Function().GetSlug(), item, resp)
+	}
 
 	if resp == nil && err != nil {
 		span.SetStatus(codes.Error, err.Error())
 		if byt, err := json.Marshal(err.Error()); err == nil {
-			span.AddEvent(string(byt), trace.WithAttributes(
+			span.AddEvent(string(e.redacted(ctx, redact.KindOutput, byt)), trace.WithAttributes(
 				attribute.Bool(consts.OtelSysStepOutput, true),
 			))
 		}
@@ -782,6 +1262,17 @@ func (e *executor) Execute(ctx context.Context, id state.Identifier, item queue.
 		return nil, err
 	}
 
+	if limit := e.parallelStepLimit(id, f); resp != nil && len(resp.Generator) > limit {
+		// Fail fast rather than scheduling a fan-out of steps which the run can't
+		// handle.  The error is final, failing the run.
+		resp.SetError(fmt.Errorf("%w: %d steps were planned at once, but the limit is %d", state.ErrFunctionParallelism, len(resp.Generator), limit))
+		resp.SetFinal()
+		if err := e.runLimitedHandler(ctx, id, s, limitParallelSteps, limit, len(resp.Generator)); err != nil {
+			logger.From(ctx).Error().Err(err).Msg("error running limited handler")
+		}
+		resp.Generator = nil
+	}
+
 	if resp != nil {
 		if op := resp.TraceVisibleStepExecution(); op != nil {
 			spanName := op.UserDefinedName()
@@ -805,7 +1296,7 @@ func (e *executor) Execute(ctx context.Context, id state.Identifier, item queue.
 			)
 
 			if byt, err := json.Marshal(resp.Output); err == nil {
-				span.AddEvent(string(byt), trace.WithAttributes(
+				span.AddEvent(string(e.redacted(ctx, redact.KindOutput, byt)), trace.WithAttributes(
 					attribute.Bool(consts.OtelSysStepOutput, true),
 				))
 			}
@@ -823,6 +1314,7 @@ func (e *executor) Execute(ctx context.Context, id state.Identifier, item queue.
 			span.SetName(spanName)
 
 			if byt, err := json.Marshal(resp.Output); err == nil {
+				byt = e.redacted(ctx, redact.KindOutput, byt)
 				fnSpan.AddEvent(string(byt), trace.WithAttributes(
 					attribute.Bool(consts.OtelSysFunctionOutput, true),
 				))
@@ -847,7 +1339,7 @@ func init() {
 }
 
 func (e *executor) HandleResponse(ctx context.Context, id state.Identifier, item queue.Item, edge inngest.Edge, resp *state.DriverResponse) error {
-	for _, e := range e.lifecycles {
+	for _, e := range e.listeners() {
 		// OnStepFinished handles step success and step errors/failures.  It is
 		// currently the responsibility of the lifecycle manager to handle the differing
 		// step statuses when a step finishes.
@@ -870,7 +1362,7 @@ func (e *executor) HandleResponse(ctx context.Context, id state.Identifier, item
 		if resp.Retryable() {
 			// Retries are a native aspect of the queue;  returning errors always
 			// retries steps if possible.
-			for _, e := range e.lifecycles {
+			for _, e := range e.listeners() {
 				// Run the lifecycle method for this retry, which is baked into the queue.
 				item.Attempt += 1
 				go e.OnStepScheduled(context.WithoutCancel(ctx), id, item, &resp.Step.Name)
@@ -894,7 +1386,7 @@ func (e *executor) HandleResponse(ctx context.Context, id state.Identifier, item
 			if serr := e.sm.SetStatus(ctx, id, enums.RunStatusFailed); serr != nil {
 				return fmt.Errorf("error marking function as complete: %w", serr)
 			}
-			s, err := e.sm.Load(ctx, id.RunID)
+			s, err := e.finishedState(ctx, id, enums.RunStatusFailed, "", "")
 			if err != nil {
 				return fmt.Errorf("unable to load run: %w", err)
 			}
@@ -902,8 +1394,12 @@ func (e *executor) HandleResponse(ctx context.Context, id state.Identifier, item
 			if err := e.runFinishHandler(ctx, id, s, *resp); err != nil {
 				logger.From(ctx).Error().Err(err).Msg("error running finish handler")
 			}
+			if err := e.runFailureHandler(ctx, id, s, *resp); err != nil {
+				logger.From(ctx).Error().Err(err).Msg("error running failure handler")
+			}
+			recordRunMetrics(ctx, id, s, enums.RunStatusFailed)
 
-			for _, e := range e.lifecycles {
+			for _, e := range e.listeners() {
 				go e.OnFunctionFinished(context.WithoutCancel(ctx), id, item, *resp, s)
 			}
 			return resp
@@ -918,19 +1414,24 @@ func (e *executor) HandleResponse(ctx context.Context, id state.Identifier, item
 			if strings.Contains(serr.Error(), "error compiling expression") {
 				resp.SetError(serr)
 				resp.SetFinal()
-				_ = e.sm.SaveResponse(ctx, id, resp.Step.ID, resp.Error())
+				output := e.redactedString(ctx, redact.KindOutput, resp.Error())
+				_ = e.sm.SaveResponse(ctx, id, resp.Step.ID, output)
 				// XXX: failureHandler is legacy.
 				if serr := e.sm.SetStatus(ctx, id, enums.RunStatusFailed); serr != nil {
 					return fmt.Errorf("error marking function as complete: %w", serr)
 				}
-				s, err := e.sm.Load(ctx, id.RunID)
+				s, err := e.finishedState(ctx, id, enums.RunStatusFailed, resp.Step.ID, output)
 				if err != nil {
 					return fmt.Errorf("unable to load run: %w", err)
 				}
 				if err := e.runFinishHandler(ctx, id, s, *resp); err != nil {
 					logger.From(ctx).Error().Err(err).Msg("error running finish handler")
 				}
-				for _, e := range e.lifecycles {
+				if err := e.runFailureHandler(ctx, id, s, *resp); err != nil {
+					logger.From(ctx).Error().Err(err).Msg("error running failure handler")
+				}
+				recordRunMetrics(ctx, id, s, enums.RunStatusFailed)
+				for _, e := range e.listeners() {
 					go e.OnFunctionFinished(context.WithoutCancel(ctx), id, item, *resp, s)
 				}
 				return nil
@@ -941,15 +1442,13 @@ func (e *executor) HandleResponse(ctx context.Context, id state.Identifier, item
 	}
 
 	// This is the function result.
-
-	// TODO: Use state loaded before function call instead of loading once again
-	// to reduce load.  That way, we never need to call SaveResponse and Load().
 	//
 	// Save this in the state store (which will inevitably be GC'd), and end
 	output, err := json.Marshal(resp.Output)
 	if err != nil {
 		return err
 	}
+	output = e.redacted(ctx, redact.KindOutput, output)
 
 	if serr := e.sm.SaveResponse(ctx, id, resp.Step.ID, string(output)); serr != nil {
 		// Final function responses can be duplicated if multiple parallel
@@ -960,17 +1459,17 @@ func (e *executor) HandleResponse(ctx context.Context, id state.Identifier, item
 		}
 		return fmt.Errorf("error saving function output: %w", serr)
 	}
-	s, err := e.sm.Load(ctx, id.RunID)
+	s, err := e.finishedState(ctx, id, enums.RunStatusCompleted, resp.Step.ID, string(output))
 	if err != nil {
 		return fmt.Errorf("unable to load run: %w", err)
 	}
-	// end todo
 
 	if err := e.runFinishHandler(ctx, id, s, *resp); err != nil {
 		logger.From(ctx).Error().Err(err).Msg("error running finish handler")
 	}
+	recordRunMetrics(ctx, id, s, enums.RunStatusCompleted)
 
-	for _, e := range e.lifecycles {
+	for _, e := range e.listeners() {
 		go e.OnFunctionFinished(context.WithoutCancel(ctx), id, item, *resp, s)
 	}
 
@@ -981,6 +1480,39 @@ func (e *executor) HandleResponse(ctx context.Context, id state.Identifier, item
 	return nil
 }
 
+// finishedState returns the run's state once it has finished with the given
+// status.  The state loaded prior to executing the step is reused where
+// possible, applying the step's saved output rather than reloading state from
+// the store.
+func (e *executor) finishedState(ctx context.Context, id state.Identifier, status enums.RunStatus, stepID, marshalledOutput string) (state.State, error) {
+	if s := getContextState(ctx); s != nil && !state.HasLazyEvents(s) {
+		md := s.Metadata()
+		md.Status = status
+		return stateWithResponse(s, md, stepID, marshalledOutput), nil
+	}
+	// Finish handlers require the run's full batch, so runs loaded lazily
+	// reload their state.  The step's output is already saved.
+	s, err := e.sm.Load(ctx, id.RunID)
+	if err != nil {
+		return nil, err
+	}
+	md := s.Metadata()
+	md.Status = status
+	return stateWithResponse(s, md, "", ""), nil
+}
+
+// releaseOrdered allows the next run sharing the finished run's ordered
+// concurrency keys to start.
+func (e *executor) releaseOrdered(ctx context.Context, id state.Identifier) {
+	r, ok := e.queue.(queue.OrderedRunReleaser)
+	if !ok {
+		return
+	}
+	if err := r.ReleaseOrdered(ctx, id); err != nil {
+		logger.From(ctx).Error().Err(err).Str("run_id", id.RunID.String()).Msg("error releasing ordered concurrency keys")
+	}
+}
+
 type functionFinishedData struct {
 	FunctionID          string           `json:"function_id"`
 	RunID               ulid.ULID        `json:"run_id"`
@@ -1010,6 +1542,10 @@ func (f functionFinishedData) Map() map[string]any {
 }
 
 func (e *executor) runFinishHandler(ctx context.Context, id state.Identifier, s state.State, resp state.DriverResponse) error {
+	// This is called once for every run which finishes, so this is where runs
+	// release their ordered concurrency keys.
+	e.releaseOrdered(ctx, id)
+
 	if e.finishHandler == nil {
 		return nil
 	}
@@ -1027,7 +1563,7 @@ func (e *executor) runFinishHandler(ctx context.Context, id state.Identifier, s
 	// for batched functions.
 	var events []event.Event
 	for n, runEvt := range s.Events() {
-		if name, ok := runEvt["name"].(string); ok && (name == event.FnFailedName || name == event.FnFinishedName) {
+		if name, ok := runEvt["name"].(string); ok && event.IsRunLifecycleEvent(name) {
 			// Don't recursively trigger internal finish handlers.
 			continue
 		}
@@ -1067,6 +1603,177 @@ func (e *executor) runFinishHandler(ctx context.Context, id state.Identifier, s
 	return e.finishHandler(ctx, s, events)
 }
 
+// runFailureHandler schedules the function's onFailure handler, if specified, when
+// a run permanently fails.  The handler is triggered with an `inngest/function.failed`
+// event containing the failed run's identifiers and error.  Handlers which are
+// triggered by `inngest/function.failed` events already run for the failure event
+// sent when the run finishes, so they're not scheduled again.
+func (e *executor) runFailureHandler(ctx context.Context, id state.Identifier, s state.State, resp state.DriverResponse) error {
+	fn := s.Function()
+	if fn.OnFailure == nil || *fn.OnFailure == "" || resp.Err == nil {
+		return nil
+	}
+	if strings.Contains(*resp.Err, state.ErrFunctionCancelled.Error()) {
+		// Cancelled runs haven't failed.
+		return nil
+	}
+	if name, ok := s.Event()["name"].(string); ok && name == event.FnFailedName {
+		// Don't recursively trigger failure handlers.
+		return nil
+	}
+	if e.failureHandlerLoader == nil {
+		return fmt.Errorf("no failure handler loader specified")
+	}
+
+	loaded, err := e.failureHandlerLoader(ctx, id, *fn.OnFailure)
+	if err != nil {
+		return fmt.Errorf("error loading failure handler '%s': %w", *fn.OnFailure, err)
+	}
+	handler, err := loaded.InngestFunction()
+	if err != nil {
+		return fmt.Errorf("error loading failure handler '%s': %w", *fn.OnFailure, err)
+	}
+	// Runs load functions by their internal ID.
+	handler.ID = loaded.ID
+	for _, t := range handler.Triggers {
+		if t.EventTrigger != nil && t.Event == event.FnFailedName {
+			return nil
+		}
+	}
+
+	data := &functionFinishedData{
+		FunctionID: fn.Slug,
+		RunID:      id.RunID,
+		Event:      s.Event(),
+		Events:     s.Events(),
+	}
+	data.setResponse(resp)
+
+	now := time.Now()
+	evt := event.Event{
+		ID:        ulid.MustNew(uint64(now.UnixMilli()), rand.Reader).String(),
+		Name:      event.FnFailedName,
+		Timestamp: now.UnixMilli(),
+		Data:      data.Map(),
+	}
+
+	// Key the handler's run by the failed run's ID so that each failure is only
+	// handled once, even if the failure is processed more than once.
+	key := id.RunID.String()
+	_, err = e.Schedule(ctx, execution.ScheduleRequest{
+		Function:       *handler,
+		AccountID:      id.AccountID,
+		WorkspaceID:    id.WorkspaceID,
+		AppID:          loaded.AppID,
+		Events:         []event.TrackedEvent{event.NewOSSTrackedEventForWorkspace(evt, id.WorkspaceID)},
+		IdempotencyKey: &key,
+	})
+	if err == state.ErrIdentifierExists {
+		return nil
+	}
+	return err
+}
+
+const (
+	// limitSteps is the limit reported when a run exceeds its step limit.
+	limitSteps = "steps"
+	// limitParallelSteps is the limit reported when a run plans too many steps at once.
+	limitParallelSteps = "parallel_steps"
+)
+
+type functionLimitedData struct {
+	FunctionID string         `json:"function_id"`
+	RunID      ulid.ULID      `json:"run_id"`
+	Event      map[string]any `json:"event"`
+	Limit      string         `json:"limit"`
+	Max        int            `json:"max"`
+	Count      int            `json:"count"`
+}
+
+func (f functionLimitedData) Map() map[string]any {
+	s := structs.New(f)
+	s.TagName = "json"
+	return s.Map()
+}
+
+// runLimitedHandler sends an `inngest/function.limited` event when a run is stopped
+// for exceeding one of its limits, so that users can alert on limited runs.
+func (e *executor) runLimitedHandler(ctx context.Context, id state.Identifier, s state.State, limit string, max, count int) error {
+	if e.finishHandler == nil {
+		return nil
+	}
+
+	evt := s.Event()
+	if name, ok := evt["name"].(string); ok && name == event.FnLimitedName {
+		// Don't recursively trigger internal limit handlers.
+		return nil
+	}
+
+	now := time.Now()
+	data := functionLimitedData{
+		FunctionID: s.Function().Slug,
+		RunID:      id.RunID,
+		Event:      evt,
+		Limit:      limit,
+		Max:        max,
+		Count:      count,
+	}
+	return e.finishHandler(ctx, s, []event.Event{{
+		ID:        ulid.MustNew(uint64(now.UnixMilli()), rand.Reader).String(),
+		Name:      event.FnLimitedName,
+		Timestamp: now.UnixMilli(),
+		Data:      data.Map(),
+	}})
+}
+
+type waitTimeoutData struct {
+	FunctionID string    `json:"function_id"`
+	RunID      ulid.ULID `json:"run_id"`
+	StepID     string    `json:"step_id"`
+	StepName   string    `json:"step_name"`
+	Event      string    `json:"event"`
+	Expression string    `json:"expression,omitempty"`
+}
+
+func (w waitTimeoutData) Map() map[string]any {
+	s := structs.New(w)
+	s.TagName = "json"
+	return s.Map()
+}
+
+// waitTimeoutHandler sends an `inngest/wait.timeout` event when a step waiting for
+// an event times out, so that users can monitor waits which frequently time out.
+func (e *executor) waitTimeoutHandler(ctx context.Context, pause state.Pause) error {
+	if e.finishHandler == nil {
+		return nil
+	}
+
+	s, err := e.sm.Load(ctx, pause.Identifier.RunID)
+	if err != nil {
+		return fmt.Errorf("error loading state for wait timeout: %w", err)
+	}
+
+	now := time.Now()
+	data := waitTimeoutData{
+		FunctionID: s.Function().Slug,
+		RunID:      pause.Identifier.RunID,
+		StepID:     pause.DataKey,
+		StepName:   pause.StepName,
+	}
+	if pause.Event != nil {
+		data.Event = *pause.Event
+	}
+	if pause.Expression != nil {
+		data.Expression = *pause.Expression
+	}
+	return e.finishHandler(ctx, s, []event.Event{{
+		ID:        ulid.MustNew(uint64(now.UnixMilli()), rand.Reader).String(),
+		Name:      event.WaitTimeoutName,
+		Timestamp: now.UnixMilli(),
+		Data:      data.Map(),
+	}})
+}
+
 func correlationID(event map[string]any) *string {
 	dataMap, ok := event["data"].(map[string]any)
 	if !ok {
@@ -1099,10 +1806,15 @@ func (e *executor) run(ctx context.Context, id state.Identifier, item queue.Item
 		return nil, newFinalError(fmt.Errorf("unknown vertex: %s", edge.Incoming))
 	}
 
-	for _, e := range e.lifecycles {
+	for _, e := range e.listeners() {
 		go e.OnStepStarted(context.WithoutCancel(ctx), id, item, edge, *step, s)
 	}
 
+	// Allow SDKs to stream step outputs, handling each step as it finishes.
+	if e.sm != nil {
+		ctx = driver.WithCheckpointer(ctx, e.checkpointer(item, edge, *step, s, f))
+	}
+
 	// Execute the actual step.
 	response, err := e.executeDriverForStep(ctx, id, item, step, s, edge, stackIndex)
 
@@ -1121,7 +1833,10 @@ func (e *executor) executeDriverForStep(ctx context.Context, id state.Identifier
 		return nil, fmt.Errorf("%w: '%s'", ErrNoRuntimeDriver, step.Driver())
 	}
 
-	response, err := d.Execute(ctx, s, item, edge, *step, stackIndex, item.Attempt)
+	ctx, cancel := context.WithTimeout(ctx, item.GetRequestTimeout())
+	defer cancel()
+
+	response, err := e.executeDriver(ctx, d, s, item, edge, *step, stackIndex)
 
 	if response == nil {
 		response = &state.DriverResponse{
@@ -1160,6 +1875,24 @@ func (e *executor) executeDriverForStep(ctx context.Context, id state.Identifier
 	return response, err
 }
 
+// executeDriver invokes the driver via any configured middleware, converting
+// panics into retryable errors so that a misbehaving driver can't crash the service.
+func (e *executor) executeDriver(ctx context.Context, d driver.Driver, s state.State, item queue.Item, edge inngest.Edge, step inngest.Step, stackIndex int) (resp *state.DriverResponse, err error) {
+	defer func() {
+		r := recover()
+		if r == nil {
+			return
+		}
+		stack := string(debug.Stack())
+		resp, err = nil, fmt.Errorf("%w: %v", ErrDriverPanic, r)
+		trace.SpanFromContext(ctx).RecordError(err, trace.WithAttributes(
+			attribute.String(consts.OtelSysStepPanicStack, stack),
+		))
+		log.From(ctx).Error().Err(err).Str("stack", stack).Msg("recovered from driver panic")
+	}()
+	return driver.Chain(d, e.driverMiddleware...).Execute(ctx, s, item, edge, step, stackIndex, item.Attempt)
+}
+
 // HandlePauses handles pauses loaded from an incoming event.
 func (e *executor) HandlePauses(ctx context.Context, iter state.PauseIterator, evt event.TrackedEvent) (execution.HandlePauseResult, error) {
 	// Use the aggregator for all funciton finished events, if there are more than
@@ -1167,6 +1900,15 @@ func (e *executor) HandlePauses(ctx context.Context, iter state.PauseIterator, e
 	// than 50;  anything more runs the risk of running slow.
 	if iter.Count() > 10 {
 		aggRes, err := e.handleAggregatePauses(ctx, evt)
+		if errors.Is(err, expressions.ErrEvaluatorEvicted) {
+			// The event has too many pauses to aggregate in memory, so
+			// evaluate each pause individually.
+			res, err := e.handlePausesAllNaively(ctx, iter, evt)
+			if err != nil {
+				log.From(ctx).Error().Err(err).Msg("error handling pauses")
+			}
+			return res, nil
+		}
 		if err != nil {
 			log.From(ctx).Error().Err(err).Msg("error handling aggregate pauses")
 		}
@@ -1235,7 +1977,7 @@ func (e *executor) handlePausesAllNaively(ctx context.Context, iter state.PauseI
 			// NOTE: Some pauses may be nil or expired, as the iterator may take
 			// time to process.  We handle that here and assume that the event
 			// did not occur in time.
-			if pause.Expires.Time().Before(time.Now()) {
+			if pause.Expired(time.Now()) {
 				// Consume this pause to remove it entirely
 				l.Debug().Msg("deleting expired pause")
 				_ = e.sm.DeletePause(context.Background(), *pause)
@@ -1280,7 +2022,17 @@ func (e *executor) handlePausesAllNaively(ctx context.Context, iter state.PauseI
 					return
 				}
 
-				val, _, err := expr.Evaluate(ctx, data)
+				val, _, err := expr.Evaluate(expressions.WithBudget(ctx, e.pauseExprBudget), data)
+				if errors.Is(err, expressions.ErrEvaluationTimeout) || errors.Is(err, expressions.ErrCostLimitExceeded) {
+					// Skip pauses with pathological expressions, so that they
+					// can't stall handling every other pause.
+					l.Warn().Err(err).Str("expression", *pause.Expression).Msg("skipping pause expression exceeding evaluation budget")
+					telemetry.IncrPauseExpressionSkippedCounter(ctx, telemetry.CounterOpt{
+						PkgName: pkgName,
+						Tags:    map[string]any{"reason": pauseExprSkipReason(err)},
+					})
+					return
+				}
 				if err != nil {
 					l.Warn().Err(err).Msg("error evaluating pause expression")
 					return
@@ -1404,7 +2156,7 @@ func (e *executor) handleAggregatePauses(ctx context.Context, evt event.TrackedE
 			// NOTE: Some pauses may be nil or expired, as the iterator may take
 			// time to process.  We handle that here and assume that the event
 			// did not occur in time.
-			if pause.Expires.Time().Before(time.Now()) {
+			if pause.Expired(time.Now()) {
 				// Consume this pause to remove it entirely
 				l.Debug("deleting expired pause")
 				_ = e.sm.DeletePause(context.Background(), pause)
@@ -1516,7 +2268,7 @@ func (e *executor) HandleInvokeFinish(ctx context.Context, evt event.TrackedEven
 		return err
 	}
 
-	if pause.Expires.Time().Before(time.Now()) {
+	if pause.Expired(time.Now()) {
 		// Consume this pause to remove it entirely
 		l.Debug().Msg("deleting expired pause")
 		_ = e.sm.DeletePause(context.Background(), *pause)
@@ -1586,7 +2338,7 @@ func (e *executor) Cancel(ctx context.Context, runID ulid.ULID, r execution.Canc
 	}
 
 	ctx = e.extractTraceCtx(ctx, md.Identifier, nil)
-	for _, e := range e.lifecycles {
+	for _, e := range e.listeners() {
 		go e.OnFunctionCancelled(context.WithoutCancel(ctx), md.Identifier, r, s)
 	}
 
@@ -1659,6 +2411,14 @@ func (e *executor) Resume(ctx context.Context, pause state.Pause, r execution.Re
 		return fmt.Errorf("error enqueueing after pause: %w", err)
 	}
 
+	// Send an event when waiting for an event times out, only once the step
+	// has been enqueued for the first time.
+	if err == nil && r.Timeout && pause.Opcode != nil && *pause.Opcode == enums.OpcodeWaitForEvent.String() {
+		if err := e.waitTimeoutHandler(ctx, pause); err != nil {
+			logger.From(ctx).Error().Err(err).Msg("error sending wait timeout event")
+		}
+	}
+
 	if pause.Opcode != nil && *pause.Opcode == enums.OpcodeInvokeFunction.String() {
 		if pause.StepSpanID != nil && *pause.StepSpanID != "" {
 			if spanID, err := trace.SpanIDFromHex(*pause.StepSpanID); err == nil {
@@ -1723,11 +2483,11 @@ func (e *executor) Resume(ctx context.Context, pause state.Pause, r execution.Re
 			}
 		}
 
-		for _, e := range e.lifecycles {
+		for _, e := range e.listeners() {
 			go e.OnInvokeFunctionResumed(context.WithoutCancel(ctx), pause.Identifier, r, pause.GroupID)
 		}
 	} else {
-		for _, e := range e.lifecycles {
+		for _, e := range e.listeners() {
 			go e.OnWaitForEventResumed(context.WithoutCancel(ctx), pause.Identifier, r, pause.GroupID)
 		}
 	}
@@ -1741,6 +2501,10 @@ func (e *executor) HandleGeneratorResponse(ctx context.Context, resp *state.Driv
 		return fmt.Errorf("error loading function metadata: %w", err)
 	}
 
+	if len(resp.Checkpointed) > 0 {
+		ctx = withCheckpointed(ctx, resp.Checkpointed)
+	}
+
 	{
 		// The following code helps with parallelism and the V2 -> V3 executor changes
 		var update *state.MetadataUpdate
@@ -1771,7 +2535,12 @@ func (e *executor) HandleGeneratorResponse(ctx context.Context, resp *state.Driv
 			}
 		}
 		if update != nil {
-			if err := e.sm.UpdateMetadata(ctx, item.Identifier.RunID, *update); err != nil {
+			_, batched := e.sm.(state.ResponseMetadataSaver)
+			if batched && len(resp.Generator) == 1 && isStepOutput(resp.Generator[0]) && !isCheckpointed(ctx, resp.Generator[0].ID) {
+				// Save the update along with the step's response, saving a
+				// round trip to the state store.
+				ctx = withMetadataUpdate(ctx, *update)
+			} else if err := e.sm.UpdateMetadata(ctx, item.Identifier.RunID, *update); err != nil {
 				return fmt.Errorf("error updating function metadata: %w", err)
 			}
 		}
@@ -1788,6 +2557,23 @@ func (e *executor) HandleGeneratorResponse(ctx context.Context, resp *state.Driv
 }
 
 func (e *executor) handleGeneratorGroup(ctx context.Context, group OpcodeGroup, resp *state.DriverResponse, item queue.Item) error {
+	// Enqueue items for each planned step within a single round trip when
+	// planning many parallel steps.  Only planned steps are batched, as they
+	// enqueue immediately;  other generators, eg. gateway requests or invokes,
+	// may take minutes to enqueue and must not block their siblings.
+	var batch *enqueueBatch
+	if producer, ok := e.queue.(queue.BulkProducer); ok {
+		size := 0
+		for _, op := range group.Opcodes {
+			if isBatchedEnqueue(op) {
+				size++
+			}
+		}
+		if size > 1 {
+			batch = newEnqueueBatch(ctx, producer, size)
+		}
+	}
+
 	eg := errgroup.Group{}
 	for _, op := range group.Opcodes {
 		if op == nil {
@@ -1806,7 +2592,15 @@ func (e *executor) handleGeneratorGroup(ctx context.Context, group OpcodeGroup,
 			newItem.GroupID = uuid.New().String()
 		}
 
-		eg.Go(func() error { return e.HandleGenerator(ctx, copied, newItem) })
+		if batch == nil || !isBatchedEnqueue(op) {
+			eg.Go(func() error { return e.HandleGenerator(ctx, copied, newItem) })
+			continue
+		}
+		opCtx, done := batch.member(ctx)
+		eg.Go(func() error {
+			defer done()
+			return e.HandleGenerator(opCtx, copied, newItem)
+		})
 	}
 	if err := eg.Wait(); err != nil {
 		if resp.NoRetry {
@@ -1821,6 +2615,27 @@ func (e *executor) handleGeneratorGroup(ctx context.Context, group OpcodeGroup,
 	return nil
 }
 
+// isBatchedEnqueue returns whether the opcode's enqueue may be batched with
+// its siblings when handling a group of generators.
+func isBatchedEnqueue(gen *state.GeneratorOpcode) bool {
+	return gen != nil && gen.Op == enums.OpcodeStepPlanned
+}
+
+// isStepOutput returns whether the opcode saves a step's output.
+func isStepOutput(gen *state.GeneratorOpcode) bool {
+	return gen != nil && (gen.Op == enums.OpcodeStep || gen.Op == enums.OpcodeStepRun)
+}
+
+// saveResponse saves a step's response, applying any metadata update deferred
+// by HandleGeneratorResponse within the same call.
+func (e *executor) saveResponse(ctx context.Context, id state.Identifier, stepID, output string) error {
+	md := getMetadataUpdate(ctx)
+	if saver, ok := e.sm.(state.ResponseMetadataSaver); ok && md != nil {
+		return saver.SaveResponseWithMetadata(ctx, id, stepID, output, *md)
+	}
+	return e.sm.SaveResponse(ctx, id, stepID, output)
+}
+
 func (e *executor) HandleGenerator(ctx context.Context, gen state.GeneratorOpcode, item queue.Item) error {
 	// Grab the edge that triggered this step execution.
 	edge, ok := item.Payload.(queue.PayloadEdge)
@@ -1849,6 +2664,10 @@ func (e *executor) HandleGenerator(ctx context.Context, gen state.GeneratorOpcod
 		return e.handleGeneratorWaitForEvent(ctx, gen, item, edge)
 	case enums.OpcodeInvokeFunction:
 		return e.handleGeneratorInvokeFunction(ctx, gen, item, edge)
+	case enums.OpcodeAIGateway:
+		return e.handleGeneratorAIGateway(ctx, gen, item, edge)
+	case enums.OpcodeGateway:
+		return e.handleGeneratorGateway(ctx, gen, item, edge)
 	}
 
 	return fmt.Errorf("unknown opcode: %s", gen.Op)
@@ -1864,14 +2683,12 @@ func (e *executor) handleGeneratorStep(ctx context.Context, gen state.GeneratorO
 		Incoming: edge.Edge.Incoming, // And re-calling the incoming function in a loop
 	}
 
-	// Save the response to the state store.
-	output, err := gen.Output()
-	if err != nil {
-		return err
-	}
-
-	if err := e.sm.SaveResponse(ctx, item.Identifier, gen.ID, output); err != nil {
-		return err
+	// Steps checkpointed while the request was in progress have already been
+	// saved.
+	if !isCheckpointed(ctx, gen.ID) {
+		if err := e.saveGeneratorStep(ctx, gen, item); err != nil {
+			return err
+		}
 	}
 
 	// Update the group ID in context;  we've already saved this step's success and we're now
@@ -1883,16 +2700,17 @@ func (e *executor) handleGeneratorStep(ctx context.Context, gen state.GeneratorO
 	jobID := fmt.Sprintf("%s-%s", item.Identifier.IdempotencyKey(), gen.ID)
 	now := time.Now()
 	nextItem := queue.Item{
-		JobID:       &jobID,
-		WorkspaceID: item.WorkspaceID,
-		GroupID:     groupID,
-		Kind:        queue.KindEdge,
-		Identifier:  item.Identifier,
-		Attempt:     0,
-		MaxAttempts: item.MaxAttempts,
-		Payload:     queue.PayloadEdge{Edge: nextEdge},
+		JobID:          &jobID,
+		WorkspaceID:    item.WorkspaceID,
+		GroupID:        groupID,
+		Kind:           queue.KindEdge,
+		Identifier:     item.Identifier,
+		Attempt:        0,
+		MaxAttempts:    item.MaxAttempts,
+		RequestTimeout: item.RequestTimeout,
+		Payload:        queue.PayloadEdge{Edge: nextEdge},
 	}
-	err = e.queue.Enqueue(ctx, nextItem, now)
+	err := e.enqueue(ctx, nextItem, now)
 	if err == redis_state.ErrQueueItemExists {
 		return nil
 	}
@@ -1901,7 +2719,7 @@ func (e *executor) handleGeneratorStep(ctx context.Context, gen state.GeneratorO
 		attribute.Int64(consts.OtelSysStepNextTimestamp, now.UnixMilli()),
 	)
 
-	for _, l := range e.lifecycles {
+	for _, l := range e.listeners() {
 		// We can't specify step name here since that will result in the
 		// "followup discovery step" having the same name as its predecessor.
 		var stepName *string = nil
@@ -1911,6 +2729,15 @@ func (e *executor) handleGeneratorStep(ctx context.Context, gen state.GeneratorO
 	return err
 }
 
+// saveGeneratorStep saves a step's output to the state store.
+func (e *executor) saveGeneratorStep(ctx context.Context, gen state.GeneratorOpcode, item queue.Item) error {
+	output, err := gen.Output()
+	if err != nil {
+		return err
+	}
+	return e.saveResponse(ctx, item.Identifier, gen.ID, e.redactedString(ctx, redact.KindOutput, output))
+}
+
 func (e *executor) handleStepError(ctx context.Context, gen state.GeneratorOpcode, item queue.Item, edge queue.PayloadEdge) error {
 	// With the introduction of the StepError opcode, step errors are handled graceully and we can
 	// finally distinguish between application level errors (this function) and network errors/other
@@ -1950,7 +2777,7 @@ func (e *executor) handleStepError(ctx context.Context, gen state.GeneratorOpcod
 
 	if retryable {
 		// Return an error to trigger standard queue retries.
-		for _, l := range e.lifecycles {
+		for _, l := range e.listeners() {
 			item.Attempt += 1
 			go l.OnStepScheduled(ctx, item.Identifier, item, &gen.Name)
 		}
@@ -1967,7 +2794,7 @@ func (e *executor) handleStepError(ctx context.Context, gen state.GeneratorOpcod
 	if err != nil {
 		return err
 	}
-	if err := e.sm.SaveResponse(ctx, item.Identifier, gen.ID, output); err != nil {
+	if err := e.sm.SaveResponse(ctx, item.Identifier, gen.ID, e.redactedString(ctx, redact.KindOutput, output)); err != nil {
 		return err
 	}
 
@@ -1984,16 +2811,17 @@ func (e *executor) handleStepError(ctx context.Context, gen state.GeneratorOpcod
 	jobID := fmt.Sprintf("%s-%s-failure", item.Identifier.IdempotencyKey(), gen.ID)
 	now := time.Now()
 	nextItem := queue.Item{
-		JobID:       &jobID,
-		WorkspaceID: item.WorkspaceID,
-		GroupID:     groupID,
-		Kind:        queue.KindEdgeError,
-		Identifier:  item.Identifier,
-		Attempt:     0,
-		MaxAttempts: item.MaxAttempts,
-		Payload:     queue.PayloadEdge{Edge: nextEdge},
+		JobID:          &jobID,
+		WorkspaceID:    item.WorkspaceID,
+		GroupID:        groupID,
+		Kind:           queue.KindEdgeError,
+		Identifier:     item.Identifier,
+		Attempt:        0,
+		MaxAttempts:    item.MaxAttempts,
+		RequestTimeout: item.RequestTimeout,
+		Payload:        queue.PayloadEdge{Edge: nextEdge},
 	}
-	err = e.queue.Enqueue(ctx, nextItem, now)
+	err = e.enqueue(ctx, nextItem, now)
 	if err == redis_state.ErrQueueItemExists {
 		return nil
 	}
@@ -2001,13 +2829,117 @@ func (e *executor) handleStepError(ctx context.Context, gen state.GeneratorOpcod
 		attribute.Int64(consts.OtelSysStepNextTimestamp, now.UnixMilli()),
 	)
 
-	for _, l := range e.lifecycles {
+	for _, l := range e.listeners() {
 		go l.OnStepScheduled(ctx, item.Identifier, nextItem, nil)
 	}
 
 	return nil
 }
 
+// handleGeneratorAIGateway handles OpcodeAIGateway, making an inference request on
+// behalf of the SDK so that long model calls happen outside of the SDK's request.
+// The provider's response is saved as the step's output and the run resumes as if
+// the step ran within the SDK.  Failed requests are handled as step errors, and
+// are retried as per the function's retry policy.
+func (e *executor) handleGeneratorAIGateway(ctx context.Context, gen state.GeneratorOpcode, item queue.Item, edge queue.PayloadEdge) error {
+	span := trace.SpanFromContext(ctx)
+
+	if e.aiGateway == nil {
+		return e.handleGatewayError(ctx, gen, item, edge, "AIGatewayError", fmt.Errorf("AI inference is not enabled"), true, nil)
+	}
+
+	opts, err := gen.AIGatewayOpts()
+	if err != nil {
+		return e.handleGatewayError(ctx, gen, item, edge, "AIGatewayError", err, true, nil)
+	}
+	span.SetAttributes(attribute.String(consts.OtelSysStepAIProvider, opts.Provider))
+
+	// Bound the request regardless of the gateway's own timeout, as the request
+	// holds this queue worker until it finishes.
+	inferCtx, cancel := context.WithTimeout(ctx, aigateway.MaxTimeout)
+	defer cancel()
+	resp, err := e.aiGateway.Infer(inferCtx, aigateway.Request{
+		Provider: opts.Provider,
+		Body:     opts.Body,
+	})
+	if err != nil {
+		return e.handleGatewayError(ctx, gen, item, edge, "AIGatewayError", err, false, nil)
+	}
+	if !resp.OK() {
+		err := fmt.Errorf("AI provider %s responded with status %d", opts.Provider, resp.StatusCode)
+		return e.handleGatewayError(ctx, gen, item, edge, "AIGatewayError", err, !resp.Retryable(), resp.Body)
+	}
+
+	e.onGatewayRequestFinished(ctx, gen, item, execution.GatewayResult{Output: resp.Body})
+
+	// Save the response as if the step ran within the SDK.
+	gen.Op = enums.OpcodeStepRun
+	gen.Data = resp.Body
+	return e.handleGeneratorStep(ctx, gen, item, edge)
+}
+
+// handleGeneratorGateway handles OpcodeGateway, making an HTTP request on behalf of
+// the SDK.  The response's status, headers, and body are saved as the step's
+// output, regardless of the status code.  Requests which can't be made, eg. as the
+// host isn't allowed, are handled as step errors.
+func (e *executor) handleGeneratorGateway(ctx context.Context, gen state.GeneratorOpcode, item queue.Item, edge queue.PayloadEdge) error {
+	if e.gateway == nil {
+		return e.handleGatewayError(ctx, gen, item, edge, "GatewayError", fmt.Errorf("Gateway requests are not enabled"), true, nil)
+	}
+
+	opts, err := gen.GatewayOpts()
+	if err != nil {
+		return e.handleGatewayError(ctx, gen, item, edge, "GatewayError", err, true, nil)
+	}
+
+	resp, err := e.gateway.Fetch(ctx, gateway.Request{
+		URL:     opts.URL,
+		Method:  opts.Method,
+		Headers: opts.Headers,
+		Body:    opts.Body,
+	})
+	if err != nil {
+		noRetry := errors.Is(err, gateway.ErrHostNotAllowed) || errors.Is(err, gateway.ErrResponseTooLarge)
+		return e.handleGatewayError(ctx, gen, item, edge, "GatewayError", err, noRetry, nil)
+	}
+
+	byt, err := json.Marshal(resp)
+	if err != nil {
+		return err
+	}
+
+	e.onGatewayRequestFinished(ctx, gen, item, execution.GatewayResult{Output: byt})
+
+	// Save the response as if the step ran within the SDK.
+	gen.Op = enums.OpcodeStepRun
+	gen.Data = byt
+	return e.handleGeneratorStep(ctx, gen, item, edge)
+}
+
+// handleGatewayError handles a failed gateway request as a step error, so that
+// the request is retried as per the function's retry policy unless noRetry is set.
+func (e *executor) handleGatewayError(ctx context.Context, gen state.GeneratorOpcode, item queue.Item, edge queue.PayloadEdge, name string, err error, noRetry bool, data json.RawMessage) error {
+	userErr := &state.UserError{
+		Name:    name,
+		Message: err.Error(),
+		Data:    data,
+		NoRetry: noRetry,
+	}
+	e.onGatewayRequestFinished(ctx, gen, item, execution.GatewayResult{Error: userErr})
+
+	gen.Op = enums.OpcodeStepError
+	gen.Error = userErr
+	return e.handleStepError(ctx, gen, item, edge)
+}
+
+// onGatewayRequestFinished notifies listeners of a gateway request's result.  gen
+// must be the step's original OpcodeGateway or OpcodeAIGateway opcode.
+func (e *executor) onGatewayRequestFinished(ctx context.Context, gen state.GeneratorOpcode, item queue.Item, res execution.GatewayResult) {
+	for _, l := range e.listeners() {
+		go l.OnGatewayRequestFinished(context.WithoutCancel(ctx), item.Identifier, item, gen, res)
+	}
+}
+
 func (e *executor) handleGeneratorStepPlanned(ctx context.Context, gen state.GeneratorOpcode, item queue.Item, edge queue.PayloadEdge) error {
 	span := trace.SpanFromContext(ctx)
 
@@ -2032,18 +2964,19 @@ func (e *executor) handleGeneratorStepPlanned(ctx context.Context, gen state.Gen
 	jobID := fmt.Sprintf("%s-%s", item.Identifier.IdempotencyKey(), gen.ID+"-plan")
 	now := time.Now()
 	nextItem := queue.Item{
-		JobID:       &jobID,
-		GroupID:     groupID, // Ensure we correlate future jobs with this group ID, eg. started/failed.
-		WorkspaceID: item.WorkspaceID,
-		Kind:        queue.KindEdge,
-		Identifier:  item.Identifier,
-		Attempt:     0,
-		MaxAttempts: item.MaxAttempts,
+		JobID:          &jobID,
+		GroupID:        groupID, // Ensure we correlate future jobs with this group ID, eg. started/failed.
+		WorkspaceID:    item.WorkspaceID,
+		Kind:           queue.KindEdge,
+		Identifier:     item.Identifier,
+		Attempt:        0,
+		MaxAttempts:    item.MaxAttempts,
+		RequestTimeout: item.RequestTimeout,
 		Payload: queue.PayloadEdge{
 			Edge: nextEdge,
 		},
 	}
-	err := e.queue.Enqueue(ctx, nextItem, now)
+	err := e.enqueue(ctx, nextItem, now)
 	if err == redis_state.ErrQueueItemExists {
 		return nil
 	}
@@ -2052,7 +2985,7 @@ func (e *executor) handleGeneratorStepPlanned(ctx context.Context, gen state.Gen
 		attribute.Int64(consts.OtelSysStepNextTimestamp, now.UnixMilli()),
 	)
 
-	for _, l := range e.lifecycles {
+	for _, l := range e.listeners() {
 		go l.OnStepScheduled(ctx, item.Identifier, nextItem, &gen.Name)
 	}
 	return err
@@ -2106,18 +3039,19 @@ func (e *executor) handleGeneratorSleep(ctx context.Context, gen state.Generator
 
 	jobID := fmt.Sprintf("%s-%s", item.Identifier.IdempotencyKey(), gen.ID)
 	// TODO Should this also include a parent step span? It will never have attempts.
-	err = e.queue.Enqueue(ctx, queue.Item{
+	err = e.enqueue(ctx, queue.Item{
 		JobID:       &jobID,
 		WorkspaceID: item.WorkspaceID,
 		// Sleeps re-enqueue the step so that we can mark the step as completed
 		// in the executor after the sleep is complete.  This will re-call the
 		// generator step, but we need the same group ID for correlation.
-		GroupID:     groupID,
-		Kind:        queue.KindSleep,
-		Identifier:  item.Identifier,
-		Attempt:     0,
-		MaxAttempts: item.MaxAttempts,
-		Payload:     queue.PayloadEdge{Edge: nextEdge},
+		GroupID:        groupID,
+		Kind:           queue.KindSleep,
+		Identifier:     item.Identifier,
+		Attempt:        0,
+		MaxAttempts:    item.MaxAttempts,
+		RequestTimeout: item.RequestTimeout,
+		Payload:        queue.PayloadEdge{Edge: nextEdge},
 	}, until)
 	if err == redis_state.ErrQueueItemExists {
 		// Safely ignore this error.
@@ -2130,7 +3064,9 @@ func (e *executor) handleGeneratorSleep(ctx context.Context, gen state.Generator
 		attribute.Int64(consts.OtelSysStepNextTimestamp, until.UnixMilli()),
 	)
 
-	for _, e := range e.lifecycles {
+	e.setWaitingStatus(ctx, item.Identifier, enums.RunStatusSleeping)
+
+	for _, e := range e.listeners() {
 		go e.OnSleep(context.WithoutCancel(ctx), item.Identifier, item, gen, until)
 	}
 
@@ -2152,6 +3088,17 @@ func (e *executor) handleGeneratorInvokeFunction(ctx context.Context, gen state.
 		return fmt.Errorf("unable to parse invoke function expires: %w", err)
 	}
 
+	// Resolve functions invoked by their app and function slugs.  If no single
+	// function matches, the invoke step fails as if the function wasn't found.
+	fnID, resolveErr := e.resolveInvokeFunctionID(ctx, item.WorkspaceID, opts.FunctionID)
+	switch {
+	case resolveErr == nil:
+		opts.FunctionID = fnID
+	case !errors.Is(resolveErr, ErrInvokeFunctionNotFound) && !errors.Is(resolveErr, ErrInvokeFunctionAmbiguous):
+		// Errors loading functions may be temporary, so retry the step.
+		return resolveErr
+	}
+
 	eventName := event.FnFinishedName
 	correlationID := item.Identifier.RunID.String() + "." + gen.ID
 	strExpr := fmt.Sprintf("async.data.%s == %s", consts.InvokeCorrelationId, strconv.Quote(correlationID))
@@ -2234,7 +3181,7 @@ func (e *executor) handleGeneratorInvokeFunction(ctx context.Context, gen state.
 	// Enqueue a job that will timeout the pause.
 	jobID := fmt.Sprintf("%s-%s-%s", item.Identifier.IdempotencyKey(), gen.ID, "invoke")
 	// TODO I think this is fine sending no metadata, as we have no attempts.
-	err = e.queue.Enqueue(ctx, queue.Item{
+	err = e.enqueue(ctx, queue.Item{
 		JobID:       &jobID,
 		WorkspaceID: item.WorkspaceID,
 		// Use the same group ID, allowing us to track the cancellation of
@@ -2257,7 +3204,18 @@ func (e *executor) handleGeneratorInvokeFunction(ctx context.Context, gen state.
 		attribute.Int64(consts.OtelSysStepNextExpires, expires.UnixMilli()),
 	)
 
-	err = e.handleSendingEvent(ctx, evt, item)
+	if resolveErr != nil {
+		err = e.InvokeNotFoundHandler(ctx, execution.InvokeNotFoundHandlerOpts{
+			OriginalEvent: event.NewOSSTrackedEventForWorkspace(evt, item.WorkspaceID),
+			FunctionID:    opts.FunctionID,
+			Err: map[string]any{
+				"name":    "Error",
+				"message": resolveErr.Error(),
+			},
+		})
+	} else {
+		err = e.handleSendingEvent(ctx, evt, item)
+	}
 	if err != nil {
 		span.Cancel(ctx)
 		// TODO Cancel pause/timeout?
@@ -2266,7 +3224,9 @@ func (e *executor) handleGeneratorInvokeFunction(ctx context.Context, gen state.
 
 	span.Send()
 
-	for _, e := range e.lifecycles {
+	e.setWaitingStatus(ctx, item.Identifier, enums.RunStatusWaitingForEvent)
+
+	for _, e := range e.listeners() {
 		go e.OnInvokeFunction(context.WithoutCancel(ctx), item.Identifier, item, gen, ulid.MustParse(evt.ID), correlationID)
 	}
 
@@ -2374,7 +3334,7 @@ func (e *executor) handleGeneratorWaitForEvent(ctx context.Context, gen state.Ge
 	// pause is no longer available and return.
 	jobID := fmt.Sprintf("%s-%s-%s", item.Identifier.IdempotencyKey(), gen.ID, "wait")
 	// TODO Is this fine to leave? No attempts.
-	err = e.queue.Enqueue(ctx, queue.Item{
+	err = e.enqueue(ctx, queue.Item{
 		JobID:       &jobID,
 		WorkspaceID: item.WorkspaceID,
 		// Use the same group ID, allowing us to track the cancellation of
@@ -2396,13 +3356,39 @@ func (e *executor) handleGeneratorWaitForEvent(ctx context.Context, gen state.Ge
 		attribute.Int64(consts.OtelSysStepNextExpires, expires.UnixMilli()),
 	)
 
-	for _, e := range e.lifecycles {
+	e.setWaitingStatus(ctx, item.Identifier, enums.RunStatusWaitingForEvent)
+
+	for _, e := range e.listeners() {
 		go e.OnWaitForEvent(context.WithoutCancel(ctx), item.Identifier, item, gen)
 	}
 
 	return err
 }
 
+// setWaitingStatus records why a running function isn't progressing, eg. when
+// sleeping or waiting for an event.  The status is informational:  it's moved
+// back to running when the function's next step is executed, and never
+// overwrites an ended status.
+func (e *executor) setWaitingStatus(ctx context.Context, id state.Identifier, status enums.RunStatus) {
+	_, err := state.TransitionStatus(
+		ctx,
+		e.sm,
+		id,
+		status,
+		enums.RunStatusRunning,
+		enums.RunStatusWaitingForEvent,
+		enums.RunStatusSleeping,
+	)
+	if err != nil {
+		logger.StdlibLogger(ctx).Error(
+			"error setting waiting status",
+			"error", err,
+			"status", status.String(),
+			"run_id", id.RunID,
+		)
+	}
+}
+
 func (e *executor) newExpressionEvaluator(ctx context.Context, expr string) (expressions.Evaluator, error) {
 	if e.evalFactory != nil {
 		return e.evalFactory(ctx, expr)
@@ -2413,7 +3399,10 @@ func (e *executor) newExpressionEvaluator(ctx context.Context, expr string) (exp
 // extractTraceCtx extracts the trace context from the given item, if it exists.
 // If it doesn't it falls back to extracting the trace for the run overall.
 // If neither exist or they are invalid, it returns the original context.
+// The run's trace sample ratio is always stored in the returned context.
 func (e *executor) extractTraceCtx(ctx context.Context, id state.Identifier, item *queue.Item) context.Context {
+	ctx = telemetry.WithSampleRatio(ctx, id.TraceSampleRatio)
+
 	if item != nil {
 		metadata := make(map[string]any)
 		for k, v := range item.Metadata {
@@ -2446,27 +3435,21 @@ func (e executor) AppendAndScheduleBatch(ctx context.Context, fn inngest.Functio
 		return err
 	}
 
+	for _, l := range e.listeners() {
+		go l.OnBatchAppended(context.WithoutCancel(ctx), fn, bi, *result)
+	}
+
 	switch result.Status {
 	case enums.BatchAppend:
-		// noop
-	case enums.BatchNew:
-		dur, err := time.ParseDuration(fn.EventBatch.Timeout)
-		if err != nil {
+		// Sliding batches extend the timeout each time an item is appended.
+		if !fn.EventBatch.IsSliding() {
+			break
+		}
+		if err := e.scheduleBatch(ctx, fn, bi, result, true); err != nil {
 			return err
 		}
-		at := time.Now().Add(dur)
-
-		if err := e.batcher.ScheduleExecution(ctx, batch.ScheduleBatchOpts{
-			ScheduleBatchPayload: batch.ScheduleBatchPayload{
-				BatchID:         ulid.MustParse(result.BatchID),
-				AccountID:       bi.AccountID,
-				WorkspaceID:     bi.WorkspaceID,
-				AppID:           bi.AppID,
-				FunctionID:      bi.FunctionID,
-				FunctionVersion: bi.FunctionVersion,
-			},
-			At: at,
-		}); err != nil {
+	case enums.BatchNew:
+		if err := e.scheduleBatch(ctx, fn, bi, result, false); err != nil {
 			return err
 		}
 	case enums.BatchFull:
@@ -2480,6 +3463,29 @@ func (e executor) AppendAndScheduleBatch(ctx context.Context, fn inngest.Functio
 		}); err != nil {
 			return fmt.Errorf("could not retrieve and schedule batch items: %w", err)
 		}
+		// Full batches hold their slot until they start, so that events appended
+		// meanwhile spill over to the function's other batch slots.  Starting the
+		// batch frees the slot.
+		if _, err := e.batcher.StartExecution(ctx, fn.ID, batchID); err != nil {
+			return fmt.Errorf("could not start full batch: %w", err)
+		}
+	case enums.BatchOverflow:
+		// The function holds its maximum number of pending batches.
+		if e.rejectBatchOverflow {
+			return fmt.Errorf("%w: unable to batch event %s for function %s", ErrBatchLimitExceeded, bi.EventID, fn.GetSlug())
+		}
+		// Start a run immediately, in a batch of its own.
+		_, err := e.Schedule(ctx, execution.ScheduleRequest{
+			AccountID:       bi.AccountID,
+			WorkspaceID:     bi.WorkspaceID,
+			AppID:           bi.AppID,
+			Function:        fn,
+			Events:          []event.TrackedEvent{bi},
+			PreventDebounce: true,
+		})
+		if err != nil && err != ErrFunctionSkipped {
+			return fmt.Errorf("could not schedule overflowing batch item: %w", err)
+		}
 	default:
 		return fmt.Errorf("invalid status of batch append ops: %d", result.Status)
 	}
@@ -2487,6 +3493,34 @@ func (e executor) AppendAndScheduleBatch(ctx context.Context, fn inngest.Functio
 	return nil
 }
 
+// scheduleBatch schedules the job which runs the given batch once its timeout
+// elapses, optionally moving the batch's existing job.
+func (e executor) scheduleBatch(ctx context.Context, fn inngest.Function, bi batch.BatchItem, result *batch.BatchAppendResult, reschedule bool) error {
+	now := time.Now()
+	start := now
+	if result.CreatedAt > 0 {
+		start = time.UnixMilli(result.CreatedAt)
+	}
+	at, err := fn.EventBatch.Deadline(start, now)
+	if err != nil {
+		return err
+	}
+
+	return e.batcher.ScheduleExecution(ctx, batch.ScheduleBatchOpts{
+		ScheduleBatchPayload: batch.ScheduleBatchPayload{
+			BatchID:         ulid.MustParse(result.BatchID),
+			AccountID:       bi.AccountID,
+			WorkspaceID:     bi.WorkspaceID,
+			AppID:           bi.AppID,
+			FunctionID:      bi.FunctionID,
+			FunctionVersion: bi.FunctionVersion,
+			Trigger:         bi.Trigger,
+		},
+		At:         at,
+		Reschedule: reschedule,
+	})
+}
+
 // RetrieveAndScheduleBatch retrieves all items from a started batch and schedules a function run
 func (e executor) RetrieveAndScheduleBatch(ctx context.Context, fn inngest.Function, payload batch.ScheduleBatchPayload) error {
 	evtList, err := e.batcher.RetrieveItems(ctx, payload.BatchID)
@@ -2534,6 +3568,9 @@ func (e executor) RetrieveAndScheduleBatch(ctx context.Context, fn inngest.Funct
 		Events:         events,
 		BatchID:        &payload.BatchID,
 		IdempotencyKey: &key,
+		// Batches may be configured per trigger for functions which debounce other
+		// triggers.  Batched runs are never debounced.
+		PreventDebounce: true,
 	})
 	if err != nil {
 		span.SetStatus(codes.Error, err.Error())
@@ -1,10 +1,19 @@
 package executor
 
 import (
+	"context"
 	"crypto/rand"
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
+	"github.com/inngest/inngest/pkg/consts"
+	"github.com/inngest/inngest/pkg/cqrs"
+	"github.com/inngest/inngest/pkg/enums"
+	"github.com/inngest/inngest/pkg/event"
+	"github.com/inngest/inngest/pkg/execution"
+	"github.com/inngest/inngest/pkg/execution/state"
+	"github.com/inngest/inngest/pkg/inngest"
 	"github.com/inngest/inngestgo"
 	"github.com/oklog/ulid/v2"
 	"github.com/stretchr/testify/require"
@@ -37,3 +46,219 @@ func TestCancelExpressionGen(t *testing.T) {
 		require.Equal(t, test.Expected, actual)
 	}
 }
+
+func TestStepLimits(t *testing.T) {
+	e := &executor{}
+	id := state.Identifier{}
+	require.Equal(t, consts.DefaultMaxStepLimit, e.stepLimit(id, &inngest.Function{}))
+	require.Equal(t, consts.DefaultMaxParallelStepLimit, e.parallelStepLimit(id, &inngest.Function{}))
+
+	e.steplimit = func(id state.Identifier) int { return 50 }
+	e.parallelsteplimit = func(id state.Identifier) int { return 5 }
+	require.Equal(t, 50, e.stepLimit(id, &inngest.Function{}))
+	require.Equal(t, 5, e.parallelStepLimit(id, &inngest.Function{}))
+
+	// Functions override the executor's limits.
+	f := &inngest.Function{StepLimits: &inngest.StepLimits{Steps: 2_000, Parallel: 20}}
+	require.Equal(t, 2_000, e.stepLimit(id, f))
+	require.Equal(t, 20, e.parallelStepLimit(id, f))
+}
+
+func TestScheduleThrottle(t *testing.T) {
+	ctx := context.Background()
+	acctID, wsID := uuid.New(), uuid.New()
+	key := func(fnID uuid.UUID, scope enums.ConcurrencyScope, evt map[string]any) string {
+		req := execution.ScheduleRequest{
+			AccountID:   acctID,
+			WorkspaceID: wsID,
+			Function: inngest.Function{
+				ID:            fnID,
+				Throttle:      &inngest.Throttle{Limit: 1, Burst: 1, Period: time.Minute, Key: inngestgo.StrPtr("event.data.user")},
+				ThrottleScope: scope,
+			},
+		}
+		return scheduleThrottle(ctx, req, evt).Key
+	}
+	x := map[string]any{"data": map[string]any{"user": "x"}}
+	y := map[string]any{"data": map[string]any{"user": "y"}}
+
+	require.Nil(t, scheduleThrottle(ctx, execution.ScheduleRequest{}, nil))
+
+	a, b := uuid.New(), uuid.New()
+	require.NotEqual(t, key(a, enums.ConcurrencyScopeFn, x), key(b, enums.ConcurrencyScopeFn, x))
+	require.NotEqual(t, key(a, enums.ConcurrencyScopeFn, x), key(a, enums.ConcurrencyScopeFn, y))
+
+	// Env and account scoped throttles are shared by functions within the scope.
+	for _, scope := range []enums.ConcurrencyScope{enums.ConcurrencyScopeEnv, enums.ConcurrencyScopeAccount} {
+		require.Equal(t, key(a, scope, x), key(b, scope, x))
+		require.NotEqual(t, key(a, scope, x), key(a, scope, y))
+		require.NotEqual(t, key(a, enums.ConcurrencyScopeFn, x), key(a, scope, x))
+	}
+	require.NotEqual(t, key(a, enums.ConcurrencyScopeEnv, x), key(a, enums.ConcurrencyScopeAccount, x))
+}
+
+func TestCancellationTimeout(t *testing.T) {
+	ctx := context.Background()
+	wsID := uuid.New()
+	e := &executor{}
+	timeout := func(fn inngest.Function, c inngest.Cancel) time.Duration {
+		dur, err := e.cancellationTimeout(ctx, wsID, fn, c)
+		require.NoError(t, err)
+		return dur
+	}
+
+	require.Equal(t, consts.CancelTimeout, timeout(inngest.Function{}, inngest.Cancel{}))
+
+	// Workspaces, then functions, then cancellations override the default.
+	e.cancelTimeout = func(ctx context.Context, workspaceID uuid.UUID) time.Duration {
+		require.Equal(t, wsID, workspaceID)
+		return consts.CancelTimeoutNever
+	}
+	require.Equal(t, consts.CancelTimeoutNever, timeout(inngest.Function{}, inngest.Cancel{}))
+
+	fn := inngest.Function{CancelTimeout: inngestgo.StrPtr("30d")}
+	require.Equal(t, 30*24*time.Hour, timeout(fn, inngest.Cancel{}))
+	require.Equal(t, consts.CancelTimeoutNever, timeout(fn, inngest.Cancel{Timeout: inngestgo.StrPtr("never")}))
+	require.Equal(t, time.Hour, timeout(fn, inngest.Cancel{Timeout: inngestgo.StrPtr("1h")}))
+
+	_, err := e.cancellationTimeout(ctx, wsID, fn, inngest.Cancel{Timeout: inngestgo.StrPtr("soon")})
+	require.Error(t, err)
+}
+
+func TestRunLimitedHandler(t *testing.T) {
+	ctx := context.Background()
+	id := state.Identifier{RunID: ulid.MustNew(ulid.Now(), rand.Reader)}
+	s := state.NewStateInstance(
+		inngest.Function{Slug: "fn"},
+		id,
+		state.Metadata{},
+		[]map[string]any{{"name": "test/event"}},
+		map[string]any{},
+		map[string]error{},
+		[]string{},
+	)
+
+	var sent []event.Event
+	e := &executor{
+		finishHandler: func(ctx context.Context, s state.State, evts []event.Event) error {
+			sent = append(sent, evts...)
+			return nil
+		},
+	}
+
+	require.NoError(t, e.runLimitedHandler(ctx, id, s, limitSteps, 10, 11))
+	require.Len(t, sent, 1)
+	require.Equal(t, event.FnLimitedName, sent[0].Name)
+	require.Equal(t, "fn", sent[0].Data["function_id"])
+	require.Equal(t, limitSteps, sent[0].Data["limit"])
+	require.Equal(t, 10, sent[0].Data["max"])
+	require.Equal(t, 11, sent[0].Data["count"])
+}
+
+// createdManager is a state.Manager which records the runs created, reporting
+// that each run already exists.
+type createdManager struct {
+	state.Manager
+	created []state.Input
+}
+
+func (m *createdManager) New(ctx context.Context, input state.Input) (state.State, error) {
+	m.created = append(m.created, input)
+	return nil, state.ErrIdentifierExists
+}
+
+func TestRunFailureHandler(t *testing.T) {
+	ctx := context.Background()
+	id := state.Identifier{
+		RunID:       ulid.MustNew(ulid.Now(), rand.Reader),
+		WorkspaceID: uuid.New(),
+		AppID:       uuid.New(),
+	}
+	s := state.NewStateInstance(
+		inngest.Function{Slug: "fn", OnFailure: inngestgo.StrPtr("handler")},
+		id,
+		state.Metadata{},
+		[]map[string]any{{"name": "test/event"}},
+		map[string]any{},
+		map[string]error{},
+		[]string{},
+	)
+	resp := state.DriverResponse{Err: inngestgo.StrPtr("oh no")}
+
+	handlerID := uuid.New()
+	handlerAppID := uuid.New()
+	handler := func(event string) *cqrs.Function {
+		return &cqrs.Function{
+			ID:     handlerID,
+			AppID:  handlerAppID,
+			Slug:   "handler",
+			Config: []byte(`{"name":"handler","slug":"handler","triggers":[{"event":"` + event + `"}]}`),
+		}
+	}
+
+	t.Run("It schedules the handler within its own app", func(t *testing.T) {
+		sm := &createdManager{}
+		e := &executor{
+			sm: sm,
+			failureHandlerLoader: func(ctx context.Context, id state.Identifier, slug string) (*cqrs.Function, error) {
+				require.Equal(t, "handler", slug)
+				return handler("test/handle"), nil
+			},
+		}
+		require.NoError(t, e.runFailureHandler(ctx, id, s, resp))
+		require.Len(t, sm.created, 1)
+		created := sm.created[0].Identifier
+		require.Equal(t, handlerID, created.WorkflowID)
+		require.Equal(t, handlerAppID, created.AppID)
+		require.Equal(t, id.WorkspaceID, created.WorkspaceID)
+		require.Equal(t, id.RunID.String(), created.Key)
+		require.Equal(t, event.FnFailedName, sm.created[0].EventBatchData[0]["name"])
+	})
+
+	t.Run("It skips handlers triggered by failure events", func(t *testing.T) {
+		sm := &createdManager{}
+		e := &executor{
+			sm: sm,
+			failureHandlerLoader: func(ctx context.Context, id state.Identifier, slug string) (*cqrs.Function, error) {
+				return handler(event.FnFailedName), nil
+			},
+		}
+		require.NoError(t, e.runFailureHandler(ctx, id, s, resp))
+		require.Empty(t, sm.created)
+	})
+}
+
+// statusManager is a state.Manager which only stores a run's status.
+type statusManager struct {
+	state.Manager
+	status enums.RunStatus
+}
+
+func (m *statusManager) Metadata(ctx context.Context, runID ulid.ULID) (*state.Metadata, error) {
+	return &state.Metadata{Status: m.status}, nil
+}
+
+func (m *statusManager) SetStatus(ctx context.Context, id state.Identifier, status enums.RunStatus) error {
+	m.status = status
+	return nil
+}
+
+func TestWaitingStatus(t *testing.T) {
+	ctx := context.Background()
+	id := state.Identifier{RunID: ulid.MustNew(ulid.Now(), rand.Reader)}
+	sm := &statusManager{status: enums.RunStatusRunning}
+	e := &executor{sm: sm}
+
+	e.setWaitingStatus(ctx, id, enums.RunStatusSleeping)
+	require.Equal(t, enums.RunStatusSleeping, sm.status)
+
+	// Running the next step clears the waiting status.
+	r := &runValidator{e: e, md: state.Metadata{Identifier: id, Status: sm.status}}
+	require.NoError(t, r.updateScheduledStatus(ctx))
+	require.Equal(t, enums.RunStatusRunning, sm.status)
+
+	// Waiting statuses never overwrite an ended status.
+	sm.status = enums.RunStatusCancelled
+	e.setWaitingStatus(ctx, id, enums.RunStatusWaitingForEvent)
+	require.Equal(t, enums.RunStatusCancelled, sm.status)
+}
@@ -0,0 +1,67 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrInvokeFunctionNotFound is returned when an invoked "{app}/{function}"
+	// ID matches no functions.
+	ErrInvokeFunctionNotFound = fmt.Errorf("invoked function not found")
+	// ErrInvokeFunctionAmbiguous is returned when an invoked "{app}/{function}"
+	// ID matches more than one function.
+	ErrInvokeFunctionAmbiguous = fmt.Errorf("invoked function is ambiguous")
+)
+
+// resolveInvokeFunctionID resolves the ID of an invoked function given as
+// "{appSlug}/{fnSlug}" to the function's fully-qualified ID, so that invokes
+// don't depend on the app's ID prefix, which may differ between environments.
+//
+// Other IDs are returned as-is, as are all IDs if no function reader is set.
+func (e *executor) resolveInvokeFunctionID(ctx context.Context, workspaceID uuid.UUID, id string) (string, error) {
+	appSlug, fnSlug, ok := strings.Cut(id, "/")
+	if !ok || e.fnReader == nil {
+		return id, nil
+	}
+	if appSlug == "" || fnSlug == "" {
+		return "", fmt.Errorf("%w: %s", ErrInvokeFunctionNotFound, id)
+	}
+
+	fns, err := e.fnReader.GetFunctionsByAppExternalID(ctx, workspaceID, appSlug)
+	if err != nil {
+		return "", fmt.Errorf("error loading functions for app '%s': %w", appSlug, err)
+	}
+
+	// Function slugs are prefixed with the app's ID by SDKs, though may be
+	// specified in full.
+	matches := map[string]struct{}{}
+	for _, fn := range fns {
+		if fn == nil || fn.ArchivedAt != nil {
+			continue
+		}
+		if fn.Slug == fnSlug || fn.Slug == appSlug+"-"+fnSlug {
+			matches[fn.Slug] = struct{}{}
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("%w: %s", ErrInvokeFunctionNotFound, id)
+	case 1:
+		for slug := range matches {
+			return slug, nil
+		}
+	}
+
+	slugs := make([]string, 0, len(matches))
+	for slug := range matches {
+		slugs = append(slugs, slug)
+	}
+	sort.Strings(slugs)
+	return "", fmt.Errorf("%w: %s matches %s", ErrInvokeFunctionAmbiguous, id, strings.Join(slugs, ", "))
+}
@@ -0,0 +1,112 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/inngest/inngest/pkg/cqrs"
+	"github.com/inngest/inngest/pkg/enums"
+	"github.com/inngest/inngest/pkg/event"
+	"github.com/inngest/inngest/pkg/execution"
+	"github.com/inngest/inngest/pkg/execution/queue"
+	"github.com/inngest/inngest/pkg/execution/state"
+	"github.com/stretchr/testify/require"
+)
+
+type appFunctionReader struct {
+	cqrs.FunctionReader
+	fns map[string][]*cqrs.Function
+	err error
+}
+
+func (r appFunctionReader) GetFunctionsByAppExternalID(ctx context.Context, workspaceID uuid.UUID, app string) ([]*cqrs.Function, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	return r.fns[app], nil
+}
+
+func TestResolveInvokeFunctionID(t *testing.T) {
+	ctx := context.Background()
+	archived := time.Now()
+	e := &executor{
+		fnReader: appFunctionReader{fns: map[string][]*cqrs.Function{
+			"billing": {
+				{Slug: "billing-charge"},
+				{Slug: "billing-refund"},
+				{Slug: "billing-old", ArchivedAt: &archived},
+			},
+			"dupes": {
+				{Slug: "dupes-sync"},
+				{Slug: "sync"},
+			},
+		}},
+	}
+
+	t.Run("fully-qualified IDs are unchanged", func(t *testing.T) {
+		id, err := e.resolveInvokeFunctionID(ctx, uuid.New(), "billing-charge")
+		require.NoError(t, err)
+		require.Equal(t, "billing-charge", id)
+	})
+
+	t.Run("resolves app and function slugs", func(t *testing.T) {
+		id, err := e.resolveInvokeFunctionID(ctx, uuid.New(), "billing/refund")
+		require.NoError(t, err)
+		require.Equal(t, "billing-refund", id)
+
+		id, err = e.resolveInvokeFunctionID(ctx, uuid.New(), "billing/billing-charge")
+		require.NoError(t, err)
+		require.Equal(t, "billing-charge", id)
+	})
+
+	t.Run("missing functions", func(t *testing.T) {
+		_, err := e.resolveInvokeFunctionID(ctx, uuid.New(), "billing/missing")
+		require.ErrorIs(t, err, ErrInvokeFunctionNotFound)
+
+		_, err = e.resolveInvokeFunctionID(ctx, uuid.New(), "billing/old")
+		require.ErrorIs(t, err, ErrInvokeFunctionNotFound)
+
+		_, err = e.resolveInvokeFunctionID(ctx, uuid.New(), "other/charge")
+		require.ErrorIs(t, err, ErrInvokeFunctionNotFound)
+	})
+
+	t.Run("ambiguous functions", func(t *testing.T) {
+		_, err := e.resolveInvokeFunctionID(ctx, uuid.New(), "dupes/sync")
+		require.ErrorIs(t, err, ErrInvokeFunctionAmbiguous)
+		require.Contains(t, err.Error(), "dupes-sync, sync")
+	})
+
+	t.Run("without a reader", func(t *testing.T) {
+		id, err := (&executor{}).resolveInvokeFunctionID(ctx, uuid.New(), "billing/refund")
+		require.NoError(t, err)
+		require.Equal(t, "billing/refund", id)
+	})
+}
+
+func TestInvokeResolveErrors(t *testing.T) {
+	ctx := context.Background()
+	loadErr := fmt.Errorf("database is locked")
+	e := &executor{
+		fnReader: appFunctionReader{err: loadErr},
+		handleSendingEvent: func(ctx context.Context, evt event.Event, item queue.Item) error {
+			require.Fail(t, "the invoke event should not be sent")
+			return nil
+		},
+		invokeNotFoundHandler: func(ctx context.Context, opts execution.InvokeNotFoundHandlerOpts, evts []event.Event) error {
+			require.Fail(t, "errors loading functions should not fail the invoke")
+			return nil
+		},
+	}
+	gen := state.GeneratorOpcode{
+		Op:   enums.OpcodeInvokeFunction,
+		ID:   "invoke",
+		Opts: map[string]any{"function_id": "billing/refund"},
+	}
+
+	// Errors loading functions are returned, so that the queue retries the step.
+	err := e.handleGeneratorInvokeFunction(ctx, gen, queue.Item{}, queue.PayloadEdge{})
+	require.ErrorIs(t, err, loadErr)
+}
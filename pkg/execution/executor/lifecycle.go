@@ -0,0 +1,126 @@
+package executor
+
+import (
+	"context"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/inngest/inngest/pkg/execution"
+	"github.com/inngest/inngest/pkg/execution/batch"
+	"github.com/inngest/inngest/pkg/execution/queue"
+	"github.com/inngest/inngest/pkg/execution/state"
+	"github.com/inngest/inngest/pkg/inngest"
+	"github.com/inngest/inngest/pkg/inngest/log"
+	"github.com/oklog/ulid/v2"
+)
+
+// listeners returns the executor's lifecycle listeners, tracked so that
+// CloseLifecycleListeners can wait for in-progress calls.  Each returned
+// listener must have exactly one of its methods called.
+func (e *executor) listeners() []execution.OpcodeLifecycleListener {
+	e.lifecycleWG.Add(len(e.lifecycles))
+	tracked := make([]execution.OpcodeLifecycleListener, len(e.lifecycles))
+	for n, l := range e.lifecycles {
+		tracked[n] = trackedListener{l: execution.UpgradeLifecycleListener(l), wg: e.lifecycleWG}
+	}
+	return tracked
+}
+
+// trackedListener marks the waitgroup as done once a lifecycle method returns.
+// Listeners are typically invoked in their own goroutine, so trackedListener
+// also recovers from listener panics, which would otherwise crash the service.
+type trackedListener struct {
+	l  execution.OpcodeLifecycleListener
+	wg *sync.WaitGroup
+}
+
+func (t trackedListener) done(ctx context.Context) {
+	defer t.wg.Done()
+	if r := recover(); r != nil {
+		log.From(ctx).Error().
+			Interface("panic", r).
+			Str("stack", string(debug.Stack())).
+			Msg("recovered from lifecycle listener panic")
+	}
+}
+
+func (t trackedListener) OnFunctionScheduled(ctx context.Context, id state.Identifier, item queue.Item, s state.State) {
+	defer t.done(ctx)
+	t.l.OnFunctionScheduled(ctx, id, item, s)
+}
+
+func (t trackedListener) OnFunctionSkipped(ctx context.Context, id state.Identifier, s execution.SkipState) {
+	defer t.done(ctx)
+	t.l.OnFunctionSkipped(ctx, id, s)
+}
+
+func (t trackedListener) OnFunctionStarted(ctx context.Context, id state.Identifier, item queue.Item, s state.State) {
+	defer t.done(ctx)
+	t.l.OnFunctionStarted(ctx, id, item, s)
+}
+
+func (t trackedListener) OnFunctionFinished(ctx context.Context, id state.Identifier, item queue.Item, resp state.DriverResponse, s state.State) {
+	defer t.done(ctx)
+	t.l.OnFunctionFinished(ctx, id, item, resp, s)
+}
+
+func (t trackedListener) OnFunctionCancelled(ctx context.Context, id state.Identifier, r execution.CancelRequest, s state.State) {
+	defer t.done(ctx)
+	t.l.OnFunctionCancelled(ctx, id, r, s)
+}
+
+func (t trackedListener) OnStepScheduled(ctx context.Context, id state.Identifier, item queue.Item, name *string) {
+	defer t.done(ctx)
+	t.l.OnStepScheduled(ctx, id, item, name)
+}
+
+func (t trackedListener) OnStepStarted(ctx context.Context, id state.Identifier, item queue.Item, edge inngest.Edge, step inngest.Step, s state.State) {
+	defer t.done(ctx)
+	t.l.OnStepStarted(ctx, id, item, edge, step, s)
+}
+
+func (t trackedListener) OnStepFinished(ctx context.Context, id state.Identifier, item queue.Item, edge inngest.Edge, step inngest.Step, resp state.DriverResponse) {
+	defer t.done(ctx)
+	t.l.OnStepFinished(ctx, id, item, edge, step, resp)
+}
+
+func (t trackedListener) OnWaitForEvent(ctx context.Context, id state.Identifier, item queue.Item, op state.GeneratorOpcode) {
+	defer t.done(ctx)
+	t.l.OnWaitForEvent(ctx, id, item, op)
+}
+
+func (t trackedListener) OnWaitForEventResumed(ctx context.Context, id state.Identifier, r execution.ResumeRequest, groupID string) {
+	defer t.done(ctx)
+	t.l.OnWaitForEventResumed(ctx, id, r, groupID)
+}
+
+func (t trackedListener) OnInvokeFunction(ctx context.Context, id state.Identifier, item queue.Item, op state.GeneratorOpcode, eventID ulid.ULID, correlationID string) {
+	defer t.done(ctx)
+	t.l.OnInvokeFunction(ctx, id, item, op, eventID, correlationID)
+}
+
+func (t trackedListener) OnInvokeFunctionResumed(ctx context.Context, id state.Identifier, r execution.ResumeRequest, groupID string) {
+	defer t.done(ctx)
+	t.l.OnInvokeFunctionResumed(ctx, id, r, groupID)
+}
+
+func (t trackedListener) OnSleep(ctx context.Context, id state.Identifier, item queue.Item, op state.GeneratorOpcode, until time.Time) {
+	defer t.done(ctx)
+	t.l.OnSleep(ctx, id, item, op, until)
+}
+
+func (t trackedListener) OnGatewayRequestFinished(ctx context.Context, id state.Identifier, item queue.Item, op state.GeneratorOpcode, res execution.GatewayResult) {
+	defer t.done(ctx)
+	t.l.OnGatewayRequestFinished(ctx, id, item, op, res)
+}
+
+func (t trackedListener) OnBatchAppended(ctx context.Context, fn inngest.Function, bi batch.BatchItem, res batch.BatchAppendResult) {
+	defer t.done(ctx)
+	t.l.OnBatchAppended(ctx, fn, bi, res)
+}
+
+// Close isn't tracked, as it's only called when the executor is shutting down.
+func (t trackedListener) Close() error {
+	return t.l.Close()
+}
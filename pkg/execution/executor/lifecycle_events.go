@@ -0,0 +1,134 @@
+package executor
+
+import (
+	"context"
+	"crypto/rand"
+	"time"
+
+	"github.com/inngest/inngest/pkg/enums"
+	"github.com/inngest/inngest/pkg/event"
+	"github.com/inngest/inngest/pkg/execution"
+	"github.com/inngest/inngest/pkg/execution/queue"
+	"github.com/inngest/inngest/pkg/execution/state"
+	"github.com/inngest/inngest/pkg/inngest"
+	"github.com/inngest/inngest/pkg/logger"
+	"github.com/oklog/ulid/v2"
+)
+
+// LifecycleEventHandler publishes events sent as the given run changes state.
+type LifecycleEventHandler func(context.Context, state.Identifier, []event.Event) error
+
+// NewLifecycleEventListener returns a lifecycle listener which sends events as
+// runs change state, so that other functions can be triggered by them:
+//
+//   - `inngest/run.started` when a run starts
+//   - `inngest/run.cancelled` when a run is cancelled
+//   - `inngest/step.failed` when a step permanently fails
+//
+// Runs triggered by these events, or by `inngest/function.finished` and
+// `inngest/function.failed`, never send lifecycle events, preventing loops.
+func NewLifecycleEventListener(sl state.StateLoader, h LifecycleEventHandler) execution.LifecycleListener {
+	return lifecycleEventListener{sl: sl, h: h}
+}
+
+type lifecycleEventListener struct {
+	execution.NoopLifecyceListener
+
+	sl state.StateLoader
+	h  LifecycleEventHandler
+}
+
+func (l lifecycleEventListener) OnFunctionStarted(
+	ctx context.Context,
+	id state.Identifier,
+	item queue.Item,
+	s state.State,
+) {
+	l.send(ctx, id, s, event.RunStartedName, runEventData(id, s))
+}
+
+func (l lifecycleEventListener) OnFunctionCancelled(
+	ctx context.Context,
+	id state.Identifier,
+	r execution.CancelRequest,
+	s state.State,
+) {
+	data := runEventData(id, s)
+	cancellation := map[string]any{}
+	if r.EventID != nil {
+		cancellation["event_id"] = r.EventID.String()
+	}
+	if r.Expression != nil {
+		cancellation["expression"] = *r.Expression
+	}
+	if r.UserID != nil {
+		cancellation["user_id"] = r.UserID.String()
+	}
+	if r.CancellationID != nil {
+		cancellation["cancellation_id"] = r.CancellationID.String()
+	}
+	data["cancellation"] = cancellation
+	l.send(ctx, id, s, event.RunCancelledName, data)
+}
+
+func (l lifecycleEventListener) OnStepFinished(
+	ctx context.Context,
+	id state.Identifier,
+	item queue.Item,
+	edge inngest.Edge,
+	step inngest.Step,
+	resp state.DriverResponse,
+) {
+	op := resp.TraceVisibleStepExecution()
+	if op == nil || op.Op != enums.OpcodeStepError || op.Error == nil {
+		return
+	}
+	if !op.Error.NoRetry && queue.ShouldRetry(nil, item.Attempt, item.GetMaxAttempts()) {
+		// The step will be retried.
+		return
+	}
+
+	s, err := l.sl.Load(ctx, id.RunID)
+	if err != nil {
+		logger.From(ctx).Error().Err(err).Str("run_id", id.RunID.String()).Msg("error loading run for step failed event")
+		return
+	}
+
+	data := runEventData(id, s)
+	data["step_id"] = op.ID
+	data["step_name"] = op.UserDefinedName()
+	data["attempt"] = item.Attempt
+	data["error"] = op.Error
+	l.send(ctx, id, s, event.StepFailedName, data)
+}
+
+// send publishes a lifecycle event, unless the run was triggered by a lifecycle
+// event.
+func (l lifecycleEventListener) send(ctx context.Context, id state.Identifier, s state.State, name string, data map[string]any) {
+	for _, evt := range s.Events() {
+		if n, ok := evt["name"].(string); ok && event.IsRunLifecycleEvent(n) {
+			return
+		}
+	}
+
+	now := time.Now()
+	evt := event.Event{
+		ID:        ulid.MustNew(uint64(now.UnixMilli()), rand.Reader).String(),
+		Name:      name,
+		Timestamp: now.UnixMilli(),
+		Data:      data,
+	}
+	if err := l.h(ctx, id, []event.Event{evt}); err != nil {
+		logger.From(ctx).Error().Err(err).Str("event", name).Msg("error sending lifecycle event")
+	}
+}
+
+// runEventData returns the data included in every lifecycle event.
+func runEventData(id state.Identifier, s state.State) map[string]any {
+	return map[string]any{
+		"function_id": s.Function().Slug,
+		"run_id":      id.RunID.String(),
+		"event":       s.Event(),
+		"events":      s.Events(),
+	}
+}
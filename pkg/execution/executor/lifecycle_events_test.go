@@ -0,0 +1,97 @@
+package executor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/inngest/inngest/pkg/enums"
+	"github.com/inngest/inngest/pkg/event"
+	"github.com/inngest/inngest/pkg/execution"
+	"github.com/inngest/inngest/pkg/execution/queue"
+	"github.com/inngest/inngest/pkg/execution/state"
+	"github.com/inngest/inngest/pkg/inngest"
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/require"
+)
+
+type staticStateLoader struct {
+	state.StateLoader
+	s state.State
+}
+
+func (l staticStateLoader) Load(ctx context.Context, runID ulid.ULID) (state.State, error) {
+	return l.s, nil
+}
+
+func TestLifecycleEventListener(t *testing.T) {
+	ctx := context.Background()
+	id := state.Identifier{RunID: ulid.Make()}
+	newState := func(name string) state.State {
+		return state.NewStateInstance(
+			inngest.Function{Slug: "fn"},
+			id,
+			state.Metadata{},
+			[]map[string]any{{"name": name}},
+			map[string]any{},
+			map[string]error{},
+			[]string{},
+		)
+	}
+
+	var sent []event.Event
+	record := func(ctx context.Context, id state.Identifier, evts []event.Event) error {
+		sent = append(sent, evts...)
+		return nil
+	}
+
+	t.Run("It sends run events", func(t *testing.T) {
+		sent = nil
+		s := newState("test/event")
+		l := NewLifecycleEventListener(staticStateLoader{s: s}, record)
+
+		l.OnFunctionStarted(ctx, id, queue.Item{}, s)
+		expr := "true"
+		l.OnFunctionCancelled(ctx, id, execution.CancelRequest{Expression: &expr}, s)
+
+		require.Len(t, sent, 2)
+		require.Equal(t, event.RunStartedName, sent[0].Name)
+		require.Equal(t, "fn", sent[0].Data["function_id"])
+		require.Equal(t, id.RunID.String(), sent[0].Data["run_id"])
+		require.Equal(t, event.RunCancelledName, sent[1].Name)
+		require.Equal(t, map[string]any{"expression": "true"}, sent[1].Data["cancellation"])
+	})
+
+	t.Run("It sends step failed events once steps permanently fail", func(t *testing.T) {
+		sent = nil
+		s := newState("test/event")
+		l := NewLifecycleEventListener(staticStateLoader{s: s}, record)
+
+		max := 2
+		resp := state.DriverResponse{
+			Generator: []*state.GeneratorOpcode{{
+				Op:    enums.OpcodeStepError,
+				ID:    "step-id",
+				Name:  "step",
+				Error: &state.UserError{Name: "Error", Message: "oh no"},
+			}},
+		}
+		l.OnStepFinished(ctx, id, queue.Item{Attempt: 0, MaxAttempts: &max}, inngest.Edge{}, inngest.Step{}, resp)
+		require.Empty(t, sent)
+
+		l.OnStepFinished(ctx, id, queue.Item{Attempt: 1, MaxAttempts: &max}, inngest.Edge{}, inngest.Step{}, resp)
+		require.Len(t, sent, 1)
+		require.Equal(t, event.StepFailedName, sent[0].Name)
+		require.Equal(t, "step-id", sent[0].Data["step_id"])
+		require.Equal(t, "step", sent[0].Data["step_name"])
+	})
+
+	t.Run("Runs triggered by lifecycle events don't send events", func(t *testing.T) {
+		sent = nil
+		for _, name := range []string{event.RunStartedName, event.FnFinishedName} {
+			s := newState(name)
+			l := NewLifecycleEventListener(staticStateLoader{s: s}, record)
+			l.OnFunctionStarted(ctx, id, queue.Item{}, s)
+		}
+		require.Empty(t, sent)
+	})
+}
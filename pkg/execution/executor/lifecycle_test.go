@@ -0,0 +1,95 @@
+package executor
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/inngest/inngest/pkg/enums"
+	"github.com/inngest/inngest/pkg/execution"
+	"github.com/inngest/inngest/pkg/execution/batch"
+	"github.com/inngest/inngest/pkg/inngest"
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/require"
+)
+
+type appendBatcher struct {
+	batch.BatchManager
+	result batch.BatchAppendResult
+}
+
+func (a appendBatcher) Append(ctx context.Context, bi batch.BatchItem, fn inngest.Function) (*batch.BatchAppendResult, error) {
+	return &a.result, nil
+}
+
+type batchListener struct {
+	execution.NoopLifecyceListener
+	appended chan batch.BatchAppendResult
+}
+
+func (b batchListener) OnBatchAppended(ctx context.Context, fn inngest.Function, bi batch.BatchItem, res batch.BatchAppendResult) {
+	b.appended <- res
+}
+
+// legacyListener only implements execution.LifecycleListener.
+type legacyListener struct {
+	execution.LifecycleListener
+}
+
+func TestLifecycleListenerBatchAppended(t *testing.T) {
+	l := batchListener{appended: make(chan batch.BatchAppendResult, 1)}
+	result := batch.BatchAppendResult{Status: enums.BatchAppend, BatchID: ulid.Make().String()}
+	e := &executor{
+		batcher: appendBatcher{result: result},
+		lifecycles: []execution.LifecycleListener{
+			l,
+			legacyListener{execution.NoopLifecyceListener{}},
+		},
+		lifecycleWG: &sync.WaitGroup{},
+	}
+
+	fn := inngest.Function{EventBatch: &inngest.EventBatchConfig{MaxSize: 10, Timeout: "10s"}}
+	err := e.AppendAndScheduleBatch(context.Background(), fn, batch.BatchItem{EventID: ulid.Make()})
+	require.NoError(t, err)
+	require.Equal(t, result, <-l.appended)
+	require.NoError(t, e.CloseLifecycleListeners(context.Background()))
+}
+
+func TestUpgradeLifecycleListener(t *testing.T) {
+	l := batchListener{}
+	require.Equal(t, l, execution.UpgradeLifecycleListener(l))
+
+	legacy := legacyListener{execution.NoopLifecyceListener{}}
+	upgraded := execution.UpgradeLifecycleListener(legacy)
+	require.NotNil(t, upgraded)
+	// Opcode events are ignored for legacy listeners.
+	upgraded.OnBatchAppended(context.Background(), inngest.Function{}, batch.BatchItem{}, batch.BatchAppendResult{})
+}
+
+type scheduleBatcher struct {
+	appendBatcher
+	scheduled *batch.ScheduleBatchOpts
+}
+
+func (s scheduleBatcher) ScheduleExecution(ctx context.Context, opts batch.ScheduleBatchOpts) error {
+	*s.scheduled = opts
+	return nil
+}
+
+func TestScheduleBatchTrigger(t *testing.T) {
+	scheduled := &batch.ScheduleBatchOpts{}
+	result := batch.BatchAppendResult{Status: enums.BatchNew, BatchID: ulid.Make().String()}
+	e := &executor{
+		batcher:     scheduleBatcher{appendBatcher: appendBatcher{result: result}, scheduled: scheduled},
+		lifecycleWG: &sync.WaitGroup{},
+	}
+
+	// The batch is scheduled with the index of the trigger which configured
+	// batching, so that the trigger's config applies once the batch starts.
+	trigger := 1
+	fn := inngest.Function{EventBatch: &inngest.EventBatchConfig{MaxSize: 10, Timeout: "10s"}}
+	err := e.AppendAndScheduleBatch(context.Background(), fn, batch.BatchItem{EventID: ulid.Make(), Trigger: &trigger})
+	require.NoError(t, err)
+	require.NotNil(t, scheduled.Trigger)
+	require.Equal(t, 1, *scheduled.Trigger)
+}
@@ -0,0 +1,115 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/inngest/inngest/pkg/enums"
+	"github.com/inngest/inngest/pkg/execution/queue"
+	"github.com/inngest/inngest/pkg/execution/state"
+	"github.com/inngest/inngest/pkg/expressions"
+	"github.com/inngest/inngest/pkg/inngest"
+	"github.com/inngest/inngest/pkg/logger"
+	"github.com/inngest/inngest/pkg/telemetry"
+	"github.com/oklog/ulid/v2"
+)
+
+const pkgName = "executor.execution.inngest"
+
+// recordStepMetrics records the duration and output size of a step request,
+// and the number of times the step was retried once it finishes.
+func recordStepMetrics(ctx context.Context, slug string, item queue.Item, resp *state.DriverResponse) {
+	status := "completed"
+	if resp.Err != nil {
+		status = "errored"
+	}
+	tags := map[string]any{"function": slug, "status": status}
+
+	telemetry.HistogramStepDuration(ctx, resp.Duration.Milliseconds(), telemetry.HistogramOpt{
+		PkgName: pkgName,
+		Tags:    tags,
+	})
+	telemetry.HistogramStepOutputSize(ctx, int64(resp.OutputSize), telemetry.HistogramOpt{
+		PkgName: pkgName,
+		Tags:    map[string]any{"function": slug},
+	})
+
+	// Only record retries once the step won't be retried again.
+	if resp.Retryable() && item.Attempt+1 < item.GetMaxAttempts() {
+		return
+	}
+	telemetry.HistogramStepRetries(ctx, int64(item.Attempt), telemetry.HistogramOpt{
+		PkgName: pkgName,
+		Tags:    tags,
+	})
+}
+
+// recordRunMetrics records the duration of a finished run.
+func recordRunMetrics(ctx context.Context, id state.Identifier, s state.State, status enums.RunStatus) {
+	start := s.Metadata().StartedAt
+	if start.IsZero() {
+		start = ulid.Time(id.RunID.Time())
+	}
+	telemetry.HistogramRunDuration(ctx, time.Since(start).Milliseconds(), telemetry.HistogramOpt{
+		PkgName: pkgName,
+		Tags: map[string]any{
+			"function": s.Function().GetSlug(),
+			"status":   status.String(),
+		},
+	})
+}
+
+// recordConcurrencyKeyCardinality counts the distinct custom concurrency keys
+// used by the function, recording the count for each custom limit.  Functions
+// whose limits exceed the executor's cardinality limit, eg. as their keys use
+// unique IDs, are logged and counted so that they can be identified.
+func (e *executor) recordConcurrencyKeyCardinality(ctx context.Context, fn inngest.Function, keys []state.CustomConcurrency) {
+	counter, ok := e.queue.(queue.ConcurrencyKeyCounter)
+	if !ok || len(keys) == 0 || fn.Concurrency == nil {
+		return
+	}
+
+	counts, err := counter.CountConcurrencyKeys(ctx, fn.ID, keys)
+	if err != nil {
+		logger.StdlibLogger(ctx).Warn("error counting concurrency keys", "error", err, "function_id", fn.ID)
+		return
+	}
+
+	for _, limit := range fn.Concurrency.Limits {
+		count, ok := counts[limit.Hash]
+		if !ok || !limit.IsCustomLimit() || limit.Key == nil {
+			continue
+		}
+		tags := map[string]any{"function": fn.GetSlug(), "scope": limit.Scope.String()}
+		telemetry.HistogramConcurrencyKeyCardinality(ctx, count, telemetry.HistogramOpt{
+			PkgName: pkgName,
+			Tags:    tags,
+		})
+
+		if e.concurrencyKeyCardinality <= 0 || count <= e.concurrencyKeyCardinality {
+			continue
+		}
+		logger.StdlibLogger(ctx).Warn(
+			"function exceeds concurrency key cardinality limit",
+			"function_id", fn.ID,
+			"function", fn.GetSlug(),
+			"key", *limit.Key,
+			"cardinality", count,
+			"limit", e.concurrencyKeyCardinality,
+		)
+		telemetry.IncrConcurrencyKeyCardinalityExceededCounter(ctx, telemetry.CounterOpt{
+			PkgName: pkgName,
+			Tags:    tags,
+		})
+	}
+}
+
+// pauseExprSkipReason returns the metric tag recorded when a pause's expression
+// exceeds its evaluation budget.
+func pauseExprSkipReason(err error) string {
+	if errors.Is(err, expressions.ErrCostLimitExceeded) {
+		return "cost"
+	}
+	return "timeout"
+}
@@ -0,0 +1,59 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/inngest/inngest/pkg/execution"
+	"github.com/inngest/inngest/pkg/execution/driver"
+	"github.com/inngest/inngest/pkg/execution/queue"
+	"github.com/inngest/inngest/pkg/execution/state"
+	"github.com/inngest/inngest/pkg/inngest"
+	"github.com/stretchr/testify/require"
+)
+
+type panicDriver struct{}
+
+func (panicDriver) RuntimeType() string { return "http" }
+
+func (panicDriver) Execute(ctx context.Context, s state.State, item queue.Item, edge inngest.Edge, step inngest.Step, idx, attempt int) (*state.DriverResponse, error) {
+	panic("boom")
+}
+
+type panicListener struct {
+	execution.NoopLifecyceListener
+}
+
+func (panicListener) OnStepScheduled(ctx context.Context, id state.Identifier, item queue.Item, name *string) {
+	panic("boom")
+}
+
+func TestExecuteDriverPanic(t *testing.T) {
+	e := &executor{
+		runtimeDrivers: map[string]driver.Driver{"http": panicDriver{}},
+	}
+
+	step := &inngest.Step{ID: "step", URI: "http://localhost/api/inngest"}
+	retries := 3
+	resp, err := e.executeDriverForStep(context.Background(), state.Identifier{}, queue.Item{MaxAttempts: &retries}, step, nil, inngest.Edge{}, 0)
+	require.True(t, errors.Is(err, ErrDriverPanic))
+	require.NotNil(t, resp)
+	require.NotNil(t, resp.Err)
+	require.Contains(t, *resp.Err, "boom")
+	require.False(t, resp.NoRetry)
+	require.Equal(t, "step", resp.Step.ID)
+}
+
+func TestLifecycleListenerPanic(t *testing.T) {
+	e := &executor{
+		lifecycles:  []execution.LifecycleListener{panicListener{}},
+		lifecycleWG: &sync.WaitGroup{},
+	}
+
+	for _, l := range e.listeners() {
+		l.OnStepScheduled(context.Background(), state.Identifier{}, queue.Item{}, nil)
+	}
+	require.NoError(t, e.CloseLifecycleListeners(context.Background()))
+}
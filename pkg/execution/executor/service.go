@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/inngest/inngest/pkg/config"
@@ -27,6 +28,10 @@ import (
 	"golang.org/x/sync/errgroup"
 )
 
+// drainGracePeriod is the additional time allowed for releasing jobs and
+// flushing lifecycles after the drain timeout.
+const drainGracePeriod = 5 * time.Second
+
 type Opt func(s *svc)
 
 func WithExecutionManager(l cqrs.Manager) func(s *svc) {
@@ -130,6 +135,11 @@ func (s *svc) getFinishHandler(ctx context.Context) (func(context.Context, state
 	if err != nil {
 		return nil, fmt.Errorf("failed to create publisher: %w", err)
 	}
+	if fe := s.config.Execution.FinishEvents; fe.FlushSize > 1 {
+		// Publish events from many runs together, instead of publishing each
+		// event individually.
+		pb = pubsub.NewBufferedPublisher(pb, fe.FlushSize, fe.FlushIntervalDuration())
+	}
 
 	topicName := s.config.EventStream.Service.Concrete.TopicName()
 
@@ -139,7 +149,7 @@ func (s *svc) getFinishHandler(ctx context.Context) (func(context.Context, state
 		for _, e := range events {
 			evt := e
 			eg.Go(func() error {
-				trackedEvent := event.NewOSSTrackedEvent(evt)
+				trackedEvent := event.NewOSSTrackedEventForWorkspace(evt, st.Identifier().WorkspaceID)
 				byt, err := json.Marshal(trackedEvent)
 				if err != nil {
 					return fmt.Errorf("error marshalling event: %w", err)
@@ -191,10 +201,23 @@ func (s *svc) Run(ctx context.Context) error {
 	})
 }
 
+// RunTimeout allows the queue to drain in-progress steps before Run is stopped.
+func (s *svc) RunTimeout() time.Duration {
+	return s.config.Execution.DrainTimeoutDuration() + drainGracePeriod
+}
+
 func (s *svc) Stop(ctx context.Context) error {
 	// Wait for all in-flight queue runs to finish
 	s.wg.Wait()
-	return nil
+
+	// Flush lifecycle listeners and spans from the runs that finished, so that
+	// history and traces aren't lost on shutdown.
+	ctx, cancel := context.WithTimeout(ctx, drainGracePeriod)
+	defer cancel()
+	return errors.Join(
+		s.exec.CloseLifecycleListeners(ctx),
+		telemetry.FlushUserTracer(ctx),
+	)
 }
 
 func (s *svc) handleQueueItem(ctx context.Context, item queue.Item) error {
@@ -282,7 +305,7 @@ func (s *svc) handlePauseTimeout(ctx context.Context, item queue.Item) error {
 		return nil
 	}
 
-	r := execution.ResumeRequest{}
+	r := execution.ResumeRequest{Timeout: true}
 
 	// If the pause timeout is for an invocation, store an error to cause the
 	// step to fail.
@@ -317,8 +340,13 @@ func (s *svc) handleScheduledBatch(ctx context.Context, item queue.Item) error {
 	if err != nil {
 		return err
 	}
+	// Batching may be configured by the trigger which matched the batched event.
+	resolved := *fn
+	if opts.Trigger != nil {
+		resolved = fn.ForTriggerIndex(*opts.Trigger)
+	}
 
-	if err := s.exec.RetrieveAndScheduleBatch(ctx, *fn, batch.ScheduleBatchPayload{
+	if err := s.exec.RetrieveAndScheduleBatch(ctx, resolved, batch.ScheduleBatchPayload{
 		BatchID:         batchID,
 		AccountID:       item.Identifier.AccountID,
 		WorkspaceID:     item.Identifier.WorkspaceID,
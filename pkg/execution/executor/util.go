@@ -3,6 +3,7 @@ package executor
 import (
 	"context"
 	"crypto/rand"
+	"encoding/json"
 	"time"
 
 	"github.com/inngest/inngest/pkg/consts"
@@ -29,6 +30,59 @@ func GetContextMetadata(ctx context.Context) *state.Metadata {
 	return val
 }
 
+var metadataUpdateCtxKey = metadataUpdateCtxType{}
+
+type metadataUpdateCtxType struct{}
+
+// withMetadataUpdate stores a metadata update which is saved along with the next
+// step response, rather than being saved separately.
+func withMetadataUpdate(ctx context.Context, md state.MetadataUpdate) context.Context {
+	return context.WithValue(ctx, metadataUpdateCtxKey, &md)
+}
+
+// getMetadataUpdate returns the metadata update stored in context or nil if not present.
+func getMetadataUpdate(ctx context.Context) *state.MetadataUpdate {
+	val, _ := ctx.Value(metadataUpdateCtxKey).(*state.MetadataUpdate)
+	return val
+}
+
+var stateCtxKey = stateCtxType{}
+
+type stateCtxType struct{}
+
+// withContextState stores the run state loaded prior to executing a step within
+// the given context, so that handling the step's response doesn't reload state.
+func withContextState(ctx context.Context, s state.State) context.Context {
+	return context.WithValue(ctx, stateCtxKey, s)
+}
+
+// getContextState returns the run state stored in context or nil if not present.
+func getContextState(ctx context.Context) state.State {
+	val, _ := ctx.Value(stateCtxKey).(state.State)
+	return val
+}
+
+// stateWithResponse returns a copy of the given state with the given metadata
+// and, if stepID is non-empty, the step's output saved and pushed onto the
+// stack.  This is equivalent to reloading state after calling SaveResponse.
+func stateWithResponse(s state.State, md state.Metadata, stepID, marshalledOutput string) state.State {
+	actions := make(map[string]any, len(s.Actions())+1)
+	for k, v := range s.Actions() {
+		actions[k] = v
+	}
+	stack := append([]string{}, s.Stack()...)
+	if stepID != "" {
+		var data any
+		if err := json.Unmarshal([]byte(marshalledOutput), &data); err != nil {
+			data = marshalledOutput
+		}
+		actions[stepID] = data
+		stack = append(stack, stepID)
+	}
+
+	return state.NewStateInstance(s.Function(), s.Identifier(), md, s.Events(), actions, s.Errors(), stack)
+}
+
 // GetFunctionMetadata returns a function run's metadata.  This attempts to load metadata
 // from context first, to reduce state store reads, falling back to the state.Manager's Metadata()
 // method if the metadata does not exist in context.
@@ -5,6 +5,7 @@ import (
 
 	"github.com/inngest/inngest/pkg/enums"
 	"github.com/inngest/inngest/pkg/execution/state"
+	"github.com/inngest/inngest/pkg/inngest"
 	"github.com/stretchr/testify/require"
 )
 
@@ -143,3 +144,29 @@ func TestOpcodeGroupsAllWithEmptyInput(t *testing.T) {
 
 	require.EqualValues(t, expected, actual)
 }
+
+func TestStateWithResponse(t *testing.T) {
+	s := state.NewStateInstance(
+		inngest.Function{Slug: "fn"},
+		state.Identifier{},
+		state.Metadata{},
+		[]map[string]any{{"name": "test/event"}},
+		map[string]any{"step": "output"},
+		map[string]error{},
+		[]string{"step"},
+	)
+
+	md := s.Metadata()
+	md.Status = enums.RunStatusCompleted
+	next := stateWithResponse(s, md, "final", `{"ok":true}`)
+
+	require.Equal(t, enums.RunStatusCompleted, next.Metadata().Status)
+	require.Equal(t, []string{"step", "final"}, next.Stack())
+	require.Equal(t, map[string]any{"ok": true}, next.Actions()["final"])
+	require.Equal(t, "output", next.Actions()["step"])
+
+	// The original state is left unmodified.
+	require.Equal(t, []string{"step"}, s.Stack())
+	require.NotContains(t, s.Actions(), "final")
+	require.Equal(t, enums.RunStatusRunning, s.Metadata().Status)
+}
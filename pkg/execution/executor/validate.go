@@ -64,23 +64,50 @@ func (r *runValidator) checkCancelled(ctx context.Context) error {
 	return nil
 }
 
-func (r *runValidator) checkStepLimit(ctx context.Context) error {
+// stepLimit returns the maximum number of steps the run may execute.  Functions
+// may override the executor's limit.
+func (e *executor) stepLimit(id state.Identifier, f *inngest.Function) int {
 	var limit int
-
-	if r.e.steplimit != nil {
-		limit = r.e.steplimit(r.item.Identifier)
+	if e.steplimit != nil {
+		limit = e.steplimit(id)
+	}
+	if f != nil && f.StepLimits != nil && f.StepLimits.Steps > 0 {
+		limit = f.StepLimits.Steps
 	}
-
 	if limit == 0 {
 		limit = consts.DefaultMaxStepLimit
 	}
+	return limit
+}
+
+// parallelStepLimit returns the maximum number of steps the run may plan at once.
+// Functions may override the executor's limit.
+func (e *executor) parallelStepLimit(id state.Identifier, f *inngest.Function) int {
+	var limit int
+	if e.parallelsteplimit != nil {
+		limit = e.parallelsteplimit(id)
+	}
+	if f != nil && f.StepLimits != nil && f.StepLimits.Parallel > 0 {
+		limit = f.StepLimits.Parallel
+	}
+	if limit <= 0 {
+		limit = consts.DefaultMaxParallelStepLimit
+	}
+	if limit > consts.AbsoluteMaxStepLimit {
+		limit = consts.AbsoluteMaxStepLimit
+	}
+	return limit
+}
+
+func (r *runValidator) checkStepLimit(ctx context.Context) error {
+	limit := r.e.stepLimit(r.item.Identifier, r.f)
 	if limit > consts.AbsoluteMaxStepLimit {
 		return fmt.Errorf("%d is greater than the absolute step limit of %d", limit, consts.AbsoluteMaxStepLimit)
 	}
 
 	if limit > 0 && len(r.s.Actions()) >= limit {
 		// Update this function's state to overflowed, if running.
-		if r.md.Status == enums.RunStatusRunning {
+		if r.md.Status == enums.RunStatusRunning || enums.RunStatusWaiting(r.md.Status) {
 			// XXX: Update error to failed, set error message
 			if err := r.e.sm.SetStatus(ctx, r.md.Identifier, enums.RunStatusFailed); err != nil {
 				return err
@@ -94,8 +121,14 @@ func (r *runValidator) checkStepLimit(ctx context.Context) error {
 			if err := r.e.runFinishHandler(ctx, r.md.Identifier, r.s, resp); err != nil {
 				logger.From(ctx).Error().Err(err).Msg("error running finish handler")
 			}
+			if err := r.e.runFailureHandler(ctx, r.md.Identifier, r.s, resp); err != nil {
+				logger.From(ctx).Error().Err(err).Msg("error running failure handler")
+			}
+			if err := r.e.runLimitedHandler(ctx, r.md.Identifier, r.s, limitSteps, limit, len(r.s.Actions())); err != nil {
+				logger.From(ctx).Error().Err(err).Msg("error running limited handler")
+			}
 
-			for _, e := range r.e.lifecycles {
+			for _, e := range r.e.listeners() {
 				go e.OnFunctionFinished(context.WithoutCancel(ctx), r.md.Identifier, r.item, resp, r.s)
 			}
 		}
@@ -141,12 +174,18 @@ func (r *runValidator) checkCancellation(ctx context.Context) error {
 	return nil
 }
 
-// updateScheduledStatus pushes a metadata mutation to update the function's scheduled status to
-// running.
+// updateScheduledStatus pushes a metadata mutation to update the function's scheduled or
+// waiting status to running.
 func (r *runValidator) updateScheduledStatus(ctx context.Context) error {
 	if r.md.Status == enums.RunStatusScheduled {
 		return r.e.sm.SetStatus(ctx, r.md.Identifier, enums.RunStatusRunning)
 	}
+	if enums.RunStatusWaiting(r.md.Status) {
+		// Waiting statuses may be set concurrently with a cancellation, so only
+		// transition from the status we loaded.
+		_, err := state.TransitionStatus(ctx, r.e.sm, r.md.Identifier, enums.RunStatusRunning, r.md.Status)
+		return err
+	}
 	return nil
 }
 
@@ -0,0 +1,254 @@
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/inngest/inngest/pkg/consts"
+)
+
+const (
+	// DefaultTimeout is the maximum time a request may take.
+	DefaultTimeout = 5 * time.Minute
+	// DefaultMaxResponseSize is the default maximum response body size, in bytes.
+	DefaultMaxResponseSize = consts.MaxBodySize
+)
+
+var (
+	// ErrHostNotAllowed is returned when requesting a host that isn't allowed.
+	ErrHostNotAllowed = errors.New("host is not allowed")
+	// ErrAddressNotAllowed is returned when a host resolves to a private,
+	// loopback or link-local address and private networks aren't allowed.
+	ErrAddressNotAllowed = errors.New("address is not allowed")
+	// ErrResponseTooLarge is returned when the response body exceeds the
+	// maximum response size.
+	ErrResponseTooLarge = errors.New("response exceeds the maximum size")
+)
+
+// Config configures the HTTP requests that the executor makes on behalf of
+// functions.
+type Config struct {
+	// AllowedHosts lists the hosts that functions may request, eg.
+	// "api.example.com".  A "*." prefix matches every subdomain, eg.
+	// "*.example.com".  If empty, every host is allowed.
+	AllowedHosts []string `json:"allowedHosts"`
+	// AllowPrivateNetworks allows requests to private, loopback and link-local
+	// addresses, eg. 10.0.0.1, 127.0.0.1 and 169.254.169.254.  These are
+	// denied by default, regardless of AllowedHosts, so that functions can't
+	// reach internal services or cloud metadata endpoints.
+	AllowPrivateNetworks bool `json:"allowPrivateNetworks"`
+	// MaxResponseSize is the maximum response body size in bytes, defaulting
+	// to DefaultMaxResponseSize.
+	MaxResponseSize int `json:"maxResponseSize"`
+	// Timeout is the number of seconds a request may take, defaulting to
+	// DefaultTimeout.
+	Timeout int `json:"timeout"`
+}
+
+// Request is an HTTP request sent from a function.
+type Request struct {
+	URL     string            `json:"url"`
+	Method  string            `json:"method"`
+	Headers map[string]string `json:"headers"`
+	Body    string            `json:"body"`
+}
+
+// Response is the result of a request, which is stored as the step's output.
+type Response struct {
+	StatusCode int               `json:"status"`
+	Headers    map[string]string `json:"headers"`
+	Body       string            `json:"body"`
+}
+
+// Gateway performs HTTP requests on behalf of functions.
+type Gateway interface {
+	Fetch(ctx context.Context, req Request) (*Response, error)
+}
+
+// New returns a Gateway which performs requests to the allowed hosts.
+func New(c Config) Gateway {
+	timeout := DefaultTimeout
+	if c.Timeout > 0 {
+		timeout = time.Duration(c.Timeout) * time.Second
+	}
+	size := DefaultMaxResponseSize
+	if c.MaxResponseSize > 0 {
+		size = c.MaxResponseSize
+	}
+	g := &gateway{
+		allowed: c.AllowedHosts,
+		private: c.AllowPrivateNetworks,
+		size:    size,
+	}
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		// Check the address of every connection once the host has been
+		// resolved, so that DNS can't be used to reach denied addresses.
+		Control: g.control,
+	}
+	g.client = &http.Client{
+		Timeout: timeout,
+		// Requests are never proxied, as the proxy would resolve hosts and
+		// connect to them without our address checks.
+		Transport: &http.Transport{
+			DialContext:           dialer.DialContext,
+			ForceAttemptHTTP2:     true,
+			MaxIdleConns:          100,
+			IdleConnTimeout:       90 * time.Second,
+			TLSHandshakeTimeout:   10 * time.Second,
+			ExpectContinueTimeout: 1 * time.Second,
+		},
+		// Check each redirect against the allowlist, so that allowed hosts
+		// can't be used to reach other hosts.
+		CheckRedirect: func(r *http.Request, via []*http.Request) error {
+			if len(via) >= 10 {
+				return fmt.Errorf("stopped after 10 redirects")
+			}
+			if !g.isAllowed(r.URL.Hostname()) {
+				return fmt.Errorf("%w: %s", ErrHostNotAllowed, r.URL.Hostname())
+			}
+			return nil
+		},
+	}
+	return g
+}
+
+type gateway struct {
+	allowed []string
+	private bool
+	size    int
+	client  *http.Client
+}
+
+func (g *gateway) Fetch(ctx context.Context, req Request) (*Response, error) {
+	u, err := url.Parse(req.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid url: %s", req.URL)
+	}
+	if !g.isAllowed(u.Hostname()) {
+		return nil, fmt.Errorf("%w: %s", ErrHostNotAllowed, u.Hostname())
+	}
+
+	method := strings.ToUpper(req.Method)
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	r, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader([]byte(req.Body)))
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+	for k, v := range req.Headers {
+		r.Header.Set(k, v)
+	}
+
+	resp, err := g.client.Do(r)
+	if err != nil {
+		return nil, fmt.Errorf("error making request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	byt, err := io.ReadAll(io.LimitReader(resp.Body, int64(g.size)+1))
+	if err != nil {
+		return nil, fmt.Errorf("error reading response: %w", err)
+	}
+	if len(byt) > g.size {
+		return nil, fmt.Errorf("%w of %d bytes", ErrResponseTooLarge, g.size)
+	}
+
+	headers := map[string]string{}
+	for k := range resp.Header {
+		headers[strings.ToLower(k)] = resp.Header.Get(k)
+	}
+
+	return &Response{
+		StatusCode: resp.StatusCode,
+		Headers:    headers,
+		Body:       string(byt),
+	}, nil
+}
+
+// isAllowed returns whether the given host matches the allowlist.
+func (g *gateway) isAllowed(host string) bool {
+	if len(g.allowed) == 0 {
+		return true
+	}
+	host = strings.ToLower(host)
+	for _, a := range g.allowed {
+		a = strings.ToLower(a)
+		if suffix, ok := strings.CutPrefix(a, "*."); ok {
+			if strings.HasSuffix(host, "."+suffix) {
+				return true
+			}
+			continue
+		}
+		if host == a {
+			return true
+		}
+	}
+	return false
+}
+
+// deniedNetworks lists the networks denied alongside those matched by the
+// net.IP helpers within isPrivate.
+var deniedNetworks = []*net.IPNet{
+	// "This" network.
+	mustParseCIDR("0.0.0.0/8"),
+	// Carrier-grade NAT shared address space.
+	mustParseCIDR("100.64.0.0/10"),
+}
+
+func mustParseCIDR(s string) *net.IPNet {
+	_, n, err := net.ParseCIDR(s)
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
+
+// isPrivate returns whether the IP is a private, loopback, link-local or
+// otherwise non-public address.
+func isPrivate(ip net.IP) bool {
+	if ip.IsLoopback() ||
+		ip.IsPrivate() ||
+		ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast() {
+		return true
+	}
+	for _, n := range deniedNetworks {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// control is called by the dialer before connecting to each resolved address,
+// denying private addresses unless private networks are allowed.
+func (g *gateway) control(network, address string, _ syscall.RawConn) error {
+	if g.private {
+		return nil
+	}
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrAddressNotAllowed, address)
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || isPrivate(ip) {
+		return fmt.Errorf("%w: %s", ErrAddressNotAllowed, host)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package gateway
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetch(t *testing.T) {
+	ctx := context.Background()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/large":
+			_, _ = w.Write([]byte(strings.Repeat("a", 100)))
+		case "/redirect":
+			http.Redirect(w, r, "http://example.com/", http.StatusFound)
+		default:
+			body, _ := io.ReadAll(r.Body)
+			w.Header().Set("X-Method", r.Method)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(r.Header.Get("X-Test") + ":" + string(body)))
+		}
+	}))
+	defer srv.Close()
+
+	g := New(Config{
+		AllowedHosts:         []string{"127.0.0.1", "*.inngest.com"},
+		AllowPrivateNetworks: true,
+		MaxResponseSize:      50,
+	})
+
+	t.Run("it returns the response", func(t *testing.T) {
+		resp, err := g.Fetch(ctx, Request{
+			URL:     srv.URL + "/ok",
+			Method:  "post",
+			Headers: map[string]string{"X-Test": "yes"},
+			Body:    "hi",
+		})
+		require.NoError(t, err)
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		require.Equal(t, "yes:hi", resp.Body)
+		require.Equal(t, "POST", resp.Headers["x-method"])
+	})
+
+	t.Run("it caps response sizes", func(t *testing.T) {
+		_, err := g.Fetch(ctx, Request{URL: srv.URL + "/large"})
+		require.ErrorIs(t, err, ErrResponseTooLarge)
+	})
+
+	t.Run("it enforces the allowlist", func(t *testing.T) {
+		_, err := g.Fetch(ctx, Request{URL: "http://example.com"})
+		require.ErrorIs(t, err, ErrHostNotAllowed)
+
+		_, err = g.Fetch(ctx, Request{URL: srv.URL + "/redirect"})
+		require.ErrorIs(t, err, ErrHostNotAllowed)
+	})
+
+	t.Run("it matches wildcard hosts", func(t *testing.T) {
+		gw := g.(*gateway)
+		require.True(t, gw.isAllowed("api.inngest.com"))
+		require.False(t, gw.isAllowed("inngest.com"))
+		require.False(t, gw.isAllowed("evilinngest.com"))
+	})
+
+	t.Run("it rejects invalid urls", func(t *testing.T) {
+		_, err := g.Fetch(ctx, Request{URL: "file:///etc/passwd"})
+		require.ErrorContains(t, err, "invalid url")
+	})
+}
+
+func TestFetchPrivateNetworks(t *testing.T) {
+	ctx := context.Background()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	t.Run("it denies private addresses by default", func(t *testing.T) {
+		// The loopback server is denied even though every host is allowed.
+		g := New(Config{})
+		_, err := g.Fetch(ctx, Request{URL: srv.URL})
+		require.ErrorIs(t, err, ErrAddressNotAllowed)
+
+		// Hosts are checked once they're resolved.
+		u := strings.Replace(srv.URL, "127.0.0.1", "localhost", 1)
+		_, err = g.Fetch(ctx, Request{URL: u})
+		require.ErrorIs(t, err, ErrAddressNotAllowed)
+	})
+
+	t.Run("it allows private addresses if configured", func(t *testing.T) {
+		g := New(Config{AllowPrivateNetworks: true})
+		resp, err := g.Fetch(ctx, Request{URL: srv.URL})
+		require.NoError(t, err)
+		require.Equal(t, "ok", resp.Body)
+	})
+
+	t.Run("it matches private addresses", func(t *testing.T) {
+		for _, ip := range []string{
+			"127.0.0.1",
+			"10.1.2.3",
+			"172.16.0.1",
+			"192.168.1.1",
+			"169.254.169.254",
+			"100.64.0.1",
+			"0.0.0.0",
+			"::1",
+			"fe80::1",
+			"fd00:ec2::254",
+			"::ffff:169.254.169.254",
+		} {
+			require.True(t, isPrivate(net.ParseIP(ip)), ip)
+		}
+		for _, ip := range []string{"8.8.8.8", "1.1.1.1", "2606:4700:4700::1111"} {
+			require.False(t, isPrivate(net.ParseIP(ip)), ip)
+		}
+	})
+}
@@ -0,0 +1,439 @@
+// Package harness provides an in-memory execution engine for testing function
+// definitions.
+//
+// The harness wires together an in-memory state store, queue, and executor with a
+// mock driver.  Instead of calling an SDK over HTTP, each function invocation calls
+// a HandlerFunc which returns the driver response, letting tests drive functions
+// with synthetic events and assert on step ordering, retries, and outputs:
+//
+//	h, _ := harness.New(ctx)
+//	defer h.Close()
+//
+//	h.Register(fn, func(ctx context.Context, r harness.Request) (*state.DriverResponse, error) {
+//		return &state.DriverResponse{Output: "ok", StatusCode: 200}, nil
+//	})
+//	run, err := h.Run(ctx, fn.ID, event.Event{Name: "test/event"})
+package harness
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/inngest/inngest/pkg/config"
+	"github.com/inngest/inngest/pkg/consts"
+	"github.com/inngest/inngest/pkg/enums"
+	"github.com/inngest/inngest/pkg/event"
+	"github.com/inngest/inngest/pkg/execution"
+	"github.com/inngest/inngest/pkg/execution/executor"
+	"github.com/inngest/inngest/pkg/execution/queue"
+	"github.com/inngest/inngest/pkg/execution/state"
+	"github.com/inngest/inngest/pkg/execution/state/redis_state"
+	"github.com/inngest/inngest/pkg/inngest"
+	"github.com/oklog/ulid/v2"
+	"github.com/redis/rueidis"
+)
+
+const (
+	// DefaultTimeout is the maximum time that Run waits for a function to finish
+	// when the given context has no deadline.
+	DefaultTimeout = 10 * time.Second
+
+	// stepURI is the URI given to steps of registered functions which have none,
+	// ensuring that steps are executed by the harness driver.
+	stepURI = "http://harness.local/api/inngest"
+)
+
+var (
+	ErrFunctionNotFound = fmt.Errorf("function not registered")
+	ErrTimeout          = fmt.Errorf("timed out waiting for function to finish")
+)
+
+// Request is passed to a HandlerFunc each time the executor invokes a function.
+type Request struct {
+	// Identifier identifies the function run.
+	Identifier state.Identifier
+	// Step is the function step being invoked.
+	Step inngest.Step
+	// Edge is the edge being executed.  Edge.IncomingGeneratorStep is set when the
+	// executor is asking the function to run a specific planned step.
+	Edge inngest.Edge
+	// Events are the events that triggered the run.
+	Events []map[string]any
+	// Actions contains the output of every step that has finished so far, keyed by
+	// step ID.
+	Actions map[string]any
+	// Stack is the order in which steps finished.
+	Stack []string
+	// StackIndex is the current position within the stack.
+	StackIndex int
+	// Attempt is the zero-indexed attempt for the current invocation.
+	Attempt int
+}
+
+// HandlerFunc returns the response for a single function invocation, emulating
+// an SDK.  Returning an error is treated as a driver error, eg. a network failure,
+// and is retried.
+type HandlerFunc func(ctx context.Context, r Request) (*state.DriverResponse, error)
+
+// Call records a single invocation of a function's handler.
+type Call struct {
+	Request
+	Response *state.DriverResponse
+	Err      error
+}
+
+// Run is the result of a finished function run.
+type Run struct {
+	Identifier state.Identifier
+	// Status is the final status of the run.
+	Status enums.RunStatus
+	// Response is the final driver response for the run.
+	Response state.DriverResponse
+	// Calls lists every invocation of the function's handler in order.
+	Calls []Call
+}
+
+// Opt configures the harness.
+type Opt func(h *Harness)
+
+// WithExecutorOpts adds options to the executor created by the harness.
+func WithExecutorOpts(opts ...executor.ExecutorOpt) Opt {
+	return func(h *Harness) {
+		h.execOpts = append(h.execOpts, opts...)
+	}
+}
+
+// WithQueueOpts adds options to the queue created by the harness.
+func WithQueueOpts(opts ...redis_state.QueueOpt) Opt {
+	return func(h *Harness) {
+		h.queueOpts = append(h.queueOpts, opts...)
+	}
+}
+
+// Harness runs functions using an in-memory executor, state store and queue.
+type Harness struct {
+	execOpts  []executor.ExecutorOpt
+	queueOpts []redis_state.QueueOpt
+
+	r      *miniredis.Miniredis
+	rc     rueidis.Client
+	sm     state.Manager
+	exec   execution.Executor
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	l         sync.Mutex
+	functions map[uuid.UUID]inngest.Function
+	handlers  map[uuid.UUID]HandlerFunc
+	calls     map[ulid.ULID][]Call
+	done      map[ulid.ULID]chan Run
+}
+
+// New creates and starts a new harness.  Close must be called to stop the
+// harness once tests are complete.
+func New(ctx context.Context, opts ...Opt) (*Harness, error) {
+	h := &Harness{
+		functions: map[uuid.UUID]inngest.Function{},
+		handlers:  map[uuid.UUID]HandlerFunc{},
+		calls:     map[ulid.ULID][]Call{},
+		done:      map[ulid.ULID]chan Run{},
+	}
+	for _, o := range opts {
+		o(h)
+	}
+
+	h.r = miniredis.NewMiniRedis()
+	if err := h.r.Start(); err != nil {
+		return nil, fmt.Errorf("error starting redis: %w", err)
+	}
+	rc, err := rueidis.NewClient(rueidis.ClientOption{
+		InitAddress:  []string{h.r.Addr()},
+		DisableCache: true,
+	})
+	if err != nil {
+		h.r.Close()
+		return nil, fmt.Errorf("error connecting to redis: %w", err)
+	}
+	h.rc = rc
+
+	h.sm, err = redis_state.New(
+		ctx,
+		redis_state.WithRedisClient(rc),
+		redis_state.WithFunctionLoader(h),
+		redis_state.WithKeyGenerator(redis_state.DefaultKeyFunc{
+			Prefix: "{state}",
+		}),
+	)
+	if err != nil {
+		h.Close()
+		return nil, err
+	}
+
+	queueOpts := append([]redis_state.QueueOpt{
+		redis_state.WithPollTick(10 * time.Millisecond),
+		redis_state.WithQueueKeyGenerator(&redis_state.DefaultQueueKeyGenerator{
+			Prefix: "{queue}",
+		}),
+		// Retry immediately, instead of using the standard backoff table.
+		redis_state.WithBackoffFunc(func(attempt int) time.Time { return time.Now() }),
+	}, h.queueOpts...)
+	q := redis_state.NewQueue(rc, queueOpts...)
+
+	execOpts := append([]executor.ExecutorOpt{
+		executor.WithStateManager(h.sm),
+		executor.WithQueue(q),
+		executor.WithFunctionLoader(h),
+		executor.WithRuntimeDrivers(&mockDriver{h: h}),
+		executor.WithLifecycleListeners(listener{h: h}),
+		executor.WithStepLimits(func(id state.Identifier) int { return consts.DefaultMaxStepLimit }),
+		executor.WithParallelStepLimits(func(id state.Identifier) int { return consts.DefaultMaxParallelStepLimit }),
+	}, h.execOpts...)
+	h.exec, err = executor.NewExecutor(execOpts...)
+	if err != nil {
+		h.Close()
+		return nil, err
+	}
+	// Finish handlers send events;  the harness has no event stream.
+	h.exec.SetFinishHandler(func(context.Context, state.State, []event.Event) error { return nil })
+
+	// The service's config is only used to create finish handlers in Pre, which is
+	// never called as the harness sets its own finish handler.
+	svc := executor.NewService(
+		config.Config{},
+		executor.WithState(h.sm),
+		executor.WithServiceQueue(q),
+		executor.WithServiceExecutor(h.exec),
+	)
+
+	runCtx, cancel := context.WithCancel(context.Background())
+	h.cancel = cancel
+	h.wg.Add(1)
+	go func() {
+		defer h.wg.Done()
+		_ = svc.Run(runCtx)
+	}()
+
+	return h, nil
+}
+
+// Close stops the harness.
+func (h *Harness) Close() {
+	if h.cancel != nil {
+		h.cancel()
+		h.wg.Wait()
+	}
+	if h.rc != nil {
+		h.rc.Close()
+	}
+	h.r.Close()
+}
+
+// Executor returns the executor used by the harness.
+func (h *Harness) Executor() execution.Executor {
+	return h.exec
+}
+
+// State returns the state store used by the harness.
+func (h *Harness) State() state.Manager {
+	return h.sm
+}
+
+// Register registers a function with the given handler.  If the function has no
+// ID a deterministic ID is generated, and if the function has no steps a single
+// step is added, as with functions registered by SDKs.
+func (h *Harness) Register(fn inngest.Function, handler HandlerFunc) inngest.Function {
+	if len(fn.Steps) == 0 {
+		fn.Steps = []inngest.Step{
+			{
+				ID:   "step",
+				Name: fn.Name,
+				URI:  stepURI,
+			},
+		}
+	}
+	if fn.ID == uuid.Nil {
+		fn.ID = inngest.DeterministicUUID(fn)
+	}
+
+	h.l.Lock()
+	defer h.l.Unlock()
+	h.functions[fn.ID] = fn
+	h.handlers[fn.ID] = handler
+	return fn
+}
+
+// LoadFunction fulfils the state.FunctionLoader interface.
+func (h *Harness) LoadFunction(ctx context.Context, id state.Identifier) (*inngest.Function, error) {
+	h.l.Lock()
+	defer h.l.Unlock()
+	fn, ok := h.functions[id.WorkflowID]
+	if !ok {
+		return nil, ErrFunctionNotFound
+	}
+	return &fn, nil
+}
+
+// Schedule starts a new run of the given function using the given events,
+// without waiting for the run to finish.
+func (h *Harness) Schedule(ctx context.Context, fnID uuid.UUID, evts ...event.Event) (*state.Identifier, error) {
+	h.l.Lock()
+	fn, ok := h.functions[fnID]
+	h.l.Unlock()
+	if !ok {
+		return nil, ErrFunctionNotFound
+	}
+	if len(evts) == 0 {
+		return nil, fmt.Errorf("at least one event is required")
+	}
+
+	tracked := make([]event.TrackedEvent, len(evts))
+	for n, e := range evts {
+		if e.Timestamp == 0 {
+			e.Timestamp = time.Now().UnixMilli()
+		}
+		tracked[n] = event.NewOSSTrackedEvent(e)
+	}
+
+	req := execution.ScheduleRequest{
+		Function: fn,
+		Events:   tracked,
+	}
+	if len(tracked) > 1 {
+		batchID := ulid.Make()
+		req.BatchID = &batchID
+	}
+	return h.exec.Schedule(ctx, req)
+}
+
+// Run starts a new run of the given function and waits for it to finish.  If the
+// context has no deadline, Run waits for up to DefaultTimeout.
+func (h *Harness) Run(ctx context.Context, fnID uuid.UUID, evts ...event.Event) (*Run, error) {
+	id, err := h.Schedule(ctx, fnID, evts...)
+	if err != nil {
+		return nil, err
+	}
+	return h.Wait(ctx, id.RunID)
+}
+
+// Wait waits for the given run to finish.  If the context has no deadline, Wait
+// waits for up to DefaultTimeout.
+func (h *Harness) Wait(ctx context.Context, runID ulid.ULID) (*Run, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, DefaultTimeout)
+		defer cancel()
+	}
+
+	select {
+	case run := <-h.doneChan(runID):
+		return &run, nil
+	case <-ctx.Done():
+		return nil, ErrTimeout
+	}
+}
+
+// Send sends an event to the harness, resuming any runs waiting for the event
+// via waitForEvent or cancelling runs with matching cancellations.
+func (h *Harness) Send(ctx context.Context, evt event.Event) error {
+	if evt.Timestamp == 0 {
+		evt.Timestamp = time.Now().UnixMilli()
+	}
+	iter, err := h.sm.PausesByEvent(ctx, uuid.UUID{}, evt.Name)
+	if err != nil {
+		return err
+	}
+	_, err = h.exec.HandlePauses(ctx, iter, event.NewOSSTrackedEvent(evt))
+	return err
+}
+
+func (h *Harness) doneChan(runID ulid.ULID) chan Run {
+	h.l.Lock()
+	defer h.l.Unlock()
+	ch, ok := h.done[runID]
+	if !ok {
+		// Buffer the channel so that runs finishing before Wait is called
+		// don't block.
+		ch = make(chan Run, 1)
+		h.done[runID] = ch
+	}
+	return ch
+}
+
+func (h *Harness) finish(id state.Identifier, status enums.RunStatus, resp state.DriverResponse) {
+	h.l.Lock()
+	calls := h.calls[id.RunID]
+	delete(h.calls, id.RunID)
+	h.l.Unlock()
+
+	h.doneChan(id.RunID) <- Run{
+		Identifier: id,
+		Status:     status,
+		Response:   resp,
+		Calls:      calls,
+	}
+}
+
+// mockDriver executes steps by calling the handler registered for each function.
+type mockDriver struct {
+	h *Harness
+}
+
+func (mockDriver) RuntimeType() string {
+	return "http"
+}
+
+func (m *mockDriver) Execute(ctx context.Context, s state.State, item queue.Item, edge inngest.Edge, step inngest.Step, idx, attempt int) (*state.DriverResponse, error) {
+	id := s.Identifier()
+
+	m.h.l.Lock()
+	handler, ok := m.h.handlers[id.WorkflowID]
+	m.h.l.Unlock()
+	if !ok {
+		return nil, ErrFunctionNotFound
+	}
+
+	req := Request{
+		Identifier: id,
+		Step:       step,
+		Edge:       edge,
+		Events:     s.Events(),
+		Actions:    s.Actions(),
+		Stack:      s.Stack(),
+		StackIndex: idx,
+		Attempt:    attempt,
+	}
+	resp, err := handler(ctx, req)
+
+	m.h.l.Lock()
+	m.h.calls[id.RunID] = append(m.h.calls[id.RunID], Call{
+		Request:  req,
+		Response: resp,
+		Err:      err,
+	})
+	m.h.l.Unlock()
+
+	return resp, err
+}
+
+// listener notifies the harness when runs finish.
+type listener struct {
+	execution.NoopLifecyceListener
+
+	h *Harness
+}
+
+func (l listener) OnFunctionFinished(ctx context.Context, id state.Identifier, item queue.Item, resp state.DriverResponse, s state.State) {
+	status := enums.RunStatusCompleted
+	if resp.Err != nil {
+		status = enums.RunStatusFailed
+	}
+	l.h.finish(id, status, resp)
+}
+
+func (l listener) OnFunctionCancelled(ctx context.Context, id state.Identifier, req execution.CancelRequest, s state.State) {
+	l.h.finish(id, enums.RunStatusCancelled, state.DriverResponse{})
+}
@@ -0,0 +1,65 @@
+package harness
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/inngest/inngest/pkg/enums"
+	"github.com/inngest/inngest/pkg/event"
+	"github.com/inngest/inngest/pkg/execution/state"
+	"github.com/inngest/inngest/pkg/inngest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHarness(t *testing.T) {
+	ctx := context.Background()
+	h, err := New(ctx)
+	require.NoError(t, err)
+	defer h.Close()
+
+	t.Run("It runs a function with steps in order", func(t *testing.T) {
+		fn := h.Register(inngest.Function{Name: "steps", Slug: "steps"}, func(ctx context.Context, r Request) (*state.DriverResponse, error) {
+			// Run step "a", then "b", then return the combined output.
+			for _, id := range []string{"a", "b"} {
+				if _, ok := r.Actions[id]; !ok {
+					return &state.DriverResponse{
+						StatusCode: 206,
+						Generator: []*state.GeneratorOpcode{{
+							Op:   enums.OpcodeStepRun,
+							ID:   id,
+							Name: id,
+							Data: json.RawMessage(fmt.Sprintf("%q", id)),
+						}},
+					}, nil
+				}
+			}
+			return &state.DriverResponse{StatusCode: 200, Output: "done"}, nil
+		})
+
+		run, err := h.Run(ctx, fn.ID, event.Event{Name: "test/event"})
+		require.NoError(t, err)
+		require.Equal(t, enums.RunStatusCompleted, run.Status)
+		require.Equal(t, "done", run.Response.Output)
+		require.Len(t, run.Calls, 3)
+		require.Equal(t, []string{"a", "b"}, run.Calls[2].Stack)
+		require.Equal(t, "test/event", run.Calls[0].Events[0]["name"])
+	})
+
+	t.Run("It retries driver errors", func(t *testing.T) {
+		fn := h.Register(inngest.Function{Name: "retries", Slug: "retries"}, func(ctx context.Context, r Request) (*state.DriverResponse, error) {
+			if r.Attempt == 0 {
+				return nil, fmt.Errorf("connection refused")
+			}
+			return &state.DriverResponse{StatusCode: 200, Output: "ok"}, nil
+		})
+
+		run, err := h.Run(ctx, fn.ID, event.Event{Name: "test/event"})
+		require.NoError(t, err)
+		require.Equal(t, enums.RunStatusCompleted, run.Status)
+		require.Len(t, run.Calls, 2)
+		require.Error(t, run.Calls[0].Err)
+		require.Equal(t, 1, run.Calls[1].Attempt)
+	})
+}
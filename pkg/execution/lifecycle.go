@@ -2,8 +2,11 @@ package execution
 
 import (
 	"context"
+	"encoding/json"
 	"time"
 
+	"github.com/inngest/inngest/pkg/enums"
+	"github.com/inngest/inngest/pkg/execution/batch"
 	"github.com/inngest/inngest/pkg/execution/queue"
 	"github.com/inngest/inngest/pkg/execution/state"
 	"github.com/inngest/inngest/pkg/inngest"
@@ -14,9 +17,24 @@ import (
 type SkipState struct {
 	// CronSchedule, if present, is the cron schedule string that triggered the skipped function.
 	CronSchedule *string
+	// Reason is the reason the function run was skipped.
+	Reason enums.SkipReason
 }
 
-var _ LifecycleListener = (*NoopLifecyceListener)(nil)
+// GatewayResult is the result of a request made by the executor on behalf of a
+// step, eg. for OpcodeGateway or OpcodeAIGateway.
+type GatewayResult struct {
+	// Output is saved as the step's output if the request succeeded.
+	Output json.RawMessage
+	// Error is saved as the step's error if the request failed.  The step is
+	// retried unless Error.NoRetry is set.
+	Error *state.UserError
+}
+
+var (
+	_ LifecycleListener       = (*NoopLifecyceListener)(nil)
+	_ OpcodeLifecycleListener = (*NoopLifecyceListener)(nil)
+)
 
 // LifecycleListener listens to lifecycle events on the executor.
 type LifecycleListener interface {
@@ -159,6 +177,62 @@ type LifecycleListener interface {
 	Close() error
 }
 
+// OpcodeLifecycleListener extends LifecycleListener with events for opcodes that
+// are handled by the executor rather than the SDK.  Without these, listeners only
+// see the generic OnStepFinished call for the SDK's response and must inspect the
+// response's opcodes to understand what happened.
+//
+// Listeners which only implement LifecycleListener continue to work: the
+// executor upgrades them with UpgradeLifecycleListener, which ignores these
+// events.
+type OpcodeLifecycleListener interface {
+	LifecycleListener
+
+	// OnGatewayRequestFinished is called when the executor finishes an HTTP or
+	// inference request on behalf of a step.  The state.GeneratorOpcode is the
+	// step's OpcodeGateway or OpcodeAIGateway opcode, containing the request.
+	OnGatewayRequestFinished(
+		context.Context,
+		state.Identifier,
+		queue.Item,
+		state.GeneratorOpcode,
+		GatewayResult,
+	)
+
+	// OnBatchAppended is called when an event is appended to one of the
+	// function's batches.  The result details whether the event started a new
+	// batch, filled the batch, or overflowed.  No run exists at this point, so
+	// this isn't called with a state.Identifier.
+	OnBatchAppended(
+		context.Context,
+		inngest.Function,
+		batch.BatchItem,
+		batch.BatchAppendResult,
+	)
+}
+
+// UpgradeLifecycleListener returns the listener as an OpcodeLifecycleListener.
+// Listeners which don't implement OpcodeLifecycleListener are wrapped so that
+// opcode events are ignored.
+func UpgradeLifecycleListener(l LifecycleListener) OpcodeLifecycleListener {
+	if ol, ok := l.(OpcodeLifecycleListener); ok {
+		return ol
+	}
+	return lifecycleShim{LifecycleListener: l}
+}
+
+// lifecycleShim ignores opcode events for listeners written before
+// OpcodeLifecycleListener existed.
+type lifecycleShim struct {
+	LifecycleListener
+}
+
+func (lifecycleShim) OnGatewayRequestFinished(context.Context, state.Identifier, queue.Item, state.GeneratorOpcode, GatewayResult) {
+}
+
+func (lifecycleShim) OnBatchAppended(context.Context, inngest.Function, batch.BatchItem, batch.BatchAppendResult) {
+}
+
 // NoopLifecyceListener does nothing.  This can be embedded into a custom implementation
 // allowing other implementations to override specific functions.
 type NoopLifecyceListener struct{}
@@ -302,4 +376,25 @@ func (NoopLifecyceListener) OnSleep(
 ) {
 }
 
+// OnGatewayRequestFinished is called when the executor finishes an HTTP or
+// inference request on behalf of a step.
+func (NoopLifecyceListener) OnGatewayRequestFinished(
+	context.Context,
+	state.Identifier,
+	queue.Item,
+	state.GeneratorOpcode,
+	GatewayResult,
+) {
+}
+
+// OnBatchAppended is called when an event is appended to one of the function's
+// batches.
+func (NoopLifecyceListener) OnBatchAppended(
+	context.Context,
+	inngest.Function,
+	batch.BatchItem,
+	batch.BatchAppendResult,
+) {
+}
+
 func (NoopLifecyceListener) Close() error { return nil }
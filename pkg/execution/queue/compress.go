@@ -0,0 +1,86 @@
+package queue
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// payloadEncodingGzip is the encoding version for gzip-compressed payloads.  The
+// version is stored as the first byte of compressed payloads, allowing the
+// encoding to change without breaking items that are already enqueued.
+const payloadEncodingGzip byte = 1
+
+// compressedPayload is the data stored within an item's CompressedPayload.
+type compressedPayload struct {
+	Payload  any               `json:"payload,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// CompressPayload returns a copy of the item whose Payload and Metadata are
+// compressed into CompressedPayload if their encoded size is at least threshold
+// bytes.  Other fields are left as-is, as they're read by queue implementations.
+//
+// Compressed payloads are decompressed when items are unmarshalled, so items
+// stored with and without compression may be read interchangeably.
+func CompressPayload(i Item, threshold int) (Item, error) {
+	if threshold <= 0 || (i.Payload == nil && len(i.Metadata) == 0) {
+		return i, nil
+	}
+
+	byt, err := json.Marshal(compressedPayload{Payload: i.Payload, Metadata: i.Metadata})
+	if err != nil {
+		return i, fmt.Errorf("error marshalling queue item payload: %w", err)
+	}
+	if len(byt) < threshold {
+		return i, nil
+	}
+
+	buf := bytes.NewBuffer([]byte{payloadEncodingGzip})
+	w := gzip.NewWriter(buf)
+	if _, err := w.Write(byt); err != nil {
+		return i, fmt.Errorf("error compressing queue item payload: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return i, fmt.Errorf("error compressing queue item payload: %w", err)
+	}
+
+	i.Payload = nil
+	i.Metadata = nil
+	i.CompressedPayload = buf.Bytes()
+	return i, nil
+}
+
+// decompressPayload returns the JSON-encoded payload and metadata stored within
+// a compressed payload.
+func decompressPayload(b []byte) (json.RawMessage, map[string]string, error) {
+	if len(b) == 0 {
+		return nil, nil, fmt.Errorf("empty compressed payload")
+	}
+
+	var byt []byte
+	switch b[0] {
+	case payloadEncodingGzip:
+		r, err := gzip.NewReader(bytes.NewReader(b[1:]))
+		if err != nil {
+			return nil, nil, fmt.Errorf("error decompressing queue item payload: %w", err)
+		}
+		defer r.Close()
+		if byt, err = io.ReadAll(r); err != nil {
+			return nil, nil, fmt.Errorf("error decompressing queue item payload: %w", err)
+		}
+	default:
+		return nil, nil, fmt.Errorf("unknown queue item payload encoding: %d", b[0])
+	}
+
+	data := struct {
+		Payload  json.RawMessage   `json:"payload"`
+		Metadata map[string]string `json:"metadata"`
+	}{}
+	if err := json.Unmarshal(byt, &data); err != nil {
+		return nil, nil, fmt.Errorf("error unmarshalling queue item payload: %w", err)
+	}
+	return data.Payload, data.Metadata, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/inngest/inngest/pkg/consts"
@@ -80,6 +81,13 @@ type Item struct {
 	// Throttle represents GCRA rate limiting for the queue item, which is applied when
 	// attempting to lease the item from the queue.
 	Throttle *Throttle `json:"throttle,omitempty"`
+	// RequestTimeout is the maximum duration of driver requests made when processing
+	// the item.  If zero, consts.MaxFunctionTimeout is used.
+	RequestTimeout time.Duration `json:"rt,omitempty"`
+	// CompressedPayload stores the versioned, compressed Payload and Metadata of
+	// large items.  This is only set on stored items, and is decompressed into
+	// Payload and Metadata when items are unmarshalled.  See CompressPayload.
+	CompressedPayload []byte `json:"pz,omitempty"`
 }
 
 type Throttle struct {
@@ -116,6 +124,15 @@ func (i Item) GetPriorityFactor() int64 {
 	return 0
 }
 
+// GetRequestTimeout returns the maximum duration of driver requests made when processing
+// the item, which is never greater than consts.MaxFunctionTimeout.
+func (i Item) GetRequestTimeout() time.Duration {
+	if i.RequestTimeout <= 0 || i.RequestTimeout > consts.MaxFunctionTimeout {
+		return consts.MaxFunctionTimeout
+	}
+	return i.RequestTimeout
+}
+
 func (i Item) GetMaxAttempts() int {
 	if i.MaxAttempts == nil {
 		return consts.DefaultRetryCount
@@ -139,6 +156,7 @@ func (i *Item) UnmarshalJSON(b []byte) error {
 		Payload     json.RawMessage   `json:"payload"`
 		Metadata    map[string]string `json:"metadata"`
 		Throttle    *Throttle         `json:"throttle"`
+		Compressed  []byte            `json:"pz"`
 	}
 	temp := &kind{}
 	err := json.Unmarshal(b, temp)
@@ -146,6 +164,13 @@ func (i *Item) UnmarshalJSON(b []byte) error {
 		return fmt.Errorf("error unmarshalling queue item: %w", err)
 	}
 
+	if len(temp.Compressed) > 0 {
+		temp.Payload, temp.Metadata, err = decompressPayload(temp.Compressed)
+		if err != nil {
+			return err
+		}
+	}
+
 	i.GroupID = temp.GroupID
 	i.WorkspaceID = temp.WorkspaceID
 	i.Kind = temp.Kind
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/inngest/inngest/pkg/execution/state"
 	"github.com/oklog/ulid/v2"
 )
 
@@ -30,6 +31,48 @@ type Producer interface {
 	Enqueue(context.Context, Item, time.Time) error
 }
 
+// BulkProducer is an optional interface that queues can fulfil, enqueueing many
+// items in a single round trip.
+type BulkProducer interface {
+	// EnqueueMany enqueues each item to run at its given time, as with calling
+	// Enqueue for each.  The returned errors correspond to each item.
+	EnqueueMany(context.Context, []ItemAt) []error
+}
+
+// HealthReporter is an optional interface that queues can fulfil, reporting
+// connectivity to the queue's backing store and the liveness of its processing
+// loop.
+type HealthReporter interface {
+	// Ping checks connectivity to the queue's backing store.
+	Ping(ctx context.Context) error
+	// Liveness returns the times of the processing loop's last scan and lease.
+	Liveness() Liveness
+}
+
+// OrderedRunReleaser is an optional interface that queues can fulfil, starting
+// runs with ordered concurrency keys one at a time.
+type OrderedRunReleaser interface {
+	// ReleaseOrdered releases the run's ordered concurrency keys once the run
+	// finishes, allowing the next run with the same keys to start.
+	ReleaseOrdered(ctx context.Context, id state.Identifier) error
+}
+
+// Liveness records the last activity of a queue's processing loop.  Times are
+// zero until the loop first scans or leases an item.
+type Liveness struct {
+	// LastScanAt is the time of the last successful scan for jobs, or the last
+	// poll skipped while all workers were busy.
+	LastScanAt time.Time `json:"last_scan_at"`
+	// LastLeaseAt is the time that an item was last successfully leased.
+	LastLeaseAt time.Time `json:"last_lease_at"`
+}
+
+// ItemAt is an item to enqueue, and the time that it should run.
+type ItemAt struct {
+	Item Item
+	At   time.Time
+}
+
 type Consumer interface {
 	// Run is a blocking function which listens to the queue and executes the
 	// given function each time a new Item becomes available.
@@ -145,6 +188,135 @@ type JobResponse struct {
 	Attempt int `json:"attempt"`
 }
 
+// ScheduledJob represents a job which is scheduled to run in the future, such as
+// a sleep, a waitForEvent timeout, or a delayed run.
+type ScheduledJob struct {
+	// JobID is the ID of the queue item.
+	JobID string `json:"jobID"`
+	// FunctionID is the ID of the function that the job belongs to.
+	FunctionID uuid.UUID `json:"functionID"`
+	// RunID is the ID of the run that the job belongs to.  This is empty for
+	// jobs which start runs, such as debounces.
+	RunID ulid.ULID `json:"runID"`
+	// Kind represents the kind of job, eg. "sleep" or "pause".
+	Kind string `json:"kind"`
+	// StepID is the ID of the step that the job runs, if any.
+	StepID string `json:"stepID,omitempty"`
+	// At represents the time the job wakes up.
+	At time.Time `json:"at"`
+	// Attempt is the job's current attempt, zero-indexed.
+	Attempt int `json:"attempt"`
+	// Metadata is the job's item metadata.
+	Metadata map[string]string `json:"-"`
+}
+
+// PartitionStats represents the current backlog for a single function's queue
+// partition.
+type PartitionStats struct {
+	// FunctionID is the ID of the function that this partition belongs to.
+	FunctionID uuid.UUID `json:"functionID"`
+	// Ready is the number of items whose scheduled time has passed and which
+	// are waiting to be leased.
+	Ready int64 `json:"ready"`
+	// Scheduled is the number of items scheduled to run in the future, eg.
+	// sleeps, retries with backoff, or future-dated events.
+	Scheduled int64 `json:"scheduled"`
+	// InProgress is the number of items currently leased by a worker.
+	InProgress int64 `json:"inProgress"`
+	// ConcurrencyLimit is the function's concurrency limit.  Zero represents
+	// no limit.
+	ConcurrencyLimit int64 `json:"concurrencyLimit"`
+	// ConcurrencyLimited is the number of ready items that cannot be leased
+	// because the function is at its concurrency limit.
+	ConcurrencyLimited int64 `json:"concurrencyLimited"`
+	// OldestItemAt is the time the oldest ready item was scheduled for, or nil
+	// if there are no ready items.
+	OldestItemAt *time.Time `json:"oldestItemAt,omitempty"`
+	// Paused is true if leasing items from the partition is paused.
+	Paused bool `json:"paused"`
+}
+
+// OldestItemAge returns how long the oldest ready item has been waiting.
+func (p PartitionStats) OldestItemAge(now time.Time) time.Duration {
+	if p.OldestItemAt == nil {
+		return 0
+	}
+	return now.Sub(*p.OldestItemAt)
+}
+
+// ErrJobNotFound is returned when an outstanding job cannot be found.
+var ErrJobNotFound = errors.New("job not found")
+
+// JobRequeuer reschedules outstanding jobs.
+type JobRequeuer interface {
+	// RequeueRunStep locates the outstanding job for the given run and step and
+	// reschedules it to run at the given time, bypassing any retry backoff or sleep.
+	//
+	// This returns ErrJobNotFound if there's no outstanding job for the step.
+	RequeueRunStep(ctx context.Context, runID ulid.ULID, stepID string, at time.Time) error
+
+	// BoostRunPriority moves the given run's jobs which are ready to run ahead of
+	// the function's backlog, scheduling them earlier by the given duration.  This
+	// returns the number of jobs boosted.
+	BoostRunPriority(ctx context.Context, runID ulid.ULID, by time.Duration) (int, error)
+}
+
+// RunStartRemover removes the jobs which start runs that are scheduled in the
+// future, such as delayed runs.
+type RunStartRemover interface {
+	// RemoveRunStart removes the job which starts the given run, so that the run
+	// never starts.
+	//
+	// This returns ErrJobNotFound if the run has no outstanding start job, eg.
+	// because the run has already started.
+	RemoveRunStart(ctx context.Context, runID ulid.ULID) error
+}
+
+// ConcurrencyKeyCounter counts the distinct custom concurrency keys used by each
+// function, so that functions generating unbounded keys can be identified.
+type ConcurrencyKeyCounter interface {
+	// CountConcurrencyKeys records the custom concurrency keys used by a run of
+	// the given function.  This returns the approximate number of distinct keys
+	// recorded for each of the function's custom limits within the current
+	// window, keyed by the limit's hash.
+	CountConcurrencyKeys(ctx context.Context, fnID uuid.UUID, keys []state.CustomConcurrency) (map[string]int64, error)
+}
+
+// ThrottleUpdater updates throttle configuration for functions at runtime, without
+// redeploying the function.
+type ThrottleUpdater interface {
+	// SetThrottle overrides the throttle limit, burst and period for the given
+	// function, applied to both queued and future jobs.  The throttle's key is
+	// ignored.  A nil throttle removes the override.
+	SetThrottle(ctx context.Context, workflowID uuid.UUID, t *Throttle) error
+}
+
+// PartitionPauser pauses and resumes processing of a function's queued jobs.
+//
+// This is independent of pausing a function, which skips new runs:  pausing a
+// function's partition stops jobs from being started immediately, without
+// dropping any queued work.
+type PartitionPauser interface {
+	// SetPartitionPaused pauses or resumes leasing of the given function's jobs.
+	// Jobs can still be enqueued while paused, and run once resumed.
+	SetPartitionPaused(ctx context.Context, workflowID uuid.UUID, paused bool) error
+}
+
+// ConcurrencyLimitUpdater updates the custom concurrency limits for outstanding jobs.
+//
+// Custom concurrency keys are evaluated when a run is scheduled and stored with
+// each job.  This allows limits to be changed for work that's already queued.
+type ConcurrencyLimitUpdater interface {
+	// UpdateConcurrencyLimits overrides the limits of the given function's custom
+	// concurrency keys, updating every outstanding job.  Limits are keyed by the
+	// hash of the concurrency expression, and apply to jobs enqueued later until
+	// they're updated again.  This returns the number of jobs updated.
+	UpdateConcurrencyLimits(ctx context.Context, workflowID uuid.UUID, limits map[string]int) (int, error)
+	// ConcurrencyLimitOverrides returns the limits set for the given function via
+	// UpdateConcurrencyLimits, keyed by the hash of the concurrency expression.
+	ConcurrencyLimitOverrides(ctx context.Context, workflowID uuid.UUID) (map[string]int, error)
+}
+
 // JobQueueReader
 type JobQueueReader interface {
 	// OutstandingJobCount returns the number of jobs in progress
@@ -167,6 +339,10 @@ type JobQueueReader interface {
 		status string,
 	) (int64, error)
 
+	// PartitionStats returns backlog information for the given function's
+	// queue partition.
+	PartitionStats(ctx context.Context, workflowID uuid.UUID) (PartitionStats, error)
+
 	// RunJobs reads items in the queue for a specific run.
 	RunJobs(
 		ctx context.Context,
@@ -176,4 +352,25 @@ type JobQueueReader interface {
 		limit,
 		offset int64,
 	) ([]JobResponse, error)
+
+	// ScheduledJobs lists jobs scheduled to run in the future for a function,
+	// ordered by the time they're scheduled for.  If runID is not nil, only
+	// jobs for the given run are returned.
+	ScheduledJobs(
+		ctx context.Context,
+		workspaceID uuid.UUID,
+		workflowID uuid.UUID,
+		runID *ulid.ULID,
+		limit int64,
+	) ([]ScheduledJob, error)
+
+	// PeekJobs returns the next jobs in a function's queue, ordered by the time
+	// they're scheduled for, without leasing them.  This includes jobs that are
+	// ready to run and excludes jobs which are in progress.
+	PeekJobs(
+		ctx context.Context,
+		workspaceID uuid.UUID,
+		workflowID uuid.UUID,
+		limit int64,
+	) ([]ScheduledJob, error)
 }
@@ -1,9 +1,12 @@
 package queue
 
 import (
+	"encoding/json"
 	"fmt"
+	"strings"
 	"testing"
 
+	"github.com/inngest/inngest/pkg/inngest"
 	"github.com/stretchr/testify/require"
 )
 
@@ -66,3 +69,52 @@ func TestShouldRetry(t *testing.T) {
 		require.Equal(t, test.expected, actual)
 	}
 }
+
+func TestCompressPayload(t *testing.T) {
+	item := Item{
+		Kind:     KindEdge,
+		Payload:  PayloadEdge{Edge: inngest.Edge{Outgoing: "step", Incoming: strings.Repeat("a", 1024)}},
+		Metadata: map[string]string{"traceparent": "00-abc-def-01"},
+		Throttle: &Throttle{Key: "k", Limit: 1, Period: 60},
+	}
+
+	t.Run("small payloads are not compressed", func(t *testing.T) {
+		stored, err := CompressPayload(item, 1<<20)
+		require.NoError(t, err)
+		require.Equal(t, item, stored)
+	})
+
+	t.Run("large payloads are compressed and decoded", func(t *testing.T) {
+		stored, err := CompressPayload(item, 128)
+		require.NoError(t, err)
+		require.Nil(t, stored.Payload)
+		require.Nil(t, stored.Metadata)
+		require.NotEmpty(t, stored.CompressedPayload)
+		require.Equal(t, item.Throttle, stored.Throttle)
+
+		byt, err := json.Marshal(stored)
+		require.NoError(t, err)
+		require.Less(t, len(byt), 1024)
+
+		decoded := Item{}
+		require.NoError(t, json.Unmarshal(byt, &decoded))
+		require.Equal(t, item, decoded)
+	})
+
+	t.Run("uncompressed items decode", func(t *testing.T) {
+		byt, err := json.Marshal(item)
+		require.NoError(t, err)
+		decoded := Item{}
+		require.NoError(t, json.Unmarshal(byt, &decoded))
+		require.Equal(t, item, decoded)
+	})
+
+	t.Run("unknown encodings error", func(t *testing.T) {
+		stored, err := CompressPayload(item, 128)
+		require.NoError(t, err)
+		stored.CompressedPayload[0] = 99
+		byt, err := json.Marshal(stored)
+		require.NoError(t, err)
+		require.Error(t, json.Unmarshal(byt, &Item{}))
+	})
+}
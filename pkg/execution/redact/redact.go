@@ -0,0 +1,132 @@
+package redact
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Kind represents the kind of data being redacted.
+type Kind string
+
+const (
+	// KindEvent is an event payload, redacted before it's recorded in traces.
+	KindEvent Kind = "event"
+	// KindOutput is a step or function output, redacted before it's saved to the
+	// state store and recorded in traces.
+	KindOutput Kind = "output"
+
+	// Placeholder replaces redacted values.
+	Placeholder = "[REDACTED]"
+
+	wildcard = "*"
+)
+
+// Hook is invoked with JSON encoded data before it's persisted, returning the
+// data to persist.  Hooks must return the input unmodified if there's nothing to
+// redact.
+type Hook func(ctx context.Context, kind Kind, data []byte) []byte
+
+// Config configures the JSON paths redacted by the hook returned from New.
+//
+// Paths are dot-separated keys, eg. "data.user.email".  A "*" segment matches
+// every key of an object or every item of an array, eg. "data.users.*.email".
+// Numeric segments index into arrays.
+type Config struct {
+	// Events lists paths redacted from event payloads.
+	Events []string `json:"events"`
+	// Outputs lists paths redacted from step and function outputs.
+	Outputs []string `json:"outputs"`
+}
+
+// New returns a hook which replaces the values at the configured paths with
+// Placeholder.  Data that isn't valid JSON is returned as-is.
+func New(c Config) Hook {
+	paths := map[Kind][][]string{
+		KindEvent:  split(c.Events),
+		KindOutput: split(c.Outputs),
+	}
+	return func(ctx context.Context, kind Kind, data []byte) []byte {
+		p := paths[kind]
+		if len(p) == 0 || len(data) == 0 {
+			return data
+		}
+		// Decode numbers as json.Number, so that numbers which aren't redacted
+		// keep their precision, eg. large IDs.
+		var val any
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		if err := dec.Decode(&val); err != nil {
+			return data
+		}
+		if _, err := dec.Token(); err != io.EOF {
+			// The data isn't a single JSON value.
+			return data
+		}
+		changed := false
+		for _, path := range p {
+			if redact(val, path) {
+				changed = true
+			}
+		}
+		if !changed {
+			return data
+		}
+		byt, err := json.Marshal(val)
+		if err != nil {
+			return data
+		}
+		return byt
+	}
+}
+
+func split(paths []string) [][]string {
+	result := make([][]string, 0, len(paths))
+	for _, p := range paths {
+		if p = strings.TrimSpace(p); p != "" {
+			result = append(result, strings.Split(p, "."))
+		}
+	}
+	return result
+}
+
+// redact replaces values at the given path within val, returning whether any
+// value was replaced.
+func redact(val any, path []string) bool {
+	if len(path) == 0 {
+		return false
+	}
+	key, rest := path[0], path[1:]
+
+	changed := false
+	switch v := val.(type) {
+	case map[string]any:
+		for k, child := range v {
+			if key != wildcard && key != k {
+				continue
+			}
+			if len(rest) == 0 {
+				v[k] = Placeholder
+				changed = true
+				continue
+			}
+			changed = redact(child, rest) || changed
+		}
+	case []any:
+		for i, child := range v {
+			if key != wildcard && key != strconv.Itoa(i) {
+				continue
+			}
+			if len(rest) == 0 {
+				v[i] = Placeholder
+				changed = true
+				continue
+			}
+			changed = redact(child, rest) || changed
+		}
+	}
+	return changed
+}
@@ -0,0 +1,48 @@
+package redact
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	ctx := context.Background()
+	hook := New(Config{
+		Events:  []string{"data.email", "data.users.*.ssn"},
+		Outputs: []string{"data.0.token", "*.password"},
+	})
+
+	t.Run("redacts event paths", func(t *testing.T) {
+		out := hook(ctx, KindEvent, []byte(`{"name":"user/created","data":{"email":"a@example.com","users":[{"ssn":"1","id":1},{"id":2}]}}`))
+		require.JSONEq(t, `{"name":"user/created","data":{"email":"[REDACTED]","users":[{"ssn":"[REDACTED]","id":1},{"id":2}]}}`, string(out))
+	})
+
+	t.Run("redacts output paths", func(t *testing.T) {
+		out := hook(ctx, KindOutput, []byte(`{"data":[{"token":"secret"},{"token":"other"}],"user":{"password":"hunter2"}}`))
+		require.JSONEq(t, `{"data":[{"token":"[REDACTED]"},{"token":"other"}],"user":{"password":"[REDACTED]"}}`, string(out))
+	})
+
+	t.Run("returns unmatched data unmodified", func(t *testing.T) {
+		in := []byte(`{ "data": {"name": "ok"} }`)
+		require.Equal(t, in, hook(ctx, KindEvent, in))
+	})
+
+	t.Run("keeps the precision of numbers", func(t *testing.T) {
+		out := hook(ctx, KindEvent, []byte(`{"data":{"email":"a@example.com","id":12345678901234567890,"amount":1.10}}`))
+		require.Equal(t, `{"data":{"amount":1.10,"email":"[REDACTED]","id":12345678901234567890}}`, string(out))
+	})
+
+	t.Run("returns invalid JSON unmodified", func(t *testing.T) {
+		in := []byte(`not json`)
+		require.Equal(t, in, hook(ctx, KindOutput, in))
+		in = []byte(`{"user":{"password":"a"}} {}`)
+		require.Equal(t, in, hook(ctx, KindOutput, in))
+	})
+
+	t.Run("empty config is a no-op", func(t *testing.T) {
+		in := []byte(`{"data":{"email":"a@example.com"}}`)
+		require.Equal(t, in, New(Config{})(ctx, KindEvent, in))
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -238,13 +239,23 @@ func (s *svc) InitializeCrons(ctx context.Context) error {
 
 	for _, f := range fns {
 		fn := f
+		// Crons start runs within the workspace that the function is
+		// registered within.
+		wsID, err := s.data.FunctionWorkspaceID(ctx, fn.ID)
+		if err != nil {
+			return err
+		}
 		// Set up a cron schedule for the current function.
 		for _, t := range f.Triggers {
 			if t.CronTrigger == nil {
 				continue
 			}
-			cron := t.CronTrigger.Cron
-			_, err := s.cronmanager.AddFunc(cron, func() {
+			expr := t.CronTrigger.Cron
+			schedule, err := t.CronTrigger.Schedule(fn.ID)
+			if err != nil {
+				return err
+			}
+			s.cronmanager.Schedule(schedule, cron.FuncJob(func() {
 				// Create a new context to avoid "context canceled" errors. This
 				// callback is run as a non-blocking goroutine in Cron.Start, so
 				// contexts from outside its scope will likely be cancelled
@@ -259,13 +270,13 @@ func (s *svc) InitializeCrons(ctx context.Context) error {
 					))
 				defer span.End()
 
-				trackedEvent := event.NewOSSTrackedEvent(event.Event{
+				trackedEvent := event.NewOSSTrackedEventForWorkspace(event.Event{
 					Data: map[string]any{
-						"cron": cron,
+						"cron": expr,
 					},
 					ID:   time.Now().UTC().Format(time.RFC3339),
 					Name: event.FnCronName,
-				})
+				}, wsID)
 
 				byt, err := json.Marshal(trackedEvent)
 				if err == nil {
@@ -289,10 +300,7 @@ func (s *svc) InitializeCrons(ctx context.Context) error {
 				if err != nil {
 					logger.From(ctx).Error().Err(err).Msg("error initializing scheduled function")
 				}
-			})
-			if err != nil {
-				return err
-			}
+			}))
 		}
 	}
 
@@ -362,11 +370,26 @@ func (s *svc) handleMessage(ctx context.Context, m pubsub.Message) error {
 		return fmt.Errorf("error creating event: %w", err)
 	}
 
+	if s.config.EventAPI.DropUnmatchedEvents {
+		// Drop events which don't start or resume any runs, so that high-volume
+		// streams of events aren't stored.
+		matched, err := s.eventMatches(ctx, tracked)
+		if err != nil {
+			return err
+		}
+		if !matched {
+			logger.From(ctx).Debug().
+				Str("event", tracked.GetEvent().Name).
+				Str("internal_id", tracked.GetInternalID().String()).
+				Msg("dropping unmatched event")
+			return nil
+		}
+	}
+
 	// Write the event to our CQRS manager for long-term storage.
-	err = s.cqrs.InsertEvent(
-		ctx,
-		cqrs.ConvertFromEvent(tracked.GetInternalID(), tracked.GetEvent()),
-	)
+	evt := cqrs.ConvertFromEvent(tracked.GetInternalID(), tracked.GetEvent())
+	evt.WorkspaceID = tracked.GetWorkspaceID()
+	err = s.cqrs.InsertEvent(ctx, evt)
 	if err != nil {
 		return err
 	}
@@ -450,7 +473,15 @@ func FindInvokedFunction(ctx context.Context, tracked event.TrackedEvent, fl cqr
 	}
 
 	for _, fn := range fns {
-		if fn.GetSlug() == fnID {
+		if fn.GetSlug() != fnID {
+			continue
+		}
+		// Functions are only invoked within the event's workspace.
+		wsID, err := fl.FunctionWorkspaceID(ctx, fn.ID)
+		if err != nil {
+			return nil, err
+		}
+		if wsID == tracked.GetWorkspaceID() {
 			return &fn, nil
 		}
 	}
@@ -506,7 +537,7 @@ func (s *svc) functions(ctx context.Context, tracked event.TrackedEvent) error {
 		}
 	}()
 
-	fns, err := s.data.FunctionsByTrigger(ctx, evt.Name)
+	fns, err := s.data.FunctionsByTrigger(ctx, tracked.GetWorkspaceID(), evt.Name)
 	if err != nil {
 		return fmt.Errorf("error loading functions by trigger: %w", err)
 	}
@@ -519,15 +550,20 @@ func (s *svc) functions(ctx context.Context, tracked event.TrackedEvent) error {
 	// Do this once instead of many times when evaluating expressions.
 	evtMap := evt.Map()
 
+	// Matched functions are scheduled together, so that each run's state and
+	// start job are created in as few round trips as possible.
+	l := &sync.Mutex{}
+	reqs := []execution.ScheduleRequest{}
+
 	for _, fn := range fns {
-		// We want to initialize each function concurrently;  some of these
+		// We want to evaluate each function concurrently;  some of these
 		// may have expressions that take ~tens of milliseconds to run, and
 		// each function should have as little latency as possible.
 		copied := fn
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			for _, t := range copied.Triggers {
+			for n, t := range copied.Triggers {
 				if t.EventTrigger == nil || t.Event != evt.Name {
 					// This isn't triggered by an event, so we skip this trigger entirely.
 					continue
@@ -549,15 +585,40 @@ func (s *svc) functions(ctx context.Context, tracked event.TrackedEvent) error {
 					}
 				}
 
+				if t.Sample != nil {
+					// Only run the function for the trigger's sample of events.
+					ok, err := t.Sample.Sampled(ctx, evtMap)
+					if err != nil {
+						l.Lock()
+						errs = multierror.Append(errs, err)
+						l.Unlock()
+						continue
+					}
+					if !ok {
+						continue
+					}
+				}
+
 				// Initialize this function for this event only once;  we don't
 				// want multiple matching triggers to run the function more than once.
-				err := s.initialize(ctx, copied, tracked)
+				//
+				// The matched trigger may override the function's batching and
+				// debounce config.
+				trigger := n
+				req, err := s.scheduleRequest(ctx, copied.ForTriggerIndex(n), tracked, &trigger)
 				if err != nil {
 					logger.From(ctx).Error().
 						Err(err).
 						Str("function", copied.Name).
 						Msg("error initializing fn")
+					l.Lock()
 					errs = multierror.Append(errs, err)
+					l.Unlock()
+				}
+				if req != nil {
+					l.Lock()
+					reqs = append(reqs, *req)
+					l.Unlock()
 				}
 				return
 			}
@@ -565,6 +626,21 @@ func (s *svc) functions(ctx context.Context, tracked event.TrackedEvent) error {
 	}
 
 	wg.Wait()
+
+	if len(reqs) == 0 {
+		return errs
+	}
+	_, schedErrs := s.executor.ScheduleMany(ctx, reqs)
+	for n, err := range schedErrs {
+		if err == nil || err == executor.ErrFunctionDebounced || err == executor.ErrFunctionSkipped {
+			continue
+		}
+		logger.From(ctx).Error().
+			Err(err).
+			Str("function", reqs[n].Function.Name).
+			Msg("error initializing fn")
+		errs = multierror.Append(errs, err)
+	}
 	return errs
 }
 
@@ -582,6 +658,30 @@ func (s *svc) invokes(ctx context.Context, evt event.TrackedEvent) error {
 }
 
 // pauses searches for and triggers all pauses from this event.
+// eventMatches returns whether the event triggers any function or matches any
+// pause.  Batches are only appended to by functions triggered by the event.
+// Internal events, eg. invocation and finished events, always match.
+func (s *svc) eventMatches(ctx context.Context, tracked event.TrackedEvent) (bool, error) {
+	evt := tracked.GetEvent()
+	if strings.HasPrefix(strings.ToLower(evt.Name), "inngest/") {
+		return true, nil
+	}
+
+	fns, err := s.data.FunctionsByTrigger(ctx, tracked.GetWorkspaceID(), evt.Name)
+	if err != nil {
+		return false, fmt.Errorf("error loading functions by trigger: %w", err)
+	}
+	if len(fns) > 0 {
+		return true, nil
+	}
+
+	ok, err := s.state.EventHasPauses(ctx, tracked.GetWorkspaceID(), evt.Name)
+	if err != nil {
+		return false, fmt.Errorf("error checking for pauses: %w", err)
+	}
+	return ok, nil
+}
+
 func (s *svc) pauses(ctx context.Context, evt event.TrackedEvent) error {
 	l := logger.From(ctx).With().
 		Str("event", evt.GetEvent().Name).
@@ -591,13 +691,13 @@ func (s *svc) pauses(ctx context.Context, evt event.TrackedEvent) error {
 
 	l.Trace().Msg("querying for pauses")
 
-	if ok, err := s.state.EventHasPauses(ctx, uuid.UUID{}, evt.GetEvent().Name); err == nil && !ok {
+	if ok, err := s.state.EventHasPauses(ctx, evt.GetWorkspaceID(), evt.GetEvent().Name); err == nil && !ok {
 		return nil
 	}
 
 	l.Trace().Msg("pauses found; handling")
 
-	iter, err := s.state.PausesByEvent(ctx, uuid.UUID{}, evt.GetEvent().Name)
+	iter, err := s.state.PausesByEvent(ctx, evt.GetWorkspaceID(), evt.GetEvent().Name)
 	if err != nil {
 		return fmt.Errorf("error finding event pauses: %w", err)
 	}
@@ -607,6 +707,24 @@ func (s *svc) pauses(ctx context.Context, evt event.TrackedEvent) error {
 }
 
 func (s *svc) initialize(ctx context.Context, fn inngest.Function, evt event.TrackedEvent) error {
+	req, err := s.scheduleRequest(ctx, fn, evt, nil)
+	if err != nil || req == nil {
+		return err
+	}
+	_, err = s.executor.Schedule(ctx, *req)
+	if err == executor.ErrFunctionDebounced || err == executor.ErrFunctionSkipped {
+		return nil
+	}
+	return err
+}
+
+// scheduleRequest returns the request used to schedule the given function for
+// the event.  This returns nil if the event is added to a batch, or if the
+// function is rate limited.
+//
+// trigger is the index of the function's trigger which matched the event, if
+// any, which the function's config has been resolved for.
+func (s *svc) scheduleRequest(ctx context.Context, fn inngest.Function, evt event.TrackedEvent, trigger *int) (*execution.ScheduleRequest, error) {
 	if fn.IsBatchEnabled() {
 		bi := batch.BatchItem{
 			WorkspaceID:     evt.GetWorkspaceID(),
@@ -614,28 +732,29 @@ func (s *svc) initialize(ctx context.Context, fn inngest.Function, evt event.Tra
 			FunctionVersion: fn.FunctionVersion,
 			EventID:         evt.GetInternalID(),
 			Event:           evt.GetEvent(),
+			Trigger:         trigger,
 		}
 
 		if err := s.executor.AppendAndScheduleBatch(ctx, fn, bi); err != nil {
-			return fmt.Errorf("could not append and schedule batch item: %w", err)
+			return nil, fmt.Errorf("could not append and schedule batch item: %w", err)
 		}
 
-		return nil
+		return nil, nil
 	}
 
 	// Attempt to rate-limit the incoming function.
 	if s.rl != nil && fn.RateLimit != nil {
 		key, err := ratelimit.RateLimitKey(ctx, fn.ID, *fn.RateLimit, evt.GetEvent().Map())
 		if err != nil {
-			return err
+			return nil, err
 		}
 		limited, _, err := s.rl.RateLimit(ctx, key, *fn.RateLimit)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		if limited {
 			// Do nothing.
-			return nil
+			return nil, nil
 		}
 	}
 
@@ -643,11 +762,8 @@ func (s *svc) initialize(ctx context.Context, fn inngest.Function, evt event.Tra
 		Str("function_id", fn.ID.String()).
 		Str("function", fn.Name).
 		Msg("initializing fn")
-	_, err := Initialize(ctx, fn, evt, s.executor)
-	if err == executor.ErrFunctionDebounced {
-		return nil
-	}
-	return err
+	req := newScheduleRequest(fn, evt)
+	return &req, nil
 }
 
 // Initialize creates a new funciton run identifier for the given workflow and
@@ -657,6 +773,12 @@ func (s *svc) initialize(ctx context.Context, fn inngest.Function, evt event.Tra
 // This is a separate, exported function so that it can be used from this service
 // and also from eg. the run command.
 func Initialize(ctx context.Context, fn inngest.Function, tracked event.TrackedEvent, e execution.Executor) (*state.Identifier, error) {
+	return e.Schedule(ctx, newScheduleRequest(fn, tracked))
+}
+
+// newScheduleRequest returns the request used to schedule the function for the
+// given event.
+func newScheduleRequest(fn inngest.Function, tracked event.TrackedEvent) execution.ScheduleRequest {
 	zero := uuid.UUID{}
 	if bytes.Equal(fn.ID[:], zero[:]) {
 		// Locally, we want to ensure that each function has its own deterministic
@@ -670,13 +792,12 @@ func Initialize(ctx context.Context, fn inngest.Function, tracked event.TrackedE
 	// use the internal event ID
 	idempotencyKey := tracked.GetEvent().ID
 
-	// If this is a debounced function, run this through a debouncer.
-
-	return e.Schedule(ctx, execution.ScheduleRequest{
+	return execution.ScheduleRequest{
 		Function:       fn,
+		WorkspaceID:    tracked.GetWorkspaceID(),
 		Events:         []event.TrackedEvent{tracked},
 		IdempotencyKey: &idempotencyKey,
-	})
+	}
 }
 
 // NewTracker returns a crappy in-memory tracker used for registering function runs.
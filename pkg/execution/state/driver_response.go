@@ -57,6 +57,21 @@ type GeneratorOpcode struct {
 	Error *UserError `json:"error"`
 	// SDK versions < 3.?.? don't respond with the display name.
 	DisplayName *string `json:"displayName"`
+	// Logs are structured log lines emitted by the SDK while running the
+	// operation, eg. via the step's logger.
+	Logs []LogLine `json:"logs,omitempty"`
+}
+
+// LogLine is a structured log line emitted by the SDK during a step.
+type LogLine struct {
+	// Level is the log level, eg. "debug", "info", "warn" or "error".
+	Level string `json:"level"`
+	// Message is the log message.
+	Message string `json:"msg"`
+	// Fields are additional structured fields logged with the message.
+	Fields map[string]any `json:"fields,omitempty"`
+	// Timestamp is the time the line was logged by the SDK.
+	Timestamp time.Time `json:"ts"`
 }
 
 // Get the name of the step as defined in code by the user.
@@ -162,6 +177,89 @@ func (g GeneratorOpcode) InvokeFunctionOpts() (*InvokeFunctionOpts, error) {
 	return opts, nil
 }
 
+func (g GeneratorOpcode) AIGatewayOpts() (*AIGatewayOpts, error) {
+	opts := &AIGatewayOpts{}
+	if err := opts.UnmarshalAny(g.Opts); err != nil {
+		return nil, err
+	}
+	if opts.Provider == "" {
+		return nil, fmt.Errorf("A provider must be specified for inference requests")
+	}
+	if len(opts.Body) == 0 {
+		return nil, fmt.Errorf("A body must be specified for inference requests")
+	}
+	return opts, nil
+}
+
+// AIGatewayOpts are the options for OpcodeAIGateway, describing the inference
+// request that the executor makes on behalf of the SDK.
+type AIGatewayOpts struct {
+	// Provider is the name of the model provider, eg. "openai".
+	Provider string `json:"provider"`
+	// Body is the request body sent to the provider as-is.
+	Body json.RawMessage `json:"body"`
+}
+
+func (a *AIGatewayOpts) UnmarshalAny(i any) error {
+	opts := AIGatewayOpts{}
+	var mappedByt []byte
+	switch typ := i.(type) {
+	case []byte:
+		mappedByt = typ
+	default:
+		byt, err := json.Marshal(i)
+		if err != nil {
+			return err
+		}
+		mappedByt = byt
+	}
+	if err := json.Unmarshal(mappedByt, &opts); err != nil {
+		return err
+	}
+	*a = opts
+	return nil
+}
+
+func (g GeneratorOpcode) GatewayOpts() (*GatewayOpts, error) {
+	opts := &GatewayOpts{}
+	if err := opts.UnmarshalAny(g.Opts); err != nil {
+		return nil, err
+	}
+	if opts.URL == "" {
+		return nil, fmt.Errorf("A URL must be specified for gateway requests")
+	}
+	return opts, nil
+}
+
+// GatewayOpts are the options for OpcodeGateway, describing the HTTP request that
+// the executor makes on behalf of the SDK.
+type GatewayOpts struct {
+	URL     string            `json:"url"`
+	Method  string            `json:"method"`
+	Headers map[string]string `json:"headers"`
+	Body    string            `json:"body"`
+}
+
+func (g *GatewayOpts) UnmarshalAny(i any) error {
+	opts := GatewayOpts{}
+	var mappedByt []byte
+	switch typ := i.(type) {
+	case []byte:
+		mappedByt = typ
+	default:
+		byt, err := json.Marshal(i)
+		if err != nil {
+			return err
+		}
+		mappedByt = byt
+	}
+	if err := json.Unmarshal(mappedByt, &opts); err != nil {
+		return err
+	}
+	*g = opts
+	return nil
+}
+
 type InvokeFunctionOpts struct {
 	FunctionID string       `json:"function_id"`
 	Payload    *event.Event `json:"payload,omitempty"`
@@ -317,6 +415,14 @@ type DriverResponse struct {
 	// SDK represents the SDK language and version used for these
 	// functions, in the format: "js:v0.1.0"
 	SDK string `json:"sdk,omitempty"`
+	// Checkpointed lists the IDs of steps whose outputs were saved while the
+	// request was in progress, as the SDK streamed them.  These steps may also
+	// be included in Generator, and must not be saved again.
+	Checkpointed []string `json:"checkpointed,omitempty"`
+	// Logs are log lines emitted by the SDK outside of any generator opcode,
+	// eg. when function code errors and the SDK responds with the error
+	// rather than an opcode.
+	Logs []LogLine `json:"logs,omitempty"`
 
 	// final indicates whether the error has been marked as final.  This occurs
 	// when the response errors and the executor detects that this is the final
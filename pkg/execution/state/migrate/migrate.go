@@ -0,0 +1,263 @@
+package migrate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/inngest/inngest/pkg/execution/state"
+	"github.com/inngest/inngest/pkg/inngest"
+	"github.com/oklog/ulid/v2"
+)
+
+// Opts configures a migration.
+type Opts struct {
+	// DryRun loads and validates every run and pause without writing to the
+	// destination store.
+	DryRun bool
+	// OnError is called when a run or pause fails to migrate.  If nil, the
+	// migration stops at the first error.  Otherwise the migration continues
+	// and the failure is counted within the result.
+	OnError func(err error)
+}
+
+// Result summarizes a migration.
+type Result struct {
+	// Runs is the number of runs migrated.
+	Runs int
+	// Steps is the number of step outputs migrated.
+	Steps int
+	// Pauses is the number of pauses migrated.
+	Pauses int
+	// Skipped is the number of runs and pauses which already existed within
+	// the destination store.
+	Skipped int
+	// Failed is the number of runs and pauses which failed to migrate.
+	Failed int
+}
+
+// Migrate copies every run's state, including its metadata, stack, and step
+// outputs, and every pause from one state store to another, verifying each run
+// once copied.  Runs and pauses which already exist within the destination are
+// skipped, so migrations may be re-run to copy runs started while migrating.
+//
+// Step outputs are saved in stack order, so that the destination's stack matches
+// the source's and SDKs continue to memoize steps correctly.
+func Migrate(ctx context.Context, from state.Manager, to state.Manager, opts Opts) (*Result, error) {
+	lister, ok := from.(state.Lister)
+	if !ok {
+		return nil, fmt.Errorf("state store %T does not support listing runs", from)
+	}
+
+	res := &Result{}
+	fail := func(err error) error {
+		if opts.OnError == nil {
+			return err
+		}
+		res.Failed++
+		opts.OnError(err)
+		return nil
+	}
+
+	err := lister.RunIDs(ctx, func(runID ulid.ULID) error {
+		steps, err := migrateRun(ctx, from, to, runID, opts.DryRun)
+		switch {
+		case errors.Is(err, state.ErrIdentifierExists):
+			res.Skipped++
+			return nil
+		case err != nil:
+			return fail(fmt.Errorf("error migrating run %s: %w", runID, err))
+		}
+		res.Runs++
+		res.Steps += steps
+		return nil
+	})
+	if err != nil {
+		return res, err
+	}
+
+	err = lister.Pauses(ctx, func(p *state.Pause) error {
+		if opts.DryRun {
+			res.Pauses++
+			return nil
+		}
+		err := to.SavePause(ctx, *p)
+		switch {
+		case errors.Is(err, state.ErrPauseAlreadyExists):
+			res.Skipped++
+			return nil
+		case err != nil:
+			return fail(fmt.Errorf("error migrating pause %s: %w", p.ID, err))
+		}
+		res.Pauses++
+		return nil
+	})
+	return res, err
+}
+
+// migrateRun copies a single run, returning the number of steps copied.
+func migrateRun(ctx context.Context, from state.Manager, to state.Manager, runID ulid.ULID, dryRun bool) (int, error) {
+	src, err := from.Load(ctx, runID)
+	if err != nil {
+		return 0, fmt.Errorf("error loading run: %w", err)
+	}
+	md := src.Metadata()
+	id := src.Identifier()
+
+	outputs := make([]string, len(src.Stack()))
+	for n, stepID := range src.Stack() {
+		output, ok := src.Actions()[stepID]
+		if !ok {
+			return 0, fmt.Errorf("step %s is within the stack but has no output", stepID)
+		}
+		byt, err := json.Marshal(output)
+		if err != nil {
+			return 0, fmt.Errorf("error marshalling output for step %s: %w", stepID, err)
+		}
+		outputs[n] = string(byt)
+	}
+	if dryRun {
+		return len(outputs), nil
+	}
+
+	_, err = to.New(ctx, state.Input{
+		Identifier:     id,
+		EventBatchData: src.Events(),
+		Debugger:       md.Debugger,
+		RunType:        md.RunType,
+		Context:        md.Context,
+		SpanID:         md.SpanID,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for n, stepID := range src.Stack() {
+		if err := to.SaveResponse(ctx, id, stepID, outputs[n]); err != nil {
+			return 0, fmt.Errorf("error saving output for step %s: %w", stepID, err)
+		}
+	}
+
+	err = to.UpdateMetadata(ctx, runID, state.MetadataUpdate{
+		Debugger:                  md.Debugger,
+		Context:                   md.Context,
+		DisableImmediateExecution: md.DisableImmediateExecution,
+		RequestVersion:            md.RequestVersion,
+		SpanID:                    md.SpanID,
+		StartedAt:                 md.StartedAt,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("error updating metadata: %w", err)
+	}
+	if err := to.SetStatus(ctx, id, md.Status); err != nil {
+		return 0, fmt.Errorf("error setting status: %w", err)
+	}
+
+	dst, err := to.Load(ctx, runID)
+	if err != nil {
+		return 0, fmt.Errorf("error loading migrated run: %w", err)
+	}
+	if err := verify(src, dst); err != nil {
+		return 0, fmt.Errorf("error verifying migrated run: %w", err)
+	}
+	return len(outputs), nil
+}
+
+// Resync returns a function which replicates a run's full state from one state
+// store to another, for use as state.AsyncReplicatorOpts.Resync.  Runs which
+// already exist within the destination have any missing step outputs saved and
+// their metadata and status updated, and runs which no longer exist within the
+// source are deleted from the destination.
+func Resync(from state.Manager, to state.Manager) func(ctx context.Context, runID ulid.ULID) error {
+	return func(ctx context.Context, runID ulid.ULID) error {
+		exists, err := from.Exists(ctx, runID)
+		if err != nil {
+			return fmt.Errorf("error checking run: %w", err)
+		}
+		if !exists {
+			dst, err := to.Load(ctx, runID)
+			if err != nil {
+				// The run doesn't exist within either store.
+				return nil
+			}
+			return to.Delete(ctx, dst.Identifier())
+		}
+
+		_, err = migrateRun(ctx, from, to, runID, false)
+		if !errors.Is(err, state.ErrIdentifierExists) {
+			return err
+		}
+		return resyncRun(ctx, from, to, runID)
+	}
+}
+
+// resyncRun updates a run which exists within the destination to match the
+// source.
+func resyncRun(ctx context.Context, from state.Manager, to state.Manager, runID ulid.ULID) error {
+	src, err := from.Load(ctx, runID)
+	if err != nil {
+		return fmt.Errorf("error loading run: %w", err)
+	}
+	md := src.Metadata()
+	id := src.Identifier()
+
+	for _, stepID := range src.Stack() {
+		byt, err := json.Marshal(src.Actions()[stepID])
+		if err != nil {
+			return fmt.Errorf("error marshalling output for step %s: %w", stepID, err)
+		}
+		err = to.SaveResponse(ctx, id, stepID, string(byt))
+		if err != nil && !errors.Is(err, state.ErrDuplicateResponse) {
+			return fmt.Errorf("error saving output for step %s: %w", stepID, err)
+		}
+	}
+
+	err = to.UpdateMetadata(ctx, runID, state.MetadataUpdate{
+		Debugger:                  md.Debugger,
+		Context:                   md.Context,
+		DisableImmediateExecution: md.DisableImmediateExecution,
+		RequestVersion:            md.RequestVersion,
+		SpanID:                    md.SpanID,
+		StartedAt:                 md.StartedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("error updating metadata: %w", err)
+	}
+	if err := to.SetStatus(ctx, id, md.Status); err != nil {
+		return fmt.Errorf("error setting status: %w", err)
+	}
+	return nil
+}
+
+// verify checks that the run's state within the destination matches the source.
+func verify(expected state.State, actual state.State) error {
+	if !reflect.DeepEqual(expected.Stack(), actual.Stack()) {
+		return fmt.Errorf("stack mismatch: expected %v, got %v", expected.Stack(), actual.Stack())
+	}
+	for _, stepID := range expected.Stack() {
+		a, _ := json.Marshal(expected.Actions()[stepID])
+		b, _ := json.Marshal(actual.Actions()[stepID])
+		if string(a) != string(b) {
+			return fmt.Errorf("output mismatch for step %s", stepID)
+		}
+	}
+	if len(expected.Events()) != len(actual.Events()) {
+		return fmt.Errorf("event mismatch: expected %d events, got %d", len(expected.Events()), len(actual.Events()))
+	}
+	if expected.Metadata().Status != actual.Metadata().Status {
+		return fmt.Errorf("status mismatch: expected %s, got %s", expected.Metadata().Status, actual.Metadata().Status)
+	}
+	return nil
+}
+
+// FunctionLoader is a state.FunctionLoader which returns an empty function for
+// every run.  State stores only use functions when loading and creating runs,
+// which isn't necessary when migrating state.
+type FunctionLoader struct{}
+
+// LoadFunction returns an empty function with the run's function ID.
+func (FunctionLoader) LoadFunction(ctx context.Context, id state.Identifier) (*inngest.Function, error) {
+	return &inngest.Function{ID: id.WorkflowID}, nil
+}
@@ -0,0 +1,139 @@
+package migrate
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/inngest/inngest/pkg/enums"
+	"github.com/inngest/inngest/pkg/execution/state"
+	"github.com/inngest/inngest/pkg/execution/state/redis_state"
+	"github.com/oklog/ulid/v2"
+	"github.com/redis/rueidis"
+	"github.com/stretchr/testify/require"
+)
+
+func newManager(t *testing.T, r *miniredis.Miniredis, prefix string) state.Manager {
+	rc, err := rueidis.NewClient(rueidis.ClientOption{
+		InitAddress:  []string{r.Addr()},
+		DisableCache: true,
+	})
+	require.NoError(t, err)
+	sm, err := redis_state.New(
+		context.Background(),
+		redis_state.WithRedisClient(rc),
+		redis_state.WithFunctionLoader(FunctionLoader{}),
+		redis_state.WithKeyGenerator(redis_state.DefaultKeyFunc{Prefix: prefix}),
+	)
+	require.NoError(t, err)
+	return sm
+}
+
+func TestMigrate(t *testing.T) {
+	ctx := context.Background()
+	r := miniredis.RunT(t)
+	from := newManager(t, r, "{from}")
+	to := newManager(t, r, "{to}")
+
+	id := state.Identifier{
+		WorkflowID: uuid.New(),
+		RunID:      ulid.MustNew(ulid.Now(), nil),
+		AccountID:  uuid.New(),
+	}
+	_, err := from.New(ctx, state.Input{
+		Identifier:     id,
+		EventBatchData: []map[string]any{{"name": "test/event", "data": map[string]any{"ok": true}}},
+		Context:        map[string]any{"key": "value"},
+	})
+	require.NoError(t, err)
+	require.NoError(t, from.SaveResponse(ctx, id, "first", `{"data":1}`))
+	require.NoError(t, from.SaveResponse(ctx, id, "second", `{"data":"two"}`))
+	require.NoError(t, from.SetStatus(ctx, id, enums.RunStatusRunning))
+
+	pause := state.Pause{
+		ID:         uuid.New(),
+		Identifier: id,
+		Outgoing:   "third",
+		Incoming:   "third",
+		Expires:    state.Time(time.Now().Add(time.Hour)),
+	}
+	require.NoError(t, from.SavePause(ctx, pause))
+
+	t.Run("dry runs don't write state", func(t *testing.T) {
+		res, err := Migrate(ctx, from, to, Opts{DryRun: true})
+		require.NoError(t, err)
+		require.Equal(t, Result{Runs: 1, Steps: 2, Pauses: 1}, *res)
+
+		exists, err := to.Exists(ctx, id.RunID)
+		require.NoError(t, err)
+		require.False(t, exists)
+	})
+
+	t.Run("it copies runs and pauses", func(t *testing.T) {
+		res, err := Migrate(ctx, from, to, Opts{})
+		require.NoError(t, err)
+		require.Equal(t, Result{Runs: 1, Steps: 2, Pauses: 1}, *res)
+
+		st, err := to.Load(ctx, id.RunID)
+		require.NoError(t, err)
+		require.Equal(t, []string{"first", "second"}, st.Stack())
+		require.Equal(t, map[string]any{"data": float64(1)}, st.Actions()["first"])
+		require.Equal(t, map[string]any{"data": "two"}, st.Actions()["second"])
+		require.Equal(t, enums.RunStatusRunning, st.Metadata().Status)
+		require.Equal(t, "value", st.Metadata().Context["key"])
+
+		p, err := to.PauseByID(ctx, pause.ID)
+		require.NoError(t, err)
+		require.Equal(t, id, p.Identifier)
+	})
+
+	t.Run("existing runs and pauses are skipped", func(t *testing.T) {
+		res, err := Migrate(ctx, from, to, Opts{})
+		require.NoError(t, err)
+		require.Equal(t, Result{Skipped: 2}, *res)
+	})
+}
+
+func TestResync(t *testing.T) {
+	ctx := context.Background()
+	r := miniredis.RunT(t)
+	from := newManager(t, r, "{from}")
+	to := newManager(t, r, "{to}")
+	resync := Resync(from, to)
+
+	id := state.Identifier{
+		WorkflowID: uuid.New(),
+		RunID:      ulid.MustNew(ulid.Now(), nil),
+		AccountID:  uuid.New(),
+	}
+	_, err := from.New(ctx, state.Input{
+		Identifier:     id,
+		EventBatchData: []map[string]any{{"name": "test/event"}},
+	})
+	require.NoError(t, err)
+	require.NoError(t, from.SaveResponse(ctx, id, "first", `{"data":1}`))
+
+	// Runs which don't exist within the destination are copied.
+	require.NoError(t, resync(ctx, id.RunID))
+	st, err := to.Load(ctx, id.RunID)
+	require.NoError(t, err)
+	require.Equal(t, []string{"first"}, st.Stack())
+
+	// Runs which exist are updated.
+	require.NoError(t, from.SaveResponse(ctx, id, "second", `{"data":2}`))
+	require.NoError(t, from.SetStatus(ctx, id, enums.RunStatusCompleted))
+	require.NoError(t, resync(ctx, id.RunID))
+	st, err = to.Load(ctx, id.RunID)
+	require.NoError(t, err)
+	require.Equal(t, []string{"first", "second"}, st.Stack())
+	require.Equal(t, enums.RunStatusCompleted, st.Metadata().Status)
+
+	// Runs deleted from the source are deleted from the destination.
+	require.NoError(t, from.Delete(ctx, id))
+	require.NoError(t, resync(ctx, id.RunID))
+	exists, err := to.Exists(ctx, id.RunID)
+	require.NoError(t, err)
+	require.False(t, exists)
+}
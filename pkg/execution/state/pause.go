@@ -2,6 +2,7 @@ package state
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/inngest/inngest/pkg/enums"
@@ -140,6 +141,10 @@ type Pause struct {
 	// not received.  Therefore, we must be able to load the pause for some
 	// time after timeout.
 	Expires Time `json:"expires"`
+	// NeverExpires indicates that the pause never expires, eg. for cancellations of
+	// runs which track long-lived entities.  Expires is ignored for these pauses,
+	// which are removed once their run ends.
+	NeverExpires bool `json:"nx,omitempty"`
 	// Event is an optional event that can resume the pause automatically,
 	// often paired with an expression.
 	Event *string `json:"event"`
@@ -192,6 +197,11 @@ type Pause struct {
 	TraceStartedAt *Time `json:"tsa"`
 }
 
+// Expired returns whether the pause has expired at the given time.
+func (p Pause) Expired(now time.Time) bool {
+	return !p.NeverExpires && p.Expires.Time().Before(now)
+}
+
 func (p Pause) GetID() uuid.UUID {
 	return p.ID
 }
@@ -152,6 +152,89 @@ func (d DefaultKeyFunc) Stack(ctx context.Context, runID ulid.ULID) string {
 	return fmt.Sprintf("%s:stack:%s", d.Prefix, runID)
 }
 
+// ClusterKeyFunc generates keys which are safe to use with Redis Cluster.
+//
+// All keys for a run are hash tagged with the run ID, ensuring that a run's
+// state lives within a single slot while runs are distributed across every
+// shard in the cluster.  Pause keys are accessed together within the same
+// scripts and therefore share a single hash tag.
+type ClusterKeyFunc struct {
+	Prefix string
+}
+
+func (c ClusterKeyFunc) Idempotency(ctx context.Context, id state.Identifier) string {
+	return fmt.Sprintf("%s:key:%s", c.Prefix, id.IdempotencyKey())
+}
+
+func (c ClusterKeyFunc) RunMetadata(ctx context.Context, runID ulid.ULID) string {
+	return fmt.Sprintf("%s:metadata:{%s}", c.Prefix, runID)
+}
+
+func (c ClusterKeyFunc) Workflow(ctx context.Context, id uuid.UUID, version int) string {
+	return fmt.Sprintf("%s:workflows:%s-%d", c.Prefix, id, version)
+}
+
+func (c ClusterKeyFunc) Event(ctx context.Context, id state.Identifier) string {
+	return fmt.Sprintf("%s:events:%s:{%s}", c.Prefix, id.WorkflowID, id.RunID)
+}
+
+func (c ClusterKeyFunc) Events(ctx context.Context, id state.Identifier) string {
+	return fmt.Sprintf("%s:bulk-events:%s:{%s}", c.Prefix, id.WorkflowID, id.RunID)
+}
+
+func (c ClusterKeyFunc) Actions(ctx context.Context, id state.Identifier) string {
+	return fmt.Sprintf("%s:actions:%s:{%s}", c.Prefix, id.WorkflowID, id.RunID)
+}
+
+func (c ClusterKeyFunc) Errors(ctx context.Context, id state.Identifier) string {
+	return fmt.Sprintf("%s:errors:%s:{%s}", c.Prefix, id.WorkflowID, id.RunID)
+}
+
+func (c ClusterKeyFunc) History(ctx context.Context, runID ulid.ULID) string {
+	return fmt.Sprintf("%s:history:{%s}", c.Prefix, runID)
+}
+
+func (c ClusterKeyFunc) Stack(ctx context.Context, runID ulid.ULID) string {
+	return fmt.Sprintf("%s:stack:{%s}", c.Prefix, runID)
+}
+
+// pauses returns the hash tagged prefix used for all pause keys.
+func (c ClusterKeyFunc) pauses() string {
+	return fmt.Sprintf("{%s:pauses}", c.Prefix)
+}
+
+func (c ClusterKeyFunc) PauseID(ctx context.Context, id uuid.UUID) string {
+	return fmt.Sprintf("%s:id:%s", c.pauses(), id.String())
+}
+
+func (c ClusterKeyFunc) PauseLease(ctx context.Context, id uuid.UUID) string {
+	return fmt.Sprintf("%s:lease:%s", c.pauses(), id.String())
+}
+
+func (c ClusterKeyFunc) PauseEvent(ctx context.Context, workspaceID uuid.UUID, event string) string {
+	return fmt.Sprintf("%s:events:%s:%s", c.pauses(), workspaceID, event)
+}
+
+func (c ClusterKeyFunc) PauseStepPrefix(ctx context.Context, id state.Identifier) string {
+	return fmt.Sprintf("%s:steps:%s", c.pauses(), id.RunID)
+}
+
+func (c ClusterKeyFunc) PauseStep(ctx context.Context, id state.Identifier, step string) string {
+	prefix := c.PauseStepPrefix(ctx, id)
+	return fmt.Sprintf("%s-%s", prefix, step)
+}
+
+func (c ClusterKeyFunc) PauseIndex(ctx context.Context, kind string, wsID uuid.UUID, event string) string {
+	if event == "" {
+		return fmt.Sprintf("%s:idx:%s:%s:-", c.pauses(), kind, wsID)
+	}
+	return fmt.Sprintf("%s:idx:%s:%s:%s", c.pauses(), kind, wsID, event)
+}
+
+func (c ClusterKeyFunc) Invoke(ctx context.Context, wsID uuid.UUID) string {
+	return fmt.Sprintf("%s:invoke:%s", c.pauses(), wsID)
+}
+
 type QueueKeyGenerator interface {
 	// QueueItem returns the key for the hash containing all items within a
 	// queue for a function.
@@ -180,6 +263,18 @@ type QueueKeyGenerator interface {
 	ShardPartitionIndex(shard string) string
 	// ThrottleKey returns the throttle key for a given queue item.
 	ThrottleKey(t *osqueue.Throttle) string
+	// ThrottleOverrides returns the hash storing throttle configuration overrides
+	// for functions, keyed by function ID.
+	ThrottleOverrides() string
+	// ConcurrencyOverrides returns the hash storing custom concurrency limit
+	// overrides for functions, keyed by function ID.
+	ConcurrencyOverrides() string
+	// OrderedLock returns the key storing the ID of the run which holds the
+	// given ordered concurrency key.
+	OrderedLock(key string) string
+	// PausedPartitions returns the hash storing the partitions which are paused
+	// and mustn't be leased, keyed by partition name.
+	PausedPartitions() string
 
 	//
 	// Queue metadata keys
@@ -208,6 +303,11 @@ type QueueKeyGenerator interface {
 	// Status returns the key used for status queue for the provided function.
 	Status(status string, fnID uuid.UUID) string
 
+	// ConcurrencyKeyCardinality returns the key for the HyperLogLog counting
+	// the distinct keys used for one of a function's custom concurrency limits
+	// within the given window.
+	ConcurrencyKeyCardinality(fnID uuid.UUID, hash string, window int64) string
+
 	// ***************** Deprecated ************************
 	BatchPointer(context.Context, uuid.UUID) string
 	Batch(context.Context, ulid.ULID) string
@@ -235,6 +335,13 @@ type BatchKeyGenerator interface {
 	// BatchPointer returns the key used as the pointer reference to the
 	// actual batch
 	BatchPointer(context.Context, uuid.UUID) string
+	// BatchPointerSlot returns the key used as the pointer reference for one
+	// of a function's concurrently open batches.  Slot 0 is the same key as
+	// BatchPointer.
+	BatchPointerSlot(context.Context, uuid.UUID, int) string
+	// BatchPending returns the key for the sorted set of a function's pending
+	// batches, which hold items but haven't yet started.
+	BatchPending(context.Context, uuid.UUID) string
 	// Batch returns the key used to store the specific batch of
 	// events, that is used to trigger a function run
 	Batch(context.Context, ulid.ULID) string
@@ -243,6 +350,20 @@ type BatchKeyGenerator interface {
 	BatchMetadata(context.Context, ulid.ULID) string
 }
 
+// NamespacedPrefix returns the given key prefix within a namespace, eg.
+// "staging:{queue}", isolating keys from other installs or environments which
+// share the same Redis instance or cluster.  The namespace is added outside of
+// any hash tag, so that keys sharing a slot continue to do so.
+func NamespacedPrefix(namespace, prefix string) string {
+	if namespace == "" {
+		return prefix
+	}
+	return namespace + ":" + prefix
+}
+
+// DefaultQueueKeyGenerator generates keys for the queue.  Queue scripts access
+// keys across many partitions;  when using Redis Cluster the Prefix must contain
+// a hash tag (eg. "{queue}") so that every queue key lives within a single slot.
 type DefaultQueueKeyGenerator struct {
 	Prefix string
 }
@@ -289,6 +410,22 @@ func (d DefaultQueueKeyGenerator) ThrottleKey(t *osqueue.Throttle) string {
 	return fmt.Sprintf("%s:throttle:%s", d.Prefix, t.Key)
 }
 
+func (d DefaultQueueKeyGenerator) ThrottleOverrides() string {
+	return fmt.Sprintf("%s:throttle:overrides", d.Prefix)
+}
+
+func (d DefaultQueueKeyGenerator) ConcurrencyOverrides() string {
+	return fmt.Sprintf("%s:concurrency:overrides", d.Prefix)
+}
+
+func (d DefaultQueueKeyGenerator) OrderedLock(key string) string {
+	return fmt.Sprintf("%s:ordered:%s", d.Prefix, key)
+}
+
+func (d DefaultQueueKeyGenerator) PausedPartitions() string {
+	return fmt.Sprintf("%s:partition:paused", d.Prefix)
+}
+
 func (d DefaultQueueKeyGenerator) PartitionMeta(id string) string {
 	return fmt.Sprintf("%s:partition:meta:%s", d.Prefix, id)
 }
@@ -325,6 +462,17 @@ func (d DefaultQueueKeyGenerator) BatchPointer(ctx context.Context, workflowID u
 	return fmt.Sprintf("%s:workflows:%s:batch", d.Prefix, workflowID)
 }
 
+func (d DefaultQueueKeyGenerator) BatchPointerSlot(ctx context.Context, workflowID uuid.UUID, slot int) string {
+	if slot <= 0 {
+		return d.BatchPointer(ctx, workflowID)
+	}
+	return fmt.Sprintf("%s:%d", d.BatchPointer(ctx, workflowID), slot)
+}
+
+func (d DefaultQueueKeyGenerator) BatchPending(ctx context.Context, workflowID uuid.UUID) string {
+	return fmt.Sprintf("%s:workflows:%s:batches:pending", d.Prefix, workflowID)
+}
+
 func (d DefaultQueueKeyGenerator) Batch(ctx context.Context, batchID ulid.ULID) string {
 	return fmt.Sprintf("%s:batches:%s", d.Prefix, batchID)
 }
@@ -349,6 +497,10 @@ func (d DefaultQueueKeyGenerator) RunIndex(runID ulid.ULID) string {
 	return fmt.Sprintf("%s:idx:run:%s", d.Prefix, runID)
 }
 
+func (d DefaultQueueKeyGenerator) ConcurrencyKeyCardinality(fnID uuid.UUID, hash string, window int64) string {
+	return fmt.Sprintf("%s:cck-count:%s:%s:%d", d.Prefix, fnID, hash, window)
+}
+
 func (d DefaultQueueKeyGenerator) Status(status string, fnID uuid.UUID) string {
 	return fmt.Sprintf("%s:queue:status:%s:%s", d.Prefix, fnID, status)
 }
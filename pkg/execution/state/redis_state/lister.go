@@ -0,0 +1,93 @@
+package redis_state
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/inngest/inngest/pkg/execution/state"
+	"github.com/oklog/ulid/v2"
+	"github.com/redis/rueidis"
+)
+
+// scanCount is the number of keys requested in each SCAN call when listing state.
+const scanCount = 1000
+
+var _ state.Lister = mgr{}
+
+// RunIDs calls f with the ID of each run held within the store, scanning for run
+// metadata keys.
+func (m mgr) RunIDs(ctx context.Context, f func(runID ulid.ULID) error) error {
+	zero := ulid.ULID{}
+	pattern := strings.Replace(m.kf.RunMetadata(ctx, zero), zero.String(), "*", 1)
+	return scanKeys(ctx, m.r, pattern, func(_ rueidis.Client, id string) error {
+		runID, err := ulid.Parse(id)
+		if err != nil {
+			// Ignore keys which happen to match the pattern.
+			return nil
+		}
+		return f(runID)
+	})
+}
+
+// Pauses calls f with each pause held within the store, scanning for pause keys
+// within every pause shard.
+func (m mgr) Pauses(ctx context.Context, f func(p *state.Pause) error) error {
+	zero := uuid.UUID{}
+	pattern := strings.Replace(m.kf.PauseID(ctx, zero), zero.String(), "*", 1)
+	for _, client := range m.pauseClients() {
+		err := scanKeys(ctx, client, pattern, func(node rueidis.Client, id string) error {
+			pauseID, err := uuid.Parse(id)
+			if err != nil {
+				return nil
+			}
+			str, err := node.Do(ctx, node.B().Get().Key(m.kf.PauseID(ctx, pauseID)).Build()).ToString()
+			if rueidis.IsRedisNil(err) {
+				// The pause was consumed or expired while scanning.
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("error loading pause %s: %w", pauseID, err)
+			}
+			pause := &state.Pause{}
+			if err := json.Unmarshal([]byte(str), pause); err != nil {
+				return fmt.Errorf("error unmarshalling pause %s: %w", pauseID, err)
+			}
+			return f(pause)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// scanKeys scans every node of the given client for keys matching the pattern,
+// which must contain a single "*" wildcard.  f is called with the node holding
+// each key and the part of the key matched by the wildcard.
+func scanKeys(ctx context.Context, r rueidis.Client, pattern string, f func(node rueidis.Client, match string) error) error {
+	prefix, suffix, _ := strings.Cut(pattern, "*")
+	for _, node := range r.Nodes() {
+		cursor := uint64(0)
+		for {
+			cmd := node.B().Scan().Cursor(cursor).Match(pattern).Count(scanCount).Build()
+			entry, err := node.Do(ctx, cmd).AsScanEntry()
+			if err != nil {
+				return fmt.Errorf("error scanning keys: %w", err)
+			}
+			for _, key := range entry.Elements {
+				match := strings.TrimSuffix(strings.TrimPrefix(key, prefix), suffix)
+				if err := f(node, match); err != nil {
+					return err
+				}
+			}
+			if entry.Cursor == 0 {
+				break
+			}
+			cursor = entry.Cursor
+		}
+	}
+	return nil
+}
@@ -13,8 +13,8 @@ local metadataKey = KEYS[1]
 local value = tonumber(redis.call("HGET", metadataKey, "status"))
 
 -- If run has ended (cancelled, completed, failed). We only allow cancellation
--- of scheduled or running functions
-if value ~= 0 and value ~= 5 then
+-- of scheduled, running, waiting, sleeping or throttled functions
+if value ~= 0 and value ~= 5 and value ~= 7 and value ~= 8 and value ~= 9 then
 	-- Return the function status as an error
 	return value;
 end
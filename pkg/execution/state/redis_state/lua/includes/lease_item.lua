@@ -0,0 +1,172 @@
+-- Leases a single queue item, returning the status documented within lease.lua.
+-- keys and args are the KEYS and ARGV of a single lease.lua call.  This requires
+-- the decode_ulid_time, check_concurrency, get_queue_item, set_item_peek_time,
+-- update_pointer_score, has_shard_key and gcra includes.
+local function lease_item(keys, args)
+    local queueKey               = keys[1]
+    local queueIndexKey          = keys[2]
+    -- We push our queue item ID into each concurrency queue
+    local accountConcurrencyKey  = keys[4] -- Account concurrency level
+    local functionConcurrencyKey = keys[5] -- When leasing an item we need to place the lease into this key.
+    local customConcurrencyKeyA  = keys[6] -- Optional for eg. for concurrency amongst steps
+    local customConcurrencyKeyB  = keys[7] -- Optional for eg. for concurrency amongst steps
+    -- We push pointers to partition concurrency items to the partition concurrency item
+    local concurrencyPointer     = keys[8]
+    local globalPointerKey       = keys[9]
+    local shardPointerKey        = keys[10]
+    local throttleKey            = keys[11] -- key used for throttling function run starts.
+    local appConcurrencyKey      = keys[12] -- App concurrency level, across all of an app's functions
+    local throttleOverridesKey   = keys[13] -- hash of throttle config overrides, keyed by function ID
+    local customOverridesKey     = keys[14] -- hash of custom concurrency limit overrides, keyed by function ID
+
+    local queueID                = args[1]
+    local newLeaseKey            = args[2]
+    local currentTime            = tonumber(args[3]) -- in ms
+    -- We check concurrency limits when leasing queue items: an account-level concurrency limit,
+    -- and a custom key.  The custom key is option.  It's used to add concurrency limits to individual
+    -- steps across differing functions
+    local accountConcurrency     = tonumber(args[4])
+    local partitionConcurrency   = tonumber(args[5])
+    local customConcurrencyA     = tonumber(args[6])
+    local customConcurrencyB     = tonumber(args[7])
+    local partitionName          = args[8] -- Same as fn queue name/workflow ID
+    local appConcurrency         = tonumber(args[9])
+    local useThrottleOverrides   = tonumber(args[10]) -- 1 if throttle overrides should be read
+    local useCustomOverrides     = tonumber(args[11]) -- 1 if concurrency overrides should be read
+    local customHashA            = args[12] -- expression hash of the first custom key
+    local customHashB            = args[13] -- expression hash of the second custom key
+
+    -- first, get the queue item.  we must do this and bail early if the queue item
+    -- was not found.
+    local item = get_queue_item(queueKey, queueID)
+    if item == nil then
+        return 1
+    end
+
+    -- Grab the current time from the new lease key.
+    local nextTime = decode_ulid_time(newLeaseKey)
+    -- check if the item is leased.
+    if item.leaseID ~= nil and item.leaseID ~= cjson.null and decode_ulid_time(item.leaseID) > currentTime then
+        -- This is already leased;  don't let this requester lease the item.
+        return 2
+    end
+
+    -- Track the earliest time this job was attempted in the queue.
+    item = set_item_peek_time(queueKey, queueID, item, currentTime)
+
+    -- Track throttling/rate limiting IF the queue item has throttling info set.  This allows
+    -- us to target specific queue items with rate limiting individually.
+    --
+    -- We handle this before concurrency as it's typically not used, and it's faster to handle than concurrency,
+    -- with o(1) operations vs o(log(n)).
+    if item.data ~= nil and item.data.throttle ~= nil then
+        local period, limit, burst = item.data.throttle.p, item.data.throttle.l, item.data.throttle.b
+        -- Use the function's current throttle config if it has been updated since the
+        -- item was enqueued.  The throttle key is always kept from the item.
+        if useThrottleOverrides == 1 then
+            local override = redis.call("HGET", throttleOverridesKey, item.wfID)
+            if override ~= false then
+                override = cjson.decode(override)
+                period, limit, burst = override.p, override.l, override.b
+            end
+        end
+
+        local throttleResult = gcra(throttleKey, currentTime, period * 1000, limit, burst)
+        if throttleResult == false then
+            return 7
+        end
+    end
+
+    -- Use the function's overridden custom concurrency limits, if they've been
+    -- updated since the item was enqueued.
+    if useCustomOverrides == 1 and (customConcurrencyA > 0 or customConcurrencyB > 0) then
+        local overrides = redis.call("HGET", customOverridesKey, item.wfID)
+        if overrides ~= false then
+            overrides = cjson.decode(overrides)
+            if customConcurrencyA > 0 and customHashA ~= "" and tonumber(overrides[customHashA]) ~= nil then
+                customConcurrencyA = tonumber(overrides[customHashA])
+            end
+            if customConcurrencyB > 0 and customHashB ~= "" and tonumber(overrides[customHashB]) ~= nil then
+                customConcurrencyB = tonumber(overrides[customHashB])
+            end
+        end
+    end
+
+    -- Check the concurrency limits for the account and custom key;  partition keys are checked when
+    -- leasing the partition and do not need to be checked again (only one worker can run a partition at
+    -- once, and the capacity is kept in memory after leasing a partition)
+    if partitionConcurrency > 0 then
+        if check_concurrency(currentTime, functionConcurrencyKey, partitionConcurrency) <= 0 then
+            return 3
+        end
+    end
+    if appConcurrency > 0 then
+        if check_concurrency(currentTime, appConcurrencyKey, appConcurrency) <= 0 then
+            return 8
+        end
+    end
+    if accountConcurrency > 0 then
+        if check_concurrency(currentTime, accountConcurrencyKey, accountConcurrency) <= 0 then
+            return 4
+        end
+    end
+    if customConcurrencyA > 0 then
+        if check_concurrency(currentTime, customConcurrencyKeyA, customConcurrencyA) <= 0 then
+            return 5
+        end
+    end
+    if customConcurrencyB > 0 then
+        if check_concurrency(currentTime, customConcurrencyKeyB, customConcurrencyB) <= 0 then
+            return 6
+        end
+    end
+
+    -- Update the item's lease key.
+    item.leaseID = newLeaseKey
+    redis.call("HSET", queueKey, queueID, cjson.encode(item))
+
+    -- Add the item to all concurrency keys
+    redis.call("ZADD", functionConcurrencyKey, nextTime, item.id)
+
+    -- Remove the item from our sorted index, as this is no longer on the queue; it's in-progress
+    -- and store din functionConcurrencyKey.
+    redis.call("ZREM", queueIndexKey, item.id)
+
+    -- Update the fn's score in the global pointer queue to the next job, if available.
+    local score = get_fn_partition_score(queueIndexKey)
+    update_pointer_score_to(partitionName, globalPointerKey, score)
+    -- And the same for any shards, as long as the shard name exists.
+    if has_shard_key(shardPointerKey) then
+        update_pointer_score_to(partitionName, shardPointerKey, score)
+    end
+
+    -- NOTE: We check if concurrency > 0 here because this disables concurrency.  AccountID
+    -- and custom concurrency items may not be set, but the keys need to be set for clustered
+    -- mode.
+    if appConcurrency > 0 then
+        redis.call("ZADD", appConcurrencyKey, nextTime, item.id)
+    end
+    if accountConcurrency > 0 then
+        redis.call("ZADD", accountConcurrencyKey, nextTime, item.id)
+    end
+    if customConcurrencyA > 0 then
+        redis.call("ZADD", customConcurrencyKeyA, nextTime, item.id)
+    end
+    if customConcurrencyB > 0 then
+        redis.call("ZADD", customConcurrencyKeyB, nextTime, item.id)
+    end
+
+    -- Get the earliest item in the partition/fn concurrency set - all items that have
+    -- been leased and are in progress.  If the current lease is the only item in the set, we'll
+    -- get the current lease.  Otherwise, we might get a lost job or a previously lost job.
+    local inProgressScores = redis.call("ZRANGE", functionConcurrencyKey, "-inf", "+inf", "BYSCORE", "LIMIT", 0, 1,
+        "WITHSCORES")
+    if inProgressScores ~= false then
+        local earliestLease = tonumber(inProgressScores[2])
+        -- Add the earliest time to the pointer queue for in-progress, allowing us to scavenge
+        -- lost jobs easily.
+        redis.call("ZADD", concurrencyPointer, earliestLease, partitionName)
+    end
+
+    return 0
+end
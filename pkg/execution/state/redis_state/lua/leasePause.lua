@@ -35,8 +35,10 @@ end
 redis.call("SETEX", leaseID, leaseTTL, currentTime + (leaseTTL * 1000))
 
 -- Increase the expiry time of the pause so that we can continue to work on this,
--- if the pause is set to expire before the lease is up.
-if redis.call("TTL", pauseID) < leaseTTL then
+-- if the pause is set to expire before the lease is up.  Pauses without a TTL
+-- never expire.
+local ttl = redis.call("TTL", pauseID)
+if ttl >= 0 and ttl < leaseTTL then
 	redis.call("EXPIRE", pauseID, leaseTTL)
 end
 
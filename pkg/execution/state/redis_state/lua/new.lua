@@ -6,16 +6,18 @@ Output:
 
 ]]
 
-local idempotencyKey = KEYS[1]
-local eventsKey = KEYS[2]
-local metadataKey = KEYS[3]
-local stepKey = KEYS[4]
+local eventsKey = KEYS[1]
+local metadataKey = KEYS[2]
+local stepKey = KEYS[3]
+-- The idempotency key is optional.  In clustered mode it lives in a different slot to
+-- the run's keys, and is claimed prior to calling this script.
+local idempotencyKey = KEYS[4]
 
 local events = ARGV[1]
 local metadata = ARGV[2]
 local steps = ARGV[3]
 
-if redis.call("SETNX", idempotencyKey, "") == 0 then
+if idempotencyKey ~= nil and redis.call("SETNX", idempotencyKey, "") == 0 then
   -- If this key exists, everything must've been initialised, so we can exit early
   return 1
 end
@@ -17,8 +17,9 @@ local customConcurrencyKeyA   = KEYS[7] -- Optional for eg. for concurrency amon
 local customConcurrencyKeyB   = KEYS[8] -- Optional for eg. for concurrency amongst steps 
 -- We push pointers to partition concurrency items to the partition concurrency item
 local concurrencyPointer      = KEYS[9]
-local keyItemIndexA           = KEYS[10]   -- custom item index 1
-local keyItemIndexB           = KEYS[11]  -- custom item index 2
+local appConcurrencyKey       = KEYS[10] -- Optional app concurrency level
+local keyItemIndexA           = KEYS[11]  -- custom item index 1
+local keyItemIndexB           = KEYS[12]  -- custom item index 2
 
 local queueID        = ARGV[1]
 local idempotencyTTL = tonumber(ARGV[2])
@@ -48,6 +49,9 @@ redis.call("ZREM", partitionConcurrencyKey, item.id)
 if accountConcurrencyKey ~= nil and accountConcurrencyKey ~= "" then
 	redis.call("ZREM", accountConcurrencyKey, item.id)
 end
+if appConcurrencyKey ~= nil and appConcurrencyKey ~= "" then
+	redis.call("ZREM", appConcurrencyKey, item.id)
+end
 if customConcurrencyKeyA ~= nil and customConcurrencyKeyA ~= "" then
 	redis.call("ZREM", customConcurrencyKeyA, item.id)
 end
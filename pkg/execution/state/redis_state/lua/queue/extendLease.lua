@@ -16,6 +16,7 @@ local accountConcurrencyKey   = KEYS[4] -- Account concurrency level
 local partitionConcurrencyKey = KEYS[5] -- Partition/function level concurrency
 local customConcurrencyKeyA   = KEYS[6] -- Optional for eg. for concurrency amongst steps 
 local customConcurrencyKeyB   = KEYS[7] -- Optional for eg. for concurrency amongst steps 
+local appConcurrencyKey       = KEYS[8] -- Optional app concurrency level
 
 local queueID         = ARGV[1]
 local currentLeaseKey = ARGV[2]
@@ -50,6 +51,9 @@ redis.call("ZADD", partitionConcurrencyKey, nextTime, item.id)
 if accountConcurrencyKey ~= nil and accountConcurrencyKey ~= "" then
 	redis.call("ZADD", accountConcurrencyKey, nextTime, item.id)
 end
+if appConcurrencyKey ~= nil and appConcurrencyKey ~= "" then
+	redis.call("ZADD", appConcurrencyKey, nextTime, item.id)
+end
 if customConcurrencyKeyA ~= nil and customConcurrencyKeyA ~= "" then
 	redis.call("ZADD", customConcurrencyKeyA, nextTime, item.id)
 end
@@ -0,0 +1,60 @@
+--[[
+
+Leases many items from a single partition in order, as with calling lease.lua for
+each item within a single round trip.  KEYS and ARGV contain the KEYS and ARGV of
+each item's lease.lua call, one after another.
+
+Keys denied by an item are denied for every later item, maintaining FIFO ordering
+amongst throttle and custom concurrency keys.  Leasing stops at the first item
+without function, app or account capacity, as no later items may be leased.
+
+Output:
+  A table containing the status of each attempted item, as returned by lease.lua.
+
+]]
+
+local keysPerItem = 14
+local argsPerItem = 13
+
+-- $include(decode_ulid_time.lua)
+-- $include(check_concurrency.lua)
+-- $include(get_queue_item.lua)
+-- $include(set_item_peek_time.lua)
+-- $include(update_pointer_score.lua)
+-- $include(has_shard_key.lua)
+-- $include(gcra.lua)
+-- $include(lease_item.lua)
+
+local deniedThrottle = {}
+local deniedCustom = {}
+local statuses = {}
+
+for i = 0, (#ARGV / argsPerItem) - 1 do
+    local keys = { unpack(KEYS, i * keysPerItem + 1, (i + 1) * keysPerItem) }
+    local args = { unpack(ARGV, i * argsPerItem + 1, (i + 1) * argsPerItem) }
+    local customA, customB = tonumber(args[6]), tonumber(args[7])
+
+    local status
+    if deniedThrottle[keys[11]] then
+        status = 7
+    elseif customA > 0 and deniedCustom[keys[6]] then
+        status = 5
+    elseif customB > 0 and deniedCustom[keys[7]] then
+        status = 6
+    else
+        status = lease_item(keys, args)
+    end
+    table.insert(statuses, status)
+
+    if status == 7 then
+        deniedThrottle[keys[11]] = true
+    elseif status == 5 then
+        deniedCustom[keys[6]] = true
+    elseif status == 6 then
+        deniedCustom[keys[7]] = true
+    elseif status == 3 or status == 4 or status == 8 then
+        break
+    end
+end
+
+return statuses
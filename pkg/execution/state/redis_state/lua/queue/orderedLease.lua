@@ -0,0 +1,34 @@
+--[[
+
+Leases the locks for a run's ordered concurrency keys, such that runs sharing
+an ordered key start one at a time.  Either every lock is leased, or none are.
+
+Output:
+  0: Successfully leased each lock
+  N: The Nth key is held by another run
+  -1: The run already holds each lock, which have been extended
+
+]]
+
+local runID = ARGV[1]
+local ttl   = tonumber(ARGV[2])
+
+local held = true
+for i, key in ipairs(KEYS) do
+	local existing = redis.call("GET", key)
+	if existing ~= false and existing ~= runID then
+		return i
+	end
+	if existing == false then
+		held = false
+	end
+end
+
+for _, key in ipairs(KEYS) do
+	redis.call("SET", key, runID, "EX", ttl)
+end
+
+if held then
+	return -1
+end
+return 0
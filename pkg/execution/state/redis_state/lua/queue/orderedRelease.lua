@@ -0,0 +1,21 @@
+--[[
+
+Releases the locks for a run's ordered concurrency keys, allowing the next run
+with the same keys to start.  Locks held by other runs are left untouched.
+
+Output:
+  The number of locks released.
+
+]]
+
+local runID = ARGV[1]
+
+local released = 0
+for _, key in ipairs(KEYS) do
+	if redis.call("GET", key) == runID then
+		redis.call("DEL", key)
+		released = released + 1
+	end
+end
+
+return released
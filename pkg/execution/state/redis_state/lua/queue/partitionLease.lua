@@ -5,6 +5,7 @@ Output:
    -1: No capacity left, not leased
    -2: Partition item not found
    -3: Partition item already leased
+   -4: Partition is paused
 
 ]]
 
@@ -12,6 +13,7 @@ local partitionKey            = KEYS[1]
 local keyGlobalPartitionPtr   = KEYS[2]
 local keyShardPartitionPtr    = KEYS[3]
 local partitionConcurrencyKey = KEYS[4]
+local keyPausedPartitions     = KEYS[5] -- hash of paused partitions, keyed by partition ID
 
 local partitionID             = ARGV[1]
 local leaseID                 = ARGV[2]
@@ -30,6 +32,10 @@ if existing == nil or existing == false then
     return -2
 end
 
+if redis.call("HEXISTS", keyPausedPartitions, partitionID) == 1 then
+    return -4
+end
+
 -- Check for an existing lease.
 if existing.leaseID ~= nil and existing.leaseID ~= cjson.null and decode_ulid_time(existing.leaseID) > currentTime then
     return -3
@@ -20,8 +20,9 @@ local customConcurrencyKeyB   = KEYS[8] -- Optional for eg. for concurrency amon
 -- We push pointers to partition concurrency items to the partition concurrency item
 local concurrencyPointer      = KEYS[9]
 local shardPointerKey         = KEYS[10]
-local keyItemIndexA           = KEYS[11]          -- custom item index 1
-local keyItemIndexB           = KEYS[12]          -- custom item index 2
+local appConcurrencyKey       = KEYS[11]          -- Optional app concurrency level
+local keyItemIndexA           = KEYS[12]          -- custom item index 1
+local keyItemIndexB           = KEYS[13]          -- custom item index 2
 
 local queueItem               = ARGV[1]           -- {id, lease id, attempt, max attempt, data, etc...}
 local queueID                 = ARGV[2]           -- id
@@ -55,6 +56,9 @@ redis.call("ZREM", partitionConcurrencyKey, item.id)
 if accountConcurrencyKey ~= nil and accountConcurrencyKey ~= "" then
     redis.call("ZREM", accountConcurrencyKey, item.id)
 end
+if appConcurrencyKey ~= nil and appConcurrencyKey ~= "" then
+    redis.call("ZREM", appConcurrencyKey, item.id)
+end
 if customConcurrencyKeyA ~= nil and customConcurrencyKeyA ~= "" then
     redis.call("ZREM", customConcurrencyKeyA, item.id)
 end
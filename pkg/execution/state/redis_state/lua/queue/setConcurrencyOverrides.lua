@@ -0,0 +1,30 @@
+--[[
+
+Merges the given custom concurrency limits into the function's overrides.  Limits
+are keyed by the hash of the concurrency expression.
+
+Return values:
+
+- 0: Successfully set the overrides.
+
+]]
+--
+
+local keyOverrides = KEYS[1]
+
+local workflowID   = ARGV[1]
+local limits       = cjson.decode(ARGV[2]) -- map of concurrency key hash to limit
+
+local overrides    = {}
+local existing     = redis.call("HGET", keyOverrides, workflowID)
+if existing ~= false then
+    overrides = cjson.decode(existing)
+end
+
+for hash, limit in pairs(limits) do
+    overrides[hash] = limit
+end
+
+redis.call("HSET", keyOverrides, workflowID, cjson.encode(overrides))
+
+return 0
@@ -0,0 +1,41 @@
+--[[
+
+Updates the custom concurrency limits stored within the given queue items.  Each
+item's concurrency keys are matched by the hash of their concurrency expression.
+
+Return values:
+
+- The number of queue items updated.
+
+]]
+--
+
+local keyQueueHash = KEYS[1]
+
+local limits       = cjson.decode(ARGV[1]) -- map of concurrency key hash to limit
+
+-- $include(get_queue_item.lua)
+
+local updated = 0
+
+-- ARGV[2..n] contains the IDs of the queue items to update.
+for i = 2, #ARGV do
+    local item = get_queue_item(keyQueueHash, ARGV[i])
+    if item ~= nil and item.data ~= nil and item.data.identifier ~= nil and type(item.data.identifier.cck) == "table" then
+        local changed = false
+        for _, cc in ipairs(item.data.identifier.cck) do
+            local limit = limits[cc.h]
+            if limit ~= nil and cc.l ~= limit then
+                cc.l = limit
+                changed = true
+            end
+        end
+
+        if changed then
+            redis.call("HSET", keyQueueHash, ARGV[i], cjson.encode(item))
+            updated = updated + 1
+        end
+    end
+end
+
+return updated
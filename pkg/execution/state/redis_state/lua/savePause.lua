@@ -25,14 +25,20 @@ if redis.call("SETNX", pauseKey, pause) == 0 then
 	return 1
 end
 
-redis.call("EXPIRE", pauseKey, extendedExpiry)
-redis.call("SETEX", stepKey, expiry, pauseID)
-
 -- Add an index of when the pause was added.
 redis.call("ZADD", keyPauseAddIdx, nowUnixSeconds, pauseID)
--- Add an index of when the pause expires.  This lets us manually
--- garbage collect expired pauses from the HSET below.
-redis.call("ZADD", keyPauseExpIdx, nowUnixSeconds+expiry, pauseID)
+
+if expiry > 0 then
+	redis.call("EXPIRE", pauseKey, extendedExpiry)
+	redis.call("SETEX", stepKey, expiry, pauseID)
+	-- Add an index of when the pause expires.  This lets us manually
+	-- garbage collect expired pauses from the HSET below.
+	redis.call("ZADD", keyPauseExpIdx, nowUnixSeconds+expiry, pauseID)
+else
+	-- The pause never expires, and is deleted once its run ends.
+	redis.call("SET", stepKey, pauseID)
+	redis.call("ZADD", keyPauseExpIdx, "+inf", pauseID)
+end
 
 if event ~= false and event ~= "" and event ~= nil then
 	redis.call("HSET", pauseEvtKey, pauseID, pause)
@@ -0,0 +1,25 @@
+--[[
+
+Stores data from a pause within the run's state, before the pause is consumed.  This
+is used when a run's state lives in a different slot or instance to the pause.
+
+Data is only stored once, such that saving the data again is a no-op and consuming
+the pause may be retried.  Concurrent consumers which then fail to consume the pause,
+eg. cancellations, never overwrite the data of the consumer which resumes the run.
+
+Output:
+  0: Successfully stored
+
+]]
+
+local actionKey    = KEYS[1]
+local stackKey     = KEYS[2]
+
+local pauseDataKey = ARGV[1] -- used to set data in run state store
+local pauseDataVal = ARGV[2] -- data to set
+
+if redis.call("HSETNX", actionKey, pauseDataKey, pauseDataVal) == 1 then
+	redis.call("RPUSH", stackKey, pauseDataKey)
+end
+
+return 0
@@ -0,0 +1,36 @@
+--[[
+
+Updates a run's metadata and saves a response for a step in a single call,
+pushing the step onto the stack.
+
+Output:
+ -1: duplicate response
+  0: Successfully saved response
+
+]]
+
+local keyStep     = KEYS[1]
+local keyMetadata = KEYS[2]
+local keyStack    = KEYS[3]
+
+local stepID   = ARGV[1]
+local data     = ARGV[2]
+local ctx      = ARGV[3]
+local debugger = ARGV[4]
+local die      = ARGV[5] -- disable immediate execution
+local rv       = ARGV[6] -- request version
+local spanid   = ARGV[7] -- spanID
+local sat      = ARGV[8] -- started at
+
+-- $include(update_metadata.lua)
+
+update_metadata(keyMetadata, ctx, debugger, die, rv, spanid, sat)
+
+if redis.call("HEXISTS", keyStep, stepID) == 1 then
+	return -1
+end
+
+redis.call("HINCRBY", keyMetadata, "pending", -1) -- no longer necessary
+redis.call("HSET", keyStep, stepID, data)
+redis.call("RPUSH", keyStack, stepID)
+return 0
@@ -0,0 +1,26 @@
+--[[
+
+Sets the run's status only if the run's current status is one of the given
+statuses, such that a run is never moved out of an ended status.
+
+Output:
+  0: Successfully set the status
+  1: Current status is not one of the given statuses
+]]
+
+local metadataKey = KEYS[1]
+local status      = tonumber(ARGV[1])
+
+local value = tonumber(redis.call("HGET", metadataKey, "status"))
+if value == nil then
+	return 1
+end
+
+for i = 2, #ARGV do
+	if value == tonumber(ARGV[i]) then
+		redis.call("HSET", metadataKey, "status", status)
+		return 0
+	end
+end
+
+return 1
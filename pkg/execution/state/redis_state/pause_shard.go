@@ -0,0 +1,95 @@
+package redis_state
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/cespare/xxhash/v2"
+	"github.com/google/uuid"
+	"github.com/inngest/inngest/pkg/execution/state"
+	"github.com/redis/rueidis"
+)
+
+// pauseShardEvent returns the event name that a pause is indexed and sharded by.
+//
+// Pauses are only indexed by event name if they're not part of an invoke; invoke
+// pauses are processed by correlation ID and are sharded by workspace alone.
+func pauseShardEvent(p state.Pause) string {
+	if p.Event != nil && (p.InvokeCorrelationID == nil || *p.InvokeCorrelationID == "") {
+		return *p.Event
+	}
+	return ""
+}
+
+// pauseClient returns the client storing pauses for the given workspace and event.
+func (m mgr) pauseClient(wsID uuid.UUID, event string) rueidis.Client {
+	if len(m.pauseShards) == 0 {
+		return m.pauseR
+	}
+	n := xxhash.Sum64String(wsID.String() + ":" + event)
+	return m.pauseShards[n%uint64(len(m.pauseShards))]
+}
+
+// pauseClients returns every client storing pauses. This is used when looking
+// up pauses by ID, as the workspace and event of the pause are unknown.
+func (m mgr) pauseClients() []rueidis.Client {
+	if len(m.pauseShards) == 0 {
+		return []rueidis.Client{m.pauseR}
+	}
+	return m.pauseShards
+}
+
+// pauseClientByID returns the client storing the pause with the given ID.
+func (m mgr) pauseClientByID(ctx context.Context, id uuid.UUID) (rueidis.Client, error) {
+	if len(m.pauseShards) == 0 {
+		return m.pauseR, nil
+	}
+	key := m.kf.PauseID(ctx, id)
+	for _, r := range m.pauseShards {
+		exists, err := r.Do(ctx, r.B().Exists().Key(key).Build()).AsBool()
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			return r, nil
+		}
+	}
+	return nil, state.ErrPauseNotFound
+}
+
+// pausesByID loads the given pauses from a single pause client.
+func (m mgr) pausesByID(ctx context.Context, r rueidis.Client, ids ...uuid.UUID) ([]*state.Pause, error) {
+	keys := make([]string, len(ids))
+	for n, id := range ids {
+		keys[n] = m.kf.PauseID(ctx, id)
+	}
+
+	cmd := r.B().Mget().Key(keys...).Build()
+	strings, err := r.Do(ctx, cmd).AsStrSlice()
+	if err == rueidis.Nil {
+		return nil, state.ErrPauseNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var merr error
+
+	pauses := []*state.Pause{}
+	for _, item := range strings {
+		if len(item) == 0 {
+			continue
+		}
+
+		pause := &state.Pause{}
+		err = json.Unmarshal([]byte(item), pause)
+		if err != nil {
+			merr = errors.Join(merr, err)
+			continue
+		}
+		pauses = append(pauses, pause)
+	}
+
+	return pauses, merr
+}
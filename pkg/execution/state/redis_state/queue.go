@@ -7,9 +7,11 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"golang.org/x/sync/semaphore"
@@ -58,7 +60,16 @@ const (
 	// This means that jobs not started because of concurrency limits incur up to this amount
 	// of additional latency.
 	PartitionConcurrencyLimitRequeueExtension = 2 * time.Second
-	PartitionLookahead                        = time.Second
+	// PartitionPausedRequeueExtension is the length of time that a paused partition is
+	// requeued for.  Resuming a partition requeues it immediately, so this only limits
+	// how often paused partitions are rescanned.
+	PartitionPausedRequeueExtension = time.Minute
+	PartitionLookahead              = time.Second
+
+	// OrderedLockTTL is how long a run holds its ordered concurrency keys without
+	// any of its queue items being leased.  Runs release their keys when they
+	// finish, so this only frees keys held by runs which never finish.
+	OrderedLockTTL = 7 * 24 * time.Hour
 
 	QueuePeekMax        int64 = 1000
 	QueuePeekDefault    int64 = 250
@@ -66,6 +77,12 @@ const (
 	ConfigLeaseDuration       = 10 * time.Second
 	ConfigLeaseMax            = 20 * time.Second
 
+	// QueueLeaseBatchSize is the maximum number of a partition's items leased within
+	// a single round trip.  Workers are claimed for each item in the batch prior to
+	// leasing, so larger batches may briefly hold workers that other partitions
+	// could use.
+	QueueLeaseBatchSize = 25
+
 	PriorityMax     uint = 0
 	PriorityDefault uint = 5
 	PriorityMin     uint = 9
@@ -74,8 +91,12 @@ const (
 	// times to edge enqueue times.
 	FunctionStartScoreBufferTime = 10 * time.Second
 
-	defaultNumWorkers           = 100
-	defaultPollTick             = 10 * time.Millisecond
+	defaultNumWorkers   = 100
+	defaultPollTick     = 10 * time.Millisecond
+	defaultDrainTimeout = 25 * time.Second
+	// drainReleaseTimeout is how long cancelled jobs have to release themselves
+	// once the drain timeout is reached.
+	drainReleaseTimeout         = 5 * time.Second
 	defaultIdempotencyTTL       = 12 * time.Hour
 	defaultPartitionConcurrency = 100 // TODO: add function to override.
 )
@@ -94,10 +115,12 @@ var (
 	ErrPartitionAlreadyLeased        = fmt.Errorf("partition already leased")
 	ErrPartitionPeekMaxExceedsLimits = fmt.Errorf("peek exceeded the maximum limit of %d", PartitionPeekMax)
 	ErrPartitionGarbageCollected     = fmt.Errorf("partition garbage collected")
+	ErrPartitionPaused               = fmt.Errorf("partition is paused")
 	ErrConfigAlreadyLeased           = fmt.Errorf("config scanner already leased")
 	ErrConfigLeaseExceedsLimits      = fmt.Errorf("config lease duration exceeds the maximum of %d seconds", int(ConfigLeaseMax.Seconds()))
 	ErrPartitionConcurrencyLimit     = fmt.Errorf("At partition concurrency limit")
 	ErrAccountConcurrencyLimit       = fmt.Errorf("At account concurrency limit")
+	ErrAppConcurrencyLimit           = fmt.Errorf("At app concurrency limit")
 
 	// ErrConcurrencyLimitCustomKeyN represents a concurrency limit being hit for *some*, but *not all*
 	// jobs in a queue, via custom concurrency keys which are evaluated to a specific string.
@@ -195,6 +218,15 @@ func WithNumWorkers(n int32) QueueOpt {
 	}
 }
 
+// WithDrainTimeout sets how long the queue waits for in-progress jobs to finish
+// when shutting down.  Jobs which are still running after the timeout are
+// cancelled and released back to the queue.
+func WithDrainTimeout(t time.Duration) QueueOpt {
+	return func(q *queue) {
+		q.drainTimeout = t
+	}
+}
+
 func WithPeekSize(n int64) QueueOpt {
 	return func(q *queue) {
 		q.peek = n
@@ -209,6 +241,23 @@ func WithPollTick(t time.Duration) QueueOpt {
 	}
 }
 
+// WithPayloadCompression compresses the payload and metadata of queue items whose
+// encoded size is at least threshold bytes, reducing the memory used by large
+// items.  Items stored with and without compression are decoded interchangeably.
+func WithPayloadCompression(threshold int) QueueOpt {
+	return func(q *queue) {
+		q.compressThreshold = threshold
+	}
+}
+
+// WithLeaseDelay delays each lease by a random duration up to d, simulating slow
+// or contended leases.  This should only be used for testing.
+func WithLeaseDelay(d time.Duration) QueueOpt {
+	return func(q *queue) {
+		q.leaseDelay = d
+	}
+}
+
 func WithQueueItemIndexer(i QueueItemIndexer) QueueOpt {
 	return func(q *queue) {
 		q.itemIndexer = i
@@ -299,6 +348,34 @@ func WithAccountConcurrencyKeyGenerator(f AccountConcurrencyKeyGenerator) func(q
 	}
 }
 
+// WithThrottleOverrides enables throttle configuration overrides set via SetThrottle.
+// When enabled, the current throttle config for each function is read when leasing
+// throttled queue items, instead of the config stored within each item.
+func WithThrottleOverrides() func(q *queue) {
+	return func(q *queue) {
+		q.throttleOverrides = true
+	}
+}
+
+// WithConcurrencyOverrides enables custom concurrency limit overrides set via
+// UpdateConcurrencyLimits.  When enabled, the overridden limit for each custom
+// concurrency key is read when leasing queue items, instead of the limit stored
+// within each item.
+func WithConcurrencyOverrides() func(q *queue) {
+	return func(q *queue) {
+		q.concurrencyOverrides = true
+	}
+}
+
+// WithAppConcurrencyKeyGenerator assigns a function that returns the app concurrency
+// key and limit for a given queue item.  This limits the total number of in-progress
+// items across all functions within an app.
+func WithAppConcurrencyKeyGenerator(f AppConcurrencyKeyGenerator) func(q *queue) {
+	return func(q *queue) {
+		q.appConcurrencyGen = f
+	}
+}
+
 func WithBackoffFunc(f backoff.BackoffFunc) func(q *queue) {
 	return func(q *queue) {
 		q.backoffFunc = f
@@ -316,6 +393,10 @@ type QueueItemConcurrencyKeyGenerator func(ctx context.Context, i QueueItem) []s
 // identifier.
 type AccountConcurrencyKeyGenerator func(ctx context.Context, i QueueItem) (string, int)
 
+// AppConcurrencyKeyGenerator returns a concurrency key and limit given the queue item's
+// app identifier.  A limit of 0 disables app concurrency limits for the item.
+type AppConcurrencyKeyGenerator func(ctx context.Context, i QueueItem) (string, int)
+
 // PartitionConcurrencyKeyGenerator returns a concurrency key and limit for a given partition
 // (function).
 //
@@ -334,6 +415,7 @@ func NewQueue(r rueidis.Client, opts ...QueueOpt) *queue {
 		seqLeaseLock:       &sync.RWMutex{},
 		scavengerLeaseLock: &sync.RWMutex{},
 		pollTick:           defaultPollTick,
+		drainTimeout:       defaultDrainTimeout,
 		idempotencyTTL:     defaultIdempotencyTTL,
 		queueKindMapping:   make(map[string]string),
 		logger:             logger.From(context.Background()),
@@ -351,6 +433,10 @@ func NewQueue(r rueidis.Client, opts ...QueueOpt) *queue {
 	}
 
 	q.sem = &trackingSemaphore{Weighted: semaphore.NewWeighted(int64(q.numWorkers))}
+	q.reservations = newReservationTable(int64(q.numWorkers))
+	if err := q.reservations.set(q.reservationOpts, int64(q.numWorkers)); err != nil {
+		q.logger.Error().Err(err).Msg("ignoring invalid capacity reservations")
+	}
 	q.workers = make(chan processItem, q.numWorkers)
 
 	return q
@@ -369,8 +455,15 @@ type queue struct {
 	lifecycles []QueueLifecycleListener
 
 	accountConcurrencyGen   AccountConcurrencyKeyGenerator
+	appConcurrencyGen       AppConcurrencyKeyGenerator
 	partitionConcurrencyGen PartitionConcurrencyKeyGenerator
 	customConcurrencyGen    QueueItemConcurrencyKeyGenerator
+	// throttleOverrides reads the current throttle config for each function when
+	// leasing throttled items.
+	throttleOverrides bool
+	// concurrencyOverrides reads the current custom concurrency limits for each
+	// function when leasing items.
+	concurrencyOverrides bool
 
 	// idempotencyTTL is the default or static idempotency duration apply to jobs,
 	// if idempotencyTTLFunc is not defined.
@@ -386,6 +479,9 @@ type queue struct {
 	quit chan error
 	// wg stores a waitgroup for all in-progress jobs
 	wg *sync.WaitGroup
+	// drainTimeout is how long in-progress jobs have to finish when the queue
+	// shuts down.
+	drainTimeout time.Duration
 	// numWorkers stores the number of workers available to concurrently process jobs.
 	numWorkers int32
 	// peek sets the number of items to check on queue peeks
@@ -397,6 +493,9 @@ type queue struct {
 	// being processed.  This lets us check whether there's capacity in the queue
 	// prior to leasing items.
 	sem *trackingSemaphore
+	// reservations tracks workers reserved for specific functions and accounts.
+	reservations    *reservationTable
+	reservationOpts []CapacityReservation
 	// queueKindMapping stores a map of job kind => queue names
 	queueKindMapping map[string]string
 	logger           *zerolog.Logger
@@ -404,6 +503,13 @@ type queue struct {
 	// itemIndexer returns indexes for a given queue item.
 	itemIndexer QueueItemIndexer
 
+	// compressThreshold is the encoded size, in bytes, at which item payloads
+	// are compressed.  Zero disables compression.
+	compressThreshold int
+
+	// leaseDelay is the maximum delay applied prior to leasing each item.
+	leaseDelay time.Duration
+
 	// denyQueues provides a denylist ensuring that the queue will never claim
 	// this partition, meaning that no jobs from this queue will run on this worker.
 	denyQueues        []string
@@ -437,6 +543,11 @@ type queue struct {
 
 	// backoffFunc is the backoff function to use when retrying operations.
 	backoffFunc backoff.BackoffFunc
+
+	// lastScanAt and lastLeaseAt store the unix milliseconds of the processing
+	// loop's last scan and lease, for health checks.
+	lastScanAt  atomic.Int64
+	lastLeaseAt atomic.Int64
 }
 
 // processItem references the queue partition and queue item to be processed by a worker.
@@ -446,6 +557,8 @@ type processItem struct {
 	P QueuePartition
 	I QueueItem
 	S *QueueShard
+	// R is the capacity reservation used to process the item, if any.
+	R *reservation
 }
 
 // QueueShard represents a sub-partition for a group of functions.  Shards maintain their
@@ -631,6 +744,24 @@ func (q QueueItem) IsLeased(time time.Time) bool {
 	return q.LeaseID != nil && ulid.Time(q.LeaseID.Time()).After(time)
 }
 
+// scheduledJob returns the public representation of the queue item.
+func (q QueueItem) scheduledJob() osqueue.ScheduledJob {
+	id := q.Data.Identifier
+	job := osqueue.ScheduledJob{
+		JobID:      q.ID,
+		FunctionID: id.WorkflowID,
+		RunID:      id.RunID,
+		Kind:       q.Data.Kind,
+		At:         time.UnixMilli(q.AtMS),
+		Attempt:    q.Data.Attempt,
+		Metadata:   q.Data.Metadata,
+	}
+	if edge, err := osqueue.GetEdge(q.Data); err == nil {
+		job.StepID = edge.Edge.Incoming
+	}
+	return job
+}
+
 func (q *queue) RunJobs(ctx context.Context, workspaceID, workflowID uuid.UUID, runID ulid.ULID, limit, offset int64) ([]osqueue.JobResponse, error) {
 	if limit > 10 || limit <= 0 {
 		limit = 10
@@ -681,6 +812,108 @@ func (q *queue) RunJobs(ctx context.Context, workspaceID, workflowID uuid.UUID,
 	return resp, nil
 }
 
+// ScheduledJobs lists the function's jobs which are scheduled to run in the future,
+// optionally filtered to a single run.
+func (q *queue) ScheduledJobs(ctx context.Context, workspaceID, workflowID uuid.UUID, runID *ulid.ULID, limit int64) ([]osqueue.ScheduledJob, error) {
+	if limit > 100 || limit <= 0 {
+		limit = 100
+	}
+	nowMS := getNow().UnixMilli()
+
+	var (
+		ids []string
+		err error
+	)
+	if runID != nil {
+		// Runs have few outstanding jobs, so read every job in the run's index.
+		cmd := q.r.B().Zrange().Key(q.kg.RunIndex(*runID)).Min("0").Max("-1").Build()
+		ids, err = q.r.Do(ctx, cmd).AsStrSlice()
+	} else {
+		cmd := q.r.B().Zrange().
+			Key(q.kg.QueueIndex(workflowID.String())).
+			Min(fmt.Sprintf("(%d", nowMS)).
+			Max("+inf").
+			Byscore().
+			Limit(0, limit).
+			Build()
+		ids, err = q.r.Do(ctx, cmd).AsStrSlice()
+	}
+	if err != nil && !rueidis.IsRedisNil(err) {
+		return nil, fmt.Errorf("error reading index: %w", err)
+	}
+	if len(ids) == 0 {
+		return []osqueue.ScheduledJob{}, nil
+	}
+
+	jsonItems, err := q.r.Do(ctx, q.r.B().Hmget().Key(q.kg.QueueItem()).Field(ids...).Build()).AsStrSlice()
+	if err != nil {
+		return nil, fmt.Errorf("error reading jobs: %w", err)
+	}
+
+	resp := []osqueue.ScheduledJob{}
+	for _, str := range jsonItems {
+		if len(str) == 0 {
+			continue
+		}
+		qi := &QueueItem{}
+		if err := json.Unmarshal([]byte(str), qi); err != nil {
+			return nil, fmt.Errorf("error unmarshalling queue item: %w", err)
+		}
+		id := qi.Data.Identifier
+		if id.WorkspaceID != workspaceID || id.WorkflowID != workflowID || qi.AtMS <= nowMS {
+			continue
+		}
+		resp = append(resp, qi.scheduledJob())
+	}
+
+	sort.SliceStable(resp, func(i, j int) bool {
+		return resp[i].At.Before(resp[j].At)
+	})
+	if int64(len(resp)) > limit {
+		resp = resp[:limit]
+	}
+	return resp, nil
+}
+
+// PeekJobs returns the next jobs in the function's partition, ordered by the time
+// they're scheduled for, without leasing them.  Jobs which are in progress are
+// not included.
+func (q *queue) PeekJobs(ctx context.Context, workspaceID, workflowID uuid.UUID, limit int64) ([]osqueue.ScheduledJob, error) {
+	if limit > 100 || limit <= 0 {
+		limit = 100
+	}
+
+	cmd := q.r.B().Zrange().Key(q.kg.QueueIndex(workflowID.String())).Min("0").Max(strconv.Itoa(int(limit - 1))).Build()
+	ids, err := q.r.Do(ctx, cmd).AsStrSlice()
+	if err != nil && !rueidis.IsRedisNil(err) {
+		return nil, fmt.Errorf("error reading index: %w", err)
+	}
+	if len(ids) == 0 {
+		return []osqueue.ScheduledJob{}, nil
+	}
+
+	jsonItems, err := q.r.Do(ctx, q.r.B().Hmget().Key(q.kg.QueueItem()).Field(ids...).Build()).AsStrSlice()
+	if err != nil {
+		return nil, fmt.Errorf("error reading jobs: %w", err)
+	}
+
+	resp := []osqueue.ScheduledJob{}
+	for _, str := range jsonItems {
+		if len(str) == 0 {
+			continue
+		}
+		qi := &QueueItem{}
+		if err := json.Unmarshal([]byte(str), qi); err != nil {
+			return nil, fmt.Errorf("error unmarshalling queue item: %w", err)
+		}
+		if qi.Data.Identifier.WorkspaceID != workspaceID {
+			continue
+		}
+		resp = append(resp, qi.scheduledJob())
+	}
+	return resp, nil
+}
+
 func (q *queue) OutstandingJobCount(ctx context.Context, workspaceID, workflowID uuid.UUID, runID ulid.ULID) (int, error) {
 	cmd := q.r.B().Zcard().Key(q.kg.RunIndex(runID)).Build()
 	count, err := q.r.Do(ctx, cmd).AsInt64()
@@ -731,6 +964,75 @@ func (q *queue) RunningCount(ctx context.Context, workflowID uuid.UUID) (int64,
 	return count, nil
 }
 
+// PartitionStats returns backlog information for the given function's partition,
+// splitting the partition's queue into items that are ready to run and items that
+// are scheduled for the future.
+func (q *queue) PartitionStats(ctx context.Context, workflowID uuid.UUID) (osqueue.PartitionStats, error) {
+	stats := osqueue.PartitionStats{FunctionID: workflowID}
+
+	cmd := q.r.B().Hget().Key(q.kg.PartitionItem()).Field(workflowID.String()).Build()
+	enc, err := q.r.Do(ctx, cmd).AsBytes()
+	if rueidis.IsRedisNil(err) {
+		return stats, nil
+	}
+	if err != nil {
+		return stats, fmt.Errorf("error fetching partition: %w", err)
+	}
+	p := &QueuePartition{}
+	if err = json.Unmarshal(enc, p); err != nil {
+		return stats, fmt.Errorf("error reading partition item: %w", err)
+	}
+
+	now := getNow()
+	nowMS := strconv.Itoa(int(now.UnixMilli()))
+	indexKey := q.kg.QueueIndex(p.Queue())
+	pk, limit := q.partitionConcurrencyGen(ctx, *p)
+
+	cmds := rueidis.Commands{
+		q.r.B().Zcount().Key(indexKey).Min("-inf").Max(nowMS).Build(),
+		q.r.B().Zcount().Key(indexKey).Min("(" + nowMS).Max("+inf").Build(),
+		// Leases in the concurrency set expire;  only count unexpired leases.
+		q.r.B().Zcount().Key(q.kg.Concurrency("p", pk)).Min(nowMS).Max("+inf").Build(),
+		q.r.B().Zrange().Key(indexKey).Min("0").Max("0").Withscores().Build(),
+		q.r.B().Hexists().Key(q.kg.PausedPartitions()).Field(p.Queue()).Build(),
+	}
+	res := q.r.DoMulti(ctx, cmds...)
+
+	if stats.Ready, err = res[0].AsInt64(); err != nil {
+		return stats, fmt.Errorf("error counting ready items: %w", err)
+	}
+	if stats.Scheduled, err = res[1].AsInt64(); err != nil {
+		return stats, fmt.Errorf("error counting scheduled items: %w", err)
+	}
+	if stats.InProgress, err = res[2].AsInt64(); err != nil {
+		return stats, fmt.Errorf("error counting in progress items: %w", err)
+	}
+	oldest, err := res[3].AsZScores()
+	if err != nil {
+		return stats, fmt.Errorf("error reading oldest item: %w", err)
+	}
+	if stats.Ready > 0 && len(oldest) > 0 {
+		at := time.UnixMilli(int64(oldest[0].Score))
+		stats.OldestItemAt = &at
+	}
+	if stats.Paused, err = res[4].AsBool(); err != nil {
+		return stats, fmt.Errorf("error reading paused status: %w", err)
+	}
+
+	if limit > 0 {
+		stats.ConcurrencyLimit = int64(limit)
+		capacity := int64(limit) - stats.InProgress
+		if capacity < 0 {
+			capacity = 0
+		}
+		if stats.Ready > capacity {
+			stats.ConcurrencyLimited = stats.Ready - capacity
+		}
+	}
+
+	return stats, nil
+}
+
 // EnqueueItem enqueues a QueueItem.  It creates a QueuePartition for the workspace
 // if a partition does not exist.
 //
@@ -739,6 +1041,30 @@ func (q *queue) RunningCount(ctx context.Context, workflowID uuid.UUID) (int64,
 //
 // The queue score must be added in milliseconds to process sub-second items in order.
 func (q *queue) EnqueueItem(ctx context.Context, i QueueItem, at time.Time) (QueueItem, error) {
+	i, keys, args, err := q.enqueueArgs(ctx, i, at)
+	if err != nil {
+		return i, err
+	}
+	status, err := scripts["queue/enqueue"].Exec(
+		ctx,
+		q.r,
+		keys,
+		args,
+	).AsInt64()
+	return i, enqueueResult(status, err)
+}
+
+// storedItem returns the item as it's stored within Redis, compressing large
+// payloads.
+func (q *queue) storedItem(i QueueItem) (QueueItem, error) {
+	var err error
+	i.Data, err = osqueue.CompressPayload(i.Data, q.compressThreshold)
+	return i, err
+}
+
+// enqueueArgs prepares the item for enqueueing, returning the keys and args for
+// the enqueue script.
+func (q *queue) enqueueArgs(ctx context.Context, i QueueItem, at time.Time) (QueueItem, []string, []string, error) {
 	if len(i.ID) == 0 {
 		i.SetID(ctx, ulid.MustNew(ulid.Now(), rnd).String())
 	} else {
@@ -755,10 +1081,10 @@ func (q *queue) EnqueueItem(ctx context.Context, i QueueItem, at time.Time) (Que
 	}
 
 	if priority > PriorityMin {
-		return i, ErrPriorityTooLow
+		return i, nil, nil, ErrPriorityTooLow
 	}
 	if priority < PriorityMax {
-		return i, ErrPriorityTooHigh
+		return i, nil, nil, ErrPriorityTooHigh
 	}
 
 	if i.WallTimeMS == 0 {
@@ -828,8 +1154,13 @@ func (q *queue) EnqueueItem(ctx context.Context, i QueueItem, at time.Time) (Que
 		}
 	}
 
+	stored, err := q.storedItem(i)
+	if err != nil {
+		return i, nil, nil, err
+	}
+
 	args, err := StrSlice([]any{
-		i,
+		stored,
 		i.ID,
 		at.UnixMilli(),
 		qn,
@@ -840,24 +1171,23 @@ func (q *queue) EnqueueItem(ctx context.Context, i QueueItem, at time.Time) (Que
 		getNow().UnixMilli(),
 	})
 	if err != nil {
-		return i, err
+		return i, nil, nil, err
 	}
-	status, err := scripts["queue/enqueue"].Exec(
-		ctx,
-		q.r,
-		keys,
-		args,
-	).AsInt64()
+	return i, keys, args, nil
+}
+
+// enqueueResult returns the error for the enqueue script's result.
+func enqueueResult(status int64, err error) error {
 	if err != nil {
-		return i, fmt.Errorf("error enqueueing item: %w", err)
+		return fmt.Errorf("error enqueueing item: %w", err)
 	}
 	switch status {
 	case 0:
-		return i, nil
+		return nil
 	case 1:
-		return i, ErrQueueItemExists
+		return ErrQueueItemExists
 	default:
-		return i, fmt.Errorf("unknown response enqueueing item: %v (%T)", status, status)
+		return fmt.Errorf("unknown response enqueueing item: %v (%T)", status, status)
 	}
 }
 
@@ -934,8 +1264,12 @@ func (q *queue) Peek(ctx context.Context, queueName string, until time.Time, lim
 // If the queue item referenced by the job ID is not outstanding (ie. it has a lease, is in
 // progress, or doesn't exist) this returns an error.
 func (q *queue) RequeueByJobID(ctx context.Context, partitionName string, jobID string, at time.Time) error {
-	jobID = HashID(ctx, jobID)
+	return q.requeueByID(ctx, partitionName, HashID(ctx, jobID), at)
+}
 
+// requeueByID requeues a job for a specific time given a partition name and the
+// hashed queue item ID.
+func (q *queue) requeueByID(ctx context.Context, partitionName string, jobID string, at time.Time) error {
 	// Find the queue item so that we can fetch the shard info.
 	qi := &QueueItem{}
 	if err := q.r.Do(ctx, q.r.B().Hget().Key(q.kg.QueueItem()).Field(jobID).Build()).DecodeJSON(qi); err != nil {
@@ -983,18 +1317,569 @@ func (q *queue) RequeueByJobID(ctx context.Context, partitionName string, jobID
 
 }
 
+// AdvanceTime fast-forwards outstanding queue items of the given kinds, scheduling
+// each item earlier by the given duration without scheduling any item in the past.
+// Leased items are left untouched.  This returns the number of items rescheduled.
+//
+// This scans every queue item and is only intended for use in development and
+// testing, eg. to skip month-long sleeps locally.
+func (q *queue) AdvanceTime(ctx context.Context, by time.Duration, kinds ...string) (int, error) {
+	now := getNow()
+	count := 0
+
+	match := map[string]struct{}{}
+	for _, k := range kinds {
+		match[k] = struct{}{}
+	}
+
+	var cursor uint64
+	for {
+		cmd := q.r.B().Hscan().Key(q.kg.QueueItem()).Cursor(cursor).Count(100).Build()
+		entry, err := q.r.Do(ctx, cmd).AsScanEntry()
+		if err != nil {
+			return count, fmt.Errorf("error scanning queue items: %w", err)
+		}
+
+		// HSCAN returns field and value pairs.
+		for i := 1; i < len(entry.Elements); i += 2 {
+			qi := &QueueItem{}
+			if err := json.Unmarshal([]byte(entry.Elements[i]), qi); err != nil {
+				return count, fmt.Errorf("error unmarshalling queue item: %w", err)
+			}
+			if _, ok := match[qi.Data.Kind]; !ok || qi.IsLeased(now) {
+				continue
+			}
+
+			at := time.UnixMilli(qi.AtMS)
+			if !at.After(now) {
+				continue
+			}
+			at = at.Add(-by)
+			if at.Before(now) {
+				at = now
+			}
+
+			err := q.requeueByID(ctx, qi.Queue(), qi.ID, at)
+			if err == ErrQueueItemNotFound || err == ErrQueueItemAlreadyLeased {
+				// This item was processed whilst scanning.
+				continue
+			}
+			if err != nil {
+				return count, err
+			}
+			count++
+		}
+
+		cursor = entry.Cursor
+		if cursor == 0 {
+			return count, nil
+		}
+	}
+}
+
+// SetThrottle overrides the throttle limit, burst and period for the given function.
+// Queue items keep their evaluated throttle key.  Passing a nil throttle removes the
+// override, reverting to the throttle config stored within each queue item.
+//
+// Overrides are only applied by queues created using WithThrottleOverrides.
+func (q *queue) SetThrottle(ctx context.Context, workflowID uuid.UUID, t *osqueue.Throttle) error {
+	if t == nil {
+		cmd := q.r.B().Hdel().Key(q.kg.ThrottleOverrides()).Field(workflowID.String()).Build()
+		if err := q.r.Do(ctx, cmd).Error(); err != nil {
+			return fmt.Errorf("error removing throttle override: %w", err)
+		}
+		return nil
+	}
+
+	if t.Limit <= 0 || t.Period <= 0 || t.Burst < 0 {
+		return fmt.Errorf("invalid throttle: limit and period must be greater than zero")
+	}
+
+	byt, err := json.Marshal(osqueue.Throttle{
+		Limit:  t.Limit,
+		Burst:  t.Burst,
+		Period: t.Period,
+	})
+	if err != nil {
+		return fmt.Errorf("error marshalling throttle: %w", err)
+	}
+	cmd := q.r.B().Hset().Key(q.kg.ThrottleOverrides()).FieldValue().FieldValue(workflowID.String(), string(byt)).Build()
+	if err := q.r.Do(ctx, cmd).Error(); err != nil {
+		return fmt.Errorf("error setting throttle override: %w", err)
+	}
+	return nil
+}
+
+// UpdateConcurrencyLimits overrides the custom concurrency limits for the given
+// function, and updates the limits stored within each outstanding queue item.
+// Limits are keyed by the hash of the concurrency expression, and keys with no
+// matching hash are left as-is.
+//
+// Overrides are stored separately from queue items so that limits re-resolved from
+// the function's config, eg. when leasing items or enqueueing a run's next step,
+// don't replace them.  Overrides are only applied when leasing by queues created
+// using WithConcurrencyOverrides.
+//
+// This returns the number of queue items updated.
+func (q *queue) UpdateConcurrencyLimits(ctx context.Context, workflowID uuid.UUID, limits map[string]int) (int, error) {
+	if len(limits) == 0 {
+		return 0, nil
+	}
+	byt, err := json.Marshal(limits)
+	if err != nil {
+		return 0, fmt.Errorf("error marshalling concurrency limits: %w", err)
+	}
+
+	err = scripts["queue/setConcurrencyOverrides"].Exec(
+		ctx,
+		q.r,
+		[]string{q.kg.ConcurrencyOverrides()},
+		[]string{workflowID.String(), string(byt)},
+	).Error()
+	if err != nil {
+		return 0, fmt.Errorf("error setting concurrency overrides: %w", err)
+	}
+
+	count := 0
+	var cursor uint64
+	for {
+		cmd := q.r.B().Zscan().Key(q.kg.QueueIndex(workflowID.String())).Cursor(cursor).Count(100).Build()
+		entry, err := q.r.Do(ctx, cmd).AsScanEntry()
+		if err != nil {
+			return count, fmt.Errorf("error scanning partition: %w", err)
+		}
+
+		// ZSCAN returns member and score pairs.
+		args := []string{string(byt)}
+		for i := 0; i < len(entry.Elements); i += 2 {
+			args = append(args, entry.Elements[i])
+		}
+
+		if len(args) > 1 {
+			updated, err := scripts["queue/updateConcurrencyLimits"].Exec(
+				ctx,
+				q.r,
+				[]string{q.kg.QueueItem()},
+				args,
+			).AsInt64()
+			if err != nil {
+				return count, fmt.Errorf("error updating concurrency limits: %w", err)
+			}
+			count += int(updated)
+		}
+
+		cursor = entry.Cursor
+		if cursor == 0 {
+			return count, nil
+		}
+	}
+}
+
+// ConcurrencyLimitOverrides returns the custom concurrency limits set for the given
+// function via UpdateConcurrencyLimits, keyed by the hash of the concurrency
+// expression.
+func (q *queue) ConcurrencyLimitOverrides(ctx context.Context, workflowID uuid.UUID) (map[string]int, error) {
+	cmd := q.r.B().Hget().Key(q.kg.ConcurrencyOverrides()).Field(workflowID.String()).Build()
+	byt, err := q.r.Do(ctx, cmd).AsBytes()
+	if rueidis.IsRedisNil(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error fetching concurrency overrides: %w", err)
+	}
+	limits := map[string]int{}
+	if err := json.Unmarshal(byt, &limits); err != nil {
+		return nil, fmt.Errorf("error reading concurrency overrides: %w", err)
+	}
+	return limits, nil
+}
+
+// SetPartitionPaused pauses or resumes leasing of the given function's partition.
+// Queue items are left in the partition while it's paused, and items can still be
+// enqueued.  Items that are already leased continue to run.
+//
+// Resuming a partition requeues it immediately so that outstanding items are
+// leased without waiting for the partition's paused requeue time.
+func (q *queue) SetPartitionPaused(ctx context.Context, workflowID uuid.UUID, paused bool) error {
+	if paused {
+		cmd := q.r.B().Hset().Key(q.kg.PausedPartitions()).FieldValue().FieldValue(workflowID.String(), strconv.FormatInt(getNow().UnixMilli(), 10)).Build()
+		if err := q.r.Do(ctx, cmd).Error(); err != nil {
+			return fmt.Errorf("error pausing partition: %w", err)
+		}
+		return nil
+	}
+
+	cmd := q.r.B().Hdel().Key(q.kg.PausedPartitions()).Field(workflowID.String()).Build()
+	if err := q.r.Do(ctx, cmd).Error(); err != nil {
+		return fmt.Errorf("error resuming partition: %w", err)
+	}
+
+	cmd = q.r.B().Hget().Key(q.kg.PartitionItem()).Field(workflowID.String()).Build()
+	enc, err := q.r.Do(ctx, cmd).AsBytes()
+	if rueidis.IsRedisNil(err) {
+		// There's no outstanding work for the function.
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error fetching partition: %w", err)
+	}
+	p := &QueuePartition{}
+	if err = json.Unmarshal(enc, p); err != nil {
+		return fmt.Errorf("error reading partition item: %w", err)
+	}
+	err = q.PartitionRequeue(ctx, p, getNow(), false)
+	if err == ErrPartitionNotFound || err == ErrPartitionGarbageCollected {
+		return nil
+	}
+	return err
+}
+
+// RequeueRunStep locates the outstanding queue item for the given run and step,
+// rescheduling it to run at the given time.  This bypasses any retry backoff or
+// sleep for the item.
+//
+// If the item is in progress this returns ErrQueueItemAlreadyLeased.
+func (q *queue) RequeueRunStep(ctx context.Context, runID ulid.ULID, stepID string, at time.Time) error {
+	cmd := q.r.B().Zrange().Key(q.kg.RunIndex(runID)).Min("0").Max("-1").Build()
+	ids, err := q.r.Do(ctx, cmd).AsStrSlice()
+	if err != nil {
+		return fmt.Errorf("error reading index: %w", err)
+	}
+	if len(ids) == 0 {
+		return osqueue.ErrJobNotFound
+	}
+
+	jsonItems, err := q.r.Do(ctx, q.r.B().Hmget().Key(q.kg.QueueItem()).Field(ids...).Build()).AsStrSlice()
+	if err != nil {
+		return fmt.Errorf("error reading jobs: %w", err)
+	}
+
+	for _, str := range jsonItems {
+		if len(str) == 0 {
+			continue
+		}
+		qi := &QueueItem{}
+		if err := json.Unmarshal([]byte(str), qi); err != nil {
+			return fmt.Errorf("error unmarshalling queue item: %w", err)
+		}
+		if !itemRunsStep(*qi, stepID) {
+			continue
+		}
+		if qi.IsLeased(getNow()) {
+			return ErrQueueItemAlreadyLeased
+		}
+		return q.requeueByID(ctx, qi.Queue(), qi.ID, at)
+	}
+
+	return osqueue.ErrJobNotFound
+}
+
+// BoostRunPriority moves the given run's jobs ahead of the function's backlog,
+// scheduling each job that's ready to run earlier by the given duration.  Jobs
+// scheduled in the future, such as sleeps, and jobs in progress are left as-is.
+//
+// This returns the number of jobs boosted.
+func (q *queue) BoostRunPriority(ctx context.Context, runID ulid.ULID, by time.Duration) (int, error) {
+	if by <= 0 || by > time.Duration(consts.PriorityFactorMax)*time.Second {
+		return 0, fmt.Errorf("invalid priority boost: must be between 0 and %s", time.Duration(consts.PriorityFactorMax)*time.Second)
+	}
+
+	cmd := q.r.B().Zrange().Key(q.kg.RunIndex(runID)).Min("0").Max("-1").Build()
+	ids, err := q.r.Do(ctx, cmd).AsStrSlice()
+	if err != nil {
+		return 0, fmt.Errorf("error reading index: %w", err)
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	jsonItems, err := q.r.Do(ctx, q.r.B().Hmget().Key(q.kg.QueueItem()).Field(ids...).Build()).AsStrSlice()
+	if err != nil {
+		return 0, fmt.Errorf("error reading jobs: %w", err)
+	}
+
+	now := getNow()
+	count := 0
+	for _, str := range jsonItems {
+		if len(str) == 0 {
+			continue
+		}
+		qi := &QueueItem{}
+		if err := json.Unmarshal([]byte(str), qi); err != nil {
+			return count, fmt.Errorf("error unmarshalling queue item: %w", err)
+		}
+		if qi.IsLeased(now) {
+			continue
+		}
+
+		// Use the item's score rather than its time, as the score may already
+		// include the run's priority factor.
+		cmd := q.r.B().Zscore().Key(q.kg.QueueIndex(qi.Queue())).Member(qi.ID).Build()
+		score, err := q.r.Do(ctx, cmd).AsFloat64()
+		if rueidis.IsRedisNil(err) {
+			// This item was processed whilst boosting.
+			continue
+		}
+		if err != nil {
+			return count, fmt.Errorf("error reading job score: %w", err)
+		}
+		if int64(score) > now.UnixMilli() {
+			continue
+		}
+
+		err = q.requeueByID(ctx, qi.Queue(), qi.ID, time.UnixMilli(int64(score)).Add(-by))
+		if err == ErrQueueItemNotFound || err == ErrQueueItemAlreadyLeased {
+			continue
+		}
+		if err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+// RemoveRunStart dequeues the job which starts the given run, so that runs
+// scheduled in the future never start.
+//
+// If the run has already started this returns ErrJobNotFound, and if the start
+// job is in progress this returns ErrQueueItemAlreadyLeased.
+func (q *queue) RemoveRunStart(ctx context.Context, runID ulid.ULID) error {
+	cmd := q.r.B().Zrange().Key(q.kg.RunIndex(runID)).Min("0").Max("-1").Build()
+	ids, err := q.r.Do(ctx, cmd).AsStrSlice()
+	if err != nil {
+		return fmt.Errorf("error reading index: %w", err)
+	}
+	if len(ids) == 0 {
+		return osqueue.ErrJobNotFound
+	}
+
+	jsonItems, err := q.r.Do(ctx, q.r.B().Hmget().Key(q.kg.QueueItem()).Field(ids...).Build()).AsStrSlice()
+	if err != nil {
+		return fmt.Errorf("error reading jobs: %w", err)
+	}
+
+	for _, str := range jsonItems {
+		if len(str) == 0 {
+			continue
+		}
+		qi := &QueueItem{}
+		if err := json.Unmarshal([]byte(str), qi); err != nil {
+			return fmt.Errorf("error unmarshalling queue item: %w", err)
+		}
+		if qi.Data.Kind != osqueue.KindStart {
+			continue
+		}
+		if qi.IsLeased(getNow()) {
+			return ErrQueueItemAlreadyLeased
+		}
+		p := QueuePartition{
+			QueueName:   qi.QueueName,
+			WorkflowID:  qi.WorkflowID,
+			WorkspaceID: qi.WorkspaceID,
+		}
+		return q.Dequeue(ctx, p, *qi)
+	}
+
+	return osqueue.ErrJobNotFound
+}
+
+// CountConcurrencyKeys records the custom concurrency keys used by a run of the
+// given function within HyperLogLogs, one per limit and window, returning the
+// approximate number of distinct keys for each limit within the current window.
+func (q *queue) CountConcurrencyKeys(ctx context.Context, fnID uuid.UUID, keys []state.CustomConcurrency) (map[string]int64, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	window := consts.ConcurrencyKeyCardinalityWindow
+	bucket := getNow().Truncate(window).Unix()
+
+	cmds := make(rueidis.Commands, 0, len(keys)*3)
+	for _, k := range keys {
+		key := q.kg.ConcurrencyKeyCardinality(fnID, k.Hash, bucket)
+		cmds = append(cmds,
+			q.r.B().Pfadd().Key(key).Element(k.Key).Build(),
+			q.r.B().Expire().Key(key).Seconds(int64(window.Seconds())).Build(),
+			q.r.B().Pfcount().Key(key).Build(),
+		)
+	}
+
+	res := q.r.DoMulti(ctx, cmds...)
+	counts := make(map[string]int64, len(keys))
+	for n, k := range keys {
+		count, err := res[n*3+2].AsInt64()
+		if err != nil {
+			return nil, fmt.Errorf("error counting concurrency keys: %w", err)
+		}
+		counts[k.Hash] = count
+	}
+	return counts, nil
+}
+
+// itemRunsStep returns whether the queue item executes the given step.  Sleeps
+// match the step that scheduled the sleep.
+func itemRunsStep(qi QueueItem, stepID string) bool {
+	payload, err := osqueue.GetEdge(qi.Data)
+	if err != nil {
+		// Pause timeouts and other jobs don't run steps.
+		return false
+	}
+	edge := payload.Edge
+	if qi.Data.Kind == osqueue.KindSleep {
+		return edge.Outgoing == stepID
+	}
+	return edge.IncomingGeneratorStep == stepID || edge.Incoming == stepID
+}
+
 // Lease temporarily dequeues an item from the queue by obtaining a lease, preventing
 // other workers from working on this queue item at the same time.
 //
 // Obtaining a lease updates the vesting time for the queue item until now() +
 // lease duration. This returns the newly acquired lease ID on success.
 func (q *queue) Lease(ctx context.Context, p QueuePartition, item QueueItem, duration time.Duration, now time.Time, denies *leaseDenies) (*ulid.ULID, error) {
+	req, err := q.leaseRequest(ctx, p, item, duration, now, denies)
+	if err != nil {
+		return nil, err
+	}
+	return q.leaseOne(ctx, req, now, denies)
+}
+
+// leaseOne leases a single prepared item, leasing the item's ordered keys first.
+func (q *queue) leaseOne(ctx context.Context, req *leaseRequest, now time.Time, denies *leaseDenies) (*ulid.ULID, error) {
+	if len(req.orderedKeys) > 0 {
+		if err := q.leaseOrdered(ctx, req.item, req.orderedKeys, now, denies); err != nil {
+			return nil, err
+		}
+	}
+
+	status, err := scripts["queue/lease"].Exec(
+		ctx,
+		q.r,
+		req.keys,
+		req.args,
+	).ToInt64()
+	if err != nil {
+		return nil, fmt.Errorf("error leasing queue item: %w", err)
+	}
+	return req.result(status)
+}
+
+// LeaseMany leases each of a partition's items in order, as with calling Lease for
+// each item, within as few round trips as possible.  This returns the lease ID or
+// error for each item.
+//
+// Leasing stops at the first item which fails with a function, app or account
+// concurrency limit or an unexpected error, as no later items may be leased without
+// breaking ordering.  Later items are never leased, and have a nil lease ID and error.
+//
+// The throttle, custom concurrency and ordered keys of items which can't be leased
+// are added to denies, so that later items with the same keys aren't leased.
+func (q *queue) LeaseMany(ctx context.Context, p QueuePartition, items []QueueItem, duration time.Duration, now time.Time, denies *leaseDenies) ([]*ulid.ULID, []error) {
 	var (
-		ak, pk string // account, partition concurrency key
-		ac, pc int    // account, partiiton concurrency max
+		ids  = make([]*ulid.ULID, len(items))
+		errs = make([]error, len(items))
+
+		pending []*leaseRequest
+		idx     []int
+	)
+
+	// flush leases each pending item within a single script, returning false if
+	// leasing must stop.
+	flush := func() bool {
+		if len(pending) == 0 {
+			return true
+		}
+		keys, args := []string{}, []string{}
+		for _, req := range pending {
+			keys = append(keys, req.keys...)
+			args = append(args, req.args...)
+		}
+		statuses, err := scripts["queue/leaseMany"].Exec(ctx, q.r, keys, args).AsIntSlice()
+		if err != nil {
+			errs[idx[0]] = fmt.Errorf("error leasing queue items: %w", err)
+			return false
+		}
+
+		for i, status := range statuses {
+			n := idx[i]
+			ids[n], errs[n] = pending[i].result(status)
+			denies.add(errs[n])
+			if !continueLeasing(errs[n]) {
+				return false
+			}
+		}
+		pending, idx = pending[:0], idx[:0]
+		return true
+	}
+
+	for n, item := range items {
+		req, err := q.leaseRequest(ctx, p, item, duration, now, denies)
+		if err == nil && len(req.orderedKeys) == 0 {
+			pending = append(pending, req)
+			idx = append(idx, n)
+			continue
+		}
+
+		// Items are leased in order, so pending items must be leased before this
+		// item's error is returned or its ordered keys are leased.
+		if !flush() {
+			return ids, errs
+		}
+		if err == nil {
+			ids[n], errs[n] = q.leaseOne(ctx, req, now, denies)
+		} else {
+			errs[n] = err
+		}
+		denies.add(errs[n])
+		if !continueLeasing(errs[n]) {
+			return ids, errs
+		}
+	}
+	flush()
+	return ids, errs
+}
+
+// continueLeasing returns whether items after an item that failed to lease with the
+// given error may be leased.
+func continueLeasing(err error) bool {
+	var key keyError
+	if errors.As(err, &key) {
+		err = key.cause
+	}
+	switch err {
+	case nil, ErrQueueItemThrottled, ErrQueueItemOrdered, ErrConcurrencyLimitCustomKey0,
+		ErrConcurrencyLimitCustomKey1, ErrQueueItemNotFound, ErrQueueItemAlreadyLeased:
+		return true
+	default:
+		return false
+	}
+}
+
+// leaseRequest is a prepared lease of a single queue item.
+type leaseRequest struct {
+	item    QueueItem
+	leaseID ulid.ULID
+	keys    []string
+	args    []string
+
+	// pk, ak and apk are the partition, account and app concurrency keys.
+	pk, ak, apk string
+	customKeys  []string
+	orderedKeys []string
+}
+
+// leaseRequest prepares the lease of the given item, returning an error if the item
+// can't be leased because its keys have been denied.
+func (q *queue) leaseRequest(ctx context.Context, p QueuePartition, item QueueItem, duration time.Duration, now time.Time, denies *leaseDenies) (*leaseRequest, error) {
+	var (
+		ak, pk, apk string // account, partition, app concurrency key
+		ac, pc, apc int    // account, partiiton, app concurrency max
 
 		customKeys   = make([]string, 2)
 		customLimits = make([]int, 2)
+		customHashes = make([]string, 2)
+		orderedKeys  []string
 	)
 
 	if item.Data.Throttle != nil && denies != nil && denies.denyThrottle(item.Data.Throttle.Key) {
@@ -1023,6 +1908,12 @@ func (q *queue) Lease(ctx context.Context, p QueuePartition, item QueueItem, dur
 			return nil, ErrAccountConcurrencyLimit
 		}
 	}
+	if q.appConcurrencyGen != nil {
+		apk, apc = q.appConcurrencyGen(ctx, item)
+		if denies != nil && denies.denyConcurrency(apk) {
+			return nil, ErrAppConcurrencyLimit
+		}
+	}
 	if q.customConcurrencyGen != nil {
 		// Get the custom concurrency key, if available.
 		for i, item := range q.customConcurrencyGen(ctx, item) {
@@ -1042,6 +1933,10 @@ func (q *queue) Lease(ctx context.Context, p QueuePartition, item QueueItem, dur
 
 			customKeys[i] = item.Key
 			customLimits[i] = item.Limit
+			customHashes[i] = item.Hash
+			if item.Ordered {
+				orderedKeys = append(orderedKeys, item.Key)
+			}
 		}
 	}
 
@@ -1069,7 +1964,20 @@ func (q *queue) Lease(ctx context.Context, p QueuePartition, item QueueItem, dur
 		q.kg.GlobalPartitionIndex(),
 		q.kg.ShardPartitionIndex(shardName),
 		q.kg.ThrottleKey(item.Data.Throttle),
+		q.kg.Concurrency("app", apk),
+		q.kg.ThrottleOverrides(),
+		q.kg.ConcurrencyOverrides(),
 	}
+
+	useThrottleOverrides := 0
+	if q.throttleOverrides {
+		useThrottleOverrides = 1
+	}
+	useConcurrencyOverrides := 0
+	if q.concurrencyOverrides {
+		useConcurrencyOverrides = 1
+	}
+
 	args, err := StrSlice([]any{
 		item.ID,
 		leaseID.String(),
@@ -1079,37 +1987,50 @@ func (q *queue) Lease(ctx context.Context, p QueuePartition, item QueueItem, dur
 		customLimits[0],
 		customLimits[1],
 		p.Queue(),
+		apc,
+		useThrottleOverrides,
+		useConcurrencyOverrides,
+		customHashes[0],
+		customHashes[1],
 	})
 	if err != nil {
 		return nil, err
 	}
-	status, err := scripts["queue/lease"].Exec(
-		ctx,
-		q.r,
-		keys,
-		args,
-	).ToInt64()
-	if err != nil {
-		return nil, fmt.Errorf("error leasing queue item: %w", err)
-	}
+	return &leaseRequest{
+		item:        item,
+		leaseID:     leaseID,
+		keys:        keys,
+		args:        args,
+		pk:          pk,
+		ak:          ak,
+		apk:         apk,
+		customKeys:  customKeys,
+		orderedKeys: orderedKeys,
+	}, nil
+}
+
+// result returns the lease ID or error for the given lease.lua status.
+func (r leaseRequest) result(status int64) (*ulid.ULID, error) {
 	switch status {
 	case 0:
-		return &leaseID, nil
+		return &r.leaseID, nil
 	case 1:
 		return nil, ErrQueueItemNotFound
 	case 2:
 		return nil, ErrQueueItemAlreadyLeased
 	case 3:
 		// fn limit relevant to all runs in the fn
-		return nil, newKeyError(ErrPartitionConcurrencyLimit, pk)
+		return nil, newKeyError(ErrPartitionConcurrencyLimit, r.pk)
 	case 4:
-		return nil, newKeyError(ErrAccountConcurrencyLimit, ak)
+		return nil, newKeyError(ErrAccountConcurrencyLimit, r.ak)
 	case 5:
-		return nil, newKeyError(ErrConcurrencyLimitCustomKey0, customKeys[0])
+		return nil, newKeyError(ErrConcurrencyLimitCustomKey0, r.customKeys[0])
 	case 6:
-		return nil, newKeyError(ErrConcurrencyLimitCustomKey1, customKeys[1])
+		return nil, newKeyError(ErrConcurrencyLimitCustomKey1, r.customKeys[1])
 	case 7:
-		return nil, newKeyError(ErrQueueItemThrottled, item.Data.Throttle.Key)
+		return nil, newKeyError(ErrQueueItemThrottled, r.item.Data.Throttle.Key)
+	case 8:
+		return nil, newKeyError(ErrAppConcurrencyLimit, r.apk)
 	default:
 		return nil, fmt.Errorf("unknown response enqueueing item: %d", status)
 	}
@@ -1125,8 +2046,8 @@ func (q *queue) Lease(ctx context.Context, p QueuePartition, item QueueItem, dur
 // lease duration. This returns the newly acquired lease ID on success.
 func (q *queue) ExtendLease(ctx context.Context, p QueuePartition, i QueueItem, leaseID ulid.ULID, duration time.Duration) (*ulid.ULID, error) {
 	var (
-		ak, pk     string // account, partition, custom concurrency key
-		customKeys = make([]string, 2)
+		ak, pk, apk string // account, partition, app concurrency key
+		customKeys  = make([]string, 2)
 	)
 	// required
 	pk, _ = q.partitionConcurrencyGen(ctx, p)
@@ -1134,6 +2055,9 @@ func (q *queue) ExtendLease(ctx context.Context, p QueuePartition, i QueueItem,
 	if q.accountConcurrencyGen != nil {
 		ak, _ = q.accountConcurrencyGen(ctx, i)
 	}
+	if q.appConcurrencyGen != nil {
+		apk, _ = q.appConcurrencyGen(ctx, i)
+	}
 	if q.customConcurrencyGen != nil {
 		// Get the custom concurrency key, if available.
 		for n, item := range q.customConcurrencyGen(ctx, i) {
@@ -1158,6 +2082,7 @@ func (q *queue) ExtendLease(ctx context.Context, p QueuePartition, i QueueItem,
 		q.kg.Concurrency("p", pk),
 		q.kg.Concurrency("custom", customKeys[0]),
 		q.kg.Concurrency("custom", customKeys[1]),
+		q.kg.Concurrency("app", apk),
 	}
 
 	args, err := StrSlice([]any{
@@ -1195,8 +2120,8 @@ func (q *queue) ExtendLease(ctx context.Context, p QueuePartition, i QueueItem,
 // Dequeue removes an item from the queue entirely.
 func (q *queue) Dequeue(ctx context.Context, p QueuePartition, i QueueItem) error {
 	var (
-		ak, pk     string // account, partition, custom concurrency key
-		customKeys = make([]string, 2)
+		ak, pk, apk string // account, partition, app concurrency key
+		customKeys  = make([]string, 2)
 	)
 	// required
 	pk, _ = q.partitionConcurrencyGen(ctx, p)
@@ -1204,6 +2129,9 @@ func (q *queue) Dequeue(ctx context.Context, p QueuePartition, i QueueItem) erro
 	if q.accountConcurrencyGen != nil {
 		ak, _ = q.accountConcurrencyGen(ctx, i)
 	}
+	if q.appConcurrencyGen != nil {
+		apk, _ = q.appConcurrencyGen(ctx, i)
+	}
 	if q.customConcurrencyGen != nil {
 		// Get the custom concurrency key, if available.
 		for n, item := range q.customConcurrencyGen(ctx, i) {
@@ -1226,6 +2154,7 @@ func (q *queue) Dequeue(ctx context.Context, p QueuePartition, i QueueItem) erro
 		q.kg.Concurrency("custom", customKeys[0]),
 		q.kg.Concurrency("custom", customKeys[1]),
 		q.kg.ConcurrencyIndex(),
+		q.kg.Concurrency("app", apk),
 	}
 	// Append indexes
 	for _, idx := range q.itemIndexer(ctx, i, q.kg) {
@@ -1278,8 +2207,8 @@ func (q *queue) Requeue(ctx context.Context, p QueuePartition, i QueueItem, at t
 	}
 
 	var (
-		ak, pk     string // account, partition, custom concurrency key
-		customKeys = make([]string, 2)
+		ak, pk, apk string // account, partition, app concurrency key
+		customKeys  = make([]string, 2)
 	)
 
 	// required
@@ -1288,6 +2217,9 @@ func (q *queue) Requeue(ctx context.Context, p QueuePartition, i QueueItem, at t
 	if q.accountConcurrencyGen != nil {
 		ak, _ = q.accountConcurrencyGen(ctx, i)
 	}
+	if q.appConcurrencyGen != nil {
+		apk, _ = q.appConcurrencyGen(ctx, i)
+	}
 	if q.customConcurrencyGen != nil {
 		// Get the custom concurrency key, if available.
 		for n, item := range q.customConcurrencyGen(ctx, i) {
@@ -1326,6 +2258,7 @@ func (q *queue) Requeue(ctx context.Context, p QueuePartition, i QueueItem, at t
 		q.kg.Concurrency("custom", customKeys[1]),
 		q.kg.ConcurrencyIndex(),
 		q.kg.ShardPartitionIndex(shardName),
+		q.kg.Concurrency("app", apk),
 	}
 	// Append indexes
 	for _, idx := range q.itemIndexer(ctx, i, q.kg) {
@@ -1334,8 +2267,13 @@ func (q *queue) Requeue(ctx context.Context, p QueuePartition, i QueueItem, at t
 		}
 	}
 
+	stored, err := q.storedItem(i)
+	if err != nil {
+		return err
+	}
+
 	args, err := StrSlice([]any{
-		i,
+		stored,
 		i.ID,
 		at.UnixMilli(),
 		i.Queue(),
@@ -1396,6 +2334,7 @@ func (q *queue) PartitionLease(ctx context.Context, p *QueuePartition, duration
 		q.kg.GlobalPartitionIndex(),
 		q.kg.ShardPartitionIndex(shardName),
 		q.kg.Concurrency("p", concurrencyKey),
+		q.kg.PausedPartitions(),
 	}
 
 	args, err := StrSlice([]any{
@@ -1426,6 +2365,8 @@ func (q *queue) PartitionLease(ctx context.Context, p *QueuePartition, duration
 		return nil, ErrPartitionNotFound
 	case -3:
 		return nil, ErrPartitionAlreadyLeased
+	case -4:
+		return nil, ErrPartitionPaused
 	default:
 		// Update the partition's last indicator.
 		if result > p.Last {
@@ -1990,6 +2931,7 @@ func newLeaseDenyList() *leaseDenies {
 		lock:        &sync.RWMutex{},
 		concurrency: map[string]struct{}{},
 		throttle:    map[string]struct{}{},
+		ordered:     map[string]struct{}{},
 	}
 }
 
@@ -2005,6 +2947,7 @@ type leaseDenies struct {
 
 	concurrency map[string]struct{}
 	throttle    map[string]struct{}
+	ordered     map[string]struct{}
 }
 
 func (l *leaseDenies) addThrottled(err error) {
@@ -2027,6 +2970,33 @@ func (l *leaseDenies) addConcurrency(err error) {
 	l.lock.Unlock()
 }
 
+func (l *leaseDenies) addOrdered(err error) {
+	var key keyError
+	if !errors.As(err, &key) {
+		return
+	}
+	l.lock.Lock()
+	l.ordered[key.key] = struct{}{}
+	l.lock.Unlock()
+}
+
+// add denies the key of an item which failed to lease with the given error, if the
+// error's key may be denied.
+func (l *leaseDenies) add(err error) {
+	var key keyError
+	if l == nil || !errors.As(err, &key) {
+		return
+	}
+	switch key.cause {
+	case ErrQueueItemThrottled:
+		l.addThrottled(err)
+	case ErrConcurrencyLimitCustomKey0, ErrConcurrencyLimitCustomKey1:
+		l.addConcurrency(err)
+	case ErrQueueItemOrdered:
+		l.addOrdered(err)
+	}
+}
+
 func (l *leaseDenies) denyConcurrency(key string) bool {
 	l.lock.RLock()
 	_, ok := l.concurrency[key]
@@ -2040,3 +3010,10 @@ func (l *leaseDenies) denyThrottle(key string) bool {
 	l.lock.RUnlock()
 	return ok
 }
+
+func (l *leaseDenies) denyOrdered(key string) bool {
+	l.lock.RLock()
+	_, ok := l.ordered[key]
+	l.lock.RUnlock()
+	return ok
+}
@@ -4,6 +4,9 @@ import "fmt"
 
 var (
 	ErrQueueItemThrottled = fmt.Errorf("queue item throttled")
+	// ErrQueueItemOrdered is returned when leasing a run's first queue item while
+	// another run holds one of its ordered concurrency keys.
+	ErrQueueItemOrdered = fmt.Errorf("queue item waiting for ordered run")
 )
 
 func newKeyError(err error, key string) error {
@@ -14,7 +17,7 @@ func newKeyError(err error, key string) error {
 }
 
 // keyError is an error string which represents the custom key used when returning a
-// concurrency or throttled error.  The ErrQueueItemThrottled error must wrap this keyError
+// concurrency, throttled or ordered error.  The ErrQueueItemThrottled error must wrap this keyError
 // to embed the key directly in the top-level error class.
 type keyError struct {
 	key   string
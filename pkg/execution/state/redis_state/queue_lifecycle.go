@@ -15,3 +15,12 @@ type QueueLifecycleListener interface {
 	// partition, or custom).
 	OnConcurrencyLimitReached(ctx context.Context, fnID uuid.UUID)
 }
+
+// QueueThrottleListener is an optional interface that QueueLifecycleListeners
+// can fulfil, called when a queue item can't be leased as its function's
+// throttle limit has been reached.
+type QueueThrottleListener interface {
+	// OnThrottled is called each time a throttled queue item is skipped whilst
+	// scanning its partition.
+	OnThrottled(ctx context.Context, item QueueItem)
+}
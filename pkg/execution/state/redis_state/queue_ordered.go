@@ -0,0 +1,96 @@
+package redis_state
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	osqueue "github.com/inngest/inngest/pkg/execution/queue"
+	"github.com/inngest/inngest/pkg/execution/state"
+	"github.com/inngest/inngest/pkg/telemetry"
+)
+
+// leaseOrdered leases the locks for the item's ordered concurrency keys, such
+// that runs sharing an ordered key start one at a time in queue order.  Only a
+// run's first item waits for the locks;  the run's later items extend the locks
+// until the run finishes and ReleaseOrdered is called.
+func (q *queue) leaseOrdered(ctx context.Context, item QueueItem, keys []string, now time.Time, denies *leaseDenies) error {
+	start := item.Data.Kind == osqueue.KindStart
+	if start && denies != nil {
+		// A run with the same key was denied earlier in this scan.  Skip this item
+		// so that runs start in order.
+		for _, key := range keys {
+			if denies.denyOrdered(key) {
+				return newKeyError(ErrQueueItemOrdered, key)
+			}
+		}
+	}
+
+	locks := make([]string, len(keys))
+	for n, key := range keys {
+		locks[n] = q.kg.OrderedLock(key)
+	}
+	args, err := StrSlice([]any{
+		item.Data.Identifier.RunID.String(),
+		int(OrderedLockTTL.Seconds()),
+	})
+	if err != nil {
+		return err
+	}
+	status, err := scripts["queue/orderedLease"].Exec(
+		ctx,
+		q.r,
+		locks,
+		args,
+	).ToInt64()
+	if err != nil {
+		return fmt.Errorf("error leasing ordered keys: %w", err)
+	}
+	if !start {
+		// Items after the run's first never wait, even if the lock has expired and
+		// was taken by another run.
+		return nil
+	}
+
+	switch {
+	case status == 0:
+		at := item.WallTimeMS
+		if at == 0 {
+			at = item.AtMS
+		}
+		if delay := now.UnixMilli() - at; delay > 0 {
+			telemetry.HistogramQueueOrderedDelay(ctx, delay, telemetry.HistogramOpt{
+				PkgName: pkgName,
+			})
+		}
+	case status > 0 && int(status) <= len(keys):
+		return newKeyError(ErrQueueItemOrdered, keys[status-1])
+	}
+	return nil
+}
+
+// ReleaseOrdered releases the run's ordered concurrency keys once the run
+// finishes, allowing the next run with the same keys to start.  Keys held by
+// other runs are left untouched, so this is safe to call more than once.
+func (q *queue) ReleaseOrdered(ctx context.Context, id state.Identifier) error {
+	locks := []string{}
+	for _, c := range id.CustomConcurrencyKeys {
+		if c.Ordered {
+			locks = append(locks, q.kg.OrderedLock(c.Key))
+		}
+	}
+	if len(locks) == 0 {
+		return nil
+	}
+
+	err := scripts["queue/orderedRelease"].Exec(
+		ctx,
+		q.r,
+		locks,
+		[]string{id.RunID.String()},
+	).Error()
+	if err != nil {
+		return fmt.Errorf("error releasing ordered keys: %w", err)
+	}
+	return nil
+}
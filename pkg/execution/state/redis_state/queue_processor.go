@@ -4,8 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"math/rand"
+	"net"
 	"runtime/debug"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -15,6 +18,7 @@ import (
 	"github.com/inngest/inngest/pkg/execution/state"
 	"github.com/inngest/inngest/pkg/telemetry"
 	"github.com/oklog/ulid/v2"
+	"github.com/redis/rueidis"
 	"golang.org/x/sync/errgroup"
 	"golang.org/x/sync/semaphore"
 	"gonum.org/v1/gonum/stat/sampleuv"
@@ -32,6 +36,13 @@ var (
 	ShardLeaseTime = 10 * time.Second
 
 	maxShardLeaseAttempts = 10
+
+	// FailoverBackoffMin is the initial delay used when retrying after losing
+	// connectivity to Redis, eg. during a Sentinel failover.
+	FailoverBackoffMin = 50 * time.Millisecond
+	// FailoverBackoffMax is the maximum delay used when retrying after losing
+	// connectivity to Redis.
+	FailoverBackoffMax = 5 * time.Second
 )
 
 var (
@@ -76,6 +87,48 @@ func GetItemConcurrencyLatency(ctx context.Context) (time.Duration, bool) {
 }
 
 func (q *queue) Enqueue(ctx context.Context, item osqueue.Item, at time.Time) error {
+	qi, next := q.queueItem(ctx, item, at)
+	_, err := q.EnqueueItem(ctx, qi, next)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+var _ osqueue.BulkProducer = &queue{}
+var _ osqueue.HealthReporter = &queue{}
+
+// EnqueueMany enqueues each item, pipelining the queue's scripts so that every
+// item is enqueued in a single round trip.
+func (q *queue) EnqueueMany(ctx context.Context, items []osqueue.ItemAt) []error {
+	errs := make([]error, len(items))
+	execs := []rueidis.LuaExec{}
+	pending := []int{}
+	for n, item := range items {
+		qi, next := q.queueItem(ctx, item.Item, item.At)
+		_, keys, args, err := q.enqueueArgs(ctx, qi, next)
+		if err != nil {
+			errs[n] = err
+			continue
+		}
+		execs = append(execs, rueidis.LuaExec{Keys: keys, Args: args})
+		pending = append(pending, n)
+	}
+	if len(execs) == 0 {
+		return errs
+	}
+
+	for i, res := range scripts["queue/enqueue"].ExecMulti(ctx, q.r, execs...) {
+		status, err := res.AsInt64()
+		errs[pending[i]] = enqueueResult(status, err)
+	}
+	return errs
+}
+
+// queueItem returns the queue item for the given item, and the time that it
+// should be enqueued at.
+func (q *queue) queueItem(ctx context.Context, item osqueue.Item, at time.Time) (QueueItem, time.Time) {
 	// propagate
 	if item.Metadata == nil {
 		item.Metadata = map[string]string{}
@@ -121,17 +174,17 @@ func (q *queue) Enqueue(ctx context.Context, item osqueue.Item, at time.Time) er
 		qi.AtMS -= factor
 	}
 
-	_, err := q.EnqueueItem(ctx, qi, next)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return qi, next
 }
 
 func (q *queue) Run(ctx context.Context, f osqueue.RunFunc) error {
+	// jobCtx is the parent context for in-progress jobs.  It isn't cancelled
+	// when ctx is, allowing jobs to finish while the queue drains on shutdown.
+	jobCtx, cancelJobs := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelJobs()
+
 	for i := int32(0); i < q.numWorkers; i++ {
-		go q.worker(ctx, f)
+		go q.worker(ctx, jobCtx, f)
 	}
 
 	go q.queueGauges(ctx)
@@ -140,6 +193,8 @@ func (q *queue) Run(ctx context.Context, f osqueue.RunFunc) error {
 	go q.runScavenger(ctx)
 
 	tick := time.NewTicker(q.pollTick)
+	// scanFailures records the number of consecutive transient scan errors.
+	scanFailures := 0
 
 	q.logger.Debug().
 		Str("poll", q.pollTick.String()).
@@ -165,26 +220,103 @@ LOOP:
 				// have capacity to run at least MinWorkersFree concurrent
 				// QueueItems.  This reduces latency of enqueued items when
 				// there are lots of enqueued and available jobs.
+				q.lastScanAt.Store(getNow().UnixMilli())
 				continue
 			}
 
 			if err := q.scan(ctx); err != nil {
+				if isTransientErr(err) {
+					// Redis is unavailable, eg. while failing over to a new primary.
+					// Back off and keep scanning once the connection recovers.
+					wait := failoverBackoff(scanFailures)
+					scanFailures++
+					q.logger.Warn().Err(err).Dur("backoff", wait).Msg("redis unavailable while scanning partition pointers")
+					select {
+					case <-ctx.Done():
+					case <-time.After(wait):
+					}
+					continue
+				}
 				// On scan errors, halt the worker entirely.
 				if errors.Unwrap(err) != context.Canceled {
 					q.logger.Error().Err(err).Msg("error scanning partition pointers")
 				}
 				break LOOP
 			}
+			scanFailures = 0
+			q.lastScanAt.Store(getNow().UnixMilli())
 		}
 	}
 
+	// Stop leasing new items, releasing any leased items which haven't started.
+	q.releaseBuffered(ctx)
+
 	// Wait for all in-progress items to complete.
-	q.logger.Info().Msg("queue waiting to quit")
-	q.wg.Wait()
+	q.logger.Info().Dur("timeout", q.drainTimeout).Msg("queue waiting to quit")
+	q.drain(cancelJobs)
 
 	return nil
 }
 
+// Ping checks connectivity to Redis.
+func (q *queue) Ping(ctx context.Context) error {
+	return q.r.Do(ctx, q.r.B().Ping().Build()).Error()
+}
+
+// Liveness returns the times of the processing loop's last scan and lease.
+func (q *queue) Liveness() osqueue.Liveness {
+	l := osqueue.Liveness{}
+	if ms := q.lastScanAt.Load(); ms > 0 {
+		l.LastScanAt = time.UnixMilli(ms)
+	}
+	if ms := q.lastLeaseAt.Load(); ms > 0 {
+		l.LastLeaseAt = time.UnixMilli(ms)
+	}
+	return l
+}
+
+// releaseBuffered requeues items which were leased but not yet picked up by a
+// worker, so that other workers can process them without waiting for their
+// leases to expire.
+func (q *queue) releaseBuffered(ctx context.Context) {
+	for {
+		select {
+		case i := <-q.workers:
+			if err := q.Requeue(context.WithoutCancel(ctx), i.P, i.I, time.UnixMilli(i.I.AtMS)); err != nil {
+				q.logger.Error().Err(err).Str("item_id", i.I.ID).Msg("error releasing queue item on shutdown")
+			}
+			q.releaseWorker(i.R)
+		default:
+			return
+		}
+	}
+}
+
+// drain waits up to the drain timeout for in-progress jobs to finish.  Jobs still
+// running after the timeout are cancelled, which releases them back to the queue.
+func (q *queue) drain(cancelJobs context.CancelFunc) {
+	done := make(chan struct{})
+	go func() {
+		q.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return
+	case <-time.After(q.drainTimeout):
+	}
+
+	q.logger.Warn().Dur("timeout", q.drainTimeout).Msg("drain timeout reached, releasing in-progress jobs")
+	cancelJobs()
+
+	select {
+	case <-done:
+	case <-time.After(drainReleaseTimeout):
+		q.logger.Error().Msg("in-progress jobs did not release after cancellation")
+	}
+}
+
 func (q *queue) claimShards(ctx context.Context) {
 	if q.sf == nil {
 		// TODO: Inspect denylists and whether this worker is capable of leasing
@@ -419,8 +551,13 @@ func (q *queue) claimSequentialLease(ctx context.Context) {
 	// Attempt to claim the lease immediately.
 	leaseID, err := q.ConfigLease(ctx, q.kg.Sequential(), ConfigLeaseDuration, q.sequentialLease())
 	if err != ErrConfigAlreadyLeased && err != nil {
-		q.quit <- err
-		return
+		if !isTransientErr(err) {
+			q.quit <- err
+			return
+		}
+		// Attempt to claim the lease on the next tick.
+		q.logger.Warn().Err(err).Msg("redis unavailable while claiming sequential lease")
+		leaseID = nil
 	}
 
 	q.seqLeaseLock.Lock()
@@ -467,8 +604,13 @@ func (q *queue) runScavenger(ctx context.Context) {
 	// Attempt to claim the lease immediately.
 	leaseID, err := q.ConfigLease(ctx, q.kg.Scavenger(), ConfigLeaseDuration, q.scavengerLease())
 	if err != ErrConfigAlreadyLeased && err != nil {
-		q.quit <- err
-		return
+		if !isTransientErr(err) {
+			q.quit <- err
+			return
+		}
+		// Attempt to claim the lease on the next tick.
+		q.logger.Warn().Err(err).Msg("redis unavailable while claiming scavenger lease")
+		leaseID = nil
 	}
 
 	q.scavengerLeaseLock.Lock()
@@ -528,7 +670,7 @@ func (q *queue) runScavenger(ctx context.Context) {
 
 // worker runs a blocking process that listens to items being pushed into the
 // worker channel.  This allows us to process an individual item from a queue.
-func (q *queue) worker(ctx context.Context, f osqueue.RunFunc) {
+func (q *queue) worker(ctx context.Context, jobCtx context.Context, f osqueue.RunFunc) {
 	for {
 		select {
 		case <-ctx.Done():
@@ -537,11 +679,13 @@ func (q *queue) worker(ctx context.Context, f osqueue.RunFunc) {
 			return
 		case i := <-q.workers:
 			// Create a new context which isn't cancelled by the parent, when quit.
+			// It's only cancelled if the job doesn't finish within the drain
+			// timeout when shutting down.
 			// XXX: When jobs can have their own cancellation signals, move this into
 			// process itself.
-			processCtx, cancel := context.WithCancel(context.Background())
+			processCtx, cancel := context.WithCancel(jobCtx)
 			err := q.process(processCtx, i.P, i.I, i.S, f)
-			q.sem.Release(1)
+			q.releaseWorker(i.R)
 			cancel()
 			if err == nil {
 				continue
@@ -567,6 +711,12 @@ func (q *queue) scan(ctx context.Context) error {
 		metricShardName = "<global>" // default global name for metrics in this function
 	)
 
+	// Reserved functions are always processed first, ensuring that they have
+	// capacity regardless of the global backlog.
+	if err := q.scanReserved(ctx); err != nil {
+		return err
+	}
+
 	// By default, use the global partition
 	partitionKey := q.kg.GlobalPartitionIndex()
 
@@ -650,6 +800,11 @@ func (q *queue) processPartition(ctx context.Context, p *QueuePartition, shard *
 		telemetry.IncrQueuePartitionConcurrencyLimitCounter(ctx, telemetry.CounterOpt{PkgName: pkgName})
 		return q.PartitionRequeue(ctx, p, getNow().Truncate(time.Second).Add(PartitionConcurrencyLimitRequeueExtension), true)
 	}
+	if err == ErrPartitionPaused {
+		// Force the partition into the future so that enqueueing new items
+		// doesn't bring it forward.  Resuming the partition requeues it.
+		return q.PartitionRequeue(ctx, p, getNow().Truncate(time.Second).Add(PartitionPausedRequeueExtension), true)
+	}
 	if err == ErrPartitionAlreadyLeased {
 		telemetry.IncrQueuePartitionLeaseContentionCounter(ctx, telemetry.CounterOpt{PkgName: pkgName})
 		return nil
@@ -714,24 +869,45 @@ func (q *queue) processPartition(ctx context.Context, p *QueuePartition, shard *
 
 	denies := newLeaseDenyList()
 
+	items := queue
 ProcessLoop:
-	for _, item := range queue {
-		// TODO: Create an in-memory mapping of rate limit keys that have been hit,
-		//       and don't bother to process if the queue item has a limited key.  This
-		//       lessens work done in the queue, as we can `continue` immediately.
-		if item.IsLeased(getNow()) {
-			telemetry.IncrQueueItemLeaseContentionCounter(ctx, telemetry.CounterOpt{PkgName: pkgName})
-			continue
-		}
-
+	for len(items) > 0 {
 		// Cbeck if there's capacity from our local workers atomically prior to leasing our tiems.
-		if !q.sem.TryAcquire(1) {
-			telemetry.IncrQueuePartitionProcessNoCapacityCounter(ctx, telemetry.CounterOpt{PkgName: pkgName})
-			// Break the entire loop to prevent out of order work.
-			break ProcessLoop
+		// This consults the item's capacity reservation prior to general capacity.  Workers are
+		// claimed for a batch of items so that the batch is leased within a single round trip.
+		var (
+			batch        []*QueueItem
+			reservations []*reservation
+			noCapacity   bool
+		)
+		for len(items) > 0 && len(batch) < QueueLeaseBatchSize {
+			item := items[0]
+			items = items[1:]
+
+			// TODO: Create an in-memory mapping of rate limit keys that have been hit,
+			//       and don't bother to process if the queue item has a limited key.  This
+			//       lessens work done in the queue, as we can `continue` immediately.
+			if item.IsLeased(getNow()) {
+				telemetry.IncrQueueItemLeaseContentionCounter(ctx, telemetry.CounterOpt{PkgName: pkgName})
+				continue
+			}
+
+			res, ok := q.acquireWorker(*item)
+			if !ok {
+				telemetry.IncrQueuePartitionProcessNoCapacityCounter(ctx, telemetry.CounterOpt{PkgName: pkgName})
+				// Stop claiming workers to prevent out of order work.
+				noCapacity = true
+				break
+			}
+			if q.leaseDelay > 0 {
+				// Simulate slow or contended leases, eg. when chaos testing SDKs.
+				<-time.After(time.Duration(rand.Int63n(int64(q.leaseDelay))))
+			}
+			batch = append(batch, item)
+			reservations = append(reservations, res)
 		}
 
-		// Attempt to lease this item before passing this to a worker.  We have to do this
+		// Attempt to lease these items before passing them to a worker.  We have to do this
 		// synchronously as we need to lease prior to requeueing the partition pointer. If
 		// we don't do this here, the workers may not lease the items before calling Peek
 		// to re-enqeueu the pointer, which then increases contention - as we requeue a
@@ -739,113 +915,151 @@ ProcessLoop:
 		//
 		// This is safe:  only one process runs scan(), and we guard the total number of
 		// available workers with the above semaphore.
-		leaseID, err := q.Lease(ctx, *p, *item, QueueLeaseDuration, staticTime, denies)
-
-		// NOTE: If this loop ends in an error, we must _always_ release an item from the
-		// semaphore to free capacity.  This will happen automatically when the worker
-		// finishes processing a queue item on success.
-		if err != nil {
-			// Continue on and handle the error below.
-			q.sem.Release(1)
+		leaseItems := make([]QueueItem, len(batch))
+		for n, item := range batch {
+			leaseItems[n] = *item
 		}
+		leaseIDs, leaseErrs := q.LeaseMany(ctx, *p, leaseItems, QueueLeaseDuration, staticTime, denies)
 
-		// Check the sojourn delay for this item in the queue. Tracking system latency vs
-		// sojourn latency from concurrency is important.
-		//
-		// Firstly, we check:  does the job store the first peek time?  If so, the
-		// delta between now and that time is the sojourn latency.  If not, this is either
-		// one of two cases:
-		//   - This is a new job in the queue, and we're peeking it for the first time.
-		//     Sojourn latency is 0.  Easy.
-		//   - We've peeked the queue since adding the job.  At this point, the only
-		//     conclusion is that the job wasn't peeked because of concurrency/capacity
-		//     issues, so the delta between now - job added is sojourn latency.
-		//
-		// NOTE: You might see that we use tracking semaphores and the worker itself has
-		// a maximum capacity.  We must ALWAYS peek the available capacity in our worker
-		// via the above Peek() call so that worker capacity doesn't prevent us from accessing
-		// all jobs in a peek.  This would break sojourn latency:  it only works if we know
-		// we're quitting early because of concurrency issues in a user's function, NOT because
-		// of capacity issues in our system.
-		//
-		// Anyway, here we set the first peek item to the item's start time if there was a
-		// peek since the job was added.
-		if p.Last > 0 && p.Last > item.AtMS {
-			// Fudge the earliest peek time because we know this wasn't peeked and so
-			// the peek time wasn't set;  but, as we were still processing jobs after
-			// the job was added this item was concurrency-limited.
-			item.EarliestPeekTime = item.AtMS
-		}
+		// next is the index of the first item in the batch that hasn't been handled.
+		// Workers claimed for items after leasing stops must be released.
+		next := 0
+		stop := false
 
-		// We may return a keyError, which masks the actual error underneath.  If so,
-		// grab the cause.
-		cause := err
-		var key keyError
-		if errors.As(err, &key) {
-			cause = key.cause
-		}
+	LeaseLoop:
+		for n, item := range batch {
+			next = n + 1
+			leaseID, err := leaseIDs[n], leaseErrs[n]
 
-		switch cause {
-		case ErrQueueItemThrottled:
-			// Here we denylist each throttled key that's been limited here, then ignore
-			// any other jobs from being leased as we continue to iterate through the loop.
-			// This maintains FIFO ordering amongst all custom concurrency keys.
-			denies.addThrottled(err)
+			// NOTE: If this loop ends in an error, we must _always_ release an item from the
+			// semaphore to free capacity.  This will happen automatically when the worker
+			// finishes processing a queue item on success.
+			if err != nil {
+				// Continue on and handle the error below.
+				q.releaseWorker(reservations[n])
+			} else {
+				q.lastLeaseAt.Store(getNow().UnixMilli())
+			}
 
-			ctrRateLimit++
-			processErr = nil
-			telemetry.IncrQueueThrottledCounter(ctx, telemetry.CounterOpt{PkgName: pkgName})
-			continue
-		case ErrPartitionConcurrencyLimit, ErrAccountConcurrencyLimit:
-			ctrConcurrency++
-			// Since the queue is at capacity on a fn or account level, no
-			// more jobs in this loop should be worked on - so break.
+			// Check the sojourn delay for this item in the queue. Tracking system latency vs
+			// sojourn latency from concurrency is important.
 			//
-			// Even if we have capacity for the next job in the loop we do NOT
-			// want to claim the job, as this breaks ordering guarantees.  The
-			// only safe thing to do when we hit a function or account level
-			// concurrency key.
-			processErr = nil
-			break ProcessLoop
-		case ErrConcurrencyLimitCustomKey0, ErrConcurrencyLimitCustomKey1:
-			ctrConcurrency++
-			// Custom concurrency keys are different.  Each job may have a different key,
-			// so we cannot break the loop in case the next job has a different key and
-			// has capacity.
+			// Firstly, we check:  does the job store the first peek time?  If so, the
+			// delta between now and that time is the sojourn latency.  If not, this is either
+			// one of two cases:
+			//   - This is a new job in the queue, and we're peeking it for the first time.
+			//     Sojourn latency is 0.  Easy.
+			//   - We've peeked the queue since adding the job.  At this point, the only
+			//     conclusion is that the job wasn't peeked because of concurrency/capacity
+			//     issues, so the delta between now - job added is sojourn latency.
 			//
-			// Here we denylist each concurrency key that's been limited here, then ignore
-			// any other jobs from being leased as we continue to iterate through the loop.
-			// This maintains FIFO ordering amongst all custom concurrency keys.
-			denies.addConcurrency(err)
+			// NOTE: You might see that we use tracking semaphores and the worker itself has
+			// a maximum capacity.  We must ALWAYS peek the available capacity in our worker
+			// via the above Peek() call so that worker capacity doesn't prevent us from accessing
+			// all jobs in a peek.  This would break sojourn latency:  it only works if we know
+			// we're quitting early because of concurrency issues in a user's function, NOT because
+			// of capacity issues in our system.
+			//
+			// Anyway, here we set the first peek item to the item's start time if there was a
+			// peek since the job was added.
+			if p.Last > 0 && p.Last > item.AtMS {
+				// Fudge the earliest peek time because we know this wasn't peeked and so
+				// the peek time wasn't set;  but, as we were still processing jobs after
+				// the job was added this item was concurrency-limited.
+				item.EarliestPeekTime = item.AtMS
+			}
 
-			processErr = nil
-			continue
-		case ErrQueueItemNotFound:
-			// This is an okay error.  Move to the next job item.
-			ctrSuccess++ // count as a success for stats purposes.
-			processErr = nil
-			continue
-		case ErrQueueItemAlreadyLeased:
-			// This is an okay error.  Move to the next job item.
-			ctrSuccess++ // count as a success for stats purposes.
-			processErr = nil
-			continue
-		}
+			// We may return a keyError, which masks the actual error underneath.  If so,
+			// grab the cause.
+			cause := err
+			var key keyError
+			if errors.As(err, &key) {
+				cause = key.cause
+			}
 
-		// Handle other errors.
-		if err != nil {
-			processErr = fmt.Errorf("error leasing in process: %w", err)
-			break ProcessLoop
-		}
+			switch cause {
+			case ErrQueueItemThrottled:
+				// LeaseMany denylists each throttled key that's been limited here, ignoring
+				// any other jobs from being leased as we continue to iterate through the loop.
+				// This maintains FIFO ordering amongst all custom concurrency keys.
+				for _, l := range q.lifecycles {
+					if t, ok := l.(QueueThrottleListener); ok {
+						go t.OnThrottled(context.WithoutCancel(ctx), *item)
+					}
+				}
 
-		// Assign the lease ID and pass this to be handled by the available worker.
-		// There should always be capacity on this queue as we track capacity via
-		// a semaphore.
-		item.LeaseID = leaseID
+				ctrRateLimit++
+				processErr = nil
+				telemetry.IncrQueueThrottledCounter(ctx, telemetry.CounterOpt{PkgName: pkgName})
+				continue
+			case ErrQueueItemOrdered:
+				// Another run holds this run's ordered concurrency key.  LeaseMany denylists
+				// the key so that later runs with the same key aren't started before this one,
+				// while still leasing other items.  As with throttling, this doesn't
+				// requeue the partition, as the run holding the key must continue.
+
+				ctrRateLimit++
+				processErr = nil
+				telemetry.IncrQueueOrderedCounter(ctx, telemetry.CounterOpt{PkgName: pkgName})
+				continue
+			case ErrPartitionConcurrencyLimit, ErrAccountConcurrencyLimit, ErrAppConcurrencyLimit:
+				ctrConcurrency++
+				// Since the queue is at capacity on a fn, app or account level, no
+				// more jobs in this loop should be worked on - so break.
+				//
+				// Even if we have capacity for the next job in the loop we do NOT
+				// want to claim the job, as this breaks ordering guarantees.  The
+				// only safe thing to do when we hit a function, app or account level
+				// concurrency key.
+				processErr = nil
+				stop = true
+				break LeaseLoop
+			case ErrConcurrencyLimitCustomKey0, ErrConcurrencyLimitCustomKey1:
+				ctrConcurrency++
+				// Custom concurrency keys are different.  Each job may have a different key,
+				// so we cannot break the loop in case the next job has a different key and
+				// has capacity.
+				//
+				// LeaseMany denylists each concurrency key that's been limited here, ignoring
+				// any other jobs from being leased as we continue to iterate through the loop.
+				// This maintains FIFO ordering amongst all custom concurrency keys.
+
+				processErr = nil
+				continue
+			case ErrQueueItemNotFound:
+				// This is an okay error.  Move to the next job item.
+				ctrSuccess++ // count as a success for stats purposes.
+				processErr = nil
+				continue
+			case ErrQueueItemAlreadyLeased:
+				// This is an okay error.  Move to the next job item.
+				ctrSuccess++ // count as a success for stats purposes.
+				processErr = nil
+				continue
+			}
 
-		// increase success counter.
-		ctrSuccess++
-		q.workers <- processItem{P: *p, I: *item, S: shard}
+			// Handle other errors.
+			if err != nil {
+				processErr = fmt.Errorf("error leasing in process: %w", err)
+				stop = true
+				break LeaseLoop
+			}
+
+			// Assign the lease ID and pass this to be handled by the available worker.
+			// There should always be capacity on this queue as we track capacity via
+			// a semaphore.
+			item.LeaseID = leaseID
+
+			// increase success counter.
+			ctrSuccess++
+			q.workers <- processItem{P: *p, I: *item, S: shard, R: reservations[n]}
+		}
+		for _, res := range reservations[next:] {
+			q.releaseWorker(res)
+		}
+		if stop || noCapacity {
+			break ProcessLoop
+		}
 	}
 
 	// If we've hit concurrency issues OR we've only hit rate limit issues, re-enqueue the partition
@@ -907,7 +1121,7 @@ func (q *queue) process(ctx context.Context, p QueuePartition, qi QueueItem, s *
 					// Don't extend lease when the ctx is done.
 					return
 				}
-				leaseID, err = q.ExtendLease(ctx, p, qi, *leaseID, QueueLeaseDuration)
+				leaseID, err = q.extendLease(ctx, p, qi, *leaseID)
 				if err != nil && err != ErrQueueItemNotFound && errors.Unwrap(err) != context.Canceled {
 					// XXX: Increase counter here.
 					q.logger.Error().Err(err).Msg("error extending lease")
@@ -1020,6 +1234,17 @@ func (q *queue) process(ctx context.Context, p QueuePartition, qi QueueItem, s *
 		// Job errored or extending lease errored.  Cancel the job ASAP.
 		jobCancel()
 
+		if ctx.Err() != nil {
+			// The queue is shutting down and the job didn't finish within the
+			// drain timeout.  Release the job without counting the attempt so
+			// that another worker can pick it up immediately.
+			if err := q.Requeue(context.WithoutCancel(ctx), p, qi, getNow()); err != nil {
+				q.logger.Error().Err(err).Interface("item", qi).Msg("error releasing job on shutdown")
+				return err
+			}
+			return nil
+		}
+
 		if osqueue.ShouldRetry(err, qi.Data.Attempt, qi.Data.GetMaxAttempts()) {
 			at := q.backoffFunc(qi.Data.Attempt)
 
@@ -1072,6 +1297,32 @@ func (q *queue) process(ctx context.Context, p QueuePartition, qi QueueItem, s *
 	return nil
 }
 
+// extendLease extends the lease for the given queue item, retrying transient errors
+// until the current lease expires.  This allows workers to keep their leases while
+// Redis fails over to a new primary.
+func (q *queue) extendLease(ctx context.Context, p QueuePartition, qi QueueItem, leaseID ulid.ULID) (*ulid.ULID, error) {
+	expires := ulid.Time(leaseID.Time())
+	for attempt := 0; ; attempt++ {
+		next, err := q.ExtendLease(ctx, p, qi, leaseID, QueueLeaseDuration)
+		if err == nil || !isTransientErr(err) {
+			return next, err
+		}
+
+		wait := failoverBackoff(attempt)
+		if getNow().Add(wait).After(expires) {
+			// The lease will expire before we can retry.
+			return nil, err
+		}
+		q.logger.Warn().Err(err).Str("item_id", qi.ID).Msg("redis unavailable while extending lease")
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
 // sequentialLease is a helper method for concurrently reading the sequential
 // lease ID.
 func (q *queue) sequentialLease() *ulid.ULID {
@@ -1217,3 +1468,47 @@ func (t *trackingSemaphore) Release(n int64) {
 	t.Weighted.Release(n)
 	atomic.AddInt64(&t.counter, -n)
 }
+
+// isTransientErr returns whether the error was caused by a temporary loss of
+// connectivity to Redis, eg. while Sentinel promotes a new primary.  These
+// errors are retried instead of stopping the queue.
+func isTransientErr(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	if rerr, ok := rueidis.IsRedisErr(err); ok {
+		if rerr.IsTryAgain() || rerr.IsClusterDown() {
+			return true
+		}
+		// A demoted primary rejects writes, and a new primary may still be loading
+		// its dataset.
+		msg := rerr.Error()
+		for _, prefix := range []string{"READONLY", "LOADING", "MASTERDOWN"} {
+			if strings.HasPrefix(msg, prefix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// failoverBackoff returns the jittered delay to wait after the given number of
+// consecutive transient errors.
+func failoverBackoff(attempt int) time.Duration {
+	wait := FailoverBackoffMin
+	for i := 0; i < attempt && wait < FailoverBackoffMax; i++ {
+		wait *= 2
+	}
+	if wait > FailoverBackoffMax {
+		wait = FailoverBackoffMax
+	}
+	// Add up to 20% jitter so that workers don't reconnect in lockstep.
+	return wait + time.Duration(rand.Int63n(int64(wait)/5+1))
+}
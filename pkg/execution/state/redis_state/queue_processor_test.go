@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/rand"
 	"fmt"
+	"io"
 	mrand "math/rand"
 	"sync/atomic"
 	"testing"
@@ -190,6 +191,121 @@ func TestQueueRunBasic(t *testing.T) {
 	// Assert metrics are correct.
 }
 
+func TestQueueRunClusterSlots(t *testing.T) {
+	r := miniredis.RunT(t)
+
+	rc, err := rueidis.NewClient(rueidis.ClientOption{
+		InitAddress:  []string{r.Addr()},
+		DisableCache: true,
+	})
+	require.NoError(t, err)
+	defer rc.Close()
+
+	// Queue keys are hash tagged by their prefix, so that every queue script
+	// may run within Redis Cluster.
+	q := NewQueue(
+		&slotCheckingClient{Client: rc, t: t},
+		WithNumWorkers(10),
+		WithQueueKeyGenerator(DefaultQueueKeyGenerator{Prefix: "{queue}"}),
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var handled int32
+	go func() {
+		_ = q.Run(ctx, func(ctx context.Context, info osqueue.RunInfo, item osqueue.Item) error {
+			// Fail each item's first attempt so that items are requeued.
+			if atomic.AddInt32(&handled, 1) <= 2 {
+				at := time.Now()
+				return osqueue.RetryAtError(fmt.Errorf("retry"), &at)
+			}
+			return nil
+		})
+	}()
+
+	for i := 0; i < 2; i++ {
+		id := uuid.New()
+		_, err = q.EnqueueItem(ctx, QueueItem{
+			WorkflowID: id,
+			Data: osqueue.Item{
+				Kind:        osqueue.KindEdge,
+				MaxAttempts: max(3),
+				Identifier: state.Identifier{
+					WorkflowID: id,
+					RunID:      ulid.MustNew(ulid.Now(), rand.Reader),
+				},
+			},
+		}, time.Now())
+		require.NoError(t, err)
+	}
+
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt32(&handled) == 4
+	}, 30*time.Second, 100*time.Millisecond)
+}
+
+func TestQueueRunFailover(t *testing.T) {
+	r := miniredis.RunT(t)
+
+	rc, err := rueidis.NewClient(rueidis.ClientOption{
+		InitAddress:  []string{r.Addr()},
+		DisableCache: true,
+	})
+	require.NoError(t, err)
+	defer rc.Close()
+
+	q := NewQueue(rc, WithNumWorkers(10))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var handled int32
+	stopped := make(chan struct{})
+	go func() {
+		_ = q.Run(ctx, func(ctx context.Context, _ osqueue.RunInfo, item osqueue.Item) error {
+			atomic.AddInt32(&handled, 1)
+			return nil
+		})
+		close(stopped)
+	}()
+
+	// Simulate a failover by taking Redis offline for a period of time.
+	<-time.After(100 * time.Millisecond)
+	r.Close()
+	<-time.After(time.Second)
+
+	select {
+	case <-stopped:
+		t.Fatal("queue stopped while redis was unavailable")
+	default:
+	}
+
+	require.NoError(t, r.Restart())
+
+	id := uuid.New()
+	_, err = q.EnqueueItem(ctx, QueueItem{
+		WorkflowID: id,
+		Data: osqueue.Item{
+			Kind: osqueue.KindEdge,
+			Identifier: state.Identifier{
+				WorkflowID: id,
+				RunID:      ulid.MustNew(ulid.Now(), rand.Reader),
+			},
+		},
+	}, time.Now())
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt32(&handled) == 1
+	}, 10*time.Second, 50*time.Millisecond)
+}
+
+func TestIsTransientErr(t *testing.T) {
+	require.False(t, isTransientErr(nil))
+	require.False(t, isTransientErr(context.Canceled))
+	require.False(t, isTransientErr(ErrQueueItemNotFound))
+	require.True(t, isTransientErr(fmt.Errorf("error peeking: %w", io.EOF)))
+}
+
 func TestQueueRunRetry(t *testing.T) {
 	r := miniredis.RunT(t)
 
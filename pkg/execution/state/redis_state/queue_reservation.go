@@ -0,0 +1,233 @@
+package redis_state
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/redis/rueidis"
+)
+
+// CapacityReservation reserves workers for a function or account.  Reserved workers
+// are only used by the reservation's function or account, ensuring that critical
+// functions always have execution capacity even when the queue has a large backlog
+// of other work.
+//
+// Reservations are per queue worker.  Work for a reserved function or account uses
+// its reserved workers first, falling back to the worker's general capacity.
+type CapacityReservation struct {
+	// FunctionID reserves capacity for a single function.
+	FunctionID uuid.UUID
+	// AccountID reserves capacity for all functions within an account.  This is
+	// ignored if FunctionID is set.
+	AccountID uuid.UUID
+	// Workers is the number of workers to reserve.
+	Workers int64
+}
+
+// WithCapacityReservations reserves workers for the given functions and accounts.
+func WithCapacityReservations(r ...CapacityReservation) QueueOpt {
+	return func(q *queue) {
+		q.reservationOpts = r
+	}
+}
+
+// SetCapacityReservations replaces the queue's capacity reservations.  This returns
+// an error if more workers are reserved than are available.
+func (q *queue) SetCapacityReservations(r ...CapacityReservation) error {
+	return q.reservations.set(r, int64(q.numWorkers))
+}
+
+// reservation tracks the number of in-use workers for a single CapacityReservation.
+type reservation struct {
+	CapacityReservation
+	used int64
+}
+
+// reservationTable tracks worker usage across all capacity reservations and
+// general, unreserved capacity.
+type reservationTable struct {
+	l sync.Mutex
+
+	fns      map[uuid.UUID]*reservation
+	accounts map[uuid.UUID]*reservation
+	// reserved is the total number of reserved workers.
+	reserved int64
+	// general is the number of in-use workers which don't use a reservation.
+	general int64
+	// workers is the total number of workers.
+	workers int64
+}
+
+func newReservationTable(workers int64) *reservationTable {
+	return &reservationTable{
+		fns:      map[uuid.UUID]*reservation{},
+		accounts: map[uuid.UUID]*reservation{},
+		workers:  workers,
+	}
+}
+
+func (t *reservationTable) set(rs []CapacityReservation, workers int64) error {
+	fns := map[uuid.UUID]*reservation{}
+	accounts := map[uuid.UUID]*reservation{}
+	total := int64(0)
+	for _, r := range rs {
+		if r.Workers <= 0 {
+			continue
+		}
+		total += r.Workers
+		switch {
+		case r.FunctionID != uuid.Nil:
+			fns[r.FunctionID] = &reservation{CapacityReservation: r}
+		case r.AccountID != uuid.Nil:
+			accounts[r.AccountID] = &reservation{CapacityReservation: r}
+		default:
+			return fmt.Errorf("capacity reservation requires a function or account ID")
+		}
+	}
+	if total > workers {
+		return fmt.Errorf("cannot reserve %d workers with %d available", total, workers)
+	}
+
+	t.l.Lock()
+	defer t.l.Unlock()
+
+	// Carry over in-use counts so that releasing in-flight work stays balanced.  Work
+	// for reservations that were removed is released from general capacity.
+	for id, r := range t.fns {
+		if next, ok := fns[id]; ok {
+			next.used = r.used
+		} else {
+			t.general += r.used
+		}
+	}
+	for id, r := range t.accounts {
+		if next, ok := accounts[id]; ok {
+			next.used = r.used
+		} else {
+			t.general += r.used
+		}
+	}
+
+	t.fns = fns
+	t.accounts = accounts
+	t.reserved = total
+	t.workers = workers
+	return nil
+}
+
+// find returns the reservation for the given function or account, if any.
+// This must be called with the lock held.
+func (t *reservationTable) find(fnID, accountID uuid.UUID) *reservation {
+	if r, ok := t.fns[fnID]; ok {
+		return r
+	}
+	if r, ok := t.accounts[accountID]; ok {
+		return r
+	}
+	return nil
+}
+
+// acquire claims a worker for the given function and account, returning the
+// reservation used or nil if general capacity was used.  This returns false if
+// there's no capacity available.
+func (t *reservationTable) acquire(fnID, accountID uuid.UUID) (*reservation, bool) {
+	t.l.Lock()
+	defer t.l.Unlock()
+
+	if r := t.find(fnID, accountID); r != nil && r.used < r.Workers {
+		r.used++
+		return r, true
+	}
+	// General work can't use workers held for reservations.
+	if t.general < t.workers-t.reserved {
+		t.general++
+		return nil, true
+	}
+	return nil, false
+}
+
+// release frees a worker acquired via acquire.
+func (t *reservationTable) release(r *reservation) {
+	t.l.Lock()
+	defer t.l.Unlock()
+
+	if r != nil {
+		// Reservations may have been replaced since the worker was acquired, so
+		// release from the current reservation.
+		cur := t.accounts[r.AccountID]
+		if r.FunctionID != uuid.Nil {
+			cur = t.fns[r.FunctionID]
+		}
+		if cur != nil {
+			cur.used--
+			return
+		}
+	}
+	t.general--
+}
+
+// available returns the IDs of reserved functions with free reserved workers.
+func (t *reservationTable) available() []uuid.UUID {
+	t.l.Lock()
+	defer t.l.Unlock()
+
+	ids := []uuid.UUID{}
+	for id, r := range t.fns {
+		if r.used < r.Workers {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// acquireWorker claims a worker for the given queue item, checking the item's
+// reservation prior to general capacity.
+func (q *queue) acquireWorker(item QueueItem) (*reservation, bool) {
+	r, ok := q.reservations.acquire(item.WorkflowID, item.Data.Identifier.AccountID)
+	if !ok {
+		return nil, false
+	}
+	if !q.sem.TryAcquire(1) {
+		q.reservations.release(r)
+		return nil, false
+	}
+	return r, true
+}
+
+// releaseWorker frees a worker claimed via acquireWorker.
+func (q *queue) releaseWorker(r *reservation) {
+	q.reservations.release(r)
+	q.sem.Release(1)
+}
+
+// scanReserved processes the partitions of reserved functions with free reserved
+// workers ahead of general scanning.  Under a large backlog these partitions may
+// not be peeked from the partition index, so they're loaded directly.
+func (q *queue) scanReserved(ctx context.Context) error {
+	for _, id := range q.reservations.available() {
+		cmd := q.r.B().Hget().Key(q.kg.PartitionItem()).Field(id.String()).Build()
+		enc, err := q.r.Do(ctx, cmd).AsBytes()
+		if rueidis.IsRedisNil(err) {
+			// There's no work for this function.
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("error fetching reserved partition: %w", err)
+		}
+		p := &QueuePartition{}
+		if err := json.Unmarshal(enc, p); err != nil {
+			return fmt.Errorf("error reading reserved partition: %w", err)
+		}
+
+		if err := q.processPartition(ctx, p, nil); err != nil {
+			if err == ErrPartitionNotFound || err == ErrPartitionGarbageCollected {
+				continue
+			}
+			return err
+		}
+	}
+	return nil
+}
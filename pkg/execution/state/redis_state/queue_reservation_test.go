@@ -0,0 +1,78 @@
+package redis_state
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReservationTable(t *testing.T) {
+	fnID, acctID, other := uuid.New(), uuid.New(), uuid.New()
+
+	table := newReservationTable(4)
+	err := table.set([]CapacityReservation{
+		{FunctionID: fnID, Workers: 1},
+		{AccountID: acctID, Workers: 1},
+	}, 4)
+	require.NoError(t, err)
+
+	t.Run("general work cannot use reserved workers", func(t *testing.T) {
+		a, ok := table.acquire(other, other)
+		require.True(t, ok)
+		require.Nil(t, a)
+		b, ok := table.acquire(other, other)
+		require.True(t, ok)
+		require.Nil(t, b)
+
+		_, ok = table.acquire(other, other)
+		require.False(t, ok, "general work leased reserved capacity")
+
+		table.release(a)
+		table.release(b)
+	})
+
+	t.Run("reserved work uses reservations first", func(t *testing.T) {
+		r, ok := table.acquire(fnID, other)
+		require.True(t, ok)
+		require.NotNil(t, r)
+		require.Equal(t, fnID, r.FunctionID)
+		require.Empty(t, table.available())
+
+		// Once the reservation is exhausted general capacity is used.
+		g, ok := table.acquire(fnID, other)
+		require.True(t, ok)
+		require.Nil(t, g)
+
+		a, ok := table.acquire(other, acctID)
+		require.True(t, ok)
+		require.Equal(t, acctID, a.AccountID)
+
+		table.release(r)
+		table.release(g)
+		table.release(a)
+		require.Equal(t, []uuid.UUID{fnID}, table.available())
+	})
+
+	t.Run("replacing reservations keeps usage balanced", func(t *testing.T) {
+		r, ok := table.acquire(fnID, other)
+		require.True(t, ok)
+		require.NotNil(t, r)
+
+		require.NoError(t, table.set(nil, 4))
+		table.release(r)
+		require.EqualValues(t, 0, table.general)
+
+		for i := 0; i < 4; i++ {
+			_, ok := table.acquire(other, other)
+			require.True(t, ok)
+		}
+	})
+
+	t.Run("it rejects reserving more workers than available", func(t *testing.T) {
+		err := newReservationTable(2).set([]CapacityReservation{
+			{FunctionID: fnID, Workers: 3},
+		}, 2)
+		require.Error(t, err)
+	})
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/google/uuid"
 	osqueue "github.com/inngest/inngest/pkg/execution/queue"
 	"github.com/inngest/inngest/pkg/execution/state"
+	"github.com/inngest/inngest/pkg/inngest"
 	"github.com/oklog/ulid/v2"
 	"github.com/redis/rueidis"
 	"github.com/stretchr/testify/require"
@@ -25,6 +26,24 @@ func init() {
 
 const testPriority = PriorityDefault
 
+func TestQueueConfig(t *testing.T) {
+	// Cluster queues must hash tag every key into a single slot.
+	_, err := queueConfig{Addrs: []string{"localhost:7000"}, Cluster: true, KeyPrefix: "queue"}.Queue()
+	require.ErrorContains(t, err, "hash tag")
+	_, err = queueConfig{Addrs: []string{"localhost:7000"}, Cluster: true, KeyPrefix: "{queue}", DB: 1}.Queue()
+	require.ErrorContains(t, err, "database")
+
+	// Sentinel config is validated as it is for the state store.
+	_, err = queueConfig{Sentinel: &SentinelConfig{Addrs: []string{"localhost:26379"}}}.Queue()
+	require.ErrorContains(t, err, "master set")
+	_, err = queueConfig{
+		Cluster:   true,
+		KeyPrefix: "{queue}",
+		Sentinel:  &SentinelConfig{MasterSet: "primary", Addrs: []string{"localhost:26379"}},
+	}.Queue()
+	require.ErrorContains(t, err, "sentinel cannot be used with redis cluster")
+}
+
 func TestQueueItemScore(t *testing.T) {
 	parse := func(layout, val string) time.Time {
 		t, _ := time.Parse(layout, val)
@@ -330,6 +349,41 @@ func TestQueueEnqueueItem(t *testing.T) {
 	})
 }
 
+func TestQueueEnqueueMany(t *testing.T) {
+	r := miniredis.RunT(t)
+	rc, err := rueidis.NewClient(rueidis.ClientOption{
+		InitAddress:  []string{r.Addr()},
+		DisableCache: true,
+	})
+	require.NoError(t, err)
+	defer rc.Close()
+	q := NewQueue(rc)
+	ctx := context.Background()
+
+	start := time.Now().Truncate(time.Second)
+	jobID := func(id string) *string { return &id }
+
+	err = q.Enqueue(ctx, osqueue.Item{JobID: jobID("existing")}, start)
+	require.NoError(t, err)
+
+	items := []osqueue.ItemAt{
+		{Item: osqueue.Item{JobID: jobID("a"), Identifier: state.Identifier{WorkflowID: uuid.New()}}, At: start},
+		{Item: osqueue.Item{JobID: jobID("b"), Identifier: state.Identifier{WorkflowID: uuid.New()}}, At: start.Add(time.Hour)},
+		{Item: osqueue.Item{JobID: jobID("existing")}, At: start},
+	}
+	errs := q.EnqueueMany(ctx, items)
+	require.Len(t, errs, 3)
+	require.NoError(t, errs[0])
+	require.NoError(t, errs[1])
+	require.ErrorIs(t, errs[2], ErrQueueItemExists)
+
+	for _, item := range items[:2] {
+		found := getQueueItem(t, r, HashID(ctx, *item.Item.JobID))
+		require.Equal(t, item.Item.Identifier.WorkflowID, found.WorkflowID)
+		requireItemScoreEquals(t, r, found, item.At)
+	}
+}
+
 func TestQueueEnqueueItemIdempotency(t *testing.T) {
 	dur := 2 * time.Second
 
@@ -752,6 +806,253 @@ func TestQueueLease(t *testing.T) {
 	})
 }
 
+func TestQueueLeaseAppConcurrency(t *testing.T) {
+	r := miniredis.RunT(t)
+
+	rc, err := rueidis.NewClient(rueidis.ClientOption{
+		InitAddress:  []string{r.Addr()},
+		DisableCache: true,
+	})
+	require.NoError(t, err)
+	defer rc.Close()
+
+	q := NewQueue(
+		rc,
+		WithAppConcurrencyKeyGenerator(func(ctx context.Context, i QueueItem) (string, int) {
+			return i.Data.Identifier.AppID.String(), 1
+		}),
+	)
+	ctx := context.Background()
+
+	appID := uuid.New()
+	enqueue := func(t *testing.T, appID uuid.UUID) (QueuePartition, QueueItem) {
+		wfID := uuid.New()
+		item, err := q.EnqueueItem(ctx, QueueItem{
+			WorkflowID: wfID,
+			Data: osqueue.Item{
+				Identifier: state.Identifier{WorkflowID: wfID, AppID: appID},
+			},
+		}, time.Now())
+		require.NoError(t, err)
+		return QueuePartition{WorkflowID: wfID}, item
+	}
+
+	pA, itemA := enqueue(t, appID)
+	pB, itemB := enqueue(t, appID)
+	pC, itemC := enqueue(t, uuid.New())
+
+	_, err = q.Lease(ctx, pA, itemA, time.Minute, getNow(), nil)
+	require.NoError(t, err)
+
+	count, err := q.InProgress(ctx, "app", appID.String())
+	require.NoError(t, err)
+	require.EqualValues(t, 1, count)
+
+	t.Run("Functions within the same app share the limit", func(t *testing.T) {
+		_, err := q.Lease(ctx, pB, itemB, time.Minute, getNow(), nil)
+		var kerr keyError
+		require.ErrorAs(t, err, &kerr)
+		require.Equal(t, ErrAppConcurrencyLimit, kerr.Cause())
+	})
+
+	t.Run("Functions within other apps are unaffected", func(t *testing.T) {
+		_, err := q.Lease(ctx, pC, itemC, time.Minute, getNow(), nil)
+		require.NoError(t, err)
+	})
+
+	t.Run("Dequeueing frees app capacity", func(t *testing.T) {
+		itemA = getQueueItem(t, r, itemA.ID)
+		require.NoError(t, q.Dequeue(ctx, pA, itemA))
+
+		count, err := q.InProgress(ctx, "app", appID.String())
+		require.NoError(t, err)
+		require.EqualValues(t, 0, count)
+
+		_, err = q.Lease(ctx, pB, itemB, time.Minute, getNow(), nil)
+		require.NoError(t, err)
+	})
+
+	t.Run("Requeueing frees app capacity", func(t *testing.T) {
+		itemB = getQueueItem(t, r, itemB.ID)
+		require.NoError(t, q.Requeue(ctx, pB, itemB, time.Now().Add(time.Minute)))
+
+		count, err := q.InProgress(ctx, "app", appID.String())
+		require.NoError(t, err)
+		require.EqualValues(t, 0, count)
+	})
+}
+
+func TestQueueLeaseOrdered(t *testing.T) {
+	r := miniredis.RunT(t)
+
+	rc, err := rueidis.NewClient(rueidis.ClientOption{
+		InitAddress:  []string{r.Addr()},
+		DisableCache: true,
+	})
+	require.NoError(t, err)
+	defer rc.Close()
+
+	q := NewQueue(
+		rc,
+		WithCustomConcurrencyKeyGenerator(func(ctx context.Context, i QueueItem) []state.CustomConcurrency {
+			return i.Data.Identifier.CustomConcurrencyKeys
+		}),
+	)
+	ctx := context.Background()
+
+	wfID := uuid.New()
+	p := QueuePartition{WorkflowID: wfID}
+	key := fmt.Sprintf("f:%s:ordered", wfID)
+	enqueue := func(t *testing.T, id state.Identifier, kind string) QueueItem {
+		item, err := q.EnqueueItem(ctx, QueueItem{
+			WorkflowID: wfID,
+			Data: osqueue.Item{
+				Kind:       kind,
+				Identifier: id,
+			},
+		}, time.Now())
+		require.NoError(t, err)
+		return item
+	}
+	newID := func() state.Identifier {
+		return state.Identifier{
+			WorkflowID: wfID,
+			RunID:      ulid.Make(),
+			CustomConcurrencyKeys: []state.CustomConcurrency{
+				{Key: key, Limit: 1, Ordered: true},
+			},
+		}
+	}
+
+	idA, idB, idC := newID(), newID(), newID()
+	startA := enqueue(t, idA, osqueue.KindStart)
+	startB := enqueue(t, idB, osqueue.KindStart)
+	startC := enqueue(t, idC, osqueue.KindStart)
+
+	_, err = q.Lease(ctx, p, startA, time.Minute, getNow(), nil)
+	require.NoError(t, err)
+	require.NoError(t, q.Dequeue(ctx, p, getQueueItem(t, r, startA.ID)))
+
+	t.Run("Runs wait for the previous run to finish", func(t *testing.T) {
+		_, err := q.Lease(ctx, p, startB, time.Minute, getNow(), nil)
+		var kerr keyError
+		require.ErrorAs(t, err, &kerr)
+		require.Equal(t, ErrQueueItemOrdered, kerr.Cause())
+		require.Equal(t, key, kerr.key)
+
+		// Later runs are denied within the same scan, without checking the lock.
+		denies := newLeaseDenyList()
+		denies.addOrdered(err)
+		_, err = q.Lease(ctx, p, startC, time.Minute, getNow(), denies)
+		require.ErrorAs(t, err, &kerr)
+		require.Equal(t, ErrQueueItemOrdered, kerr.Cause())
+	})
+
+	t.Run("The running run's later steps are unaffected", func(t *testing.T) {
+		edge := enqueue(t, idA, osqueue.KindEdge)
+		_, err := q.Lease(ctx, p, edge, time.Minute, getNow(), nil)
+		require.NoError(t, err)
+		require.NoError(t, q.Dequeue(ctx, p, getQueueItem(t, r, edge.ID)))
+	})
+
+	t.Run("Finishing a run starts the next run", func(t *testing.T) {
+		require.NoError(t, q.ReleaseOrdered(ctx, idA))
+		_, err := q.Lease(ctx, p, startB, time.Minute, getNow(), nil)
+		require.NoError(t, err)
+		require.NoError(t, q.Dequeue(ctx, p, getQueueItem(t, r, startB.ID)))
+
+		// Releasing a finished run again never releases another run's keys.
+		require.NoError(t, q.ReleaseOrdered(ctx, idA))
+		_, err = q.Lease(ctx, p, startC, time.Minute, getNow(), nil)
+		var kerr keyError
+		require.ErrorAs(t, err, &kerr)
+		require.Equal(t, ErrQueueItemOrdered, kerr.Cause())
+	})
+}
+
+func TestQueueLeaseMany(t *testing.T) {
+	r := miniredis.RunT(t)
+
+	rc, err := rueidis.NewClient(rueidis.ClientOption{
+		InitAddress:  []string{r.Addr()},
+		DisableCache: true,
+	})
+	require.NoError(t, err)
+	defer rc.Close()
+
+	q := NewQueue(
+		rc,
+		WithPartitionConcurrencyKeyGenerator(func(ctx context.Context, p QueuePartition) (string, int) {
+			return p.Queue(), 4
+		}),
+		WithCustomConcurrencyKeyGenerator(func(ctx context.Context, i QueueItem) []state.CustomConcurrency {
+			return i.Data.Identifier.CustomConcurrencyKeys
+		}),
+	)
+	ctx := context.Background()
+
+	wfID := uuid.New()
+	p := QueuePartition{WorkflowID: wfID}
+	enqueue := func(t *testing.T, key string, throttle *osqueue.Throttle) QueueItem {
+		id := state.Identifier{WorkflowID: wfID, RunID: ulid.Make()}
+		if key != "" {
+			id.CustomConcurrencyKeys = []state.CustomConcurrency{{Key: key, Limit: 1}}
+		}
+		item, err := q.EnqueueItem(ctx, QueueItem{
+			WorkflowID: wfID,
+			Data: osqueue.Item{
+				Identifier: id,
+				Throttle:   throttle,
+			},
+		}, time.Now())
+		require.NoError(t, err)
+		return item
+	}
+
+	throttle := &osqueue.Throttle{Key: "throttled", Limit: 1, Burst: 1, Period: 60}
+	items := []QueueItem{
+		enqueue(t, "a", nil),
+		enqueue(t, "a", nil),
+		enqueue(t, "", throttle),
+		enqueue(t, "", throttle),
+		enqueue(t, "b", nil),
+		enqueue(t, "", nil),
+		enqueue(t, "", nil),
+		enqueue(t, "", nil),
+	}
+
+	denies := newLeaseDenyList()
+	ids, errs := q.LeaseMany(ctx, p, items, time.Minute, getNow(), denies)
+
+	cause := func(err error) error {
+		var kerr keyError
+		require.ErrorAs(t, err, &kerr)
+		return kerr.Cause()
+	}
+
+	t.Run("It leases items with capacity", func(t *testing.T) {
+		for _, n := range []int{0, 2, 4, 5} {
+			require.NoError(t, errs[n])
+			require.NotNil(t, ids[n])
+			require.EqualValues(t, ids[n], getQueueItem(t, r, items[n].ID).LeaseID)
+		}
+	})
+
+	t.Run("It denies keys for later items", func(t *testing.T) {
+		require.Equal(t, ErrConcurrencyLimitCustomKey0, cause(errs[1]))
+		require.Equal(t, ErrQueueItemThrottled, cause(errs[3]))
+		require.True(t, denies.denyConcurrency("a"))
+		require.True(t, denies.denyThrottle("throttled"))
+	})
+
+	t.Run("It stops leasing at the function's concurrency limit", func(t *testing.T) {
+		require.Equal(t, ErrPartitionConcurrencyLimit, cause(errs[6]))
+		require.Nil(t, ids[7])
+		require.NoError(t, errs[7])
+		require.Nil(t, getQueueItem(t, r, items[7].ID).LeaseID)
+	})
+}
+
 func TestQueueExtendLease(t *testing.T) {
 	r := miniredis.RunT(t)
 
@@ -931,6 +1232,54 @@ func TestQueueDequeue(t *testing.T) {
 
 }
 
+func TestQueuePayloadCompression(t *testing.T) {
+	r := miniredis.RunT(t)
+	rc, err := rueidis.NewClient(rueidis.ClientOption{
+		InitAddress:  []string{r.Addr()},
+		DisableCache: true,
+	})
+	require.NoError(t, err)
+	defer rc.Close()
+	q := NewQueue(rc, WithPayloadCompression(256))
+	ctx := context.Background()
+
+	data := osqueue.Item{
+		Kind: osqueue.KindEdge,
+		Payload: osqueue.PayloadEdge{
+			Edge: inngest.Edge{Outgoing: "step", Incoming: strings.Repeat("a", 1024)},
+		},
+		Metadata: map[string]string{"traceparent": "00-abc-def-01"},
+	}
+	item, err := q.EnqueueItem(ctx, QueueItem{WorkflowID: uuid.New(), Data: data}, time.Now())
+	require.NoError(t, err)
+
+	raw := map[string]any{}
+	require.NoError(t, json.Unmarshal([]byte(r.HGet(defaultQueueKey.QueueItem(), item.ID)), &raw))
+	stored := raw["data"].(map[string]any)
+	require.NotContains(t, stored, "payload")
+	require.Contains(t, stored, "pz")
+
+	found := getQueueItem(t, r, item.ID)
+	require.Equal(t, data.Payload, found.Data.Payload)
+	require.Equal(t, data.Metadata, found.Data.Metadata)
+
+	// Leasing re-encodes the item within Lua, which must keep the payload intact.
+	p := QueuePartition{WorkflowID: item.WorkflowID}
+	_, err = q.Lease(ctx, p, item, time.Second, getNow(), nil)
+	require.NoError(t, err)
+	found = getQueueItem(t, r, item.ID)
+	require.NotNil(t, found.LeaseID)
+	require.Equal(t, data.Payload, found.Data.Payload)
+
+	// Requeueing the decoded item compresses the payload again.
+	require.NoError(t, q.Requeue(ctx, p, found, time.Now().Add(time.Minute)))
+	raw = map[string]any{}
+	require.NoError(t, json.Unmarshal([]byte(r.HGet(defaultQueueKey.QueueItem(), item.ID)), &raw))
+	require.Contains(t, raw["data"].(map[string]any), "pz")
+	found = getQueueItem(t, r, item.ID)
+	require.Equal(t, data.Payload, found.Data.Payload)
+}
+
 func TestQueueRequeue(t *testing.T) {
 	r := miniredis.RunT(t)
 
@@ -1674,7 +2023,7 @@ func TestQueueRequeueByJobID(t *testing.T) {
 	})
 }
 
-func TestQueueLeaseSequential(t *testing.T) {
+func TestQueueRequeueRunStep(t *testing.T) {
 	ctx := context.Background()
 	r := miniredis.RunT(t)
 
@@ -1685,95 +2034,411 @@ func TestQueueLeaseSequential(t *testing.T) {
 	require.NoError(t, err)
 	defer rc.Close()
 
-	q := queue{
-		kg: defaultQueueKey,
-		r:  rc,
-		pf: func(ctx context.Context, item QueueItem) uint {
-			return PriorityMin
+	q := NewQueue(rc)
+
+	runID := ulid.MustNew(ulid.Now(), rand.Reader)
+	wfID := uuid.New()
+	at := time.Now().Add(time.Hour).Truncate(time.Millisecond)
+
+	item, err := q.EnqueueItem(ctx, QueueItem{
+		WorkflowID: wfID,
+		Data: osqueue.Item{
+			Kind:       osqueue.KindEdge,
+			Identifier: state.Identifier{RunID: runID, WorkflowID: wfID},
+			Payload: osqueue.PayloadEdge{
+				Edge: inngest.Edge{Outgoing: "first", Incoming: "second"},
+			},
 		},
-	}
+	}, at)
+	require.NoError(t, err)
 
-	var (
-		leaseID *ulid.ULID
-	)
+	t.Run("It returns ErrJobNotFound for unknown steps", func(t *testing.T) {
+		err := q.RequeueRunStep(ctx, runID, "nope", time.Now())
+		require.ErrorIs(t, err, osqueue.ErrJobNotFound)
 
-	t.Run("It claims sequential leases", func(t *testing.T) {
-		now := time.Now()
-		dur := 500 * time.Millisecond
-		leaseID, err = q.ConfigLease(ctx, q.kg.Sequential(), dur)
-		require.NoError(t, err)
-		require.NotNil(t, leaseID)
-		require.WithinDuration(t, now.Add(dur), ulid.Time(leaseID.Time()), 5*time.Millisecond)
+		err = q.RequeueRunStep(ctx, ulid.MustNew(ulid.Now(), rand.Reader), "second", time.Now())
+		require.ErrorIs(t, err, osqueue.ErrJobNotFound)
 	})
 
-	t.Run("It doesn't allow leasing without an existing lease ID", func(t *testing.T) {
-		id, err := q.ConfigLease(ctx, q.kg.Sequential(), time.Second)
-		require.Equal(t, ErrConfigAlreadyLeased, err)
-		require.Nil(t, id)
+	t.Run("It requeues the step's item", func(t *testing.T) {
+		now := time.Now().Truncate(time.Millisecond)
+		err := q.RequeueRunStep(ctx, runID, "second", now)
+		require.NoError(t, err)
+
+		found := getQueueItem(t, r, item.ID)
+		require.Equal(t, now.UnixMilli(), found.AtMS)
 	})
+}
 
-	t.Run("It doesn't allow leasing with an invalid lease ID", func(t *testing.T) {
-		newULID := ulid.MustNew(ulid.Now(), rnd)
-		id, err := q.ConfigLease(ctx, q.kg.Sequential(), time.Second, &newULID)
-		require.Equal(t, ErrConfigAlreadyLeased, err)
-		require.Nil(t, id)
+func TestQueueRemoveRunStart(t *testing.T) {
+	ctx := context.Background()
+	r := miniredis.RunT(t)
+
+	rc, err := rueidis.NewClient(rueidis.ClientOption{
+		InitAddress:  []string{r.Addr()},
+		DisableCache: true,
 	})
+	require.NoError(t, err)
+	defer rc.Close()
 
-	t.Run("It extends the lease with a valid lease ID", func(t *testing.T) {
-		require.NotNil(t, leaseID)
+	q := NewQueue(rc)
 
-		now := time.Now()
-		dur := 50 * time.Millisecond
-		leaseID, err = q.ConfigLease(ctx, q.kg.Sequential(), dur, leaseID)
+	runID := ulid.MustNew(ulid.Now(), rand.Reader)
+	wfID := uuid.New()
+	at := time.Now().Add(time.Hour).Truncate(time.Millisecond)
+
+	enqueue := func(runID ulid.ULID, kind string) QueueItem {
+		item, err := q.EnqueueItem(ctx, QueueItem{
+			WorkflowID: wfID,
+			Data: osqueue.Item{
+				Kind:       kind,
+				Identifier: state.Identifier{RunID: runID, WorkflowID: wfID},
+			},
+		}, at)
 		require.NoError(t, err)
-		require.NotNil(t, leaseID)
-		require.WithinDuration(t, now.Add(dur), ulid.Time(leaseID.Time()), 5*time.Millisecond)
+		return item
+	}
+
+	t.Run("It returns ErrJobNotFound for runs without start jobs", func(t *testing.T) {
+		err := q.RemoveRunStart(ctx, ulid.MustNew(ulid.Now(), rand.Reader))
+		require.ErrorIs(t, err, osqueue.ErrJobNotFound)
+
+		started := ulid.MustNew(ulid.Now(), rand.Reader)
+		enqueue(started, osqueue.KindEdge)
+		err = q.RemoveRunStart(ctx, started)
+		require.ErrorIs(t, err, osqueue.ErrJobNotFound)
 	})
 
-	t.Run("It allows leasing when the current lease is expired", func(t *testing.T) {
-		<-time.After(100 * time.Millisecond)
+	t.Run("It removes the run's start job", func(t *testing.T) {
+		item := enqueue(runID, osqueue.KindStart)
+		require.True(t, r.Exists(q.kg.QueueItem()))
 
-		now := time.Now()
-		dur := 50 * time.Millisecond
-		leaseID, err = q.ConfigLease(ctx, q.kg.Sequential(), dur)
+		err := q.RemoveRunStart(ctx, runID)
 		require.NoError(t, err)
-		require.NotNil(t, leaseID)
-		require.WithinDuration(t, now.Add(dur), ulid.Time(leaseID.Time()), 5*time.Millisecond)
+
+		require.Empty(t, r.HGet(q.kg.QueueItem(), item.ID))
+		jobs, err := q.ScheduledJobs(ctx, uuid.UUID{}, wfID, &runID, 0)
+		require.NoError(t, err)
+		require.Empty(t, jobs)
+
+		err = q.RemoveRunStart(ctx, runID)
+		require.ErrorIs(t, err, osqueue.ErrJobNotFound)
 	})
 }
 
-// TestSharding covers the basics of shards;  we assert that function enqueues/dequeues/leasing
-// modify shards appropriately, and that partition opeartions also modify the shards.
-func TestSharding(t *testing.T) {
+func TestQueueBoostRunPriority(t *testing.T) {
+	ctx := context.Background()
 	r := miniredis.RunT(t)
+
 	rc, err := rueidis.NewClient(rueidis.ClientOption{
 		InitAddress:  []string{r.Addr()},
 		DisableCache: true,
 	})
 	require.NoError(t, err)
 	defer rc.Close()
-	ctx := context.Background()
 
-	shouldShard := true // indicate whether to shard in tests
-	shard := &QueueShard{
-		Name:               "sharded",
-		Priority:           0,
-		GuaranteedCapacity: 1,
-	}
-	sf := func(ctx context.Context, queueName string, wsID uuid.UUID) *QueueShard {
-		if !shouldShard {
-			return nil
-		}
-		return shard
+	q := NewQueue(rc)
+
+	wfID := uuid.New()
+	runA := ulid.MustNew(ulid.Now(), rand.Reader)
+	runB := ulid.MustNew(ulid.Now(), rand.Reader)
+	now := time.Now().Truncate(time.Millisecond)
+
+	enqueue := func(runID ulid.ULID, kind string, at time.Time) QueueItem {
+		item, err := q.EnqueueItem(ctx, QueueItem{
+			WorkflowID: wfID,
+			Data: osqueue.Item{
+				Kind: kind,
+				// Retries are scored by their time, ignoring the run's start time.
+				Attempt:    1,
+				Identifier: state.Identifier{RunID: runID, WorkflowID: wfID},
+			},
+		}, at)
+		require.NoError(t, err)
+		return item
 	}
-	q := NewQueue(rc, WithShardFinder(sf))
-	require.NotNil(t, sf(ctx, "", uuid.UUID{}))
+	ready := enqueue(runA, osqueue.KindEdge, now.Add(-time.Second))
+	sleep := enqueue(runA, osqueue.KindSleep, now.Add(time.Hour))
+	other := enqueue(runB, osqueue.KindEdge, now.Add(-2*time.Second))
 
-	t.Run("QueueItem which shards", func(t *testing.T) {
+	t.Run("It rejects invalid boosts", func(t *testing.T) {
+		_, err := q.BoostRunPriority(ctx, runA, 0)
+		require.Error(t, err)
+		_, err = q.BoostRunPriority(ctx, runA, 24*time.Hour)
+		require.Error(t, err)
+	})
 
-		// NOTE: Times for shards or global pointers cannot be <= now.
-		// Because of this, tests start with the earliest item 1 hour ahead of now so that
-		// we can appropriately test enqueueing earlier items adjust pointer times.
+	t.Run("It moves the run's ready jobs ahead of the backlog", func(t *testing.T) {
+		items, err := q.Peek(ctx, wfID.String(), now, 10)
+		require.NoError(t, err)
+		require.Len(t, items, 2)
+		require.Equal(t, other.ID, items[0].ID)
+
+		n, err := q.BoostRunPriority(ctx, runA, time.Minute)
+		require.NoError(t, err)
+		require.Equal(t, 1, n)
+
+		items, err = q.Peek(ctx, wfID.String(), now, 10)
+		require.NoError(t, err)
+		require.Len(t, items, 2)
+		require.Equal(t, ready.ID, items[0].ID)
+		requireItemScoreEquals(t, r, ready, now.Add(-time.Second-time.Minute))
+	})
+
+	t.Run("It leaves future jobs as-is", func(t *testing.T) {
+		requireItemScoreEquals(t, r, sleep, now.Add(time.Hour))
+	})
+}
+
+func TestQueueUpdateConcurrencyLimits(t *testing.T) {
+	ctx := context.Background()
+	r := miniredis.RunT(t)
+
+	rc, err := rueidis.NewClient(rueidis.ClientOption{
+		InitAddress:  []string{r.Addr()},
+		DisableCache: true,
+	})
+	require.NoError(t, err)
+	defer rc.Close()
+
+	q := NewQueue(rc)
+
+	wfID := uuid.New()
+	keys := []state.CustomConcurrency{
+		{Key: "f:key-a", Hash: "hash-a", Limit: 1},
+		{Key: "f:key-b", Hash: "hash-b", Limit: 2},
+	}
+
+	ids := []string{}
+	for i := 0; i < 3; i++ {
+		item, err := q.EnqueueItem(ctx, QueueItem{
+			WorkflowID: wfID,
+			Data: osqueue.Item{
+				Kind: osqueue.KindEdge,
+				Identifier: state.Identifier{
+					RunID:                 ulid.MustNew(ulid.Now(), rand.Reader),
+					WorkflowID:            wfID,
+					CustomConcurrencyKeys: keys,
+				},
+			},
+		}, time.Now().Add(time.Minute))
+		require.NoError(t, err)
+		ids = append(ids, item.ID)
+	}
+
+	// Items for other functions are untouched.
+	other, err := q.EnqueueItem(ctx, QueueItem{
+		WorkflowID: uuid.New(),
+		Data: osqueue.Item{
+			Kind:       osqueue.KindEdge,
+			Identifier: state.Identifier{CustomConcurrencyKeys: keys},
+		},
+	}, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+
+	n, err := q.UpdateConcurrencyLimits(ctx, wfID, map[string]int{"hash-a": 10})
+	require.NoError(t, err)
+	require.Equal(t, 3, n)
+
+	for _, id := range ids {
+		found := getQueueItem(t, r, id)
+		require.Equal(t, []state.CustomConcurrency{
+			{Key: "f:key-a", Hash: "hash-a", Limit: 10},
+			{Key: "f:key-b", Hash: "hash-b", Limit: 2},
+		}, found.Data.Identifier.CustomConcurrencyKeys)
+	}
+	require.Equal(t, keys, getQueueItem(t, r, other.ID).Data.Identifier.CustomConcurrencyKeys)
+
+	// Unchanged limits don't rewrite items.
+	n, err = q.UpdateConcurrencyLimits(ctx, wfID, map[string]int{"hash-a": 10})
+	require.NoError(t, err)
+	require.Equal(t, 0, n)
+}
+
+func TestQueueConcurrencyOverrides(t *testing.T) {
+	ctx := context.Background()
+	r := miniredis.RunT(t)
+
+	rc, err := rueidis.NewClient(rueidis.ClientOption{
+		InitAddress:  []string{r.Addr()},
+		DisableCache: true,
+	})
+	require.NoError(t, err)
+	defer rc.Close()
+
+	wfID := uuid.New()
+	key := fmt.Sprintf("f:%s:custom", wfID)
+	// config represents the function's configured keys, which are re-resolved
+	// whenever items are leased.
+	config := []state.CustomConcurrency{{Key: key, Hash: "hash-a", Limit: 1}}
+
+	q := NewQueue(
+		rc,
+		WithConcurrencyOverrides(),
+		WithCustomConcurrencyKeyGenerator(func(ctx context.Context, i QueueItem) []state.CustomConcurrency {
+			return config
+		}),
+	)
+
+	p := QueuePartition{WorkflowID: wfID}
+	enqueue := func(t *testing.T) QueueItem {
+		item, err := q.EnqueueItem(ctx, QueueItem{
+			WorkflowID: wfID,
+			Data: osqueue.Item{
+				Kind: osqueue.KindEdge,
+				Identifier: state.Identifier{
+					WorkflowID:            wfID,
+					RunID:                 ulid.Make(),
+					CustomConcurrencyKeys: config,
+				},
+			},
+		}, time.Now())
+		require.NoError(t, err)
+		return item
+	}
+
+	_, err = q.Lease(ctx, p, enqueue(t), time.Minute, getNow(), nil)
+	require.NoError(t, err)
+
+	item := enqueue(t)
+	_, err = q.Lease(ctx, p, item, time.Minute, getNow(), nil)
+	var kerr keyError
+	require.ErrorAs(t, err, &kerr)
+	require.Equal(t, ErrConcurrencyLimitCustomKey0, kerr.Cause())
+
+	t.Run("Overrides apply to queued items", func(t *testing.T) {
+		_, err := q.UpdateConcurrencyLimits(ctx, wfID, map[string]int{"hash-a": 2})
+		require.NoError(t, err)
+
+		limits, err := q.ConcurrencyLimitOverrides(ctx, wfID)
+		require.NoError(t, err)
+		require.Equal(t, map[string]int{"hash-a": 2}, limits)
+
+		_, err = q.Lease(ctx, p, item, time.Minute, getNow(), nil)
+		require.NoError(t, err)
+
+		// The overridden limit is still enforced.
+		_, err = q.Lease(ctx, p, enqueue(t), time.Minute, getNow(), nil)
+		require.ErrorAs(t, err, &kerr)
+		require.Equal(t, ErrConcurrencyLimitCustomKey0, kerr.Cause())
+	})
+
+	t.Run("Overrides are merged", func(t *testing.T) {
+		_, err := q.UpdateConcurrencyLimits(ctx, wfID, map[string]int{"hash-b": 5})
+		require.NoError(t, err)
+
+		limits, err := q.ConcurrencyLimitOverrides(ctx, wfID)
+		require.NoError(t, err)
+		require.Equal(t, map[string]int{"hash-a": 2, "hash-b": 5}, limits)
+	})
+
+	t.Run("Functions without overrides return nil", func(t *testing.T) {
+		limits, err := q.ConcurrencyLimitOverrides(ctx, uuid.New())
+		require.NoError(t, err)
+		require.Nil(t, limits)
+	})
+}
+
+func TestQueueLeaseSequential(t *testing.T) {
+	ctx := context.Background()
+	r := miniredis.RunT(t)
+
+	rc, err := rueidis.NewClient(rueidis.ClientOption{
+		InitAddress:  []string{r.Addr()},
+		DisableCache: true,
+	})
+	require.NoError(t, err)
+	defer rc.Close()
+
+	q := queue{
+		kg: defaultQueueKey,
+		r:  rc,
+		pf: func(ctx context.Context, item QueueItem) uint {
+			return PriorityMin
+		},
+	}
+
+	var (
+		leaseID *ulid.ULID
+	)
+
+	t.Run("It claims sequential leases", func(t *testing.T) {
+		now := time.Now()
+		dur := 500 * time.Millisecond
+		leaseID, err = q.ConfigLease(ctx, q.kg.Sequential(), dur)
+		require.NoError(t, err)
+		require.NotNil(t, leaseID)
+		require.WithinDuration(t, now.Add(dur), ulid.Time(leaseID.Time()), 5*time.Millisecond)
+	})
+
+	t.Run("It doesn't allow leasing without an existing lease ID", func(t *testing.T) {
+		id, err := q.ConfigLease(ctx, q.kg.Sequential(), time.Second)
+		require.Equal(t, ErrConfigAlreadyLeased, err)
+		require.Nil(t, id)
+	})
+
+	t.Run("It doesn't allow leasing with an invalid lease ID", func(t *testing.T) {
+		newULID := ulid.MustNew(ulid.Now(), rnd)
+		id, err := q.ConfigLease(ctx, q.kg.Sequential(), time.Second, &newULID)
+		require.Equal(t, ErrConfigAlreadyLeased, err)
+		require.Nil(t, id)
+	})
+
+	t.Run("It extends the lease with a valid lease ID", func(t *testing.T) {
+		require.NotNil(t, leaseID)
+
+		now := time.Now()
+		dur := 50 * time.Millisecond
+		leaseID, err = q.ConfigLease(ctx, q.kg.Sequential(), dur, leaseID)
+		require.NoError(t, err)
+		require.NotNil(t, leaseID)
+		require.WithinDuration(t, now.Add(dur), ulid.Time(leaseID.Time()), 5*time.Millisecond)
+	})
+
+	t.Run("It allows leasing when the current lease is expired", func(t *testing.T) {
+		<-time.After(100 * time.Millisecond)
+
+		now := time.Now()
+		dur := 50 * time.Millisecond
+		leaseID, err = q.ConfigLease(ctx, q.kg.Sequential(), dur)
+		require.NoError(t, err)
+		require.NotNil(t, leaseID)
+		require.WithinDuration(t, now.Add(dur), ulid.Time(leaseID.Time()), 5*time.Millisecond)
+	})
+}
+
+// TestSharding covers the basics of shards;  we assert that function enqueues/dequeues/leasing
+// modify shards appropriately, and that partition opeartions also modify the shards.
+func TestSharding(t *testing.T) {
+	r := miniredis.RunT(t)
+	rc, err := rueidis.NewClient(rueidis.ClientOption{
+		InitAddress:  []string{r.Addr()},
+		DisableCache: true,
+	})
+	require.NoError(t, err)
+	defer rc.Close()
+	ctx := context.Background()
+
+	shouldShard := true // indicate whether to shard in tests
+	shard := &QueueShard{
+		Name:               "sharded",
+		Priority:           0,
+		GuaranteedCapacity: 1,
+	}
+	sf := func(ctx context.Context, queueName string, wsID uuid.UUID) *QueueShard {
+		if !shouldShard {
+			return nil
+		}
+		return shard
+	}
+	q := NewQueue(rc, WithShardFinder(sf))
+	require.NotNil(t, sf(ctx, "", uuid.UUID{}))
+
+	t.Run("QueueItem which shards", func(t *testing.T) {
+
+		// NOTE: Times for shards or global pointers cannot be <= now.
+		// Because of this, tests start with the earliest item 1 hour ahead of now so that
+		// we can appropriately test enqueueing earlier items adjust pointer times.
 
 		t.Run("Basic enqueue lease dequeue operations", func(t *testing.T) {
 			at := time.Now().Truncate(time.Second).Add(time.Hour)
@@ -2094,6 +2759,70 @@ func TestShardLease(t *testing.T) {
 }
 
 // TestQueueRateLimit asserts that the queue respects rate limits when added to a queue item.
+func TestQueueThrottleOverrides(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rc, err := rueidis.NewClient(rueidis.ClientOption{
+		InitAddress:  []string{mr.Addr()},
+		DisableCache: true,
+	})
+	require.NoError(t, err)
+	defer rc.Close()
+	ctx := context.Background()
+	q := NewQueue(rc, WithThrottleOverrides())
+
+	wfID := uuid.New()
+	p := QueuePartition{WorkflowID: wfID}
+	enqueue := func(t *testing.T) QueueItem {
+		item, err := q.EnqueueItem(ctx, QueueItem{
+			WorkflowID: wfID,
+			Data: osqueue.Item{
+				Identifier: state.Identifier{WorkflowID: wfID},
+				Throttle: &osqueue.Throttle{
+					Key:    "throttle-key",
+					Limit:  1,
+					Period: 60,
+				},
+			},
+		}, getNow())
+		require.NoError(t, err)
+		return item
+	}
+
+	_, err = q.Lease(ctx, p, enqueue(t), time.Minute, getNow(), nil)
+	require.NoError(t, err)
+
+	item := enqueue(t)
+	_, err = q.Lease(ctx, p, item, time.Minute, getNow(), nil)
+	var kerr keyError
+	require.ErrorAs(t, err, &kerr)
+	require.Equal(t, ErrQueueItemThrottled, kerr.Cause())
+
+	t.Run("It rejects invalid throttles", func(t *testing.T) {
+		err := q.SetThrottle(ctx, wfID, &osqueue.Throttle{Limit: 0, Period: 60})
+		require.Error(t, err)
+	})
+
+	t.Run("Overrides apply to queued items", func(t *testing.T) {
+		err := q.SetThrottle(ctx, wfID, &osqueue.Throttle{Limit: 10, Burst: 10, Period: 60})
+		require.NoError(t, err)
+
+		_, err = q.Lease(ctx, p, item, time.Minute, getNow(), nil)
+		require.NoError(t, err)
+
+		// The item's stored throttle is left as-is.
+		found := getQueueItem(t, mr, item.ID)
+		require.Equal(t, 1, found.Data.Throttle.Limit)
+	})
+
+	t.Run("Removing an override uses the item's throttle", func(t *testing.T) {
+		require.NoError(t, q.SetThrottle(ctx, wfID, nil))
+
+		_, err := q.Lease(ctx, p, enqueue(t), time.Minute, getNow(), nil)
+		require.ErrorAs(t, err, &kerr)
+		require.Equal(t, ErrQueueItemThrottled, kerr.Cause())
+	})
+}
+
 func TestQueueRateLimit(t *testing.T) {
 	mr := miniredis.RunT(t)
 	rc, err := rueidis.NewClient(rueidis.ClientOption{
@@ -2327,6 +3056,290 @@ func concurrencyQueueScores(t *testing.T, r *miniredis.Miniredis, key string, fr
 	return scores
 }
 
+func TestQueuePartitionStats(t *testing.T) {
+	r := miniredis.RunT(t)
+	rc, err := rueidis.NewClient(rueidis.ClientOption{
+		InitAddress:  []string{r.Addr()},
+		DisableCache: true,
+	})
+	require.NoError(t, err)
+	defer rc.Close()
+
+	q := NewQueue(
+		rc,
+		WithPartitionConcurrencyKeyGenerator(func(ctx context.Context, p QueuePartition) (string, int) {
+			return p.Queue(), 1
+		}),
+	)
+	ctx := context.Background()
+	fnID := uuid.New()
+
+	t.Run("It returns empty stats for unknown partitions", func(t *testing.T) {
+		stats, err := q.PartitionStats(ctx, fnID)
+		require.NoError(t, err)
+		require.Equal(t, osqueue.PartitionStats{FunctionID: fnID}, stats)
+	})
+
+	now := time.Now().Truncate(time.Second)
+	past := now.Add(-10 * time.Second)
+	for _, at := range []time.Time{past, now, now.Add(-time.Second), now.Add(time.Hour)} {
+		_, err := q.EnqueueItem(ctx, QueueItem{WorkflowID: fnID}, at)
+		require.NoError(t, err)
+	}
+
+	t.Run("It splits ready and scheduled items", func(t *testing.T) {
+		stats, err := q.PartitionStats(ctx, fnID)
+		require.NoError(t, err)
+		require.EqualValues(t, 3, stats.Ready)
+		require.EqualValues(t, 1, stats.Scheduled)
+		require.EqualValues(t, 0, stats.InProgress)
+		require.EqualValues(t, 1, stats.ConcurrencyLimit)
+		require.EqualValues(t, 2, stats.ConcurrencyLimited)
+		require.NotNil(t, stats.OldestItemAt)
+		require.WithinDuration(t, past, *stats.OldestItemAt, time.Millisecond)
+	})
+
+	t.Run("It counts leased items as in progress", func(t *testing.T) {
+		p := QueuePartition{WorkflowID: fnID}
+		items, err := q.Peek(ctx, p.Queue(), now, 1)
+		require.NoError(t, err)
+		require.Len(t, items, 1)
+		_, err = q.Lease(ctx, p, *items[0], 10*time.Second, getNow(), nil)
+		require.NoError(t, err)
+
+		stats, err := q.PartitionStats(ctx, fnID)
+		require.NoError(t, err)
+		require.EqualValues(t, 2, stats.Ready)
+		require.EqualValues(t, 1, stats.InProgress)
+		require.EqualValues(t, 2, stats.ConcurrencyLimited)
+	})
+}
+
+func TestQueuePartitionPause(t *testing.T) {
+	r := miniredis.RunT(t)
+	rc, err := rueidis.NewClient(rueidis.ClientOption{
+		InitAddress:  []string{r.Addr()},
+		DisableCache: true,
+	})
+	require.NoError(t, err)
+	defer rc.Close()
+
+	q := NewQueue(rc)
+	ctx := context.Background()
+	fnID := uuid.New()
+	p := QueuePartition{WorkflowID: fnID}
+
+	_, err = q.EnqueueItem(ctx, QueueItem{WorkflowID: fnID}, time.Now())
+	require.NoError(t, err)
+
+	t.Run("Paused partitions aren't leased", func(t *testing.T) {
+		require.NoError(t, q.SetPartitionPaused(ctx, fnID, true))
+
+		_, err := q.PartitionLease(ctx, &p, time.Second)
+		require.Equal(t, ErrPartitionPaused, err)
+
+		stats, err := q.PartitionStats(ctx, fnID)
+		require.NoError(t, err)
+		require.True(t, stats.Paused)
+		require.EqualValues(t, 1, stats.Ready)
+	})
+
+	t.Run("Processing a paused partition requeues it into the future", func(t *testing.T) {
+		require.NoError(t, q.processPartition(ctx, &p, nil))
+		requirePartitionScoreEquals(t, r, fnID, time.Now().Truncate(time.Second).Add(PartitionPausedRequeueExtension))
+
+		// Enqueueing doesn't bring the partition forward.
+		_, err = q.EnqueueItem(ctx, QueueItem{WorkflowID: fnID}, time.Now())
+		require.NoError(t, err)
+		requirePartitionScoreEquals(t, r, fnID, time.Now().Truncate(time.Second).Add(PartitionPausedRequeueExtension))
+	})
+
+	t.Run("Resuming requeues the partition immediately", func(t *testing.T) {
+		require.NoError(t, q.SetPartitionPaused(ctx, fnID, false))
+		requirePartitionScoreEquals(t, r, fnID, time.Now())
+
+		_, err := q.PartitionLease(ctx, &p, time.Second)
+		require.NoError(t, err)
+
+		stats, err := q.PartitionStats(ctx, fnID)
+		require.NoError(t, err)
+		require.False(t, stats.Paused)
+		require.EqualValues(t, 2, stats.Ready)
+	})
+}
+
+func TestQueuePeekJobs(t *testing.T) {
+	r := miniredis.RunT(t)
+	rc, err := rueidis.NewClient(rueidis.ClientOption{
+		InitAddress:  []string{r.Addr()},
+		DisableCache: true,
+	})
+	require.NoError(t, err)
+	defer rc.Close()
+
+	q := NewQueue(rc)
+	ctx := context.Background()
+	wsID, fnID := uuid.New(), uuid.New()
+	runA, runB := ulid.Make(), ulid.Make()
+	now := time.Now().Truncate(time.Second)
+
+	enqueue := func(runID ulid.ULID, kind string, at time.Time) QueueItem {
+		item, err := q.EnqueueItem(ctx, QueueItem{
+			WorkflowID: fnID,
+			Data: osqueue.Item{
+				WorkspaceID: wsID,
+				Kind:        kind,
+				Identifier: state.Identifier{
+					WorkflowID:  fnID,
+					RunID:       runID,
+					WorkspaceID: wsID,
+				},
+				Payload: osqueue.PayloadEdge{Edge: inngest.Edge{Incoming: "step-" + kind}},
+			},
+		}, at)
+		require.NoError(t, err)
+		return item
+	}
+	leased := enqueue(runA, osqueue.KindEdge, now.Add(-2*time.Second))
+	enqueue(runA, osqueue.KindSleep, now.Add(time.Hour))
+	enqueue(runB, osqueue.KindEdge, now.Add(-time.Second))
+
+	_, err = q.Lease(ctx, QueuePartition{WorkflowID: fnID}, leased, time.Minute, getNow(), nil)
+	require.NoError(t, err)
+
+	t.Run("It returns ready and future jobs in order, without leased jobs", func(t *testing.T) {
+		jobs, err := q.PeekJobs(ctx, wsID, fnID, 0)
+		require.NoError(t, err)
+		require.Len(t, jobs, 2)
+		require.Equal(t, runB, jobs[0].RunID)
+		require.Equal(t, osqueue.KindEdge, jobs[0].Kind)
+		require.Equal(t, "step-edge", jobs[0].StepID)
+		require.WithinDuration(t, now.Add(-time.Second), jobs[0].At, time.Millisecond)
+		require.Equal(t, runA, jobs[1].RunID)
+		require.Equal(t, osqueue.KindSleep, jobs[1].Kind)
+	})
+
+	t.Run("It limits jobs", func(t *testing.T) {
+		jobs, err := q.PeekJobs(ctx, wsID, fnID, 1)
+		require.NoError(t, err)
+		require.Len(t, jobs, 1)
+		require.Equal(t, runB, jobs[0].RunID)
+	})
+
+	t.Run("It filters by workspace", func(t *testing.T) {
+		jobs, err := q.PeekJobs(ctx, uuid.New(), fnID, 0)
+		require.NoError(t, err)
+		require.Empty(t, jobs)
+	})
+
+	t.Run("Peeking doesn't lease jobs", func(t *testing.T) {
+		stats, err := q.PartitionStats(ctx, fnID)
+		require.NoError(t, err)
+		require.EqualValues(t, 1, stats.Ready)
+	})
+}
+
+func TestQueueScheduledJobs(t *testing.T) {
+	r := miniredis.RunT(t)
+	rc, err := rueidis.NewClient(rueidis.ClientOption{
+		InitAddress:  []string{r.Addr()},
+		DisableCache: true,
+	})
+	require.NoError(t, err)
+	defer rc.Close()
+
+	q := NewQueue(rc)
+	ctx := context.Background()
+	wsID, fnID := uuid.New(), uuid.New()
+	runA, runB := ulid.Make(), ulid.Make()
+	now := time.Now().Truncate(time.Second)
+
+	enqueue := func(runID ulid.ULID, kind string, at time.Time) {
+		item := QueueItem{
+			WorkflowID: fnID,
+			Data: osqueue.Item{
+				WorkspaceID: wsID,
+				Kind:        kind,
+				Identifier: state.Identifier{
+					WorkflowID:  fnID,
+					RunID:       runID,
+					WorkspaceID: wsID,
+				},
+				Payload: osqueue.PayloadEdge{Edge: inngest.Edge{Incoming: "step-" + kind}},
+			},
+		}
+		_, err := q.EnqueueItem(ctx, item, at)
+		require.NoError(t, err)
+	}
+	enqueue(runA, osqueue.KindEdge, now.Add(-time.Second))
+	enqueue(runA, osqueue.KindSleep, now.Add(2*time.Hour))
+	enqueue(runB, osqueue.KindSleep, now.Add(time.Hour))
+
+	t.Run("It lists a function's future jobs in order", func(t *testing.T) {
+		jobs, err := q.ScheduledJobs(ctx, wsID, fnID, nil, 0)
+		require.NoError(t, err)
+		require.Len(t, jobs, 2)
+		require.Equal(t, runB, jobs[0].RunID)
+		require.Equal(t, runA, jobs[1].RunID)
+		require.Equal(t, osqueue.KindSleep, jobs[1].Kind)
+		require.Equal(t, "step-sleep", jobs[1].StepID)
+		require.WithinDuration(t, now.Add(2*time.Hour), jobs[1].At, time.Millisecond)
+	})
+
+	t.Run("It filters by run", func(t *testing.T) {
+		jobs, err := q.ScheduledJobs(ctx, wsID, fnID, &runA, 0)
+		require.NoError(t, err)
+		require.Len(t, jobs, 1)
+		require.Equal(t, runA, jobs[0].RunID)
+	})
+
+	t.Run("It filters by workspace", func(t *testing.T) {
+		jobs, err := q.ScheduledJobs(ctx, uuid.New(), fnID, nil, 0)
+		require.NoError(t, err)
+		require.Empty(t, jobs)
+	})
+}
+
+func TestQueueAdvanceTime(t *testing.T) {
+	r := miniredis.RunT(t)
+	rc, err := rueidis.NewClient(rueidis.ClientOption{
+		InitAddress:  []string{r.Addr()},
+		DisableCache: true,
+	})
+	require.NoError(t, err)
+	defer rc.Close()
+
+	q := NewQueue(rc)
+	ctx := context.Background()
+
+	now := time.Now()
+	sleep, err := q.EnqueueItem(ctx, QueueItem{Data: osqueue.Item{Kind: osqueue.KindSleep}}, now.Add(48*time.Hour))
+	require.NoError(t, err)
+	short, err := q.EnqueueItem(ctx, QueueItem{Data: osqueue.Item{Kind: osqueue.KindPause}}, now.Add(time.Hour))
+	require.NoError(t, err)
+	edge, err := q.EnqueueItem(ctx, QueueItem{Data: osqueue.Item{Kind: osqueue.KindEdge}}, now.Add(48*time.Hour))
+	require.NoError(t, err)
+
+	n, err := q.AdvanceTime(ctx, 24*time.Hour, osqueue.KindSleep, osqueue.KindPause)
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+
+	// The sleep is moved forward by a day.
+	item := getQueueItem(t, r, sleep.ID)
+	require.WithinDuration(t, now.Add(24*time.Hour), time.UnixMilli(item.AtMS), 10*time.Millisecond)
+	score, err := r.ZScore(q.kg.QueueIndex(item.Queue()), item.ID)
+	require.NoError(t, err)
+	require.EqualValues(t, item.AtMS, score)
+
+	// Items are never scheduled in the past.
+	item = getQueueItem(t, r, short.ID)
+	require.WithinDuration(t, now, time.UnixMilli(item.AtMS), 100*time.Millisecond)
+
+	// Other kinds are untouched.
+	item = getQueueItem(t, r, edge.ID)
+	require.Equal(t, edge.AtMS, item.AtMS)
+}
+
 func TestCheckList(t *testing.T) {
 	checks := []struct {
 		Check    string
@@ -2364,3 +3377,159 @@ func TestCheckList(t *testing.T) {
 }
 
 func int64ptr(i int64) *int64 { return &i }
+
+func TestQueueNamespaces(t *testing.T) {
+	r := miniredis.RunT(t)
+	rc, err := rueidis.NewClient(rueidis.ClientOption{
+		InitAddress:  []string{r.Addr()},
+		DisableCache: true,
+	})
+	require.NoError(t, err)
+	defer rc.Close()
+
+	require.Equal(t, "{queue}", NamespacedPrefix("", "{queue}"))
+	require.Equal(t, "staging:{queue}", NamespacedPrefix("staging", "{queue}"))
+
+	ctx := context.Background()
+	prod := NewQueue(rc, WithQueueKeyGenerator(&DefaultQueueKeyGenerator{Prefix: NamespacedPrefix("prod", "{queue}")}))
+	staging := NewQueue(rc, WithQueueKeyGenerator(&DefaultQueueKeyGenerator{Prefix: NamespacedPrefix("staging", "{queue}")}))
+
+	wsID, fnID := uuid.New(), uuid.New()
+	item := QueueItem{
+		WorkflowID: fnID,
+		Data: osqueue.Item{
+			WorkspaceID: wsID,
+			Kind:        osqueue.KindSleep,
+			Identifier: state.Identifier{
+				WorkflowID:  fnID,
+				RunID:       ulid.Make(),
+				WorkspaceID: wsID,
+			},
+		},
+	}
+	_, err = prod.EnqueueItem(ctx, item, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+
+	jobs, err := prod.ScheduledJobs(ctx, wsID, fnID, nil, 0)
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+
+	// Queues within other namespaces don't see the item.
+	jobs, err = staging.ScheduledJobs(ctx, wsID, fnID, nil, 0)
+	require.NoError(t, err)
+	require.Empty(t, jobs)
+
+	for _, k := range r.Keys() {
+		require.True(t, strings.HasPrefix(k, "prod:{queue}"), k)
+	}
+}
+
+func TestQueueCountConcurrencyKeys(t *testing.T) {
+	ctx := context.Background()
+	r := miniredis.RunT(t)
+
+	rc, err := rueidis.NewClient(rueidis.ClientOption{
+		InitAddress:  []string{r.Addr()},
+		DisableCache: true,
+	})
+	require.NoError(t, err)
+	defer rc.Close()
+
+	q := NewQueue(rc)
+	fnID := uuid.New()
+
+	counts, err := q.CountConcurrencyKeys(ctx, fnID, nil)
+	require.NoError(t, err)
+	require.Empty(t, counts)
+
+	// Each run records one key per limit.
+	for i := 0; i < 20; i++ {
+		counts, err = q.CountConcurrencyKeys(ctx, fnID, []state.CustomConcurrency{
+			{Key: fmt.Sprintf("f:%s:user-%d", fnID, i), Hash: "per-user", Limit: 1},
+			{Key: fmt.Sprintf("f:%s:plan-%d", fnID, i%2), Hash: "per-plan", Limit: 5},
+		})
+		require.NoError(t, err)
+	}
+	require.EqualValues(t, 20, counts["per-user"])
+	require.EqualValues(t, 2, counts["per-plan"])
+
+	// Keys are counted per function.
+	counts, err = q.CountConcurrencyKeys(ctx, uuid.New(), []state.CustomConcurrency{
+		{Key: "f:other:user-1", Hash: "per-user", Limit: 1},
+	})
+	require.NoError(t, err)
+	require.EqualValues(t, 1, counts["per-user"])
+}
+
+// BenchmarkQueueLease leases a batch of a partition's items by leasing each item in
+// turn, requiring a round trip per item.
+func BenchmarkQueueLease(b *testing.B) {
+	benchmarkQueueLease(b, false)
+}
+
+// BenchmarkQueueLeaseMany leases a batch of a partition's items via LeaseMany.
+func BenchmarkQueueLeaseMany(b *testing.B) {
+	benchmarkQueueLease(b, true)
+}
+
+// roundTripClient counts the round trips made to Redis.
+type roundTripClient struct {
+	rueidis.Client
+	count int
+}
+
+func (c *roundTripClient) Do(ctx context.Context, cmd rueidis.Completed) rueidis.RedisResult {
+	c.count++
+	return c.Client.Do(ctx, cmd)
+}
+
+func (c *roundTripClient) DoMulti(ctx context.Context, multi ...rueidis.Completed) []rueidis.RedisResult {
+	c.count++
+	return c.Client.DoMulti(ctx, multi...)
+}
+
+func benchmarkQueueLease(b *testing.B, many bool) {
+	r := miniredis.RunT(b)
+	client, err := rueidis.NewClient(rueidis.ClientOption{
+		InitAddress:  []string{r.Addr()},
+		DisableCache: true,
+	})
+	require.NoError(b, err)
+	defer client.Close()
+	rc := &roundTripClient{Client: client}
+
+	q := NewQueue(rc)
+	ctx := context.Background()
+
+	roundTrips := 0
+	for n := 0; n < b.N; n++ {
+		b.StopTimer()
+		wfID := uuid.New()
+		p := QueuePartition{WorkflowID: wfID}
+		items := make([]QueueItem, QueueLeaseBatchSize)
+		for i := range items {
+			items[i], err = q.EnqueueItem(ctx, QueueItem{WorkflowID: wfID}, time.Now())
+			require.NoError(b, err)
+		}
+		before := rc.count
+		b.StartTimer()
+
+		if many {
+			_, errs := q.LeaseMany(ctx, p, items, time.Minute, getNow(), newLeaseDenyList())
+			for _, err := range errs {
+				require.NoError(b, err)
+			}
+		} else {
+			denies := newLeaseDenyList()
+			for _, item := range items {
+				_, err := q.Lease(ctx, p, item, time.Minute, getNow(), denies)
+				require.NoError(b, err)
+			}
+		}
+
+		b.StopTimer()
+		roundTrips += rc.count - before
+		b.StartTimer()
+	}
+	b.ReportMetric(float64(roundTrips)/float64(b.N), "roundtrips/op")
+}
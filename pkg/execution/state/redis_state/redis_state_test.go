@@ -6,7 +6,9 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/alicebob/miniredis/v2"
 	"github.com/google/uuid"
@@ -14,6 +16,7 @@ import (
 	"github.com/inngest/inngest/pkg/event"
 	"github.com/inngest/inngest/pkg/execution/state"
 	"github.com/inngest/inngest/pkg/execution/state/testharness"
+	"github.com/inngest/inngest/pkg/inngest"
 	"github.com/oklog/ulid/v2"
 	"github.com/redis/rueidis"
 	"github.com/stretchr/testify/require"
@@ -109,6 +112,457 @@ func TestStateHarness(t *testing.T) {
 	testharness.CheckState(t, create)
 }
 
+func TestStateHarnessClusterMode(t *testing.T) {
+	r := miniredis.RunT(t)
+	rc, err := rueidis.NewClient(rueidis.ClientOption{
+		InitAddress:  []string{r.Addr()},
+		DisableCache: true,
+	})
+	require.NoError(t, err)
+	defer rc.Close()
+
+	// miniredis doesn't enforce hash slots, so assert that every script's keys
+	// share a slot as Redis Cluster would.
+	sm, err := New(
+		context.Background(),
+		WithKeyGenerator(ClusterKeyFunc{Prefix: "test"}),
+		WithClusterMode(),
+		WithFunctionLoader(testharness.FunctionLoader()),
+		WithRedisClient(&slotCheckingClient{Client: rc, t: t}),
+	)
+	require.NoError(t, err)
+
+	create := func() (state.Manager, func()) {
+		return sm, func() {
+			r.FlushAll()
+		}
+	}
+
+	testharness.CheckState(t, create)
+}
+
+type anyFunctionLoader struct{}
+
+func (anyFunctionLoader) LoadFunction(ctx context.Context, id state.Identifier) (*inngest.Function, error) {
+	return &inngest.Function{ID: id.WorkflowID}, nil
+}
+
+func TestSaveResponseWithMetadata(t *testing.T) {
+	ctx := context.Background()
+	r := miniredis.RunT(t)
+	sm, err := New(
+		ctx,
+		WithKeyPrefix("{test}:"),
+		WithFunctionLoader(anyFunctionLoader{}),
+		WithConnectOpts(rueidis.ClientOption{
+			InitAddress:  []string{r.Addr()},
+			DisableCache: true,
+		}),
+	)
+	require.NoError(t, err)
+
+	id := state.Identifier{
+		WorkflowID: uuid.New(),
+		RunID:      ulid.MustNew(ulid.Now(), rand.Reader),
+	}
+	_, err = sm.New(ctx, state.Input{
+		Identifier:     id,
+		EventBatchData: []map[string]any{{"name": "test/event"}},
+	})
+	require.NoError(t, err)
+
+	saver, ok := sm.(state.ResponseMetadataSaver)
+	require.True(t, ok)
+
+	md := state.MetadataUpdate{
+		Context:                   map[string]any{"key": "value"},
+		DisableImmediateExecution: true,
+		RequestVersion:            1,
+	}
+	err = saver.SaveResponseWithMetadata(ctx, id, "step", `{"data":1}`, md)
+	require.NoError(t, err)
+
+	kf := DefaultKeyFunc{Prefix: "{test}:"}
+	stack, err := r.List(kf.Stack(ctx, id.RunID))
+	require.NoError(t, err)
+	require.Equal(t, []string{"step"}, stack)
+	require.Equal(t, `{"data":1}`, r.HGet(kf.Actions(ctx, id), "step"))
+
+	meta, err := sm.Metadata(ctx, id.RunID)
+	require.NoError(t, err)
+	require.Equal(t, 1, meta.RequestVersion)
+	require.True(t, meta.DisableImmediateExecution)
+	require.Equal(t, "value", meta.Context["key"])
+
+	err = saver.SaveResponseWithMetadata(ctx, id, "step", `{"data":2}`, md)
+	require.ErrorIs(t, err, state.ErrDuplicateResponse)
+}
+
+func TestLoadLazy(t *testing.T) {
+	ctx := context.Background()
+	r := miniredis.RunT(t)
+	sm, err := New(
+		ctx,
+		WithKeyPrefix("{test}:"),
+		WithFunctionLoader(anyFunctionLoader{}),
+		WithConnectOpts(rueidis.ClientOption{
+			InitAddress:  []string{r.Addr()},
+			DisableCache: true,
+		}),
+	)
+	require.NoError(t, err)
+	l, ok := sm.(state.LazyLoader)
+	require.True(t, ok)
+
+	create := func(events []map[string]any) state.Identifier {
+		id := state.Identifier{
+			WorkflowID: uuid.New(),
+			RunID:      ulid.MustNew(ulid.Now(), rand.Reader),
+		}
+		for range events {
+			id.EventIDs = append(id.EventIDs, ulid.MustNew(ulid.Now(), rand.Reader))
+		}
+		_, err := sm.New(ctx, state.Input{Identifier: id, EventBatchData: events})
+		require.NoError(t, err)
+		return id
+	}
+
+	t.Run("Only the first event is loaded", func(t *testing.T) {
+		events := []map[string]any{
+			{"name": "test/event", "data": map[string]any{"n": 1.0, "s": "]}"}},
+			{"name": "test/event", "data": map[string]any{"n": 2.0}},
+			{"name": "test/event", "data": map[string]any{"n": 3.0}},
+		}
+		id := create(events)
+
+		s, err := l.LoadLazy(ctx, id.RunID)
+		require.NoError(t, err)
+		require.Equal(t, events[:1], s.Events())
+		require.Equal(t, events[0], s.Event())
+		require.Equal(t, id.EventIDs, s.Identifier().EventIDs)
+		require.True(t, state.HasLazyEvents(s))
+
+		s, err = sm.Load(ctx, id.RunID)
+		require.NoError(t, err)
+		require.Equal(t, events, s.Events())
+		require.False(t, state.HasLazyEvents(s))
+	})
+
+	t.Run("Large first events are loaded", func(t *testing.T) {
+		events := []map[string]any{
+			{"name": "test/event", "data": map[string]any{"large": strings.Repeat("a", lazyEventBytes)}},
+			{"name": "test/event", "data": map[string]any{"n": 2.0}},
+		}
+		id := create(events)
+
+		s, err := l.LoadLazy(ctx, id.RunID)
+		require.NoError(t, err)
+		require.Equal(t, events[:1], s.Events())
+	})
+
+	t.Run("Single events are loaded in full", func(t *testing.T) {
+		events := []map[string]any{{"name": "test/event"}}
+		id := create(events)
+
+		s, err := l.LoadLazy(ctx, id.RunID)
+		require.NoError(t, err)
+		require.Equal(t, events, s.Events())
+		require.False(t, state.HasLazyEvents(s))
+	})
+}
+
+func TestNewMany(t *testing.T) {
+	ctx := context.Background()
+	r := miniredis.RunT(t)
+	sm, err := New(
+		ctx,
+		WithKeyPrefix("{test}:"),
+		WithFunctionLoader(anyFunctionLoader{}),
+		WithConnectOpts(rueidis.ClientOption{
+			InitAddress:  []string{r.Addr()},
+			DisableCache: true,
+		}),
+	)
+	require.NoError(t, err)
+
+	creator, ok := sm.(state.BulkCreator)
+	require.True(t, ok)
+
+	existing := state.Identifier{
+		WorkflowID: uuid.New(),
+		RunID:      ulid.MustNew(ulid.Now(), rand.Reader),
+		Key:        "existing",
+	}
+	_, err = sm.New(ctx, state.Input{Identifier: existing})
+	require.NoError(t, err)
+
+	inputs := []state.Input{}
+	for i := 0; i < 3; i++ {
+		inputs = append(inputs, state.Input{
+			Identifier: state.Identifier{
+				WorkflowID: uuid.New(),
+				RunID:      ulid.MustNew(ulid.Now(), rand.Reader),
+				Key:        fmt.Sprintf("key-%d", i),
+			},
+			EventBatchData: []map[string]any{{"name": "test/event"}},
+		})
+	}
+	// The same run can't be created twice.
+	duplicate := existing
+	duplicate.RunID = ulid.MustNew(ulid.Now(), rand.Reader)
+	inputs = append(inputs, state.Input{Identifier: duplicate})
+
+	states, errs := creator.NewMany(ctx, inputs)
+	require.Len(t, states, 4)
+	require.Len(t, errs, 4)
+	for i := 0; i < 3; i++ {
+		require.NoError(t, errs[i])
+		require.Equal(t, inputs[i].Identifier.RunID, states[i].RunID())
+
+		loaded, err := sm.Load(ctx, inputs[i].Identifier.RunID)
+		require.NoError(t, err)
+		require.Equal(t, "test/event", loaded.Event()["name"])
+	}
+	require.Nil(t, states[3])
+	require.ErrorIs(t, errs[3], state.ErrIdentifierExists)
+}
+
+func TestStateHarnessPauseShards(t *testing.T) {
+	r := miniredis.RunT(t)
+	shards, clients := newPauseShards(t, 3)
+
+	sm, err := New(
+		context.Background(),
+		WithKeyPrefix("{test}:"),
+		WithFunctionLoader(testharness.FunctionLoader()),
+		WithConnectOpts(rueidis.ClientOption{
+			InitAddress:  []string{r.Addr()},
+			DisableCache: true,
+		}),
+		WithPauseShards(clients...),
+	)
+	require.NoError(t, err)
+
+	create := func() (state.Manager, func()) {
+		return sm, func() {
+			r.FlushAll()
+			for _, s := range shards {
+				s.FlushAll()
+			}
+		}
+	}
+
+	testharness.CheckState(t, create)
+}
+
+func TestPauseShardsDistribution(t *testing.T) {
+	ctx := context.Background()
+	r := miniredis.RunT(t)
+	shards, clients := newPauseShards(t, 3)
+
+	sm, err := New(
+		ctx,
+		WithKeyPrefix("{test}:"),
+		WithFunctionLoader(testharness.FunctionLoader()),
+		WithConnectOpts(rueidis.ClientOption{
+			InitAddress:  []string{r.Addr()},
+			DisableCache: true,
+		}),
+		WithPauseShards(clients...),
+	)
+	require.NoError(t, err)
+	m := sm.(*mgr)
+
+	wsID := uuid.New()
+	ids := []uuid.UUID{}
+	for i := 0; i < 30; i++ {
+		evt := fmt.Sprintf("test/event-%d", i)
+		p := state.Pause{
+			ID:          uuid.New(),
+			WorkspaceID: wsID,
+			Identifier: state.Identifier{
+				WorkflowID: uuid.New(),
+				RunID:      ulid.MustNew(ulid.Now(), rand.Reader),
+			},
+			Incoming: "step",
+			Expires:  state.Time(time.Now().Add(time.Minute)),
+			Event:    &evt,
+		}
+		require.NoError(t, sm.SavePause(ctx, p))
+		ids = append(ids, p.ID)
+
+		// All pauses for the event are stored in the event's shard.
+		iter, err := sm.PausesByEvent(ctx, wsID, evt)
+		require.NoError(t, err)
+		require.Equal(t, 1, iter.Count())
+
+		evals, err := m.EvaluablesByEvent(ctx, wsID, evt, p.ID)
+		require.NoError(t, err)
+		require.Len(t, evals, 1)
+	}
+
+	// Pauses are spread across every shard, and none are stored alongside run state.
+	for n, s := range shards {
+		require.NotEmpty(t, s.Keys(), "shard %d has no pauses", n)
+	}
+	require.Empty(t, r.Keys())
+
+	// Pauses can be loaded by ID regardless of shard.
+	pauses, err := sm.PausesByID(ctx, ids...)
+	require.NoError(t, err)
+	require.Len(t, pauses, len(ids))
+
+	for _, id := range ids {
+		require.NoError(t, sm.LeasePause(ctx, id))
+		require.NoError(t, sm.ConsumePause(ctx, id, nil))
+		_, err := sm.PauseByID(ctx, id)
+		require.ErrorIs(t, err, state.ErrPauseNotFound)
+	}
+}
+
+func TestReplicaReads(t *testing.T) {
+	ctx := context.Background()
+	r := miniredis.RunT(t)
+	_, replicas := newPauseShards(t, 1)
+
+	newMgr := func(opts ...Opt) state.Manager {
+		sm, err := New(
+			ctx,
+			append([]Opt{
+				WithKeyPrefix("{test}:"),
+				WithFunctionLoader(anyFunctionLoader{}),
+			}, opts...)...,
+		)
+		require.NoError(t, err)
+		return sm
+	}
+	primary := WithConnectOpts(rueidis.ClientOption{
+		InitAddress:  []string{r.Addr()},
+		DisableCache: true,
+	})
+
+	// Runs started within the lag are read from the primary, so reader reads
+	// every run from replicas.  Replication is simulated by writing to the
+	// replica directly.
+	writer := newMgr(primary, WithReplicas(time.Hour, replicas...))
+	reader := newMgr(primary, WithReplicas(0, replicas...))
+	replica := newMgr(WithRedisClient(replicas[0]))
+
+	id := state.Identifier{
+		WorkflowID: uuid.New(),
+		RunID:      ulid.MustNew(ulid.Now(), rand.Reader),
+	}
+	input := state.Input{
+		Identifier:     id,
+		EventBatchData: []map[string]any{{"name": "test/event"}},
+	}
+	_, err := writer.New(ctx, input)
+	require.NoError(t, err)
+
+	// The run hasn't been replicated, so reads fall back to the primary.
+	s, err := reader.Load(ctx, id.RunID)
+	require.NoError(t, err)
+	require.Equal(t, "test/event", s.Event()["name"])
+	_, err = reader.Metadata(ctx, id.RunID)
+	require.NoError(t, err)
+
+	_, err = replica.New(ctx, input)
+	require.NoError(t, err)
+	require.NoError(t, writer.SaveResponse(ctx, id, "step-a", `"primary"`))
+
+	// The replica is missing the step, so the load falls back to the primary.
+	s, err = reader.Load(ctx, id.RunID)
+	require.NoError(t, err)
+	require.Equal(t, "primary", s.Actions()["step-a"])
+
+	require.NoError(t, replica.SaveResponse(ctx, id, "step-a", `"replica"`))
+
+	s, err = reader.Load(ctx, id.RunID)
+	require.NoError(t, err)
+	require.Equal(t, "replica", s.Actions()["step-a"])
+
+	// Metadata is always read from the primary, as statuses change in place.
+	require.NoError(t, writer.SetStatus(ctx, id, enums.RunStatusCompleted))
+	md, err := reader.Metadata(ctx, id.RunID)
+	require.NoError(t, err)
+	require.Equal(t, enums.RunStatusCompleted, md.Status)
+	s, err = reader.Load(ctx, id.RunID)
+	require.NoError(t, err)
+	require.Equal(t, "replica", s.Actions()["step-a"])
+	require.Equal(t, enums.RunStatusCompleted, s.Metadata().Status)
+
+	// Recent runs are read from the primary by every process.
+	s, err = writer.Load(ctx, id.RunID)
+	require.NoError(t, err)
+	require.Equal(t, "primary", s.Actions()["step-a"])
+}
+
+func TestReplicator(t *testing.T) {
+	ctx := context.Background()
+	_, clients := newPauseShards(t, 2)
+
+	newMgr := func(opts ...Opt) state.Manager {
+		sm, err := New(
+			ctx,
+			append([]Opt{
+				WithKeyPrefix("{test}:"),
+				WithFunctionLoader(anyFunctionLoader{}),
+			}, opts...)...,
+		)
+		require.NoError(t, err)
+		return sm
+	}
+
+	// Mutations to the primary are shipped to the standby's state store.
+	standby := newMgr(WithRedisClient(clients[1]))
+	replicator := state.NewAsyncReplicator(state.NewManagerReplicator(standby), state.AsyncReplicatorOpts{})
+	primary := newMgr(WithRedisClient(clients[0]), WithReplicator(replicator))
+
+	id := state.Identifier{
+		WorkflowID: uuid.New(),
+		RunID:      ulid.MustNew(ulid.Now(), rand.Reader),
+	}
+	_, err := primary.New(ctx, state.Input{
+		Identifier:     id,
+		EventBatchData: []map[string]any{{"name": "test/event"}},
+	})
+	require.NoError(t, err)
+	md := state.MetadataUpdate{Context: map[string]any{"key": "value"}, RequestVersion: 1}
+	require.NoError(t, primary.(state.ResponseMetadataSaver).SaveResponseWithMetadata(ctx, id, "step-a", `"a"`, md))
+	require.NoError(t, primary.SaveResponse(ctx, id, "step-b", `"b"`))
+	require.NoError(t, primary.SetStatus(ctx, id, enums.RunStatusCompleted))
+	// Duplicate responses aren't committed, so they're not replicated.
+	require.ErrorIs(t, primary.SaveResponse(ctx, id, "step-b", `"c"`), state.ErrDuplicateResponse)
+
+	require.NoError(t, replicator.Close(ctx))
+	require.Zero(t, replicator.Dropped())
+
+	s, err := standby.Load(ctx, id.RunID)
+	require.NoError(t, err)
+	require.Equal(t, "test/event", s.Event()["name"])
+	require.Equal(t, []string{"step-a", "step-b"}, s.Stack())
+	require.Equal(t, "b", s.Actions()["step-b"])
+	require.Equal(t, enums.RunStatusCompleted, s.Metadata().Status)
+	require.Equal(t, 1, s.Metadata().RequestVersion)
+	require.Equal(t, "value", s.Metadata().Context["key"])
+}
+
+func newPauseShards(t *testing.T, n int) ([]*miniredis.Miniredis, []rueidis.Client) {
+	shards := make([]*miniredis.Miniredis, n)
+	clients := make([]rueidis.Client, n)
+	for i := range shards {
+		shards[i] = miniredis.RunT(t)
+		rc, err := rueidis.NewClient(rueidis.ClientOption{
+			InitAddress:  []string{shards[i].Addr()},
+			DisableCache: true,
+		})
+		require.NoError(t, err)
+		t.Cleanup(rc.Close)
+		clients[i] = rc
+	}
+	return shards, clients
+}
+
 func TestScanIter(t *testing.T) {
 	ctx := context.Background()
 	redis := miniredis.RunT(t)
@@ -225,3 +679,132 @@ func BenchmarkNew(b *testing.B) {
 	}
 
 }
+
+func TestConsumePauseSeparateData(t *testing.T) {
+	ctx := context.Background()
+	r := miniredis.RunT(t)
+	_, pauseClients := newPauseShards(t, 1)
+	pauseClient := &failingScriptClient{Client: pauseClients[0]}
+
+	// Pauses are stored separately to run state, so pause data is saved
+	// separately when consuming pauses.
+	sm, err := New(
+		ctx,
+		WithKeyPrefix("{test}:"),
+		WithFunctionLoader(anyFunctionLoader{}),
+		WithConnectOpts(rueidis.ClientOption{
+			InitAddress:  []string{r.Addr()},
+			DisableCache: true,
+		}),
+		WithPauseRedisClient(pauseClient),
+	)
+	require.NoError(t, err)
+
+	id := state.Identifier{
+		WorkflowID: uuid.New(),
+		RunID:      ulid.MustNew(ulid.Now(), rand.Reader),
+	}
+	_, err = sm.New(ctx, state.Input{
+		Identifier:     id,
+		EventBatchData: []map[string]any{{"name": "test/event"}},
+	})
+	require.NoError(t, err)
+
+	pause := state.Pause{
+		ID:          uuid.New(),
+		WorkspaceID: id.WorkspaceID,
+		Identifier:  id,
+		Incoming:    "step",
+		DataKey:     "wait-data",
+		Expires:     state.Time(time.Now().Add(time.Hour)),
+	}
+	require.NoError(t, sm.SavePause(ctx, pause))
+
+	// Consuming the pause fails after its data is saved.  The pause is kept, so
+	// that consuming the pause can be retried.
+	pauseClient.fail = true
+	err = sm.ConsumePause(ctx, pause.ID, map[string]any{"ok": true})
+	require.Error(t, err)
+	pauseClient.fail = false
+
+	_, err = sm.PauseByID(ctx, pause.ID)
+	require.NoError(t, err)
+	s, err := sm.Load(ctx, id.RunID)
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"ok": true}, s.Actions()["wait-data"])
+
+	// Saving the data fails, so the pause isn't consumed.
+	r.SetError("injected failure")
+	err = sm.ConsumePause(ctx, pause.ID, map[string]any{"ok": true})
+	require.Error(t, err)
+	r.SetError("")
+	_, err = sm.PauseByID(ctx, pause.ID)
+	require.NoError(t, err)
+
+	// Retrying consumes the pause, storing its data once.
+	require.NoError(t, sm.ConsumePause(ctx, pause.ID, map[string]any{"ok": true}))
+	_, err = sm.PauseByID(ctx, pause.ID)
+	require.ErrorIs(t, err, state.ErrPauseNotFound)
+
+	s, err = sm.Load(ctx, id.RunID)
+	require.NoError(t, err)
+	require.Equal(t, []string{"wait-data"}, s.Stack())
+	require.Equal(t, map[string]any{"ok": true}, s.Actions()["wait-data"])
+}
+
+// failingScriptClient fails scripts while fail is set, allowing other commands.
+type failingScriptClient struct {
+	rueidis.Client
+	fail bool
+}
+
+func (c *failingScriptClient) Do(ctx context.Context, cmd rueidis.Completed) rueidis.RedisResult {
+	if name := cmd.Commands()[0]; c.fail && strings.HasPrefix(strings.ToUpper(name), "EVAL") {
+		// Unknown commands are rejected by Redis, failing the script.
+		return c.Client.Do(ctx, c.Client.B().Arbitrary("INJECTED_FAILURE").Build())
+	}
+	return c.Client.Do(ctx, cmd)
+}
+
+// slotCheckingClient fails the test if a script is called with keys in more than
+// one hash slot, which Redis Cluster rejects.
+type slotCheckingClient struct {
+	rueidis.Client
+	t *testing.T
+}
+
+func (c *slotCheckingClient) Do(ctx context.Context, cmd rueidis.Completed) rueidis.RedisResult {
+	c.check(cmd)
+	return c.Client.Do(ctx, cmd)
+}
+
+func (c *slotCheckingClient) DoMulti(ctx context.Context, cmds ...rueidis.Completed) []rueidis.RedisResult {
+	for _, cmd := range cmds {
+		c.check(cmd)
+	}
+	return c.Client.DoMulti(ctx, cmds...)
+}
+
+func (c *slotCheckingClient) check(cmd rueidis.Completed) {
+	args := cmd.Commands()
+	if !strings.HasPrefix(strings.ToUpper(args[0]), "EVAL") || len(args) < 3 {
+		return
+	}
+	n, err := strconv.Atoi(args[2])
+	require.NoError(c.t, err)
+	keys := args[3 : 3+n]
+	for _, key := range keys {
+		require.Equal(c.t, hashTag(keys[0]), hashTag(key), "script keys must share a hash slot: %v", keys)
+	}
+}
+
+// hashTag returns the part of the key which Redis Cluster hashes to find the
+// key's slot.
+func hashTag(key string) string {
+	if start := strings.IndexByte(key, '{'); start >= 0 {
+		if end := strings.IndexByte(key[start+1:], '}'); end > 0 {
+			return key[start+1 : start+1+end]
+		}
+	}
+	return key
+}
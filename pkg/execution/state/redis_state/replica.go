@@ -0,0 +1,59 @@
+package redis_state
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cespare/xxhash/v2"
+	"github.com/inngest/inngest/pkg/execution/state"
+	"github.com/oklog/ulid/v2"
+	"github.com/redis/rueidis"
+)
+
+// replicate sends the committed mutation to the replicator, if set.  Errors are
+// ignored, as the mutation is already committed to the primary.
+func (m mgr) replicate(ctx context.Context, mut state.Mutation) {
+	if m.replicator == nil {
+		return
+	}
+	mut.At = time.Now()
+	_ = m.replicator.Replicate(ctx, mut)
+}
+
+// readClient returns the client used to read the given run's state.  This is the
+// primary if no replicas are configured or if the run started within the replica
+// lag, as recent runs are most likely being executed by any process.  Otherwise,
+// runs are spread across each replica by ID.
+func (m mgr) readClient(runID ulid.ULID) rueidis.Client {
+	if len(m.replicas) == 0 {
+		return m.r
+	}
+	if time.Since(ulid.Time(runID.Time())) < m.replicaLag {
+		return m.r
+	}
+	n := xxhash.Sum64String(runID.String())
+	return m.replicas[n%uint64(len(m.replicas))]
+}
+
+// loadReplica loads the run's state from the given replica, falling back to the
+// primary if the replica errors or has not yet replicated every step in the
+// run's stack.  The stack is append-only and is pushed alongside each step's
+// output, so its length is compared against the primary's to detect stale reads
+// without loading the run's state twice.
+func (m mgr) loadReplica(ctx context.Context, r rueidis.Client, runID ulid.ULID, lazy bool) (state.State, error) {
+	s, err := m.load(ctx, r, runID, lazy)
+	if err != nil {
+		return m.load(ctx, m.r, runID, lazy)
+	}
+
+	cmd := m.r.B().Llen().Key(m.kf.Stack(ctx, runID)).Build()
+	size, err := m.r.Do(ctx, cmd).AsInt64()
+	if err != nil {
+		return nil, fmt.Errorf("error fetching stack length: %w", err)
+	}
+	if int64(len(s.Stack())) < size {
+		return m.load(ctx, m.r, runID, lazy)
+	}
+	return s, nil
+}
@@ -0,0 +1,386 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cespare/xxhash/v2"
+	"github.com/inngest/inngest/pkg/enums"
+	"github.com/inngest/inngest/pkg/logger"
+	"github.com/oklog/ulid/v2"
+)
+
+// MutationType is the kind of change recorded within a Mutation.
+type MutationType string
+
+const (
+	// MutationRunCreated is recorded when a run's state is created.
+	MutationRunCreated MutationType = "run_created"
+	// MutationMetadataUpdated is recorded when a run's metadata is updated.
+	MutationMetadataUpdated MutationType = "metadata_updated"
+	// MutationStepSaved is recorded when a step's output or error is saved,
+	// optionally with a metadata update.
+	MutationStepSaved MutationType = "step_saved"
+	// MutationStatusChanged is recorded when a run's status is set.
+	MutationStatusChanged MutationType = "status_changed"
+	// MutationRunCancelled is recorded when a run is cancelled.
+	MutationRunCancelled MutationType = "run_cancelled"
+	// MutationRunDeleted is recorded when a run's state is deleted.
+	MutationRunDeleted MutationType = "run_deleted"
+)
+
+var (
+	// ErrReplicationBufferFull is returned when an AsyncReplicator's buffer is
+	// full, and the mutation is dropped.
+	ErrReplicationBufferFull = fmt.Errorf("replication buffer full")
+	// ErrReplicatorClosed is returned when replicating mutations after an
+	// AsyncReplicator is closed.
+	ErrReplicatorClosed = fmt.Errorf("replicator closed")
+)
+
+// Mutation is a single change to a run's state, recorded after the change is
+// committed to the state store.  Mutations for a run are recorded in the order
+// that they're committed.
+type Mutation struct {
+	Type  MutationType `json:"type"`
+	RunID ulid.ULID    `json:"runID"`
+	At    time.Time    `json:"at"`
+	// Identifier is the run's identifier.  This is nil for metadata updates,
+	// which only reference the run ID.
+	Identifier *Identifier `json:"id,omitempty"`
+	// Input is the input used to create the run, for MutationRunCreated.
+	Input *Input `json:"input,omitempty"`
+	// StepID and Output are the saved step's ID and marshalled output, for
+	// MutationStepSaved.
+	StepID string `json:"stepID,omitempty"`
+	Output string `json:"output,omitempty"`
+	// Metadata is the metadata update for MutationMetadataUpdated, and for
+	// MutationStepSaved when saved alongside the step.
+	Metadata *MetadataUpdate `json:"metadata,omitempty"`
+	// Status is the run's new status, for MutationStatusChanged.
+	Status enums.RunStatus `json:"status,omitempty"`
+}
+
+// Replicator receives each mutation to run state, eg. to ship mutations to a
+// secondary region's state store for warm-standby disaster recovery.
+//
+// Replicators are called synchronously after each write and their errors don't
+// fail the write.  Wrap slow replicators with NewAsyncReplicator.
+type Replicator interface {
+	Replicate(ctx context.Context, m Mutation) error
+}
+
+// NewManagerReplicator returns a Replicator which applies each mutation to the
+// given state manager, eg. a state store within a standby region.  Applying a
+// mutation more than once is safe, so mutations can be retried and replayed.
+func NewManagerReplicator(m Manager) Replicator {
+	return managerReplicator{m: m}
+}
+
+type managerReplicator struct {
+	m Manager
+}
+
+func (r managerReplicator) Replicate(ctx context.Context, m Mutation) error {
+	var err error
+	switch m.Type {
+	case MutationRunCreated:
+		if m.Input == nil {
+			return fmt.Errorf("run created mutation has no input")
+		}
+		_, err = r.m.New(ctx, *m.Input)
+		if errors.Is(err, ErrIdentifierExists) {
+			return nil
+		}
+		return err
+	case MutationMetadataUpdated:
+		if m.Metadata == nil {
+			return fmt.Errorf("metadata mutation has no metadata")
+		}
+		return r.m.UpdateMetadata(ctx, m.RunID, *m.Metadata)
+	}
+
+	if m.Identifier == nil {
+		return fmt.Errorf("%s mutation has no identifier", m.Type)
+	}
+	switch m.Type {
+	case MutationStepSaved:
+		if m.Metadata != nil {
+			if err := r.m.UpdateMetadata(ctx, m.RunID, *m.Metadata); err != nil {
+				return err
+			}
+		}
+		err = r.m.SaveResponse(ctx, *m.Identifier, m.StepID, m.Output)
+		if errors.Is(err, ErrDuplicateResponse) {
+			return nil
+		}
+	case MutationStatusChanged:
+		err = r.m.SetStatus(ctx, *m.Identifier, m.Status)
+	case MutationRunCancelled:
+		err = r.m.Cancel(ctx, *m.Identifier)
+		if errors.Is(err, ErrFunctionCancelled) || errors.Is(err, ErrFunctionComplete) || errors.Is(err, ErrFunctionFailed) {
+			return nil
+		}
+	case MutationRunDeleted:
+		err = r.m.Delete(ctx, *m.Identifier)
+	default:
+		err = fmt.Errorf("unknown mutation type: %s", m.Type)
+	}
+	return err
+}
+
+// AsyncReplicatorOpts configures an AsyncReplicator.
+type AsyncReplicatorOpts struct {
+	// Buffer is the number of mutations buffered by each worker before new
+	// mutations are dropped.  Defaults to 1,000.
+	Buffer int
+	// Workers is the number of goroutines shipping mutations.  Each run's
+	// mutations are shipped in order by the same worker.  Defaults to 4.
+	Workers int
+	// Attempts is the maximum number of attempts to ship each mutation.
+	// Defaults to 3.
+	Attempts int
+	// Backoff is the delay between attempts, multiplied by the attempt number.
+	// Defaults to 100ms.
+	Backoff time.Duration
+	// Resync, if set, replicates a run's full state.  Runs whose mutations are
+	// dropped are resynced in the background, and their remaining mutations are
+	// skipped until they're resynced.  See migrate.Resync.
+	Resync func(ctx context.Context, runID ulid.ULID) error
+	// ResyncInterval is how often runs with dropped mutations are resynced.
+	// Defaults to 5 seconds.
+	ResyncInterval time.Duration
+}
+
+// NewAsyncReplicator returns a Replicator which ships mutations to r in the
+// background, so that replication never adds latency to state store writes.
+// Mutations are dropped when the buffer is full or when shipping fails after
+// each attempt;  dropped mutations are logged and counted by Dropped, and the
+// run is resynced if opts.Resync is set.
+func NewAsyncReplicator(r Replicator, opts AsyncReplicatorOpts) *AsyncReplicator {
+	if opts.Buffer <= 0 {
+		opts.Buffer = 1_000
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = 4
+	}
+	if opts.Attempts <= 0 {
+		opts.Attempts = 3
+	}
+	if opts.Backoff <= 0 {
+		opts.Backoff = 100 * time.Millisecond
+	}
+	if opts.ResyncInterval <= 0 {
+		opts.ResyncInterval = 5 * time.Second
+	}
+
+	a := &AsyncReplicator{
+		r:       r,
+		opts:    opts,
+		workers: make([]chan asyncMutation, opts.Workers),
+		wg:      &sync.WaitGroup{},
+		resync:  map[ulid.ULID]context.Context{},
+		stop:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	for n := range a.workers {
+		a.workers[n] = make(chan asyncMutation, opts.Buffer)
+		a.wg.Add(1)
+		go a.work(a.workers[n])
+	}
+	go a.resyncLoop()
+	return a
+}
+
+// AsyncReplicator ships mutations to a Replicator in the background.
+type AsyncReplicator struct {
+	r       Replicator
+	opts    AsyncReplicatorOpts
+	workers []chan asyncMutation
+	wg      *sync.WaitGroup
+
+	lock    sync.RWMutex
+	closed  bool
+	dropped atomic.Int64
+
+	// resync stores runs whose mutations were dropped, which are resynced in
+	// the background, with the context of the dropped mutation.
+	resyncLock sync.Mutex
+	resync     map[ulid.ULID]context.Context
+	resynced   atomic.Int64
+	stop       chan struct{}
+	stopped    chan struct{}
+}
+
+type asyncMutation struct {
+	ctx context.Context
+	m   Mutation
+}
+
+// Replicate buffers the mutation to be shipped, returning ErrReplicationBufferFull
+// without blocking if the buffer is full.
+func (a *AsyncReplicator) Replicate(ctx context.Context, m Mutation) error {
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+	if a.closed {
+		return ErrReplicatorClosed
+	}
+
+	w := a.workers[xxhash.Sum64(m.RunID[:])%uint64(len(a.workers))]
+	select {
+	case w <- asyncMutation{ctx: context.WithoutCancel(ctx), m: m}:
+		return nil
+	default:
+		a.drop(ctx, m, ErrReplicationBufferFull)
+		return ErrReplicationBufferFull
+	}
+}
+
+// Dropped returns the number of mutations which were not shipped.
+func (a *AsyncReplicator) Dropped() int64 {
+	return a.dropped.Load()
+}
+
+// Resynced returns the number of runs whose full state was replicated after
+// their mutations were dropped.
+func (a *AsyncReplicator) Resynced() int64 {
+	return a.resynced.Load()
+}
+
+// Close stops accepting mutations and waits for buffered mutations to be
+// shipped and for runs with dropped mutations to be resynced, or for ctx to be
+// done.
+func (a *AsyncReplicator) Close(ctx context.Context) error {
+	a.lock.Lock()
+	if !a.closed {
+		a.closed = true
+		for _, w := range a.workers {
+			close(w)
+		}
+	}
+	a.lock.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		a.wg.Wait()
+		// Stop resyncing once every buffered mutation is shipped, after a
+		// final resync.
+		a.closeResync()
+		<-a.stopped
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (a *AsyncReplicator) closeResync() {
+	a.resyncLock.Lock()
+	defer a.resyncLock.Unlock()
+	select {
+	case <-a.stop:
+	default:
+		close(a.stop)
+	}
+}
+
+func (a *AsyncReplicator) work(ch chan asyncMutation) {
+	defer a.wg.Done()
+	for item := range ch {
+		if a.resyncing(item.m.RunID) {
+			// The run's full state is replicated when it's resynced.
+			continue
+		}
+		var err error
+		for attempt := 1; attempt <= a.opts.Attempts; attempt++ {
+			if err = a.r.Replicate(item.ctx, item.m); err == nil {
+				break
+			}
+			if attempt < a.opts.Attempts {
+				time.Sleep(time.Duration(attempt) * a.opts.Backoff)
+			}
+		}
+		if err != nil {
+			a.drop(item.ctx, item.m, err)
+		}
+	}
+}
+
+func (a *AsyncReplicator) drop(ctx context.Context, m Mutation, err error) {
+	a.dropped.Add(1)
+	logger.From(ctx).Error().
+		Err(err).
+		Str("run_id", m.RunID.String()).
+		Str("mutation", string(m.Type)).
+		Bool("resync", a.opts.Resync != nil).
+		Msg("error replicating state mutation")
+
+	if a.opts.Resync != nil {
+		a.resyncLock.Lock()
+		a.resync[m.RunID] = context.WithoutCancel(ctx)
+		a.resyncLock.Unlock()
+	}
+}
+
+// resyncing returns whether the run's mutations were dropped, and the run is
+// yet to be resynced.
+func (a *AsyncReplicator) resyncing(runID ulid.ULID) bool {
+	a.resyncLock.Lock()
+	defer a.resyncLock.Unlock()
+	_, ok := a.resync[runID]
+	return ok
+}
+
+func (a *AsyncReplicator) resyncLoop() {
+	defer close(a.stopped)
+	if a.opts.Resync == nil {
+		<-a.stop
+		return
+	}
+
+	t := time.NewTicker(a.opts.ResyncInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			a.resyncRuns()
+		case <-a.stop:
+			a.resyncRuns()
+			return
+		}
+	}
+}
+
+// resyncRuns replicates the full state of each run whose mutations were dropped.
+func (a *AsyncReplicator) resyncRuns() {
+	a.resyncLock.Lock()
+	runs := a.resync
+	a.resync = map[ulid.ULID]context.Context{}
+	a.resyncLock.Unlock()
+
+	for runID, ctx := range runs {
+		// Mutations committed after the run is removed from the resync set
+		// are shipped as usual.  Applying mutations more than once is safe, so
+		// mutations which are also copied by the resync are fine.
+		if err := a.opts.Resync(ctx, runID); err != nil {
+			logger.From(ctx).Error().
+				Err(err).
+				Str("run_id", runID.String()).
+				Msg("error resyncing replicated run")
+			a.resyncLock.Lock()
+			if _, ok := a.resync[runID]; !ok {
+				a.resync[runID] = ctx
+			}
+			a.resyncLock.Unlock()
+			continue
+		}
+		a.resynced.Add(1)
+	}
+}
@@ -0,0 +1,105 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingReplicator struct {
+	lock    sync.Mutex
+	block   chan struct{}
+	fails   int
+	shipped []Mutation
+}
+
+func (r *recordingReplicator) Replicate(ctx context.Context, m Mutation) error {
+	if r.block != nil {
+		<-r.block
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.fails > 0 {
+		r.fails--
+		return fmt.Errorf("unavailable")
+	}
+	r.shipped = append(r.shipped, m)
+	return nil
+}
+
+func TestAsyncReplicator(t *testing.T) {
+	ctx := context.Background()
+	runID := ulid.Make()
+
+	t.Run("Mutations for a run are shipped in order", func(t *testing.T) {
+		r := &recordingReplicator{fails: 1}
+		a := NewAsyncReplicator(r, AsyncReplicatorOpts{Backoff: time.Millisecond})
+		for _, step := range []string{"a", "b", "c"} {
+			require.NoError(t, a.Replicate(ctx, Mutation{Type: MutationStepSaved, RunID: runID, StepID: step}))
+		}
+		require.NoError(t, a.Close(ctx))
+		require.ErrorIs(t, a.Replicate(ctx, Mutation{RunID: runID}), ErrReplicatorClosed)
+
+		require.Zero(t, a.Dropped())
+		require.Len(t, r.shipped, 3)
+		for n, step := range []string{"a", "b", "c"} {
+			require.Equal(t, step, r.shipped[n].StepID)
+		}
+	})
+
+	t.Run("Mutations are dropped when the buffer is full", func(t *testing.T) {
+		r := &recordingReplicator{block: make(chan struct{})}
+		a := NewAsyncReplicator(r, AsyncReplicatorOpts{Buffer: 1, Workers: 1})
+
+		// The first mutation is held by the worker, and the second is buffered.
+		require.NoError(t, a.Replicate(ctx, Mutation{RunID: runID}))
+		require.Eventually(t, func() bool {
+			return len(a.workers[0]) == 0
+		}, time.Second, time.Millisecond)
+		require.NoError(t, a.Replicate(ctx, Mutation{RunID: runID}))
+		require.ErrorIs(t, a.Replicate(ctx, Mutation{RunID: runID}), ErrReplicationBufferFull)
+		require.EqualValues(t, 1, a.Dropped())
+
+		close(r.block)
+		require.NoError(t, a.Close(ctx))
+		require.Len(t, r.shipped, 2)
+	})
+
+	t.Run("Runs with dropped mutations are resynced", func(t *testing.T) {
+		r := &recordingReplicator{block: make(chan struct{})}
+		resynced := make(chan ulid.ULID, 1)
+		a := NewAsyncReplicator(r, AsyncReplicatorOpts{
+			Buffer:         1,
+			Workers:        1,
+			ResyncInterval: time.Hour,
+			Resync: func(ctx context.Context, runID ulid.ULID) error {
+				resynced <- runID
+				return nil
+			},
+		})
+
+		// Fill the buffer, so that the third mutation is dropped.
+		require.NoError(t, a.Replicate(ctx, Mutation{RunID: runID, StepID: "a"}))
+		require.Eventually(t, func() bool {
+			return len(a.workers[0]) == 0
+		}, time.Second, time.Millisecond)
+		require.NoError(t, a.Replicate(ctx, Mutation{RunID: runID, StepID: "b"}))
+		require.ErrorIs(t, a.Replicate(ctx, Mutation{RunID: runID, StepID: "c"}), ErrReplicationBufferFull)
+		require.EqualValues(t, 1, a.Dropped())
+
+		// Buffered mutations for the run are skipped, as the run's full state
+		// is replicated when it's resynced.
+		close(r.block)
+		require.NoError(t, a.Close(ctx))
+		require.Len(t, r.shipped, 1)
+		require.Equal(t, "a", r.shipped[0].StepID)
+		require.Equal(t, runID, <-resynced)
+		require.EqualValues(t, 1, a.Resynced())
+	})
+
+}
@@ -3,9 +3,11 @@ package state
 import (
 	"context"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/inngest/inngest/pkg/consts"
 	"github.com/inngest/inngest/pkg/enums"
 	"github.com/inngest/inngest/pkg/inngest"
 	"github.com/oklog/ulid/v2"
@@ -35,6 +37,10 @@ var (
 	ErrFunctionFailed     = fmt.Errorf("function failed")
 	ErrFunctionOverflowed = fmt.Errorf("function has too many steps")
 	ErrDuplicateResponse  = fmt.Errorf("duplicate response")
+
+	// ErrFunctionParallelism is returned when a run plans more steps at once than
+	// its parallel step limit allows.
+	ErrFunctionParallelism = fmt.Errorf("function planned too many parallel steps")
 )
 
 // Identifier represents the unique identifier for a workflow run.
@@ -77,6 +83,12 @@ type Identifier struct {
 	// allows us to use custom concurrency keys for each job when processing steps for
 	// the function, with cached expression results.
 	CustomConcurrencyKeys []CustomConcurrency `json:"cck,omitempty"`
+	// IdempotencyPeriod is how long the run's idempotency key is retained once the
+	// run finishes.  If zero, consts.FunctionIdempotencyPeriod is used.
+	IdempotencyPeriod time.Duration `json:"ip,omitempty"`
+	// TraceSampleRatio is the ratio of runs whose traces are exported, decided
+	// when the run is scheduled.  If nil, every run's trace is exported.
+	TraceSampleRatio *float64 `json:"tsr,omitempty"`
 }
 
 type CustomConcurrency struct {
@@ -94,6 +106,48 @@ type CustomConcurrency struct {
 	// NOTE: If the value is removed from the last deployed function we could also disregard
 	// this concurrency key.
 	Limit int `json:"l"`
+	// Ordered records whether runs with this key start one at a time, in order,
+	// as decided when the run was scheduled.  Unlike Limit, this is never
+	// updated from later function versions, so that runs always release the
+	// ordering locks that they were started with.
+	Ordered bool `json:"o,omitempty"`
+}
+
+// LatestConcurrencyLimits re-resolves the custom concurrency limits for the given
+// keys using the latest version of the function, matching keys by their expression
+// hash.  Keys whose expression no longer exists in the function keep the limit
+// stored when the run was scheduled.
+//
+// overrides are limits set for the function's queued work, keyed by expression
+// hash, and take precedence over the function's config.
+//
+// This returns a copy and never modifies the given keys.
+func LatestConcurrencyLimits(keys []CustomConcurrency, fn inngest.Function, overrides map[string]int) []CustomConcurrency {
+	if len(keys) == 0 {
+		return keys
+	}
+	updated := make([]CustomConcurrency, len(keys))
+	copy(updated, keys)
+	if fn.Concurrency != nil {
+		for _, c := range fn.Concurrency.Limits {
+			if !c.IsCustomLimit() || c.Hash == "" {
+				continue
+			}
+			// NOTE: This is quadratic, though concurrency keys are bounded to a low
+			// value (2-3).
+			for n := range updated {
+				if updated[n].Hash == c.Hash {
+					updated[n].Limit = c.Limit
+				}
+			}
+		}
+	}
+	for n := range updated {
+		if limit, ok := overrides[updated[n].Hash]; ok && limit > 0 {
+			updated[n].Limit = limit
+		}
+	}
+	return updated
 }
 
 // IdempotencyKey returns the unique key used to represent this single
@@ -106,6 +160,15 @@ func (i Identifier) IdempotencyKey() string {
 	return fmt.Sprintf("%s:%d:%s", i.WorkflowID, i.WorkflowVersion, key)
 }
 
+// GetIdempotencyPeriod returns how long the run's idempotency key is retained
+// once the run finishes.
+func (i Identifier) GetIdempotencyPeriod() time.Duration {
+	if i.IdempotencyPeriod <= 0 {
+		return consts.FunctionIdempotencyPeriod
+	}
+	return i.IdempotencyPeriod
+}
+
 type StepNotification struct {
 	ID      Identifier
 	Step    string
@@ -274,6 +337,79 @@ type FunctionNotifier interface {
 
 type FunctionCallback func(context.Context, Identifier, enums.RunStatus)
 
+// Lister is an optional interface that state stores can fulfil, listing every run
+// and pause held within the store.  This allows state to be migrated from one
+// store to another without abandoning in-progress runs.
+type Lister interface {
+	// RunIDs calls f with the ID of each run held within the store.
+	RunIDs(ctx context.Context, f func(runID ulid.ULID) error) error
+	// Pauses calls f with each pause held within the store.
+	Pauses(ctx context.Context, f func(p *Pause) error) error
+}
+
+// ResponseMetadataSaver is an optional interface that state stores can fulfil,
+// updating a run's metadata and saving a step's response in a single call.  This
+// reduces the round trips made when handling a step's first response.
+type ResponseMetadataSaver interface {
+	// SaveResponseWithMetadata applies the metadata update then saves the
+	// step's response, as with calling UpdateMetadata then SaveResponse.
+	SaveResponseWithMetadata(ctx context.Context, i Identifier, stepID, marshalledOutput string, md MetadataUpdate) error
+}
+
+// LazyLoader is an optional interface that state stores can fulfil, loading a
+// run's state without the run's full batch of events.  This is used for functions
+// whose SDK fetches the batch from the API when required, so that large batches
+// aren't loaded for each step.
+type LazyLoader interface {
+	// LoadLazy returns run state for the given identifier, containing only the
+	// first event of the run's batch.  The Identifier's EventIDs still lists
+	// every event in the batch.
+	LoadLazy(ctx context.Context, runID ulid.ULID) (State, error)
+}
+
+// HasLazyEvents returns whether the state contains only part of the run's batch
+// of events, ie. it was loaded via LazyLoader.
+func HasLazyEvents(s State) bool {
+	return len(s.Events()) < len(s.Identifier().EventIDs)
+}
+
+// BulkCreator is an optional interface that state stores can fulfil, creating
+// state for many runs in a single round trip.  This reduces latency when an event
+// triggers many functions.
+type BulkCreator interface {
+	// NewMany creates state for each input, as with calling New for each.  The
+	// returned states and errors correspond to each input.
+	NewMany(ctx context.Context, inputs []Input) ([]State, []error)
+}
+
+// StatusTransitioner is an optional interface that state stores can fulfil,
+// atomically setting a run's status only if the run's current status is one of
+// the given statuses.  This ensures that informational statuses, such as
+// RunStatusSleeping, never overwrite a concurrent cancellation or finish.
+type StatusTransitioner interface {
+	// TransitionStatus sets the run's status to the given status if the run's
+	// current status is one of from, returning whether the status was set.
+	TransitionStatus(ctx context.Context, i Identifier, to enums.RunStatus, from ...enums.RunStatus) (bool, error)
+}
+
+// TransitionStatus sets the run's status to the given status if the run's
+// current status is one of from, returning whether the status was set.  This
+// uses StatusTransitioner if implemented by the state store, falling back to
+// loading the run's metadata before setting the status.
+func TransitionStatus(ctx context.Context, m Manager, i Identifier, to enums.RunStatus, from ...enums.RunStatus) (bool, error) {
+	if t, ok := m.(StatusTransitioner); ok {
+		return t.TransitionStatus(ctx, i, to, from...)
+	}
+	md, err := m.Metadata(ctx, i.RunID)
+	if err != nil {
+		return false, err
+	}
+	if !slices.Contains(from, md.Status) {
+		return false, nil
+	}
+	return true, m.SetStatus(ctx, i, to)
+}
+
 // StateLoader allows loading of previously stored state based off of a given Identifier.
 type StateLoader interface {
 	// Exists checks whether the run ID exists.
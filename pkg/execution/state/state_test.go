@@ -0,0 +1,46 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/inngest/inngest/pkg/inngest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLatestConcurrencyLimits(t *testing.T) {
+	key := "event.data.user_id"
+	keys := []CustomConcurrency{
+		{Key: "f:a", Hash: "hash-a", Limit: 1},
+		{Key: "f:b", Hash: "hash-b", Limit: 2},
+	}
+
+	t.Run("It keeps stored limits without concurrency config", func(t *testing.T) {
+		require.Equal(t, keys, LatestConcurrencyLimits(keys, inngest.Function{}, nil))
+	})
+
+	t.Run("It updates limits with matching hashes", func(t *testing.T) {
+		fn := inngest.Function{
+			Concurrency: &inngest.ConcurrencyLimits{
+				Limits: []inngest.Concurrency{
+					{Limit: 100},
+					{Limit: 5, Key: &key, Hash: "hash-b"},
+					{Limit: 9, Key: &key, Hash: "hash-c"},
+				},
+			},
+		}
+		actual := LatestConcurrencyLimits(keys, fn, nil)
+		require.Equal(t, []CustomConcurrency{
+			{Key: "f:a", Hash: "hash-a", Limit: 1},
+			{Key: "f:b", Hash: "hash-b", Limit: 5},
+		}, actual)
+		// The original keys are never modified.
+		require.Equal(t, 2, keys[1].Limit)
+
+		// Overrides take precedence over the function's config.
+		actual = LatestConcurrencyLimits(keys, fn, map[string]int{"hash-a": 3, "hash-b": 7, "hash-c": 11})
+		require.Equal(t, []CustomConcurrency{
+			{Key: "f:a", Hash: "hash-a", Limit: 3},
+			{Key: "f:b", Hash: "hash-b", Limit: 7},
+		}, actual)
+	})
+}
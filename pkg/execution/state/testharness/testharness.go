@@ -138,6 +138,7 @@ func CheckState(t *testing.T, gen Generator) {
 		"PausesByID":                       checkPausesByID,
 		"Idempotency":                      checkIdempotency,
 		"SetStatus":                        checkSetStatus,
+		"TransitionStatus":                 checkTransitionStatus,
 		"Cancel":                           checkCancel,
 		"Cancel/AlreadyCompleted":          checkCancel_completed,
 		"Cancel/AlreadyCancelled":          checkCancel_cancelled,
@@ -1566,6 +1567,46 @@ func checkSetStatus(t *testing.T, m state.Manager) {
 	require.EqualValues(t, enums.RunStatusOverflowed, reloaded.Metadata().Status, "Status is not Overflowed")
 }
 
+func checkTransitionStatus(t *testing.T, m state.Manager) {
+	ctx := context.Background()
+	runID := ulid.MustNew(ulid.Now(), rand.Reader)
+	id := state.Identifier{
+		WorkflowID: w.ID,
+		RunID:      runID,
+		Key:        runID.String(),
+	}
+
+	init := state.Input{
+		Identifier:     id,
+		EventBatchData: []map[string]any{input.Map()},
+	}
+
+	s, err := m.New(ctx, init)
+	require.NoError(t, err)
+
+	ok, err := state.TransitionStatus(ctx, m, s.Identifier(), enums.RunStatusSleeping, enums.RunStatusRunning)
+	require.NoError(t, err)
+	require.False(t, ok, "Scheduled runs should not transition to Sleeping")
+
+	ok, err = state.TransitionStatus(ctx, m, s.Identifier(), enums.RunStatusSleeping, enums.RunStatusScheduled, enums.RunStatusRunning)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	reloaded, err := m.Load(ctx, s.RunID())
+	require.NoError(t, err)
+	require.EqualValues(t, enums.RunStatusSleeping, reloaded.Metadata().Status, "Status is not Sleeping")
+
+	// Waiting runs can be cancelled, and are never moved out of an ended status.
+	require.NoError(t, m.Cancel(ctx, s.Identifier()))
+	ok, err = state.TransitionStatus(ctx, m, s.Identifier(), enums.RunStatusRunning, enums.RunStatusSleeping)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	reloaded, err = m.Load(ctx, s.RunID())
+	require.NoError(t, err)
+	require.EqualValues(t, enums.RunStatusCancelled, reloaded.Metadata().Status, "Status is not Cancelled")
+}
+
 func checkCancel(t *testing.T, m state.Manager) {
 	ctx := context.Background()
 	runID := ulid.MustNew(ulid.Now(), rand.Reader)
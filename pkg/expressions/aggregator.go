@@ -11,20 +11,58 @@ import (
 	"github.com/inngest/expr"
 	"github.com/inngest/inngest/pkg/event"
 	"github.com/inngest/inngest/pkg/logger"
+	"github.com/inngest/inngest/pkg/telemetry"
 	"github.com/karlseguin/ccache/v2"
 )
 
+const (
+	pkgName = "expressions.inngest"
+
+	// DefaultEvictionTTL is the default duration for which an evicted workspace
+	// event isn't aggregated, before its tree is rebuilt.
+	DefaultEvictionTTL = 10 * time.Minute
+)
+
+// ErrEvaluatorEvicted is returned when the aggregate evaluator for a workspace event
+// was evicted for exceeding the maximum tree size.  Callers should fall back to
+// iterating and evaluating each pause individually.
+var ErrEvaluatorEvicted = fmt.Errorf("aggregate evaluator evicted")
+
 type EventEvaluable interface {
 	expr.Evaluable
 	GetEvent() *string
 	GetWorkspaceID() uuid.UUID
 }
 
+// AggregatorOpt configures an aggregator created with NewAggregator.
+type AggregatorOpt func(a *aggregator)
+
+// WithMaxTreeSize limits the number of expressions held in each workspace event's
+// aggregate tree.  Trees which grow larger are evicted, and pauses for the event
+// must be evaluated individually until the eviction TTL passes.  A size of 0
+// disables the limit.
+func WithMaxTreeSize(size int) AggregatorOpt {
+	return func(a *aggregator) {
+		a.maxTreeSize = size
+	}
+}
+
+// WithEvictionTTL sets how long workspace events are not aggregated after their
+// tree is evicted for exceeding the maximum tree size.
+func WithEvictionTTL(ttl time.Duration) AggregatorOpt {
+	return func(a *aggregator) {
+		a.evictionTTL = ttl
+	}
+}
+
+// NewAggregator returns an aggregator which holds up to size aggregate trees in
+// memory, evicting the least recently used trees once full.
 func NewAggregator(
 	ctx context.Context,
 	size int64,
 	loader EvaluableLoader,
 	log *slog.Logger,
+	opts ...AggregatorOpt,
 ) Aggregator {
 	// use the package's singleton caching parser to create a new tree parser.
 	// this uses lifted expression parsing with caching for speed.
@@ -32,17 +70,37 @@ func NewAggregator(
 	if log == nil {
 		log = logger.StdlibLogger(ctx)
 	}
-	return &aggregator{
+	a := &aggregator{
 		log:     log,
 		records: ccache.New(ccache.Configure().MaxSize(size).ItemsToPrune(uint32(size) / 4)),
+		evicted: ccache.New(ccache.Configure().MaxSize(size).ItemsToPrune(uint32(size) / 4)),
 		loader:  loader,
 		parser:  parser,
 		// use the package's exprEvaluator function as the actual logic which evaluates
 		// expressions after the aggregate evaluator does matching.
-		evaluator: exprEvaluator,
-		mapLock:   &sync.Mutex{},
-		locks:     map[string]*sync.Mutex{},
+		evaluator:   exprEvaluator,
+		mapLock:     &sync.Mutex{},
+		locks:       map[string]*sync.Mutex{},
+		evictionTTL: DefaultEvictionTTL,
+	}
+	for _, opt := range opts {
+		opt(a)
 	}
+
+	telemetry.GaugeAggregatorTreeCount(ctx, telemetry.GaugeOpt{
+		PkgName: pkgName,
+		Observer: func(ctx context.Context) (int64, error) {
+			return int64(a.records.ItemCount()), nil
+		},
+	})
+	telemetry.GaugeAggregatorTreeSize(ctx, telemetry.GaugeOpt{
+		PkgName: pkgName,
+		Observer: func(ctx context.Context) (int64, error) {
+			return int64(a.treeSize()), nil
+		},
+	})
+
+	return a
 }
 
 // EvaluableLoader loads all Evaluables from a store since the given time, invoking the given do function for each
@@ -55,6 +113,15 @@ type EvaluableLoader interface {
 	EvaluablesByID(ctx context.Context, evaluableIDs ...uuid.UUID) ([]expr.Evaluable, error)
 }
 
+// ShardedEvaluableLoader is an EvaluableLoader which shards evaluables across multiple
+// stores by workspace and event name.  When the aggregator's loader implements this
+// interface, evaluables matched for an event are loaded from the event's shard only
+// instead of querying every shard by ID.
+type ShardedEvaluableLoader interface {
+	EvaluableLoader
+	EvaluablesByEvent(ctx context.Context, workspaceID uuid.UUID, eventName string, evaluableIDs ...uuid.UUID) ([]expr.Evaluable, error)
+}
+
 // Aggregator manages a set of AggregateEvaluator instances to quickly evaluate expressions
 // for incoming events.
 //
@@ -93,6 +160,12 @@ type aggregator struct {
 	log *slog.Logger
 
 	records *ccache.Cache
+	// evicted records workspace events whose trees were evicted for exceeding
+	// maxTreeSize.
+	evicted *ccache.Cache
+
+	maxTreeSize int
+	evictionTTL time.Duration
 
 	loader    EvaluableLoader
 	parser    expr.TreeParser
@@ -139,6 +212,10 @@ func (a *aggregator) EvaluateAsyncEvent(ctx context.Context, event event.Tracked
 func (a *aggregator) LoadEventEvaluator(ctx context.Context, wsID uuid.UUID, eventName string, eventTS time.Time) (expr.AggregateEvaluator, error) {
 	key := wsID.String() + ":" + eventName
 
+	if item := a.evicted.Get(key); item != nil && !item.Expired() {
+		return nil, ErrEvaluatorEvicted
+	}
+
 	var bk *bookkeeper
 
 	val := a.records.Get(key)
@@ -146,7 +223,7 @@ func (a *aggregator) LoadEventEvaluator(ctx context.Context, wsID uuid.UUID, eve
 		bk = &bookkeeper{
 			wsID:  wsID,
 			event: eventName,
-			ae:    expr.NewAggregateEvaluator(a.parser, a.evaluator, a.loader.EvaluablesByID),
+			ae:    expr.NewAggregateEvaluator(a.parser, a.evaluator, a.evaluableLoader(wsID, eventName)),
 			// updatedAt is a zero time.
 		}
 
@@ -190,9 +267,54 @@ func (a *aggregator) LoadEventEvaluator(ctx context.Context, wsID uuid.UUID, eve
 		return bk.ae, nil
 	}
 
+	if a.maxTreeSize > 0 && bk.ae.Len() > a.maxTreeSize {
+		a.evict(ctx, key, bk)
+		return nil, ErrEvaluatorEvicted
+	}
+
 	return bk.ae, nil
 }
 
+// evict removes the given tree from memory, preventing the workspace event from
+// being aggregated until the eviction TTL passes.
+func (a *aggregator) evict(ctx context.Context, key string, bk *bookkeeper) {
+	a.records.Delete(key)
+	a.evicted.Set(key, struct{}{}, a.evictionTTL)
+
+	telemetry.IncrAggregatorEvictedCounter(ctx, telemetry.CounterOpt{PkgName: pkgName})
+	a.log.Warn(
+		"evicted aggregate evaluator",
+		"size", bk.ae.Len(),
+		"max_size", a.maxTreeSize,
+		"workspace_id", bk.wsID,
+		"event", bk.event,
+	)
+}
+
+// treeSize returns the total number of expressions held in all trees.
+func (a *aggregator) treeSize() int {
+	total := 0
+	a.records.ForEachFunc(func(key string, item *ccache.Item) bool {
+		if bk, ok := item.Value().(*bookkeeper); ok {
+			total += bk.ae.Len()
+		}
+		return true
+	})
+	return total
+}
+
+// evaluableLoader returns the function used to load matched evaluables by ID for
+// the given workspace event.
+func (a *aggregator) evaluableLoader(wsID uuid.UUID, eventName string) func(context.Context, ...uuid.UUID) ([]expr.Evaluable, error) {
+	sl, ok := a.loader.(ShardedEvaluableLoader)
+	if !ok {
+		return a.loader.EvaluablesByID
+	}
+	return func(ctx context.Context, ids ...uuid.UUID) ([]expr.Evaluable, error) {
+		return sl.EvaluablesByEvent(ctx, wsID, eventName, ids...)
+	}
+}
+
 func (a *aggregator) getBookkeeper(ctx context.Context, wsID uuid.UUID, eventName string) *bookkeeper {
 	key := wsID.String() + ":" + eventName
 	var bk *bookkeeper
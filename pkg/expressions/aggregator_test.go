@@ -0,0 +1,82 @@
+package expressions
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/inngest/expr"
+	"github.com/stretchr/testify/require"
+)
+
+type testEvaluable struct {
+	id   uuid.UUID
+	expr string
+}
+
+func (t testEvaluable) GetID() uuid.UUID          { return t.id }
+func (t testEvaluable) GetExpression() string     { return t.expr }
+func (t testEvaluable) GetEvent() *string         { return nil }
+func (t testEvaluable) GetWorkspaceID() uuid.UUID { return uuid.UUID{} }
+
+// testLoader loads the given number of evaluables for every workspace event.
+type testLoader struct {
+	count int
+}
+
+func (l *testLoader) LoadEvaluablesSince(ctx context.Context, workspaceID uuid.UUID, eventName string, since time.Time, do func(context.Context, expr.Evaluable) error) error {
+	for i := 0; i < l.count; i++ {
+		err := do(ctx, testEvaluable{
+			id:   uuid.New(),
+			expr: fmt.Sprintf("async.data.id == '%d'", i),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	// Only load evaluables once.
+	l.count = 0
+	return nil
+}
+
+func (l *testLoader) EvaluablesByID(ctx context.Context, evaluableIDs ...uuid.UUID) ([]expr.Evaluable, error) {
+	return nil, nil
+}
+
+func TestAggregatorMaxTreeSize(t *testing.T) {
+	ctx := context.Background()
+	wsID := uuid.New()
+
+	t.Run("trees within the limit are aggregated", func(t *testing.T) {
+		a := NewAggregator(ctx, 10, &testLoader{count: 5}, nil, WithMaxTreeSize(5))
+		ae, err := a.LoadEventEvaluator(ctx, wsID, "test/event", time.Now())
+		require.NoError(t, err)
+		require.Equal(t, 5, ae.Len())
+	})
+
+	t.Run("trees over the limit are evicted", func(t *testing.T) {
+		l := &testLoader{count: 6}
+		a := NewAggregator(ctx, 10, l, nil, WithMaxTreeSize(5), WithEvictionTTL(50*time.Millisecond))
+		_, err := a.LoadEventEvaluator(ctx, wsID, "test/event", time.Now())
+		require.ErrorIs(t, err, ErrEvaluatorEvicted)
+		require.Equal(t, 0, a.(*aggregator).records.ItemCount())
+
+		// Evicted events aren't reloaded until the TTL passes.
+		_, err = a.LoadEventEvaluator(ctx, wsID, "test/event", time.Now())
+		require.ErrorIs(t, err, ErrEvaluatorEvicted)
+
+		// Other events are unaffected.
+		l.count = 1
+		ae, err := a.LoadEventEvaluator(ctx, wsID, "test/other", time.Now())
+		require.NoError(t, err)
+		require.Equal(t, 1, ae.Len())
+		require.Equal(t, 1, a.(*aggregator).treeSize())
+
+		<-time.After(60 * time.Millisecond)
+		ae, err = a.LoadEventEvaluator(ctx, wsID, "test/event", time.Now())
+		require.NoError(t, err)
+		require.Equal(t, 0, ae.Len())
+	})
+}
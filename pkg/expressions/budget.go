@@ -0,0 +1,96 @@
+package expressions
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/interpreter"
+)
+
+var (
+	// ErrEvaluationTimeout is returned when evaluating an expression takes
+	// longer than its budget's timeout.
+	ErrEvaluationTimeout = errors.New("expression evaluation timed out")
+	// ErrCostLimitExceeded is returned when evaluating an expression exceeds
+	// its budget's cost limit.
+	ErrCostLimitExceeded = errors.New("expression exceeded its cost limit")
+)
+
+// interruptCheckFrequency is the number of comprehension iterations between
+// checks of an evaluation's timeout.
+const interruptCheckFrequency = 100
+
+// Budget bounds the time and work spent evaluating a single expression, so that
+// pathological expressions, eg. nested comprehensions over large lists, can't
+// stall the caller.
+type Budget struct {
+	// Timeout is the maximum time spent evaluating an expression.  The timeout
+	// is checked while iterating within comprehensions.  Zero disables the
+	// timeout.
+	Timeout time.Duration
+	// CostLimit is the maximum runtime cost of evaluating an expression, as
+	// calculated by CEL.  Each operation costs roughly one, and functions over
+	// strings and lists cost more depending on their size.  Zero disables the
+	// limit.
+	CostLimit uint64
+}
+
+type budgetKey struct{}
+
+// WithBudget returns a context which bounds the evaluation of expressions
+// evaluated with the context.
+func WithBudget(ctx context.Context, b Budget) context.Context {
+	return context.WithValue(ctx, budgetKey{}, b)
+}
+
+func budgetFromContext(ctx context.Context) Budget {
+	b, _ := ctx.Value(budgetKey{}).(Budget)
+	return b
+}
+
+// programOpts returns the program options which enforce the budget.
+func (b Budget) programOpts() []cel.ProgramOption {
+	opts := []cel.ProgramOption{}
+	if b.Timeout > 0 {
+		opts = append(opts, cel.InterruptCheckFrequency(interruptCheckFrequency))
+	}
+	if b.CostLimit > 0 {
+		opts = append(opts, cel.CostLimit(b.CostLimit))
+	}
+	return opts
+}
+
+// budgetErr returns the error for an evaluation which exceeded its budget, or
+// nil if the budget wasn't exceeded.
+func budgetErr(ctx context.Context, err error) error {
+	cancelled := interpreter.EvalCancelledError{}
+	if errors.As(err, &cancelled) && cancelled.Cause == interpreter.CostLimitExceeded {
+		return ErrCostLimitExceeded
+	}
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return ErrEvaluationTimeout
+	}
+	return nil
+}
+
+// interruptible wraps an activation, interrupting comprehensions once the
+// context is done.  This retains the PartialActivation interface, unlike
+// cel.Program's ContextEval, so that unknowns are handled as usual.
+type interruptible struct {
+	interpreter.PartialActivation
+	done <-chan struct{}
+}
+
+func (i interruptible) ResolveName(name string) (any, bool) {
+	if name == "#interrupted" {
+		select {
+		case <-i.done:
+			return true, true
+		default:
+			return false, true
+		}
+	}
+	return i.PartialActivation.ResolveName(name)
+}
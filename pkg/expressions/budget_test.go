@@ -0,0 +1,62 @@
+package expressions
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluateBudget(t *testing.T) {
+	ctx := context.Background()
+
+	items := make([]any, 1000)
+	for n := range items {
+		items[n] = n
+	}
+	data := NewData(map[string]any{
+		"async": map[string]any{"data": map[string]any{"items": items, "id": 1}},
+	})
+	// This iterates over every pair of items, costing over a million operations.
+	pathological := "async.data.items.all(a, async.data.items.all(b, a >= 0 && b >= 0))"
+
+	t.Run("It evaluates expressions within the budget", func(t *testing.T) {
+		eval, err := NewExpressionEvaluator(ctx, "async.data.id == 1")
+		require.NoError(t, err)
+
+		ctx := WithBudget(ctx, Budget{Timeout: time.Second, CostLimit: 1000})
+		val, _, err := eval.Evaluate(ctx, data)
+		require.NoError(t, err)
+		require.Equal(t, true, val)
+	})
+
+	t.Run("It stops expressions which exceed the cost limit", func(t *testing.T) {
+		eval, err := NewExpressionEvaluator(ctx, pathological)
+		require.NoError(t, err)
+
+		ctx := WithBudget(ctx, Budget{CostLimit: 10_000})
+		_, _, err = eval.Evaluate(ctx, data)
+		require.ErrorIs(t, err, ErrCostLimitExceeded)
+	})
+
+	t.Run("It stops expressions which exceed the timeout", func(t *testing.T) {
+		eval, err := NewExpressionEvaluator(ctx, pathological)
+		require.NoError(t, err)
+
+		ctx := WithBudget(ctx, Budget{Timeout: time.Millisecond})
+		start := time.Now()
+		_, _, err = eval.Evaluate(ctx, data)
+		require.ErrorIs(t, err, ErrEvaluationTimeout)
+		require.Less(t, time.Since(start), time.Second)
+	})
+
+	t.Run("It evaluates without limits by default", func(t *testing.T) {
+		eval, err := NewExpressionEvaluator(ctx, pathological)
+		require.NoError(t, err)
+
+		val, _, err := eval.Evaluate(ctx, data)
+		require.NoError(t, err)
+		require.Equal(t, true, val)
+	})
+}
@@ -72,6 +72,9 @@ func program(
 		opts = append(opts, cel.CustomDecorator(unknownDecorator(act)))
 	}
 
+	// Enforce any evaluation budget set on the context.
+	opts = append(opts, budgetFromContext(ctx).programOpts()...)
+
 	// Create the program, refusing to short circuit if a match is found.
 	//
 	// This will add all functions from functions.StandardOverloads as we
@@ -81,8 +84,14 @@ func program(
 	return &celProgram{Program: prog, timeRefs: tr}, act, err
 }
 
-func eval(program *celProgram, activation interpreter.PartialActivation) (interface{}, *time.Time, error) {
+func eval(ctx context.Context, program *celProgram, activation interpreter.PartialActivation) (interface{}, *time.Time, error) {
+	if budgetFromContext(ctx).Timeout > 0 {
+		activation = interruptible{PartialActivation: activation, done: ctx.Done()}
+	}
 	result, _, err := program.Eval(activation)
+	if exceeded := budgetErr(ctx, err); exceeded != nil {
+		return false, nil, exceeded
+	}
 	if result == nil {
 		return false, nil, ErrNoResult
 	}
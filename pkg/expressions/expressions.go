@@ -263,13 +263,19 @@ func (e *expressionEvaluator) Evaluate(ctx context.Context, data *Data) (interfa
 		})
 	}
 
+	if b := budgetFromContext(ctx); b.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, b.Timeout)
+		defer cancel()
+	}
+
 	program, act, err := program(ctx, e.ast, e.env, data, true, e.attrs)
 
 	if err != nil {
 		return nil, nil, err
 	}
 
-	return eval(program, act)
+	return eval(ctx, program, act)
 }
 
 // UsedAttributes returns the attributes used within the expression.
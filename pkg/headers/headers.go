@@ -2,6 +2,7 @@ package headers
 
 import (
 	"net/http"
+	"strings"
 )
 
 const (
@@ -15,6 +16,12 @@ const (
 	// to be, used to validate that every part of a registration is performed
 	// against the same target.
 	HeaderKeyExpectedServerKind = "X-Inngest-Expected-Server-Kind"
+	// Signs request and response bodies with the signing key, allowing the
+	// receiver to verify the sender.
+	HeaderKeySignature = "X-Inngest-Signature"
+	// Selects the environment (eg. a branch environment) that a request acts
+	// within.
+	HeaderKeyEnv = "X-Inngest-Env"
 )
 
 const (
@@ -40,3 +47,13 @@ func ContentTypeJsonResponse() func(http.Handler) http.Handler {
 		})
 	}
 }
+
+// BearerToken returns the token within the request's "Authorization: Bearer"
+// header, or an empty string if the header isn't set.
+func BearerToken(r *http.Request) string {
+	auth := r.Header.Get("Authorization")
+	if len(auth) < 7 || !strings.EqualFold(auth[:7], "bearer ") {
+		return ""
+	}
+	return strings.TrimSpace(auth[7:])
+}
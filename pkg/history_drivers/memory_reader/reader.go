@@ -115,6 +115,23 @@ func (r *reader) GetRunHistory(
 	return items, nil
 }
 
+func (r *reader) GetRunTimeline(
+	ctx context.Context,
+	accountID uuid.UUID,
+	workspaceID uuid.UUID,
+	runID ulid.ULID,
+) (*cqrs.RunTimeline, error) {
+	r.store.Mu.RLock()
+	defer r.store.Mu.RUnlock()
+
+	run, ok := r.store.Data[runID]
+	if !ok {
+		return nil, history_reader.ErrNotFound
+	}
+
+	return cqrs.NewRunTimeline(*run.Run.ToCQRS(), run.History), nil
+}
+
 func (r *reader) GetRunHistoryItemOutput(
 	ctx context.Context,
 	historyID ulid.ULID,
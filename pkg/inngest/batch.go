@@ -36,9 +36,33 @@ func NewEventBatchConfig(conf map[string]any) (*EventBatchConfig, error) {
 		config.Timeout = "60s"
 	}
 
+	if config.MaxInFlight > consts.MaxBatchesInFlight {
+		config.MaxInFlight = consts.MaxBatchesInFlight
+	}
+
+	if config.MaxTimeout != "" {
+		window, err := time.ParseDuration(config.MaxTimeout)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse max timeout: %v", err)
+		}
+		if window > consts.MaxBatchWindow {
+			config.MaxTimeout = consts.MaxBatchWindow.String()
+		}
+	}
+
 	return config, nil
 }
 
+const (
+	// BatchModeFixed runs a batch once its timeout elapses after the first
+	// event is appended.  This is the default.
+	BatchModeFixed = "fixed"
+	// BatchModeSliding extends the batch's timeout each time an event is
+	// appended, running the batch once no events are received for the timeout
+	// or once the batch's max timeout elapses.
+	BatchModeSliding = "sliding"
+)
+
 // EventBatchConfig represents how a function would expect
 // a list of events to look like for consumption
 //
@@ -54,6 +78,28 @@ type EventBatchConfig struct {
 	// Timeout is the maximum number of time the batch will
 	// wait before being consumed.
 	Timeout string `json:"timeout"`
+
+	// Mode is either "fixed" or "sliding", defaulting to fixed.  Sliding
+	// batches extend the timeout each time an event is appended.
+	Mode string `json:"mode,omitempty"`
+
+	// MaxTimeout is the maximum total time that a sliding batch waits after
+	// its first event, defaulting to consts.MaxBatchWindow.
+	MaxTimeout string `json:"maxTimeout,omitempty"`
+
+	// MaxInFlight is the number of batches that events may be appended to
+	// concurrently, defaulting to 1.  Events fill the first batch, and only
+	// append to further batches while earlier batches are full but haven't yet
+	// started, so bursts of events don't queue behind a single batch.
+	// When more than one batch is in flight events are not guaranteed to be
+	// batched or run in the order they were received.
+	MaxInFlight int `json:"maxInFlight,omitempty"`
+
+	// Lazy sends only the first event of each batch to the SDK, alongside the
+	// internal ID of every event, with the SDK fetching the batch from the API
+	// when it's used.  This is set when the function's SDK declares the
+	// lazy_batch_events capability during registration.
+	Lazy bool `json:"lazy,omitempty"`
 }
 
 func (c EventBatchConfig) IsEnabled() bool {
@@ -75,9 +121,72 @@ func (c EventBatchConfig) IsValid() error {
 		}
 	}
 
-	if _, err := time.ParseDuration(c.Timeout); err != nil {
+	timeout, err := time.ParseDuration(c.Timeout)
+	if err != nil {
 		return fmt.Errorf("invalid timeout string: %v", err)
 	}
 
+	switch c.Mode {
+	case "", BatchModeFixed, BatchModeSliding:
+	default:
+		return fmt.Errorf("invalid batch mode: %s", c.Mode)
+	}
+
+	if c.MaxInFlight < 0 || c.MaxInFlight > consts.MaxBatchesInFlight {
+		return fmt.Errorf("batch max in flight must be between 1 and %d: %d", consts.MaxBatchesInFlight, c.MaxInFlight)
+	}
+
+	if c.MaxTimeout != "" {
+		window, err := time.ParseDuration(c.MaxTimeout)
+		if err != nil {
+			return fmt.Errorf("invalid max timeout string: %v", err)
+		}
+		if window < timeout {
+			return fmt.Errorf("batch max timeout cannot be smaller than the timeout: %s", c.MaxTimeout)
+		}
+		if window > consts.MaxBatchWindow {
+			return fmt.Errorf("batch max timeout cannot be larger than %s", consts.MaxBatchWindow)
+		}
+	}
+
 	return nil
 }
+
+// InFlight returns the number of batches that events may be appended to
+// concurrently.
+func (c EventBatchConfig) InFlight() int {
+	if c.MaxInFlight <= 1 {
+		return 1
+	}
+	return min(c.MaxInFlight, consts.MaxBatchesInFlight)
+}
+
+// IsSliding returns whether each appended event extends the batch's timeout.
+func (c EventBatchConfig) IsSliding() bool {
+	return c.Mode == BatchModeSliding
+}
+
+// Deadline returns the time at which a batch whose first event was appended at
+// start should run, given that the latest event was appended at now.
+func (c EventBatchConfig) Deadline(start, now time.Time) (time.Time, error) {
+	timeout, err := time.ParseDuration(c.Timeout)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if !c.IsSliding() {
+		return start.Add(timeout), nil
+	}
+
+	window := consts.MaxBatchWindow
+	if c.MaxTimeout != "" {
+		if window, err = time.ParseDuration(c.MaxTimeout); err != nil {
+			return time.Time{}, err
+		}
+	}
+
+	at := now.Add(timeout)
+	if limit := start.Add(window); at.After(limit) {
+		return limit, nil
+	}
+	return at, nil
+}
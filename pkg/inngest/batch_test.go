@@ -3,6 +3,7 @@ package inngest
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/inngest/inngest/pkg/consts"
 	"github.com/stretchr/testify/require"
@@ -139,3 +140,26 @@ func TestEventBatchConfigIsValid(t *testing.T) {
 		})
 	}
 }
+
+func TestEventBatchDeadline(t *testing.T) {
+	start := time.Now()
+
+	fixed := EventBatchConfig{MaxSize: 10, Timeout: "10s"}
+	at, err := fixed.Deadline(start, start.Add(5*time.Second))
+	require.NoError(t, err)
+	require.Equal(t, start.Add(10*time.Second), at)
+
+	sliding := EventBatchConfig{MaxSize: 10, Timeout: "10s", Mode: BatchModeSliding, MaxTimeout: "30s"}
+	at, err = sliding.Deadline(start, start.Add(5*time.Second))
+	require.NoError(t, err)
+	require.Equal(t, start.Add(15*time.Second), at)
+
+	// The deadline is capped by the max timeout.
+	at, err = sliding.Deadline(start, start.Add(25*time.Second))
+	require.NoError(t, err)
+	require.Equal(t, start.Add(30*time.Second), at)
+
+	require.Error(t, EventBatchConfig{MaxSize: 10, Timeout: "10s", Mode: "nope"}.IsValid())
+	require.Error(t, EventBatchConfig{MaxSize: 10, Timeout: "10s", MaxTimeout: "5s"}.IsValid())
+	require.NoError(t, sliding.IsValid())
+}
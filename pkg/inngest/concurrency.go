@@ -118,12 +118,30 @@ type Concurrency struct {
 	Key   *string                `json:"key,omitempty"`
 	Scope enums.ConcurrencyScope `json:"scope"`
 	Hash  string                 `json:"hash"`
+	// Ordered runs functions sharing the same key strictly one at a time, in
+	// the order that their events were received.  Concurrency limits otherwise
+	// only limit in-progress steps, so a run that's sleeping or waiting for an
+	// event allows the next run with the same key to start.
+	//
+	// Ordered keys must have a limit of 1.  This trades throughput for ordering:
+	// each key's runs are processed serially from start to finish, including
+	// any time spent sleeping, waiting or retrying, so a slow run delays every
+	// run queued behind it.
+	Ordered bool `json:"ordered,omitempty"`
 }
 
 func (c Concurrency) Validate(ctx context.Context) error {
 	if c.Scope != enums.ConcurrencyScopeFn && c.Key == nil {
 		return fmt.Errorf("A concurrency key must be specified for %s scoped limits", c.Scope)
 	}
+	if c.Ordered {
+		if c.Key == nil {
+			return fmt.Errorf("A concurrency key must be specified for ordered limits")
+		}
+		if c.Limit != 1 {
+			return fmt.Errorf("Ordered concurrency limits must have a limit of 1")
+		}
+	}
 	if c.Key != nil {
 		if _, err := expressions.NewExpressionEvaluator(ctx, *c.Key); err != nil {
 			return fmt.Errorf("Invalid concurrency key '%s': %w", *c.Key, err)
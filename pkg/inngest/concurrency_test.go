@@ -192,3 +192,18 @@ func TestConcurrencyEvaluate(t *testing.T) {
 		})
 	}
 }
+
+func TestConcurrencyValidateOrdered(t *testing.T) {
+	ctx := context.Background()
+
+	c := ConcurrencyLimits{}
+	require.NoError(t, json.Unmarshal([]byte(`[{"key":"event.data.user_id","limit":1,"ordered":true}]`), &c))
+	require.True(t, c.Limits[0].Ordered)
+	require.NoError(t, c.Validate(ctx))
+
+	err := Concurrency{Limit: 2, Key: strptr("event.data.user_id"), Ordered: true}.Validate(ctx)
+	require.ErrorContains(t, err, "limit of 1")
+
+	err = Concurrency{Limit: 1, Ordered: true}.Validate(ctx)
+	require.ErrorContains(t, err, "concurrency key must be specified")
+}
@@ -0,0 +1,137 @@
+package inngest
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	cron "github.com/robfig/cron/v3"
+)
+
+// cronStarBit is set within a cron field when the field is "*", matching the
+// unexported bit used by the cron parser.
+const cronStarBit = 1 << 63
+
+var cronParser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow)
+
+// ParseCron parses a cron trigger's schedule.  Schedules may be prefixed with an IANA
+// timezone, eg. "TZ=America/New_York 0 9 * * *".  Schedules without a timezone run in
+// UTC.
+//
+// Schedules that run at specific hours are evaluated against the wall clock of their
+// timezone, so that DST transitions are handled as you'd expect:  runs at a time which
+// is skipped when clocks go forward happen at the transition, and runs at a time which
+// is repeated when clocks go back happen once.  Schedules running every hour are
+// evaluated in elapsed time and are unaffected by DST.
+func ParseCron(spec string) (cron.Schedule, error) {
+	spec = strings.TrimSpace(spec)
+
+	loc := time.UTC
+	if strings.HasPrefix(spec, "TZ=") || strings.HasPrefix(spec, "CRON_TZ=") {
+		tz, rest, ok := strings.Cut(spec, " ")
+		if !ok {
+			return nil, fmt.Errorf("'%s' is missing a schedule after the timezone", spec)
+		}
+		_, name, _ := strings.Cut(tz, "=")
+		var err error
+		if loc, err = time.LoadLocation(name); err != nil {
+			return nil, fmt.Errorf("'%s' isn't a valid timezone", name)
+		}
+		spec = strings.TrimSpace(rest)
+	}
+
+	parsed, err := cronParser.Parse(spec)
+	if err != nil {
+		return nil, err
+	}
+	s, ok := parsed.(*cron.SpecSchedule)
+	if !ok {
+		return parsed, nil
+	}
+
+	if loc == time.UTC || s.Hour&cronStarBit != 0 {
+		s.Location = loc
+		return s, nil
+	}
+
+	// Evaluate the schedule against the wall clock, which is represented in UTC so
+	// that every wall clock time exists exactly once.
+	s.Location = time.UTC
+	return wallClockSchedule{spec: s, loc: loc}, nil
+}
+
+// offsetSchedule delays every run of a schedule by a fixed offset.
+type offsetSchedule struct {
+	cron.Schedule
+	offset time.Duration
+}
+
+func (o offsetSchedule) Next(t time.Time) time.Time {
+	next := o.Schedule.Next(t.Add(-o.offset))
+	if next.IsZero() {
+		return next
+	}
+	return next.Add(o.offset)
+}
+
+// wallClockSchedule evaluates a cron schedule against the wall clock of a timezone.
+type wallClockSchedule struct {
+	spec *cron.SpecSchedule
+	loc  *time.Location
+}
+
+func (w wallClockSchedule) Next(t time.Time) time.Time {
+	wall := toWallClock(t, w.loc)
+	for {
+		next := w.spec.Next(wall)
+		if next.IsZero() {
+			return next
+		}
+		if at := fromWallClock(next, w.loc); at.After(t) {
+			return at
+		}
+		// The wall clock was repeated, and this time has already passed.
+		wall = next
+	}
+}
+
+// toWallClock returns the wall clock time of t within loc, represented in UTC.
+func toWallClock(t time.Time, loc *time.Location) time.Time {
+	t = t.In(loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+}
+
+// fromWallClock returns the earliest instant at which the wall clock within loc
+// reads the given wall time.  Wall times repeated by a DST transition return their
+// first occurrence, and wall times skipped by a DST transition return the transition.
+func fromWallClock(wall time.Time, loc *time.Location) time.Time {
+	// Timezones transition at most once within this window.
+	_, before := wall.Add(-24 * time.Hour).In(loc).Zone()
+	_, after := wall.Add(24 * time.Hour).In(loc).Zone()
+
+	early := wall.Add(-time.Duration(before) * time.Second)
+	late := wall.Add(-time.Duration(after) * time.Second)
+	if late.Before(early) {
+		early, late = late, early
+	}
+
+	for _, at := range []time.Time{early, late} {
+		if toWallClock(at, loc).Equal(wall) {
+			return at.In(loc)
+		}
+	}
+
+	// The wall time was skipped.  Find the transition, which lies between both
+	// candidates.
+	_, offset := early.In(loc).Zone()
+	lo, hi := early.Unix(), late.Unix()
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		if _, o := time.Unix(mid, 0).In(loc).Zone(); o == offset {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	return time.Unix(lo, 0).In(loc)
+}
@@ -0,0 +1,127 @@
+package inngest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCron(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		spec     string
+		from     time.Time
+		expected []time.Time
+	}{
+		{
+			name:     "It defaults to UTC",
+			spec:     "0 9 * * *",
+			from:     time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
+			expected: []time.Time{time.Date(2024, 1, 2, 9, 0, 0, 0, time.UTC)},
+		},
+		{
+			name: "It keeps the wall clock time across DST",
+			spec: "TZ=America/New_York 0 9 * * *",
+			from: time.Date(2024, 3, 9, 10, 0, 0, 0, ny),
+			expected: []time.Time{
+				time.Date(2024, 3, 10, 13, 0, 0, 0, time.UTC),
+				time.Date(2024, 3, 11, 13, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "It runs skipped times at the transition",
+			spec: "CRON_TZ=America/New_York 30 2 * * *",
+			from: time.Date(2024, 3, 9, 3, 0, 0, 0, ny),
+			expected: []time.Time{
+				// 2:30am doesn't exist; clocks go from 2am EST to 3am EDT.
+				time.Date(2024, 3, 10, 7, 0, 0, 0, time.UTC),
+				time.Date(2024, 3, 11, 6, 30, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "It runs repeated times once",
+			spec: "TZ=America/New_York 30 1 * * *",
+			from: time.Date(2024, 11, 3, 0, 0, 0, 0, ny),
+			expected: []time.Time{
+				time.Date(2024, 11, 3, 5, 30, 0, 0, time.UTC),
+				time.Date(2024, 11, 4, 6, 30, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "Hourly schedules run every hour across DST",
+			spec: "TZ=America/New_York 0 * * * *",
+			from: time.Date(2024, 11, 3, 0, 30, 0, 0, ny),
+			expected: []time.Time{
+				time.Date(2024, 11, 3, 5, 0, 0, 0, time.UTC),
+				time.Date(2024, 11, 3, 6, 0, 0, 0, time.UTC),
+				time.Date(2024, 11, 3, 7, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s, err := ParseCron(test.spec)
+			require.NoError(t, err)
+
+			at := test.from
+			for _, expected := range test.expected {
+				at = s.Next(at)
+				require.True(t, expected.Equal(at), "expected %s, got %s", expected, at.UTC())
+			}
+		})
+	}
+
+	t.Run("It rejects invalid timezones", func(t *testing.T) {
+		_, err := ParseCron("TZ=Nope/Nope 0 9 * * *")
+		require.Error(t, err)
+
+		_, err = ParseCron("TZ=America/New_York")
+		require.Error(t, err)
+	})
+}
+
+func TestCronTriggerJitter(t *testing.T) {
+	jitter := "10m"
+	c := CronTrigger{Cron: "0 * * * *", Jitter: &jitter}
+	require.NoError(t, c.Validate(context.Background()))
+
+	fnA, fnB := uuid.New(), uuid.New()
+
+	t.Run("Offsets are stable and within the jitter window", func(t *testing.T) {
+		for _, id := range []uuid.UUID{fnA, fnB} {
+			offset := c.JitterOffset(id)
+			require.Equal(t, offset, c.JitterOffset(id))
+			require.GreaterOrEqual(t, offset, time.Duration(0))
+			require.Less(t, offset, 10*time.Minute)
+		}
+	})
+
+	t.Run("Schedules are offset from the cron schedule", func(t *testing.T) {
+		s, err := c.Schedule(fnA)
+		require.NoError(t, err)
+
+		from := time.Date(2024, 1, 1, 10, 30, 0, 0, time.UTC)
+		offset := c.JitterOffset(fnA)
+
+		next := s.Next(from)
+		require.Equal(t, time.Date(2024, 1, 1, 11, 0, 0, 0, time.UTC).Add(offset), next)
+		require.Equal(t, time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC).Add(offset), s.Next(next))
+	})
+
+	t.Run("It rejects jitter above the max", func(t *testing.T) {
+		jitter := "2h"
+		c := CronTrigger{Cron: "0 * * * *", Jitter: &jitter}
+		require.Error(t, c.Validate(context.Background()))
+	})
+
+	t.Run("No jitter has no offset", func(t *testing.T) {
+		require.Zero(t, CronTrigger{Cron: "0 * * * *"}.JitterOffset(fnA))
+	})
+}
@@ -8,15 +8,18 @@ import (
 	"fmt"
 	"net/url"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/cespare/xxhash/v2"
 	petname "github.com/dustinkirkland/golang-petname"
 	"github.com/fatih/structs"
 	"github.com/google/uuid"
 	"github.com/gosimple/slug"
 	multierror "github.com/hashicorp/go-multierror"
 	"github.com/inngest/inngest/pkg/consts"
+	"github.com/inngest/inngest/pkg/enums"
 	"github.com/inngest/inngest/pkg/expressions"
 	"github.com/inngest/inngest/pkg/syscode"
 	"github.com/xhit/go-str2duration/v2"
@@ -55,11 +58,45 @@ type Function struct {
 	// Slug is the human-friendly ID for the function
 	Slug string `json:"slug"`
 
+	// ArchivedAt is set by the server when the function is archived.  Archived
+	// functions don't start new runs, while existing runs complete as usual.
+	ArchivedAt *time.Time `json:"archivedAt,omitempty"`
+
 	Priority *Priority `json:"priority,omitempty"`
 
 	// Timeouts represents timeouts for a function.
 	Timeouts *Timeouts `json:"timeouts,omitempty"`
 
+	// RequestTimeout is the maximum duration of each request to the function, eg. "30s".
+	// Requests which time out fail and are retried.  If unset, consts.MaxFunctionTimeout
+	// is used.
+	RequestTimeout *string `json:"requestTimeout,omitempty"`
+
+	// IdempotencyPeriod is how long a run's idempotency key (the triggering event or
+	// batch ID) prevents duplicate runs once the run finishes, eg. "48h".  If unset,
+	// the workspace's period or consts.FunctionIdempotencyPeriod is used.
+	IdempotencyPeriod *string `json:"idempotencyPeriod,omitempty"`
+
+	// Idempotency is an optional expression evaluated against the triggering event,
+	// eg. "event.data.order_id".  The result is used as the run's idempotency key,
+	// so that the function runs at most once per unique key within the function's
+	// idempotency period.
+	Idempotency *string `json:"idempotency,omitempty"`
+
+	// OnFailure is the ID (slug) of a function to run whenever a run of this function
+	// permanently fails.  The failure handler is triggered with an
+	// `inngest/function.failed` event containing the failed run's ID and error.
+	OnFailure *string `json:"onFailure,omitempty"`
+
+	// StepLimits overrides the executor's limits on the number of steps each run of
+	// the function may execute.
+	StepLimits *StepLimits `json:"stepLimits,omitempty"`
+
+	// TraceSampleRatio is the ratio of runs, between 0 and 1, whose traces are
+	// exported.  Spans which record errors are always exported.  If unset, the
+	// workspace's ratio is used.
+	TraceSampleRatio *float64 `json:"traceSampleRatio,omitempty"`
+
 	// ConcurrencyLimits allows limiting the concurrency of running functions, optionally constrained
 	// by individual concurrency keys.
 	//
@@ -84,9 +121,20 @@ type Function struct {
 	// time.
 	Throttle *Throttle `json:"throttle,omitempty"`
 
+	// ThrottleScope determines which functions share the throttle.  By default each
+	// function is throttled independently.  Env and account scoped throttles are
+	// shared by every function using the same scope and key, throttling the combined
+	// runs of functions within an environment or account.
+	ThrottleScope enums.ConcurrencyScope `json:"throttleScope,omitempty"`
+
 	// Cancel specifies cancellation signals for the function
 	Cancel []Cancel `json:"cancel,omitempty"`
 
+	// CancelTimeout is how long the function's cancellations last when they don't
+	// specify their own timeout, eg. "30d", or "never" for cancellations which never
+	// expire.  If unset, the workspace's timeout or consts.CancelTimeout is used.
+	CancelTimeout *string `json:"cancelTimeout,omitempty"`
+
 	// Actions represents the actions to take for this function.  If empty, this assumes
 	// that we have a single action specified in the current directory using
 	Steps []Step `json:"steps,omitempty"`
@@ -152,6 +200,15 @@ func (t Throttle) MarshalJSON() ([]byte, error) {
 	return json.Marshal(val)
 }
 
+// StepLimits represents limits on the steps a function's runs may execute.  Zero
+// values use the executor's limits.
+type StepLimits struct {
+	// Steps is the maximum number of steps a run may execute.
+	Steps int `json:"steps,omitempty"`
+	// Parallel is the maximum number of steps a run may plan at once.
+	Parallel int `json:"parallel,omitempty"`
+}
+
 // Timeouts represents timeouts for the function. If any of the timeouts are hit, the function
 // will be marked as cancelled with a cancellation reason.
 type Timeouts struct {
@@ -172,6 +229,32 @@ type Timeouts struct {
 	Finish time.Duration `json:"finish"`
 }
 
+// RequestTimeoutDuration returns the maximum duration of each request to the function,
+// which is never greater than consts.MaxFunctionTimeout.
+func (f Function) RequestTimeoutDuration() time.Duration {
+	if f.RequestTimeout == nil || *f.RequestTimeout == "" {
+		return consts.MaxFunctionTimeout
+	}
+	dur, err := str2duration.ParseDuration(*f.RequestTimeout)
+	if err != nil || dur <= 0 || dur > consts.MaxFunctionTimeout {
+		return consts.MaxFunctionTimeout
+	}
+	return dur
+}
+
+// IdempotencyPeriodDuration returns the function's configured idempotency period,
+// or 0 if the period is unset or invalid.
+func (f Function) IdempotencyPeriodDuration() time.Duration {
+	if f.IdempotencyPeriod == nil || *f.IdempotencyPeriod == "" {
+		return 0
+	}
+	dur, err := str2duration.ParseDuration(*f.IdempotencyPeriod)
+	if err != nil || dur <= 0 || dur > consts.MaxFunctionIdempotencyPeriod {
+		return 0
+	}
+	return dur
+}
+
 type Priority struct {
 	Run *string `json:"run"`
 }
@@ -182,6 +265,29 @@ type Debounce struct {
 	Timeout *string `json:"timeout,omitempty"`
 }
 
+// Validate returns an error if the debounce config is invalid.
+func (d Debounce) Validate(ctx context.Context) error {
+	var err error
+	if d.Key != nil && *d.Key != "" {
+		// Ensure the expression is valid if present.
+		if exprErr := expressions.Validate(ctx, *d.Key); exprErr != nil {
+			err = multierror.Append(err, fmt.Errorf("Debounce expression is invalid: %s", exprErr))
+		}
+	}
+
+	period, perr := str2duration.ParseDuration(d.Period)
+	if perr != nil {
+		err = multierror.Append(err, fmt.Errorf("The debounce period of '%s' is invalid: %w", d.Period, perr))
+	}
+	if period < consts.MinDebouncePeriod {
+		err = multierror.Append(err, fmt.Errorf("The debounce period of '%s' is less than the min of: %s", d.Period, consts.MinDebouncePeriod))
+	}
+	if period > consts.MaxDebouncePeriod {
+		err = multierror.Append(err, fmt.Errorf("The debounce period of '%s' is greater than the max of: %s", d.Period, consts.MaxDebouncePeriod))
+	}
+	return err
+}
+
 func (d Debounce) TimeoutDuration() *time.Duration {
 	if d.Timeout == nil || *d.Timeout == "" {
 		return nil
@@ -196,11 +302,45 @@ func (d Debounce) TimeoutDuration() *time.Duration {
 // will set up pauses which automatically cancel the function based off of matching
 // events and expressions.
 type Cancel struct {
-	Event   string  `json:"event"`
+	Event string `json:"event"`
+	// Timeout is how long the cancellation lasts, eg. "30d", or "never" for
+	// cancellations which never expire.
 	Timeout *string `json:"timeout,omitempty"`
 	If      *string `json:"if,omitempty"`
 }
 
+// CancelTimeoutNever is the timeout of cancellations which never expire.
+const CancelTimeoutNever = "never"
+
+// ParseCancelTimeout parses a cancellation's timeout, returning
+// consts.CancelTimeoutNever for cancellations which never expire.
+func ParseCancelTimeout(s string) (time.Duration, error) {
+	if strings.EqualFold(strings.TrimSpace(s), CancelTimeoutNever) {
+		return consts.CancelTimeoutNever, nil
+	}
+	dur, err := str2duration.ParseDuration(s)
+	if err != nil {
+		return 0, err
+	}
+	if dur <= 0 {
+		return 0, fmt.Errorf("cancellation timeout must be positive: %s", s)
+	}
+	return dur, nil
+}
+
+// CancelTimeoutDuration returns the function's default cancellation timeout, or 0
+// if the timeout is unset or invalid.
+func (f Function) CancelTimeoutDuration() time.Duration {
+	if f.CancelTimeout == nil || *f.CancelTimeout == "" {
+		return 0
+	}
+	dur, err := ParseCancelTimeout(*f.CancelTimeout)
+	if err != nil {
+		return 0
+	}
+	return dur
+}
+
 // ConcurrencyLimit returns the limit for the function itself, ie. the concurrnecy limit
 // set without keys and scoped to the function.
 func (f Function) ConcurrencyLimit() int {
@@ -227,6 +367,46 @@ func (f Function) IsScheduled() bool {
 	return false
 }
 
+// ForTrigger returns the function's config for runs started by the given trigger,
+// applying any batching or debounce overrides defined within the trigger.
+func (f Function) ForTrigger(t Trigger) Function {
+	if t.EventTrigger == nil {
+		return f
+	}
+	if t.EventTrigger.EventBatch != nil {
+		f.EventBatch = nil
+		if t.EventTrigger.EventBatch.IsEnabled() {
+			batch := *t.EventTrigger.EventBatch
+			f.EventBatch = &batch
+		}
+	}
+	if t.EventTrigger.Debounce != nil {
+		f.Debounce = nil
+		if t.EventTrigger.Debounce.Period != "" {
+			debounce := *t.EventTrigger.Debounce
+			f.Debounce = &debounce
+		}
+	}
+	return f
+}
+
+// ForTriggerIndex returns the function's config for runs started by the trigger
+// at the given index, as with ForTrigger.  Triggers are referenced by index once
+// the trigger itself isn't available, eg. once a batch starts, as several of the
+// function's triggers may match the same event.  The function's own config is
+// returned for indexes out of range.
+func (f Function) ForTriggerIndex(n int) Function {
+	if n < 0 || n >= len(f.Triggers) {
+		return f
+	}
+	return f.ForTrigger(f.Triggers[n])
+}
+
+// IsArchived returns whether the function has been archived.
+func (f Function) IsArchived() bool {
+	return f.ArchivedAt != nil && !f.ArchivedAt.After(time.Now())
+}
+
 func (f Function) IsBatchEnabled() bool {
 	if f.EventBatch == nil {
 		return false
@@ -274,10 +454,68 @@ func (f Function) Validate(ctx context.Context) error {
 		}
 	}
 
+	if f.RequestTimeout != nil && *f.RequestTimeout != "" {
+		dur, perr := str2duration.ParseDuration(*f.RequestTimeout)
+		if perr != nil {
+			err = multierror.Append(err, fmt.Errorf("The request timeout is invalid: %w", perr))
+		} else if dur <= 0 || dur > consts.MaxFunctionTimeout {
+			err = multierror.Append(err, fmt.Errorf("The request timeout must be between 0 and %s", consts.MaxFunctionTimeout))
+		}
+	}
+
+	if f.Timeouts != nil && (f.Timeouts.Start < 0 || f.Timeouts.Finish < 0) {
+		err = multierror.Append(err, fmt.Errorf("Timeouts must not be negative"))
+	}
+
+	if f.ThrottleScope != enums.ConcurrencyScopeFn && f.Throttle == nil {
+		err = multierror.Append(err, fmt.Errorf("A throttle must be specified for %s scoped throttles", f.ThrottleScope))
+	}
+
+	if f.OnFailure != nil && *f.OnFailure == f.GetSlug() {
+		err = multierror.Append(err, fmt.Errorf("A function cannot be its own failure handler"))
+	}
+
+	if f.StepLimits != nil {
+		if f.StepLimits.Steps < 0 || f.StepLimits.Steps > consts.AbsoluteMaxStepLimit {
+			err = multierror.Append(err, fmt.Errorf("The step limit must be between 0 and %d", consts.AbsoluteMaxStepLimit))
+		}
+		if f.StepLimits.Parallel < 0 || f.StepLimits.Parallel > consts.AbsoluteMaxStepLimit {
+			err = multierror.Append(err, fmt.Errorf("The parallel step limit must be between 0 and %d", consts.AbsoluteMaxStepLimit))
+		}
+	}
+
+	if f.TraceSampleRatio != nil && (*f.TraceSampleRatio < 0 || *f.TraceSampleRatio > 1) {
+		err = multierror.Append(err, fmt.Errorf("The trace sample ratio must be between 0 and 1"))
+	}
+
+	if f.Idempotency != nil {
+		if exprErr := expressions.Validate(ctx, *f.Idempotency); exprErr != nil {
+			err = multierror.Append(err, fmt.Errorf("The idempotency expression is invalid: %s", exprErr))
+		}
+		if f.EventBatch != nil {
+			err = multierror.Append(err, syscode.Error{
+				Code:    syscode.CodeComboUnsupported,
+				Message: "Batching and idempotency are mutually exclusive",
+			})
+		}
+	}
+
+	if f.IdempotencyPeriod != nil && *f.IdempotencyPeriod != "" {
+		dur, perr := str2duration.ParseDuration(*f.IdempotencyPeriod)
+		if perr != nil {
+			err = multierror.Append(err, fmt.Errorf("The idempotency period is invalid: %w", perr))
+		} else if dur <= 0 || dur > consts.MaxFunctionIdempotencyPeriod {
+			err = multierror.Append(err, fmt.Errorf("The idempotency period must be between 0 and %s", consts.MaxFunctionIdempotencyPeriod))
+		}
+	}
+
 	for _, step := range f.Steps {
 		if step.Name == "" {
 			err = multierror.Append(err, fmt.Errorf("All steps must have a name"))
 		}
+		if step.Retries != nil && (*step.Retries < 0 || *step.Retries > consts.MaxRetries) {
+			err = multierror.Append(err, fmt.Errorf("Step retries must be between 0 and %d", consts.MaxRetries))
+		}
 		uri, serr := url.Parse(step.URI)
 		if serr != nil {
 			err = multierror.Append(err, fmt.Errorf("Steps must have a valid URI"))
@@ -340,6 +578,16 @@ func (f Function) Validate(ctx context.Context) error {
 				err = multierror.Append(err, fmt.Errorf("Cancellation expression is invalid: %s", exprErr))
 			}
 		}
+		if c.Timeout != nil {
+			if _, terr := ParseCancelTimeout(*c.Timeout); terr != nil {
+				err = multierror.Append(err, fmt.Errorf("Cancellation timeout is invalid: %s", terr))
+			}
+		}
+	}
+	if f.CancelTimeout != nil && *f.CancelTimeout != "" {
+		if _, terr := ParseCancelTimeout(*f.CancelTimeout); terr != nil {
+			err = multierror.Append(err, fmt.Errorf("The cancellation timeout is invalid: %s", terr))
+		}
 	}
 
 	if len(f.Cancel) > consts.MaxCancellations {
@@ -351,22 +599,39 @@ func (f Function) Validate(ctx context.Context) error {
 			// Some clients may send an empty string.
 			f.Debounce.Key = nil
 		}
-		if f.Debounce.Key != nil {
-			// Ensure the expression is valid if present.
-			if exprErr := expressions.Validate(ctx, *f.Debounce.Key); exprErr != nil {
-				err = multierror.Append(err, fmt.Errorf("Debounce expression is invalid: %s", exprErr))
-			}
+		if derr := f.Debounce.Validate(ctx); derr != nil {
+			err = multierror.Append(err, derr)
 		}
+	}
 
-		period, perr := str2duration.ParseDuration(f.Debounce.Period)
-		if perr != nil {
-			err = multierror.Append(err, fmt.Errorf("The debounce period of '%s' is invalid: %w", f.Debounce.Period, perr))
+	// Ensure that trigger-level overrides don't combine batching with debouncing
+	// or cancellation.
+	for _, t := range f.Triggers {
+		if t.EventTrigger == nil || (t.EventTrigger.EventBatch == nil && t.EventTrigger.Debounce == nil) {
+			continue
+		}
+		resolved := f.ForTrigger(t)
+		if !resolved.IsBatchEnabled() {
+			continue
+		}
+		if resolved.Debounce != nil {
+			err = multierror.Append(err, syscode.Error{
+				Code:    syscode.CodeComboUnsupported,
+				Message: fmt.Sprintf("Batching and debouncing are mutually exclusive for trigger '%s'", t.Event),
+			})
 		}
-		if period < consts.MinDebouncePeriod {
-			err = multierror.Append(err, fmt.Errorf("The debounce period of '%s' is less than the min of: %s", f.Debounce.Period, consts.MinDebouncePeriod))
+		if f.EventBatch != nil {
+			// Function-level batching is validated above.
+			continue
+		}
+		if len(f.Cancel) > 0 {
+			err = multierror.Append(err, syscode.Error{
+				Code:    syscode.CodeComboUnsupported,
+				Message: fmt.Sprintf("Batching and cancellation are mutually exclusive for trigger '%s'", t.Event),
+			})
 		}
-		if period > consts.MaxDebouncePeriod {
-			err = multierror.Append(err, fmt.Errorf("The debounce period of '%s' is greater than the max of: %s", f.Debounce.Period, consts.MaxDebouncePeriod))
+		if f.Priority != nil && f.Priority.Run != nil {
+			err = multierror.Append(err, fmt.Errorf("A function cannot specify Priority.Run and Batch together for trigger '%s'", t.Event))
 		}
 	}
 
@@ -380,6 +645,32 @@ func (f Function) Validate(ctx context.Context) error {
 	return err
 }
 
+// IdempotencyKey evaluates the function's idempotency expression against the given
+// event, returning a key unique to the expression's result.  This returns an empty
+// key if the function has no idempotency expression.
+func (f Function) IdempotencyKey(ctx context.Context, event map[string]any) (string, error) {
+	if f.Idempotency == nil || *f.Idempotency == "" {
+		return "", nil
+	}
+
+	expr, err := expressions.NewExpressionEvaluator(ctx, *f.Idempotency)
+	if err != nil {
+		return "", fmt.Errorf("Idempotency expression is invalid: %s", err)
+	}
+
+	val, _, err := expr.Evaluate(ctx, expressions.NewData(map[string]any{"event": event}))
+	if err != nil {
+		return "", fmt.Errorf("Idempotency expression errored: %s", err)
+	}
+	if val == nil {
+		return "", fmt.Errorf("Idempotency expression returned null")
+	}
+
+	// Take a checksum of the result, so that keys are a consistent length regardless
+	// of the data the expression returns.
+	return strconv.FormatUint(xxhash.Sum64String(fmt.Sprintf("%v", val)), 36), nil
+}
+
 // RunPriorityFactor returns the run priority factor for this function, given an input event.
 func (f Function) RunPriorityFactor(ctx context.Context, event map[string]any) (int64, error) {
 	if f.Priority == nil || f.Priority.Run == nil {
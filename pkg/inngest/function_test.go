@@ -5,8 +5,10 @@ import (
 	"encoding/json"
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/inngest/inngest/pkg/consts"
+	"github.com/inngest/inngest/pkg/enums"
 	"github.com/stretchr/testify/require"
 )
 
@@ -139,6 +141,83 @@ func TestValidate(t *testing.T) {
 			require.Contains(t, err.Error(), "Invalid concurrency key")
 		})
 
+		t.Run("With itself as a failure handler", func(t *testing.T) {
+			f := Function{
+				Name:      "hi",
+				Slug:      "hi",
+				OnFailure: strptr("hi"),
+				Triggers: []Trigger{
+					{
+						EventTrigger: &EventTrigger{
+							Event: "fail",
+						},
+					},
+				},
+				Steps: []Step{
+					{
+						ID:   "step",
+						Name: "Function body",
+						URI:  "http://lol/what.xml.api",
+					},
+				},
+			}
+
+			err := f.Validate(context.Background())
+			require.NotNil(t, err)
+			require.Contains(t, err.Error(), "A function cannot be its own failure handler")
+		})
+
+		t.Run("With invalid step retries", func(t *testing.T) {
+			retries := consts.MaxRetries + 1
+			f := Function{
+				Name: "hi",
+				Triggers: []Trigger{
+					{
+						EventTrigger: &EventTrigger{
+							Event: "fail",
+						},
+					},
+				},
+				Steps: []Step{
+					{
+						ID:      "step",
+						Name:    "Function body",
+						URI:     "http://lol/what.xml.api",
+						Retries: &retries,
+					},
+				},
+			}
+
+			err := f.Validate(context.Background())
+			require.NotNil(t, err)
+			require.Contains(t, err.Error(), "Step retries must be between 0 and")
+		})
+
+		t.Run("With negative timeouts", func(t *testing.T) {
+			f := Function{
+				Name: "hi",
+				Triggers: []Trigger{
+					{
+						EventTrigger: &EventTrigger{
+							Event: "fail",
+						},
+					},
+				},
+				Timeouts: &Timeouts{Finish: -time.Second},
+				Steps: []Step{
+					{
+						ID:   "step",
+						Name: "Function body",
+						URI:  "http://lol/what.xml.api",
+					},
+				},
+			}
+
+			err := f.Validate(context.Background())
+			require.NotNil(t, err)
+			require.Contains(t, err.Error(), "Timeouts must not be negative")
+		})
+
 		t.Run("Without edges", func(t *testing.T) {
 			f := Function{
 				Name: "hi",
@@ -158,6 +237,171 @@ func TestValidate(t *testing.T) {
 	})
 }
 
+func TestForTrigger(t *testing.T) {
+	f := Function{
+		Name:     "hi",
+		Debounce: &Debounce{Period: "5s"},
+		Triggers: []Trigger{
+			{EventTrigger: &EventTrigger{Event: "user.signup"}},
+			{
+				EventTrigger: &EventTrigger{
+					Event:      "analytics.*",
+					EventBatch: &EventBatchConfig{MaxSize: 50, Timeout: "10s"},
+					Debounce:   &Debounce{},
+				},
+			},
+		},
+		Steps: []Step{
+			{
+				ID:   "step",
+				Name: "Function body",
+				URI:  "http://lol/what.xml.api",
+			},
+		},
+	}
+	require.NoError(t, f.Validate(context.Background()))
+
+	t.Run("Without overrides", func(t *testing.T) {
+		resolved := f.ForTrigger(f.Triggers[0])
+		require.False(t, resolved.IsBatchEnabled())
+		require.NotNil(t, resolved.Debounce)
+		require.Equal(t, "5s", resolved.Debounce.Period)
+	})
+
+	t.Run("With overrides", func(t *testing.T) {
+		resolved := f.ForTrigger(f.Triggers[1])
+		require.True(t, resolved.IsBatchEnabled())
+		require.Equal(t, 50, resolved.EventBatch.MaxSize)
+		require.Nil(t, resolved.Debounce)
+		// The original function is unchanged.
+		require.Nil(t, f.EventBatch)
+		require.NotNil(t, f.Debounce)
+	})
+
+	t.Run("By index", func(t *testing.T) {
+		require.False(t, f.ForTriggerIndex(0).IsBatchEnabled())
+		require.True(t, f.ForTriggerIndex(1).IsBatchEnabled())
+		// Indexes without a trigger use the function's config.
+		require.NotNil(t, f.ForTriggerIndex(2).Debounce)
+		require.NotNil(t, f.ForTriggerIndex(-1).Debounce)
+
+		// Triggers for the same event resolve to their own config.
+		same := f
+		same.Triggers = []Trigger{
+			{EventTrigger: &EventTrigger{Event: "analytics.*", Expression: strptr("event.data.a == 1")}},
+			f.Triggers[1],
+		}
+		require.False(t, same.ForTriggerIndex(0).IsBatchEnabled())
+		require.True(t, same.ForTriggerIndex(1).IsBatchEnabled())
+	})
+
+	t.Run("Batching without disabling debounce", func(t *testing.T) {
+		invalid := f
+		invalid.Triggers = []Trigger{
+			{
+				EventTrigger: &EventTrigger{
+					Event:      "analytics.*",
+					EventBatch: &EventBatchConfig{MaxSize: 50, Timeout: "10s"},
+				},
+			},
+		}
+		err := invalid.Validate(context.Background())
+		require.NotNil(t, err)
+		require.Contains(t, err.Error(), "analytics.*")
+	})
+}
+
+func TestRequestTimeout(t *testing.T) {
+	f := Function{}
+	require.Equal(t, consts.MaxFunctionTimeout, f.RequestTimeoutDuration())
+
+	f.RequestTimeout = strptr("30s")
+	require.Equal(t, 30*time.Second, f.RequestTimeoutDuration())
+
+	// Timeouts are capped to the server's max.
+	f.RequestTimeout = strptr("1000h")
+	require.Equal(t, consts.MaxFunctionTimeout, f.RequestTimeoutDuration())
+	err := f.Validate(context.Background())
+	require.NotNil(t, err)
+	require.Contains(t, err.Error(), "The request timeout must be between")
+}
+
+func TestIdempotencyPeriod(t *testing.T) {
+	f := Function{}
+	require.EqualValues(t, 0, f.IdempotencyPeriodDuration())
+
+	f.IdempotencyPeriod = strptr("2d")
+	require.Equal(t, 48*time.Hour, f.IdempotencyPeriodDuration())
+
+	f.IdempotencyPeriod = strptr("30d")
+	require.EqualValues(t, 0, f.IdempotencyPeriodDuration())
+	err := f.Validate(context.Background())
+	require.NotNil(t, err)
+	require.Contains(t, err.Error(), "The idempotency period must be between")
+}
+
+func TestCancelTimeout(t *testing.T) {
+	f := Function{}
+	require.EqualValues(t, 0, f.CancelTimeoutDuration())
+
+	f.CancelTimeout = strptr("30d")
+	require.Equal(t, 30*24*time.Hour, f.CancelTimeoutDuration())
+
+	f.CancelTimeout = strptr("Never")
+	require.Equal(t, consts.CancelTimeoutNever, f.CancelTimeoutDuration())
+
+	f.CancelTimeout = strptr("-1h")
+	require.EqualValues(t, 0, f.CancelTimeoutDuration())
+	err := f.Validate(context.Background())
+	require.NotNil(t, err)
+	require.Contains(t, err.Error(), "The cancellation timeout is invalid")
+
+	f.CancelTimeout = nil
+	f.Cancel = []Cancel{{Event: "test/cancel", Timeout: strptr("soon")}}
+	err = f.Validate(context.Background())
+	require.NotNil(t, err)
+	require.Contains(t, err.Error(), "Cancellation timeout is invalid")
+}
+
+func TestThrottleScope(t *testing.T) {
+	f := Function{}
+	require.NoError(t, json.Unmarshal([]byte(`{"throttle":{"limit":5,"period":"1m"},"throttleScope":"account"}`), &f))
+	require.Equal(t, enums.ConcurrencyScopeAccount, f.ThrottleScope)
+
+	byt, err := json.Marshal(f)
+	require.NoError(t, err)
+	decoded := Function{}
+	require.NoError(t, json.Unmarshal(byt, &decoded))
+	require.Equal(t, f.ThrottleScope, decoded.ThrottleScope)
+
+	f.Throttle = nil
+	verr := f.Validate(context.Background())
+	require.NotNil(t, verr)
+	require.Contains(t, verr.Error(), "A throttle must be specified for Account scoped throttles")
+}
+
+func TestIdempotencyKey(t *testing.T) {
+	ctx := context.Background()
+	f := Function{}
+
+	key, err := f.IdempotencyKey(ctx, map[string]any{})
+	require.NoError(t, err)
+	require.Empty(t, key)
+
+	f.Idempotency = strptr("event.data.orderId")
+	a, err := f.IdempotencyKey(ctx, map[string]any{"data": map[string]any{"orderId": "a"}})
+	require.NoError(t, err)
+	require.NotEmpty(t, a)
+
+	again, err := f.IdempotencyKey(ctx, map[string]any{"data": map[string]any{"orderId": "a", "other": 1}})
+	require.NoError(t, err)
+	require.Equal(t, a, again)
+
+	b, err := f.IdempotencyKey(ctx, map[string]any{"data": map[string]any{"orderId": "b"}})
+	require.NoError(t, err)
+	require.NotEqual(t, a, b)
+}
+
 func TestRunPriorityFactor(t *testing.T) {
 	ctx := context.Background()
 	f := Function{}
@@ -0,0 +1,58 @@
+package inngest
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/cespare/xxhash/v2"
+	"github.com/inngest/inngest/pkg/expressions"
+)
+
+// Sample runs a function for a deterministic fraction of the events matching a
+// trigger.  This allows high-volume events, eg. analytics events, to trigger
+// low-volume functions without sampling events before they're sent.
+type Sample struct {
+	// Rate is the fraction of matching events which start runs, between 0 and
+	// 1.  For example, 0.01 runs the function for 1% of matching events.
+	Rate float64 `json:"rate"`
+	// Key is an optional expression which decides whether each event is
+	// sampled, eg. "event.data.user_id".  Events with the same key are always
+	// either sampled or skipped, across every function sampling with the same
+	// key and rate.  Defaults to the event's ID.
+	Key *string `json:"key,omitempty"`
+}
+
+// Validate returns an error if the sample rate or key are invalid.
+func (s Sample) Validate(ctx context.Context) error {
+	if s.Rate <= 0 || s.Rate > 1 || math.IsNaN(s.Rate) {
+		return fmt.Errorf("The sample rate of '%v' must be greater than 0 and at most 1", s.Rate)
+	}
+	if s.Key != nil {
+		if err := expressions.Validate(ctx, *s.Key); err != nil {
+			return fmt.Errorf("The sample key is invalid: %w", err)
+		}
+	}
+	return nil
+}
+
+// Sampled returns whether the given event, as a map, is sampled and should start
+// a run.
+func (s Sample) Sampled(ctx context.Context, evt map[string]any) (bool, error) {
+	if s.Rate >= 1 {
+		return true, nil
+	}
+
+	key := fmt.Sprintf("%v", evt["id"])
+	if s.Key != nil {
+		val, _, err := expressions.Evaluate(ctx, *s.Key, map[string]any{"event": evt})
+		if err != nil {
+			return false, fmt.Errorf("error evaluating sample key: %w", err)
+		}
+		key = fmt.Sprintf("%v", val)
+	}
+
+	// Compare the key's hash as a fraction of the hash space with the rate.
+	hash := xxhash.Sum64String(key)
+	return float64(hash) < s.Rate*float64(math.MaxUint64), nil
+}
@@ -0,0 +1,60 @@
+package inngest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSampleValidate(t *testing.T) {
+	ctx := context.Background()
+	key := "event.data.user_id"
+	invalid := "event.data.user_id +"
+
+	require.NoError(t, Sample{Rate: 0.01}.Validate(ctx))
+	require.NoError(t, Sample{Rate: 1, Key: &key}.Validate(ctx))
+	require.Error(t, Sample{Rate: 0}.Validate(ctx))
+	require.Error(t, Sample{Rate: 1.5}.Validate(ctx))
+	require.Error(t, Sample{Rate: 0.5, Key: &invalid}.Validate(ctx))
+
+	trigger := Trigger{EventTrigger: &EventTrigger{Event: "test/event", Sample: &Sample{Rate: -1}}}
+	require.Error(t, trigger.Validate(ctx))
+}
+
+func TestSampleSampled(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("It samples roughly the given rate of events", func(t *testing.T) {
+		s := Sample{Rate: 0.1}
+		sampled := 0
+		for i := 0; i < 10_000; i++ {
+			ok, err := s.Sampled(ctx, map[string]any{"id": fmt.Sprintf("evt-%d", i)})
+			require.NoError(t, err)
+			if ok {
+				sampled++
+			}
+		}
+		require.InDelta(t, 1_000, sampled, 150)
+	})
+
+	t.Run("It samples events with the same key consistently", func(t *testing.T) {
+		key := "event.data.user_id"
+		s := Sample{Rate: 0.5, Key: &key}
+		for i := 0; i < 100; i++ {
+			user := fmt.Sprintf("user-%d", i)
+			first, err := s.Sampled(ctx, map[string]any{"id": "a", "data": map[string]any{"user_id": user}})
+			require.NoError(t, err)
+			second, err := s.Sampled(ctx, map[string]any{"id": "b", "data": map[string]any{"user_id": user}})
+			require.NoError(t, err)
+			require.Equal(t, first, second)
+		}
+	})
+
+	t.Run("It samples every event at a rate of 1", func(t *testing.T) {
+		ok, err := Sample{Rate: 1}.Sampled(ctx, map[string]any{"id": "a"})
+		require.NoError(t, err)
+		require.True(t, ok)
+	})
+}
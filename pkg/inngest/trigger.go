@@ -5,12 +5,15 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/cespare/xxhash/v2"
+	"github.com/google/uuid"
 	"github.com/hashicorp/go-multierror"
 	"github.com/inngest/inngest/pkg/consts"
 	"github.com/inngest/inngest/pkg/expressions"
 	cron "github.com/robfig/cron/v3"
+	"github.com/xhit/go-str2duration/v2"
 )
 
 // Triggerable represents a single or multiple triggers for a function.
@@ -114,6 +117,23 @@ type EventTrigger struct {
 	// Expression is an optional expression which must evaluate to true for the function
 	// to run.
 	Expression *string `json:"expression,omitempty"`
+
+	// EventBatch optionally overrides the function's batching config for events
+	// matching this trigger.  A max size below 2 disables batching for the trigger.
+	//
+	// Batches are scoped to the function and are shared with other triggers.
+	EventBatch *EventBatchConfig `json:"batchEvents,omitempty"`
+
+	// Debounce optionally overrides the function's debounce config for events
+	// matching this trigger.  An empty period disables debouncing for the trigger.
+	//
+	// Debounces are scoped to the function and debounce key, and are shared with
+	// other triggers.
+	Debounce *Debounce `json:"debounce,omitempty"`
+
+	// Sample optionally runs the function for a fraction of the events matching
+	// this trigger.
+	Sample *Sample `json:"sample,omitempty"`
 }
 
 func (e EventTrigger) TitleName() string {
@@ -143,20 +163,86 @@ func (e EventTrigger) Validate(ctx context.Context) error {
 			return fmt.Errorf("invalid trigger expression on '%s': %w", e.Event, err)
 		}
 	}
+	if e.EventBatch != nil && e.EventBatch.IsEnabled() {
+		if err := e.EventBatch.IsValid(); err != nil {
+			return fmt.Errorf("invalid batch config on '%s': %w", e.Event, err)
+		}
+	}
+	if e.Debounce != nil && e.Debounce.Period != "" {
+		if err := e.Debounce.Validate(ctx); err != nil {
+			return fmt.Errorf("invalid debounce config on '%s': %w", e.Event, err)
+		}
+	}
+	if e.Sample != nil {
+		if err := e.Sample.Validate(ctx); err != nil {
+			return fmt.Errorf("invalid sample config on '%s': %w", e.Event, err)
+		}
+	}
 	return nil
 }
 
-// CronTrigger is a trigger which invokes the function on a CRON schedule.
+// CronTrigger is a trigger which invokes the function on a CRON schedule.  The
+// schedule may be prefixed with an IANA timezone, eg. "TZ=Europe/Paris 0 9 * * *",
+// and runs in UTC otherwise.
 type CronTrigger struct {
 	Cron string `json:"cron"`
+	// Jitter optionally delays each scheduled run by a stable, per-function offset
+	// within the given duration, eg. "5m".  This spreads out functions sharing the
+	// same schedule so that they don't all run at the same instant.
+	Jitter *string `json:"jitter,omitempty"`
 }
 
 func (c CronTrigger) Validate(ctx context.Context) error {
-	_, err := cron.
-		NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow).
-		Parse(c.Cron)
-	if err != nil {
-		return fmt.Errorf("'%s' isn't a valid cron schedule", c.Cron)
+	if _, err := ParseCron(c.Cron); err != nil {
+		return fmt.Errorf("'%s' isn't a valid cron schedule: %w", c.Cron, err)
+	}
+	if c.Jitter != nil {
+		jitter, err := str2duration.ParseDuration(*c.Jitter)
+		if err != nil {
+			return fmt.Errorf("The cron jitter of '%s' is invalid: %w", *c.Jitter, err)
+		}
+		if jitter < 0 {
+			return fmt.Errorf("The cron jitter of '%s' must not be negative", *c.Jitter)
+		}
+		if jitter > consts.MaxCronJitter {
+			return fmt.Errorf("The cron jitter of '%s' is greater than the max of: %s", *c.Jitter, consts.MaxCronJitter)
+		}
 	}
 	return nil
 }
+
+// JitterDuration returns the jitter window for the cron trigger, or 0 if no jitter
+// is configured.
+func (c CronTrigger) JitterDuration() time.Duration {
+	if c.Jitter == nil || *c.Jitter == "" {
+		return 0
+	}
+	if dur, err := str2duration.ParseDuration(*c.Jitter); err == nil && dur > 0 {
+		return dur
+	}
+	return 0
+}
+
+// JitterOffset returns the offset applied to each run of the cron trigger for the
+// given function.  This is stable for the function and schedule and lies within
+// the jitter window, with second granularity.
+func (c CronTrigger) JitterOffset(fnID uuid.UUID) time.Duration {
+	window := int64(c.JitterDuration() / time.Second)
+	if window <= 0 {
+		return 0
+	}
+	hash := xxhash.Sum64String(fnID.String() + ":" + c.Cron)
+	return time.Duration(hash%uint64(window)) * time.Second
+}
+
+// Schedule returns the cron schedule for the given function, including any jitter.
+func (c CronTrigger) Schedule(fnID uuid.UUID) (cron.Schedule, error) {
+	s, err := ParseCron(c.Cron)
+	if err != nil {
+		return nil, err
+	}
+	if offset := c.JitterOffset(fnID); offset > 0 {
+		return offsetSchedule{Schedule: s, offset: offset}, nil
+	}
+	return s, nil
+}
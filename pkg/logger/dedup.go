@@ -0,0 +1,344 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+var (
+	// DefaultDedupFields are the fields which key repeated logs alongside each
+	// log's level and message, such that logs for different functions are
+	// counted separately.
+	DefaultDedupFields = []string{"function_id", "workflow_id", "function"}
+
+	dedup     *Deduplicator
+	dedupLock sync.RWMutex
+)
+
+// DedupOpts configures the suppression of repeated logs.
+type DedupOpts struct {
+	// Window is the period that repeated logs are counted within.  Once the
+	// window ends, a summary of suppressed logs is written.
+	Window time.Duration
+	// Limits is the number of logs with the same key written within each
+	// window, for each level.  Levels without a limit are never suppressed.
+	Limits map[zerolog.Level]int
+	// Fields are the fields which key each log alongside its level and message,
+	// defaulting to DefaultDedupFields.
+	Fields []string
+}
+
+// SetDedup suppresses repeated logs written by loggers created after this call,
+// replacing any previous deduplication.  Passing no limits disables
+// deduplication.
+func SetDedup(opts DedupOpts) {
+	dedupLock.Lock()
+	defer dedupLock.Unlock()
+	if dedup != nil {
+		dedup.Close()
+		dedup = nil
+	}
+	if len(opts.Limits) > 0 && opts.Window > 0 {
+		dedup = NewDeduplicator(opts)
+	}
+}
+
+// output returns the writer for new loggers, deduplicating logs if enabled.
+func output(w io.Writer) io.Writer {
+	dedupLock.RLock()
+	defer dedupLock.RUnlock()
+	if dedup == nil {
+		return w
+	}
+	return dedup.Writer(w)
+}
+
+// handler returns the handler for new stdlib loggers, deduplicating logs if
+// enabled.
+func handler(h slog.Handler) slog.Handler {
+	dedupLock.RLock()
+	defer dedupLock.RUnlock()
+	if dedup == nil {
+		return h
+	}
+	return dedup.Handler(h)
+}
+
+// NewDeduplicator returns a Deduplicator which periodically writes summaries of
+// suppressed logs until closed.
+func NewDeduplicator(opts DedupOpts) *Deduplicator {
+	if opts.Fields == nil {
+		opts.Fields = DefaultDedupFields
+	}
+	d := &Deduplicator{
+		opts:    opts,
+		entries: map[string]*dedupEntry{},
+		quit:    make(chan struct{}),
+	}
+	go d.run()
+	return d
+}
+
+// Deduplicator suppresses repeated logs, keyed by level, message and fields.
+// Logs over each level's limit within a window are dropped, and a single
+// "suppressed N similar logs" summary is written once the window ends.
+type Deduplicator struct {
+	opts DedupOpts
+
+	lock    sync.Mutex
+	entries map[string]*dedupEntry
+
+	quit chan struct{}
+	once sync.Once
+}
+
+type dedupEntry struct {
+	start      time.Time
+	count      int
+	suppressed int
+	// out writes the summary, using the writer or handler that the latest log
+	// for the key was written to.
+	out    summarizer
+	level  string
+	msg    string
+	fields map[string]any
+}
+
+// summarizer writes a summary of suppressed logs.
+type summarizer interface {
+	summarize(e *dedupEntry)
+}
+
+// Writer returns a writer which writes zerolog's JSON logs to w, suppressing
+// repeated logs.
+func (d *Deduplicator) Writer(w io.Writer) io.Writer {
+	return dedupWriter{d: d, next: w}
+}
+
+// Handler returns a slog handler which passes logs to next, suppressing
+// repeated logs.
+func (d *Deduplicator) Handler(next slog.Handler) slog.Handler {
+	return dedupHandler{d: d, next: next, fields: map[string]any{}}
+}
+
+// Close stops writing summaries, writing a summary for logs suppressed within
+// the current window.
+func (d *Deduplicator) Close() {
+	d.once.Do(func() {
+		close(d.quit)
+		d.flush(time.Time{})
+	})
+}
+
+func (d *Deduplicator) run() {
+	interval := d.opts.Window / 2
+	if interval < 100*time.Millisecond {
+		interval = 100 * time.Millisecond
+	}
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-d.quit:
+			return
+		case now := <-t.C:
+			d.flush(now)
+		}
+	}
+}
+
+// allow records a zerolog JSON log, returning whether it should be written.
+func (d *Deduplicator) allow(now time.Time, out summarizer, p []byte) bool {
+	log := map[string]any{}
+	if err := json.Unmarshal(p, &log); err != nil {
+		return true
+	}
+	level, _ := log[zerolog.LevelFieldName].(string)
+	lvl, err := zerolog.ParseLevel(level)
+	if err != nil {
+		return true
+	}
+	msg, _ := log[zerolog.MessageFieldName].(string)
+	fields := map[string]any{}
+	for _, f := range d.opts.Fields {
+		if v, ok := log[f]; ok {
+			fields[f] = v
+		}
+	}
+	return d.record(now, out, lvl, level, msg, fields)
+}
+
+// record records a log with the given level, message and dedup fields,
+// returning whether it should be written.
+func (d *Deduplicator) record(now time.Time, out summarizer, lvl zerolog.Level, level, msg string, fields map[string]any) bool {
+	limit, ok := d.opts.Limits[lvl]
+	if !ok {
+		return true
+	}
+
+	key := strings.Builder{}
+	key.WriteString(level + "\x00" + msg)
+	for _, f := range d.opts.Fields {
+		if v, ok := fields[f]; ok {
+			key.WriteString(fmt.Sprintf("\x00%s=%v", f, v))
+		}
+	}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	e, ok := d.entries[key.String()]
+	if !ok {
+		e = &dedupEntry{start: now, level: level, msg: msg, fields: fields}
+		d.entries[key.String()] = e
+	}
+	e.out = out
+	if e.count < limit {
+		e.count++
+		return true
+	}
+	e.suppressed++
+	return false
+}
+
+// flush writes summaries for windows which ended before now, resetting their
+// counts.  A zero now flushes every window.
+func (d *Deduplicator) flush(now time.Time) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	for key, e := range d.entries {
+		if !now.IsZero() && now.Sub(e.start) < d.opts.Window {
+			continue
+		}
+		delete(d.entries, key)
+		if e.suppressed == 0 {
+			continue
+		}
+
+		e.out.summarize(e)
+	}
+}
+
+type dedupWriter struct {
+	d    *Deduplicator
+	next io.Writer
+}
+
+func (w dedupWriter) Write(p []byte) (int, error) {
+	if !w.d.allow(time.Now(), w, p) {
+		return len(p), nil
+	}
+	return w.next.Write(p)
+}
+
+func (w dedupWriter) summarize(e *dedupEntry) {
+	summary := map[string]any{}
+	for k, v := range e.fields {
+		summary[k] = v
+	}
+	summary[zerolog.LevelFieldName] = e.level
+	summary[zerolog.TimestampFieldName] = time.Now().Format(zerolog.TimeFieldFormat)
+	summary[zerolog.MessageFieldName] = fmt.Sprintf("suppressed %d similar logs", e.suppressed)
+	summary["suppressed"] = e.suppressed
+	summary["suppressed_message"] = e.msg
+	byt, err := json.Marshal(summary)
+	if err != nil {
+		return
+	}
+	_, _ = w.next.Write(append(byt, '\n'))
+}
+
+type dedupHandler struct {
+	d    *Deduplicator
+	next slog.Handler
+	// fields are the dedup fields added via WithAttrs.
+	fields map[string]any
+	// grouped is set once attributes are nested within a group, after which
+	// attributes are no longer top-level fields.
+	grouped bool
+}
+
+func (h dedupHandler) Enabled(ctx context.Context, l slog.Level) bool {
+	return h.next.Enabled(ctx, l)
+}
+
+func (h dedupHandler) Handle(ctx context.Context, r slog.Record) error {
+	fields := h.fields
+	if !h.grouped && r.NumAttrs() > 0 {
+		fields = h.withFields(r.Attrs)
+	}
+	if !h.d.record(time.Now(), h, zerologLevel(r.Level), r.Level.String(), r.Message, fields) {
+		return nil
+	}
+	return h.next.Handle(ctx, r)
+}
+
+func (h dedupHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	fields := h.fields
+	if !h.grouped {
+		fields = h.withFields(func(f func(slog.Attr) bool) {
+			for _, a := range attrs {
+				if !f(a) {
+					return
+				}
+			}
+		})
+	}
+	return dedupHandler{d: h.d, next: h.next.WithAttrs(attrs), fields: fields, grouped: h.grouped}
+}
+
+func (h dedupHandler) WithGroup(name string) slog.Handler {
+	return dedupHandler{d: h.d, next: h.next.WithGroup(name), fields: h.fields, grouped: true}
+}
+
+// withFields returns the handler's fields, plus any dedup fields within attrs.
+func (h dedupHandler) withFields(attrs func(func(slog.Attr) bool)) map[string]any {
+	fields := make(map[string]any, len(h.fields))
+	for k, v := range h.fields {
+		fields[k] = v
+	}
+	attrs(func(a slog.Attr) bool {
+		for _, f := range h.d.opts.Fields {
+			if a.Key == f {
+				fields[f] = a.Value.Resolve().Any()
+			}
+		}
+		return true
+	})
+	return fields
+}
+
+func (h dedupHandler) summarize(e *dedupEntry) {
+	var lvl slog.Level
+	_ = lvl.UnmarshalText([]byte(e.level))
+	r := slog.NewRecord(time.Now(), lvl, fmt.Sprintf("suppressed %d similar logs", e.suppressed), 0)
+	for k, v := range e.fields {
+		// Fields added via WithAttrs are already written by the handler.
+		if _, ok := h.fields[k]; !ok {
+			r.AddAttrs(slog.Any(k, v))
+		}
+	}
+	r.AddAttrs(slog.Int("suppressed", e.suppressed), slog.String("suppressed_message", e.msg))
+	_ = h.next.Handle(context.Background(), r)
+}
+
+// zerologLevel maps slog levels to zerolog levels, used to look up limits.
+func zerologLevel(l slog.Level) zerolog.Level {
+	switch {
+	case l < slog.LevelInfo:
+		return zerolog.DebugLevel
+	case l < slog.LevelWarn:
+		return zerolog.InfoLevel
+	case l < slog.LevelError:
+		return zerolog.WarnLevel
+	default:
+		return zerolog.ErrorLevel
+	}
+}
@@ -0,0 +1,130 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeduplicator(t *testing.T) {
+	d := NewDeduplicator(DedupOpts{
+		Window: time.Hour,
+		Limits: map[zerolog.Level]int{zerolog.WarnLevel: 2},
+	})
+	defer d.Close()
+
+	buf := &bytes.Buffer{}
+	l := zerolog.New(d.Writer(buf))
+	for i := 0; i < 5; i++ {
+		// Logs are keyed by function, ignoring other fields.
+		l.Warn().Str("workflow_id", "a").Int("n", i).Msg("error evaluating pause expression")
+		l.Warn().Str("workflow_id", "b").Int("n", i).Msg("error evaluating pause expression")
+		// Levels without limits are never suppressed.
+		l.Info().Str("workflow_id", "a").Msg("error evaluating pause expression")
+	}
+
+	lines := func() []map[string]any {
+		out := []map[string]any{}
+		for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+			log := map[string]any{}
+			require.NoError(t, json.Unmarshal([]byte(line), &log))
+			out = append(out, log)
+		}
+		return out
+	}
+	logs := lines()
+	require.Len(t, logs, 2+2+5)
+
+	// Summaries are written once the window ends.
+	buf.Reset()
+	d.flush(time.Now().Add(time.Hour))
+	logs = lines()
+	require.Len(t, logs, 2)
+	for _, log := range logs {
+		require.Equal(t, "warn", log["level"])
+		require.Equal(t, "suppressed 3 similar logs", log["message"])
+		require.Equal(t, "error evaluating pause expression", log["suppressed_message"])
+		require.EqualValues(t, 3, log["suppressed"])
+		require.Contains(t, []any{"a", "b"}, log["workflow_id"])
+	}
+
+	// Counts reset for the next window.
+	buf.Reset()
+	l.Warn().Str("workflow_id", "a").Msg("error evaluating pause expression")
+	require.Len(t, lines(), 1)
+}
+
+func TestDeduplicatorHandler(t *testing.T) {
+	d := NewDeduplicator(DedupOpts{
+		Window: time.Hour,
+		Limits: map[zerolog.Level]int{zerolog.WarnLevel: 2},
+	})
+	defer d.Close()
+
+	buf := &bytes.Buffer{}
+	l := slog.New(d.Handler(slog.NewJSONHandler(buf, nil)))
+	fnA := l.With("function_id", "a")
+	for i := 0; i < 5; i++ {
+		// Fields added via With key logs, as do fields on each log.
+		fnA.Warn("error evaluating pause expression", "n", i)
+		l.Warn("error evaluating pause expression", "function_id", "b", "n", i)
+		l.Info("error evaluating pause expression", "function_id", "a")
+	}
+
+	lines := func() []map[string]any {
+		out := []map[string]any{}
+		for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+			log := map[string]any{}
+			require.NoError(t, json.Unmarshal([]byte(line), &log))
+			out = append(out, log)
+		}
+		return out
+	}
+	require.Len(t, lines(), 2+2+5)
+
+	buf.Reset()
+	d.flush(time.Now().Add(time.Hour))
+	logs := lines()
+	require.Len(t, logs, 2)
+	for _, log := range logs {
+		require.Equal(t, "WARN", log["level"])
+		require.Equal(t, "suppressed 3 similar logs", log["msg"])
+		require.Equal(t, "error evaluating pause expression", log["suppressed_message"])
+		require.EqualValues(t, 3, log["suppressed"])
+		require.Contains(t, []any{"a", "b"}, log["function_id"])
+	}
+}
+
+func TestSetDedup(t *testing.T) {
+	defer SetDedup(DedupOpts{})
+
+	// Loggers may be created while deduplication is configured.
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			SetDedup(DedupOpts{Window: time.Hour, Limits: map[zerolog.Level]int{zerolog.WarnLevel: 1}})
+		}()
+		go func() {
+			defer wg.Done()
+			_ = StdlibLogger(context.Background())
+			_ = New(zerolog.InfoLevel)
+		}()
+	}
+	wg.Wait()
+
+	_, ok := handler(slog.NewJSONHandler(&bytes.Buffer{}, nil)).(dedupHandler)
+	require.True(t, ok)
+	SetDedup(DedupOpts{})
+	_, ok = handler(slog.NewJSONHandler(&bytes.Buffer{}, nil)).(dedupHandler)
+	require.False(t, ok)
+}
@@ -20,9 +20,9 @@ type stdlibKey struct{}
 func StdlibLogger(ctx context.Context) *slog.Logger {
 	logger := ctx.Value(stdlibCtxKey)
 	if logger == nil {
-		return slog.New(slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{
+		return slog.New(handler(slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{
 			Level: StdlibLevel(),
-		}))
+		})))
 	}
 	return logger.(*slog.Logger)
 }
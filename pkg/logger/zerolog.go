@@ -57,12 +57,12 @@ func From(ctx context.Context) *zerolog.Logger {
 // New returns a new logger set to the given level, with no associated context
 // embedded.
 func New(lvl zerolog.Level) *zerolog.Logger {
-	l := zerolog.New(os.Stderr).Level(lvl).With().Timestamp().Logger()
+	l := zerolog.New(output(os.Stderr)).Level(lvl).With().Timestamp().Logger()
 
 	if !viper.GetBool("json") && logFormat != "json" {
-		l = l.Output(zerolog.ConsoleWriter{
+		l = l.Output(output(zerolog.ConsoleWriter{
 			Out: os.Stderr,
-		})
+		}))
 	}
 
 	return &l
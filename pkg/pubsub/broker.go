@@ -15,6 +15,7 @@ import (
 	_ "gocloud.dev/pubsub/gcppubsub"
 	_ "gocloud.dev/pubsub/mempubsub"
 	_ "gocloud.dev/pubsub/natspubsub"
+	"golang.org/x/sync/errgroup"
 	"golang.org/x/sync/semaphore"
 )
 
@@ -82,6 +83,40 @@ func (b *broker) Publish(ctx context.Context, topic string, m Message) error {
 	return nil
 }
 
+// PublishBatch publishes many events on the given topic.  Messages are sent
+// concurrently, allowing the backing implementation to batch sends.
+func (b *broker) PublishBatch(ctx context.Context, topic string, msgs []Message) error {
+	t, err := b.openPublishTopic(ctx, topic)
+	if err != nil {
+		return err
+	}
+
+	eg := errgroup.Group{}
+	for _, m := range msgs {
+		body, err := m.Encode()
+		if err != nil {
+			return fmt.Errorf("error encoding message: %w", err)
+		}
+		wrapped := &pubsub.Message{
+			Body: body,
+			Metadata: map[string]string{
+				"name":    m.Name,
+				"version": fmt.Sprintf("%d", m.Version),
+			},
+		}
+		eg.Go(func() error {
+			if err := t.Send(ctx, wrapped); err != nil {
+				return fmt.Errorf("error publishing event: %w", err)
+			}
+			return nil
+		})
+	}
+
+	log.From(ctx).Debug().Int("len", len(msgs)).Str("topic", topic).Msg("published event batch")
+
+	return eg.Wait()
+}
+
 // Subscribe subscribes to a topic, invoking the given run function consecutively
 // in a single threaded manner each time an event is received.
 func (b *broker) Subscribe(ctx context.Context, topic string, run PerformFunc) error {
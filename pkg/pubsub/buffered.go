@@ -0,0 +1,122 @@
+package pubsub
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// DefaultFlushInterval is the default maximum time messages are buffered by a
+// buffered publisher.
+const DefaultFlushInterval = 50 * time.Millisecond
+
+// BatchPublisher publishes many messages to a topic at once.
+type BatchPublisher interface {
+	PublishBatch(ctx context.Context, topic string, msgs []Message) error
+}
+
+// PublishBatch publishes all messages to the given topic, using the publisher's
+// PublishBatch method if it's a BatchPublisher.
+func PublishBatch(ctx context.Context, pb Publisher, topic string, msgs []Message) error {
+	if bp, ok := pb.(BatchPublisher); ok {
+		return bp.PublishBatch(ctx, topic, msgs)
+	}
+	eg := errgroup.Group{}
+	for _, m := range msgs {
+		m := m
+		eg.Go(func() error { return pb.Publish(ctx, topic, m) })
+	}
+	return eg.Wait()
+}
+
+// NewBufferedPublisher returns a publisher which buffers messages for each topic,
+// publishing them as a single batch once size messages are buffered or after the
+// given interval, whichever comes first.
+//
+// Publish blocks until the message's batch is published, returning the batch's
+// error.  This allows many concurrent callers to share a single publish call.
+func NewBufferedPublisher(pb Publisher, size int, interval time.Duration) Publisher {
+	if interval <= 0 {
+		interval = DefaultFlushInterval
+	}
+	return &bufferedPublisher{
+		pb:       pb,
+		size:     size,
+		interval: interval,
+		buffers:  map[string]*buffer{},
+	}
+}
+
+type bufferedPublisher struct {
+	pb       Publisher
+	size     int
+	interval time.Duration
+
+	l       sync.Mutex
+	buffers map[string]*buffer
+}
+
+// buffer holds messages for a single topic until they're published.
+type buffer struct {
+	// ctx is the context used to publish the batch, taken from the first
+	// message's caller.
+	ctx   context.Context
+	msgs  []Message
+	timer *time.Timer
+
+	// done is closed once the batch is published, after setting err.
+	done chan struct{}
+	err  error
+}
+
+func (b *bufferedPublisher) Publish(ctx context.Context, topic string, m Message) error {
+	b.l.Lock()
+	buf, ok := b.buffers[topic]
+	if !ok {
+		buf = &buffer{
+			ctx:  context.WithoutCancel(ctx),
+			done: make(chan struct{}),
+		}
+		buf.timer = time.AfterFunc(b.interval, func() { b.flush(topic, buf) })
+		b.buffers[topic] = buf
+	}
+	buf.msgs = append(buf.msgs, m)
+	full := len(buf.msgs) >= b.size
+	if full {
+		delete(b.buffers, topic)
+	}
+	b.l.Unlock()
+
+	if full {
+		buf.timer.Stop()
+		b.publish(topic, buf)
+	}
+
+	select {
+	case <-buf.done:
+		return buf.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// flush publishes the given buffer once its interval elapses, unless it was
+// already published after filling up.
+func (b *bufferedPublisher) flush(topic string, buf *buffer) {
+	b.l.Lock()
+	if b.buffers[topic] != buf {
+		b.l.Unlock()
+		return
+	}
+	delete(b.buffers, topic)
+	b.l.Unlock()
+
+	b.publish(topic, buf)
+}
+
+func (b *bufferedPublisher) publish(topic string, buf *buffer) {
+	buf.err = PublishBatch(buf.ctx, b.pb, topic, buf.msgs)
+	close(buf.done)
+}
@@ -0,0 +1,73 @@
+package pubsub
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type recordingPublisher struct {
+	l       sync.Mutex
+	batches [][]Message
+	err     error
+}
+
+func (r *recordingPublisher) Publish(ctx context.Context, topic string, m Message) error {
+	return r.PublishBatch(ctx, topic, []Message{m})
+}
+
+func (r *recordingPublisher) PublishBatch(ctx context.Context, topic string, msgs []Message) error {
+	r.l.Lock()
+	defer r.l.Unlock()
+	r.batches = append(r.batches, msgs)
+	return r.err
+}
+
+func TestBufferedPublisher(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("It publishes once the buffer is full", func(t *testing.T) {
+		rp := &recordingPublisher{}
+		pb := NewBufferedPublisher(rp, 10, time.Hour)
+
+		wg := sync.WaitGroup{}
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				require.NoError(t, pb.Publish(ctx, "topic", Message{Name: fmt.Sprintf("%d", i)}))
+			}(i)
+		}
+		wg.Wait()
+
+		require.Len(t, rp.batches, 2)
+		require.Len(t, rp.batches[0], 10)
+		require.Len(t, rp.batches[1], 10)
+	})
+
+	t.Run("It publishes after the interval", func(t *testing.T) {
+		rp := &recordingPublisher{}
+		pb := NewBufferedPublisher(rp, 10, 10*time.Millisecond)
+
+		start := time.Now()
+		require.NoError(t, pb.Publish(ctx, "topic", Message{}))
+		require.GreaterOrEqual(t, time.Since(start), 10*time.Millisecond)
+		require.Len(t, rp.batches, 1)
+	})
+
+	t.Run("It returns the batch's error", func(t *testing.T) {
+		rp := &recordingPublisher{err: fmt.Errorf("nope")}
+		pb := NewBufferedPublisher(rp, 2, time.Hour)
+
+		errs := make(chan error, 2)
+		for i := 0; i < 2; i++ {
+			go func() { errs <- pb.Publish(ctx, "topic", Message{}) }()
+		}
+		require.EqualError(t, <-errs, "nope")
+		require.EqualError(t, <-errs, "nope")
+	})
+}
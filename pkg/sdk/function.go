@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/inngest/inngest/pkg/consts"
+	"github.com/inngest/inngest/pkg/enums"
 	"github.com/inngest/inngest/pkg/inngest"
 )
 
@@ -34,8 +35,8 @@ type SDKFunction struct {
 	//
 	//  `event.data.order_id`.
 	//
-	// When specified, a function will run at most once per 24 hours for the given unique
-	// key.
+	// When specified, a function will run at most once per idempotency period (24 hours by
+	// default) for the given unique key.
 	Idempotency *string `json:"idempotency,omitempty"`
 
 	// RateLimit allows specifying custom rate limiting for the function.
@@ -46,6 +47,10 @@ type SDKFunction struct {
 	// time.
 	Throttle *inngest.Throttle `json:"throttle,omitempty"`
 
+	// ThrottleScope determines which functions share the throttle, defaulting to
+	// the function.
+	ThrottleScope enums.ConcurrencyScope `json:"throttleScope,omitempty"`
+
 	// Retries allows specifying the number of retries to attempt across all steps in the
 	// function.
 	Retries *int `json:"retries,omitempty"`
@@ -54,9 +59,30 @@ type SDKFunction struct {
 
 	Timeouts *inngest.Timeouts `json:"timeouts,omitempty"`
 
+	// RequestTimeout is the maximum duration of each request to the function, eg. "30s".
+	RequestTimeout *string `json:"requestTimeout,omitempty"`
+
+	// IdempotencyPeriod is how long the triggering event or batch ID prevents
+	// duplicate runs once a run finishes, eg. "48h".
+	IdempotencyPeriod *string `json:"idempotencyPeriod,omitempty"`
+
+	// OnFailure is the ID of a function to run whenever a run of this function
+	// permanently fails.
+	OnFailure *string `json:"onFailure,omitempty"`
+
+	// StepLimits overrides the limits on the number of steps each run may execute.
+	StepLimits *inngest.StepLimits `json:"stepLimits,omitempty"`
+
+	// TraceSampleRatio is the ratio of runs, between 0 and 1, whose traces are exported.
+	TraceSampleRatio *float64 `json:"traceSampleRatio,omitempty"`
+
 	// Cancel specifies cancellation signals for the function
 	Cancel []inngest.Cancel `json:"cancel,omitempty"`
 
+	// CancelTimeout is how long the function's cancellations last when they don't
+	// specify their own timeout, eg. "30d" or "never".
+	CancelTimeout *string `json:"cancelTimeout,omitempty"`
+
 	Steps map[string]SDKStep `json:"steps"`
 }
 
@@ -72,6 +98,15 @@ func (s SDKFunction) Function() (*inngest.Function, error) {
 		Cancel:      s.Cancel,
 		Debounce:    s.Debounce,
 		Timeouts:    s.Timeouts,
+
+		RequestTimeout:    s.RequestTimeout,
+		IdempotencyPeriod: s.IdempotencyPeriod,
+		Idempotency:       s.Idempotency,
+		StepLimits:        s.StepLimits,
+		OnFailure:         s.OnFailure,
+		TraceSampleRatio:  s.TraceSampleRatio,
+		CancelTimeout:     s.CancelTimeout,
+		ThrottleScope:     s.ThrottleScope,
 	}
 	// Ensure we set the slug here if s.ID is nil.  This defaults to using
 	// the slugged version of the function name.
@@ -82,13 +117,6 @@ func (s SDKFunction) Function() (*inngest.Function, error) {
 		return nil, err
 	}
 	f.EventBatch = eventbatch
-	if s.Idempotency != nil {
-		f.RateLimit = &inngest.RateLimit{
-			Limit:  1,
-			Period: consts.FunctionIdempotencyPeriod.String(),
-			Key:    s.Idempotency,
-		}
-	}
 
 	for _, step := range s.Steps {
 		url, ok := step.Runtime["url"].(string)
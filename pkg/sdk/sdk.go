@@ -76,11 +76,21 @@ type RegisterRequest struct {
 	// on all calls to Inngest from the SDK, and are separate from the RegisterRequest
 	// JSON payload to have a single source of truth.
 	Headers Headers `json:"headers"`
+	// Capabilities lists the optional features supported by the SDK.
+	Capabilities Capabilities `json:"capabilities,omitempty"`
 
 	// checksum is a memoized field.
 	checksum string
 }
 
+// Capabilities lists the optional features supported by an SDK, such that
+// features are only enabled for apps whose SDK can handle them.
+type Capabilities struct {
+	// LazyBatchEvents is true if the SDK fetches a run's batch of events from
+	// the API when requests only contain the batch's first event.
+	LazyBatchEvents bool `json:"lazy_batch_events,omitempty"`
+}
+
 type Headers struct {
 	Env      string `json:"env"`
 	Platform string `json:"platform"`
@@ -142,6 +152,11 @@ func (f RegisterRequest) Parse(ctx context.Context) ([]*inngest.Function, error)
 		}
 		funcs[n] = fn
 
+		if fn.EventBatch != nil {
+			// Only load batches lazily if the SDK can fetch the batch.
+			fn.EventBatch.Lazy = f.Capabilities.LazyBatchEvents
+		}
+
 		if ferr := fn.Validate(ctx); ferr != nil {
 			err = multierror.Append(err, ferr)
 		}
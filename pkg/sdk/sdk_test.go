@@ -131,3 +131,31 @@ func TestRegisterRequestValidate(t *testing.T) {
 		})
 	}
 }
+
+func TestRegisterRequestLazyBatchEvents(t *testing.T) {
+	fn := SDKFunction{
+		Name: "batch",
+		Triggers: []inngest.Trigger{
+			{EventTrigger: &inngest.EventTrigger{Event: "my/event"}},
+		},
+		EventBatch: map[string]any{"maxSize": 10, "timeout": "5s", "lazy": true},
+		Steps: map[string]SDKStep{
+			"step": {
+				ID:      "step",
+				Name:    "step",
+				Runtime: map[string]any{"url": "https://www.example.net/api/inngest"},
+			},
+		},
+	}
+
+	// Batches are only loaded lazily if the SDK declares the capability.
+	r := RegisterRequest{Functions: []SDKFunction{fn}}
+	funcs, err := r.Parse(context.Background())
+	require.NoError(t, err)
+	require.False(t, funcs[0].EventBatch.Lazy)
+
+	r.Capabilities.LazyBatchEvents = true
+	funcs, err = r.Parse(context.Background())
+	require.NoError(t, err)
+	require.True(t, funcs[0].EventBatch.Lazy)
+}
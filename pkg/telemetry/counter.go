@@ -70,6 +70,15 @@ func IncrQueueThrottledCounter(ctx context.Context, opts CounterOpt) {
 	})
 }
 
+func IncrQueueOrderedCounter(ctx context.Context, opts CounterOpt) {
+	recordCounterMetric(ctx, 1, counterOpt{
+		Name:        opts.PkgName,
+		MetricName:  "queue_ordered_total",
+		Description: "The number of times a run has waited for a previous run with the same ordered concurrency key",
+		Attributes:  opts.Tags,
+	})
+}
+
 func IncrQueuePartitionConcurrencyLimitCounter(ctx context.Context, opts CounterOpt) {
 	recordCounterMetric(ctx, 1, counterOpt{
 		Name:        opts.PkgName,
@@ -150,3 +159,39 @@ func IncrQueueSequentialLeaseClaimsCounter(ctx context.Context, opts CounterOpt)
 		Attributes:  opts.Tags,
 	})
 }
+
+func IncrAggregatorEvictedCounter(ctx context.Context, opts CounterOpt) {
+	recordCounterMetric(ctx, 1, counterOpt{
+		Name:        opts.PkgName,
+		MetricName:  "expr_aggregator_evicted_total",
+		Description: "Total number of aggregate trees evicted for exceeding their size limit",
+		Attributes:  opts.Tags,
+	})
+}
+
+func IncrEventAPIRateLimitedCounter(ctx context.Context, opts CounterOpt) {
+	recordCounterMetric(ctx, 1, counterOpt{
+		Name:        opts.PkgName,
+		MetricName:  "event_api_rate_limited_total",
+		Description: "Total number of event API requests rejected by rate limits",
+		Attributes:  opts.Tags,
+	})
+}
+
+func IncrPauseExpressionSkippedCounter(ctx context.Context, opts CounterOpt) {
+	recordCounterMetric(ctx, 1, counterOpt{
+		Name:        opts.PkgName,
+		MetricName:  "pause_expression_skipped_total",
+		Description: "Total number of pauses skipped as their expressions exceeded the evaluation budget",
+		Attributes:  opts.Tags,
+	})
+}
+
+func IncrConcurrencyKeyCardinalityExceededCounter(ctx context.Context, opts CounterOpt) {
+	recordCounterMetric(ctx, 1, counterOpt{
+		Name:        opts.PkgName,
+		MetricName:  "concurrency_key_cardinality_exceeded_total",
+		Description: "Total number of runs scheduled for functions using more distinct custom concurrency keys than allowed",
+		Attributes:  opts.Tags,
+	})
+}
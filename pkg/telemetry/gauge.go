@@ -97,3 +97,23 @@ func GaugeQueueShardPartitionAvailableCount(ctx context.Context, opts GaugeOpt)
 		Callback:    opts.Observer,
 	})
 }
+
+func GaugeAggregatorTreeCount(ctx context.Context, opts GaugeOpt) {
+	recordGaugeMetric(ctx, gaugeOpt{
+		Name:        opts.PkgName,
+		MetricName:  "expr_aggregator_tree_count",
+		Description: "Number of aggregate trees held in memory",
+		Attributes:  opts.Tags,
+		Callback:    opts.Observer,
+	})
+}
+
+func GaugeAggregatorTreeSize(ctx context.Context, opts GaugeOpt) {
+	recordGaugeMetric(ctx, gaugeOpt{
+		Name:        opts.PkgName,
+		MetricName:  "expr_aggregator_tree_size",
+		Description: "Total number of expressions held in aggregate trees",
+		Attributes:  opts.Tags,
+		Callback:    opts.Observer,
+	})
+}
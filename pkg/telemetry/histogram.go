@@ -12,6 +12,24 @@ var (
 		600_000, 1_800_000, // < 1h
 	}
 
+	functionDurationBoundaries = []float64{
+		10, 50, 100, 200, 500, // < 1s
+		1000, 2000, 5000, 10_000, 30_000, // < 1m
+		60_000, 300_000, // < 10m
+		600_000, 1_800_000, 3_600_000, // <= 1h
+		21_600_000, 86_400_000, // <= 1d
+	}
+
+	retryBoundaries = []float64{0, 1, 2, 3, 5, 10, 20}
+
+	// in bytes
+	outputSizeBoundaries = []float64{
+		100, 1024, 10_240, 102_400, // < 100KB
+		512_000, 1_048_576, 4_194_304, // <= 4MB
+	}
+
+	cardinalityBoundaries = []float64{1, 10, 100, 1_000, 10_000, 100_000, 1_000_000}
+
 	processPartitionBoundaries = []float64{
 		5, 10, 25, 50, 100, 200, // < 1s
 		400, 600, 800, 1_000,
@@ -36,6 +54,17 @@ func HistogramQueueItemLatency(ctx context.Context, value int64, opts HistogramO
 	})
 }
 
+func HistogramQueueOrderedDelay(ctx context.Context, value int64, opts HistogramOpt) {
+	recordIntHistogramMetric(ctx, value, histogramOpt{
+		Name:        opts.PkgName,
+		MetricName:  "queue_ordered_delay_duration",
+		Description: "Distribution of how long runs with ordered concurrency keys wait to start",
+		Attributes:  opts.Tags,
+		Unit:        "ms",
+		Boundaries:  functionDurationBoundaries,
+	})
+}
+
 func HistogramProcessPartitionDration(ctx context.Context, value int64, opts HistogramOpt) {
 	recordIntHistogramMetric(ctx, value, histogramOpt{
 		Name:        opts.PkgName,
@@ -46,3 +75,56 @@ func HistogramProcessPartitionDration(ctx context.Context, value int64, opts His
 		Boundaries:  processPartitionBoundaries,
 	})
 }
+
+func HistogramStepDuration(ctx context.Context, value int64, opts HistogramOpt) {
+	recordIntHistogramMetric(ctx, value, histogramOpt{
+		Name:        opts.PkgName,
+		MetricName:  "function_step_duration",
+		Description: "Distribution of how long each step request to a function takes",
+		Attributes:  opts.Tags,
+		Unit:        "ms",
+		Boundaries:  functionDurationBoundaries,
+	})
+}
+
+func HistogramStepOutputSize(ctx context.Context, value int64, opts HistogramOpt) {
+	recordIntHistogramMetric(ctx, value, histogramOpt{
+		Name:        opts.PkgName,
+		MetricName:  "function_step_output_size",
+		Description: "Distribution of the size of step outputs",
+		Attributes:  opts.Tags,
+		Unit:        "bytes",
+		Boundaries:  outputSizeBoundaries,
+	})
+}
+
+func HistogramStepRetries(ctx context.Context, value int64, opts HistogramOpt) {
+	recordIntHistogramMetric(ctx, value, histogramOpt{
+		Name:        opts.PkgName,
+		MetricName:  "function_step_retries",
+		Description: "Distribution of how many times steps are retried before finishing",
+		Attributes:  opts.Tags,
+		Boundaries:  retryBoundaries,
+	})
+}
+
+func HistogramConcurrencyKeyCardinality(ctx context.Context, value int64, opts HistogramOpt) {
+	recordIntHistogramMetric(ctx, value, histogramOpt{
+		Name:        opts.PkgName,
+		MetricName:  "concurrency_key_cardinality",
+		Description: "Distribution of the number of distinct custom concurrency keys used by functions",
+		Attributes:  opts.Tags,
+		Boundaries:  cardinalityBoundaries,
+	})
+}
+
+func HistogramRunDuration(ctx context.Context, value int64, opts HistogramOpt) {
+	recordIntHistogramMetric(ctx, value, histogramOpt{
+		Name:        opts.PkgName,
+		MetricName:  "function_run_duration",
+		Description: "Distribution of how long function runs take to finish",
+		Attributes:  opts.Tags,
+		Unit:        "ms",
+		Boundaries:  functionDurationBoundaries,
+	})
+}
@@ -1,14 +1,26 @@
 package telemetry
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"sync"
 
+	"github.com/inngest/inngest/pkg/consts"
+	"github.com/inngest/inngest/pkg/event"
+	"go.opentelemetry.io/otel/propagation"
+	tracesdk "go.opentelemetry.io/otel/sdk/trace"
 	"go.opentelemetry.io/otel/trace"
 )
 
-const psidKey = "psid"
+const (
+	psidKey = "psid"
+
+	// traceparentKey and tracestateKey are the W3C trace context keys stored
+	// in an event's metadata.
+	traceparentKey = "traceparent"
+	tracestateKey  = "tracestate"
+)
 
 // TraceCarrier stores the data that needs to be carried through systems.
 // e.g. pubsub, queues, etc
@@ -52,3 +64,63 @@ func (tc *TraceCarrier) ParentSpanID() (*trace.SpanID, error) {
 	sid, err := trace.SpanIDFromHex(val)
 	return &sid, err
 }
+
+// InjectEventTraceContext stores the remote trace context within ctx, eg. from
+// a traceparent header, in the event's "_inngest" metadata.  Events which already
+// specify a traceparent are left as-is.  Storing the context in the event allows
+// runs to link to the caller's trace even when events are batched or debounced.
+func InjectEventTraceContext(ctx context.Context, evt *event.Event) {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() || !sc.IsRemote() {
+		return
+	}
+
+	if evt.Data == nil {
+		evt.Data = map[string]any{}
+	}
+	meta, ok := evt.Data[consts.InngestEventDataPrefix].(map[string]any)
+	if !ok {
+		if evt.Data[consts.InngestEventDataPrefix] != nil {
+			return
+		}
+		meta = map[string]any{}
+	}
+	if _, ok := meta[traceparentKey]; ok {
+		return
+	}
+
+	carrier := propagation.MapCarrier{}
+	propagation.TraceContext{}.Inject(trace.ContextWithRemoteSpanContext(context.Background(), sc), carrier)
+	for k, v := range carrier {
+		meta[k] = v
+	}
+	evt.Data[consts.InngestEventDataPrefix] = meta
+}
+
+// EventLinks returns span links to the trace contexts stored in each event's
+// "_inngest" metadata, ignoring events without a valid trace context.
+func EventLinks(evts ...event.TrackedEvent) []tracesdk.Link {
+	links := []tracesdk.Link{}
+	seen := map[string]bool{}
+	for _, evt := range evts {
+		if evt == nil {
+			continue
+		}
+		meta := evt.GetEvent().InngestMetadata()
+		if meta == nil || meta.TraceParent == "" {
+			continue
+		}
+
+		carrier := propagation.MapCarrier{traceparentKey: meta.TraceParent}
+		if meta.TraceState != "" {
+			carrier[tracestateKey] = meta.TraceState
+		}
+		sc := trace.SpanContextFromContext(propagation.TraceContext{}.Extract(context.Background(), carrier))
+		if !sc.IsValid() || seen[meta.TraceParent] {
+			continue
+		}
+		seen[meta.TraceParent] = true
+		links = append(links, tracesdk.Link{SpanContext: sc})
+	}
+	return links
+}
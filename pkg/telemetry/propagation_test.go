@@ -0,0 +1,59 @@
+package telemetry
+
+import (
+	"context"
+	"testing"
+
+	"github.com/inngest/inngest/pkg/consts"
+	"github.com/inngest/inngest/pkg/event"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestEventTraceContext(t *testing.T) {
+	traceparent := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	ctx := propagation.TraceContext{}.Extract(context.Background(), propagation.MapCarrier{
+		"traceparent": traceparent,
+	})
+
+	t.Run("injects the remote trace context", func(t *testing.T) {
+		evt := event.Event{Name: "test/a", Data: map[string]any{"foo": "bar"}}
+		InjectEventTraceContext(ctx, &evt)
+
+		meta := evt.InngestMetadata()
+		require.NotNil(t, meta)
+		require.Equal(t, traceparent, meta.TraceParent)
+
+		links := EventLinks(event.NewOSSTrackedEvent(evt))
+		require.Len(t, links, 1)
+		require.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", links[0].SpanContext.TraceID().String())
+		require.Equal(t, "00f067aa0ba902b7", links[0].SpanContext.SpanID().String())
+	})
+
+	t.Run("keeps an existing traceparent", func(t *testing.T) {
+		existing := "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"
+		evt := event.Event{Name: "test/a", Data: map[string]any{
+			consts.InngestEventDataPrefix: map[string]any{"traceparent": existing},
+		}}
+		InjectEventTraceContext(ctx, &evt)
+		require.Equal(t, existing, evt.InngestMetadata().TraceParent)
+	})
+
+	t.Run("ignores local and missing trace contexts", func(t *testing.T) {
+		evt := event.Event{Name: "test/a", Data: map[string]any{}}
+		InjectEventTraceContext(context.Background(), &evt)
+
+		local := trace.ContextWithSpanContext(context.Background(), trace.SpanContextFromContext(ctx).WithRemote(false))
+		InjectEventTraceContext(local, &evt)
+		require.Nil(t, evt.InngestMetadata())
+		require.Empty(t, EventLinks(event.NewOSSTrackedEvent(evt)))
+	})
+
+	t.Run("ignores invalid traceparents", func(t *testing.T) {
+		evt := event.Event{Name: "test/a", Data: map[string]any{
+			consts.InngestEventDataPrefix: map[string]any{"traceparent": "nope"},
+		}}
+		require.Empty(t, EventLinks(event.NewOSSTrackedEvent(evt)))
+	})
+}
@@ -0,0 +1,46 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/binary"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type sampleRatioCtxKey struct{}
+
+// WithSampleRatio stores the ratio of traces to export within the context.  Spans
+// created from the returned context are only exported if their trace is sampled,
+// or if the span records an error.  A nil ratio exports every trace.
+func WithSampleRatio(ctx context.Context, ratio *float64) context.Context {
+	if ratio == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, sampleRatioCtxKey{}, *ratio)
+}
+
+// SampleRatio returns the ratio of traces to export stored within the context,
+// defaulting to 1.
+func SampleRatio(ctx context.Context) float64 {
+	if r, ok := ctx.Value(sampleRatioCtxKey{}).(float64); ok {
+		return r
+	}
+	return 1
+}
+
+// Sampled returns whether the trace with the given ID is sampled for the given
+// ratio.  The decision only depends on the trace ID, so that every span of a
+// trace is consistently exported or dropped, regardless of which service ends
+// the span.
+func Sampled(tid trace.TraceID, ratio float64) bool {
+	if ratio >= 1 {
+		return true
+	}
+	if ratio <= 0 {
+		return false
+	}
+	// Mirrors otel's TraceIDRatioBased sampler.
+	bound := uint64(ratio * (1 << 63))
+	x := binary.BigEndian.Uint64(tid[8:16]) >> 1
+	return x < bound
+}
@@ -0,0 +1,47 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSampled(t *testing.T) {
+	ctx := context.Background()
+
+	sampled := 0
+	for i := 0; i < 10_000; i++ {
+		tid, _ := gen.NewIDs(ctx)
+		require.True(t, Sampled(tid, 1))
+		require.False(t, Sampled(tid, 0))
+		// The decision is deterministic for each trace.
+		require.Equal(t, Sampled(tid, 0.25), Sampled(tid, 0.25))
+		if Sampled(tid, 0.25) {
+			sampled++
+		}
+	}
+	require.InDelta(t, 2_500, sampled, 250)
+}
+
+func TestSpanSampled(t *testing.T) {
+	ctx := context.Background()
+	require.EqualValues(t, 1, SampleRatio(ctx))
+	require.EqualValues(t, 1, SampleRatio(WithSampleRatio(ctx, nil)))
+
+	none := 0.0
+	ctx = WithSampleRatio(ctx, &none)
+	require.EqualValues(t, 0, SampleRatio(ctx))
+
+	ctx, span := NewSpan(ctx, WithName("parent"))
+	require.False(t, span.sampled())
+
+	// Child spans inherit the parent's ratio.
+	_, child := NewSpan(ctx, WithName("child"))
+	require.False(t, child.sampled())
+
+	// Errors are always sampled.
+	child.RecordError(errors.New("nope"))
+	require.True(t, child.sampled())
+}
@@ -269,6 +269,7 @@ func NewSpan(ctx context.Context, opts ...SpanOpt) (context.Context, *Span) {
 		conf:     sconf,
 		kind:     so.SpanKind(),
 		dedup:    so.Dedup(),
+		ratio:    SampleRatio(ctx),
 	}
 
 	return trace.ContextWithSpan(ctx, s), s
@@ -308,7 +309,10 @@ type Span struct {
 	// can be used as an indicator to allow the span to be sent of not.
 	dedup bool
 	// Mark the span as cancelled, so it doesn't get sent out when it ends
-	cancel            bool
+	cancel bool
+	// ratio is the ratio of traces exported.  Spans in unsampled traces are
+	// dropped unless they record an error.
+	ratio             float64
 	childSpanCount    int
 	droppedAttributes int
 }
@@ -421,12 +425,25 @@ func (s *Span) End(opts ...trace.SpanEndOption) {
 		s.SetAttributes(attribute.Bool(consts.OtelSysStepDelete, true))
 	}
 
+	if !s.sampled() {
+		return
+	}
+
 	if err := UserTracer().Export(s); err != nil {
 		ctx := context.Background()
 		log.From(ctx).Error().Err(err).Msg("error ending span")
 	}
 }
 
+// sampled returns whether the span should be exported.  Errored spans are
+// always exported so that failures are visible in unsampled traces.
+func (s *Span) sampled() bool {
+	if s.Status().Code == codes.Error {
+		return true
+	}
+	return Sampled(s.conf.TraceID, s.ratio)
+}
+
 func (s *Span) AddEvent(name string, opts ...trace.EventOption) {
 	s.Lock()
 	defer s.Unlock()
@@ -451,10 +468,9 @@ func (s *Span) IsRecording() bool {
 
 // official one doesn't actually set the status, but we'll just do it here
 // for convinence's sake.
+//
+// AddEvent and SetStatus each lock the span, so the span isn't locked here.
 func (s *Span) RecordError(err error, opts ...trace.EventOption) {
-	s.Lock()
-	defer s.Unlock()
-
 	s.AddEvent(err.Error(), opts...)
 	s.SetStatus(codes.Error, err.Error())
 }
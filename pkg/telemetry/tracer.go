@@ -59,11 +59,19 @@ func UserTracer() Tracer {
 
 func CloseUserTracer(ctx context.Context) error {
 	if userTracer != nil {
-		userTracer.Shutdown(ctx)
+		userTracer.Shutdown(ctx)()
 	}
 	return nil
 }
 
+// FlushUserTracer exports any buffered user spans without shutting down the tracer.
+func FlushUserTracer(ctx context.Context) error {
+	if userTracer == nil {
+		return nil
+	}
+	return userTracer.Provider().ForceFlush(ctx)
+}
+
 type tracer struct {
 	provider   *trace.TracerProvider
 	propagator propagation.TextMapPropagator
@@ -252,3 +252,9 @@ export const RERUN = gql`
     rerun(runID: $runID)
   }
 `;
+
+export const REQUEUE_STEP = gql`
+  mutation RequeueStep($runID: ULID!, $stepID: String!) {
+    requeueStep(runID: $runID, stepID: $stepID)
+  }
+`;
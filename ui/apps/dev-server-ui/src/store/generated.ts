@@ -128,6 +128,7 @@ export type FunctionRun = {
   pendingSteps: Maybe<Scalars['Int']>;
   startedAt: Maybe<Scalars['Time']>;
   status: Maybe<FunctionRunStatus>;
+  stepLogs: Array<StepLog>;
   waitingFor: Maybe<StepEventWait>;
   workspace: Maybe<Workspace>;
 };
@@ -137,6 +138,11 @@ export type FunctionRunHistoryItemOutputArgs = {
   id: Scalars['ULID'];
 };
 
+
+export type FunctionRunStepLogsArgs = {
+  stepID: InputMaybe<Scalars['String']>;
+};
+
 export type FunctionRunEvent = FunctionEvent | StepEvent;
 
 export type FunctionRunQuery = {
@@ -216,6 +222,7 @@ export type Mutation = {
   deleteApp: Scalars['String'];
   deleteAppByName: Scalars['Boolean'];
   invokeFunction: Maybe<Scalars['Boolean']>;
+  requeueStep: Scalars['Boolean'];
   rerun: Scalars['ULID'];
   updateApp: App;
 };
@@ -247,6 +254,12 @@ export type MutationInvokeFunctionArgs = {
 };
 
 
+export type MutationRequeueStepArgs = {
+  runID: Scalars['ULID'];
+  stepID: Scalars['String'];
+};
+
+
 export type MutationRerunArgs = {
   runID: Scalars['ULID'];
 };
@@ -385,6 +398,17 @@ export type StepEventWait = {
   expression: Maybe<Scalars['String']>;
 };
 
+export type StepLog = {
+  __typename?: 'StepLog';
+  attempt: Scalars['Int'];
+  fields: Maybe<Scalars['Map']>;
+  level: Scalars['String'];
+  message: Scalars['String'];
+  runID: Scalars['ULID'];
+  stepID: Scalars['String'];
+  timestamp: Scalars['Time'];
+};
+
 export type StreamItem = {
   __typename?: 'StreamItem';
   createdAt: Scalars['Time'];
@@ -516,6 +540,14 @@ export type RerunMutationVariables = Exact<{
 
 export type RerunMutation = { __typename?: 'Mutation', rerun: any };
 
+export type RequeueStepMutationVariables = Exact<{
+  runID: Scalars['ULID'];
+  stepID: Scalars['String'];
+}>;
+
+
+export type RequeueStepMutation = { __typename?: 'Mutation', requeueStep: boolean };
+
 
 export const GetEventDocument = `
     query GetEvent($id: ID!) {
@@ -746,6 +778,11 @@ export const RerunDocument = `
   rerun(runID: $runID)
 }
     `;
+export const RequeueStepDocument = `
+    mutation RequeueStep($runID: ULID!, $stepID: String!) {
+  requeueStep(runID: $runID, stepID: $stepID)
+}
+    `;
 
 const injectedRtkApi = api.injectEndpoints({
   endpoints: (build) => ({
@@ -791,9 +828,12 @@ const injectedRtkApi = api.injectEndpoints({
     Rerun: build.mutation<RerunMutation, RerunMutationVariables>({
       query: (variables) => ({ document: RerunDocument, variables })
     }),
+    RequeueStep: build.mutation<RequeueStepMutation, RequeueStepMutationVariables>({
+      query: (variables) => ({ document: RequeueStepDocument, variables })
+    }),
   }),
 });
 
 export { injectedRtkApi as api };
-export const { useGetEventQuery, useLazyGetEventQuery, useGetFunctionRunQuery, useLazyGetFunctionRunQuery, useGetFunctionsQuery, useLazyGetFunctionsQuery, useGetAppsQuery, useLazyGetAppsQuery, useCreateAppMutation, useUpdateAppMutation, useDeleteAppMutation, useGetTriggersStreamQuery, useLazyGetTriggersStreamQuery, useGetFunctionRunStatusQuery, useLazyGetFunctionRunStatusQuery, useGetFunctionRunOutputQuery, useLazyGetFunctionRunOutputQuery, useGetHistoryItemOutputQuery, useLazyGetHistoryItemOutputQuery, useInvokeFunctionMutation, useCancelRunMutation, useRerunMutation } = injectedRtkApi;
+export const { useGetEventQuery, useLazyGetEventQuery, useGetFunctionRunQuery, useLazyGetFunctionRunQuery, useGetFunctionsQuery, useLazyGetFunctionsQuery, useGetAppsQuery, useLazyGetAppsQuery, useCreateAppMutation, useUpdateAppMutation, useDeleteAppMutation, useGetTriggersStreamQuery, useLazyGetTriggersStreamQuery, useGetFunctionRunStatusQuery, useLazyGetFunctionRunStatusQuery, useGetFunctionRunOutputQuery, useLazyGetFunctionRunOutputQuery, useGetHistoryItemOutputQuery, useLazyGetHistoryItemOutputQuery, useInvokeFunctionMutation, useCancelRunMutation, useRerunMutation, useRequeueStepMutation } = injectedRtkApi;
 